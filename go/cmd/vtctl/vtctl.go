@@ -28,6 +28,7 @@ import (
 
 	"golang.org/x/net/context"
 	"vitess.io/vitess/go/exit"
+	"vitess.io/vitess/go/vt/dbconfigs"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/servenv"
@@ -43,6 +44,10 @@ var (
 )
 
 func init() {
+	// The dba credentials are used by VerifyBackup on the
+	// temporary mysqld it restores backups into.
+	dbconfigs.RegisterFlags(dbconfigs.DbaConfig)
+
 	logger := logutil.NewConsoleLogger()
 	flag.CommandLine.SetOutput(logutil.NewLoggerWriter(logger))
 	flag.Usage = func() {
@@ -37,6 +37,8 @@ var (
 	schemaChangeCheckInterval = flag.Int("schema_change_check_interval", 60, "this value decides how often we check schema change dir, in seconds")
 	schemaChangeUser          = flag.String("schema_change_user", "", "The user who submits this schema change.")
 	schemaChangeSlaveTimeout  = flag.Duration("schema_change_slave_timeout", 10*time.Second, "how long to wait for slaves to receive the schema change")
+
+	schemaMigrationCheckInterval = flag.Duration("schema_migration_check_interval", 0, "if set, the schema migrations submitted with 'ApplySchema -async' are executed by this vtctld, which checks the migration queues at this interval")
 )
 
 func initSchema() {
@@ -75,4 +77,12 @@ func initSchema() {
 		})
 		servenv.OnClose(func() { timer.Stop() })
 	}
+
+	// Start the schema migration scheduler if needed.
+	if *schemaMigrationCheckInterval > 0 {
+		wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+		scheduler := schemamanager.NewMigrationScheduler(wr, *schemaChangeSlaveTimeout, *schemaMigrationCheckInterval)
+		scheduler.Open()
+		servenv.OnClose(scheduler.Close)
+	}
 }
@@ -339,6 +339,13 @@ func (c *echoClient) MessageAckKeyspaceIds(ctx context.Context, keyspace string,
 	return c.fallback.MessageAckKeyspaceIds(ctx, keyspace, name, idKeyspaceIDs)
 }
 
+func (c *echoClient) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	if strings.HasPrefix(name, EchoPrefix) {
+		return int64(len(ids)), nil
+	}
+	return c.fallback.MessageRequeue(ctx, keyspace, name, ids)
+}
+
 func (c *echoClient) SplitQuery(
 	ctx context.Context,
 	keyspace string,
@@ -290,6 +290,18 @@ func (c *errorClient) MessageAckKeyspaceIds(ctx context.Context, keyspace string
 	return c.fallback.MessageAckKeyspaceIds(ctx, keyspace, name, idKeyspaceIDs)
 }
 
+func (c *errorClient) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	cid := callerid.EffectiveCallerIDFromContext(ctx)
+	request := callerid.GetPrincipal(cid)
+	if err := requestToError(request); err != nil {
+		return 0, err
+	}
+	if err := requestToError(name); err != nil {
+		return 0, err
+	}
+	return c.fallback.MessageRequeue(ctx, keyspace, name, ids)
+}
+
 func (c *errorClient) SplitQuery(
 	ctx context.Context,
 	keyspace string,
@@ -115,6 +115,10 @@ func (c fallbackClient) MessageAckKeyspaceIds(ctx context.Context, keyspace stri
 	return c.fallback.MessageAckKeyspaceIds(ctx, keyspace, name, idKeyspaceIDs)
 }
 
+func (c fallbackClient) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	return c.fallback.MessageRequeue(ctx, keyspace, name, ids)
+}
+
 func (c fallbackClient) SplitQuery(
 	ctx context.Context,
 	keyspace string,
@@ -137,6 +141,10 @@ func (c fallbackClient) UpdateStream(ctx context.Context, keyspace string, shard
 	return c.fallback.UpdateStream(ctx, keyspace, shard, keyRange, tabletType, timestamp, event, callback)
 }
 
+func (c fallbackClient) StreamChanges(ctx context.Context, keyspace string, tabletType topodatapb.TabletType, vgtid *vtgatepb.VGtid, callback func(*vtgatepb.StreamChangesResponse) error) error {
+	return c.fallback.StreamChanges(ctx, keyspace, tabletType, vgtid, callback)
+}
+
 func (c fallbackClient) HandlePanic(err *error) {
 	c.fallback.HandlePanic(err)
 }
@@ -124,6 +124,10 @@ func (c *terminalClient) MessageAckKeyspaceIds(ctx context.Context, keyspace str
 	return 0, errTerminal
 }
 
+func (c *terminalClient) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	return 0, errTerminal
+}
+
 func (c *terminalClient) SplitQuery(
 	ctx context.Context,
 	keyspace string,
@@ -145,6 +149,10 @@ func (c *terminalClient) UpdateStream(ctx context.Context, keyspace string, shar
 	return errTerminal
 }
 
+func (c *terminalClient) StreamChanges(ctx context.Context, keyspace string, tabletType topodatapb.TabletType, vgtid *vtgatepb.VGtid, callback func(*vtgatepb.StreamChangesResponse) error) error {
+	return errTerminal
+}
+
 func (c *terminalClient) HandlePanic(err *error) {
 	if x := recover(); x != nil {
 		log.Errorf("Uncaught panic:\n%v\n%s", x, tb.Stack(4))
@@ -0,0 +1,97 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// vttopo is an embedded topology server. A few vttopo processes form a
+// replicated cluster that Vitess can use as its topology service,
+// instead of etcd, Consul or ZooKeeper. The other Vitess processes
+// connect to it with -topo_implementation vttopo, and the list of all
+// the vttopo gRPC addresses as -topo_global_server_address.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/exit"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vttopo"
+)
+
+var (
+	id                = flag.String("id", "", "id of this server, one of the ids in -peers")
+	peers             = flag.String("peers", "", "all the servers of the cluster, including this one, as a comma-separated list of id=host:grpc_port")
+	dataDir           = flag.String("data_dir", "", "directory to store the data in")
+	heartbeatInterval = flag.Duration("heartbeat_interval", 100*time.Millisecond, "how often the leader contacts the other servers")
+	electionTimeout   = flag.Duration("election_timeout", time.Second, "how long to wait without hearing from the leader before electing a new one, at least twice -heartbeat_interval")
+	snapshotThreshold = flag.Int("snapshot_threshold", 10000, "number of changes after which the log is compacted")
+)
+
+func init() {
+	servenv.RegisterDefaultFlags()
+}
+
+// parsePeers parses the -peers flag.
+func parsePeers(value string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, peer := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(peer), "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid peer %q, expected id=host:port", peer)
+		}
+		result[parts[0]] = parts[1]
+	}
+	return result, nil
+}
+
+func main() {
+	defer exit.Recover()
+
+	servenv.ParseFlags("vttopo")
+	if *id == "" || *dataDir == "" {
+		log.Exitf("-id and -data_dir are required")
+	}
+	if *servenv.GRPCPort == 0 {
+		log.Exitf("-grpc_port is required")
+	}
+	peerAddrs, err := parsePeers(*peers)
+	if err != nil {
+		log.Exitf("invalid -peers: %v", err)
+	}
+
+	servenv.Init()
+	defer servenv.Close()
+
+	server, err := vttopo.NewServer(vttopo.Config{
+		ID:                *id,
+		Peers:             peerAddrs,
+		DataDir:           *dataDir,
+		HeartbeatInterval: *heartbeatInterval,
+		ElectionTimeout:   *electionTimeout,
+		SnapshotThreshold: *snapshotThreshold,
+	})
+	if err != nil {
+		log.Exitf("cannot start vttopo: %v", err)
+	}
+	servenv.OnRun(func() {
+		server.Register(servenv.GRPCServer)
+	})
+	servenv.OnClose(server.Close)
+
+	servenv.RunDefault()
+}
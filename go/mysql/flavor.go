@@ -58,10 +58,21 @@ type flavor interface {
 	// a change master command.
 	changeMasterArg() string
 
+	// startSlaveUntilAfterCommand returns the command to start
+	// replication until the given position is applied.
+	startSlaveUntilAfterCommand(pos Position) string
+
+	// statusQuery returns the query returning the replication
+	// status, like 'SHOW SLAVE STATUS'.
+	statusQuery() string
+
 	// status returns the result of 'SHOW SLAVE STATUS',
 	// with parsed replication position.
 	status(c *Conn) (SlaveStatus, error)
 
+	// parseStatus parses a row returned by statusQuery.
+	parseStatus(resultMap map[string]string) (SlaveStatus, error)
+
 	// waitUntilPositionCommand returns the SQL command to issue
 	// to wait until the given position, until the context
 	// expires.  The command returns -1 if it times out. It
@@ -113,6 +124,62 @@ func (c *Conn) fillFlavor() {
 	c.flavor = mysqlFlavor{}
 }
 
+// flavorForPosition returns the flavor of the position, so commands can
+// be built without a connection to the server.
+func flavorForPosition(pos Position) (flavor, error) {
+	if pos.IsZero() {
+		return nil, fmt.Errorf("cannot find the flavor of an empty position")
+	}
+	switch pos.GTIDSet.Flavor() {
+	case mysql56FlavorID:
+		return mysqlFlavor{}, nil
+	case mariadbFlavorID:
+		return mariadbFlavor{}, nil
+	}
+	return nil, fmt.Errorf("unsupported flavor %v for position %v", pos.GTIDSet.Flavor(), pos)
+}
+
+// StartSlaveUntilAfterCommand returns the command to start replication
+// until the given position is applied, after which the SQL thread
+// stops. The flavor of the command is the one of the position, so it
+// can be built without a connection to the server.
+func StartSlaveUntilAfterCommand(pos Position) (string, error) {
+	f, err := flavorForPosition(pos)
+	if err != nil {
+		return "", err
+	}
+	return f.startSlaveUntilAfterCommand(pos), nil
+}
+
+// SlaveStatusQuery returns the query that returns the replication status
+// of a slave, in the flavor of the given position. The result is parsed
+// by ParseSlaveStatus. This is meant to read the status through a
+// tablet, when the SlaveStatus RPC does not return enough of it.
+func SlaveStatusQuery(pos Position) (string, error) {
+	f, err := flavorForPosition(pos)
+	if err != nil {
+		return "", err
+	}
+	return f.statusQuery(), nil
+}
+
+// ParseSlaveStatus parses the result of SlaveStatusQuery, in the flavor
+// of the given position.
+func ParseSlaveStatus(pos Position, qr *sqltypes.Result) (SlaveStatus, error) {
+	f, err := flavorForPosition(pos)
+	if err != nil {
+		return SlaveStatus{}, err
+	}
+	if len(qr.Rows) == 0 {
+		return SlaveStatus{}, ErrNotSlave
+	}
+	resultMap, err := resultToMap(qr)
+	if err != nil {
+		return SlaveStatus{}, err
+	}
+	return f.parseStatus(resultMap)
+}
+
 //
 // The following methods are dependent on the flavor.
 // Only valid for client connections (will panic for server connections).
@@ -105,9 +105,19 @@ func (mariadbFlavor) changeMasterArg() string {
 	return "MASTER_USE_GTID = current_pos"
 }
 
+// startSlaveUntilAfterCommand is part of the Flavor interface.
+func (mariadbFlavor) startSlaveUntilAfterCommand(pos Position) string {
+	return fmt.Sprintf("START SLAVE UNTIL master_gtid_pos = '%s'", pos)
+}
+
+// statusQuery is part of the Flavor interface.
+func (mariadbFlavor) statusQuery() string {
+	return "SHOW ALL SLAVES STATUS"
+}
+
 // status is part of the Flavor interface.
-func (mariadbFlavor) status(c *Conn) (SlaveStatus, error) {
-	qr, err := c.ExecuteFetch("SHOW ALL SLAVES STATUS", 100, true /* wantfields */)
+func (f mariadbFlavor) status(c *Conn) (SlaveStatus, error) {
+	qr, err := c.ExecuteFetch(f.statusQuery(), 100, true /* wantfields */)
 	if err != nil {
 		return SlaveStatus{}, err
 	}
@@ -121,12 +131,30 @@ func (mariadbFlavor) status(c *Conn) (SlaveStatus, error) {
 	if err != nil {
 		return SlaveStatus{}, err
 	}
+	return f.parseStatus(resultMap)
+}
 
+// parseStatus is part of the Flavor interface.
+func (mariadbFlavor) parseStatus(resultMap map[string]string) (SlaveStatus, error) {
 	status := parseSlaveStatus(resultMap)
+	var err error
 	status.Position.GTIDSet, err = parseMariadbGTIDSet(resultMap["Gtid_Slave_Pos"])
 	if err != nil {
 		return SlaveStatus{}, fmt.Errorf("SlaveStatus can't parse MariaDB GTID (Gtid_Slave_Pos: %#v): %v", resultMap["Gtid_Slave_Pos"], err)
 	}
+
+	// Gtid_IO_Pos is the last GTID received by the IO thread. It
+	// is empty if the slave never connected to its master.
+	status.RelayLogPosition = status.Position
+	if ioPos := resultMap["Gtid_IO_Pos"]; ioPos != "" {
+		gtidSet, err := parseMariadbGTIDSet(ioPos)
+		if err != nil {
+			return SlaveStatus{}, fmt.Errorf("SlaveStatus can't parse MariaDB GTID (Gtid_IO_Pos: %#v): %v", ioPos, err)
+		}
+		if gtidSet.Contains(status.Position.GTIDSet) {
+			status.RelayLogPosition.GTIDSet = gtidSet
+		}
+	}
 	return status, nil
 }
 
@@ -18,6 +18,8 @@ package mysql
 
 import (
 	"testing"
+
+	"vitess.io/vitess/go/sqltypes"
 )
 
 func TestMariadbSetMasterCommands(t *testing.T) {
@@ -75,3 +77,56 @@ func TestMariadbSetMasterCommandsSSL(t *testing.T) {
 		t.Errorf("mariadbFlavor.SetMasterCommands(%#v, %#v, %#v, %#v) = %#v, want %#v", params, masterHost, masterPort, masterConnectRetry, got, want)
 	}
 }
+
+func TestMariadbStartSlaveUntilAfterCommand(t *testing.T) {
+	pos, err := DecodePosition("MariaDB/0-1-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "START SLAVE UNTIL master_gtid_pos = '0-1-123'"
+	got, err := StartSlaveUntilAfterCommand(pos)
+	if err != nil || got != want {
+		t.Errorf("StartSlaveUntilAfterCommand(%v) = (%v, %v), want %v", pos, got, err, want)
+	}
+}
+
+func TestMariadbParseSlaveStatus(t *testing.T) {
+	pos, err := DecodePosition("MariaDB/0-1-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, err := SlaveStatusQuery(pos)
+	if err != nil || query != "SHOW ALL SLAVES STATUS" {
+		t.Errorf("SlaveStatusQuery(%v) = (%v, %v), want SHOW ALL SLAVES STATUS", pos, query, err)
+	}
+
+	fields := sqltypes.MakeTestFields(
+		"Master_Host|Slave_IO_Running|Slave_SQL_Running|Gtid_IO_Pos|Gtid_Slave_Pos",
+		"varchar|varchar|varchar|varchar|varchar")
+	table := []struct {
+		row, want string
+	}{{
+		// The relay logs have more transactions.
+		row:  "master|No|No|0-1-130|0-1-123",
+		want: "MariaDB/0-1-130",
+	}, {
+		// The IO thread never connected.
+		row:  "master|No|No||0-1-123",
+		want: "MariaDB/0-1-123",
+	}}
+	for _, tcase := range table {
+		status, err := ParseSlaveStatus(pos, sqltypes.MakeTestResult(fields, tcase.row))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := EncodePosition(status.Position), "MariaDB/0-1-123"; got != want {
+			t.Errorf("%v: Position = %v, want %v", tcase.row, got, want)
+		}
+		if got := EncodePosition(status.RelayLogPosition); got != tcase.want {
+			t.Errorf("%v: RelayLogPosition = %v, want %v", tcase.row, got, tcase.want)
+		}
+		if status.MasterUUID != "" {
+			t.Errorf("%v: MasterUUID = %v, want empty", tcase.row, status.MasterUUID)
+		}
+	}
+}
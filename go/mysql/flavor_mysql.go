@@ -72,9 +72,19 @@ func (mysqlFlavor) changeMasterArg() string {
 	return "MASTER_AUTO_POSITION = 1"
 }
 
+// startSlaveUntilAfterCommand is part of the Flavor interface.
+func (mysqlFlavor) startSlaveUntilAfterCommand(pos Position) string {
+	return fmt.Sprintf("START SLAVE UNTIL SQL_AFTER_GTIDS = '%s'", pos)
+}
+
+// statusQuery is part of the Flavor interface.
+func (mysqlFlavor) statusQuery() string {
+	return "SHOW SLAVE STATUS"
+}
+
 // status is part of the Flavor interface.
-func (mysqlFlavor) status(c *Conn) (SlaveStatus, error) {
-	qr, err := c.ExecuteFetch("SHOW SLAVE STATUS", 100, true /* wantfields */)
+func (f mysqlFlavor) status(c *Conn) (SlaveStatus, error) {
+	qr, err := c.ExecuteFetch(f.statusQuery(), 100, true /* wantfields */)
 	if err != nil {
 		return SlaveStatus{}, err
 	}
@@ -88,12 +98,26 @@ func (mysqlFlavor) status(c *Conn) (SlaveStatus, error) {
 	if err != nil {
 		return SlaveStatus{}, err
 	}
+	return f.parseStatus(resultMap)
+}
 
+// parseStatus is part of the Flavor interface.
+func (mysqlFlavor) parseStatus(resultMap map[string]string) (SlaveStatus, error) {
 	status := parseSlaveStatus(resultMap)
-	status.Position.GTIDSet, err = parseMysql56GTIDSet(resultMap["Executed_Gtid_Set"])
+	executed, err := parseMysql56GTIDSet(resultMap["Executed_Gtid_Set"])
 	if err != nil {
 		return SlaveStatus{}, fmt.Errorf("SlaveStatus can't parse MySQL 5.6 GTID (Executed_Gtid_Set: %#v): %v", resultMap["Executed_Gtid_Set"], err)
 	}
+	status.Position.GTIDSet = executed
+
+	// The relay logs have the transactions that were retrieved,
+	// but not necessarily applied yet.
+	retrieved, err := parseMysql56GTIDSet(resultMap["Retrieved_Gtid_Set"])
+	if err != nil {
+		return SlaveStatus{}, fmt.Errorf("SlaveStatus can't parse MySQL 5.6 GTID (Retrieved_Gtid_Set: %#v): %v", resultMap["Retrieved_Gtid_Set"], err)
+	}
+	status.RelayLogPosition.GTIDSet = executed.(Mysql56GTIDSet).Union(retrieved)
+	status.MasterUUID = resultMap["Master_UUID"]
 	return status, nil
 }
 
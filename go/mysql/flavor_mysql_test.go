@@ -16,7 +16,11 @@ limitations under the License.
 
 package mysql
 
-import "testing"
+import (
+	"testing"
+
+	"vitess.io/vitess/go/sqltypes"
+)
 
 func TestMysql56SetMasterCommands(t *testing.T) {
 	params := &ConnParams{
@@ -73,3 +77,56 @@ func TestMysql56SetMasterCommandsSSL(t *testing.T) {
 		t.Errorf("mysqlFlavor.SetMasterCommands(%#v, %#v, %#v, %#v) = %#v, want %#v", params, masterHost, masterPort, masterConnectRetry, got, want)
 	}
 }
+
+func TestMysql56StartSlaveUntilAfterCommand(t *testing.T) {
+	pos, err := DecodePosition("MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "START SLAVE UNTIL SQL_AFTER_GTIDS = '00010203-0405-0607-0809-0a0b0c0d0e0f:1-5'"
+	got, err := StartSlaveUntilAfterCommand(pos)
+	if err != nil || got != want {
+		t.Errorf("StartSlaveUntilAfterCommand(%v) = (%v, %v), want %v", pos, got, err, want)
+	}
+
+	if _, err := StartSlaveUntilAfterCommand(Position{}); err == nil {
+		t.Errorf("StartSlaveUntilAfterCommand(empty position) should have failed")
+	}
+}
+
+func TestMysql56ParseSlaveStatus(t *testing.T) {
+	pos, err := DecodePosition("MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, err := SlaveStatusQuery(pos)
+	if err != nil || query != "SHOW SLAVE STATUS" {
+		t.Errorf("SlaveStatusQuery(%v) = (%v, %v), want SHOW SLAVE STATUS", pos, query, err)
+	}
+
+	qr := sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+		"Master_Host|Master_Port|Slave_IO_Running|Slave_SQL_Running|Master_UUID|Retrieved_Gtid_Set|Executed_Gtid_Set",
+		"varchar|int64|varchar|varchar|varchar|varchar|varchar"),
+		"master|3306|No|No|00010203-0405-0607-0809-0a0b0c0d0e0f|00010203-0405-0607-0809-0a0b0c0d0e0f:3-8|00010203-0405-0607-0809-0a0b0c0d0e0f:1-5",
+	)
+	status, err := ParseSlaveStatus(pos, qr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := EncodePosition(status.Position), "MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-5"; got != want {
+		t.Errorf("Position = %v, want %v", got, want)
+	}
+	if got, want := EncodePosition(status.RelayLogPosition), "MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-8"; got != want {
+		t.Errorf("RelayLogPosition = %v, want %v", got, want)
+	}
+	if got, want := status.MasterUUID, "00010203-0405-0607-0809-0a0b0c0d0e0f"; got != want {
+		t.Errorf("MasterUUID = %v, want %v", got, want)
+	}
+	if status.MasterHost != "master" || status.MasterPort != 3306 || status.SlaveIORunning {
+		t.Errorf("unexpected status: %#v", status)
+	}
+
+	if _, err := ParseSlaveStatus(pos, &sqltypes.Result{}); err != ErrNotSlave {
+		t.Errorf("ParseSlaveStatus(no rows) = %v, want %v", err, ErrNotSlave)
+	}
+}
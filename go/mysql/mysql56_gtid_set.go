@@ -332,6 +332,78 @@ func (set Mysql56GTIDSet) AddGTID(gtid GTID) GTIDSet {
 	return newSet
 }
 
+// Union returns a new set with the GTIDs of both sets. It returns the
+// receiver if other is not a Mysql56GTIDSet.
+func (set Mysql56GTIDSet) Union(other GTIDSet) GTIDSet {
+	other56, ok := other.(Mysql56GTIDSet)
+	if !ok {
+		return set
+	}
+
+	newSet := make(Mysql56GTIDSet, len(set))
+	for sid, intervals := range set {
+		newSet[sid] = intervals
+	}
+	for sid, otherIntervals := range other56 {
+		// Merge the sorted lists, then coalesce the intervals that
+		// overlap or touch.
+		all := make([]interval, 0, len(newSet[sid])+len(otherIntervals))
+		all = append(all, newSet[sid]...)
+		all = append(all, otherIntervals...)
+		sort.Sort(intervalList(all))
+
+		merged := make([]interval, 0, len(all))
+		for _, iv := range all {
+			count := len(merged)
+			if count != 0 && iv.start <= merged[count-1].end+1 {
+				if iv.end > merged[count-1].end {
+					merged[count-1].end = iv.end
+				}
+				continue
+			}
+			merged = append(merged, iv)
+		}
+		newSet[sid] = merged
+	}
+	return newSet
+}
+
+// Difference returns a new set with the GTIDs of the receiver that are
+// not in other.
+func (set Mysql56GTIDSet) Difference(other Mysql56GTIDSet) Mysql56GTIDSet {
+	newSet := make(Mysql56GTIDSet)
+	for sid, intervals := range set {
+		otherIntervals := other[sid]
+		var remaining []interval
+		for _, iv := range intervals {
+			// Cut out each interval of other from iv. Both lists
+			// are sorted.
+			for _, oiv := range otherIntervals {
+				if oiv.end < iv.start {
+					continue
+				}
+				if oiv.start > iv.end {
+					break
+				}
+				if oiv.start > iv.start {
+					remaining = append(remaining, interval{start: iv.start, end: oiv.start - 1})
+				}
+				iv.start = oiv.end + 1
+				if iv.start > iv.end {
+					break
+				}
+			}
+			if iv.start <= iv.end {
+				remaining = append(remaining, iv)
+			}
+		}
+		if len(remaining) != 0 {
+			newSet[sid] = remaining
+		}
+	}
+	return newSet
+}
+
 // SIDBlock returns the binary encoding of a MySQL 5.6 GTID set as expected
 // by internal commands that refer to an "SID block".
 //
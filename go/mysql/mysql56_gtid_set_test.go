@@ -417,6 +417,61 @@ func TestMysql56GTIDSetAddGTID(t *testing.T) {
 	}
 }
 
+func TestMysql56GTIDSetUnion(t *testing.T) {
+	sid1 := SID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	sid2 := SID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 16}
+	sid3 := SID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 17}
+
+	set := Mysql56GTIDSet{
+		sid1: []interval{{20, 30}, {35, 40}, {42, 45}},
+		sid2: []interval{{1, 5}, {50, 50}, {60, 70}},
+	}
+	other := Mysql56GTIDSet{
+		sid1: []interval{{1, 19}, {25, 36}, {46, 46}},
+		sid2: []interval{{52, 55}},
+		sid3: []interval{{1, 3}},
+	}
+	want := Mysql56GTIDSet{
+		sid1: []interval{{1, 40}, {42, 46}},
+		sid2: []interval{{1, 5}, {50, 50}, {52, 55}, {60, 70}},
+		sid3: []interval{{1, 3}},
+	}
+	if got := set.Union(other); !got.Equal(want) {
+		t.Errorf("Union() = %#v, want %#v", got, want)
+	}
+
+	// The union with another flavor is a no-op.
+	if got := set.Union(fakeGTID{}); !got.Equal(set) {
+		t.Errorf("Union(fakeGTID) = %#v, want %#v", got, set)
+	}
+}
+
+func TestMysql56GTIDSetDifference(t *testing.T) {
+	sid1 := SID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	sid2 := SID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 16}
+	sid3 := SID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 17}
+
+	set := Mysql56GTIDSet{
+		sid1: []interval{{20, 30}, {35, 40}, {42, 45}},
+		sid2: []interval{{1, 5}, {50, 50}, {60, 70}},
+		sid3: []interval{{1, 3}},
+	}
+	other := Mysql56GTIDSet{
+		sid1: []interval{{1, 21}, {25, 26}, {30, 37}, {43, 43}},
+		sid2: []interval{{1, 70}},
+	}
+	want := Mysql56GTIDSet{
+		sid1: []interval{{22, 24}, {27, 29}, {38, 40}, {42, 42}, {44, 45}},
+		sid3: []interval{{1, 3}},
+	}
+	if got := set.Difference(other); !got.Equal(want) {
+		t.Errorf("Difference() = %#v, want %#v", got, want)
+	}
+	if got := set.Difference(set); len(got) != 0 {
+		t.Errorf("Difference(self) = %#v, want empty", got)
+	}
+}
+
 func TestMysql56GTIDSetSIDBlock(t *testing.T) {
 	sid1 := SID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
 	sid2 := SID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 16}
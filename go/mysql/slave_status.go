@@ -31,6 +31,13 @@ type SlaveStatus struct {
 	MasterHost          string
 	MasterPort          int
 	MasterConnectRetry  int
+
+	// RelayLogPosition is the position the slave reaches once it
+	// applied all the transactions it received from its master.
+	RelayLogPosition Position
+	// MasterUUID is the server UUID of the master. MariaDB does not
+	// report it.
+	MasterUUID string
 }
 
 // SlaveRunning returns true iff both the Slave IO and Slave SQL threads are
@@ -2,7 +2,9 @@ package prometheusbackend
 
 import (
 	"expvar"
+	"fmt"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/servenv"
 )
 
 // PromBackend implements PullBackend using Prometheus as the backing metrics storage.
@@ -28,6 +31,14 @@ func Init(namespace string) {
 	be.namespace = namespace
 	logUnsupported = logutil.NewThrottledLogger("PrometheusUnsupportedMetricType", 1*time.Minute)
 	stats.Register(be.publishPrometheusMetric)
+	if *pushgatewayURL != "" {
+		hostname, _ := os.Hostname()
+		instance := hostname
+		if servenv.Port != nil {
+			instance = fmt.Sprintf("%s:%d", hostname, *servenv.Port)
+		}
+		stats.RegisterPushBackend("prometheus", newPushgateway(*pushgatewayURL, namespace, instance, prometheus.DefaultGatherer))
+	}
 }
 
 // PublishPromMetric is used to publish the metric to Prometheus.
@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheusbackend
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+)
+
+var (
+	pushgatewayURL     = flag.String("prometheus_pushgateway_url", "", "if set, the URL of a Prometheus Pushgateway to push the stats to, in the OpenMetrics text format, when running with -emit_stats -stats_backend=prometheus")
+	pushgatewayTimeout = flag.Duration("prometheus_pushgateway_timeout", 10*time.Second, "the timeout of the pushes to the Prometheus Pushgateway")
+)
+
+// pushgateway implements stats.PushBackend. It pushes the metrics of a
+// Prometheus registry to a Pushgateway, in the text exposition format.
+type pushgateway struct {
+	url      string
+	gatherer prometheus.Gatherer
+	client   *http.Client
+}
+
+// newPushgateway returns a pushgateway that replaces the metrics of
+// the job and instance grouping key of the Pushgateway at address at
+// each push.
+func newPushgateway(address, job, instance string, gatherer prometheus.Gatherer) *pushgateway {
+	return &pushgateway{
+		url:      fmt.Sprintf("%s/metrics/job/%s/instance/%s", strings.TrimSuffix(address, "/"), url.PathEscape(job), url.PathEscape(instance)),
+		gatherer: gatherer,
+		client:   &http.Client{Timeout: *pushgatewayTimeout},
+	}
+}
+
+// PushAll pushes all the metrics of the registry to the Pushgateway.
+func (pg *pushgateway) PushAll() error {
+	mfs, err := pg.gatherer.Gather()
+	if err != nil {
+		return err
+	}
+	buf := &bytes.Buffer{}
+	enc := expfmt.NewEncoder(buf, expfmt.FmtText)
+	for _, mf := range mfs {
+		if err := enc.Encode(mf); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest("PUT", pg.url, buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", string(expfmt.FmtText))
+	resp, err := pg.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code %d pushing to %s: %s", resp.StatusCode, pg.url, body)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheusbackend
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestPushgateway(t *testing.T) {
+	var method, path, contentType, body string
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		method, path, contentType, body = r.Method, r.URL.Path, r.Header.Get("Content-Type"), string(data)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "pushed_total",
+		Help: "a pushed counter",
+	})
+	registry.MustRegister(counter)
+	counter.Add(3)
+
+	pg := newPushgateway(server.URL+"/", "vttablet", "host:15100", registry)
+	if err := pg.PushAll(); err != nil {
+		t.Fatalf("PushAll failed: %v", err)
+	}
+	if method != "PUT" || path != "/metrics/job/vttablet/instance/host:15100" {
+		t.Errorf("got %v %v, want PUT /metrics/job/vttablet/instance/host:15100", method, path)
+	}
+	if !strings.HasPrefix(contentType, "text/plain") {
+		t.Errorf("Content-Type: %v, want text/plain", contentType)
+	}
+	if !strings.Contains(body, "pushed_total 3") {
+		t.Errorf("body: %v, want pushed_total 3", body)
+	}
+
+	status = http.StatusBadRequest
+	if err := pg.PushAll(); err == nil || !strings.Contains(err.Error(), "unexpected status code 400") {
+		t.Errorf("PushAll: %v, want unexpected status code 400", err)
+	}
+}
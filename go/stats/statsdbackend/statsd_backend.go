@@ -0,0 +1,271 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statsdbackend is useful for publishing metrics to a StatsD
+// agent. The metrics are tagged the DogStatsD way, with the labels of
+// the multi-dimensional variables as tags.
+//
+// The stats are pushed every -stats_emit_period when vitess runs with
+// -emit_stats -stats_backend=statsd. They are mapped this way:
+//   - counters are sent as StatsD counters, with the increase since the
+//     previous push.
+//   - gauges are sent as StatsD gauges.
+//   - durations are in milliseconds.
+//   - Timings and Histograms are sent as three counters: <name>.count,
+//     <name>.total (<name>.time_ms for timings), and <name>.bucket, which
+//     is tagged with the upper bound of each histogram bucket.
+package statsdbackend
+
+import (
+	"bytes"
+	"expvar"
+	"flag"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+var (
+	statsdAddress       = flag.String("statsd_address", "localhost:8125", "the StatsD agent to push the stats to (with port)")
+	statsdPrefix        = flag.String("statsd_prefix", "vitess.", "the prefix of the StatsD metric names")
+	statsdMaxPacketSize = flag.Int("statsd_max_packet_size", 1432, "the maximum size of the UDP packets sent to the StatsD agent")
+)
+
+// StatsdBackend implements stats.PushBackend
+type StatsdBackend struct {
+	conn          net.Conn
+	prefix        string
+	maxPacketSize int
+
+	// mu protects last.
+	mu sync.Mutex
+	// last has the values of the counters at the previous push,
+	// indexed by metric name and tags.
+	last map[string]float64
+}
+
+// init registers a StatsdBackend as a PushBackend.
+func init() {
+	// Needs to happen in servenv.OnRun() instead of init because it requires flag parsing and logging
+	servenv.OnRun(func() {
+		backend, err := NewStatsdBackend(*statsdAddress, *statsdPrefix, *statsdMaxPacketSize)
+		if err != nil {
+			log.Errorf("Unable to create a StatsD backend: %v", err)
+			return
+		}
+		stats.RegisterPushBackend("statsd", backend)
+	})
+}
+
+// NewStatsdBackend returns a StatsdBackend that sends the stats to the
+// StatsD agent at address, in UDP packets of at most maxPacketSize bytes.
+func NewStatsdBackend(address, prefix string, maxPacketSize int) (*StatsdBackend, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	return &StatsdBackend{
+		conn:          conn,
+		prefix:        prefix,
+		maxPacketSize: maxPacketSize,
+		last:          make(map[string]float64),
+	}, nil
+}
+
+// PushAll pushes all expvar stats to StatsD
+func (backend *StatsdBackend) PushAll() error {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	w := &packetWriter{
+		conn:          backend.conn,
+		maxPacketSize: backend.maxPacketSize,
+	}
+	expvar.Do(func(kv expvar.KeyValue) {
+		backend.writeVar(w, backend.prefix+stats.GetSnakeName(kv.Key), kv.Value)
+	})
+	return w.flush()
+}
+
+// writeVar writes the metrics of a variable.
+func (backend *StatsdBackend) writeVar(w *packetWriter, name string, v expvar.Var) {
+	switch st := v.(type) {
+	case *stats.Counter:
+		backend.writeCounter(w, name, nil, float64(st.Get()))
+	case *stats.CounterFunc:
+		backend.writeCounter(w, name, nil, float64(st.F()))
+	case *stats.Gauge:
+		w.writeMetric(name, nil, float64(st.Get()), "g")
+	case *stats.GaugeFunc:
+		w.writeMetric(name, nil, float64(st.F()), "g")
+	case *stats.CounterDuration:
+		backend.writeCounter(w, name, nil, milliseconds(st.Get()))
+	case *stats.CounterDurationFunc:
+		backend.writeCounter(w, name, nil, milliseconds(st.F()))
+	case *stats.GaugeDuration:
+		w.writeMetric(name, nil, milliseconds(st.Get()), "g")
+	case *stats.GaugeDurationFunc:
+		w.writeMetric(name, nil, milliseconds(st.F()), "g")
+	case *stats.CountersWithSingleLabel:
+		for key, value := range st.Counts() {
+			backend.writeCounter(w, name, makeTags([]string{st.Label()}, key), float64(value))
+		}
+	case *stats.CountersWithMultiLabels:
+		for key, value := range st.Counts() {
+			backend.writeCounter(w, name, makeTags(st.Labels(), key), float64(value))
+		}
+	case *stats.CountersFuncWithMultiLabels:
+		for key, value := range st.Counts() {
+			backend.writeCounter(w, name, makeTags(st.Labels(), key), float64(value))
+		}
+	case *stats.GaugesWithSingleLabel:
+		for key, value := range st.Counts() {
+			w.writeMetric(name, makeTags([]string{st.Label()}, key), float64(value), "g")
+		}
+	case *stats.GaugesWithMultiLabels:
+		for key, value := range st.Counts() {
+			w.writeMetric(name, makeTags(st.Labels(), key), float64(value), "g")
+		}
+	case *stats.GaugesFuncWithMultiLabels:
+		for key, value := range st.Counts() {
+			w.writeMetric(name, makeTags(st.Labels(), key), float64(value), "g")
+		}
+	case *stats.Timings:
+		for key, h := range st.Histograms() {
+			backend.writeTimings(w, name, makeTags([]string{st.Label()}, key), h)
+		}
+	case *stats.MultiTimings:
+		for key, h := range st.Histograms() {
+			backend.writeTimings(w, name, makeTags(st.Labels(), key), h)
+		}
+	case *stats.Histogram:
+		backend.writeCounter(w, name+".count", nil, float64(st.Count()))
+		backend.writeCounter(w, name+".total", nil, float64(st.Total()))
+		backend.writeBuckets(w, name, nil, st.Labels(), st.Buckets())
+	}
+}
+
+// writeTimings writes the metrics of a histogram of Timings, whose
+// values are in nanoseconds.
+func (backend *StatsdBackend) writeTimings(w *packetWriter, name string, tags []string, h *stats.Histogram) {
+	backend.writeCounter(w, name+".count", tags, float64(h.Count()))
+	backend.writeCounter(w, name+".time_ms", tags, milliseconds(time.Duration(h.Total())))
+	bounds := make([]string, len(h.Labels()))
+	for i, cutoff := range h.Cutoffs() {
+		bounds[i] = strconv.FormatFloat(milliseconds(time.Duration(cutoff)), 'f', -1, 64)
+	}
+	bounds[len(bounds)-1] = "inf"
+	backend.writeBuckets(w, name, tags, bounds, h.Buckets())
+}
+
+// writeBuckets writes the counts of the buckets of a histogram, tagged
+// with their upper bounds.
+func (backend *StatsdBackend) writeBuckets(w *packetWriter, name string, tags []string, bounds []string, buckets []int64) {
+	for i, count := range buckets {
+		bucketTags := append(append(make([]string, 0, len(tags)+1), tags...), "le:"+bounds[i])
+		backend.writeCounter(w, name+".bucket", bucketTags, float64(count))
+	}
+}
+
+// writeCounter writes the increase of a counter since the previous push.
+func (backend *StatsdBackend) writeCounter(w *packetWriter, name string, tags []string, value float64) {
+	key := name + "|" + strings.Join(tags, ",")
+	delta := value - backend.last[key]
+	if delta < 0 {
+		// The counter was reset.
+		delta = value
+	}
+	backend.last[key] = value
+	if delta == 0 {
+		return
+	}
+	w.writeMetric(name, tags, delta, "c")
+}
+
+// makeTags returns the tags of a multi-dimensional variable, from the
+// names of its labels and the '.' separated key of one of its values.
+func makeTags(labels []string, key string) []string {
+	values := strings.SplitN(key, ".", len(labels))
+	tags := make([]string, 0, len(labels))
+	for i, label := range labels {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		tags = append(tags, sanitize(stats.GetSnakeName(label))+":"+sanitize(value))
+	}
+	return tags
+}
+
+// sanitize replaces the characters of the StatsD line format.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', ',', '#', '@', '\n':
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// packetWriter batches the metric lines in UDP packets.
+type packetWriter struct {
+	conn          net.Conn
+	maxPacketSize int
+	buf           bytes.Buffer
+	err           error
+}
+
+// writeMetric writes a metric line, like name:1|c|#tag:value.
+func (w *packetWriter) writeMetric(name string, tags []string, value float64, metricType string) {
+	line := fmt.Sprintf("%s:%s|%s", sanitize(name), strconv.FormatFloat(value, 'f', -1, 64), metricType)
+	if len(tags) > 0 {
+		line += "|#" + strings.Join(tags, ",")
+	}
+	if w.buf.Len() > 0 && w.buf.Len()+1+len(line) > w.maxPacketSize {
+		w.send()
+	}
+	if w.buf.Len() > 0 {
+		w.buf.WriteByte('\n')
+	}
+	w.buf.WriteString(line)
+}
+
+// flush sends the last packet, and returns the first error.
+func (w *packetWriter) flush() error {
+	if w.buf.Len() > 0 {
+		w.send()
+	}
+	return w.err
+}
+
+func (w *packetWriter) send() {
+	if _, err := w.conn.Write(w.buf.Bytes()); err != nil && w.err == nil {
+		w.err = err
+	}
+	w.buf.Reset()
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statsdbackend
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+)
+
+// listen returns a local UDP listener and a StatsdBackend sending to it.
+func listen(t *testing.T, maxPacketSize int) (*net.UDPConn, *StatsdBackend) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		t.Fatalf("ListenUDP failed: %v", err)
+	}
+	backend, err := NewStatsdBackend(conn.LocalAddr().String(), "test.", maxPacketSize)
+	if err != nil {
+		t.Fatalf("NewStatsdBackend failed: %v", err)
+	}
+	return conn, backend
+}
+
+// receive pushes the stats, and returns the sorted metric lines
+// received for the names starting with prefix.
+func receive(t *testing.T, conn *net.UDPConn, backend *StatsdBackend, prefix string) []string {
+	if err := backend.PushAll(); err != nil {
+		t.Fatalf("PushAll failed: %v", err)
+	}
+	var lines []string
+	buf := make([]byte, 65536)
+	for {
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		n, err := conn.Read(buf)
+		if err != nil {
+			break
+		}
+		for _, line := range strings.Split(string(buf[:n]), "\n") {
+			if strings.HasPrefix(line, prefix) {
+				lines = append(lines, line)
+			}
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func checkLines(t *testing.T, got, want []string) {
+	t.Helper()
+	sort.Strings(want)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%v\nwant:\n%v", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestStatsdCountersAndGauges(t *testing.T) {
+	conn, backend := listen(t, 1432)
+	defer conn.Close()
+
+	c := stats.NewCounter("StatsdTestCounter", "help")
+	g := stats.NewGauge("StatsdTestGauge", "help")
+	cs := stats.NewCountersWithSingleLabel("StatsdTestCountersSingle", "help", "TabletType")
+	cm := stats.NewCountersWithMultiLabels("StatsdTestCountersMulti", "help", []string{"Keyspace", "ShardName"})
+	gm := stats.NewGaugesWithMultiLabels("StatsdTestGaugesMulti", "help", []string{"Keyspace", "ShardName"})
+	d := stats.NewCounterDuration("StatsdTestDuration", "help")
+	c.Add(3)
+	g.Set(7)
+	cs.Add("replica", 2)
+	cm.Add([]string{"ks", "-80"}, 4)
+	gm.Set([]string{"ks", "80-"}, 5)
+	d.Add(1500 * time.Microsecond)
+
+	checkLines(t, receive(t, conn, backend, "test.statsd_test"), []string{
+		"test.statsd_test_counter:3|c",
+		"test.statsd_test_gauge:7|g",
+		"test.statsd_test_counters_single:2|c|#tablet_type:replica",
+		"test.statsd_test_counters_multi:4|c|#keyspace:ks,shard_name:-80",
+		"test.statsd_test_gauges_multi:5|g|#keyspace:ks,shard_name:80-",
+		"test.statsd_test_duration:1.5|c",
+	})
+
+	// Counters send their increase, gauges their value.
+	c.Add(2)
+	cm.Add([]string{"ks", "-80"}, 1)
+	checkLines(t, receive(t, conn, backend, "test.statsd_test"), []string{
+		"test.statsd_test_counter:2|c",
+		"test.statsd_test_gauge:7|g",
+		"test.statsd_test_counters_multi:1|c|#keyspace:ks,shard_name:-80",
+		"test.statsd_test_gauges_multi:5|g|#keyspace:ks,shard_name:80-",
+	})
+}
+
+func TestStatsdTimingsAndHistogram(t *testing.T) {
+	conn, backend := listen(t, 1432)
+	defer conn.Close()
+
+	tm := stats.NewMultiTimings("StatsdHistogramTestTimings", "help", []string{"Operation", "Keyspace"})
+	h := stats.NewHistogram("StatsdHistogramTestHistogram", "help", []int64{1, 5})
+	tm.Add([]string{"Execute", "ks"}, 2*time.Millisecond)
+	tm.Add([]string{"Execute", "ks"}, 4*time.Millisecond)
+	h.Add(3)
+
+	checkLines(t, receive(t, conn, backend, "test.statsd_histogram_test"), []string{
+		"test.statsd_histogram_test_timings.count:2|c|#operation:Execute,keyspace:ks",
+		"test.statsd_histogram_test_timings.time_ms:6|c|#operation:Execute,keyspace:ks",
+		"test.statsd_histogram_test_timings.bucket:2|c|#operation:Execute,keyspace:ks,le:5",
+		"test.statsd_histogram_test_histogram.count:1|c",
+		"test.statsd_histogram_test_histogram.total:3|c",
+		"test.statsd_histogram_test_histogram.bucket:1|c|#le:5",
+	})
+}
+
+func TestStatsdPacketSize(t *testing.T) {
+	conn, backend := listen(t, 64)
+	defer conn.Close()
+
+	cm := stats.NewCountersWithMultiLabels("StatsdTestPackets", "help", []string{"Keyspace"})
+	for _, ks := range []string{"ks1", "ks2", "ks3", "ks4"} {
+		cm.Add([]string{ks}, 1)
+	}
+	if err := backend.PushAll(); err != nil {
+		t.Fatalf("PushAll failed: %v", err)
+	}
+	buf := make([]byte, 65536)
+	for {
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		n, err := conn.Read(buf)
+		if err != nil {
+			break
+		}
+		if n > 64 && strings.Contains(string(buf[:n]), "\n") {
+			t.Errorf("packet of %v bytes with several metrics: %q", n, buf[:n])
+		}
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"sync"
+)
+
+var fileExporterPath = flag.String("tracing_file", "", "file the file tracer appends the spans to, one JSON object per line")
+
+func init() {
+	RegisterExporter("file", func(serviceName string) (Exporter, error) {
+		if *fileExporterPath == "" {
+			return nil, errors.New("-tracing_file is required with -tracer=file")
+		}
+		return NewFileExporter(*fileExporterPath)
+	})
+}
+
+// FileExporter is an Exporter that appends the spans to a local file,
+// one JSON-encoded SpanData per line. It is meant for tests and
+// debugging.
+type FileExporter struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewFileExporter returns a FileExporter appending to the file at path,
+// which is created if needed.
+func NewFileExporter(path string) (*FileExporter, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileExporter{
+		file: file,
+		enc:  json.NewEncoder(file),
+	}, nil
+}
+
+// ExportSpan is part of the Exporter interface.
+func (fe *FileExporter) ExportSpan(data *SpanData) {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	if fe.file == nil {
+		return
+	}
+	// Encode writes the trailing newline.
+	fe.enc.Encode(data)
+}
+
+// Close is part of the Exporter interface.
+func (fe *FileExporter) Close() error {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	if fe.file == nil {
+		return nil
+	}
+	err := fe.file.Close()
+	fe.file = nil
+	return err
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/vt/log"
+)
+
+var (
+	jaegerCollectorURL  = flag.String("jaeger_collector_url", "http://localhost:9411/api/v2/spans", "Zipkin-compatible span endpoint of the Jaeger collector, used with -tracer=jaeger")
+	jaegerFlushInterval = flag.Duration("jaeger_flush_interval", time.Second, "how often the jaeger tracer sends the spans to the collector")
+	jaegerQueueSize     = flag.Int("jaeger_queue_size", 10000, "maximum number of spans the jaeger tracer buffers; spans finished while the queue is full are dropped")
+)
+
+const (
+	jaegerMaxBatchSize    = 500
+	jaegerRequestTimeout  = 5 * time.Second
+	jaegerDroppedLogEvery = time.Minute
+)
+
+func init() {
+	RegisterExporter("jaeger", func(serviceName string) (Exporter, error) {
+		return NewJaegerExporter(*jaegerCollectorURL, serviceName, *jaegerFlushInterval, *jaegerQueueSize), nil
+	})
+}
+
+// JaegerExporter is an Exporter sending the spans to a Jaeger
+// collector, through its Zipkin-compatible JSON endpoint (which Zipkin
+// itself also serves). Together with the uber-trace-id span context
+// used by Tracer, this lets the Vitess traces be joined with the ones
+// of the applications instrumented with Jaeger or OpenTracing clients.
+//
+// The spans are buffered, and sent in batches in the background.
+type JaegerExporter struct {
+	url           string
+	service       string
+	flushInterval time.Duration
+	client        *http.Client
+
+	spans   chan *SpanData
+	dropped int64
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewJaegerExporter returns a JaegerExporter posting to url, and starts
+// its background sender.
+func NewJaegerExporter(url, serviceName string, flushInterval time.Duration, queueSize int) *JaegerExporter {
+	je := &JaegerExporter{
+		url:           url,
+		service:       serviceName,
+		flushInterval: flushInterval,
+		client:        &http.Client{Timeout: jaegerRequestTimeout},
+		spans:         make(chan *SpanData, queueSize),
+		done:          make(chan struct{}),
+	}
+	je.wg.Add(1)
+	go je.run()
+	return je
+}
+
+// ExportSpan is part of the Exporter interface.
+func (je *JaegerExporter) ExportSpan(data *SpanData) {
+	select {
+	case je.spans <- data:
+	default:
+		atomic.AddInt64(&je.dropped, 1)
+	}
+}
+
+// Close is part of the Exporter interface. It sends the buffered spans
+// before returning.
+func (je *JaegerExporter) Close() error {
+	close(je.done)
+	je.wg.Wait()
+	return nil
+}
+
+func (je *JaegerExporter) run() {
+	defer je.wg.Done()
+	ticker := time.NewTicker(je.flushInterval)
+	defer ticker.Stop()
+	lastDroppedLog := time.Now()
+
+	var batch []*SpanData
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := je.send(batch); err != nil {
+			log.Warningf("cannot send %v spans to %v: %v", len(batch), je.url, err)
+		}
+		batch = nil
+	}
+	for {
+		select {
+		case data := <-je.spans:
+			batch = append(batch, data)
+			if len(batch) >= jaegerMaxBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+			if time.Since(lastDroppedLog) >= jaegerDroppedLogEvery {
+				if dropped := atomic.SwapInt64(&je.dropped, 0); dropped != 0 {
+					log.Warningf("dropped %v spans, the jaeger tracer queue was full", dropped)
+				}
+				lastDroppedLog = time.Now()
+			}
+		case <-je.done:
+			for {
+				select {
+				case data := <-je.spans:
+					batch = append(batch, data)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// zipkinSpan is a span in the Zipkin v2 JSON format.
+type zipkinSpan struct {
+	TraceID       string            `json:"traceId"`
+	ID            string            `json:"id"`
+	ParentID      string            `json:"parentId,omitempty"`
+	Name          string            `json:"name"`
+	Kind          string            `json:"kind,omitempty"`
+	Timestamp     int64             `json:"timestamp"`
+	Duration      int64             `json:"duration"`
+	LocalEndpoint zipkinEndpoint    `json:"localEndpoint"`
+	Tags          map[string]string `json:"tags,omitempty"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+func toZipkinSpan(data *SpanData) *zipkinSpan {
+	zs := &zipkinSpan{
+		TraceID:   fmt.Sprintf("%016x", data.TraceID),
+		ID:        fmt.Sprintf("%016x", data.SpanID),
+		Name:      data.Label,
+		Timestamp: data.Start.UnixNano() / int64(time.Microsecond),
+		Duration:  int64(data.Duration / time.Microsecond),
+		LocalEndpoint: zipkinEndpoint{
+			ServiceName: data.Service,
+		},
+		Tags: data.Annotations,
+	}
+	if data.ParentID != 0 {
+		zs.ParentID = fmt.Sprintf("%016x", data.ParentID)
+	}
+	switch data.Kind {
+	case KindClient:
+		zs.Kind = "CLIENT"
+	case KindServer:
+		zs.Kind = "SERVER"
+	}
+	// Zipkin rejects the spans shorter than a microsecond.
+	if zs.Duration < 1 {
+		zs.Duration = 1
+	}
+	return zs
+}
+
+func (je *JaegerExporter) send(batch []*SpanData) error {
+	spans := make([]*zipkinSpan, 0, len(batch))
+	for _, data := range batch {
+		zs := toZipkinSpan(data)
+		if zs.LocalEndpoint.ServiceName == "" {
+			zs.LocalEndpoint.ServiceName = je.service
+		}
+		spans = append(spans, zs)
+	}
+	body, err := json.Marshal(spans)
+	if err != nil {
+		return err
+	}
+	resp, err := je.client.Post(je.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %v", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestJaegerExporter(t *testing.T) {
+	received := make(chan []zipkinSpan, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("cannot read body: %v", err)
+		}
+		var spans []zipkinSpan
+		if err := json.Unmarshal(body, &spans); err != nil {
+			t.Errorf("cannot decode %s: %v", body, err)
+		}
+		received <- spans
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	exporter := NewJaegerExporter(server.URL, "vtgate", time.Hour, 10)
+	start := time.Now()
+	exporter.ExportSpan(&SpanData{
+		TraceID:     1,
+		SpanID:      2,
+		Kind:        KindServer,
+		Label:       "Executor.Execute",
+		Start:       start,
+		Duration:    3 * time.Millisecond,
+		Annotations: map[string]string{"sql": "select 1"},
+	})
+	exporter.ExportSpan(&SpanData{
+		Service:  "vtgate",
+		TraceID:  1,
+		SpanID:   3,
+		ParentID: 2,
+		Kind:     KindLocal,
+		Label:    "Route.Execute",
+		Start:    start,
+	})
+	// Close flushes the pending spans.
+	exporter.Close()
+
+	var spans []zipkinSpan
+	for len(received) > 0 {
+		spans = append(spans, <-received...)
+	}
+	want := []zipkinSpan{{
+		TraceID:       "0000000000000001",
+		ID:            "0000000000000002",
+		Name:          "Executor.Execute",
+		Kind:          "SERVER",
+		Timestamp:     start.UnixNano() / 1000,
+		Duration:      3000,
+		LocalEndpoint: zipkinEndpoint{ServiceName: "vtgate"},
+		Tags:          map[string]string{"sql": "select 1"},
+	}, {
+		TraceID:       "0000000000000001",
+		ID:            "0000000000000003",
+		ParentID:      "0000000000000002",
+		Name:          "Route.Execute",
+		Timestamp:     start.UnixNano() / 1000,
+		Duration:      1,
+		LocalEndpoint: zipkinEndpoint{ServiceName: "vtgate"},
+	}}
+	if len(spans) != len(want) {
+		t.Fatalf("got spans %+v, want %+v", spans, want)
+	}
+	for i := range want {
+		got, _ := json.Marshal(spans[i])
+		wantJSON, _ := json.Marshal(want[i])
+		if string(got) != string(wantJSON) {
+			t.Errorf("span %v: got %s, want %s", i, got, wantJSON)
+		}
+	}
+}
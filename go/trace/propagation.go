@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"golang.org/x/net/context"
+)
+
+// SpanContextHeader is the gRPC metadata key carrying the span context
+// between processes. It is the header used by Jaeger clients.
+const SpanContextHeader = "uber-trace-id"
+
+// Propagator is implemented by the SpanFactories whose spans can be
+// continued in another process.
+type Propagator interface {
+	// Inject returns the serialized context of span.
+	Inject(span Span) (string, bool)
+	// Extract parses a serialized span context. The returned Span is
+	// only meant to be used as the parent of new Spans.
+	Extract(value string) (Span, bool)
+	// Sampled returns true if span is part of a recorded trace.
+	Sampled(span Span) bool
+}
+
+// SpanContext returns the serialized context of the Span of ctx, for
+// another process to continue the trace. It returns "" if ctx has no
+// Span, or if the installed SpanFactory cannot propagate it.
+func SpanContext(ctx context.Context) string {
+	value, _ := spanContext(ctx)
+	return value
+}
+
+// spanContext returns the serialized context of the Span of ctx, and
+// whether its trace is recorded.
+func spanContext(ctx context.Context) (string, bool) {
+	p, ok := spanFactory.(Propagator)
+	if !ok {
+		return "", false
+	}
+	span, ok := FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	value, ok := p.Inject(span)
+	if !ok {
+		return "", false
+	}
+	return value, p.Sampled(span)
+}
+
+// extractSpan parses a serialized span context with the installed
+// SpanFactory.
+func extractSpan(value string) (Span, bool) {
+	p, ok := spanFactory.(Propagator)
+	if !ok {
+		return nil, false
+	}
+	return p.Extract(value)
+}
+
+// WithSpanContext returns a context based on ctx whose Span is the
+// remote span described by value, as returned by SpanContext in
+// another process. The Spans created from that context join the remote
+// trace. ctx is returned unchanged if value cannot be parsed.
+func WithSpanContext(ctx context.Context, value string) context.Context {
+	ctx, _ = ContinueSpanContext(ctx, value)
+	return ctx
+}
+
+// ContinueSpanContext is like WithSpanContext, but it also returns
+// whether value could be parsed.
+func ContinueSpanContext(ctx context.Context, value string) (context.Context, bool) {
+	if value == "" {
+		return ctx, false
+	}
+	span, ok := extractSpan(value)
+	if !ok {
+		return ctx, false
+	}
+	return NewContext(ctx, span), true
+}
+
+// SampledSpanContext is like SpanContext, but it also returns "" if
+// the trace of the Span of ctx is not recorded.
+func SampledSpanContext(ctx context.Context) string {
+	value, sampled := spanContext(ctx)
+	if !sampled {
+		return ""
+	}
+	return value
+}
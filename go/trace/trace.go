@@ -17,6 +17,9 @@ limitations under the License.
 // Package trace contains a helper interface that allows various tracing
 // tools to be plugged in to components using this interface. If no plugin is
 // registered, the default one makes all trace calls into no-ops.
+//
+// Tracer is the built-in implementation, installed by StartTracing when
+// the -tracer flag selects one of the registered Exporters.
 package trace
 
 import (
@@ -92,8 +95,11 @@ var spanFactory SpanFactory = fakeSpanFactory{}
 // factory that creates Spans for that plugin's tracing framework. Each call to
 // RegisterSpanFactory will overwrite any previous setting. If no factory is
 // registered, the default fake factory will produce Spans whose methods are all
-// no-ops.
+// no-ops. Registering nil restores that default factory.
 func RegisterSpanFactory(sf SpanFactory) {
+	if sf == nil {
+		sf = fakeSpanFactory{}
+	}
 	spanFactory = sf
 }
 
@@ -0,0 +1,321 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/log"
+)
+
+var (
+	tracerName   = flag.String("tracer", "noop", "tracing exporter to use: noop, jaeger or file")
+	samplingRate = flag.Float64("tracing_sampling_rate", 0.1, "fraction of the traces started in this process that are recorded, between 0 and 1")
+)
+
+// The kinds of spans, set by the Start methods.
+const (
+	KindLocal  = "local"
+	KindClient = "client"
+	KindServer = "server"
+)
+
+// SpanData is the record of a finished Span, as passed to an Exporter.
+type SpanData struct {
+	// Service is the name of the process that recorded the span.
+	Service string
+	// TraceID identifies the trace the span belongs to.
+	TraceID uint64
+	// SpanID identifies the span within the trace.
+	SpanID uint64
+	// ParentID is the SpanID of the parent span, or 0 for a root span.
+	ParentID uint64
+	// Kind is one of KindLocal, KindClient or KindServer.
+	Kind string
+	// Label is the label passed to the Start method.
+	Label string
+	// Start is when the span was started.
+	Start time.Time
+	// Duration is the time between Start and Finish.
+	Duration time.Duration
+	// Annotations are the values recorded with Annotate.
+	Annotations map[string]string `json:",omitempty"`
+}
+
+// Exporter sends the finished spans to a tracing backend.
+type Exporter interface {
+	// ExportSpan records a finished span. It is called inline by
+	// Span.Finish, and should not block.
+	ExportSpan(data *SpanData)
+	// Close flushes the pending spans and releases the resources
+	// of the Exporter.
+	Close() error
+}
+
+// ExporterFactory creates an Exporter. serviceName identifies the
+// current process in the exported spans.
+type ExporterFactory func(serviceName string) (Exporter, error)
+
+var exporterFactories = make(map[string]ExporterFactory)
+
+// RegisterExporter registers an Exporter under the name to use with
+// the -tracer flag. It should be called during init().
+func RegisterExporter(name string, factory ExporterFactory) {
+	if _, ok := exporterFactories[name]; ok {
+		panic(fmt.Sprintf("trace exporter %v already registered", name))
+	}
+	exporterFactories[name] = factory
+}
+
+// StartTracing installs a Tracer sending its spans to the Exporter
+// selected by the -tracer flag. The returned Closer flushes the
+// Exporter, and should be called when the process exits. With
+// -tracer=noop, nothing is installed and all trace calls stay no-ops.
+func StartTracing(serviceName string) io.Closer {
+	if *tracerName == "" || *tracerName == "noop" {
+		return nopCloser{}
+	}
+	factory, ok := exporterFactories[*tracerName]
+	if !ok {
+		log.Errorf("unknown tracer %v, tracing is disabled", *tracerName)
+		return nopCloser{}
+	}
+	exporter, err := factory(serviceName)
+	if err != nil {
+		log.Errorf("cannot create tracer %v, tracing is disabled: %v", *tracerName, err)
+		return nopCloser{}
+	}
+	log.Infof("tracing with %v, sampling rate %v", *tracerName, *samplingRate)
+	RegisterSpanFactory(NewTracer(serviceName, exporter, *samplingRate))
+	return exporter
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+// Tracer is a SpanFactory that records the spans of the sampled traces
+// and sends them to an Exporter. It is also a Propagator: its span
+// context is serialized in the format used by Jaeger clients, so traces
+// can be continued across processes.
+type Tracer struct {
+	service      string
+	exporter     Exporter
+	samplingRate float64
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewTracer returns a Tracer recording the fraction samplingRate of
+// the traces it starts. The traces continued from a remote span keep
+// the sampling decision of the remote process.
+func NewTracer(serviceName string, exporter Exporter, samplingRate float64) *Tracer {
+	return &Tracer{
+		service:      serviceName,
+		exporter:     exporter,
+		samplingRate: samplingRate,
+		rnd:          rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// newID returns a random, non-zero ID.
+func (t *Tracer) newID() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for {
+		if id := t.rnd.Uint64(); id != 0 {
+			return id
+		}
+	}
+}
+
+func (t *Tracer) sample() bool {
+	if t.samplingRate >= 1 {
+		return true
+	}
+	if t.samplingRate <= 0 {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rnd.Float64() < t.samplingRate
+}
+
+// New is part of the SpanFactory interface.
+func (t *Tracer) New(parent Span) Span {
+	s := &span{
+		tracer: t,
+		spanID: t.newID(),
+	}
+	if p, ok := parent.(*span); ok && p != nil {
+		s.traceID = p.traceID
+		s.parentID = p.spanID
+		s.sampled = p.sampled
+	} else {
+		s.traceID = t.newID()
+		s.sampled = t.sample()
+	}
+	return s
+}
+
+type spanKey struct{}
+
+// FromContext is part of the SpanFactory interface.
+func (t *Tracer) FromContext(ctx context.Context) (Span, bool) {
+	s, ok := ctx.Value(spanKey{}).(*span)
+	if !ok {
+		return nil, false
+	}
+	return s, true
+}
+
+// NewContext is part of the SpanFactory interface.
+func (t *Tracer) NewContext(parent context.Context, s Span) context.Context {
+	if s, ok := s.(*span); ok {
+		return context.WithValue(parent, spanKey{}, s)
+	}
+	return parent
+}
+
+// Inject is part of the Propagator interface.
+func (t *Tracer) Inject(s Span) (string, bool) {
+	sp, ok := s.(*span)
+	if !ok {
+		return "", false
+	}
+	flags := 0
+	if sp.sampled {
+		flags = 1
+	}
+	return fmt.Sprintf("%x:%x:%x:%d", sp.traceID, sp.spanID, sp.parentID, flags), true
+}
+
+// Sampled is part of the Propagator interface.
+func (t *Tracer) Sampled(s Span) bool {
+	sp, ok := s.(*span)
+	return ok && sp.sampled
+}
+
+// Extract is part of the Propagator interface.
+func (t *Tracer) Extract(value string) (Span, bool) {
+	var traceID, spanID, parentID uint64
+	var flags int
+	if n, err := fmt.Sscanf(value, "%x:%x:%x:%d", &traceID, &spanID, &parentID, &flags); n != 4 || err != nil {
+		return nil, false
+	}
+	if traceID == 0 || spanID == 0 {
+		return nil, false
+	}
+	return &span{
+		tracer:   t,
+		traceID:  traceID,
+		spanID:   spanID,
+		parentID: parentID,
+		sampled:  flags&1 != 0,
+		remote:   true,
+	}, true
+}
+
+// span is the Span implementation of Tracer.
+type span struct {
+	tracer   *Tracer
+	traceID  uint64
+	spanID   uint64
+	parentID uint64
+	sampled  bool
+	// remote is set for the spans extracted from another process.
+	// They are only used as parents, and are never exported.
+	remote bool
+
+	mu          sync.Mutex
+	kind        string
+	label       string
+	start       time.Time
+	annotations map[string]string
+}
+
+func (s *span) begin(kind, label string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.kind = kind
+	s.label = label
+	s.start = time.Now()
+	s.annotations = nil
+}
+
+// StartLocal is part of the Span interface.
+func (s *span) StartLocal(label string) {
+	s.begin(KindLocal, label)
+}
+
+// StartClient is part of the Span interface.
+func (s *span) StartClient(label string) {
+	s.begin(KindClient, label)
+}
+
+// StartServer is part of the Span interface.
+func (s *span) StartServer(label string) {
+	s.begin(KindServer, label)
+}
+
+// Finish is part of the Span interface.
+func (s *span) Finish() {
+	if !s.sampled || s.remote {
+		return
+	}
+	s.mu.Lock()
+	if s.start.IsZero() {
+		s.mu.Unlock()
+		return
+	}
+	data := &SpanData{
+		Service:     s.tracer.service,
+		TraceID:     s.traceID,
+		SpanID:      s.spanID,
+		ParentID:    s.parentID,
+		Kind:        s.kind,
+		Label:       s.label,
+		Start:       s.start,
+		Duration:    time.Since(s.start),
+		Annotations: s.annotations,
+	}
+	s.start = time.Time{}
+	s.annotations = nil
+	s.mu.Unlock()
+	s.tracer.exporter.ExportSpan(data)
+}
+
+// Annotate is part of the Span interface.
+func (s *span) Annotate(key string, value interface{}) {
+	if !s.sampled {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.annotations == nil {
+		s.annotations = make(map[string]string)
+	}
+	s.annotations[key] = fmt.Sprint(value)
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+// memoryExporter keeps the exported spans in memory.
+type memoryExporter struct {
+	mu    sync.Mutex
+	spans []*SpanData
+}
+
+func (me *memoryExporter) ExportSpan(data *SpanData) {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	me.spans = append(me.spans, data)
+}
+
+func (me *memoryExporter) Close() error { return nil }
+
+func TestTracerParentChild(t *testing.T) {
+	exporter := &memoryExporter{}
+	RegisterSpanFactory(NewTracer("test", exporter, 1))
+	defer RegisterSpanFactory(fakeSpanFactory{})
+
+	ctx := context.Background()
+	parent := NewSpanFromContext(ctx)
+	parent.StartServer("parent")
+	parent.Annotate("key", 10)
+	ctx = NewContext(ctx, parent)
+
+	child := NewSpanFromContext(ctx)
+	child.StartClient("child")
+	child.Finish()
+	parent.Finish()
+
+	if len(exporter.spans) != 2 {
+		t.Fatalf("got %v spans, want 2", len(exporter.spans))
+	}
+	c, p := exporter.spans[0], exporter.spans[1]
+	if c.Label != "child" || c.Kind != KindClient || p.Label != "parent" || p.Kind != KindServer {
+		t.Errorf("got spans %+v and %+v", c, p)
+	}
+	if c.TraceID != p.TraceID || c.ParentID != p.SpanID || p.ParentID != 0 {
+		t.Errorf("child %+v is not a child of %+v", c, p)
+	}
+	if got := p.Annotations["key"]; got != "10" {
+		t.Errorf("annotation: got %v, want 10", got)
+	}
+	if p.Service != "test" {
+		t.Errorf("service: got %v, want test", p.Service)
+	}
+}
+
+func TestTracerSampling(t *testing.T) {
+	exporter := &memoryExporter{}
+	RegisterSpanFactory(NewTracer("test", exporter, 0))
+	defer RegisterSpanFactory(fakeSpanFactory{})
+
+	ctx := context.Background()
+	parent := NewSpanFromContext(ctx)
+	parent.StartLocal("parent")
+	child := NewSpanFromContext(NewContext(ctx, parent))
+	child.StartLocal("child")
+	child.Finish()
+	parent.Finish()
+
+	if len(exporter.spans) != 0 {
+		t.Errorf("got %v spans, want none", len(exporter.spans))
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	exporter := &memoryExporter{}
+	RegisterSpanFactory(NewTracer("test", exporter, 1))
+	defer RegisterSpanFactory(fakeSpanFactory{})
+
+	span := NewSpanFromContext(context.Background())
+	span.StartClient("client")
+	ctx := NewContext(context.Background(), span)
+
+	value := SpanContext(ctx)
+	if value == "" {
+		t.Fatalf("SpanContext returned nothing")
+	}
+	if got := SampledSpanContext(ctx); got != value {
+		t.Errorf("SampledSpanContext: got %v, want %v", got, value)
+	}
+	remoteCtx, ok := ContinueSpanContext(context.Background(), value)
+	if !ok {
+		t.Errorf("ContinueSpanContext(%v) failed", value)
+	}
+	for _, remoteCtx := range []context.Context{
+		WithSpanContext(context.Background(), value),
+		remoteCtx,
+	} {
+		server := NewSpanFromContext(remoteCtx)
+		server.StartServer("server")
+		server.Finish()
+	}
+	span.Finish()
+
+	if len(exporter.spans) != 3 {
+		t.Fatalf("got %v spans, want 3", len(exporter.spans))
+	}
+	client := exporter.spans[2]
+	for _, server := range exporter.spans[:2] {
+		if server.TraceID != client.TraceID || server.ParentID != client.SpanID {
+			t.Errorf("server %+v does not continue client %+v", server, client)
+		}
+	}
+
+	// Invalid or missing span contexts leave the context alone.
+	for _, value := range []string{"", "junk"} {
+		if _, ok := FromContext(WithSpanContext(context.Background(), value)); ok {
+			t.Errorf("unexpected span in context for %q", value)
+		}
+		if _, ok := ContinueSpanContext(context.Background(), value); ok {
+			t.Errorf("ContinueSpanContext(%q) succeeded", value)
+		}
+	}
+}
+
+func TestPropagationSampling(t *testing.T) {
+	exporter := &memoryExporter{}
+	RegisterSpanFactory(NewTracer("test", exporter, 0))
+	defer RegisterSpanFactory(fakeSpanFactory{})
+
+	span := NewSpanFromContext(context.Background())
+	span.StartClient("client")
+	defer span.Finish()
+	ctx := NewContext(context.Background(), span)
+
+	// The sampling decision is propagated to other processes, but
+	// MySQL doesn't need the span context of a trace that isn't recorded.
+	if got, want := SpanContext(ctx), ":0"; !strings.HasSuffix(got, want) {
+		t.Errorf("SpanContext: got %v, want a value ending with %v", got, want)
+	}
+	if got := SampledSpanContext(ctx); got != "" {
+		t.Errorf("SampledSpanContext: got %v, want empty", got)
+	}
+}
+
+func TestPropagationFake(t *testing.T) {
+	RegisterSpanFactory(fakeSpanFactory{})
+	ctx := context.Background()
+	if got := SpanContext(ctx); got != "" {
+		t.Errorf("SpanContext: got %v, want empty", got)
+	}
+	if got := SampledSpanContext(ctx); got != "" {
+		t.Errorf("SampledSpanContext: got %v, want empty", got)
+	}
+	if got := WithSpanContext(ctx, "1:2:0:1"); got != ctx {
+		t.Errorf("WithSpanContext changed the context")
+	}
+}
+
+func TestFileExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := path.Join(dir, "spans.json")
+
+	exporter, err := NewFileExporter(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	RegisterSpanFactory(NewTracer("test", exporter, 1))
+	defer RegisterSpanFactory(fakeSpanFactory{})
+
+	for _, label := range []string{"first", "second"} {
+		span := NewSpanFromContext(context.Background())
+		span.StartLocal(label)
+		span.Finish()
+	}
+	if err := exporter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var labels []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		data := &SpanData{}
+		if err := json.Unmarshal(scanner.Bytes(), data); err != nil {
+			t.Fatalf("cannot decode %q: %v", scanner.Text(), err)
+		}
+		labels = append(labels, data.Label)
+	}
+	if len(labels) != 2 || labels[0] != "first" || labels[1] != "second" {
+		t.Errorf("got spans %v, want [first second]", labels)
+	}
+}
@@ -73,6 +73,16 @@ type FullBinlogStatement struct {
 	KeyspaceID []byte
 	PKNames    []*querypb.Field
 	PKValues   []sqltypes.Value
+
+	// Fields, Before and After are only set for row based events if
+	// the Streamer extracts row images. Before and After are the
+	// values of all the Fields before and after the change. They
+	// are nil if the event does not have the full row, like with
+	// binlog_row_image=MINIMAL, or for the missing side of inserts
+	// and deletes.
+	Fields []*querypb.Field
+	Before []sqltypes.Value
+	After  []sqltypes.Value
 }
 
 // sendTransactionFunc is used to send binlog events.
@@ -112,6 +122,18 @@ type tableCacheEntry struct {
 	// pkNames contains an array of fields for the PK.
 	pkNames []*querypb.Field
 
+	// fields contains the fields of the columns in the events, if
+	// row images are extracted.
+	fields []*querypb.Field
+
+	// whereColumns tells, for each column, if it is used to
+	// identify rows in the WHERE clause of UPDATE and DELETE
+	// statements. It is nil if all the columns sent by MySQL are
+	// used, when the table has no primary key. Otherwise only the
+	// PK columns are used: comparing the other columns, like floats,
+	// may not match the row.
+	whereColumns []bool
+
 	// pkIndexes contains the index of a given column in the
 	// PK. It is -1 f the column is not in any PK. It contains as
 	// many fields as there are columns in the table.
@@ -137,6 +159,7 @@ type Streamer struct {
 	se              *schema.Engine
 	resolverFactory keyspaceIDResolverFactory
 	extractPK       bool
+	extractImages   bool
 
 	clientCharset    *binlogdatapb.Charset
 	startPos         mysql.Position
@@ -461,17 +484,30 @@ func (bls *Streamer) parseEvents(ctx context.Context, events <-chan mysql.Binlog
 				continue
 			}
 
-			// Find and fill in the table schema.
+			// Find and fill in the table schema. If the table
+			// was altered, the schema may not know it yet. It
+			// may have more columns than the event though, if
+			// they were added at the end since.
 			tce.ti = bls.se.GetTable(sqlparser.NewTableIdent(tm.Name))
+			if tce.ti == nil || len(tce.ti.Columns) < len(tm.Types) {
+				if err := bls.se.Reload(ctx); err != nil {
+					return pos, fmt.Errorf("cannot reload schema for table %v: %v", tm.Name, err)
+				}
+				tce.ti = bls.se.GetTable(sqlparser.NewTableIdent(tm.Name))
+			}
 			if tce.ti == nil {
 				return pos, fmt.Errorf("unknown table %v in schema", tm.Name)
 			}
+			if len(tce.ti.Columns) < len(tm.Types) {
+				return pos, fmt.Errorf("table %v has %v columns in the binlog, but only %v in the schema", tm.Name, len(tm.Types), len(tce.ti.Columns))
+			}
+			tce.whereColumns = pkColumns(tce.ti, len(tm.Types))
 
 			// Fill in the resolver if needed.
 			if bls.resolverFactory != nil {
 				tce.keyspaceIDIndex, tce.resolver, err = bls.resolverFactory(tce.ti)
 				if err != nil {
-					return pos, fmt.Errorf("cannot find column to use to find keyspace_id for table %v", tm.Name)
+					return pos, fmt.Errorf("cannot find column to use to find keyspace_id for table %v: %v", tm.Name, err)
 				}
 			}
 
@@ -493,6 +529,17 @@ func (bls *Streamer) parseEvents(ctx context.Context, events <-chan mysql.Binlog
 					}
 				}
 			}
+
+			// Fill in the fields of the row images if necessary.
+			if bls.extractImages {
+				tce.fields = make([]*querypb.Field, len(tm.Types))
+				for c := range tce.fields {
+					tce.fields[c] = &querypb.Field{
+						Name: tce.ti.Columns[c].Name.String(),
+						Type: tce.ti.Columns[c].Type,
+					}
+				}
+			}
 		case ev.IsWriteRows():
 			tableID := ev.TableID(format)
 			tce, ok := tableMaps[tableID]
@@ -516,7 +563,10 @@ func (bls *Streamer) parseEvents(ctx context.Context, events <-chan mysql.Binlog
 				return pos, err
 			}
 
-			statements = bls.appendInserts(statements, tce, &rows)
+			statements, err = bls.appendInserts(statements, tce, &rows)
+			if err != nil {
+				return pos, err
+			}
 
 			if autocommit {
 				if err = commit(ev.Timestamp()); err != nil {
@@ -546,7 +596,10 @@ func (bls *Streamer) parseEvents(ctx context.Context, events <-chan mysql.Binlog
 				return pos, err
 			}
 
-			statements = bls.appendUpdates(statements, tce, &rows)
+			statements, err = bls.appendUpdates(statements, tce, &rows)
+			if err != nil {
+				return pos, err
+			}
 
 			if autocommit {
 				if err = commit(ev.Timestamp()); err != nil {
@@ -576,7 +629,10 @@ func (bls *Streamer) parseEvents(ctx context.Context, events <-chan mysql.Binlog
 				return pos, err
 			}
 
-			statements = bls.appendDeletes(statements, tce, &rows)
+			statements, err = bls.appendDeletes(statements, tce, &rows)
+			if err != nil {
+				return pos, err
+			}
 
 			if autocommit {
 				if err = commit(ev.Timestamp()); err != nil {
@@ -587,25 +643,21 @@ func (bls *Streamer) parseEvents(ctx context.Context, events <-chan mysql.Binlog
 	}
 }
 
-func (bls *Streamer) appendInserts(statements []FullBinlogStatement, tce *tableCacheEntry, rows *mysql.Rows) []FullBinlogStatement {
+// appendInserts adds an INSERT statement for each row of a WriteRows
+// event.
+func (bls *Streamer) appendInserts(statements []FullBinlogStatement, tce *tableCacheEntry, rows *mysql.Rows) ([]FullBinlogStatement, error) {
 	for i := range rows.Rows {
 		sql := sqlparser.NewTrackedBuffer(nil)
 		sql.Myprintf("INSERT INTO %v SET ", sqlparser.NewTableIdent(tce.tm.Name))
 
-		keyspaceIDCell, pkValues, err := writeValuesAsSQL(sql, tce, rows, i, tce.pkNames != nil)
+		after := bls.newRowImage(tce, rows.DataColumns)
+		keyspaceIDCell, pkValues, err := writeValuesAsSQL(sql, tce, rows, i, tce.pkNames != nil, after)
 		if err != nil {
-			log.Warningf("writeValuesAsSQL(%v) failed: %v", i, err)
-			continue
+			return nil, fmt.Errorf("cannot decode row %v of WriteRows event for table %v: %v", i, tce.tm.Name, err)
 		}
-
-		// Fill in keyspace id if needed.
-		var ksid []byte
-		if tce.resolver != nil {
-			var err error
-			ksid, err = tce.resolver.keyspaceID(keyspaceIDCell)
-			if err != nil {
-				log.Warningf("resolver(%v) failed: %v", err)
-			}
+		ksid, err := tce.keyspaceID(keyspaceIDCell)
+		if err != nil {
+			return nil, err
 		}
 
 		statement := &binlogdatapb.BinlogTransaction_Statement{
@@ -618,39 +670,84 @@ func (bls *Streamer) appendInserts(statements []FullBinlogStatement, tce *tableC
 			KeyspaceID: ksid,
 			PKNames:    tce.pkNames,
 			PKValues:   pkValues,
+			Fields:     tce.fields,
+			After:      after,
 		})
 	}
-	return statements
+	return statements, nil
 }
 
-func (bls *Streamer) appendUpdates(statements []FullBinlogStatement, tce *tableCacheEntry, rows *mysql.Rows) []FullBinlogStatement {
+// appendUpdates adds an UPDATE statement for each row of an UpdateRows
+// event. If an update changes the keyspace id of a row, the row may move
+// to another shard: it is then sent as a DELETE of the old row followed
+// by an INSERT of the new row, each with its own keyspace id.
+func (bls *Streamer) appendUpdates(statements []FullBinlogStatement, tce *tableCacheEntry, rows *mysql.Rows) ([]FullBinlogStatement, error) {
+	table := sqlparser.NewTableIdent(tce.tm.Name)
 	for i := range rows.Rows {
-		sql := sqlparser.NewTrackedBuffer(nil)
-		sql.Myprintf("UPDATE %v SET ", sqlparser.NewTableIdent(tce.tm.Name))
-
-		keyspaceIDCell, pkValues, err := writeValuesAsSQL(sql, tce, rows, i, tce.pkNames != nil)
+		values := sqlparser.NewTrackedBuffer(nil)
+		after := bls.newRowImage(tce, rows.DataColumns)
+		afterCell, pkValues, err := writeValuesAsSQL(values, tce, rows, i, tce.pkNames != nil, after)
 		if err != nil {
-			log.Warningf("writeValuesAsSQL(%v) failed: %v", i, err)
-			continue
+			return nil, fmt.Errorf("cannot decode row %v of UpdateRows event for table %v: %v", i, tce.tm.Name, err)
+		}
+		where := sqlparser.NewTrackedBuffer(nil)
+		before := bls.newRowImage(tce, rows.IdentifyColumns)
+		beforeCell, beforePKValues, err := writeIdentifiersAsSQL(where, tce, rows, i, tce.pkNames != nil, before)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode row %v of UpdateRows event for table %v: %v", i, tce.tm.Name, err)
 		}
 
-		sql.WriteString(" WHERE ")
-
-		if _, _, err := writeIdentifiersAsSQL(sql, tce, rows, i, false); err != nil {
-			log.Warningf("writeIdentifiesAsSQL(%v) failed: %v", i, err)
-			continue
+		// Fill in keyspace id if needed. With a minimal row
+		// image, the new row only has the changed columns.
+		beforeKsid, err := tce.keyspaceID(beforeCell)
+		if err != nil {
+			return nil, err
+		}
+		ksid := beforeKsid
+		if tce.resolver != nil && rows.DataColumns.Bit(tce.keyspaceIDIndex) {
+			if ksid, err = tce.keyspaceID(afterCell); err != nil {
+				return nil, err
+			}
 		}
 
-		// Fill in keyspace id if needed.
-		var ksid []byte
-		if tce.resolver != nil {
-			var err error
-			ksid, err = tce.resolver.keyspaceID(keyspaceIDCell)
-			if err != nil {
-				log.Warningf("resolver(%v) failed: %v", err)
+		if !bytes.Equal(beforeKsid, ksid) {
+			if rows.DataColumns.BitCount() != len(tce.tm.Types) {
+				return nil, fmt.Errorf("update of the keyspace id of a row of table %v needs the full row, use binlog_row_image=FULL", tce.tm.Name)
 			}
+			sql := sqlparser.NewTrackedBuffer(nil)
+			sql.Myprintf("DELETE FROM %v WHERE %s", table, where.String())
+			statements = append(statements, FullBinlogStatement{
+				Statement: &binlogdatapb.BinlogTransaction_Statement{
+					Category: binlogdatapb.BinlogTransaction_Statement_BL_DELETE,
+					Sql:      sql.Bytes(),
+				},
+				Table:      tce.tm.Name,
+				KeyspaceID: beforeKsid,
+				PKNames:    tce.pkNames,
+				PKValues:   beforePKValues,
+				Fields:     tce.fields,
+				Before:     before,
+			})
+
+			sql = sqlparser.NewTrackedBuffer(nil)
+			sql.Myprintf("INSERT INTO %v SET %s", table, values.String())
+			statements = append(statements, FullBinlogStatement{
+				Statement: &binlogdatapb.BinlogTransaction_Statement{
+					Category: binlogdatapb.BinlogTransaction_Statement_BL_INSERT,
+					Sql:      sql.Bytes(),
+				},
+				Table:      tce.tm.Name,
+				KeyspaceID: ksid,
+				PKNames:    tce.pkNames,
+				PKValues:   pkValues,
+				Fields:     tce.fields,
+				After:      after,
+			})
+			continue
 		}
 
+		sql := sqlparser.NewTrackedBuffer(nil)
+		sql.Myprintf("UPDATE %v SET %s WHERE %s", table, values.String(), where.String())
 		update := &binlogdatapb.BinlogTransaction_Statement{
 			Category: binlogdatapb.BinlogTransaction_Statement_BL_UPDATE,
 			Sql:      sql.Bytes(),
@@ -661,30 +758,29 @@ func (bls *Streamer) appendUpdates(statements []FullBinlogStatement, tce *tableC
 			KeyspaceID: ksid,
 			PKNames:    tce.pkNames,
 			PKValues:   pkValues,
+			Fields:     tce.fields,
+			Before:     before,
+			After:      after,
 		})
 	}
-	return statements
+	return statements, nil
 }
 
-func (bls *Streamer) appendDeletes(statements []FullBinlogStatement, tce *tableCacheEntry, rows *mysql.Rows) []FullBinlogStatement {
+// appendDeletes adds a DELETE statement for each row of a DeleteRows
+// event.
+func (bls *Streamer) appendDeletes(statements []FullBinlogStatement, tce *tableCacheEntry, rows *mysql.Rows) ([]FullBinlogStatement, error) {
 	for i := range rows.Rows {
 		sql := sqlparser.NewTrackedBuffer(nil)
 		sql.Myprintf("DELETE FROM %v WHERE ", sqlparser.NewTableIdent(tce.tm.Name))
 
-		keyspaceIDCell, pkValues, err := writeIdentifiersAsSQL(sql, tce, rows, i, tce.pkNames != nil)
+		before := bls.newRowImage(tce, rows.IdentifyColumns)
+		keyspaceIDCell, pkValues, err := writeIdentifiersAsSQL(sql, tce, rows, i, tce.pkNames != nil, before)
 		if err != nil {
-			log.Warningf("writeIdentifiesAsSQL(%v) failed: %v", i, err)
-			continue
+			return nil, fmt.Errorf("cannot decode row %v of DeleteRows event for table %v: %v", i, tce.tm.Name, err)
 		}
-
-		// Fill in keyspace id if needed.
-		var ksid []byte
-		if tce.resolver != nil {
-			var err error
-			ksid, err = tce.resolver.keyspaceID(keyspaceIDCell)
-			if err != nil {
-				log.Warningf("resolver(%v) failed: %v", err)
-			}
+		ksid, err := tce.keyspaceID(keyspaceIDCell)
+		if err != nil {
+			return nil, err
 		}
 
 		statement := &binlogdatapb.BinlogTransaction_Statement{
@@ -697,15 +793,63 @@ func (bls *Streamer) appendDeletes(statements []FullBinlogStatement, tce *tableC
 			KeyspaceID: ksid,
 			PKNames:    tce.pkNames,
 			PKValues:   pkValues,
+			Fields:     tce.fields,
+			Before:     before,
 		})
 	}
-	return statements
+	return statements, nil
+}
+
+// newRowImage returns the slice the values of a row are decoded into,
+// or nil if row images are not extracted or if the event, which has the
+// given columns, does not have the full row.
+func (bls *Streamer) newRowImage(tce *tableCacheEntry, columns mysql.Bitmap) []sqltypes.Value {
+	if !bls.extractImages || columns.BitCount() != len(tce.tm.Types) {
+		return nil
+	}
+	return make([]sqltypes.Value, len(tce.tm.Types))
+}
+
+// pkColumns returns, for each column of a table, if it is part of its
+// primary key. It returns nil if the table has no primary key, or if
+// one of its columns is not in the events, which have count columns.
+func pkColumns(ti *schema.Table, count int) []bool {
+	if len(ti.PKColumns) == 0 {
+		return nil
+	}
+	result := make([]bool, len(ti.Columns))
+	for _, c := range ti.PKColumns {
+		if c >= count {
+			return nil
+		}
+		result[c] = true
+	}
+	return result
+}
+
+// keyspaceID returns the keyspace id of a row, given the value of its
+// keyspace id column, or nil if keyspace ids are not needed. A row that
+// can't be mapped would be lost by filtered replication, so it is an
+// error.
+func (tce *tableCacheEntry) keyspaceID(keyspaceIDCell sqltypes.Value) ([]byte, error) {
+	if tce.resolver == nil {
+		return nil, nil
+	}
+	if keyspaceIDCell.IsNull() {
+		return nil, fmt.Errorf("no value for the keyspace id column %v of table %v in the binlog (binlog_row_image=FULL may be needed)", tce.ti.Columns[tce.keyspaceIDIndex].Name, tce.tm.Name)
+	}
+	ksid, err := tce.resolver.keyspaceID(keyspaceIDCell)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute keyspace id of %v for table %v: %v", keyspaceIDCell, tce.tm.Name, err)
+	}
+	return ksid, nil
 }
 
 // writeValuesAsSQL is a helper method to print the values as SQL in the
 // provided bytes.Buffer. It also returns the value for the keyspaceIDColumn,
-// and the array of values for the PK, if necessary.
-func writeValuesAsSQL(sql *sqlparser.TrackedBuffer, tce *tableCacheEntry, rs *mysql.Rows, rowIndex int, getPK bool) (sqltypes.Value, []sqltypes.Value, error) {
+// and the array of values for the PK, if necessary. If image is not nil,
+// the values are also stored in it, by column index.
+func writeValuesAsSQL(sql *sqlparser.TrackedBuffer, tce *tableCacheEntry, rs *mysql.Rows, rowIndex int, getPK bool, image []sqltypes.Value) (sqltypes.Value, []sqltypes.Value, error) {
 	valueIndex := 0
 	data := rs.Rows[rowIndex].Data
 	pos := 0
@@ -751,6 +895,9 @@ func writeValuesAsSQL(sql *sqlparser.TrackedBuffer, tce *tableCacheEntry, rs *my
 		if c == tce.keyspaceIDIndex {
 			keyspaceIDCell = value
 		}
+		if image != nil {
+			image[c] = value
+		}
 		if getPK {
 			if tce.pkIndexes[c] != -1 {
 				pkValues[tce.pkIndexes[c]] = value
@@ -765,9 +912,11 @@ func writeValuesAsSQL(sql *sqlparser.TrackedBuffer, tce *tableCacheEntry, rs *my
 
 // writeIdentifiersAsSQL is a helper method to print the identifies as SQL in the
 // provided bytes.Buffer. It also returns the value for the keyspaceIDColumn,
-// and the array of values for the PK, if necessary.
-func writeIdentifiersAsSQL(sql *sqlparser.TrackedBuffer, tce *tableCacheEntry, rs *mysql.Rows, rowIndex int, getPK bool) (sqltypes.Value, []sqltypes.Value, error) {
+// and the array of values for the PK, if necessary. If image is not nil,
+// the values are also stored in it, by column index.
+func writeIdentifiersAsSQL(sql *sqlparser.TrackedBuffer, tce *tableCacheEntry, rs *mysql.Rows, rowIndex int, getPK bool, image []sqltypes.Value) (sqltypes.Value, []sqltypes.Value, error) {
 	valueIndex := 0
+	printed := 0
 	data := rs.Rows[rowIndex].Identify
 	pos := 0
 	var keyspaceIDCell sqltypes.Value
@@ -780,38 +929,30 @@ func writeIdentifiersAsSQL(sql *sqlparser.TrackedBuffer, tce *tableCacheEntry, r
 			continue
 		}
 
-		// Print a separator if needed, then print the name.
-		if valueIndex > 0 {
-			sql.WriteString(" AND ")
-		}
-		sql.Myprintf("%v", tce.ti.Columns[c].Name)
-
 		if rs.Rows[rowIndex].NullIdentifyColumns.Bit(valueIndex) {
 			// This column is represented, but its value is NULL.
-			sql.WriteString(" IS NULL")
+			if tce.whereColumns == nil || tce.whereColumns[c] {
+				if printed > 0 {
+					sql.WriteString(" AND ")
+				}
+				sql.Myprintf("%v IS NULL", tce.ti.Columns[c].Name)
+				printed++
+			}
 			valueIndex++
 			continue
 		}
-		sql.WriteByte('=')
 
 		// We have real data.
 		value, l, err := mysql.CellValue(data, pos, tce.tm.Types[c], tce.tm.Metadata[c], tce.ti.Columns[c].Type)
 		if err != nil {
 			return keyspaceIDCell, nil, err
 		}
-		if value.Type() == querypb.Type_TIMESTAMP && !bytes.HasPrefix(value.ToBytes(), mysql.ZeroTimestamp) {
-			// Values in the binary log are UTC. Let's convert them
-			// to whatever timezone the connection is using,
-			// so MySQL properly converts them back to UTC.
-			sql.WriteString("convert_tz(")
-			value.EncodeSQL(sql)
-			sql.WriteString(", '+00:00', @@session.time_zone)")
-		} else {
-			value.EncodeSQL(sql)
-		}
 		if c == tce.keyspaceIDIndex {
 			keyspaceIDCell = value
 		}
+		if image != nil {
+			image[c] = value
+		}
 		if getPK {
 			if tce.pkIndexes[c] != -1 {
 				pkValues[tce.pkIndexes[c]] = value
@@ -819,6 +960,28 @@ func writeIdentifiersAsSQL(sql *sqlparser.TrackedBuffer, tce *tableCacheEntry, r
 		}
 		pos += l
 		valueIndex++
+		if tce.whereColumns != nil && !tce.whereColumns[c] {
+			// Not part of the primary key.
+			continue
+		}
+
+		// Print a separator if needed, then print the name
+		// and the value.
+		if printed > 0 {
+			sql.WriteString(" AND ")
+		}
+		sql.Myprintf("%v=", tce.ti.Columns[c].Name)
+		printed++
+		if value.Type() == querypb.Type_TIMESTAMP && !bytes.HasPrefix(value.ToBytes(), mysql.ZeroTimestamp) {
+			// Values in the binary log are UTC. Let's convert them
+			// to whatever timezone the connection is using,
+			// so MySQL properly converts them back to UTC.
+			sql.WriteString("convert_tz(")
+			value.EncodeSQL(sql)
+			sql.WriteString(", '+00:00', @@session.time_zone)")
+		} else {
+			value.EncodeSQL(sql)
+		}
 	}
 
 	return keyspaceIDCell, pkValues, nil
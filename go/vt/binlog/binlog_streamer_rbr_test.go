@@ -18,16 +18,19 @@ package binlog
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"golang.org/x/net/context"
 
 	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 // This file tests the RBR events are parsed correctly.
@@ -512,3 +515,139 @@ func TestStreamerParseRBRNameEscapes(t *testing.T) {
 		}
 	}
 }
+
+func TestStreamerParseRBRKeyspaceIDs(t *testing.T) {
+	f := mysql.NewMySQL56BinlogFormat()
+	s := mysql.NewFakeBinlogStream()
+	s.ServerID = 62344
+
+	// The table has a primary key, and a sharding column.
+	se := schema.NewEngineForTests()
+	se.SetTableForTests(&schema.Table{
+		Name: sqlparser.NewTableIdent("vt_a"),
+		Columns: []schema.TableColumn{
+			{
+				Name: sqlparser.NewColIdent("id"),
+				Type: querypb.Type_INT64,
+			},
+			{
+				Name: sqlparser.NewColIdent("sharding_key"),
+				Type: querypb.Type_INT64,
+			},
+			{
+				Name: sqlparser.NewColIdent("message"),
+				Type: querypb.Type_VARCHAR,
+			},
+		},
+		PKColumns: []int{0},
+	})
+	tableID := uint64(0x102030405060)
+	tm := &mysql.TableMap{
+		Flags:     0x8090,
+		Database:  "vt_test_keyspace",
+		Name:      "vt_a",
+		Types:     []byte{mysql.TypeLong, mysql.TypeLong, mysql.TypeVarchar},
+		CanBeNull: mysql.NewServerBitmap(3),
+		Metadata:  []uint16{0, 0, 384},
+	}
+
+	fullBitmap := func() mysql.Bitmap {
+		b := mysql.NewServerBitmap(3)
+		for i := 0; i < 3; i++ {
+			b.Set(i, true)
+		}
+		return b
+	}
+	updateRows := func(before, after []byte) mysql.Rows {
+		return mysql.Rows{
+			IdentifyColumns: fullBitmap(),
+			DataColumns:     fullBitmap(),
+			Rows: []mysql.Row{{
+				NullIdentifyColumns: mysql.NewServerBitmap(3),
+				NullColumns:         mysql.NewServerBitmap(3),
+				Identify:            before,
+				Data:                after,
+			}},
+		}
+	}
+	row := func(id, shardingKey byte, message string) []byte {
+		return append([]byte{id, 0, 0, 0, shardingKey, 0, 0, 0, byte(len(message)), 0}, message...)
+	}
+
+	// The first update keeps the keyspace id, the second one changes it.
+	input := []mysql.BinlogEvent{
+		mysql.NewRotateEvent(f, s, 0, ""),
+		mysql.NewFormatDescriptionEvent(f, s),
+		mysql.NewTableMapEvent(f, s, tableID, tm),
+		mysql.NewMariaDBGTIDEvent(f, s, mysql.MariadbGTID{Domain: 0, Sequence: 0xd}, false /* hasBegin */),
+		mysql.NewQueryEvent(f, s, mysql.Query{
+			Database: "vt_test_keyspace",
+			SQL:      "BEGIN"}),
+		mysql.NewUpdateRowsEvent(f, s, tableID, updateRows(row(1, 1, "abc"), row(1, 1, "abcd"))),
+		mysql.NewUpdateRowsEvent(f, s, tableID, updateRows(row(1, 1, "abcd"), row(1, 2, "abcd"))),
+		mysql.NewXIDEvent(f, s),
+	}
+
+	type statement struct {
+		sql  string
+		ksid []byte
+	}
+	want := []statement{
+		{"SET TIMESTAMP=1407805592", nil},
+		{"UPDATE vt_a SET id=1, sharding_key=1, message='abcd' WHERE id=1", key.Uint64Key(1).Bytes()},
+		{"SET TIMESTAMP=1407805592", nil},
+		{"DELETE FROM vt_a WHERE id=1", key.Uint64Key(1).Bytes()},
+		{"INSERT INTO vt_a SET id=1, sharding_key=2, message='abcd'", key.Uint64Key(2).Bytes()},
+	}
+	var got []statement
+	sendTransaction := func(eventToken *querypb.EventToken, statements []FullBinlogStatement) error {
+		for _, s := range statements {
+			got = append(got, statement{string(s.Statement.Sql), s.KeyspaceID})
+		}
+		return nil
+	}
+	bls := NewStreamer(&mysql.ConnParams{DbName: "vt_test_keyspace"}, se, nil, mysql.Position{}, 0, sendTransaction)
+	bls.resolverFactory = func(table *schema.Table) (int, keyspaceIDResolver, error) {
+		return 1, &keyspaceIDResolverFactoryV2{shardingColumnType: topodatapb.KeyspaceIdType_UINT64}, nil
+	}
+
+	events := make(chan mysql.BinlogEvent)
+	go sendTestEvents(events, input)
+	if _, err := bls.parseEvents(context.Background(), events); err != ErrServerEOF {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("binlogConnStreamer.parseEvents():\ngot:  %v\nwant: %v", got, want)
+	}
+
+	// With a minimal row image, the sharding column may be missing:
+	// the stream stops instead of losing the row.
+	minimal := mysql.Rows{
+		IdentifyColumns: mysql.NewServerBitmap(3),
+		DataColumns:     mysql.NewServerBitmap(3),
+		Rows: []mysql.Row{{
+			NullIdentifyColumns: mysql.NewServerBitmap(3),
+			NullColumns:         mysql.NewServerBitmap(3),
+			Identify:            []byte{1, 0, 0, 0},
+			Data:                []byte{3, 0, 'x', 'y', 'z'},
+		}},
+	}
+	minimal.IdentifyColumns.Set(0, true)
+	minimal.DataColumns.Set(2, true)
+	input = []mysql.BinlogEvent{
+		mysql.NewRotateEvent(f, s, 0, ""),
+		mysql.NewFormatDescriptionEvent(f, s),
+		mysql.NewTableMapEvent(f, s, tableID, tm),
+		mysql.NewMariaDBGTIDEvent(f, s, mysql.MariadbGTID{Domain: 0, Sequence: 0xe}, false /* hasBegin */),
+		mysql.NewQueryEvent(f, s, mysql.Query{
+			Database: "vt_test_keyspace",
+			SQL:      "BEGIN"}),
+		mysql.NewUpdateRowsEvent(f, s, tableID, minimal),
+	}
+	events = make(chan mysql.BinlogEvent)
+	go sendTestEvents(events, input)
+	_, err := bls.parseEvents(context.Background(), events)
+	if err == nil || !strings.Contains(err.Error(), "no value for the keyspace id column sharding_key") {
+		t.Errorf("parseEvents with a minimal row image: %v, want missing keyspace id column", err)
+	}
+}
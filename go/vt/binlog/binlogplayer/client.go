@@ -18,6 +18,7 @@ package binlogplayer
 
 import (
 	"flag"
+	"fmt"
 
 	"golang.org/x/net/context"
 
@@ -69,3 +70,13 @@ func RegisterClientFactory(name string, factory ClientFactory) {
 	}
 	clientFactories[name] = factory
 }
+
+// NewClient returns a new Client for the protocol selected by
+// the -binlog_player_protocol flag.
+func NewClient() (Client, error) {
+	clientFactory, ok := clientFactories[*binlogPlayerProtocol]
+	if !ok {
+		return nil, fmt.Errorf("no binlog player client factory named %v", *binlogPlayerProtocol)
+	}
+	return clientFactory(), nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binlog
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+type sendChangeFunc func(event *querypb.ChangeEvent) error
+
+// ChangeStreamer is an adapter on top of a binlog Streamer that converts
+// the transactions into querypb.ChangeEvent objects, with the row images if the
+// binlogs have them.
+type ChangeStreamer struct {
+	bls        *Streamer
+	sendChange sendChangeFunc
+}
+
+// NewChangeStreamer returns a new ChangeStreamer on top of a Streamer.
+// It streams from the current position if startPos is zero.
+func NewChangeStreamer(cp *mysql.ConnParams, se *schema.Engine, startPos mysql.Position, sendChange sendChangeFunc) *ChangeStreamer {
+	cs := &ChangeStreamer{
+		sendChange: sendChange,
+	}
+	cs.bls = NewStreamer(cp, se, nil, startPos, 0, cs.transactionToChange)
+	cs.bls.extractPK = true
+	cs.bls.extractImages = true
+	return cs
+}
+
+// Stream starts streaming changes.
+func (cs *ChangeStreamer) Stream(ctx context.Context) error {
+	return cs.bls.Stream(ctx)
+}
+
+func (cs *ChangeStreamer) transactionToChange(eventToken *querypb.EventToken, statements []FullBinlogStatement) error {
+	event := &querypb.ChangeEvent{
+		Timestamp: eventToken.GetTimestamp(),
+		Position:  eventToken.GetPosition(),
+	}
+	var insertid int64
+	for _, stmt := range statements {
+		switch stmt.Statement.Category {
+		case binlogdatapb.BinlogTransaction_Statement_BL_SET:
+			sql := string(stmt.Statement.Sql)
+			if strings.HasPrefix(sql, binlogSetInsertID) {
+				var err error
+				insertid, err = strconv.ParseInt(sql[binlogSetInsertIDLen:], 10, 64)
+				if err != nil {
+					binlogStreamerErrors.Add("ChangeStreamer", 1)
+					log.Errorf("%v: %s", err, sql)
+				}
+			}
+		case binlogdatapb.BinlogTransaction_Statement_BL_INSERT,
+			binlogdatapb.BinlogTransaction_Statement_BL_UPDATE,
+			binlogdatapb.BinlogTransaction_Statement_BL_DELETE:
+			var change *querypb.RowChange
+			change, insertid = buildRowChange(stmt, insertid)
+			event.Changes = append(event.Changes, change)
+		case binlogdatapb.BinlogTransaction_Statement_BL_DDL:
+			event.Changes = append(event.Changes, &querypb.RowChange{
+				Type: querypb.RowChange_DDL,
+				Sql:  stmt.Statement.Sql,
+			})
+		case binlogdatapb.BinlogTransaction_Statement_BL_UNRECOGNIZED:
+			event.Changes = append(event.Changes, &querypb.RowChange{
+				Type: querypb.RowChange_UNRECOGNIZED,
+				Sql:  stmt.Statement.Sql,
+			})
+		default:
+			binlogStreamerErrors.Add("ChangeStreamer", 1)
+			log.Errorf("Unrecognized event: %v: %s", stmt.Statement.Category, stmt.Statement.Sql)
+		}
+	}
+	return cs.sendChange(event)
+}
+
+// buildRowChange converts a DML into a RowChange. The primary keys are
+// recovered like for the EventStreamer. If they cannot be, the change
+// only has the statement.
+func buildRowChange(stmt FullBinlogStatement, insertid int64) (*querypb.RowChange, int64) {
+	change := &querypb.RowChange{
+		Table: stmt.Table,
+	}
+	switch stmt.Statement.Category {
+	case binlogdatapb.BinlogTransaction_Statement_BL_INSERT:
+		change.Type = querypb.RowChange_INSERT
+	case binlogdatapb.BinlogTransaction_Statement_BL_UPDATE:
+		change.Type = querypb.RowChange_UPDATE
+	case binlogdatapb.BinlogTransaction_Statement_BL_DELETE:
+		change.Type = querypb.RowChange_DELETE
+	}
+
+	if stmt.Before != nil || stmt.After != nil {
+		change.Fields = stmt.Fields
+		if stmt.Before != nil {
+			change.Before = sqltypes.RowToProto3(stmt.Before)
+		}
+		if stmt.After != nil {
+			change.After = sqltypes.RowToProto3(stmt.After)
+		}
+	} else {
+		change.Sql = stmt.Statement.Sql
+	}
+
+	dmlStatement, insertid, err := buildDMLStatement(stmt, insertid)
+	if err != nil {
+		binlogStreamerErrors.Add("ChangeStreamer", 1)
+		log.Errorf("cannot find the primary key of %s: %v", stmt.Statement.Sql, err)
+		change.Sql = stmt.Statement.Sql
+		return change, insertid
+	}
+	if change.Table == "" {
+		change.Table = dmlStatement.TableName
+	}
+	change.PkFields = dmlStatement.PrimaryKeyFields
+	change.PkValues = dmlStatement.PrimaryKeyValues
+	return change, insertid
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binlog
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestChangeEvent(t *testing.T) {
+	fields := []*querypb.Field{{
+		Name: "id",
+		Type: querypb.Type_INT64,
+	}, {
+		Name: "name",
+		Type: querypb.Type_VARCHAR,
+	}, {
+		Name: "data",
+		Type: querypb.Type_BLOB,
+	}}
+	pkNames := fields[:1]
+	statements := []FullBinlogStatement{
+		{
+			Statement: &binlogdatapb.BinlogTransaction_Statement{
+				Category: binlogdatapb.BinlogTransaction_Statement_BL_SET,
+				Sql:      []byte("SET INSERT_ID=10"),
+			},
+		},
+		{
+			// Statement based.
+			Statement: &binlogdatapb.BinlogTransaction_Statement{
+				Category: binlogdatapb.BinlogTransaction_Statement_BL_INSERT,
+				Sql:      []byte("insert into t(name) values ('a') /* _stream t (id ) (null ); */"),
+			},
+		},
+		{
+			// Row based, with images.
+			Statement: &binlogdatapb.BinlogTransaction_Statement{
+				Category: binlogdatapb.BinlogTransaction_Statement_BL_UPDATE,
+				Sql:      []byte("UPDATE t SET id=1, name='b', data='\\0' WHERE id=1"),
+			},
+			Table:    "t",
+			PKNames:  pkNames,
+			PKValues: []sqltypes.Value{sqltypes.NewInt64(1)},
+			Fields:   fields,
+			Before:   []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("a"), sqltypes.NULL},
+			After:    []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("b"), sqltypes.NewVarBinary("\x00")},
+		},
+		{
+			// Row based, without images.
+			Statement: &binlogdatapb.BinlogTransaction_Statement{
+				Category: binlogdatapb.BinlogTransaction_Statement_BL_DELETE,
+				Sql:      []byte("DELETE FROM t WHERE id=2"),
+			},
+			Table:    "t",
+			PKNames:  pkNames,
+			PKValues: []sqltypes.Value{sqltypes.NewInt64(2)},
+		},
+		{
+			Statement: &binlogdatapb.BinlogTransaction_Statement{
+				Category: binlogdatapb.BinlogTransaction_Statement_BL_INSERT,
+				Sql:      []byte("query"),
+			},
+		},
+		{
+			Statement: &binlogdatapb.BinlogTransaction_Statement{
+				Category: binlogdatapb.BinlogTransaction_Statement_BL_DDL,
+				Sql:      []byte("alter table t add column c int"),
+			},
+		},
+	}
+	eventToken := &querypb.EventToken{
+		Timestamp: 1,
+		Position:  "MariaDB/0-41983-20",
+	}
+
+	var got *querypb.ChangeEvent
+	cs := &ChangeStreamer{
+		sendChange: func(event *querypb.ChangeEvent) error {
+			got = event
+			return nil
+		},
+	}
+	if err := cs.transactionToChange(eventToken, statements); err != nil {
+		t.Fatal(err)
+	}
+	want := &querypb.ChangeEvent{
+		Timestamp: 1,
+		Position:  "MariaDB/0-41983-20",
+		Changes: []*querypb.RowChange{{
+			Type:     querypb.RowChange_INSERT,
+			Table:    "t",
+			PkFields: pkNames,
+			PkValues: []*querypb.Row{sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(10)})},
+			Sql:      []byte("insert into t(name) values ('a') /* _stream t (id ) (null ); */"),
+		}, {
+			Type:     querypb.RowChange_UPDATE,
+			Table:    "t",
+			Fields:   fields,
+			Before:   sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("a"), sqltypes.NULL}),
+			After:    sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("b"), sqltypes.NewVarBinary("\x00")}),
+			PkFields: pkNames,
+			PkValues: []*querypb.Row{sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(1)})},
+		}, {
+			Type:     querypb.RowChange_DELETE,
+			Table:    "t",
+			PkFields: pkNames,
+			PkValues: []*querypb.Row{sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(2)})},
+			Sql:      []byte("DELETE FROM t WHERE id=2"),
+		}, {
+			Type: querypb.RowChange_INSERT,
+			Sql:  []byte("query"),
+		}, {
+			Type: querypb.RowChange_DDL,
+			Sql:  []byte("alter table t add column c int"),
+		}},
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("got:\n%v\nwant:\n%v", got, want)
+	}
+}
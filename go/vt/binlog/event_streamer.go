@@ -17,8 +17,8 @@ limitations under the License.
 package binlog
 
 import (
-	"encoding/base64"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
@@ -27,7 +27,6 @@ import (
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/log"
-	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
@@ -86,7 +85,7 @@ func (evs *EventStreamer) transactionToEvent(eventToken *querypb.EventToken, sta
 			binlogdatapb.BinlogTransaction_Statement_BL_UPDATE,
 			binlogdatapb.BinlogTransaction_Statement_BL_DELETE:
 			var dmlStatement *querypb.StreamEvent_Statement
-			dmlStatement, insertid, err = evs.buildDMLStatement(stmt, insertid)
+			dmlStatement, insertid, err = buildDMLStatement(stmt, insertid)
 			if err != nil {
 				dmlStatement = &querypb.StreamEvent_Statement{
 					Category: querypb.StreamEvent_Statement_Error,
@@ -122,7 +121,7 @@ The _stream comment is extracted into a StreamEvent.Statement.
 */
 // Example query: insert into _table_(foo) values ('foo') /* _stream _table_ (eid id name ) (null 1 'bmFtZQ==' ); */
 // the "null" value is used for auto-increment columns.
-func (evs *EventStreamer) buildDMLStatement(stmt FullBinlogStatement, insertid int64) (*querypb.StreamEvent_Statement, int64, error) {
+func buildDMLStatement(stmt FullBinlogStatement, insertid int64) (*querypb.StreamEvent_Statement, int64, error) {
 	// For RBR events, we know all this already, just extract it.
 	if stmt.PKNames != nil {
 		// We get an array of []sqltypes.Value, need to convert to querypb.Row.
@@ -137,180 +136,92 @@ func (evs *EventStreamer) buildDMLStatement(stmt FullBinlogStatement, insertid i
 		return dmlStatement, 0, nil
 	}
 
-	sql := string(stmt.Statement.Sql)
-
-	// first extract the comment
-	commentIndex := strings.LastIndex(sql, streamCommentStart)
-	if commentIndex == -1 {
-		return nil, insertid, fmt.Errorf("missing stream comment")
+	table, pkNames, pks, insertid, err := ParseStreamComment(string(stmt.Statement.Sql), insertid)
+	if err != nil {
+		return nil, insertid, err
 	}
-	dmlComment := sql[commentIndex+streamCommentStartLen:]
-
-	// then start building the response
 	dmlStatement := &querypb.StreamEvent_Statement{
-		Category: querypb.StreamEvent_Statement_DML,
+		Category:  querypb.StreamEvent_Statement_DML,
+		TableName: table,
 	}
-	tokenizer := sqlparser.NewStringTokenizer(dmlComment)
-
-	// first parse the table name
-	typ, val := tokenizer.Scan()
-	if typ != sqlparser.ID {
-		return nil, insertid, fmt.Errorf("expecting table name in stream comment")
+	for _, name := range pkNames {
+		dmlStatement.PrimaryKeyFields = append(dmlStatement.PrimaryKeyFields, &querypb.Field{
+			Name: name,
+		})
 	}
-	dmlStatement.TableName = string(val)
-
-	// then parse the PK names
-	var err error
-	dmlStatement.PrimaryKeyFields, err = parsePkNames(tokenizer)
-	hasNegatives := make([]bool, len(dmlStatement.PrimaryKeyFields))
-	if err != nil {
+	if err := setPkTypes(dmlStatement.PrimaryKeyFields, pks); err != nil {
 		return nil, insertid, err
 	}
-
-	// then parse the PK values, one at a time
-	for typ, val = tokenizer.Scan(); typ != ';'; typ, val = tokenizer.Scan() {
-		switch typ {
-		case '(':
-			// pkTuple is a list of pk values
-			var pkTuple *querypb.Row
-			pkTuple, insertid, err = parsePkTuple(tokenizer, insertid, dmlStatement.PrimaryKeyFields, hasNegatives)
-			if err != nil {
-				return nil, insertid, err
-			}
-			dmlStatement.PrimaryKeyValues = append(dmlStatement.PrimaryKeyValues, pkTuple)
-		default:
-			return nil, insertid, fmt.Errorf("expecting '('")
-		}
+	for _, pk := range pks {
+		dmlStatement.PrimaryKeyValues = append(dmlStatement.PrimaryKeyValues, sqltypes.RowToProto3(pk))
 	}
-
 	return dmlStatement, insertid, nil
 }
 
-// parsePkNames parses something like (eid id name )
-func parsePkNames(tokenizer *sqlparser.Tokenizer) ([]*querypb.Field, error) {
-	var columns []*querypb.Field
-	if typ, _ := tokenizer.Scan(); typ != '(' {
-		return nil, fmt.Errorf("expecting '('")
-	}
-	for typ, val := tokenizer.Scan(); typ != ')'; typ, val = tokenizer.Scan() {
-		switch typ {
-		case sqlparser.ID:
-			columns = append(columns, &querypb.Field{
-				Name: string(val),
-			})
-		default:
-			return nil, fmt.Errorf("syntax error at position: %d", tokenizer.Position)
-		}
-	}
-	return columns, nil
-}
-
-// parsePkTuple parses something like (null 1 'bmFtZQ==' ). For numbers, the default
-// type is Int64. If an unsigned number that can't fit in an int64 is seen, then the
-// type is set to Uint64. In such cases, if a negative number was previously seen, the
+// setPkTypes sets the types of the PK fields from the values of the
+// stream comment. For numbers, the default type is Int64. If an unsigned
+// number that can't fit in an int64 is seen, then the type is set to
+// Uint64. In such cases, if a negative number was also seen, the
 // function returns an error.
-func parsePkTuple(tokenizer *sqlparser.Tokenizer, insertid int64, fields []*querypb.Field, hasNegatives []bool) (*querypb.Row, int64, error) {
-	result := &querypb.Row{}
-
-	index := 0
-	for typ, val := tokenizer.Scan(); typ != ')'; typ, val = tokenizer.Scan() {
-		if index >= len(fields) {
-			return nil, insertid, fmt.Errorf("length mismatch in values")
-		}
-
-		switch typ {
-		case '-':
-			hasNegatives[index] = true
-			typ2, val2 := tokenizer.Scan()
-			if typ2 != sqlparser.INTEGRAL {
-				return nil, insertid, fmt.Errorf("expecting number after '-'")
-			}
-			fullVal := append([]byte{'-'}, val2...)
-			if _, err := strconv.ParseInt(string(fullVal), 0, 64); err != nil {
-				return nil, insertid, err
-			}
-			switch fields[index].Type {
-			case sqltypes.Null:
-				fields[index].Type = sqltypes.Int64
-			case sqltypes.Int64:
-				// no-op
-			default:
-				return nil, insertid, fmt.Errorf("incompatible negative number field with type %v", fields[index].Type)
-			}
-
-			result.Lengths = append(result.Lengths, int64(len(fullVal)))
-			result.Values = append(result.Values, fullVal...)
-		case sqlparser.INTEGRAL:
-			unsigned, err := strconv.ParseUint(string(val), 0, 64)
-			if err != nil {
-				return nil, insertid, err
-			}
-			if unsigned > uint64(9223372036854775807) {
+func setPkTypes(fields []*querypb.Field, pks [][]sqltypes.Value) error {
+	for index, field := range fields {
+		hasNegatives := false
+		for _, pk := range pks {
+			v := pk[index]
+			switch {
+			case v.Type() == sqltypes.Int64 && strings.HasPrefix(v.ToString(), "-"):
+				hasNegatives = true
+				switch field.Type {
+				case sqltypes.Null:
+					field.Type = sqltypes.Int64
+				case sqltypes.Int64:
+					// no-op
+				default:
+					return fmt.Errorf("incompatible negative number field with type %v", field.Type)
+				}
+			case v.Type() == sqltypes.Uint64 && !fitsInt64(v):
 				// Number is a uint64 that can't fit in an int64.
-				if hasNegatives[index] {
-					return nil, insertid, fmt.Errorf("incompatible unsigned number field with type %v", fields[index].Type)
+				if hasNegatives {
+					return fmt.Errorf("incompatible unsigned number field with type %v", field.Type)
 				}
-				switch fields[index].Type {
+				switch field.Type {
 				case sqltypes.Null, sqltypes.Int64:
-					fields[index].Type = sqltypes.Uint64
+					field.Type = sqltypes.Uint64
 				case sqltypes.Uint64:
 					// no-op
 				default:
-					return nil, insertid, fmt.Errorf("incompatible number field with type %v", fields[index].Type)
+					return fmt.Errorf("incompatible number field with type %v", field.Type)
 				}
-			} else {
-				// Could be int64 or uint64.
-				switch fields[index].Type {
+			case v.Type() == sqltypes.Int64 || v.Type() == sqltypes.Uint64:
+				// Could be int64 or uint64, including the
+				// auto-increment values.
+				switch field.Type {
 				case sqltypes.Null:
-					fields[index].Type = sqltypes.Int64
+					field.Type = sqltypes.Int64
 				case sqltypes.Int64, sqltypes.Uint64:
 					// no-op
 				default:
-					return nil, insertid, fmt.Errorf("incompatible number field with type %v", fields[index].Type)
+					return fmt.Errorf("incompatible number field with type %v", field.Type)
+				}
+			case v.Type() == sqltypes.VarBinary:
+				switch field.Type {
+				case sqltypes.Null:
+					field.Type = sqltypes.VarBinary
+				case sqltypes.VarBinary:
+					// no-op
+				default:
+					return fmt.Errorf("incompatible string field with type %v", field.Type)
 				}
-			}
-
-			result.Lengths = append(result.Lengths, int64(len(val)))
-			result.Values = append(result.Values, val...)
-		case sqlparser.NULL:
-			switch fields[index].Type {
-			case sqltypes.Null:
-				fields[index].Type = sqltypes.Int64
-			case sqltypes.Int64, sqltypes.Uint64:
-				// no-op
-			default:
-				return nil, insertid, fmt.Errorf("incompatible auto-increment field with type %v", fields[index].Type)
-			}
-
-			v := strconv.AppendInt(nil, insertid, 10)
-			result.Lengths = append(result.Lengths, int64(len(v)))
-			result.Values = append(result.Values, v...)
-			insertid++
-		case sqlparser.STRING:
-			switch fields[index].Type {
-			case sqltypes.Null:
-				fields[index].Type = sqltypes.VarBinary
-			case sqltypes.VarBinary:
-				// no-op
 			default:
-				return nil, insertid, fmt.Errorf("incompatible string field with type %v", fields[index].Type)
+				return fmt.Errorf("unsupported %v value in stream comment", v.Type())
 			}
-
-			decoded := make([]byte, base64.StdEncoding.DecodedLen(len(val)))
-			numDecoded, err := base64.StdEncoding.Decode(decoded, val)
-			if err != nil {
-				return nil, insertid, err
-			}
-			result.Lengths = append(result.Lengths, int64(numDecoded))
-			result.Values = append(result.Values, decoded[:numDecoded]...)
-		default:
-			return nil, insertid, fmt.Errorf("syntax error at position: %d", tokenizer.Position)
 		}
-		index++
 	}
+	return nil
+}
 
-	if index != len(fields) {
-		return nil, insertid, fmt.Errorf("length mismatch in values")
-	}
-	return result, insertid, nil
+// fitsInt64 returns true if the Uint64 value v can be stored in an int64.
+func fitsInt64(v sqltypes.Value) bool {
+	u, err := sqltypes.ToUint64(v)
+	return err == nil && u <= math.MaxInt64
 }
@@ -0,0 +1,147 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binlog
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// HasStreamComment returns true if sql carries the stream comment
+// vttablet appends to every DML replicated with statement based
+// replication.
+func HasStreamComment(sql string) bool {
+	return strings.Contains(sql, streamCommentStart)
+}
+
+// ParseInsertID returns the value of a SET INSERT_ID statement read from
+// the binlogs, and false if sql is not such a statement.
+func ParseInsertID(sql string) (int64, bool) {
+	if !strings.HasPrefix(sql, binlogSetInsertID) {
+		return 0, false
+	}
+	insertid, err := strconv.ParseInt(sql[binlogSetInsertIDLen:], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return insertid, true
+}
+
+// ParseStreamComment extracts the table name, the primary key columns
+// and the primary key values from the stream comment vttablet appends to
+// every DML, like
+// "/* _stream t (eid id name ) (null 1 'bmFtZQ==' ) (null 2 'YQ==' ); */".
+// Negative numbers are returned as Int64 values, the other ones as
+// Uint64 values, and strings, which are base64 encoded, as VarBinary
+// values. A "null" value stands for an auto-increment column, and is
+// replaced by insertid as an Int64 value. insertid is incremented for
+// every such value, and returned.
+func ParseStreamComment(sql string, insertid int64) (table string, pkColumns []string, pks [][]sqltypes.Value, nextid int64, err error) {
+	commentIndex := strings.LastIndex(sql, streamCommentStart)
+	if commentIndex == -1 {
+		return "", nil, nil, insertid, fmt.Errorf("missing stream comment")
+	}
+	tokenizer := sqlparser.NewStringTokenizer(sql[commentIndex+streamCommentStartLen:])
+
+	// first parse the table name
+	typ, val := tokenizer.Scan()
+	if typ != sqlparser.ID {
+		return "", nil, nil, insertid, fmt.Errorf("expecting table name in stream comment")
+	}
+	table = string(val)
+
+	// then parse the PK names, like (eid id name )
+	if typ, _ := tokenizer.Scan(); typ != '(' {
+		return "", nil, nil, insertid, fmt.Errorf("expecting '('")
+	}
+	for typ, val := tokenizer.Scan(); typ != ')'; typ, val = tokenizer.Scan() {
+		if typ != sqlparser.ID {
+			return "", nil, nil, insertid, fmt.Errorf("syntax error at position: %d", tokenizer.Position)
+		}
+		pkColumns = append(pkColumns, string(val))
+	}
+
+	// then parse the PK values, one tuple at a time
+	for typ, _ := tokenizer.Scan(); typ != ';'; typ, _ = tokenizer.Scan() {
+		if typ != '(' {
+			return "", nil, nil, insertid, fmt.Errorf("expecting '('")
+		}
+		var pk []sqltypes.Value
+		for {
+			var v sqltypes.Value
+			var end bool
+			v, end, insertid, err = parsePKValue(tokenizer, insertid)
+			if err != nil {
+				return "", nil, nil, insertid, err
+			}
+			if end {
+				break
+			}
+			if len(pk) == len(pkColumns) {
+				return "", nil, nil, insertid, fmt.Errorf("length mismatch in values")
+			}
+			pk = append(pk, v)
+		}
+		if len(pk) != len(pkColumns) {
+			return "", nil, nil, insertid, fmt.Errorf("length mismatch in values")
+		}
+		pks = append(pks, pk)
+	}
+	return table, pkColumns, pks, insertid, nil
+}
+
+// parsePKValue parses the next value of a tuple of primary key values,
+// and returns true instead if the tuple ended.
+func parsePKValue(tokenizer *sqlparser.Tokenizer, insertid int64) (sqltypes.Value, bool, int64, error) {
+	typ, val := tokenizer.Scan()
+	switch typ {
+	case ')':
+		return sqltypes.NULL, true, insertid, nil
+	case '-':
+		typ, val = tokenizer.Scan()
+		if typ != sqlparser.INTEGRAL {
+			return sqltypes.NULL, false, insertid, fmt.Errorf("expecting number after '-'")
+		}
+		v, err := strconv.ParseInt("-"+string(val), 0, 64)
+		if err != nil {
+			return sqltypes.NULL, false, insertid, err
+		}
+		return sqltypes.NewInt64(v), false, insertid, nil
+	case sqlparser.INTEGRAL:
+		v, err := strconv.ParseUint(string(val), 0, 64)
+		if err != nil {
+			return sqltypes.NULL, false, insertid, err
+		}
+		return sqltypes.NewUint64(v), false, insertid, nil
+	case sqlparser.FLOAT:
+		return sqltypes.MakeTrusted(sqltypes.Float64, val), false, insertid, nil
+	case sqlparser.NULL:
+		return sqltypes.NewInt64(insertid), false, insertid + 1, nil
+	case sqlparser.STRING:
+		decoded, err := base64.StdEncoding.DecodeString(string(val))
+		if err != nil {
+			return sqltypes.NULL, false, insertid, err
+		}
+		return sqltypes.NewVarBinary(string(decoded)), false, insertid, nil
+	}
+	return sqltypes.NULL, false, insertid, fmt.Errorf("syntax error at position: %d", tokenizer.Position)
+}
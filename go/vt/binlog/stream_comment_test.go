@@ -0,0 +1,107 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binlog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+func TestParseStreamComment(t *testing.T) {
+	testcases := []struct {
+		sql       string
+		insertid  int64
+		table     string
+		pkColumns []string
+		pks       [][]sqltypes.Value
+		nextid    int64
+		err       string
+	}{{
+		sql:       "insert into t values (null, 'a') /* _stream t (eid id name ) (null -1 'bmFtZQ==' ) (null 2 'YQ==' ); */",
+		insertid:  10,
+		table:     "t",
+		pkColumns: []string{"eid", "id", "name"},
+		pks: [][]sqltypes.Value{
+			{sqltypes.NewInt64(10), sqltypes.NewInt64(-1), sqltypes.NewVarBinary("name")},
+			{sqltypes.NewInt64(11), sqltypes.NewUint64(2), sqltypes.NewVarBinary("a")},
+		},
+		nextid: 12,
+	}, {
+		sql:       "update t set a=1 where id=1.5 /* _stream t (id ) (1.5 ); */",
+		table:     "t",
+		pkColumns: []string{"id"},
+		pks:       [][]sqltypes.Value{{sqltypes.MakeTrusted(sqltypes.Float64, []byte("1.5"))}},
+	}, {
+		sql: "delete from t where id=1",
+		err: "missing stream comment",
+	}, {
+		sql: "delete from t where id=1 /* _stream 1 (id ) (1 ); */",
+		err: "expecting table name in stream comment",
+	}, {
+		sql: "delete from t where id=1 /* _stream t id (1 ); */",
+		err: "expecting '('",
+	}, {
+		sql: "delete from t where id=1 /* _stream t (id ) (1 2 ); */",
+		err: "length mismatch in values",
+	}, {
+		sql: "delete from t where id=1 /* _stream t (id name ) (1 ); */",
+		err: "length mismatch in values",
+	}, {
+		sql: "delete from t where id=1 /* _stream t (id ) (- 'a' ); */",
+		err: "expecting number after '-'",
+	}, {
+		sql: "delete from t where id=1 /* _stream t (id ) (1 */",
+		err: "syntax error at position",
+	}}
+	for _, tcase := range testcases {
+		table, pkColumns, pks, nextid, err := ParseStreamComment(tcase.sql, tcase.insertid)
+		if tcase.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tcase.err) {
+				t.Errorf("ParseStreamComment(%q): %v, want %v", tcase.sql, err, tcase.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseStreamComment(%q): %v", tcase.sql, err)
+			continue
+		}
+		if table != tcase.table || !reflect.DeepEqual(pkColumns, tcase.pkColumns) || !reflect.DeepEqual(pks, tcase.pks) || nextid != tcase.nextid {
+			t.Errorf("ParseStreamComment(%q): %v %v %v %v, want %v %v %v %v", tcase.sql, table, pkColumns, pks, nextid, tcase.table, tcase.pkColumns, tcase.pks, tcase.nextid)
+		}
+	}
+}
+
+func TestParseInsertID(t *testing.T) {
+	if id, ok := ParseInsertID("SET INSERT_ID=42"); !ok || id != 42 {
+		t.Errorf("ParseInsertID: %v %v, want 42 true", id, ok)
+	}
+	if _, ok := ParseInsertID("SET TIMESTAMP=42"); ok {
+		t.Errorf("ParseInsertID(SET TIMESTAMP) should fail")
+	}
+}
+
+func TestHasStreamComment(t *testing.T) {
+	if !HasStreamComment("delete from t where id=1 /* _stream t (id ) (1 ); */") {
+		t.Errorf("HasStreamComment should find the stream comment")
+	}
+	if HasStreamComment("delete from t where id=1") {
+		t.Errorf("HasStreamComment should not find a stream comment")
+	}
+}
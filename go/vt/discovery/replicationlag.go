@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"sort"
 	"time"
+
+	"vitess.io/vitess/go/vt/topo/topoproto"
 )
 
 var (
@@ -43,7 +45,7 @@ func IsReplicationLagVeryHigh(tabletStats *TabletStats) bool {
 }
 
 // FilterByReplicationLag filters the list of TabletStats by TabletStats.Stats.SecondsBehindMaster.
-// The algorithm (TabletStats that is non-serving or has error is ignored):
+// The algorithm (TabletStats that is non-serving, has error or is a delayed replica is ignored):
 // - Return the list if there is 0 or 1 tablet.
 // - Return the list if all tablets have <=30s lag.
 // - Filter by replication lag: for each tablet, if the mean value without it is more than 0.7 of the mean value across all tablets, it is valid.
@@ -58,10 +60,18 @@ func IsReplicationLagVeryHigh(tabletStats *TabletStats) bool {
 // * degraded_threshold: this is only used by vttablet for display. It should match
 //   discovery_low_replication_lag here, so the vttablet status display matches what vtgate will do of it.
 func FilterByReplicationLag(tabletStatsList []*TabletStats) []*TabletStats {
-	res := filterByLag(tabletStatsList)
+	// delayed replicas are not meant to serve queries, and must not
+	// count as a removed tablet below.
+	candidates := make([]*TabletStats, 0, len(tabletStatsList))
+	for _, ts := range tabletStatsList {
+		if !topoproto.IsDelayedReplica(ts.Tablet) {
+			candidates = append(candidates, ts)
+		}
+	}
+	res := filterByLag(candidates)
 	// run the filter again if exactly one tablet is removed,
 	// and we have spare tablets.
-	if len(res) > *minNumTablets && len(res) == len(tabletStatsList)-1 {
+	if len(res) > *minNumTablets && len(res) == len(candidates)-1 {
 		res = filterByLag(res)
 	}
 	return res
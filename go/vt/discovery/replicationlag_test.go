@@ -22,6 +22,7 @@ import (
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 )
 
 // testSetMinNumTablets is a test helper function, if this is used by a production code path, something is wrong.
@@ -50,6 +51,42 @@ func TestFilterByReplicationLagUnhealthy(t *testing.T) {
 	}
 }
 
+func TestFilterByReplicationLagDelayedReplica(t *testing.T) {
+	ts1 := &TabletStats{
+		Tablet:  topo.NewTablet(1, "cell", "host1"),
+		Serving: true,
+		Stats:   &querypb.RealtimeStats{},
+	}
+	ts2 := &TabletStats{
+		Tablet:  topo.NewTablet(2, "cell", "host2"),
+		Serving: true,
+		Stats:   &querypb.RealtimeStats{},
+	}
+	ts2.Tablet.Tags = map[string]string{topoproto.DelayedReplicaTag: "1h0m0s"}
+	got := FilterByReplicationLag([]*TabletStats{ts1, ts2})
+	if len(got) != 1 || !got[0].DeepEqual(ts1) {
+		t.Errorf("FilterByReplicationLag with a delayed replica = %+v, want only %+v", got, ts1)
+	}
+}
+
+func TestFilterByReplicationLagDelayedReplicaNotCounted(t *testing.T) {
+	// lags of (1s, 1s, 10m, 40m) run the filter twice, a delayed
+	// replica must not prevent the second run.
+	var tsl []*TabletStats
+	for i, lag := range []uint32{1, 1, 10 * 60, 40 * 60, 0} {
+		tsl = append(tsl, &TabletStats{
+			Tablet:  topo.NewTablet(uint32(i+1), "cell", fmt.Sprintf("host%v", i+1)),
+			Serving: true,
+			Stats:   &querypb.RealtimeStats{SecondsBehindMaster: lag},
+		})
+	}
+	tsl[4].Tablet.Tags = map[string]string{topoproto.DelayedReplicaTag: "1h0m0s"}
+	got := FilterByReplicationLag(tsl)
+	if len(got) != 2 || !got[0].DeepEqual(tsl[0]) || !got[1].DeepEqual(tsl[1]) {
+		t.Errorf("FilterByReplicationLag with lags of (1s, 1s, 10m, 40m) and a delayed replica = %+v, want the first two", got)
+	}
+}
+
 func TestFilterByReplicationLag(t *testing.T) {
 	cases := []struct {
 		description string
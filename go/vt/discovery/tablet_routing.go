@@ -0,0 +1,203 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// RoutingTier describes how far a tablet is from the cell of a
+// TabletStatsCache.
+type RoutingTier int
+
+const (
+	// RoutingTierCell is for tablets in the local cell.
+	RoutingTierCell RoutingTier = iota
+	// RoutingTierRegion is for tablets in another cell of the same region.
+	RoutingTierRegion
+	// RoutingTierRemote is for tablets in another region.
+	RoutingTierRemote
+)
+
+var routingTierNames = map[RoutingTier]string{
+	RoutingTierCell:   "cell",
+	RoutingTierRegion: "region",
+	RoutingTierRemote: "remote",
+}
+
+// String returns the name of the tier, as used in routing policies.
+func (t RoutingTier) String() string {
+	if name, ok := routingTierNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("RoutingTier(%d)", int(t))
+}
+
+// ParseRoutingTier parses the name of a tier.
+func ParseRoutingTier(name string) (RoutingTier, error) {
+	for t, n := range routingTierNames {
+		if n == strings.ToLower(name) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown routing tier %v", name)
+}
+
+// RoutingPolicy is the list of tiers queries for a tablet type can be
+// routed to, in order of preference. Tablets of tiers not in the
+// policy are not kept by the TabletStatsCache.
+type RoutingPolicy []RoutingTier
+
+// DefaultRoutingPolicy is used for the tablet types without a policy:
+// local cell first, then the other cells of the region.
+var DefaultRoutingPolicy = RoutingPolicy{RoutingTierCell, RoutingTierRegion}
+
+// Contains returns true if the policy allows routing to the tier.
+func (p RoutingPolicy) Contains(tier RoutingTier) bool {
+	for _, t := range p {
+		if t == tier {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the policy in the format of ParseRoutingPolicies.
+func (p RoutingPolicy) String() string {
+	names := make([]string, len(p))
+	for i, t := range p {
+		names[i] = t.String()
+	}
+	return strings.Join(names, ",")
+}
+
+// ParseRoutingPolicies parses a list of per tablet type routing
+// policies, like "replica:cell,region,remote;rdonly:cell".
+func ParseRoutingPolicies(value string) (map[topodatapb.TabletType]RoutingPolicy, error) {
+	result := make(map[topodatapb.TabletType]RoutingPolicy)
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid routing policy %v, expected <tablet type>:<tier>,<tier>...", entry)
+		}
+		tabletType, err := topoproto.ParseTabletType(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, err
+		}
+		if tabletType == topodatapb.TabletType_MASTER {
+			return nil, fmt.Errorf("invalid routing policy %v: the master is always used, wherever it is", entry)
+		}
+		if _, ok := result[tabletType]; ok {
+			return nil, fmt.Errorf("duplicate routing policy for tablet type %v", topoproto.TabletTypeLString(tabletType))
+		}
+		var policy RoutingPolicy
+		for _, name := range strings.Split(parts[1], ",") {
+			tier, err := ParseRoutingTier(strings.TrimSpace(name))
+			if err != nil {
+				return nil, err
+			}
+			if policy.Contains(tier) {
+				return nil, fmt.Errorf("invalid routing policy %v: tier %v is listed twice", entry, tier)
+			}
+			policy = append(policy, tier)
+		}
+		result[tabletType] = policy
+	}
+	return result, nil
+}
+
+// RoutedTabletStats is a healthy tablet, with its routing tier.
+type RoutedTabletStats struct {
+	TabletStats
+	Tier RoutingTier
+}
+
+// SetRoutingPolicies sets the routing policy of each tablet type.
+// Tablet types without a policy use DefaultRoutingPolicy. It needs to be
+// called before the cache receives any update, as tablets of tiers a
+// policy does not use are dropped when they are received.
+func (tc *TabletStatsCache) SetRoutingPolicies(policies map[topodatapb.TabletType]RoutingPolicy) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.policies = policies
+}
+
+// RoutingPolicy returns the routing policy of a tablet type.
+func (tc *TabletStatsCache) RoutingPolicy(tabletType topodatapb.TabletType) RoutingPolicy {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	if policy, ok := tc.policies[tabletType]; ok {
+		return policy
+	}
+	return DefaultRoutingPolicy
+}
+
+// RoutingTier returns the tier of the tablets of a cell.
+func (tc *TabletStatsCache) RoutingTier(cell string) RoutingTier {
+	switch {
+	case cell == tc.cell:
+		return RoutingTierCell
+	case tc.getRegionByCell(cell) == tc.getRegionByCell(tc.cell):
+		return RoutingTierRegion
+	default:
+		return RoutingTierRemote
+	}
+}
+
+// GetRoutedTabletStats returns the healthy targets in the order they
+// should be used: grouped by tier, in the order of the routing policy
+// of the tablet type, and shuffled within a tier.
+// For TabletType_MASTER, this will only return at most one entry.
+// The returned array is owned by the caller.
+func (tc *TabletStatsCache) GetRoutedTabletStats(keyspace, shard string, tabletType topodatapb.TabletType) []RoutedTabletStats {
+	tablets := tc.GetHealthyTabletStats(keyspace, shard, tabletType)
+	if len(tablets) == 0 {
+		return nil
+	}
+
+	byTier := make(map[RoutingTier][]TabletStats)
+	for _, ts := range tablets {
+		tier := tc.RoutingTier(ts.Tablet.Alias.Cell)
+		byTier[tier] = append(byTier[tier], ts)
+	}
+	policy := tc.RoutingPolicy(tabletType)
+	if tabletType == topodatapb.TabletType_MASTER {
+		policy = RoutingPolicy{RoutingTierCell, RoutingTierRegion, RoutingTierRemote}
+	}
+
+	result := make([]RoutedTabletStats, 0, len(tablets))
+	for _, tier := range policy {
+		list := byTier[tier]
+		for _, i := range rand.Perm(len(list)) {
+			result = append(result, RoutedTabletStats{
+				TabletStats: list[i],
+				Tier:        tier,
+			})
+		}
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"reflect"
+	"testing"
+
+	"vitess.io/vitess/go/vt/topo"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestParseRoutingPolicies(t *testing.T) {
+	policies, err := ParseRoutingPolicies("replica:cell,region,remote; rdonly:cell")
+	if err != nil {
+		t.Fatalf("ParseRoutingPolicies failed: %v", err)
+	}
+	want := map[topodatapb.TabletType]RoutingPolicy{
+		topodatapb.TabletType_REPLICA: {RoutingTierCell, RoutingTierRegion, RoutingTierRemote},
+		topodatapb.TabletType_RDONLY:  {RoutingTierCell},
+	}
+	if !reflect.DeepEqual(policies, want) {
+		t.Errorf("ParseRoutingPolicies: got %v, want %v", policies, want)
+	}
+	if got, want := policies[topodatapb.TabletType_REPLICA].String(), "cell,region,remote"; got != want {
+		t.Errorf("String(): got %v, want %v", got, want)
+	}
+
+	for _, value := range []string{
+		"replica",
+		"replica:cell;replica:region",
+		"replica:cell,cell",
+		"replica:nowhere",
+		"nosuchtype:cell",
+		"master:cell",
+	} {
+		if _, err := ParseRoutingPolicies(value); err == nil {
+			t.Errorf("ParseRoutingPolicies(%v) worked, expected an error", value)
+		}
+	}
+}
+
+func TestGetRoutedTabletStats(t *testing.T) {
+	defer topo.UpdateCellsToRegionsForTests(map[string]string{})
+	topo.UpdateCellsToRegionsForTests(map[string]string{
+		"cell1": "region1",
+		"cell2": "region1",
+		"cell3": "region2",
+	})
+
+	tsc := &TabletStatsCache{
+		cell:    "cell1",
+		entries: make(map[string]map[string]map[topodatapb.TabletType]*tabletStatsCacheEntry),
+	}
+	newTabletStats := func(key, cell string, uid uint32) *TabletStats {
+		return &TabletStats{
+			Key:     key,
+			Tablet:  topo.NewTablet(uid, cell, key),
+			Target:  &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA},
+			Up:      true,
+			Serving: true,
+			Stats:   &querypb.RealtimeStats{SecondsBehindMaster: 1, CpuUsage: 0.2},
+		}
+	}
+	add := func() {
+		tsc.ResetForTesting()
+		tsc.StatsUpdate(newTabletStats("t1", "cell1", 1))
+		tsc.StatsUpdate(newTabletStats("t2", "cell1", 2))
+		tsc.StatsUpdate(newTabletStats("t3", "cell2", 3))
+		tsc.StatsUpdate(newTabletStats("t4", "cell2", 4))
+		tsc.StatsUpdate(newTabletStats("t5", "cell3", 5))
+	}
+	check := func(want map[string]RoutingTier, order ...[]string) {
+		t.Helper()
+		// repeat 10 times, as tablets are shuffled within a tier
+		for i := 0; i < 10; i++ {
+			result := tsc.GetRoutedTabletStats("k", "s", topodatapb.TabletType_REPLICA)
+			if len(result) != len(want) {
+				t.Fatalf("got %v tablets, want %v: %+v", len(result), len(want), result)
+			}
+			offset := 0
+			for _, keys := range order {
+				for _, r := range result[offset : offset+len(keys)] {
+					found := false
+					for _, key := range keys {
+						found = found || r.Key == key
+					}
+					if !found || r.Tier != want[r.Key] {
+						t.Errorf("unexpected tablet %v of tier %v at position %v, want one of %v: %+v", r.Key, r.Tier, offset, keys, result)
+					}
+				}
+				offset += len(keys)
+			}
+		}
+	}
+
+	// default policy: remote tablets are dropped, same cell tablets first
+	add()
+	check(map[string]RoutingTier{
+		"t1": RoutingTierCell,
+		"t2": RoutingTierCell,
+		"t3": RoutingTierRegion,
+		"t4": RoutingTierRegion,
+	}, []string{"t1", "t2"}, []string{"t3", "t4"})
+
+	// remote tablets last
+	tsc.SetRoutingPolicies(map[topodatapb.TabletType]RoutingPolicy{
+		topodatapb.TabletType_REPLICA: {RoutingTierCell, RoutingTierRegion, RoutingTierRemote},
+	})
+	add()
+	check(map[string]RoutingTier{
+		"t1": RoutingTierCell,
+		"t2": RoutingTierCell,
+		"t3": RoutingTierRegion,
+		"t4": RoutingTierRegion,
+		"t5": RoutingTierRemote,
+	}, []string{"t1", "t2"}, []string{"t3", "t4"}, []string{"t5"})
+
+	// the order of the policy is used
+	tsc.SetRoutingPolicies(map[topodatapb.TabletType]RoutingPolicy{
+		topodatapb.TabletType_REPLICA: {RoutingTierRemote, RoutingTierCell},
+	})
+	add()
+	check(map[string]RoutingTier{
+		"t1": RoutingTierCell,
+		"t2": RoutingTierCell,
+		"t5": RoutingTierRemote,
+	}, []string{"t5"}, []string{"t1", "t2"})
+}
@@ -33,8 +33,10 @@ import (
 // current list of available TabletStats, and a serving list:
 // - for master tablets, only the current master is kept.
 // - for non-master tablets, we filter the list using FilterByReplicationLag.
-// It keeps entries for all tablets in the cell(s) it's configured to serve for,
-// and for the master independently of which cell it's in.
+// It keeps entries for all tablets in the tiers the routing policy of
+// their type allows (by default the cell it's configured to serve for
+// and the other cells of its region), and for the master independently
+// of which cell it's in.
 // Note the healthy tablet computation is done when we receive a tablet
 // update only, not at serving time.
 // Also note the cache may not have the last entry received by the tablet.
@@ -53,6 +55,8 @@ type TabletStatsCache struct {
 	mu sync.RWMutex
 	// entries maps from keyspace/shard/tabletType to our cache.
 	entries map[string]map[string]map[topodatapb.TabletType]*tabletStatsCacheEntry
+	// policies has the routing policy of each tablet type.
+	policies map[topodatapb.TabletType]RoutingPolicy
 	// tsm is a helper to broadcast aggregate stats.
 	tsm srvtopo.TargetStatsMultiplexer
 }
@@ -199,8 +203,8 @@ func (tc *TabletStatsCache) getRegionByCell(cell string) string {
 
 // StatsUpdate is part of the HealthCheckStatsListener interface.
 func (tc *TabletStatsCache) StatsUpdate(ts *TabletStats) {
-	if ts.Target.TabletType != topodatapb.TabletType_MASTER && ts.Tablet.Alias.Cell != tc.cell && tc.getRegionByCell(ts.Tablet.Alias.Cell) != tc.getRegionByCell(tc.cell) {
-		// this is for a non-master tablet in a different cell and a different region, drop it
+	if ts.Target.TabletType != topodatapb.TabletType_MASTER && !tc.RoutingPolicy(ts.Target.TabletType).Contains(tc.RoutingTier(ts.Tablet.Alias.Cell)) {
+		// this is for a non-master tablet in a tier we never route to, drop it
 		return
 	}
 
@@ -93,10 +93,16 @@ func Dial(target string, failFast FailFast, opts ...grpc.DialOption) (*grpc.Clie
 		}
 	}
 
+	// gRPC accepts a single interceptor of each type, so they are
+	// chained.
+	unaryInterceptors := []grpc.UnaryClientInterceptor{traceUnaryInterceptor}
+	streamInterceptors := []grpc.StreamClientInterceptor{traceStreamInterceptor}
 	if *grpccommon.EnableGRPCPrometheus {
-		newopts = append(newopts, grpc.WithUnaryInterceptor(grpc_prometheus.UnaryClientInterceptor))
-		newopts = append(newopts, grpc.WithStreamInterceptor(grpc_prometheus.StreamClientInterceptor))
+		unaryInterceptors = append(unaryInterceptors, grpc_prometheus.UnaryClientInterceptor)
+		streamInterceptors = append(streamInterceptors, grpc_prometheus.StreamClientInterceptor)
 	}
+	newopts = append(newopts, grpc.WithUnaryInterceptor(chainUnaryInterceptors(unaryInterceptors)))
+	newopts = append(newopts, grpc.WithStreamInterceptor(chainStreamInterceptors(streamInterceptors)))
 
 	return grpc.Dial(target, newopts...)
 }
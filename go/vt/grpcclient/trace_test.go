@@ -0,0 +1,55 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcclient
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+
+	"vitess.io/vitess/go/trace"
+)
+
+type discardExporter struct{}
+
+func (discardExporter) ExportSpan(*trace.SpanData) {}
+func (discardExporter) Close() error               { return nil }
+
+func TestTraceContext(t *testing.T) {
+	// Without a tracer, nothing is added to the metadata.
+	ctx := traceContext(context.Background())
+	if _, ok := metadata.FromOutgoingContext(ctx); ok {
+		t.Errorf("unexpected outgoing metadata without a tracer")
+	}
+
+	trace.RegisterSpanFactory(trace.NewTracer("test", discardExporter{}, 1))
+	defer trace.RegisterSpanFactory(nil)
+
+	ctx = metadata.NewOutgoingContext(context.Background(), metadata.Pairs("key", "value"))
+	ctx = trace.WithSpanContext(ctx, "1:2:0:1")
+	md, ok := metadata.FromOutgoingContext(traceContext(ctx))
+	if !ok {
+		t.Fatalf("no outgoing metadata")
+	}
+	if got := md[trace.SpanContextHeader]; len(got) != 1 || got[0] != "1:2:0:1" {
+		t.Errorf("span context: got %v, want [1:2:0:1]", got)
+	}
+	if got := md["key"]; len(got) != 1 || got[0] != "value" {
+		t.Errorf("existing metadata: got %v, want [value]", got)
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -41,18 +42,24 @@ import (
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/encryptedbackupstorage"
 )
 
 // This file handles the backup and restore related code
 
 const (
-	// the three bases for files to restore
+	// the bases for files to restore
 	backupInnodbDataHomeDir     = "InnoDBData"
 	backupInnodbLogGroupHomeDir = "InnoDBLog"
 	backupData                  = "Data"
+	backupBinlog                = "Binlog"
 
 	// the manifest file name
 	backupManifest = "MANIFEST"
+
+	// the file listing what a builtin backup in progress uploaded
+	// already, to resume it
+	backupProgressFile = "PROGRESS"
 )
 
 const (
@@ -78,6 +85,30 @@ var (
 	// on the backups. Usually would be set if a hook is used, and
 	// the hook compresses the data.
 	backupStorageCompress = flag.Bool("backup_storage_compress", true, "if set, the backup files will be compressed (default is true). Set to false for instance if a backup_storage_hook is specified and it compresses the data.")
+
+	// backupChunkSize is the size of the chunks large files are
+	// split into. Each chunk is uploaded and retried separately.
+	backupChunkSize = flag.Int64("backup_chunk_size", 0, "if set, files larger than this many bytes are backed up in chunks of this size, so a failed upload only retries one chunk")
+
+	// backupUploadRetries is how many times a failed upload of a
+	// file or chunk is retried before the backup is aborted.
+	backupUploadRetries = flag.Int("backup_upload_retries", 2, "how many times a failed upload of a backup file (or chunk, see -backup_chunk_size) is retried before the backup is aborted")
+
+	// backupMaxBandwidth limits the upload rate of all the backups
+	// of the process.
+	backupMaxBandwidth = flag.Int64("backup_max_bandwidth", 0, "if set, the maximum rate in bytes per second at which backup data is sent to the backup storage, for all the files uploaded concurrently")
+
+	// backupResume keeps failed builtin backups, with the list of
+	// what they uploaded, so the next backup resumes them.
+	backupResume = flag.Bool("backup_resume", false, "if set, a failed builtin backup is kept with the list of the files and chunks (see -backup_chunk_size) it uploaded, and the next backup of the tablet resumes it: the files and chunks whose data did not change are not uploaded again")
+
+	// backupChecksumTables records the row count and checksum of
+	// the tables in the MANIFEST of builtin backups.
+	backupChecksumTables = flag.Bool("backup_checksum_tables", true, "if set, a builtin backup records the row count and checksum of all the tables before shutting down mysqld, and VerifyBackup fails if the restored tables don't match them. Reading all the tables makes mysqld unavailable longer during the backup")
+
+	// backupProgressInterval is how often the progress of a backup
+	// is recorded, if -backup_resume is set.
+	backupProgressInterval = 10 * time.Second
 )
 
 // FileEntry is one file to backup
@@ -86,6 +117,7 @@ type FileEntry struct {
 	// - backupInnodbDataHomeDir for files that go into Mycnf.InnodbDataHomeDir
 	// - backupInnodbLogGroupHomeDir for files that go into Mycnf.InnodbLogGroupHomeDir
 	// - backupData for files that go into Mycnf.DataDir
+	// - backupBinlog for files that go into the directory of Mycnf.BinLogPath
 	Base string
 
 	// Name is the file name, relative to Base
@@ -94,6 +126,32 @@ type FileEntry struct {
 	// Hash is the hash of the final data (transformed and
 	// compressed if specified) stored in the BackupStorage.
 	Hash string
+
+	// RawHash is the hash of the original file, checked after
+	// the file is restored. Old backups don't have it.
+	RawHash string
+
+	// ChunkSize is set if the file was backed up in chunks of
+	// this size. The chunks are stored as separate files, and
+	// Hash and RawHash are in Chunks instead.
+	ChunkSize int64
+
+	// Chunks has the hashes of the chunks of the file, in order.
+	Chunks []ChunkEntry
+}
+
+// ChunkEntry is one chunk of a file backed up in chunks.
+type ChunkEntry struct {
+	// Hash is the hash of the data stored in the BackupStorage.
+	Hash string
+
+	// RawHash is the hash of this part of the original file.
+	RawHash string
+}
+
+// chunkName returns the name of a chunk of the file name in the backup.
+func chunkName(name string, chunk int) string {
+	return fmt.Sprintf("%v-%v", name, chunk)
 }
 
 func (fe *FileEntry) open(cnf *Mycnf, readOnly bool) (*os.File, error) {
@@ -106,6 +164,8 @@ func (fe *FileEntry) open(cnf *Mycnf, readOnly bool) (*os.File, error) {
 		root = cnf.InnodbLogGroupHomeDir
 	case backupData:
 		root = cnf.DataDir
+	case backupBinlog:
+		root = path.Dir(cnf.BinLogPath)
 	default:
 		return nil, fmt.Errorf("unknown base: %v", fe.Base)
 	}
@@ -147,6 +207,41 @@ type BackupManifest struct {
 	// backups that don't have this flag are assumed to be
 	// compressed.
 	SkipCompress bool
+
+	// BackupTime is when the backup was taken. It is used to
+	// find the full backup to start from when restoring to a
+	// point in time. Old backups don't have it.
+	BackupTime time.Time
+
+	// Incremental is set for backups that contain archived
+	// binlog files instead of a copy of the data files. All
+	// their FileEntries use the backupBinlog base, and they
+	// contain the transactions between FromPosition and Position.
+	Incremental bool
+
+	// FromPosition is the position the first binlog file of an
+	// incremental backup starts at.
+	FromPosition mysql.Position
+
+	// BackupMethod is the name of the BackupEngine that took the
+	// backup. Old backups don't have it, and are builtin backups.
+	BackupMethod string
+
+	// Tables has the row count and checksum of the tables of
+	// each database when the backup was taken, for VerifyBackup
+	// to compare the restored tables with. Old backups, and
+	// backups taken without -backup_checksum_tables or while
+	// mysqld was serving writes (xtrabackup), don't have it.
+	Tables map[string][]TableVerification
+
+	// Verification is the result of the last VerifyBackup on
+	// this backup, if any.
+	Verification *BackupVerification
+
+	// Encryption describes the data key the other files of the
+	// backup are encrypted with. It is nil for backups that are
+	// not encrypted.
+	Encryption *encryptedbackupstorage.KeyInfo
 }
 
 // isDbDir returns true if the given directory contains a DB
@@ -220,26 +315,45 @@ func findFilesToBackup(cnf *Mycnf) ([]FileEntry, error) {
 
 // Backup is the main entry point for a backup:
 // - uses the BackupStorage service to store a new backup
-// - shuts down Mysqld during the backup
-// - remember if we were replicating, restore the exact same state
+// - uses the BackupEngine to take the backup
 func Backup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, dir, name string, backupConcurrency int, hookExtraEnv map[string]string) error {
+	be, err := GetBackupEngine()
+	if err != nil {
+		return err
+	}
+
 	// Start the backup with the BackupStorage.
 	bs, err := backupstorage.GetBackupStorage()
 	if err != nil {
 		return err
 	}
 	defer bs.Close()
-	bh, err := bs.StartBackup(ctx, dir, name)
-	if err != nil {
-		return fmt.Errorf("StartBackup failed: %v", err)
+	_, resumable := be.(*BuiltinBackupEngine)
+	resumable = resumable && *backupResume
+	var bh backupstorage.BackupHandle
+	if resumable {
+		bh, err = resumeBackup(ctx, bs, logger, dir, name)
+		if err != nil {
+			return err
+		}
+	}
+	if bh == nil {
+		bh, err = startBackup(ctx, bs, dir, name)
+		if err != nil {
+			return err
+		}
 	}
 
-	// Take the backup, and either AbortBackup or EndBackup.
-	usable, err := backup(ctx, mysqld, logger, bh, backupConcurrency, hookExtraEnv)
+	// Take the backup, and either AbortBackup or EndBackup. A
+	// resumable backup is kept as is, for the next one to resume it.
+	usable, err := be.ExecuteBackup(ctx, mysqld, logger, bh, backupConcurrency, hookExtraEnv)
 	var finishErr error
-	if usable {
+	switch {
+	case usable:
 		finishErr = bh.EndBackup(ctx)
-	} else {
+	case resumable:
+		logger.Errorf("backup is not usable, keeping it for the next backup to resume it: %v", err)
+	default:
 		logger.Errorf("backup is not usable, aborting it: %v", err)
 		finishErr = bh.AbortBackup(ctx)
 	}
@@ -257,7 +371,10 @@ func Backup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, dir,
 	return finishErr
 }
 
-// backup returns a boolean that indicates if the backup is usable,
+// backup is the builtin backup:
+// - shuts down Mysqld during the backup
+// - remember if we were replicating, restore the exact same state
+// It returns a boolean that indicates if the backup is usable,
 // and an overall error.
 func backup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh backupstorage.BackupHandle, backupConcurrency int, hookExtraEnv map[string]string) (bool, error) {
 	// Save initial state so we can restore.
@@ -311,6 +428,16 @@ func backup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh b
 	}
 	logger.Infof("using replication position: %v", replicationPosition)
 
+	// record the tables while the data doesn't change
+	var tables map[string][]TableVerification
+	if *backupChecksumTables {
+		logger.Infof("recording the row count and checksum of the tables")
+		tables, err = checksumDatabases(ctx, mysqld)
+		if err != nil {
+			return false, fmt.Errorf("can't checksum tables: %v", err)
+		}
+	}
+
 	// shutdown mysqld
 	err = mysqld.Shutdown(ctx, true)
 	if err != nil {
@@ -318,7 +445,7 @@ func backup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh b
 	}
 
 	// Backup everything, capture the error.
-	backupErr := backupFiles(ctx, mysqld, logger, bh, replicationPosition, backupConcurrency, hookExtraEnv)
+	backupErr := backupFiles(ctx, mysqld, logger, bh, replicationPosition, tables, backupConcurrency, hookExtraEnv)
 	usable := backupErr == nil
 
 	// Try to restart mysqld
@@ -365,7 +492,15 @@ func backup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh b
 }
 
 // backupFiles finds the list of files to backup, and creates the backup.
-func backupFiles(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh backupstorage.BackupHandle, replicationPosition mysql.Position, backupConcurrency int, hookExtraEnv map[string]string) (err error) {
+// tables is recorded in the MANIFEST.
+func backupFiles(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh backupstorage.BackupHandle, replicationPosition mysql.Position, tables map[string][]TableVerification, backupConcurrency int, hookExtraEnv map[string]string) (err error) {
+	// A resumed backup has the list of what it uploaded already.
+	var prev *BackupManifest
+	if rbh, ok := bh.(*resumedBackupHandle); ok {
+		prev = rbh.progress
+		bh = rbh.BackupHandle
+	}
+
 	// Get the files to backup.
 	fes, err := findFilesToBackup(mysqld.Cnf())
 	if err != nil {
@@ -373,6 +508,17 @@ func backupFiles(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger,
 	}
 	logger.Infof("found %v files to backup", len(fes))
 
+	// Record what is uploaded, to resume the backup if it fails.
+	var progress *backupProgress
+	if *backupResume {
+		progress = newBackupProgress(bh, fes, prev)
+		defer func() {
+			if err != nil {
+				progress.flush(context.Background(), logger)
+			}
+		}()
+	}
+
 	// Backup with the provided concurrency.
 	sema := sync2.NewSemaphore(backupConcurrency, 0)
 	rec := concurrency.AllErrorRecorder{}
@@ -392,7 +538,13 @@ func backupFiles(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger,
 
 			// Backup the individual file.
 			name := fmt.Sprintf("%v", i)
-			rec.RecordError(backupFile(ctx, mysqld, logger, bh, &fes[i], name, hookExtraEnv))
+			var record func(force bool) error
+			if progress != nil {
+				record = func(force bool) error {
+					return progress.record(ctx, logger, i, &fes[i], force)
+				}
+			}
+			rec.RecordError(backupFile(ctx, mysqld, logger, bh, &fes[i], resumableEntry(prev, i, &fes[i]), name, hookExtraEnv, record))
 		}(i)
 	}
 
@@ -401,10 +553,72 @@ func backupFiles(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger,
 		return rec.Error()
 	}
 
-	// open the MANIFEST
-	wc, err := bh.AddFile(ctx, backupManifest, 0)
+	// and write the MANIFEST
+	return writeManifest(ctx, bh, &BackupManifest{
+		FileEntries:   fes,
+		Position:      replicationPosition,
+		TransformHook: *backupStorageHook,
+		SkipCompress:  !*backupStorageCompress,
+		BackupTime:    time.Now().UTC(),
+		BackupMethod:  builtin,
+		Tables:        tables,
+	})
+}
+
+// startBackup starts a backup with the BackupStorage. If backup
+// encryption is enabled, the files added to the returned BackupHandle
+// are encrypted.
+func startBackup(ctx context.Context, bs backupstorage.BackupStorage, dir, name string) (backupstorage.BackupHandle, error) {
+	bh, err := bs.StartBackup(ctx, dir, name)
+	if err != nil {
+		return nil, fmt.Errorf("StartBackup failed: %v", err)
+	}
+	return encryptNewBackup(ctx, bh)
+}
+
+// encryptNewBackup returns bh, a backup that was just started, or a
+// BackupHandle encrypting the files added to it if backup encryption
+// is enabled. bh is aborted if it can't be encrypted.
+func encryptNewBackup(ctx context.Context, bh backupstorage.BackupHandle) (backupstorage.BackupHandle, error) {
+	if !encryptedbackupstorage.Enabled() {
+		return bh, nil
+	}
+	ebh, err := encryptedbackupstorage.NewWriteHandle(ctx, bh)
+	if err != nil {
+		if abortErr := bh.AbortBackup(ctx); abortErr != nil {
+			log.Errorf("failed to abort backup %v: %v", bh.Name(), abortErr)
+		}
+		return nil, err
+	}
+	return ebh, nil
+}
+
+// openBackup returns a BackupHandle to read the files of a backup
+// returned by ListBackups, decrypting them if needed.
+func openBackup(ctx context.Context, bh backupstorage.BackupHandle, bm *BackupManifest) (backupstorage.BackupHandle, error) {
+	if bm.Encryption == nil {
+		return bh, nil
+	}
+	return encryptedbackupstorage.NewReadHandle(ctx, bh, bm.Encryption)
+}
+
+// writeManifest adds the JSON-encoded MANIFEST to a backup.
+// The MANIFEST of an encrypted backup is not encrypted, since it
+// describes the key of the other files.
+func writeManifest(ctx context.Context, bh backupstorage.BackupHandle, bm *BackupManifest) error {
+	return writeManifestFile(ctx, bh, backupManifest, bm)
+}
+
+// writeManifestFile adds bm to a backup as the JSON-encoded file
+// called filename, like the MANIFEST.
+func writeManifestFile(ctx context.Context, bh backupstorage.BackupHandle, filename string, bm *BackupManifest) (err error) {
+	if ebh, ok := bh.(*encryptedbackupstorage.EncryptedBackupHandle); ok {
+		bm.Encryption = ebh.KeyInfo()
+		bh = ebh.Unwrap()
+	}
+	wc, err := bh.AddFile(ctx, filename, 0)
 	if err != nil {
-		return fmt.Errorf("cannot add %v to backup: %v", backupManifest, err)
+		return fmt.Errorf("cannot add %v to backup: %v", filename, err)
 	}
 	defer func() {
 		if closeErr := wc.Close(); err == nil {
@@ -412,26 +626,43 @@ func backupFiles(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger,
 		}
 	}()
 
-	// JSON-encode and write the MANIFEST
-	bm := &BackupManifest{
-		FileEntries:   fes,
-		Position:      replicationPosition,
-		TransformHook: *backupStorageHook,
-		SkipCompress:  !*backupStorageCompress,
-	}
 	data, err := json.MarshalIndent(bm, "", "  ")
 	if err != nil {
-		return fmt.Errorf("cannot JSON encode %v: %v", backupManifest, err)
+		return fmt.Errorf("cannot JSON encode %v: %v", filename, err)
 	}
 	if _, err := wc.Write([]byte(data)); err != nil {
-		return fmt.Errorf("cannot write %v: %v", backupManifest, err)
+		return fmt.Errorf("cannot write %v: %v", filename, err)
 	}
-
 	return nil
 }
 
-// backupFile backs up an individual file.
-func backupFile(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh backupstorage.BackupHandle, fe *FileEntry, name string, hookExtraEnv map[string]string) (err error) {
+// readManifest reads and decodes the MANIFEST of a backup.
+func readManifest(ctx context.Context, bh backupstorage.BackupHandle) (*BackupManifest, error) {
+	return readManifestFile(ctx, bh, backupManifest)
+}
+
+// readManifestFile reads and decodes the file called filename of a
+// backup, written by writeManifestFile.
+func readManifestFile(ctx context.Context, bh backupstorage.BackupHandle, filename string) (*BackupManifest, error) {
+	rc, err := bh.ReadFile(ctx, filename)
+	if err != nil {
+		return nil, fmt.Errorf("can't read %v: %v", filename, err)
+	}
+	defer rc.Close()
+
+	bm := &BackupManifest{}
+	if err := json.NewDecoder(rc).Decode(bm); err != nil {
+		return nil, fmt.Errorf("cannot JSON decode %v: %v", filename, err)
+	}
+	return bm, nil
+}
+
+// backupFile backs up an individual file. prev is the entry of the file
+// in the backup being resumed, if any: its file or chunks are only
+// uploaded again if their data changed. record, if set, is called to
+// record the progress of fe, and forces it to be written before
+// anything of prev is replaced.
+func backupFile(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh backupstorage.BackupHandle, fe *FileEntry, prev *FileEntry, name string, hookExtraEnv map[string]string, record func(force bool) error) (err error) {
 	// Open the source file for reading.
 	var source *os.File
 	source, err = fe.open(mysqld.Cnf(), true)
@@ -444,11 +675,128 @@ func backupFile(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger,
 	if err != nil {
 		return err
 	}
+	size := fi.Size()
+
+	// beforeUpload records that the entry of prev is not valid
+	// anymore, before any of its data is overwritten.
+	prevRecorded := prev != nil
+	beforeUpload := func() error {
+		if !prevRecorded || record == nil {
+			return nil
+		}
+		prevRecorded = false
+		return record(true)
+	}
+	recordProgress := func() error {
+		if record == nil {
+			return nil
+		}
+		return record(false)
+	}
 
+	chunkSize := *backupChunkSize
+	if chunkSize <= 0 || size <= chunkSize {
+		section := io.NewSectionReader(source, 0, size)
+		if prev != nil && prev.ChunkSize == 0 && prev.Hash != "" {
+			unchanged, err := sectionHasHash(section, prev.RawHash)
+			if err != nil {
+				return err
+			}
+			if unchanged {
+				logger.Infof("backup file %v did not change, not uploading it again", name)
+				fe.Hash, fe.RawHash = prev.Hash, prev.RawHash
+				return recordProgress()
+			}
+		}
+		if err := beforeUpload(); err != nil {
+			return err
+		}
+		fe.Hash, fe.RawHash, err = backupSection(ctx, logger, bh, name, section, hookExtraEnv)
+		if err != nil {
+			return err
+		}
+		return recordProgress()
+	}
+
+	// Large files are split in chunks, each one stored in its own
+	// file, so a failed upload resumes at the chunk that failed.
+	fe.ChunkSize = chunkSize
+	fe.Chunks = nil
+	reused := 0
+	for offset := int64(0); offset < size; offset += chunkSize {
+		length := chunkSize
+		if offset+length > size {
+			length = size - offset
+		}
+		section := io.NewSectionReader(source, offset, length)
+		i := len(fe.Chunks)
+		if prev != nil && prev.ChunkSize == chunkSize && i < len(prev.Chunks) {
+			unchanged, err := sectionHasHash(section, prev.Chunks[i].RawHash)
+			if err != nil {
+				return err
+			}
+			if unchanged {
+				fe.Chunks = append(fe.Chunks, prev.Chunks[i])
+				reused++
+				continue
+			}
+		}
+		if err := beforeUpload(); err != nil {
+			return err
+		}
+		hash, rawHash, err := backupSection(ctx, logger, bh, chunkName(name, i), section, hookExtraEnv)
+		if err != nil {
+			return err
+		}
+		fe.Chunks = append(fe.Chunks, ChunkEntry{
+			Hash:    hash,
+			RawHash: rawHash,
+		})
+		if err := recordProgress(); err != nil {
+			return err
+		}
+	}
+	if reused > 0 {
+		logger.Infof("%v of the %v chunks of backup file %v did not change, they were not uploaded again", reused, len(fe.Chunks), name)
+	}
+	return recordProgress()
+}
+
+// sectionHasHash returns true if the data of section has the hash
+// rawHash.
+func sectionHasHash(section *io.SectionReader, rawHash string) (bool, error) {
+	hasher := newHasher()
+	if _, err := io.Copy(hasher, io.NewSectionReader(section, 0, section.Size())); err != nil {
+		return false, err
+	}
+	return hasher.HashString() == rawHash, nil
+}
+
+// backupSection backs up a section of a file to the file name of the
+// backup. If the upload fails, it is retried from the start of the
+// section, up to -backup_upload_retries times.
+func backupSection(ctx context.Context, logger logutil.Logger, bh backupstorage.BackupHandle, name string, section *io.SectionReader, hookExtraEnv map[string]string) (hash, rawHash string, err error) {
+	for attempt := 0; ; attempt++ {
+		if _, err := section.Seek(0, io.SeekStart); err != nil {
+			return "", "", err
+		}
+		hash, rawHash, err = backupStream(ctx, logger, bh, name, section.Size(), section, hookExtraEnv)
+		if err == nil || attempt >= *backupUploadRetries || ctx.Err() != nil {
+			return hash, rawHash, err
+		}
+		logger.Warningf("upload of backup file %v failed, retrying (%v/%v): %v", name, attempt+1, *backupUploadRetries, err)
+	}
+}
+
+// backupStream sends source to the file called name in the backup,
+// through the backup storage hook and compression if they are enabled.
+// It returns the hash of the data stored in the BackupStorage, and the
+// hash of source.
+func backupStream(ctx context.Context, logger logutil.Logger, bh backupstorage.BackupHandle, name string, size int64, source io.Reader, hookExtraEnv map[string]string) (hash, rawHash string, err error) {
 	// Open the destination file for writing, and a buffer.
-	wc, err := bh.AddFile(ctx, name, fi.Size())
+	wc, err := bh.AddFile(ctx, name, size)
 	if err != nil {
-		return fmt.Errorf("cannot add file: %v", err)
+		return "", "", fmt.Errorf("cannot add file: %v", err)
 	}
 	defer func() {
 		if rerr := wc.Close(); rerr != nil {
@@ -460,12 +808,16 @@ func backupFile(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger,
 			}
 		}
 	}()
-	dst := bufio.NewWriterSize(wc, 2*1024*1024)
+	dst := bufio.NewWriterSize(newThrottledWriter(ctx, wc), 2*1024*1024)
 
 	// Create the hasher and the tee on top.
 	hasher := newHasher()
 	writer := io.MultiWriter(dst, hasher)
 
+	// And hash the source as it is read.
+	rawHasher := newHasher()
+	source = io.TeeReader(source, rawHasher)
+
 	// Create the external write pipe, if any.
 	var pipe io.WriteCloser
 	var wait hook.WaitFunc
@@ -474,7 +826,7 @@ func backupFile(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger,
 		h.ExtraEnv = hookExtraEnv
 		pipe, wait, _, err = h.ExecuteAsWritePipe(writer)
 		if err != nil {
-			return fmt.Errorf("'%v' hook returned error: %v", *backupStorageHook, err)
+			return "", "", fmt.Errorf("'%v' hook returned error: %v", *backupStorageHook, err)
 		}
 		writer = pipe
 	}
@@ -484,47 +836,45 @@ func backupFile(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger,
 	if *backupStorageCompress {
 		gzip, err = cgzip.NewWriterLevel(writer, cgzip.Z_BEST_SPEED)
 		if err != nil {
-			return fmt.Errorf("cannot create gziper: %v", err)
+			return "", "", fmt.Errorf("cannot create gziper: %v", err)
 		}
 		writer = gzip
 	}
 
-	// Copy from the source file to writer (optional gzip,
+	// Copy from the source to writer (optional gzip,
 	// optional pipe, tee, output file and hasher).
 	_, err = io.Copy(writer, source)
 	if err != nil {
-		return fmt.Errorf("cannot copy data: %v", err)
+		return "", "", fmt.Errorf("cannot copy data: %v", err)
 	}
 
 	// Close gzip to flush it, after that all data is sent to writer.
 	if gzip != nil {
 		if err = gzip.Close(); err != nil {
-			return fmt.Errorf("cannot close gzip: %v", err)
+			return "", "", fmt.Errorf("cannot close gzip: %v", err)
 		}
 	}
 
 	// Close the hook pipe if necessary.
 	if pipe != nil {
 		if err := pipe.Close(); err != nil {
-			return fmt.Errorf("cannot close hook pipe: %v", err)
+			return "", "", fmt.Errorf("cannot close hook pipe: %v", err)
 		}
 		stderr, err := wait()
 		if stderr != "" {
 			logger.Infof("'%v' hook returned stderr: %v", *backupStorageHook, stderr)
 		}
 		if err != nil {
-			return fmt.Errorf("'%v' returned error: %v", *backupStorageHook, err)
+			return "", "", fmt.Errorf("'%v' returned error: %v", *backupStorageHook, err)
 		}
 	}
 
 	// Flush the buffer to finish writing on destination.
 	if err = dst.Flush(); err != nil {
-		return fmt.Errorf("cannot flush dst: %v", err)
+		return "", "", fmt.Errorf("cannot flush dst: %v", err)
 	}
 
-	// Save the hash.
-	fe.Hash = hasher.HashString()
-	return nil
+	return hasher.HashString(), rawHasher.HashString(), nil
 }
 
 // checkNoDB makes sure there is no user data already there.
@@ -590,14 +940,6 @@ func restoreFiles(ctx context.Context, cnf *Mycnf, bh backupstorage.BackupHandle
 
 // restoreFile restores an individual file.
 func restoreFile(ctx context.Context, cnf *Mycnf, bh backupstorage.BackupHandle, fe *FileEntry, transformHook string, compress bool, name string, hookExtraEnv map[string]string) (err error) {
-	// Open the source file for reading.
-	var source io.ReadCloser
-	source, err = bh.ReadFile(ctx, name)
-	if err != nil {
-		return err
-	}
-	defer source.Close()
-
 	// Open the destination file for writing.
 	dstFile, err := fe.open(cnf, false)
 	if err != nil {
@@ -617,6 +959,35 @@ func restoreFile(ctx context.Context, cnf *Mycnf, bh backupstorage.BackupHandle,
 	// Create a buffering output.
 	dst := bufio.NewWriterSize(dstFile, 2*1024*1024)
 
+	if len(fe.Chunks) == 0 {
+		if err := restoreStream(ctx, bh, name, fe.Name, fe.Hash, fe.RawHash, transformHook, compress, dst, hookExtraEnv); err != nil {
+			return err
+		}
+	} else {
+		for i, chunk := range fe.Chunks {
+			description := fmt.Sprintf("%v (chunk %v)", fe.Name, i)
+			if err := restoreStream(ctx, bh, chunkName(name, i), description, chunk.Hash, chunk.RawHash, transformHook, compress, dst, hookExtraEnv); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Flush the buffer.
+	return dst.Flush()
+}
+
+// restoreStream reads the file called name from the backup into dst,
+// through the transform hook and decompression if they were used, and
+// checks its hash. description is used in the hash mismatch error.
+func restoreStream(ctx context.Context, bh backupstorage.BackupHandle, name, description, expectedHash, expectedRawHash, transformHook string, compress bool, dst io.Writer, hookExtraEnv map[string]string) (err error) {
+	// Open the source file for reading.
+	var source io.ReadCloser
+	source, err = bh.ReadFile(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
 	// Create hash to write the compressed data to.
 	hasher := newHasher()
 
@@ -654,8 +1025,10 @@ func restoreFile(ctx context.Context, cnf *Mycnf, bh backupstorage.BackupHandle,
 		reader = gz
 	}
 
-	// Copy the data. Will also write to the hasher.
-	if _, err = io.Copy(dst, reader); err != nil {
+	// Copy the data. Will also write to the hasher, and to the
+	// hasher of the restored data.
+	rawHasher := newHasher()
+	if _, err = io.Copy(io.MultiWriter(dst, rawHasher), reader); err != nil {
 		return err
 	}
 
@@ -672,12 +1045,17 @@ func restoreFile(ctx context.Context, cnf *Mycnf, bh backupstorage.BackupHandle,
 
 	// Check the hash.
 	hash := hasher.HashString()
-	if hash != fe.Hash {
-		return fmt.Errorf("hash mismatch for %v, got %v expected %v", fe.Name, hash, fe.Hash)
+	if hash != expectedHash {
+		return fmt.Errorf("hash mismatch for %v, got %v expected %v", description, hash, expectedHash)
 	}
 
-	// Flush the buffer.
-	return dst.Flush()
+	// Old backups don't have the hash of the restored data.
+	if expectedRawHash != "" {
+		if rawHash := rawHasher.HashString(); rawHash != expectedRawHash {
+			return fmt.Errorf("restored data hash mismatch for %v, got %v expected %v", description, rawHash, expectedRawHash)
+		}
+	}
+	return nil
 }
 
 // removeExistingFiles will delete existing files in the data dir to prevent
@@ -732,6 +1110,11 @@ func removeExistingFiles(cnf *Mycnf) error {
 // Restore is the main entry point for backup restore.  If there is no
 // appropriate backup on the BackupStorage, Restore logs an error
 // and returns ErrNoBackup. Any other error is returned.
+//
+// If restoreToTime or restoreToPos is set, Restore does a point-in-time
+// recovery: it restores the last full backup taken before that point,
+// and then replays the archived binlogs of the incremental backups
+// that follow it, up to that point.
 func Restore(
 	ctx context.Context,
 	mysqld MysqlDaemon,
@@ -741,7 +1124,19 @@ func Restore(
 	localMetadata map[string]string,
 	logger logutil.Logger,
 	deleteBeforeRestore bool,
-	dbName string) (mysql.Position, error) {
+	dbName string,
+	restoreToTime time.Time,
+	restoreToPos mysql.Position) (mysql.Position, error) {
+
+	pointInTime := !restoreToTime.IsZero() || !restoreToPos.IsZero()
+	if !restoreToTime.IsZero() && !restoreToPos.IsZero() {
+		return mysql.Position{}, errors.New("cannot restore to both a timestamp and a position")
+	}
+	if !restoreToPos.IsZero() {
+		if _, ok := restoreToPos.GTIDSet.(mysql.Mysql56GTIDSet); !ok {
+			return mysql.Position{}, fmt.Errorf("restoring to a position is only supported for MySQL 5.6+ GTIDs, got %v", restoreToPos)
+		}
+	}
 
 	// Wait for mysqld to be ready, in case it was launched in parallel with us.
 	if err := mysqld.Wait(ctx); err != nil {
@@ -786,20 +1181,21 @@ func Restore(
 	}
 
 	var bh backupstorage.BackupHandle
-	var bm BackupManifest
+	var bm *BackupManifest
 	var toRestore int
 	for toRestore = len(bhs) - 1; toRestore >= 0; toRestore-- {
 		bh = bhs[toRestore]
-		rc, err := bh.ReadFile(ctx, backupManifest)
+		bm, err = readManifest(ctx, bh)
 		if err != nil {
-			log.Warningf("Possibly incomplete backup %v in directory %v on BackupStorage: can't read MANIFEST: %v)", bh.Name(), dir, err)
+			log.Warningf("Possibly incomplete backup %v in directory %v on BackupStorage: %v", bh.Name(), dir, err)
 			continue
 		}
-
-		err = json.NewDecoder(rc).Decode(&bm)
-		rc.Close()
-		if err != nil {
-			log.Warningf("Possibly incomplete backup %v in directory %v on BackupStorage (cannot JSON decode MANIFEST: %v)", bh.Name(), dir, err)
+		if !restoreToTime.IsZero() && (bm.BackupTime.IsZero() || bm.BackupTime.After(restoreToTime)) {
+			logger.Infof("Restore: skipping backup %v, it was not taken before %v", bh.Name(), restoreToTime)
+			continue
+		}
+		if !restoreToPos.IsZero() && !restoreToPos.AtLeast(bm.Position) {
+			logger.Infof("Restore: skipping backup %v, its position %v is not before %v", bh.Name(), bm.Position, restoreToPos)
 			continue
 		}
 
@@ -807,35 +1203,89 @@ func Restore(
 		break
 	}
 	if toRestore < 0 {
+		if pointInTime {
+			return mysql.Position{}, errors.New("no full backup found before the point in time to restore to")
+		}
 		// There is at least one attempted backup, but none could be read.
 		// This implies there is data we ought to have, so it's not safe to start
 		// up empty.
 		return mysql.Position{}, errors.New("backup(s) found but none could be read, unsafe to start up empty, restart to retry restore")
 	}
 
+	re, err := getRestoreEngine(bm)
+	if err != nil {
+		return mysql.Position{}, err
+	}
+
+	// For a point-in-time recovery, find the archived binlogs to
+	// replay now, before we destroy anything.
+	var incrementals []backupstorage.BackupHandle
+	var incrementalManifests []*BackupManifest
+	if pointInTime {
+		if err := checkBinlogReplayPosition(bm.Position); err != nil {
+			return mysql.Position{}, err
+		}
+		if !restoreToPos.IsZero() {
+			if err := checkBinlogReplayPosition(restoreToPos); err != nil {
+				return mysql.Position{}, err
+			}
+		}
+		abhs, err := bs.ListBinlogArchives(ctx, dir)
+		if err != nil {
+			return mysql.Position{}, fmt.Errorf("ListBinlogArchives failed: %v", err)
+		}
+		incrementals, incrementalManifests, err = findIncrementalBackups(ctx, logger, abhs, bm.Position, restoreToPos)
+		if err != nil {
+			return mysql.Position{}, err
+		}
+	}
+
 	// Starting from here we won't be able to recover if we get stopped by a cancelled
 	// context. Thus we use the background context to get through to the finish.
 
-	logger.Infof("Restore: shutdown mysqld")
-	err = mysqld.Shutdown(context.Background(), true)
-	if err != nil {
+	if err := restoreBackup(context.Background(), mysqld, logger, bh, bm, re, restoreConcurrency, hookExtraEnv, localMetadata); err != nil {
 		return mysql.Position{}, err
 	}
 
+	if pointInTime {
+		logger.Infof("Restore: replaying archived binlogs from %v incremental backups", len(incrementals))
+		if err := applyIncrementalBackups(context.Background(), mysqld, incrementals, incrementalManifests, bm.Position, restoreToTime, restoreToPos, restoreConcurrency, hookExtraEnv); err != nil {
+			return mysql.Position{}, err
+		}
+		return mysqld.MasterPosition()
+	}
+
+	return bm.Position, nil
+}
+
+// restoreBackup replaces the data of mysqld with the backup bh, using
+// the BackupEngine re, and restarts mysqld.
+func restoreBackup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh backupstorage.BackupHandle, bm *BackupManifest, re BackupEngine, restoreConcurrency int, hookExtraEnv map[string]string, localMetadata map[string]string) error {
+	// Get the data key before touching mysqld.
+	bh, err := openBackup(ctx, bh, bm)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Restore: shutdown mysqld")
+	if err := mysqld.Shutdown(ctx, true); err != nil {
+		return err
+	}
+
 	logger.Infof("Restore: deleting existing files")
 	if err := removeExistingFiles(mysqld.Cnf()); err != nil {
-		return mysql.Position{}, err
+		return err
 	}
 
 	logger.Infof("Restore: reinit config file")
-	err = mysqld.ReinitConfig(context.Background())
+	err = mysqld.ReinitConfig(ctx)
 	if err != nil {
-		return mysql.Position{}, err
+		return err
 	}
 
-	logger.Infof("Restore: copying all files")
-	if err := restoreFiles(context.Background(), mysqld.Cnf(), bh, bm.FileEntries, bm.TransformHook, !bm.SkipCompress, restoreConcurrency, hookExtraEnv); err != nil {
-		return mysql.Position{}, err
+	logger.Infof("Restore: restoring files")
+	if err := re.ExecuteRestore(ctx, mysqld.Cnf(), logger, bh, bm, restoreConcurrency, hookExtraEnv); err != nil {
+		return err
 	}
 
 	// mysqld needs to be running in order for mysql_upgrade to work.
@@ -847,14 +1297,14 @@ func Restore(
 	// of those who can connect.
 	logger.Infof("Restore: starting mysqld for mysql_upgrade")
 	// Note Start will use dba user for waiting, this is fine, it will be allowed.
-	err = mysqld.Start(context.Background(), "--skip-grant-tables", "--skip-networking")
+	err = mysqld.Start(ctx, "--skip-grant-tables", "--skip-networking")
 	if err != nil {
-		return mysql.Position{}, err
+		return err
 	}
 
 	logger.Infof("Restore: running mysql_upgrade")
 	if err := mysqld.RunMysqlUpgrade(); err != nil {
-		return mysql.Position{}, fmt.Errorf("mysql_upgrade failed: %v", err)
+		return fmt.Errorf("mysql_upgrade failed: %v", err)
 	}
 
 	// Populate local_metadata before starting without --skip-networking,
@@ -862,20 +1312,15 @@ func Restore(
 	logger.Infof("Restore: populating local_metadata")
 	err = populateMetadataTables(mysqld, localMetadata)
 	if err != nil {
-		return mysql.Position{}, err
+		return err
 	}
 
 	// The MySQL manual recommends restarting mysqld after running mysql_upgrade,
 	// so that any changes made to system tables take effect.
 	logger.Infof("Restore: restarting mysqld after mysql_upgrade")
-	err = mysqld.Shutdown(context.Background(), true)
-	if err != nil {
-		return mysql.Position{}, err
-	}
-	err = mysqld.Start(context.Background())
+	err = mysqld.Shutdown(ctx, true)
 	if err != nil {
-		return mysql.Position{}, err
+		return err
 	}
-
-	return bm.Position, nil
+	return mysqld.Start(ctx)
 }
@@ -0,0 +1,95 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"io"
+	"sync"
+
+	"golang.org/x/net/context"
+	"golang.org/x/time/rate"
+)
+
+var (
+	// uploadLimiter is shared by all the uploads of the process,
+	// so -backup_max_bandwidth is a limit for all of them together.
+	uploadLimiterOnce sync.Once
+	uploadLimiter     *rate.Limiter
+)
+
+// maxThrottleBurst is the largest write sent at once when the
+// bandwidth is limited.
+const maxThrottleBurst = 1024 * 1024
+
+// getUploadLimiter returns the limiter for -backup_max_bandwidth,
+// or nil if the bandwidth is not limited.
+func getUploadLimiter() *rate.Limiter {
+	uploadLimiterOnce.Do(func() {
+		if *backupMaxBandwidth <= 0 {
+			return
+		}
+		burst := maxThrottleBurst
+		if *backupMaxBandwidth < int64(burst) {
+			burst = int(*backupMaxBandwidth)
+		}
+		uploadLimiter = rate.NewLimiter(rate.Limit(*backupMaxBandwidth), burst)
+	})
+	return uploadLimiter
+}
+
+// throttledWriter waits on a rate limiter before each write.
+type throttledWriter struct {
+	ctx     context.Context
+	w       io.Writer
+	limiter *rate.Limiter
+}
+
+// newThrottledWriter returns w limited to -backup_max_bandwidth, or w
+// itself if the bandwidth is not limited.
+func newThrottledWriter(ctx context.Context, w io.Writer) io.Writer {
+	limiter := getUploadLimiter()
+	if limiter == nil {
+		return w
+	}
+	return &throttledWriter{
+		ctx:     ctx,
+		w:       w,
+		limiter: limiter,
+	}
+}
+
+// Write is part of the io.Writer interface. Large writes are split
+// in pieces no larger than the burst of the limiter.
+func (tw *throttledWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := len(p)
+		if n > tw.limiter.Burst() {
+			n = tw.limiter.Burst()
+		}
+		if err := tw.limiter.WaitN(tw.ctx, n); err != nil {
+			return written, err
+		}
+		n, err := tw.w.Write(p[:n])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/encryptedbackupstorage"
+)
+
+// This file handles the resuming of builtin backups: with -backup_resume,
+// the files and chunks uploaded by a backup are recorded in its PROGRESS
+// file, and a failed backup is kept. The next backup of the same tablet
+// reopens it, and only uploads the files and chunks whose data changed.
+
+// resumedBackupHandle is a backup reopened to be resumed, with the
+// progress recorded by the backup that failed.
+type resumedBackupHandle struct {
+	backupstorage.BackupHandle
+	progress *BackupManifest
+}
+
+// backupNameOwner returns what follows the time prefix of a backup
+// name, i.e. the alias of the tablet that took it.
+func backupNameOwner(name string) string {
+	if len(name) <= len(BackupNameTimeFormat)+1 || name[len(BackupNameTimeFormat)] != '.' {
+		return ""
+	}
+	return name[len(BackupNameTimeFormat)+1:]
+}
+
+// resumeBackup reopens the last backup in dir taken by the owner of
+// name, if it did not finish and can be resumed with the current
+// settings. It returns a nil BackupHandle if there is nothing to resume.
+func resumeBackup(ctx context.Context, bs backupstorage.BackupStorage, logger logutil.Logger, dir, name string) (backupstorage.BackupHandle, error) {
+	owner := backupNameOwner(name)
+	if owner == "" {
+		return nil, nil
+	}
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("ListBackups failed: %v", err)
+	}
+	var last backupstorage.BackupHandle
+	for i := len(bhs) - 1; i >= 0; i-- {
+		if backupNameOwner(bhs[i].Name()) == owner {
+			last = bhs[i]
+			break
+		}
+	}
+	if last == nil {
+		return nil, nil
+	}
+	if _, err := readManifest(ctx, last); err == nil {
+		// The last backup finished.
+		return nil, nil
+	}
+	progress, err := readManifestFile(ctx, last, backupProgressFile)
+	if err != nil {
+		logger.Infof("not resuming backup %v: %v", last.Name(), err)
+		return nil, nil
+	}
+	if progress.TransformHook != *backupStorageHook || progress.SkipCompress != !*backupStorageCompress || (progress.Encryption != nil) != encryptedbackupstorage.Enabled() {
+		logger.Infof("not resuming backup %v, it was taken with different backup storage settings", last.Name())
+		return nil, nil
+	}
+
+	bh, err := bs.ResumeBackup(ctx, dir, last.Name())
+	if err != nil {
+		return nil, fmt.Errorf("ResumeBackup failed: %v", err)
+	}
+	if progress.Encryption != nil {
+		// The files uploaded already are encrypted with the
+		// data key of the backup, so it is used for the others.
+		ebh, err := encryptedbackupstorage.NewReadHandle(ctx, bh, progress.Encryption)
+		if err != nil {
+			return nil, err
+		}
+		bh = ebh
+	}
+	logger.Infof("resuming backup %v", last.Name())
+	return &resumedBackupHandle{
+		BackupHandle: bh,
+		progress:     progress,
+	}, nil
+}
+
+// resumableEntry returns the entry of the file fe, at index i, in the
+// progress of a resumed backup. The files of a backup are stored under
+// their index, so the entry is only usable if the file has the same one.
+func resumableEntry(prev *BackupManifest, i int, fe *FileEntry) *FileEntry {
+	if prev == nil || i >= len(prev.FileEntries) {
+		return nil
+	}
+	entry := &prev.FileEntries[i]
+	if entry.Base != fe.Base || entry.Name != fe.Name {
+		return nil
+	}
+	return entry
+}
+
+// backupProgress records the files and chunks uploaded by a backup in
+// its PROGRESS file.
+type backupProgress struct {
+	bh backupstorage.BackupHandle
+
+	mu        sync.Mutex
+	bm        BackupManifest
+	lastWrite time.Time
+}
+
+// newBackupProgress returns a backupProgress for the files fes. The
+// entries of prev that can be resumed stay valid until they are
+// replaced.
+func newBackupProgress(bh backupstorage.BackupHandle, fes []FileEntry, prev *BackupManifest) *backupProgress {
+	p := &backupProgress{
+		bh: bh,
+		bm: BackupManifest{
+			FileEntries:   make([]FileEntry, len(fes)),
+			TransformHook: *backupStorageHook,
+			SkipCompress:  !*backupStorageCompress,
+			BackupMethod:  builtin,
+		},
+	}
+	for i := range fes {
+		if entry := resumableEntry(prev, i, &fes[i]); entry != nil {
+			p.bm.FileEntries[i] = *entry
+		} else {
+			p.bm.FileEntries[i] = FileEntry{Base: fes[i].Base, Name: fes[i].Name}
+		}
+	}
+	return p
+}
+
+// record updates the entry of the file at index i to fe. The PROGRESS
+// file is written if force is set, or every backupProgressInterval.
+// Only a forced write returns its error, the backup can go on without
+// the others.
+func (p *backupProgress) record(ctx context.Context, logger logutil.Logger, i int, fe *FileEntry, force bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry := *fe
+	entry.Chunks = append([]ChunkEntry(nil), fe.Chunks...)
+	p.bm.FileEntries[i] = entry
+	if !force && time.Since(p.lastWrite) < backupProgressInterval {
+		return nil
+	}
+	err := p.writeLocked(ctx)
+	if err != nil && !force {
+		logger.Warningf("cannot record the progress of the backup: %v", err)
+		return nil
+	}
+	return err
+}
+
+// flush writes the PROGRESS file, when the backup stops.
+func (p *backupProgress) flush(ctx context.Context, logger logutil.Logger) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.writeLocked(ctx); err != nil {
+		logger.Warningf("cannot record the progress of the backup, the next backup will not resume it: %v", err)
+	}
+}
+
+func (p *backupProgress) writeLocked(ctx context.Context) error {
+	p.lastWrite = time.Now()
+	return writeManifestFile(ctx, p.bh, backupProgressFile, &p.bm)
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+// gatedBackupHandle only starts the upload of the file gated once the
+// file first is uploaded.
+type gatedBackupHandle struct {
+	backupstorage.BackupHandle
+
+	first, gated string
+	done         chan struct{}
+}
+
+func (gbh *gatedBackupHandle) AddFile(ctx context.Context, filename string, filesize int64) (io.WriteCloser, error) {
+	if filename == gbh.gated {
+		<-gbh.done
+	}
+	wc, err := gbh.BackupHandle.AddFile(ctx, filename, filesize)
+	if err != nil || filename != gbh.first {
+		return wc, err
+	}
+	return &notifyingWriteCloser{WriteCloser: wc, done: gbh.done}, nil
+}
+
+type notifyingWriteCloser struct {
+	io.WriteCloser
+	done chan struct{}
+}
+
+func (nwc *notifyingWriteCloser) Close() error {
+	defer close(nwc.done)
+	return nwc.WriteCloser.Close()
+}
+
+func TestBackupNameOwner(t *testing.T) {
+	testcases := []struct {
+		name string
+		want string
+	}{{
+		name: "2018-06-01.120000.zone1-0000000100",
+		want: "zone1-0000000100",
+	}, {
+		name: "2018-06-01.120000",
+		want: "",
+	}, {
+		name: "backup",
+		want: "",
+	}}
+	for _, tcase := range testcases {
+		if got := backupNameOwner(tcase.name); got != tcase.want {
+			t.Errorf("backupNameOwner(%v): %v, want %v", tcase.name, got, tcase.want)
+		}
+	}
+}
+
+func TestBackupResume(t *testing.T) {
+	root, err := ioutil.TempDir("", "backuptest")
+	if err != nil {
+		t.Fatalf("os.TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(root)
+	*filebackupstorage.FileBackupStorageRoot = path.Join(root, "backups")
+	bs := &filebackupstorage.FileBackupStorage{}
+	ctx := context.Background()
+	logger := logutil.NewMemoryLogger()
+
+	*backupResume = true
+	*backupChunkSize = 3000
+	*backupUploadRetries = 0
+	defer func() {
+		*backupResume = false
+		*backupChunkSize = 0
+		*backupUploadRetries = 2
+	}()
+
+	cnf := &Mycnf{
+		DataDir:               path.Join(root, "data"),
+		InnodbDataHomeDir:     path.Join(root, "innodb", "data"),
+		InnodbLogGroupHomeDir: path.Join(root, "innodb", "logs"),
+	}
+	for _, dir := range []string{path.Join(cnf.DataDir, "vt_db"), cnf.InnodbDataHomeDir, cnf.InnodbLogGroupHomeDir} {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(path.Join(cnf.DataDir, "vt_db", "db.opt"), []byte("default-character-set=utf8"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i * i)
+	}
+	if err := ioutil.WriteFile(path.Join(cnf.DataDir, "vt_db", "t1.ibd"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	mysqld := &Mysqld{config: cnf}
+
+	// The backup fails at the third chunk of t1.ibd, the second file,
+	// after the first one is uploaded.
+	name := "2018-06-01.120000.zone1-0000000100"
+	bh, err := bs.StartBackup(ctx, "ks/0", name)
+	if err != nil {
+		t.Fatalf("StartBackup failed: %v", err)
+	}
+	gbh := &gatedBackupHandle{
+		BackupHandle: &flakyBackupHandle{
+			BackupHandle: bh,
+			failures:     map[string]int{"1-2": 1},
+		},
+		first: "0",
+		gated: "1-2",
+		done:  make(chan struct{}),
+	}
+	if err := backupFiles(ctx, mysqld, logger, gbh, mysql.Position{}, nil, 2, nil); err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Fatalf("backupFiles with a failed upload: %v, want connection reset", err)
+	}
+
+	// Only the backups of the same tablet are resumed.
+	if bh, err := resumeBackup(ctx, bs, logger, "ks/0", "2018-06-01.130000.zone1-0000000101"); err != nil || bh != nil {
+		t.Errorf("resumeBackup for another tablet: %v, %v, want nothing to resume", bh, err)
+	}
+	bh, err = resumeBackup(ctx, bs, logger, "ks/0", "2018-06-01.130000.zone1-0000000100")
+	if err != nil || bh == nil {
+		t.Fatalf("resumeBackup: %v, %v", bh, err)
+	}
+	if bh.Name() != name {
+		t.Errorf("resumed backup %v, want %v", bh.Name(), name)
+	}
+
+	// The first chunk changed: only it and the chunks that were not
+	// uploaded are uploaded again.
+	data[0]++
+	if err := ioutil.WriteFile(path.Join(cnf.DataDir, "vt_db", "t1.ibd"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	rbh := bh.(*resumedBackupHandle)
+	fbh := &flakyBackupHandle{BackupHandle: rbh.BackupHandle}
+	rbh.BackupHandle = fbh
+	if err := backupFiles(ctx, mysqld, logger, rbh, mysql.Position{}, nil, 2, nil); err != nil {
+		t.Fatalf("backupFiles failed: %v", err)
+	}
+	var uploaded []string
+	for _, filename := range fbh.added {
+		if filename != backupProgressFile {
+			uploaded = append(uploaded, filename)
+		}
+	}
+	sort.Strings(uploaded)
+	if want := []string{"1-0", "1-2", "1-3", backupManifest}; !reflect.DeepEqual(uploaded, want) {
+		t.Errorf("uploaded files: %v, want %v", uploaded, want)
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		t.Fatalf("EndBackup failed: %v", err)
+	}
+
+	// The backup has the new data.
+	if bh, err := resumeBackup(ctx, bs, logger, "ks/0", "2018-06-01.140000.zone1-0000000100"); err != nil || bh != nil {
+		t.Errorf("resumeBackup after a finished backup: %v, %v, want nothing to resume", bh, err)
+	}
+	_, bm, err := findBackup(ctx, bs, "ks/0", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := &Mycnf{
+		DataDir:               path.Join(root, "restored"),
+		InnodbDataHomeDir:     path.Join(root, "restored"),
+		InnodbLogGroupHomeDir: path.Join(root, "restored"),
+	}
+	bhs, err := bs.ListBackups(ctx, "ks/0")
+	if err != nil || len(bhs) != 1 {
+		t.Fatalf("ListBackups: %v, %v", bhs, err)
+	}
+	if err := restoreFiles(ctx, restored, bhs[0], bm.FileEntries, "", !bm.SkipCompress, 1, nil); err != nil {
+		t.Fatalf("restoreFiles failed: %v", err)
+	}
+	got, err := ioutil.ReadFile(path.Join(root, "restored", "vt_db", "t1.ibd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("restored data is different from the backed up data")
+	}
+}
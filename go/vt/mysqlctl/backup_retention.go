@@ -0,0 +1,184 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+// This file handles the retention of backups: old backups are removed
+// from the BackupStorage, keeping the newest backup of each of the last
+// days and weeks.
+
+// BackupNameTimeFormat is the format of the time prefix of backup and
+// binlog archive names.
+const BackupNameTimeFormat = "2006-01-02.150405"
+
+// backupRetentionInfo is what the retention policy needs to know
+// about a backup. Incremental backups are binlog archives.
+type backupRetentionInfo struct {
+	name        string
+	time        time.Time
+	incremental bool
+}
+
+// backupTime returns the time a backup was taken. Backups taken before
+// the MANIFEST recorded it get it from the prefix of their name. It
+// returns the zero time if it is unknown.
+func backupTime(name string, bm *BackupManifest) time.Time {
+	if !bm.BackupTime.IsZero() {
+		return bm.BackupTime
+	}
+	if i := strings.IndexByte(name, '.'); i != -1 {
+		if j := strings.IndexByte(name[i+1:], '.'); j != -1 {
+			if t, err := time.Parse(BackupNameTimeFormat, name[:i+1+j]); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// backupsToPrune returns the backups to remove so that
+// only the newest full backup of each of the keepDaily most recent days
+// and of each of the keepWeekly most recent ISO weeks (in UTC) are left.
+// The newest full backup is always kept. Incremental backups are only
+// removed once they are older than the oldest full backup kept, since
+// they can't be used for a restore anymore.
+func backupsToPrune(backups []backupRetentionInfo, keepDaily, keepWeekly int) []backupRetentionInfo {
+	var full []backupRetentionInfo
+	for _, b := range backups {
+		if !b.incremental {
+			full = append(full, b)
+		}
+	}
+	if len(full) == 0 {
+		return nil
+	}
+	sort.SliceStable(full, func(i, j int) bool {
+		return full[i].time.After(full[j].time)
+	})
+
+	keep := map[string]bool{full[0].name: true}
+	days := make(map[string]bool)
+	weeks := make(map[string]bool)
+	for _, b := range full {
+		t := b.time.UTC()
+		day := t.Format("2006-01-02")
+		if !days[day] && len(days) < keepDaily {
+			days[day] = true
+			keep[b.name] = true
+		}
+		year, week := t.ISOWeek()
+		weekKey := fmt.Sprintf("%v-%v", year, week)
+		if !weeks[weekKey] && len(weeks) < keepWeekly {
+			weeks[weekKey] = true
+			keep[b.name] = true
+		}
+	}
+
+	var oldestKept time.Time
+	for _, b := range full {
+		if keep[b.name] {
+			oldestKept = b.time
+		}
+	}
+
+	var result []backupRetentionInfo
+	for _, b := range backups {
+		if !b.incremental && keep[b.name] {
+			continue
+		}
+		if b.incremental && !b.time.Before(oldestKept) {
+			continue
+		}
+		result = append(result, b)
+	}
+	return result
+}
+
+// PruneBackups removes the backups in dir that are not needed to keep
+// the newest backup of each of the keepDaily most recent days and of
+// each of the keepWeekly most recent weeks, and the binlog archives
+// older than all the backups kept. Backups with no readable MANIFEST
+// may be in progress, and are never removed. If dryRun is set, the
+// backups are only logged. It returns the names of the backups and
+// binlog archives that were (or would be) removed.
+func PruneBackups(ctx context.Context, logger logutil.Logger, dir string, keepDaily, keepWeekly int, dryRun bool) ([]string, error) {
+	if keepDaily < 0 || keepWeekly < 0 {
+		return nil, fmt.Errorf("invalid retention policy: %v daily, %v weekly", keepDaily, keepWeekly)
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("ListBackups failed: %v", err)
+	}
+	abhs, err := bs.ListBinlogArchives(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("ListBinlogArchives failed: %v", err)
+	}
+	var backups []backupRetentionInfo
+	for i, bh := range append(bhs, abhs...) {
+		bm, err := readManifest(ctx, bh)
+		if err != nil {
+			logger.Infof("PruneBackups: skipping possibly incomplete backup %v: %v", bh.Name(), err)
+			continue
+		}
+		t := backupTime(bh.Name(), bm)
+		if t.IsZero() {
+			logger.Infof("PruneBackups: skipping backup %v, its time is unknown", bh.Name())
+			continue
+		}
+		backups = append(backups, backupRetentionInfo{
+			name:        bh.Name(),
+			time:        t,
+			incremental: i >= len(bhs),
+		})
+	}
+
+	var names []string
+	for _, b := range backupsToPrune(backups, keepDaily, keepWeekly) {
+		kind, remove := "backup", bs.RemoveBackup
+		if b.incremental {
+			kind, remove = "binlog archive", bs.RemoveBinlogArchive
+		}
+		names = append(names, b.name)
+		if dryRun {
+			logger.Infof("PruneBackups: would remove %v %v", kind, b.name)
+			continue
+		}
+		logger.Infof("PruneBackups: removing %v %v", kind, b.name)
+		if err := remove(ctx, dir, b.name); err != nil {
+			return nil, fmt.Errorf("removing %v %v failed: %v", kind, b.name, err)
+		}
+	}
+	return names, nil
+}
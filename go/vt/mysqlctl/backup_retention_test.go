@@ -0,0 +1,146 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func TestBackupTime(t *testing.T) {
+	want := time.Date(2018, 3, 4, 5, 6, 7, 0, time.UTC)
+	if got := backupTime("2018-03-04.050607.cell-0000000100", &BackupManifest{}); !got.Equal(want) {
+		t.Errorf("backupTime(name) = %v, want %v", got, want)
+	}
+	other := time.Date(2018, 3, 5, 0, 0, 0, 0, time.UTC)
+	if got := backupTime("2018-03-04.050607.cell-0000000100", &BackupManifest{BackupTime: other}); !got.Equal(other) {
+		t.Errorf("backupTime(manifest) = %v, want %v", got, other)
+	}
+	if got := backupTime("custom", &BackupManifest{}); !got.IsZero() {
+		t.Errorf("backupTime(custom) = %v, want zero time", got)
+	}
+}
+
+func TestBackupsToPrune(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2018, 3, day, hour, 0, 0, 0, time.UTC)
+	}
+	// 2018-03-05 is a Monday: days 5 to 11 are one ISO week,
+	// days 12 to 18 the next one.
+	backups := []backupRetentionInfo{
+		{name: "a", time: at(1, 10)},
+		{name: "b", time: at(6, 10)},
+		{name: "b-inc", time: at(6, 12), incremental: true},
+		{name: "c", time: at(8, 10)},
+		{name: "d", time: at(13, 10)},
+		{name: "e", time: at(14, 10)},
+		{name: "e-inc", time: at(14, 12), incremental: true},
+		{name: "f", time: at(15, 8)},
+		{name: "g", time: at(15, 10)},
+	}
+
+	testcases := []struct {
+		keepDaily, keepWeekly int
+		want                  []string
+	}{{
+		// Only the newest backup is kept, even with no policy.
+		keepDaily:  0,
+		keepWeekly: 0,
+		want:       []string{"a", "b", "b-inc", "c", "d", "e", "e-inc", "f"},
+	}, {
+		keepDaily:  2,
+		keepWeekly: 0,
+		want:       []string{"a", "b", "b-inc", "c", "d", "f"},
+	}, {
+		keepDaily:  1,
+		keepWeekly: 2,
+		want:       []string{"a", "b", "b-inc", "d", "e", "f"},
+	}, {
+		keepDaily:  10,
+		keepWeekly: 10,
+		want:       []string{"f"},
+	}}
+	for _, tc := range testcases {
+		var got []string
+		for _, b := range backupsToPrune(backups, tc.keepDaily, tc.keepWeekly) {
+			got = append(got, b.name)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("backupsToPrune(%v, %v) = %v, want %v", tc.keepDaily, tc.keepWeekly, got, tc.want)
+		}
+	}
+
+	if got := backupsToPrune([]backupRetentionInfo{{name: "inc", time: at(1, 0), incremental: true}}, 0, 0); got != nil {
+		t.Errorf("backupsToPrune(only incremental) = %v, want nil", got)
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	root, err := ioutil.TempDir("", "pruneBackupsTest")
+	if err != nil {
+		t.Fatalf("os.TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(root)
+	*filebackupstorage.FileBackupStorageRoot = root
+	*backupstorage.BackupStorageImplementation = "file"
+	defer func() { *backupstorage.BackupStorageImplementation = "" }()
+	bs := &filebackupstorage.FileBackupStorage{}
+
+	ctx := context.Background()
+	at := func(day int) time.Time {
+		return time.Date(2018, 3, day, 10, 0, 0, 0, time.UTC)
+	}
+	for _, b := range []backupRetentionInfo{
+		{name: "1-full", time: at(1)},
+		{name: "2-archive", time: at(2), incremental: true},
+		{name: "3-full", time: at(3)},
+		{name: "4-archive", time: at(4), incremental: true},
+	} {
+		start := bs.StartBackup
+		if b.incremental {
+			start = bs.StartBinlogArchive
+		}
+		bh, err := start(ctx, "ks/0", b.name)
+		if err != nil {
+			t.Fatalf("StartBackup(%v) failed: %v", b.name, err)
+		}
+		if err := writeManifest(ctx, bh, &BackupManifest{BackupTime: b.time, Incremental: b.incremental}); err != nil {
+			t.Fatalf("writeManifest failed: %v", err)
+		}
+	}
+
+	// Only the newest full backup is kept, and the archives after it.
+	names, err := PruneBackups(ctx, logutil.NewMemoryLogger(), "ks/0", 0, 0, false /* dryRun */)
+	if want := []string{"1-full", "2-archive"}; err != nil || !reflect.DeepEqual(names, want) {
+		t.Errorf("PruneBackups() = %v, %v, want %v", names, err, want)
+	}
+	if bhs, err := bs.ListBackups(ctx, "ks/0"); err != nil || len(bhs) != 1 || bhs[0].Name() != "3-full" {
+		t.Errorf("ListBackups() = %v, %v, want 3-full only", bhs, err)
+	}
+	if bhs, err := bs.ListBinlogArchives(ctx, "ks/0"); err != nil || len(bhs) != 1 || bhs[0].Name() != "4-archive" {
+		t.Errorf("ListBinlogArchives() = %v, %v, want 4-archive only", bhs, err)
+	}
+}
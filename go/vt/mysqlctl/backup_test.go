@@ -17,12 +17,26 @@ limitations under the License.
 package mysqlctl
 
 import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path"
 	"reflect"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/encryptedbackupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
 )
 
 func TestFindFilesToBackup(t *testing.T) {
@@ -103,3 +117,185 @@ type forTest []FileEntry
 func (f forTest) Len() int           { return len(f) }
 func (f forTest) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
 func (f forTest) Less(i, j int) bool { return f[i].Base+f[i].Name < f[j].Base+f[j].Name }
+
+func TestEncryptedBackup(t *testing.T) {
+	root, err := ioutil.TempDir("", "backuptest")
+	if err != nil {
+		t.Fatalf("os.TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(root)
+	*filebackupstorage.FileBackupStorageRoot = path.Join(root, "backups")
+	*encryptedbackupstorage.KeyringFile = path.Join(root, "keyring")
+	*encryptedbackupstorage.KeyManagerImplementation = "keyring"
+	defer func() { *encryptedbackupstorage.KeyManagerImplementation = "" }()
+	keyring := fmt.Sprintf("key1 %v\n", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)))
+	if err := ioutil.WriteFile(*encryptedbackupstorage.KeyringFile, []byte(keyring), 0600); err != nil {
+		t.Fatal(err)
+	}
+	bs := &filebackupstorage.FileBackupStorage{}
+	ctx := context.Background()
+
+	data := strings.Repeat("some backup data ", 1000)
+	bh, err := startBackup(ctx, bs, "ks/0", "backup")
+	if err != nil {
+		t.Fatalf("startBackup failed: %v", err)
+	}
+	hash, _, err := backupStream(ctx, logutil.NewMemoryLogger(), bh, "0", int64(len(data)), strings.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf("backupStream failed: %v", err)
+	}
+	if err := writeManifest(ctx, bh, &BackupManifest{}); err != nil {
+		t.Fatalf("writeManifest failed: %v", err)
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		t.Fatalf("EndBackup failed: %v", err)
+	}
+
+	// The MANIFEST is readable, and has the key.
+	bhs, err := bs.ListBackups(ctx, "ks/0")
+	if err != nil || len(bhs) != 1 {
+		t.Fatalf("ListBackups: %v, %v", bhs, err)
+	}
+	bm, err := readManifest(ctx, bhs[0])
+	if err != nil {
+		t.Fatalf("readManifest failed: %v", err)
+	}
+	if bm.Encryption == nil || bm.Encryption.KeyManager != "keyring" || bm.Encryption.KeyID != "key1" {
+		t.Fatalf("unexpected Encryption in MANIFEST: %v", bm.Encryption)
+	}
+
+	// The data can only be read through openBackup.
+	err = restoreStream(ctx, bhs[0], "0", "stream", hash, "", "", *backupStorageCompress, &bytes.Buffer{}, nil)
+	if err == nil {
+		t.Errorf("restoreStream of encrypted data worked")
+	}
+	rbh, err := openBackup(ctx, bhs[0], bm)
+	if err != nil {
+		t.Fatalf("openBackup failed: %v", err)
+	}
+	got := &bytes.Buffer{}
+	if err := restoreStream(ctx, rbh, "0", "stream", hash, "", "", *backupStorageCompress, got, nil); err != nil {
+		t.Fatalf("restoreStream failed: %v", err)
+	}
+	if got.String() != data {
+		t.Errorf("restoreStream returned %v bytes, want %v", got.Len(), len(data))
+	}
+}
+
+// flakyBackupHandle fails the uploads of some files, and records the
+// other ones.
+type flakyBackupHandle struct {
+	backupstorage.BackupHandle
+
+	mu       sync.Mutex
+	failures map[string]int
+	added    []string
+}
+
+func (fbh *flakyBackupHandle) AddFile(ctx context.Context, filename string, filesize int64) (io.WriteCloser, error) {
+	fbh.mu.Lock()
+	defer fbh.mu.Unlock()
+	if fbh.failures[filename] > 0 {
+		fbh.failures[filename]--
+		return failingWriter{}, nil
+	}
+	fbh.added = append(fbh.added, filename)
+	return fbh.BackupHandle.AddFile(ctx, filename, filesize)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func (failingWriter) Close() error {
+	return nil
+}
+
+func TestBackupFileChunks(t *testing.T) {
+	root, err := ioutil.TempDir("", "backuptest")
+	if err != nil {
+		t.Fatalf("os.TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(root)
+	*filebackupstorage.FileBackupStorageRoot = path.Join(root, "backups")
+	bs := &filebackupstorage.FileBackupStorage{}
+	ctx := context.Background()
+	logger := logutil.NewMemoryLogger()
+
+	*backupChunkSize = 3000
+	*backupUploadRetries = 1
+	defer func() {
+		*backupChunkSize = 0
+		*backupUploadRetries = 2
+	}()
+
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i * i)
+	}
+	if err := os.MkdirAll(path.Join(root, "data", "vt_db"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(root, "data", "vt_db", "t1.ibd"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	mysqld := &Mysqld{config: &Mycnf{DataDir: path.Join(root, "data")}}
+
+	// A failed chunk is retried.
+	bh, err := bs.StartBackup(ctx, "ks/0", "backup")
+	if err != nil {
+		t.Fatalf("StartBackup failed: %v", err)
+	}
+	fbh := &flakyBackupHandle{
+		BackupHandle: bh,
+		failures:     map[string]int{"0-1": 1},
+	}
+	fe := FileEntry{Base: backupData, Name: "vt_db/t1.ibd"}
+	if err := backupFile(ctx, mysqld, logger, fbh, &fe, nil, "0", nil, nil); err != nil {
+		t.Fatalf("backupFile failed: %v", err)
+	}
+	if fe.ChunkSize != 3000 || len(fe.Chunks) != 4 || fe.Hash != "" {
+		t.Fatalf("unexpected FileEntry: %+v", fe)
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		t.Fatalf("EndBackup failed: %v", err)
+	}
+
+	// The chunks are restored in order, and checked.
+	bhs, err := bs.ListBackups(ctx, "ks/0")
+	if err != nil || len(bhs) != 1 {
+		t.Fatalf("ListBackups: %v, %v", bhs, err)
+	}
+	cnf := &Mycnf{DataDir: path.Join(root, "restored")}
+	if err := restoreFile(ctx, cnf, bhs[0], &fe, "", *backupStorageCompress, "0", nil); err != nil {
+		t.Fatalf("restoreFile failed: %v", err)
+	}
+	got, err := ioutil.ReadFile(path.Join(root, "restored", "vt_db", "t1.ibd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("restored file has %v bytes, not the original data", len(got))
+	}
+	fe.Chunks[2].RawHash = "bad"
+	err = restoreFile(ctx, cnf, bhs[0], &fe, "", *backupStorageCompress, "0", nil)
+	if err == nil || !strings.HasPrefix(err.Error(), "restored data hash mismatch for vt_db/t1.ibd (chunk 2)") {
+		t.Errorf("restoreFile with a bad hash: %v, want restored data hash mismatch", err)
+	}
+
+	// The backup fails once the retries are exhausted.
+	bh, err = bs.StartBackup(ctx, "ks/0", "backup2")
+	if err != nil {
+		t.Fatalf("StartBackup failed: %v", err)
+	}
+	fbh = &flakyBackupHandle{
+		BackupHandle: bh,
+		failures:     map[string]int{"0-3": 2},
+	}
+	fe = FileEntry{Base: backupData, Name: "vt_db/t1.ibd"}
+	if err := backupFile(ctx, mysqld, logger, fbh, &fe, nil, "0", nil, nil); err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Errorf("backupFile with failed uploads: %v, want connection reset", err)
+	}
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+// This file handles the verification of backups: a backup is restored
+// into a temporary mysqld, its tables are checked and compared with the
+// row counts and checksums recorded when it was taken, and the result is
+// recorded in its MANIFEST.
+
+// systemDatabases are the databases whose tables are not recorded in
+// the MANIFEST of a backup. _vt is changed by the restore itself.
+var systemDatabases = map[string]bool{
+	"information_schema": true,
+	"mysql":              true,
+	"performance_schema": true,
+	"sys":                true,
+	"_vt":                true,
+}
+
+// BackupVerification is the result of a backup verification.
+type BackupVerification struct {
+	// Time is when the verification finished.
+	Time time.Time
+
+	// Error is empty if the backup was restored, all its
+	// tables were checked, and they match the row counts and
+	// checksums recorded in the MANIFEST, if any.
+	Error string
+
+	// Tables has the row count and checksum of the tables of
+	// the database, if the restore worked.
+	Tables []TableVerification
+}
+
+// TableVerification is the result of the checks on one table.
+type TableVerification struct {
+	Name     string
+	Rows     uint64
+	Checksum string
+}
+
+// findBackup returns the backup called name in dir, and its MANIFEST.
+func findBackup(ctx context.Context, bs backupstorage.BackupStorage, dir, name string) (backupstorage.BackupHandle, *BackupManifest, error) {
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ListBackups failed: %v", err)
+	}
+	for _, bh := range bhs {
+		if bh.Name() != name {
+			continue
+		}
+		bm, err := readManifest(ctx, bh)
+		if err != nil {
+			return nil, nil, fmt.Errorf("backup %v is not usable: %v", name, err)
+		}
+		return bh, bm, nil
+	}
+	return nil, nil, fmt.Errorf("backup %v not found in %v", name, dir)
+}
+
+// tempMysqlDaemon is a MysqlDaemon that is created for a single
+// operation, and removed when done.
+type tempMysqlDaemon interface {
+	MysqlDaemon
+	InitConfig() error
+	Teardown(ctx context.Context, force bool) error
+}
+
+// VerifyBackup restores the backup called name into mysqld, which must
+// be a temporary mysqld with no data, and checks the tables of dbName
+// can be read and have the row counts and checksums they had when the
+// backup was taken. Backups that did not record them (see
+// -backup_checksum_tables) are only checked for readability. The result
+// is recorded in the MANIFEST of the backup, and returned. The data of
+// mysqld is removed when done.
+func VerifyBackup(ctx context.Context, mysqld *Mysqld, logger logutil.Logger, dir, name, dbName string, restoreConcurrency int) (*BackupVerification, error) {
+	return verifyBackup(ctx, mysqld, logger, dir, name, dbName, restoreConcurrency)
+}
+
+func verifyBackup(ctx context.Context, mysqld tempMysqlDaemon, logger logutil.Logger, dir, name, dbName string, restoreConcurrency int) (*BackupVerification, error) {
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	bh, bm, err := findBackup(ctx, bs, dir, name)
+	if err != nil {
+		return nil, err
+	}
+	re, err := getRestoreEngine(bm)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("VerifyBackup: initializing temporary mysqld in %v", mysqld.TabletDir())
+	if err := mysqld.InitConfig(); err != nil {
+		return nil, fmt.Errorf("can't initialize temporary mysqld: %v", err)
+	}
+	defer func() {
+		logger.Infof("VerifyBackup: removing temporary mysqld")
+		if err := mysqld.Teardown(context.Background(), true); err != nil {
+			logger.Warningf("failed to remove temporary mysqld: %v", err)
+		}
+	}()
+
+	var tables []TableVerification
+	verifyErr := restoreBackup(ctx, mysqld, logger, bh, bm, re, restoreConcurrency, nil, map[string]string{})
+	if verifyErr == nil {
+		logger.Infof("VerifyBackup: checking tables of %v", dbName)
+		tables, verifyErr = checkTables(ctx, mysqld, dbName)
+	}
+	if verifyErr == nil && bm.Tables != nil {
+		verifyErr = compareTables(bm.Tables[dbName], tables)
+	}
+	v := &BackupVerification{
+		Time:   time.Now().UTC(),
+		Tables: tables,
+	}
+	if verifyErr != nil {
+		v.Error = verifyErr.Error()
+	}
+
+	bm.Verification = v
+	data, err := json.MarshalIndent(bm, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("cannot JSON encode %v: %v", backupManifest, err)
+	}
+	if err := bh.ReplaceFile(ctx, backupManifest, data); err != nil {
+		return nil, fmt.Errorf("cannot update %v: %v", backupManifest, err)
+	}
+	return v, nil
+}
+
+// checkTables returns the row count and checksum of all the tables of
+// dbName. It fails if any table can't be read, or if there is none.
+func checkTables(ctx context.Context, mysqld MysqlDaemon, dbName string) ([]TableVerification, error) {
+	result, err := checksumTables(ctx, mysqld, dbName)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, errors.New("no table found")
+	}
+	return result, nil
+}
+
+// checksumDatabases returns the row count and checksum of all the
+// tables of the databases of mysqld, except the system databases.
+// It is used when taking a backup, for VerifyBackup to compare
+// the restored tables with.
+func checksumDatabases(ctx context.Context, mysqld MysqlDaemon) (map[string][]TableVerification, error) {
+	qr, err := mysqld.FetchSuperQuery(ctx, "SHOW DATABASES")
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]TableVerification)
+	for _, row := range qr.Rows {
+		dbName := row[0].ToString()
+		if systemDatabases[dbName] {
+			continue
+		}
+		tables, err := checksumTables(ctx, mysqld, dbName)
+		if err != nil {
+			return nil, err
+		}
+		result[dbName] = tables
+	}
+	return result, nil
+}
+
+// checksumTables returns the row count and checksum of all the tables
+// of dbName.
+func checksumTables(ctx context.Context, mysqld MysqlDaemon, dbName string) ([]TableVerification, error) {
+	backtickDBName := sqlescape.EscapeID(dbName)
+	qr, err := mysqld.FetchSuperQuery(ctx, "SHOW FULL TABLES FROM "+backtickDBName+" WHERE Table_type = 'BASE TABLE'")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []TableVerification
+	for _, row := range qr.Rows {
+		table := row[0].ToString()
+		fullName := backtickDBName + "." + sqlescape.EscapeID(table)
+
+		qr, err := mysqld.FetchSuperQuery(ctx, "CHECKSUM TABLE "+fullName)
+		if err != nil {
+			return nil, err
+		}
+		if len(qr.Rows) != 1 || qr.Rows[0][1].IsNull() {
+			return nil, fmt.Errorf("CHECKSUM TABLE %v failed", table)
+		}
+		checksum := qr.Rows[0][1].ToString()
+
+		qr, err = mysqld.FetchSuperQuery(ctx, "SELECT COUNT(*) FROM "+fullName)
+		if err != nil {
+			return nil, err
+		}
+		if len(qr.Rows) != 1 {
+			return nil, fmt.Errorf("unexpected result for row count of %v: %v", table, qr.Rows)
+		}
+		rows, err := sqltypes.ToUint64(qr.Rows[0][0])
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, TableVerification{
+			Name:     table,
+			Rows:     rows,
+			Checksum: checksum,
+		})
+	}
+	return result, nil
+}
+
+// compareTables checks the restored tables have the row counts and
+// checksums that were recorded when the backup was taken.
+func compareTables(recorded, restored []TableVerification) error {
+	restoredByName := make(map[string]TableVerification, len(restored))
+	for _, tv := range restored {
+		restoredByName[tv.Name] = tv
+	}
+	for _, want := range recorded {
+		got, ok := restoredByName[want.Name]
+		if !ok {
+			return fmt.Errorf("table %v is missing from the restored data", want.Name)
+		}
+		delete(restoredByName, want.Name)
+		if got.Rows != want.Rows {
+			return fmt.Errorf("table %v has %v rows, it had %v when the backup was taken", want.Name, got.Rows, want.Rows)
+		}
+		if got.Checksum != want.Checksum {
+			return fmt.Errorf("table %v has checksum %v, it had %v when the backup was taken", want.Name, got.Checksum, want.Checksum)
+		}
+	}
+	for name := range restoredByName {
+		return fmt.Errorf("table %v was not there when the backup was taken", name)
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql/fakesqldb"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/dbconnpool"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+// verifyTestDaemon is a temporary mysqld that only has the files of its
+// Mycnf, and runs the queries of checkTables from a map.
+type verifyTestDaemon struct {
+	MysqlDaemon
+
+	cnf     *Mycnf
+	db      *fakesqldb.DB
+	queries map[string]*sqltypes.Result
+
+	running  bool
+	tornDown bool
+}
+
+func (d *verifyTestDaemon) InitConfig() error {
+	return os.MkdirAll(d.cnf.DataDir, os.ModePerm)
+}
+
+func (d *verifyTestDaemon) Teardown(ctx context.Context, force bool) error {
+	// The files are left for the test to check.
+	d.tornDown = true
+	return nil
+}
+
+func (d *verifyTestDaemon) Cnf() *Mycnf {
+	return d.cnf
+}
+
+func (d *verifyTestDaemon) TabletDir() string {
+	return path.Dir(d.cnf.DataDir)
+}
+
+func (d *verifyTestDaemon) Shutdown(ctx context.Context, waitForMysqld bool) error {
+	d.running = false
+	return nil
+}
+
+func (d *verifyTestDaemon) ReinitConfig(ctx context.Context) error {
+	return nil
+}
+
+func (d *verifyTestDaemon) Start(ctx context.Context, mysqldArgs ...string) error {
+	d.running = true
+	return nil
+}
+
+func (d *verifyTestDaemon) RunMysqlUpgrade() error {
+	return nil
+}
+
+func (d *verifyTestDaemon) GetDbaConnection() (*dbconnpool.DBConnection, error) {
+	return dbconnpool.NewDBConnection(d.db.ConnParams(), stats.NewTimings("", "", ""))
+}
+
+func (d *verifyTestDaemon) FetchSuperQuery(ctx context.Context, query string) (*sqltypes.Result, error) {
+	if !d.running {
+		return nil, errors.New("mysqld is not running")
+	}
+	qr, ok := d.queries[query]
+	if !ok {
+		return nil, fmt.Errorf("unexpected query: %v", query)
+	}
+	return qr, nil
+}
+
+// newVerifyTestBackup creates the backup ks/0/backup in the file backup
+// storage under root, with the single file vt_db/t1.ibd. corrupt
+// changes the hash of the restored data in the MANIFEST, tables is
+// recorded in the MANIFEST.
+func newVerifyTestBackup(t *testing.T, root string, corrupt bool, tables map[string][]TableVerification) []byte {
+	t.Helper()
+	data := []byte("some table data")
+	if err := os.MkdirAll(path.Join(root, "data", "vt_db"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(root, "data", "vt_db", "t1.ibd"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	bs := &filebackupstorage.FileBackupStorage{}
+	bh, err := bs.StartBackup(ctx, "ks/0", "backup")
+	if err != nil {
+		t.Fatalf("StartBackup failed: %v", err)
+	}
+	mysqld := &Mysqld{config: &Mycnf{DataDir: path.Join(root, "data")}}
+	fe := FileEntry{Base: backupData, Name: "vt_db/t1.ibd"}
+	if err := backupFile(ctx, mysqld, logutil.NewMemoryLogger(), bh, &fe, nil, "0", nil, nil); err != nil {
+		t.Fatalf("backupFile failed: %v", err)
+	}
+	if corrupt {
+		fe.RawHash = "corrupt"
+	}
+	bm := &BackupManifest{
+		BackupMethod: builtin,
+		FileEntries:  []FileEntry{fe},
+		SkipCompress: !*backupStorageCompress,
+		Tables:       tables,
+	}
+	if err := writeManifest(ctx, bh, bm); err != nil {
+		t.Fatalf("writeManifest failed: %v", err)
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		t.Fatalf("EndBackup failed: %v", err)
+	}
+	return data
+}
+
+func TestVerifyBackup(t *testing.T) {
+	checksumQuery := "CHECKSUM TABLE `vt_db`.`t1`"
+	testcases := []struct {
+		desc     string
+		corrupt  bool
+		recorded map[string][]TableVerification
+		checksum *sqltypes.Result
+		want     *BackupVerification
+	}{{
+		desc:     "verified",
+		checksum: sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Checksum", "varchar|int64"), "vt_db.t1|1234"),
+		want: &BackupVerification{
+			Tables: []TableVerification{{Name: "t1", Rows: 3, Checksum: "1234"}},
+		},
+	}, {
+		desc:     "recorded tables match",
+		recorded: map[string][]TableVerification{"vt_db": {{Name: "t1", Rows: 3, Checksum: "1234"}}},
+		checksum: sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Checksum", "varchar|int64"), "vt_db.t1|1234"),
+		want: &BackupVerification{
+			Tables: []TableVerification{{Name: "t1", Rows: 3, Checksum: "1234"}},
+		},
+	}, {
+		desc:     "row count mismatch",
+		recorded: map[string][]TableVerification{"vt_db": {{Name: "t1", Rows: 4, Checksum: "1234"}}},
+		checksum: sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Checksum", "varchar|int64"), "vt_db.t1|1234"),
+		want: &BackupVerification{
+			Error:  "table t1 has 3 rows, it had 4 when the backup was taken",
+			Tables: []TableVerification{{Name: "t1", Rows: 3, Checksum: "1234"}},
+		},
+	}, {
+		desc:     "checksum mismatch",
+		recorded: map[string][]TableVerification{"vt_db": {{Name: "t1", Rows: 3, Checksum: "5678"}}},
+		checksum: sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Checksum", "varchar|int64"), "vt_db.t1|1234"),
+		want: &BackupVerification{
+			Error:  "table t1 has checksum 1234, it had 5678 when the backup was taken",
+			Tables: []TableVerification{{Name: "t1", Rows: 3, Checksum: "1234"}},
+		},
+	}, {
+		desc:     "table missing",
+		recorded: map[string][]TableVerification{"vt_db": {{Name: "t1", Rows: 3, Checksum: "1234"}, {Name: "t2", Rows: 1, Checksum: "1"}}},
+		checksum: sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Checksum", "varchar|int64"), "vt_db.t1|1234"),
+		want: &BackupVerification{
+			Error:  "table t2 is missing from the restored data",
+			Tables: []TableVerification{{Name: "t1", Rows: 3, Checksum: "1234"}},
+		},
+	}, {
+		desc:     "restored data mismatch",
+		corrupt:  true,
+		checksum: sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Checksum", "varchar|int64"), "vt_db.t1|1234"),
+		want: &BackupVerification{
+			Error: "restored data hash mismatch for vt_db/t1.ibd",
+		},
+	}, {
+		desc:     "checksum failed",
+		checksum: sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Checksum", "varchar|int64"), "vt_db.t1|null"),
+		want: &BackupVerification{
+			Error: "CHECKSUM TABLE t1 failed",
+		},
+	}}
+	for _, tcase := range testcases {
+		t.Run(tcase.desc, func(t *testing.T) {
+			root, err := ioutil.TempDir("", "verifytest")
+			if err != nil {
+				t.Fatalf("os.TempDir failed: %v", err)
+			}
+			defer os.RemoveAll(root)
+			*filebackupstorage.FileBackupStorageRoot = path.Join(root, "backups")
+			*backupstorage.BackupStorageImplementation = "file"
+			defer func() { *backupstorage.BackupStorageImplementation = "" }()
+			data := newVerifyTestBackup(t, root, tcase.corrupt, tcase.recorded)
+
+			db := fakesqldb.New(t)
+			defer db.Close()
+			for _, query := range []string{
+				"SET @@session.sql_log_bin = 0",
+				"CREATE DATABASE IF NOT EXISTS _vt",
+				sqlCreateLocalMetadataTable,
+				sqlCreateShardMetadataTable,
+				"BEGIN",
+				"COMMIT",
+			} {
+				db.AddQuery(query, &sqltypes.Result{})
+			}
+			tmp := path.Join(root, "tmp")
+			mysqld := &verifyTestDaemon{
+				cnf: &Mycnf{
+					DataDir:               path.Join(tmp, "data"),
+					InnodbDataHomeDir:     path.Join(tmp, "innodb", "data"),
+					InnodbLogGroupHomeDir: path.Join(tmp, "innodb", "logs"),
+					BinLogPath:            path.Join(tmp, "bin-logs", "bin"),
+					RelayLogPath:          path.Join(tmp, "relay-logs", "relay"),
+					RelayLogIndexPath:     path.Join(tmp, "relay-logs", "relay.index"),
+					RelayLogInfoPath:      path.Join(tmp, "relay-logs", "relay.info"),
+				},
+				db: db,
+				queries: map[string]*sqltypes.Result{
+					"SHOW FULL TABLES FROM `vt_db` WHERE Table_type = 'BASE TABLE'": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Tables_in_vt_db|Table_type", "varchar|varchar"), "t1|BASE TABLE"),
+					checksumQuery:                       tcase.checksum,
+					"SELECT COUNT(*) FROM `vt_db`.`t1`": sqltypes.MakeTestResult(sqltypes.MakeTestFields("count(*)", "int64"), "3"),
+				},
+			}
+			ctx := context.Background()
+			v, err := verifyBackup(ctx, mysqld, logutil.NewMemoryLogger(), "ks/0", "backup", "vt_db", 1)
+			if err != nil {
+				t.Fatalf("verifyBackup failed: %v", err)
+			}
+			if !mysqld.tornDown {
+				t.Errorf("temporary mysqld was not removed")
+			}
+			if v.Time.IsZero() {
+				t.Errorf("Time is not set")
+			}
+			if !strings.HasPrefix(v.Error, tcase.want.Error) || (tcase.want.Error == "") != (v.Error == "") {
+				t.Errorf("got error %q, want %q", v.Error, tcase.want.Error)
+			}
+			if !reflect.DeepEqual(v.Tables, tcase.want.Tables) {
+				t.Errorf("got tables %+v, want %+v", v.Tables, tcase.want.Tables)
+			}
+			if restored, _ := ioutil.ReadFile(path.Join(tmp, "data", "vt_db", "t1.ibd")); tcase.want.Error == "" && string(restored) != string(data) {
+				t.Errorf("got restored data %q, want %q", restored, data)
+			}
+
+			// The result is recorded in the MANIFEST.
+			bs := &filebackupstorage.FileBackupStorage{}
+			_, bm, err := findBackup(ctx, bs, "ks/0", "backup")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bm.Verification == nil || bm.Verification.Error != v.Error || !reflect.DeepEqual(bm.Verification.Tables, v.Tables) {
+				t.Errorf("got MANIFEST verification %+v, want %+v", bm.Verification, v)
+			}
+		})
+	}
+}
+
+func TestChecksumDatabases(t *testing.T) {
+	mysqld := &verifyTestDaemon{
+		running: true,
+		queries: map[string]*sqltypes.Result{
+			"SHOW DATABASES": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Database", "varchar"), "information_schema", "mysql", "_vt", "vt_db", "empty"),
+			"SHOW FULL TABLES FROM `vt_db` WHERE Table_type = 'BASE TABLE'": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Tables_in_vt_db|Table_type", "varchar|varchar"), "t1|BASE TABLE"),
+			"SHOW FULL TABLES FROM `empty` WHERE Table_type = 'BASE TABLE'": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Tables_in_empty|Table_type", "varchar|varchar")),
+			"CHECKSUM TABLE `vt_db`.`t1`":                                   sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Checksum", "varchar|int64"), "vt_db.t1|1234"),
+			"SELECT COUNT(*) FROM `vt_db`.`t1`":                             sqltypes.MakeTestResult(sqltypes.MakeTestFields("count(*)", "int64"), "3"),
+		},
+	}
+	got, err := checksumDatabases(context.Background(), mysqld)
+	if err != nil {
+		t.Fatalf("checksumDatabases failed: %v", err)
+	}
+	want := map[string][]TableVerification{
+		"vt_db": {{Name: "t1", Rows: 3, Checksum: "1234"}},
+		"empty": nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checksumDatabases returned %+v, want %+v", got, want)
+	}
+}
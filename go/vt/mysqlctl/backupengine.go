@@ -0,0 +1,78 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"flag"
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+var (
+	// backupEngineImplementation is the implementation to use
+	// for new backups. Restores use the implementation that took
+	// the backup, as recorded in its MANIFEST.
+	backupEngineImplementation = flag.String("backup_engine_implementation", builtin, "which implementation to use for taking backups (builtin or xtrabackup)")
+)
+
+// BackupEngine is the interface to the different ways of taking and
+// restoring a backup.
+type BackupEngine interface {
+	// ExecuteBackup takes a backup into bh, including its MANIFEST.
+	// It returns a boolean that indicates if the backup is usable,
+	// and an overall error.
+	ExecuteBackup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh backupstorage.BackupHandle, backupConcurrency int, hookExtraEnv map[string]string) (bool, error)
+
+	// ExecuteRestore restores the files of the backup bh, described
+	// by bm, into the directories of cnf. mysqld is not running, and
+	// the directories have been emptied.
+	ExecuteRestore(ctx context.Context, cnf *Mycnf, logger logutil.Logger, bh backupstorage.BackupHandle, bm *BackupManifest, restoreConcurrency int, hookExtraEnv map[string]string) error
+
+	// ShouldDrainForBackup returns true if the tablet must stop
+	// serving while the backup is taken.
+	ShouldDrainForBackup() bool
+}
+
+// BackupEngineMap contains the registered implementations for BackupEngine
+var BackupEngineMap = make(map[string]BackupEngine)
+
+// GetBackupEngine returns the BackupEngine to use for new backups.
+func GetBackupEngine() (BackupEngine, error) {
+	be, ok := BackupEngineMap[*backupEngineImplementation]
+	if !ok {
+		return nil, fmt.Errorf("unknown backup engine implementation %q", *backupEngineImplementation)
+	}
+	return be, nil
+}
+
+// getRestoreEngine returns the BackupEngine that took a backup.
+func getRestoreEngine(bm *BackupManifest) (BackupEngine, error) {
+	method := bm.BackupMethod
+	if method == "" {
+		// Backups from before there were engines.
+		method = builtin
+	}
+	be, ok := BackupEngineMap[method]
+	if !ok {
+		return nil, fmt.Errorf("unknown backup method %q in MANIFEST", method)
+	}
+	return be, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func TestGetRestoreEngine(t *testing.T) {
+	testcases := []struct {
+		method string
+		want   BackupEngine
+		err    string
+	}{{
+		method: "",
+		want:   BackupEngineMap[builtin],
+	}, {
+		method: "xtrabackup",
+		want:   BackupEngineMap[xtrabackup],
+	}, {
+		method: "unknown",
+		err:    `unknown backup method "unknown" in MANIFEST`,
+	}}
+	for _, tcase := range testcases {
+		got, err := getRestoreEngine(&BackupManifest{BackupMethod: tcase.method})
+		if tcase.err != "" {
+			if err == nil || err.Error() != tcase.err {
+				t.Errorf("getRestoreEngine(%q): %v, want %v", tcase.method, err, tcase.err)
+			}
+			continue
+		}
+		if err != nil || got != tcase.want {
+			t.Errorf("getRestoreEngine(%q): %v, %v, want %v", tcase.method, got, err, tcase.want)
+		}
+	}
+}
+
+func TestBackupStream(t *testing.T) {
+	root, err := ioutil.TempDir("", "backupstreamtest")
+	if err != nil {
+		t.Fatalf("os.TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(root)
+	*filebackupstorage.FileBackupStorageRoot = root
+	bs := &filebackupstorage.FileBackupStorage{}
+	ctx := context.Background()
+
+	data := strings.Repeat("some backup data ", 1000)
+	bh, err := bs.StartBackup(ctx, "ks/0", "backup")
+	if err != nil {
+		t.Fatalf("StartBackup failed: %v", err)
+	}
+	hash, _, err := backupStream(ctx, logutil.NewMemoryLogger(), bh, "0", int64(len(data)), strings.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf("backupStream failed: %v", err)
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		t.Fatalf("EndBackup failed: %v", err)
+	}
+
+	bhs, err := bs.ListBackups(ctx, "ks/0")
+	if err != nil || len(bhs) != 1 {
+		t.Fatalf("ListBackups: %v, %v", bhs, err)
+	}
+	got := &bytes.Buffer{}
+	if err := restoreStream(ctx, bhs[0], "0", "stream", hash, "", "", *backupStorageCompress, got, nil); err != nil {
+		t.Fatalf("restoreStream failed: %v", err)
+	}
+	if got.String() != data {
+		t.Errorf("restoreStream returned %v bytes, want %v", got.Len(), len(data))
+	}
+
+	err = restoreStream(ctx, bhs[0], "0", "stream", "bad", "", "", *backupStorageCompress, &bytes.Buffer{}, nil)
+	if err == nil || !strings.HasPrefix(err.Error(), "hash mismatch for stream") {
+		t.Errorf("restoreStream with a bad hash: %v, want hash mismatch", err)
+	}
+}
@@ -66,6 +66,12 @@ type BackupHandle interface {
 	// The context is valid for the duration of the reads, until the
 	// ReadCloser is closed.
 	ReadFile(ctx context.Context, filename string) (io.ReadCloser, error)
+
+	// ReplaceFile replaces the contents of a file of a finished
+	// backup, for instance to record more information in its
+	// MANIFEST. Implementations should replace the file atomically.
+	// Only works for read-only backups (created by ListBackups).
+	ReplaceFile(ctx context.Context, filename string, contents []byte) error
 }
 
 // BackupStorage is the interface to the storage system
@@ -85,16 +91,50 @@ type BackupStorage interface {
 	// function, and should not be stored by the implementation.
 	StartBackup(ctx context.Context, dir, name string) (BackupHandle, error)
 
+	// ResumeBackup reopens a backup that was started with
+	// StartBackup, and neither ended nor aborted, to add more files
+	// to it. Adding a file that already exists replaces it. The
+	// returned backup is read-write, like the ones returned by
+	// StartBackup.
+	ResumeBackup(ctx context.Context, dir, name string) (BackupHandle, error)
+
 	// RemoveBackup removes all the data associated with a backup.
 	// It will not appear in ListBackups after RemoveBackup succeeds.
 	RemoveBackup(ctx context.Context, dir, name string) error
 
+	// ListBinlogArchives returns all the binlog archives of the
+	// backups in a directory. A binlog archive contains binlog files
+	// closed by the master, and a MANIFEST with the range of GTIDs
+	// they contain. Binlog archives are never returned by
+	// ListBackups. The returned archives are read-only, and sorted
+	// like ListBackups.
+	ListBinlogArchives(ctx context.Context, dir string) ([]BackupHandle, error)
+
+	// StartBinlogArchive creates a new binlog archive with the given
+	// name for the backups in a directory. If an archive with the
+	// same name already exists, it's an error. The returned archive
+	// is read-write, like the backups returned by StartBackup.
+	StartBinlogArchive(ctx context.Context, dir, name string) (BackupHandle, error)
+
+	// RemoveBinlogArchive removes all the data associated with a
+	// binlog archive. It will not appear in ListBinlogArchives after
+	// RemoveBinlogArchive succeeds.
+	RemoveBinlogArchive(ctx context.Context, dir, name string) error
+
 	// Close frees resources associated with an active backup
 	// session, such as closing connections. Implementations of
 	// BackupStorage must support being reused after Close() is called.
 	Close() error
 }
 
+// BinlogArchiveDir returns the directory the implementations that store
+// binlog archives like backups use for the binlog archives of the backups
+// in dir. It is next to dir rather than in it, so the archives are not
+// listed as backups.
+func BinlogArchiveDir(dir string) string {
+	return dir + ".binlogs"
+}
+
 // BackupStorageMap contains the registered implementations for BackupStorage
 var BackupStorageMap = make(map[string]BackupStorage)
 
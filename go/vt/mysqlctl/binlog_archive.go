@@ -0,0 +1,392 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/dbconfigs"
+	vtenv "vitess.io/vitess/go/vt/env"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+// This file handles binlog archiving and point-in-time recovery.
+//
+// Closed binlog files are uploaded as incremental backups to the binlog
+// archives of the BackupStorage (see BackupStorage.StartBinlogArchive).
+// The MANIFEST of an incremental backup lists the binlog files and the
+// range of GTIDs they contain (FromPosition to Position). A
+// point-in-time restore restores a full backup, and then replays the
+// binlog files of the archives that follow it with mysqlbinlog, which
+// requires MySQL 5.6+ GTIDs.
+
+var (
+	// ErrNoBinlogsToArchive is returned by ArchiveBinlogs when all
+	// the closed binlog files are already in the BackupStorage.
+	ErrNoBinlogsToArchive = errors.New("no new binlogs to archive")
+)
+
+// binlogFile is a closed binlog file of mysqld.
+type binlogFile struct {
+	// name is the file name, relative to the binlog directory.
+	name string
+
+	// from is the set of GTIDs executed before the file,
+	// to is from plus the GTIDs in the file.
+	from, to mysql.Position
+}
+
+// positionContains returns true if pos contains all the GTIDs of other.
+// Unlike Position.AtLeast, an empty other is contained in any pos.
+func positionContains(pos, other mysql.Position) bool {
+	if other.IsZero() {
+		return true
+	}
+	return pos.AtLeast(other)
+}
+
+// binlogStartPosition returns the set of GTIDs executed before the given
+// binlog file, read from its Previous_gtids (MySQL) or Gtid_list
+// (MariaDB) event.
+func binlogStartPosition(ctx context.Context, mysqld MysqlDaemon, flavor, name string) (mysql.Position, error) {
+	qr, err := mysqld.FetchSuperQuery(ctx, fmt.Sprintf("SHOW BINLOG EVENTS IN '%v' LIMIT 2", name))
+	if err != nil {
+		return mysql.Position{}, err
+	}
+	for _, row := range qr.Rows {
+		if len(row) < 6 {
+			return mysql.Position{}, fmt.Errorf("unexpected result for SHOW BINLOG EVENTS: %v", qr.Rows)
+		}
+		switch row[2].ToString() {
+		case "Previous_gtids", "Gtid_list":
+			info := strings.Trim(strings.Replace(row[5].ToString(), "\n", "", -1), "[]")
+			if info == "" {
+				return mysql.Position{}, nil
+			}
+			return mysql.ParsePosition(flavor, info)
+		}
+	}
+	return mysql.Position{}, fmt.Errorf("binlog %v has no Previous_gtids or Gtid_list event, is GTID mode enabled?", name)
+}
+
+// listClosedBinlogs returns the binlog files mysqld is done writing to,
+// oldest first. The file being written to is the last one in SHOW
+// BINARY LOGS, and each closed file ends where the next one starts.
+func listClosedBinlogs(ctx context.Context, mysqld MysqlDaemon, flavor string) ([]binlogFile, error) {
+	qr, err := mysqld.FetchSuperQuery(ctx, "SHOW BINARY LOGS")
+	if err != nil {
+		return nil, err
+	}
+	if len(qr.Rows) < 2 {
+		return nil, nil
+	}
+
+	starts := make([]mysql.Position, len(qr.Rows))
+	for i, row := range qr.Rows {
+		starts[i], err = binlogStartPosition(ctx, mysqld, flavor, row[0].ToString())
+		if err != nil {
+			return nil, err
+		}
+	}
+	result := make([]binlogFile, 0, len(qr.Rows)-1)
+	for i := 0; i < len(qr.Rows)-1; i++ {
+		result = append(result, binlogFile{
+			name: qr.Rows[i][0].ToString(),
+			from: starts[i],
+			to:   starts[i+1],
+		})
+	}
+	return result, nil
+}
+
+// ArchiveBinlogs uploads the binlog files that mysqld closed since the
+// most recent binlog archive of the backups in dir, or since the most
+// recent backup if there is no archive yet, as a new binlog archive
+// called name. It returns ErrNoBackup if there is no backup to start
+// from, and ErrNoBinlogsToArchive if there is nothing new to archive.
+func ArchiveBinlogs(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, dir, name string, hookExtraEnv map[string]string) error {
+	pos, err := mysqld.MasterPosition()
+	if err != nil {
+		return fmt.Errorf("can't get master position: %v", err)
+	}
+	// Archives that can't be replayed would only take space.
+	if err := checkBinlogReplayPosition(pos); err != nil {
+		return fmt.Errorf("cannot archive binlogs: %v", err)
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return err
+	}
+	defer bs.Close()
+
+	// Find how far the most recent backup goes.
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("ListBackups failed: %v", err)
+	}
+	archived := lastManifest(ctx, bhs)
+	if archived == nil {
+		return ErrNoBackup
+	}
+	// The archives are kept contiguous, so the older backups can
+	// be brought up to any later point in time too.
+	abhs, err := bs.ListBinlogArchives(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("ListBinlogArchives failed: %v", err)
+	}
+	if last := lastManifest(ctx, abhs); last != nil {
+		archived = last
+	}
+
+	// And find the closed binlogs that go further.
+	files, err := listClosedBinlogs(ctx, mysqld, pos.GTIDSet.Flavor())
+	if err != nil {
+		return fmt.Errorf("can't list binlogs: %v", err)
+	}
+	var fes []FileEntry
+	var from, to mysql.Position
+	for _, f := range files {
+		if positionContains(archived.Position, f.to) {
+			continue
+		}
+		if fes == nil {
+			from = f.from
+			if !positionContains(archived.Position, from) {
+				logger.Warningf("binlogs between %v and %v are not available anymore, archived binlogs will have a gap", archived.Position, from)
+			}
+		}
+		fes = append(fes, FileEntry{
+			Base: backupBinlog,
+			Name: f.name,
+		})
+		to = f.to
+	}
+	if len(fes) == 0 {
+		return ErrNoBinlogsToArchive
+	}
+	logger.Infof("archiving %v binlog files from %v to %v", len(fes), from, to)
+
+	bh, err := bs.StartBinlogArchive(ctx, dir, name)
+	if err != nil {
+		return fmt.Errorf("StartBinlogArchive failed: %v", err)
+	}
+	if bh, err = encryptNewBackup(ctx, bh); err != nil {
+		return err
+	}
+	err = archiveBinlogFiles(ctx, mysqld, logger, bh, fes, from, to, hookExtraEnv)
+	if err != nil {
+		logger.Errorf("binlog archive is not usable, aborting it: %v", err)
+		if abortErr := bh.AbortBackup(ctx); abortErr != nil {
+			logger.Errorf("failed to abort binlog archive: %v", abortErr)
+		}
+		return err
+	}
+	return bh.EndBackup(ctx)
+}
+
+// lastManifest returns the MANIFEST of the most recent backup of bhs
+// that has a readable one, or nil.
+func lastManifest(ctx context.Context, bhs []backupstorage.BackupHandle) *BackupManifest {
+	for i := len(bhs) - 1; i >= 0; i-- {
+		if bm, err := readManifest(ctx, bhs[i]); err == nil {
+			return bm
+		}
+	}
+	return nil
+}
+
+// archiveBinlogFiles copies the binlog files to the incremental backup,
+// and writes its MANIFEST.
+func archiveBinlogFiles(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh backupstorage.BackupHandle, fes []FileEntry, from, to mysql.Position, hookExtraEnv map[string]string) error {
+	for i := range fes {
+		name := fmt.Sprintf("%v", i)
+		if err := backupFile(ctx, mysqld, logger, bh, &fes[i], nil, name, hookExtraEnv, nil); err != nil {
+			return err
+		}
+	}
+	return writeManifest(ctx, bh, &BackupManifest{
+		FileEntries:   fes,
+		Position:      to,
+		TransformHook: *backupStorageHook,
+		SkipCompress:  !*backupStorageCompress,
+		BackupTime:    time.Now().UTC(),
+		Incremental:   true,
+		FromPosition:  from,
+	})
+}
+
+// findIncrementalBackups returns the binlog archives among bhs that
+// bring a full backup taken at pos up to stopPos, or as far as they go
+// if stopPos is not set, in the order they need to be applied.
+func findIncrementalBackups(ctx context.Context, logger logutil.Logger, bhs []backupstorage.BackupHandle, pos, stopPos mysql.Position) ([]backupstorage.BackupHandle, []*BackupManifest, error) {
+	var result []backupstorage.BackupHandle
+	var manifests []*BackupManifest
+	for _, bh := range bhs {
+		if !stopPos.IsZero() && positionContains(pos, stopPos) {
+			break
+		}
+		bm, err := readManifest(ctx, bh)
+		if err != nil {
+			logger.Warningf("Restore: skipping possibly incomplete binlog archive %v: %v", bh.Name(), err)
+			continue
+		}
+		if !bm.Incremental || positionContains(pos, bm.Position) {
+			continue
+		}
+		if !positionContains(pos, bm.FromPosition) {
+			logger.Warningf("Restore: archived binlogs have a gap between %v and %v", pos, bm.FromPosition)
+			break
+		}
+		bh, err = openBackup(ctx, bh, bm)
+		if err != nil {
+			return nil, nil, err
+		}
+		result = append(result, bh)
+		manifests = append(manifests, bm)
+		pos = bm.Position
+	}
+	if !stopPos.IsZero() && !positionContains(pos, stopPos) {
+		return nil, nil, fmt.Errorf("archived binlogs only go up to %v, cannot restore to %v", pos, stopPos)
+	}
+	return result, manifests, nil
+}
+
+// applyIncrementalBackups downloads the binlog files of the incremental
+// backups, and replays the transactions after fromPos, the position of
+// the restored full backup, up to stopTime or stopPos.
+func applyIncrementalBackups(ctx context.Context, mysqld MysqlDaemon, bhs []backupstorage.BackupHandle, bms []*BackupManifest, fromPos mysql.Position, stopTime time.Time, stopPos mysql.Position, restoreConcurrency int, hookExtraEnv map[string]string) error {
+	if len(bhs) == 0 {
+		return nil
+	}
+	tmpDir, err := ioutil.TempDir(mysqld.Cnf().TmpDir, "restore_binlogs")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var files []string
+	for i, bh := range bhs {
+		// Each incremental backup goes into its own directory,
+		// so binlog files with the same name can't collide.
+		cnf := *mysqld.Cnf()
+		cnf.BinLogPath = path.Join(tmpDir, fmt.Sprintf("%v", i), "binlog")
+		if err := restoreFiles(ctx, &cnf, bh, bms[i].FileEntries, bms[i].TransformHook, !bms[i].SkipCompress, restoreConcurrency, hookExtraEnv); err != nil {
+			return err
+		}
+		for _, fe := range bms[i].FileEntries {
+			files = append(files, path.Join(path.Dir(cnf.BinLogPath), fe.Name))
+		}
+	}
+	return mysqld.ApplyBinlogFiles(ctx, files, fromPos, stopTime, stopPos)
+}
+
+// checkBinlogReplayPosition returns an error if the binlogs cannot be
+// replayed on top of a backup taken at pos. mysqlbinlog can only skip
+// the transactions of the backup, and stop at a position, with MySQL
+// 5.6+ GTIDs: the MariaDB one has no --exclude-gtids or --include-gtids.
+func checkBinlogReplayPosition(pos mysql.Position) error {
+	if pos.IsZero() {
+		return errors.New("point-in-time recovery requires GTIDs")
+	}
+	if _, ok := pos.GTIDSet.(mysql.Mysql56GTIDSet); !ok {
+		return fmt.Errorf("point-in-time recovery requires MySQL 5.6+ GTIDs, got %v", pos.GTIDSet.Flavor())
+	}
+	return nil
+}
+
+// binlogReplayArgs returns the mysqlbinlog arguments that replay the
+// transactions after fromPos, up to stopTime or stopPos if they are set.
+func binlogReplayArgs(fromPos mysql.Position, stopTime time.Time, stopPos mysql.Position) ([]string, error) {
+	if err := checkBinlogReplayPosition(fromPos); err != nil {
+		return nil, err
+	}
+	// The backup already contains the transactions in fromPos. They
+	// are skipped explicitly, instead of relying on gtid_executed.
+	args := []string{"--exclude-gtids=" + fromPos.GTIDSet.String()}
+	if !stopTime.IsZero() {
+		// mysqlbinlog reads the date in the local time zone.
+		args = append(args, "--stop-datetime="+stopTime.Local().Format("2006-01-02 15:04:05"))
+	}
+	if !stopPos.IsZero() {
+		if err := checkBinlogReplayPosition(stopPos); err != nil {
+			return nil, err
+		}
+		args = append(args, "--include-gtids="+stopPos.GTIDSet.String())
+	}
+	return args, nil
+}
+
+// ApplyBinlogFiles is part of the MysqlDaemon interface. It pipes the
+// output of mysqlbinlog into the mysql command line tool.
+func (mysqld *Mysqld) ApplyBinlogFiles(ctx context.Context, binlogFiles []string, fromPos mysql.Position, stopTime time.Time, stopPos mysql.Position) error {
+	args, err := binlogReplayArgs(fromPos, stopTime, stopPos)
+	if err != nil {
+		return err
+	}
+	dir, err := vtenv.VtMysqlRoot()
+	if err != nil {
+		return err
+	}
+	name, err := binaryPath(dir, "mysqlbinlog")
+	if err != nil {
+		return err
+	}
+	params, err := dbconfigs.WithCredentials(&mysqld.dbcfgs.Dba)
+	if err != nil {
+		return err
+	}
+
+	args = append(args, binlogFiles...)
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = []string{
+		"LD_LIBRARY_PATH=" + path.Join(dir, "lib/mysql"),
+	}
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("can't start mysqlbinlog: %v", err)
+	}
+
+	// If mysql fails, mysqlbinlog will fail writing to the pipe,
+	// so the mysql error is the interesting one.
+	scriptErr := mysqld.executeMysqlScript(&params, stdout)
+	waitErr := cmd.Wait()
+	if scriptErr != nil {
+		return fmt.Errorf("can't apply binlogs: %v", scriptErr)
+	}
+	if waitErr != nil {
+		return fmt.Errorf("mysqlbinlog failed: %v, output: %v", waitErr, stderr.String())
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func TestFindIncrementalBackups(t *testing.T) {
+	root, err := ioutil.TempDir("", "binlogarchivetest")
+	if err != nil {
+		t.Fatalf("os.TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(root)
+	*filebackupstorage.FileBackupStorageRoot = root
+	bs := &filebackupstorage.FileBackupStorage{}
+
+	ctx := context.Background()
+	pos := func(s string) mysql.Position {
+		return mysql.MustParsePosition("MySQL56", "00010203-0405-0607-0809-0a0b0c0d0e0f:"+s)
+	}
+	manifests := []struct {
+		name string
+		bm   *BackupManifest
+	}{{
+		name: "1-full",
+		bm:   &BackupManifest{Position: pos("1-10")},
+	}, {
+		name: "2-incremental",
+		bm:   &BackupManifest{Incremental: true, FromPosition: pos("1-5"), Position: pos("1-20")},
+	}, {
+		name: "3-incremental",
+		bm:   &BackupManifest{Incremental: true, FromPosition: pos("1-20"), Position: pos("1-30")},
+	}, {
+		name: "4-full",
+		bm:   &BackupManifest{Position: pos("1-35")},
+	}, {
+		name: "5-incremental",
+		bm:   &BackupManifest{Incremental: true, FromPosition: pos("1-40"), Position: pos("1-50")},
+	}}
+	for _, m := range manifests {
+		start := bs.StartBackup
+		if m.bm.Incremental {
+			start = bs.StartBinlogArchive
+		}
+		bh, err := start(ctx, "ks/0", m.name)
+		if err != nil {
+			t.Fatalf("StartBackup(%v) failed: %v", m.name, err)
+		}
+		if err := writeManifest(ctx, bh, m.bm); err != nil {
+			t.Fatalf("writeManifest failed: %v", err)
+		}
+		if err := bh.EndBackup(ctx); err != nil {
+			t.Fatalf("EndBackup failed: %v", err)
+		}
+	}
+	bhs, err := bs.ListBackups(ctx, "ks/0")
+	if err != nil || len(bhs) != 2 || bhs[0].Name() != "1-full" || bhs[1].Name() != "4-full" {
+		t.Fatalf("ListBackups returned %v, %v, want only the full backups", bhs, err)
+	}
+	abhs, err := bs.ListBinlogArchives(ctx, "ks/0")
+	if err != nil || len(abhs) != 3 {
+		t.Fatalf("ListBinlogArchives returned %v, %v, want the 3 incremental backups", abhs, err)
+	}
+
+	testcases := []struct {
+		stopPos mysql.Position
+		want    []string
+		err     string
+	}{{
+		// As far as they go: stops at the gap before 5-incremental.
+		want: []string{"2-incremental", "3-incremental"},
+	}, {
+		stopPos: pos("1-15"),
+		want:    []string{"2-incremental"},
+	}, {
+		stopPos: pos("1-25"),
+		want:    []string{"2-incremental", "3-incremental"},
+	}, {
+		stopPos: pos("1-45"),
+		err:     "archived binlogs only go up to 00010203-0405-0607-0809-0a0b0c0d0e0f:1-30, cannot restore to 00010203-0405-0607-0809-0a0b0c0d0e0f:1-45",
+	}}
+	for _, tcase := range testcases {
+		got, bms, err := findIncrementalBackups(ctx, logutil.NewMemoryLogger(), abhs, pos("1-10"), tcase.stopPos)
+		if tcase.err != "" {
+			if err == nil || err.Error() != tcase.err {
+				t.Errorf("findIncrementalBackups(%v): %v, want %v", tcase.stopPos, err, tcase.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("findIncrementalBackups(%v) failed: %v", tcase.stopPos, err)
+			continue
+		}
+		var names []string
+		for _, bh := range got {
+			names = append(names, bh.Name())
+		}
+		if len(names) != len(tcase.want) || len(bms) != len(tcase.want) {
+			t.Errorf("findIncrementalBackups(%v): %v, want %v", tcase.stopPos, names, tcase.want)
+			continue
+		}
+		for i := range names {
+			if names[i] != tcase.want[i] {
+				t.Errorf("findIncrementalBackups(%v): %v, want %v", tcase.stopPos, names, tcase.want)
+				break
+			}
+		}
+	}
+}
+
+func TestBinlogReplayArgs(t *testing.T) {
+	mysql56 := func(s string) mysql.Position {
+		return mysql.MustParsePosition("MySQL56", "00010203-0405-0607-0809-0a0b0c0d0e0f:"+s)
+	}
+	mariadb := mysql.MustParsePosition("MariaDB", "0-1-10")
+	stopTime := time.Date(2018, 6, 1, 14, 25, 0, 0, time.UTC)
+
+	testcases := []struct {
+		fromPos  mysql.Position
+		stopTime time.Time
+		stopPos  mysql.Position
+		want     []string
+		err      string
+	}{{
+		fromPos: mysql56("1-10"),
+		want:    []string{"--exclude-gtids=00010203-0405-0607-0809-0a0b0c0d0e0f:1-10"},
+	}, {
+		fromPos: mysql56("1-10"),
+		stopPos: mysql56("1-20"),
+		want: []string{
+			"--exclude-gtids=00010203-0405-0607-0809-0a0b0c0d0e0f:1-10",
+			"--include-gtids=00010203-0405-0607-0809-0a0b0c0d0e0f:1-20",
+		},
+	}, {
+		fromPos:  mysql56("1-10"),
+		stopTime: stopTime,
+		want: []string{
+			"--exclude-gtids=00010203-0405-0607-0809-0a0b0c0d0e0f:1-10",
+			"--stop-datetime=" + stopTime.Local().Format("2006-01-02 15:04:05"),
+		},
+	}, {
+		err: "requires GTIDs",
+	}, {
+		fromPos:  mariadb,
+		stopTime: stopTime,
+		err:      "requires MySQL 5.6+ GTIDs, got MariaDB",
+	}, {
+		fromPos: mysql56("1-10"),
+		stopPos: mariadb,
+		err:     "requires MySQL 5.6+ GTIDs, got MariaDB",
+	}}
+	for _, tcase := range testcases {
+		got, err := binlogReplayArgs(tcase.fromPos, tcase.stopTime, tcase.stopPos)
+		if tcase.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tcase.err) {
+				t.Errorf("binlogReplayArgs(%v, %v, %v): %v, want %v", tcase.fromPos, tcase.stopTime, tcase.stopPos, err, tcase.err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tcase.want) {
+			t.Errorf("binlogReplayArgs(%v, %v, %v): %v, %v, want %v", tcase.fromPos, tcase.stopTime, tcase.stopPos, got, err, tcase.want)
+		}
+	}
+}
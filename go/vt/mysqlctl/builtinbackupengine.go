@@ -0,0 +1,51 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+const builtin = "builtin"
+
+// BuiltinBackupEngine is the original BackupEngine: it shuts mysqld
+// down, and copies the data files one by one.
+type BuiltinBackupEngine struct{}
+
+// ExecuteBackup is part of the BackupEngine interface.
+func (be *BuiltinBackupEngine) ExecuteBackup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh backupstorage.BackupHandle, backupConcurrency int, hookExtraEnv map[string]string) (bool, error) {
+	return backup(ctx, mysqld, logger, bh, backupConcurrency, hookExtraEnv)
+}
+
+// ExecuteRestore is part of the BackupEngine interface.
+func (be *BuiltinBackupEngine) ExecuteRestore(ctx context.Context, cnf *Mycnf, logger logutil.Logger, bh backupstorage.BackupHandle, bm *BackupManifest, restoreConcurrency int, hookExtraEnv map[string]string) error {
+	logger.Infof("Restore: copying %v files", len(bm.FileEntries))
+	return restoreFiles(ctx, cnf, bh, bm.FileEntries, bm.TransformHook, !bm.SkipCompress, restoreConcurrency, hookExtraEnv)
+}
+
+// ShouldDrainForBackup is part of the BackupEngine interface. mysqld
+// is shut down during the backup.
+func (be *BuiltinBackupEngine) ShouldDrainForBackup() bool {
+	return true
+}
+
+func init() {
+	BackupEngineMap[builtin] = &BuiltinBackupEngine{}
+}
@@ -19,6 +19,7 @@ limitations under the License.
 package cephbackupstorage
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -129,6 +130,18 @@ func (bh *CephBackupHandle) ReadFile(ctx context.Context, filename string) (io.R
 	return bh.client.GetObject(bucket, object)
 }
 
+// ReplaceFile implements BackupHandle.
+func (bh *CephBackupHandle) ReplaceFile(ctx context.Context, filename string, contents []byte) error {
+	if !bh.readOnly {
+		return fmt.Errorf("ReplaceFile cannot be called on read-write backup")
+	}
+	// ceph bucket name
+	bucket := alterBucketName(bh.dir)
+	object := objName(bh.dir, bh.name, filename)
+	_, err := bh.client.PutObject(bucket, object, bytes.NewReader(contents), "application/octet-stream")
+	return err
+}
+
 // CephBackupStorage implements BackupStorage for Ceph Cloud Storage.
 type CephBackupStorage struct {
 	// client is the instance of the Ceph Cloud Storage Go client.
@@ -209,6 +222,40 @@ func (bs *CephBackupStorage) StartBackup(ctx context.Context, dir, name string)
 	}, nil
 }
 
+// ResumeBackup implements BackupStorage.
+// The files of a backup are objects under its prefix, and AddFile
+// overwrites them, so the backup only needs to have some objects.
+func (bs *CephBackupStorage) ResumeBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	c, err := bs.client()
+	if err != nil {
+		return nil, err
+	}
+	// ceph bucket name
+	bucket := alterBucketName(dir)
+
+	found := false
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	for object := range c.ListObjects(bucket, objName(dir, name, ""), true, doneCh) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		found = true
+		break
+	}
+	if !found {
+		return nil, fmt.Errorf("backup %v/%v does not exist", dir, name)
+	}
+
+	return &CephBackupHandle{
+		client:   c,
+		bs:       bs,
+		dir:      dir,
+		name:     name,
+		readOnly: false,
+	}, nil
+}
+
 // RemoveBackup implements BackupStorage.
 func (bs *CephBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	c, err := bs.client()
@@ -237,6 +284,23 @@ func (bs *CephBackupStorage) RemoveBackup(ctx context.Context, dir, name string)
 	return nil
 }
 
+// ListBinlogArchives implements BackupStorage.
+// The binlog archives are stored like backups, in
+// backupstorage.BinlogArchiveDir(dir).
+func (bs *CephBackupStorage) ListBinlogArchives(ctx context.Context, dir string) ([]backupstorage.BackupHandle, error) {
+	return bs.ListBackups(ctx, backupstorage.BinlogArchiveDir(dir))
+}
+
+// StartBinlogArchive implements BackupStorage.
+func (bs *CephBackupStorage) StartBinlogArchive(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return bs.StartBackup(ctx, backupstorage.BinlogArchiveDir(dir), name)
+}
+
+// RemoveBinlogArchive implements BackupStorage.
+func (bs *CephBackupStorage) RemoveBinlogArchive(ctx context.Context, dir, name string) error {
+	return bs.RemoveBackup(ctx, backupstorage.BinlogArchiveDir(dir), name)
+}
+
 // Close implements BackupStorage.
 func (bs *CephBackupStorage) Close() error {
 	bs.mu.Lock()
@@ -0,0 +1,214 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package encryptedbackupstorage wraps a backupstorage.BackupHandle so
+// the files of a backup are encrypted before they reach the
+// BackupStorage, whichever implementation it is.
+//
+// Each backup is encrypted with its own random data key, using AES-GCM
+// on chunks of the files. The data key is itself encrypted (wrapped) by a
+// KeyManager, which holds the master keys, and the wrapped key is
+// recorded in the MANIFEST of the backup, which is not encrypted.
+package encryptedbackupstorage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"io"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+var (
+	// KeyManagerImplementation is the KeyManager used to wrap the
+	// data keys of new backups. Backups are not encrypted if it is
+	// empty. Exported for test purposes.
+	KeyManagerImplementation = flag.String("backup_encryption_key_manager", "", "if set, backups are encrypted with a data key wrapped by this key manager (for instance 'keyring')")
+)
+
+// dataKeySize is the size of the data keys, for AES-256.
+const dataKeySize = 32
+
+// KeyManager wraps and unwraps the data keys of backups with master
+// keys it holds. It can be implemented with a local keyring file, or
+// with a KMS.
+type KeyManager interface {
+	// WrapKey encrypts dataKey with the current master key,
+	// and returns the ID of that master key.
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+
+	// UnwrapKey decrypts a data key wrapped by WrapKey with
+	// the master key keyID.
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// KeyManagerMap contains the registered implementations for KeyManager.
+var KeyManagerMap = make(map[string]KeyManager)
+
+// KeyInfo describes how the data key of a backup is wrapped. It is
+// recorded in the MANIFEST of the backup.
+type KeyInfo struct {
+	// KeyManager is the name of the KeyManager that wrapped the key.
+	KeyManager string
+
+	// KeyID is the ID of the master key that wrapped the key.
+	KeyID string
+
+	// WrappedKey is the wrapped data key.
+	WrappedKey []byte
+}
+
+// Enabled returns true if new backups should be encrypted.
+func Enabled() bool {
+	return *KeyManagerImplementation != ""
+}
+
+// EncryptedBackupHandle implements BackupHandle by encrypting and
+// decrypting the files of another BackupHandle.
+type EncryptedBackupHandle struct {
+	bh      backupstorage.BackupHandle
+	keyInfo *KeyInfo
+	dataKey []byte
+}
+
+// NewWriteHandle wraps a backup started with StartBackup, using a new
+// data key wrapped by the KeyManager selected with the command line flag.
+func NewWriteHandle(ctx context.Context, bh backupstorage.BackupHandle) (*EncryptedBackupHandle, error) {
+	km, ok := KeyManagerMap[*KeyManagerImplementation]
+	if !ok {
+		return nil, fmt.Errorf("no registered implementation of KeyManager %v", *KeyManagerImplementation)
+	}
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("cannot generate data key: %v", err)
+	}
+	keyID, wrapped, err := km.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot wrap data key: %v", err)
+	}
+	return &EncryptedBackupHandle{
+		bh: bh,
+		keyInfo: &KeyInfo{
+			KeyManager: *KeyManagerImplementation,
+			KeyID:      keyID,
+			WrappedKey: wrapped,
+		},
+		dataKey: dataKey,
+	}, nil
+}
+
+// NewReadHandle wraps a backup returned by ListBackups, or a backup
+// reopened with ResumeBackup, whose data key is described by keyInfo.
+func NewReadHandle(ctx context.Context, bh backupstorage.BackupHandle, keyInfo *KeyInfo) (*EncryptedBackupHandle, error) {
+	km, ok := KeyManagerMap[keyInfo.KeyManager]
+	if !ok {
+		return nil, fmt.Errorf("no registered implementation of KeyManager %v", keyInfo.KeyManager)
+	}
+	dataKey, err := km.UnwrapKey(ctx, keyInfo.KeyID, keyInfo.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unwrap data key of backup %v with key %v: %v", bh.Name(), keyInfo.KeyID, err)
+	}
+	if len(dataKey) != dataKeySize {
+		return nil, fmt.Errorf("invalid data key size for backup %v: %v", bh.Name(), len(dataKey))
+	}
+	return &EncryptedBackupHandle{
+		bh:      bh,
+		keyInfo: keyInfo,
+		dataKey: dataKey,
+	}, nil
+}
+
+// KeyInfo returns the description of the data key, to be recorded
+// in the MANIFEST.
+func (ebh *EncryptedBackupHandle) KeyInfo() *KeyInfo {
+	return ebh.keyInfo
+}
+
+// Unwrap returns the BackupHandle the files are stored in, to read
+// and write the files that are not encrypted.
+func (ebh *EncryptedBackupHandle) Unwrap() backupstorage.BackupHandle {
+	return ebh.bh
+}
+
+// Directory is part of the BackupHandle interface
+func (ebh *EncryptedBackupHandle) Directory() string {
+	return ebh.bh.Directory()
+}
+
+// Name is part of the BackupHandle interface
+func (ebh *EncryptedBackupHandle) Name() string {
+	return ebh.bh.Name()
+}
+
+// AddFile is part of the BackupHandle interface
+func (ebh *EncryptedBackupHandle) AddFile(ctx context.Context, filename string, filesize int64) (io.WriteCloser, error) {
+	wc, err := ebh.bh.AddFile(ctx, filename, encryptedSize(filesize))
+	if err != nil {
+		return nil, err
+	}
+	return newEncryptWriter(wc, ebh.dataKey, filename)
+}
+
+// EndBackup is part of the BackupHandle interface
+func (ebh *EncryptedBackupHandle) EndBackup(ctx context.Context) error {
+	return ebh.bh.EndBackup(ctx)
+}
+
+// AbortBackup is part of the BackupHandle interface
+func (ebh *EncryptedBackupHandle) AbortBackup(ctx context.Context) error {
+	return ebh.bh.AbortBackup(ctx)
+}
+
+// ReadFile is part of the BackupHandle interface
+func (ebh *EncryptedBackupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	rc, err := ebh.bh.ReadFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	return newDecryptReader(rc, ebh.dataKey, filename)
+}
+
+// ReplaceFile is part of the BackupHandle interface
+func (ebh *EncryptedBackupHandle) ReplaceFile(ctx context.Context, filename string, contents []byte) error {
+	buf := &bytes.Buffer{}
+	wc, err := newEncryptWriter(nopWriteCloser{buf}, ebh.dataKey, filename)
+	if err != nil {
+		return err
+	}
+	if _, err := wc.Write(contents); err != nil {
+		return err
+	}
+	if err := wc.Close(); err != nil {
+		return err
+	}
+	return ebh.bh.ReplaceFile(ctx, filename, buf.Bytes())
+}
+
+// nopWriteCloser adds a no-op Close to a Writer.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// make sure EncryptedBackupHandle implements BackupHandle.
+var _ backupstorage.BackupHandle = (*EncryptedBackupHandle)(nil)
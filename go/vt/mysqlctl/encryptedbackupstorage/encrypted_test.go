@@ -0,0 +1,208 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptedbackupstorage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+// writeKeyring writes a keyring with the given key ids, and random keys.
+func writeKeyring(t *testing.T, ids ...string) {
+	buf := &bytes.Buffer{}
+	for _, id := range ids {
+		key := make([]byte, dataKeySize)
+		if _, err := rand.Read(key); err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(buf, "%v %v\n", id, base64.StdEncoding.EncodeToString(key))
+	}
+	if err := ioutil.WriteFile(*KeyringFile, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEncryptedBackupHandle(t *testing.T) {
+	root, err := ioutil.TempDir("", "encryptedbackupstoragetest")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(root)
+	*filebackupstorage.FileBackupStorageRoot = path.Join(root, "backups")
+	*KeyringFile = path.Join(root, "keyring")
+	*KeyManagerImplementation = "keyring"
+	defer func() { *KeyManagerImplementation = "" }()
+	writeKeyring(t, "key1")
+
+	ctx := context.Background()
+	fbs := &filebackupstorage.FileBackupStorage{}
+	bh, err := fbs.StartBackup(ctx, "ks/0", "backup")
+	if err != nil {
+		t.Fatalf("StartBackup failed: %v", err)
+	}
+	ebh, err := NewWriteHandle(ctx, bh)
+	if err != nil {
+		t.Fatalf("NewWriteHandle failed: %v", err)
+	}
+	if ki := ebh.KeyInfo(); ki.KeyManager != "keyring" || ki.KeyID != "key1" {
+		t.Errorf("unexpected KeyInfo: %v", ki)
+	}
+
+	// Write files around the chunk boundaries.
+	sizes := []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 3*chunkSize + 17}
+	contents := make(map[string][]byte)
+	for i, size := range sizes {
+		name := fmt.Sprintf("%v", i)
+		data := make([]byte, size)
+		rand.Read(data)
+		contents[name] = data
+
+		wc, err := ebh.AddFile(ctx, name, int64(size))
+		if err != nil {
+			t.Fatalf("AddFile failed: %v", err)
+		}
+		// Write in uneven pieces.
+		for p := data; len(p) > 0; {
+			n := 1000
+			if n > len(p) {
+				n = len(p)
+			}
+			if _, err := wc.Write(p[:n]); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			p = p[n:]
+		}
+		if err := wc.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		stored, err := ioutil.ReadFile(path.Join(root, "backups", "ks/0", "backup", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int64(len(stored)) != encryptedSize(int64(size)) {
+			t.Errorf("file %v has size %v, expected %v", name, len(stored), encryptedSize(int64(size)))
+		}
+		if size > 0 && bytes.Contains(stored, data) {
+			t.Errorf("file %v is stored in plaintext", name)
+		}
+	}
+	if err := ebh.EndBackup(ctx); err != nil {
+		t.Fatalf("EndBackup failed: %v", err)
+	}
+
+	// Rotate the key: the backup can still be read.
+	data, err := ioutil.ReadFile(*KeyringFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKeyring(t, "key2")
+	data2, err := ioutil.ReadFile(*KeyringFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*KeyringFile, append(data, data2...), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	bhs, err := fbs.ListBackups(ctx, "ks/0")
+	if err != nil || len(bhs) != 1 {
+		t.Fatalf("ListBackups failed: %v %v", bhs, err)
+	}
+	rbh, err := NewReadHandle(ctx, bhs[0], ebh.KeyInfo())
+	if err != nil {
+		t.Fatalf("NewReadHandle failed: %v", err)
+	}
+	readFile := func(name string) ([]byte, error) {
+		rc, err := rbh.ReadFile(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return ioutil.ReadAll(rc)
+	}
+	for name, want := range contents {
+		got, err := readFile(name)
+		if err != nil {
+			t.Errorf("reading %v failed: %v", name, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("file %v was not decrypted correctly", name)
+		}
+	}
+
+	// ReplaceFile encrypts too.
+	if err := rbh.ReplaceFile(ctx, "1", []byte("new contents")); err != nil {
+		t.Fatalf("ReplaceFile failed: %v", err)
+	}
+	if got, err := readFile("1"); err != nil || string(got) != "new contents" {
+		t.Errorf("reading replaced file got %q, %v", got, err)
+	}
+
+	// Tampering, truncation, and swapped files are detected.
+	filePath := func(name string) string {
+		return path.Join(root, "backups", "ks/0", "backup", name)
+	}
+	stored, err := ioutil.ReadFile(filePath("4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := append([]byte(nil), stored...)
+	tampered[headerSize+10] ^= 1
+	truncated := stored[:headerSize+sealedChunkSize]
+	for _, tc := range []struct {
+		desc     string
+		contents []byte
+	}{
+		{"tampered", tampered},
+		{"truncated", truncated},
+		{"empty", stored[:headerSize]},
+	} {
+		if err := ioutil.WriteFile(filePath("4"), tc.contents, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := readFile("4"); err == nil {
+			t.Errorf("reading %v file worked", tc.desc)
+		}
+	}
+	other, err := ioutil.ReadFile(filePath("3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filePath("4"), other, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readFile("4"); err == nil {
+		t.Errorf("reading swapped file worked")
+	}
+
+	// The backup can't be read without the key.
+	writeKeyring(t, "key2")
+	if _, err := NewReadHandle(ctx, bhs[0], ebh.KeyInfo()); err == nil {
+		t.Errorf("NewReadHandle worked without the key")
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptedbackupstorage
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+var (
+	// KeyringFile is the file the keyring KeyManager reads its keys
+	// from. Exported for test purposes.
+	KeyringFile = flag.String("backup_encryption_keyring", "", "file with the master keys for the 'keyring' backup encryption key manager: one '<key id> <base64 encoded 32 bytes key>' per line, the last key wraps the data keys of new backups")
+)
+
+// KeyringKeyManager implements KeyManager with master keys read from a
+// local file. The file is read for each backup, so keys can be rotated
+// by appending a new key: the previous keys are still needed to restore
+// older backups.
+type KeyringKeyManager struct{}
+
+// readKeyring parses the keyring file, and returns the keys by ID, and
+// the ID of the last key.
+func readKeyring() (map[string][]byte, string, error) {
+	if *KeyringFile == "" {
+		return nil, "", errors.New("-backup_encryption_keyring is not set")
+	}
+	data, err := ioutil.ReadFile(*KeyringFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot read keyring: %v", err)
+	}
+
+	keys := make(map[string][]byte)
+	lastID := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, "", fmt.Errorf("invalid keyring line %v: expected '<key id> <key>'", lineNumber)
+		}
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid keyring line %v: %v", lineNumber, err)
+		}
+		if len(key) != dataKeySize {
+			return nil, "", fmt.Errorf("invalid keyring line %v: key must be %v bytes, not %v", lineNumber, dataKeySize, len(key))
+		}
+		if _, ok := keys[fields[0]]; ok {
+			return nil, "", fmt.Errorf("invalid keyring line %v: duplicate key id %v", lineNumber, fields[0])
+		}
+		keys[fields[0]] = key
+		lastID = fields[0]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, "", err
+	}
+	if lastID == "" {
+		return nil, "", errors.New("keyring has no key")
+	}
+	return keys, lastID, nil
+}
+
+// WrapKey is part of the KeyManager interface. The wrapped key is a
+// nonce followed by the data key sealed with AES-GCM.
+func (KeyringKeyManager) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	keys, keyID, err := readKeyring()
+	if err != nil {
+		return "", nil, err
+	}
+	aead, err := newAEAD(keys[keyID])
+	if err != nil {
+		return "", nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	return keyID, aead.Seal(nonce, nonce, dataKey, []byte(keyID)), nil
+}
+
+// UnwrapKey is part of the KeyManager interface.
+func (KeyringKeyManager) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	keys, _, err := readKeyring()
+	if err != nil {
+		return nil, err
+	}
+	key, ok := keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("key %v is not in the keyring", keyID)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("wrapped key is too short")
+	}
+	return aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], []byte(keyID))
+}
+
+func init() {
+	KeyManagerMap["keyring"] = KeyringKeyManager{}
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptedbackupstorage
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Encrypted files are a header followed by chunks, each one sealed
+// separately with AES-GCM:
+//   - the header is a version byte and a random nonce prefix.
+//   - the nonce of a chunk is the prefix, the chunk number, and a byte
+//     set to 1 for the last chunk only. So chunks can't be reordered,
+//     and a truncated file is detected.
+//   - the name of the file is the additional data, so files can't be
+//     swapped within a backup.
+const (
+	streamVersion    = 1
+	noncePrefixSize  = 7
+	headerSize       = 1 + noncePrefixSize
+	chunkSize        = 64 * 1024
+	tagSize          = 16
+	sealedChunkSize  = chunkSize + tagSize
+	maxChunkSequence = 1<<32 - 1
+)
+
+// encryptedSize returns the size of the encrypted version of a file
+// of size bytes.
+func encryptedSize(size int64) int64 {
+	if size < 0 {
+		return size
+	}
+	// The last chunk may be full, but there is always one.
+	chunks := (size + chunkSize - 1) / chunkSize
+	if chunks == 0 {
+		chunks = 1
+	}
+	return headerSize + size + chunks*tagSize
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce returns the nonce of a chunk.
+func chunkNonce(prefix []byte, seq uint32, last bool) []byte {
+	nonce := make([]byte, noncePrefixSize+5)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], seq)
+	if last {
+		nonce[noncePrefixSize+4] = 1
+	}
+	return nonce
+}
+
+// encryptWriter encrypts what is written to it, and writes it to
+// the underlying WriteCloser.
+type encryptWriter struct {
+	wc     io.WriteCloser
+	aead   cipher.AEAD
+	prefix []byte
+	ad     []byte
+	seq    uint32
+	buf    []byte
+	sealed []byte
+}
+
+func newEncryptWriter(wc io.WriteCloser, key []byte, filename string) (io.WriteCloser, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, headerSize)
+	header[0] = streamVersion
+	if _, err := rand.Read(header[1:]); err != nil {
+		return nil, fmt.Errorf("cannot generate nonce: %v", err)
+	}
+	if _, err := wc.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{
+		wc:     wc,
+		aead:   aead,
+		prefix: header[1:],
+		ad:     []byte(filename),
+		buf:    make([]byte, 0, chunkSize),
+		sealed: make([]byte, 0, sealedChunkSize),
+	}, nil
+}
+
+// Write is part of the io.Writer interface. A full chunk is only
+// sealed when more data comes, since we don't know yet if it is the
+// last one.
+func (ew *encryptWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if len(ew.buf) == chunkSize {
+			if err := ew.flush(false); err != nil {
+				return written, err
+			}
+		}
+		n := copy(ew.buf[len(ew.buf):chunkSize], p)
+		ew.buf = ew.buf[:len(ew.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+func (ew *encryptWriter) flush(last bool) error {
+	if ew.seq == maxChunkSequence {
+		return errors.New("file too large to encrypt")
+	}
+	ew.sealed = ew.aead.Seal(ew.sealed[:0], chunkNonce(ew.prefix, ew.seq, last), ew.buf, ew.ad)
+	ew.seq++
+	ew.buf = ew.buf[:0]
+	_, err := ew.wc.Write(ew.sealed)
+	return err
+}
+
+// Close is part of the io.Closer interface. It seals the last chunk,
+// which may be empty.
+func (ew *encryptWriter) Close() error {
+	if err := ew.flush(true); err != nil {
+		ew.wc.Close()
+		return err
+	}
+	return ew.wc.Close()
+}
+
+// decryptReader decrypts what it reads from the underlying ReadCloser.
+type decryptReader struct {
+	rc     io.ReadCloser
+	br     *bufio.Reader
+	aead   cipher.AEAD
+	prefix []byte
+	ad     []byte
+	seq    uint32
+	sealed []byte
+	buf    []byte
+	done   bool
+}
+
+func newDecryptReader(rc io.ReadCloser, key []byte, filename string) (io.ReadCloser, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	br := bufio.NewReaderSize(rc, sealedChunkSize)
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(br, header); err != nil {
+		rc.Close()
+		return nil, fmt.Errorf("cannot read encryption header of %v: %v", filename, err)
+	}
+	if header[0] != streamVersion {
+		rc.Close()
+		return nil, fmt.Errorf("unsupported encryption version %v for %v", header[0], filename)
+	}
+	return &decryptReader{
+		rc:     rc,
+		br:     br,
+		aead:   aead,
+		prefix: header[1:],
+		ad:     []byte(filename),
+		sealed: make([]byte, sealedChunkSize),
+	}, nil
+}
+
+// Read is part of the io.Reader interface.
+func (dr *decryptReader) Read(p []byte) (int, error) {
+	for len(dr.buf) == 0 {
+		if dr.done {
+			return 0, io.EOF
+		}
+		if err := dr.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, dr.buf)
+	dr.buf = dr.buf[n:]
+	return n, nil
+}
+
+// next reads and decrypts the next chunk.
+func (dr *decryptReader) next() error {
+	n, err := io.ReadFull(dr.br, dr.sealed)
+	last := false
+	switch err {
+	case nil:
+		// A full chunk is the last one if nothing follows.
+		if _, err := dr.br.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	case io.ErrUnexpectedEOF:
+		last = true
+	case io.EOF:
+		return errors.New("encrypted file is truncated")
+	default:
+		return err
+	}
+
+	plain, err := dr.aead.Open(dr.sealed[:0], chunkNonce(dr.prefix, dr.seq, last), dr.sealed[:n], dr.ad)
+	if err != nil {
+		return fmt.Errorf("cannot decrypt chunk %v: %v", dr.seq, err)
+	}
+	dr.seq++
+	dr.buf = plain
+	dr.done = last
+	return nil
+}
+
+// Close is part of the io.Closer interface.
+func (dr *decryptReader) Close() error {
+	return dr.rc.Close()
+}
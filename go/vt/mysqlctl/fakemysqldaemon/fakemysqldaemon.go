@@ -130,6 +130,9 @@ type FakeMysqlDaemon struct {
 	// BinlogPlayerEnabled is used by {Enable,Disable}BinlogPlayer
 	BinlogPlayerEnabled bool
 
+	// AppliedBinlogFiles is appended to by ApplyBinlogFiles.
+	AppliedBinlogFiles []string
+
 	// SemiSyncMasterEnabled represents the state of rpl_semi_sync_master_enabled.
 	SemiSyncMasterEnabled bool
 	// SemiSyncSlaveEnabled represents the state of rpl_semi_sync_slave_enabled.
@@ -360,6 +363,12 @@ func (fmd *FakeMysqlDaemon) DisableBinlogPlayback() error {
 	return nil
 }
 
+// ApplyBinlogFiles is part of the MysqlDaemon interface
+func (fmd *FakeMysqlDaemon) ApplyBinlogFiles(ctx context.Context, binlogFiles []string, fromPos mysql.Position, stopTime time.Time, stopPos mysql.Position) error {
+	fmd.AppliedBinlogFiles = append(fmd.AppliedBinlogFiles, binlogFiles...)
+	return nil
+}
+
 // Close is part of the MysqlDaemon interface
 func (fmd *FakeMysqlDaemon) Close() {
 	if fmd.appPool != nil {
@@ -89,6 +89,19 @@ func (fbh *FileBackupHandle) ReadFile(ctx context.Context, filename string) (io.
 	return os.Open(p)
 }
 
+// ReplaceFile is part of the BackupHandle interface
+func (fbh *FileBackupHandle) ReplaceFile(ctx context.Context, filename string, contents []byte) error {
+	if !fbh.readOnly {
+		return fmt.Errorf("ReplaceFile cannot be called on read-write backup")
+	}
+	p := path.Join(*FileBackupStorageRoot, fbh.dir, fbh.name, filename)
+	tmp := p + ".tmp"
+	if err := ioutil.WriteFile(tmp, contents, 0666); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
 // FileBackupStorage implements BackupStorage for local file system.
 type FileBackupStorage struct{}
 
@@ -144,12 +157,44 @@ func (fbs *FileBackupStorage) StartBackup(ctx context.Context, dir, name string)
 	}, nil
 }
 
+// ResumeBackup is part of the BackupStorage interface
+func (fbs *FileBackupStorage) ResumeBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	p := path.Join(*FileBackupStorageRoot, dir, name)
+	if _, err := os.Stat(p); err != nil {
+		return nil, err
+	}
+
+	return &FileBackupHandle{
+		fbs:      fbs,
+		dir:      dir,
+		name:     name,
+		readOnly: false,
+	}, nil
+}
+
 // RemoveBackup is part of the BackupStorage interface
 func (fbs *FileBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	p := path.Join(*FileBackupStorageRoot, dir, name)
 	return os.RemoveAll(p)
 }
 
+// ListBinlogArchives is part of the BackupStorage interface
+// The binlog archives are stored like backups, in
+// backupstorage.BinlogArchiveDir(dir).
+func (fbs *FileBackupStorage) ListBinlogArchives(ctx context.Context, dir string) ([]backupstorage.BackupHandle, error) {
+	return fbs.ListBackups(ctx, backupstorage.BinlogArchiveDir(dir))
+}
+
+// StartBinlogArchive is part of the BackupStorage interface
+func (fbs *FileBackupStorage) StartBinlogArchive(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return fbs.StartBackup(ctx, backupstorage.BinlogArchiveDir(dir), name)
+}
+
+// RemoveBinlogArchive is part of the BackupStorage interface
+func (fbs *FileBackupStorage) RemoveBinlogArchive(ctx context.Context, dir, name string) error {
+	return fbs.RemoveBackup(ctx, backupstorage.BinlogArchiveDir(dir), name)
+}
+
 // Close implements BackupStorage.
 func (fbs *FileBackupStorage) Close() error {
 	return nil
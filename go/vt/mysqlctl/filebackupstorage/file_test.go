@@ -204,3 +204,42 @@ func TestFileContents(t *testing.T) {
 		t.Fatalf("rc.Close failed: %v", err)
 	}
 }
+
+func TestBinlogArchives(t *testing.T) {
+	fbs := setupFileBackupStorage(t)
+	defer cleanupFileBackupStorage(fbs)
+	ctx := context.Background()
+
+	dir := "keyspace/shard"
+	if _, err := fbs.StartBackup(ctx, dir, "backup1"); err != nil {
+		t.Fatalf("fbs.StartBackup failed: %v", err)
+	}
+	bh, err := fbs.StartBinlogArchive(ctx, dir, "archive1")
+	if err != nil {
+		t.Fatalf("fbs.StartBinlogArchive failed: %v", err)
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		t.Fatalf("bh.EndBackup failed: %v", err)
+	}
+
+	// The archives and the backups are listed separately.
+	bhs, err := fbs.ListBackups(ctx, dir)
+	if err != nil || len(bhs) != 1 || bhs[0].Name() != "backup1" {
+		t.Fatalf("ListBackups returned %v, %v, want backup1 only", bhs, err)
+	}
+	bhs, err = fbs.ListBinlogArchives(ctx, dir)
+	if err != nil || len(bhs) != 1 || bhs[0].Name() != "archive1" {
+		t.Fatalf("ListBinlogArchives returned %v, %v, want archive1 only", bhs, err)
+	}
+
+	if err := fbs.RemoveBinlogArchive(ctx, dir, "archive1"); err != nil {
+		t.Fatalf("RemoveBinlogArchive failed: %v", err)
+	}
+	bhs, err = fbs.ListBinlogArchives(ctx, dir)
+	if err != nil || len(bhs) != 0 {
+		t.Fatalf("ListBinlogArchives after RemoveBinlogArchive returned %v, %v, want nothing", bhs, err)
+	}
+	if bhs, err := fbs.ListBackups(ctx, dir); err != nil || len(bhs) != 1 {
+		t.Fatalf("ListBackups after RemoveBinlogArchive returned %v, %v, want backup1", bhs, err)
+	}
+}
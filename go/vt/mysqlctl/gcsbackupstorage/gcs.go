@@ -99,6 +99,21 @@ func (bh *GCSBackupHandle) ReadFile(ctx context.Context, filename string) (io.Re
 	return bh.client.Bucket(*bucket).Object(object).NewReader(ctx)
 }
 
+// ReplaceFile implements BackupHandle. The new object only becomes
+// visible when it is completely written.
+func (bh *GCSBackupHandle) ReplaceFile(ctx context.Context, filename string, contents []byte) error {
+	if !bh.readOnly {
+		return fmt.Errorf("ReplaceFile cannot be called on read-write backup")
+	}
+	object := objName(bh.dir, bh.name, filename)
+	w := bh.client.Bucket(*bucket).Object(object).NewWriter(ctx)
+	if _, err := w.Write(contents); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
 // GCSBackupStorage implements BackupStorage for Google Cloud Storage.
 type GCSBackupStorage struct {
 	// client is the instance of the Google Cloud Storage Go client.
@@ -173,6 +188,35 @@ func (bs *GCSBackupStorage) StartBackup(ctx context.Context, dir, name string) (
 	}, nil
 }
 
+// ResumeBackup implements BackupStorage.
+// The files of a backup are objects under its prefix, and AddFile
+// overwrites them, so the backup only needs to have some objects.
+func (bs *GCSBackupStorage) ResumeBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	c, err := bs.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &storage.Query{
+		Prefix: objName(dir, name, "" /* include trailing slash */),
+	}
+	it := c.Bucket(*bucket).Objects(ctx, query)
+	if _, err := it.Next(); err != nil {
+		if err == iterator.Done {
+			return nil, fmt.Errorf("backup %v/%v does not exist", dir, name)
+		}
+		return nil, err
+	}
+
+	return &GCSBackupHandle{
+		client:   c,
+		bs:       bs,
+		dir:      dir,
+		name:     name,
+		readOnly: false,
+	}, nil
+}
+
 // RemoveBackup implements BackupStorage.
 func (bs *GCSBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	c, err := bs.client(ctx)
@@ -201,6 +245,23 @@ func (bs *GCSBackupStorage) RemoveBackup(ctx context.Context, dir, name string)
 	return nil
 }
 
+// ListBinlogArchives implements BackupStorage.
+// The binlog archives are stored like backups, in
+// backupstorage.BinlogArchiveDir(dir).
+func (bs *GCSBackupStorage) ListBinlogArchives(ctx context.Context, dir string) ([]backupstorage.BackupHandle, error) {
+	return bs.ListBackups(ctx, backupstorage.BinlogArchiveDir(dir))
+}
+
+// StartBinlogArchive implements BackupStorage.
+func (bs *GCSBackupStorage) StartBinlogArchive(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return bs.StartBackup(ctx, backupstorage.BinlogArchiveDir(dir), name)
+}
+
+// RemoveBinlogArchive implements BackupStorage.
+func (bs *GCSBackupStorage) RemoveBinlogArchive(ctx context.Context, dir, name string) error {
+	return bs.RemoveBackup(ctx, backupstorage.BinlogArchiveDir(dir), name)
+}
+
 // Close implements BackupStorage.
 func (bs *GCSBackupStorage) Close() error {
 	bs.mu.Lock()
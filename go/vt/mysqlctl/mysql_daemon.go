@@ -17,6 +17,8 @@ limitations under the License.
 package mysqlctl
 
 import (
+	"time"
+
 	"golang.org/x/net/context"
 
 	"vitess.io/vitess/go/mysql"
@@ -95,6 +97,10 @@ type MysqlDaemon interface {
 	// DisableBinlogPlayback disable playback of binlog events
 	DisableBinlogPlayback() error
 
+	// ApplyBinlogFiles replays the transactions of binlog files that
+	// are not in fromPos, up to stopTime or stopPos if they are set.
+	ApplyBinlogFiles(ctx context.Context, binlogFiles []string, fromPos mysql.Position, stopTime time.Time, stopPos mysql.Position) error
+
 	// Close will close this instance of Mysqld. It will wait for all dba
 	// queries to be finished.
 	Close()
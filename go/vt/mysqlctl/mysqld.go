@@ -73,6 +73,11 @@ var (
 	// masterConnectRetry is used in 'SET MASTER' commands
 	masterConnectRetry = flag.Duration("master_connect_retry", 10*time.Second, "how long to wait in between slave -> connection attempts. Only precise to the second.")
 
+	// MasterDelay is used in 'SET MASTER' commands, to run a delayed
+	// replica. Such tablets are tagged in the topology, see
+	// topoproto.DelayedReplicaTag, and do not serve queries.
+	MasterDelay = flag.Duration("master_delay", 0, "if set, replication is delayed by this long (MASTER_DELAY), to keep a replica behind its master as a protection against bad writes. Only precise to the second.")
+
 	dbaMysqlStats      = stats.NewTimings("MysqlDba", "MySQL DBA stats", "operation")
 	allprivsMysqlStats = stats.NewTimings("MysqlAllPrivs", "MySQl Stats for all privs", "operation")
 	appMysqlStats      = stats.NewTimings("MysqlApp", "MySQL app stats", "operation")
@@ -44,6 +44,14 @@ const (
 	SQLStopSlave = "STOP SLAVE"
 )
 
+// SetMasterDelayCommand returns the command to delay replication by the
+// given duration, rounded down to the second. A zero delay makes the
+// replica catch up with its master. It has to be run with replication
+// stopped.
+func SetMasterDelayCommand(delay time.Duration) string {
+	return fmt.Sprintf("CHANGE MASTER TO MASTER_DELAY = %d", int64(delay.Seconds()))
+}
+
 // WaitForSlaveStart waits until the deadline for replication to start.
 // This validates the current master is correct and can be connected to.
 func WaitForSlaveStart(mysqld MysqlDaemon, slaveStartDeadline int) error {
@@ -227,6 +235,9 @@ func (mysqld *Mysqld) SetMaster(ctx context.Context, masterHost string, masterPo
 	}
 	smc := conn.SetMasterCommand(&params, masterHost, masterPort, int(masterConnectRetry.Seconds()))
 	cmds = append(cmds, smc)
+	if *MasterDelay > 0 {
+		cmds = append(cmds, SetMasterDelayCommand(*MasterDelay))
+	}
 	if slaveStartAfter {
 		cmds = append(cmds, SQLStartSlave)
 	}
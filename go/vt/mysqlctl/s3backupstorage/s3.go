@@ -24,6 +24,7 @@ limitations under the License.
 package s3backupstorage
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
 	"io"
@@ -159,6 +160,26 @@ func (bh *S3BackupHandle) ReadFile(ctx context.Context, filename string) (io.Rea
 	return out.Body, nil
 }
 
+// ReplaceFile is part of the backupstorage.BackupHandle interface.
+func (bh *S3BackupHandle) ReplaceFile(ctx context.Context, filename string, contents []byte) error {
+	if !bh.readOnly {
+		return fmt.Errorf("ReplaceFile cannot be called on read-write backup")
+	}
+	object := objName(bh.dir, bh.name, filename)
+
+	var sseOption *string
+	if *sse != "" {
+		sseOption = sse
+	}
+	_, err := bh.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:               bucket,
+		Key:                  object,
+		Body:                 bytes.NewReader(contents),
+		ServerSideEncryption: sseOption,
+	})
+	return err
+}
+
 var _ backupstorage.BackupHandle = (*S3BackupHandle)(nil)
 
 // S3BackupStorage implements the backupstorage.BackupStorage interface.
@@ -231,6 +252,36 @@ func (bs *S3BackupStorage) StartBackup(ctx context.Context, dir, name string) (b
 	}, nil
 }
 
+// ResumeBackup is part of the backupstorage.BackupStorage interface.
+// The files of a backup are objects under its prefix, and AddFile
+// overwrites them, so the backup only needs to have some objects.
+func (bs *S3BackupStorage) ResumeBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	c, err := bs.client()
+	if err != nil {
+		return nil, err
+	}
+
+	objs, err := c.ListObjectsV2WithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket:  bucket,
+		Prefix:  objName(dir, name, ""),
+		MaxKeys: aws.Int64(1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(objs.Contents) == 0 {
+		return nil, fmt.Errorf("backup %v/%v does not exist", dir, name)
+	}
+
+	return &S3BackupHandle{
+		client:   c,
+		bs:       bs,
+		dir:      dir,
+		name:     name,
+		readOnly: false,
+	}, nil
+}
+
 // RemoveBackup is part of the backupstorage.BackupStorage interface.
 func (bs *S3BackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	c, err := bs.client()
@@ -283,6 +334,23 @@ func (bs *S3BackupStorage) RemoveBackup(ctx context.Context, dir, name string) e
 	return nil
 }
 
+// ListBinlogArchives is part of the backupstorage.BackupStorage interface.
+// The binlog archives are stored like backups, in
+// backupstorage.BinlogArchiveDir(dir).
+func (bs *S3BackupStorage) ListBinlogArchives(ctx context.Context, dir string) ([]backupstorage.BackupHandle, error) {
+	return bs.ListBackups(ctx, backupstorage.BinlogArchiveDir(dir))
+}
+
+// StartBinlogArchive is part of the backupstorage.BackupStorage interface.
+func (bs *S3BackupStorage) StartBinlogArchive(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return bs.StartBackup(ctx, backupstorage.BinlogArchiveDir(dir), name)
+}
+
+// RemoveBinlogArchive is part of the backupstorage.BackupStorage interface.
+func (bs *S3BackupStorage) RemoveBinlogArchive(ctx context.Context, dir, name string) error {
+	return bs.RemoveBackup(ctx, backupstorage.BinlogArchiveDir(dir), name)
+}
+
 // Close is part of the backupstorage.BackupStorage interface.
 func (bs *S3BackupStorage) Close() error {
 	bs.mu.Lock()
@@ -0,0 +1,263 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+// This file implements a BackupEngine that uses Percona XtraBackup to
+// take hot backups, while mysqld keeps running and replicating. The
+// xbstream output of xtrabackup is stored as a single file in the
+// backup, and extracted and prepared at restore time.
+
+const (
+	xtrabackup = "xtrabackup"
+
+	// xtrabackupStream is the name of the FileEntry for the
+	// xbstream output.
+	xtrabackupStream = "xtrabackup.xbstream"
+)
+
+var (
+	xtrabackupRootPath     = flag.String("xtrabackup_root_path", "", "directory of the xtrabackup and xbstream binaries, if not in the PATH")
+	xtrabackupBackupFlags  = flag.String("xtrabackup_backup_flags", "", "space separated flags added to the xtrabackup backup command")
+	xtrabackupPrepareFlags = flag.String("xtrabackup_prepare_flags", "", "space separated flags added to the xtrabackup prepare command")
+	xtrabackupUser         = flag.String("xtrabackup_user", "", "user that xtrabackup uses to connect to mysqld through its socket, it needs the privileges listed in the xtrabackup documentation")
+
+	// xtrabackupGTIDRegexp finds the position of the backup in the
+	// output of xtrabackup. The GTID set can span multiple lines.
+	xtrabackupGTIDRegexp = regexp.MustCompile(`GTID of the last change '([^']*)'`)
+)
+
+// XtrabackupEngine is a BackupEngine that uses xtrabackup.
+type XtrabackupEngine struct{}
+
+// xtrabackupBinary returns the path of an xtrabackup binary.
+func xtrabackupBinary(name string) string {
+	if *xtrabackupRootPath == "" {
+		return name
+	}
+	return path.Join(*xtrabackupRootPath, name)
+}
+
+// ExecuteBackup is part of the BackupEngine interface.
+func (be *XtrabackupEngine) ExecuteBackup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh backupstorage.BackupHandle, backupConcurrency int, hookExtraEnv map[string]string) (bool, error) {
+	if *xtrabackupUser == "" {
+		return false, errors.New("xtrabackup_user must be set to take xtrabackup backups")
+	}
+	masterPos, err := mysqld.MasterPosition()
+	if err != nil {
+		return false, fmt.Errorf("can't get master position: %v", err)
+	}
+	if masterPos.IsZero() {
+		return false, errors.New("xtrabackup backups require GTIDs")
+	}
+
+	// The storage may need a size to plan the upload, the size
+	// of the data files is a good estimate.
+	cnf := mysqld.Cnf()
+	size, err := estimateBackupSize(cnf)
+	if err != nil {
+		return false, err
+	}
+
+	tmpDir, err := ioutil.TempDir(cnf.TmpDir, xtrabackup)
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	args := []string{
+		"--defaults-file=" + cnf.path,
+		"--backup",
+		"--socket=" + cnf.SocketFile,
+		"--slave-info",
+		"--user=" + *xtrabackupUser,
+		"--target-dir=" + tmpDir,
+		"--stream=xbstream",
+	}
+	args = append(args, strings.Fields(*xtrabackupBackupFlags)...)
+	cmd := exec.CommandContext(ctx, xtrabackupBinary(xtrabackup), args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return false, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return false, err
+	}
+	logger.Infof("running %v %v", xtrabackup, strings.Join(args, " "))
+	if err := cmd.Start(); err != nil {
+		return false, fmt.Errorf("can't start %v: %v", xtrabackup, err)
+	}
+
+	// Log the output as it comes, and keep it to find the position.
+	var output []string
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			line := scanner.Text()
+			logger.Infof("%v: %v", xtrabackup, line)
+			output = append(output, line)
+		}
+	}()
+
+	hash, rawHash, streamErr := backupStream(ctx, logger, bh, "0", size, stdout, hookExtraEnv)
+	if streamErr != nil {
+		// xtrabackup would block writing to its output.
+		cmd.Process.Kill()
+	}
+	wg.Wait()
+	waitErr := cmd.Wait()
+	if streamErr != nil {
+		return false, streamErr
+	}
+	if waitErr != nil {
+		return false, fmt.Errorf("%v failed: %v", xtrabackup, waitErr)
+	}
+
+	match := xtrabackupGTIDRegexp.FindStringSubmatch(strings.Join(output, "\n"))
+	if match == nil {
+		return false, fmt.Errorf("cannot find the GTID position in the %v output", xtrabackup)
+	}
+	gtids := strings.Replace(match[1], "\n", "", -1)
+	pos, err := mysql.ParsePosition(masterPos.GTIDSet.Flavor(), gtids)
+	if err != nil {
+		return false, fmt.Errorf("cannot parse the GTID position %q in the %v output: %v", gtids, xtrabackup, err)
+	}
+	logger.Infof("backup position: %v", pos)
+
+	if err := writeManifest(ctx, bh, &BackupManifest{
+		FileEntries: []FileEntry{{
+			Base:    backupData,
+			Name:    xtrabackupStream,
+			Hash:    hash,
+			RawHash: rawHash,
+		}},
+		Position:      pos,
+		TransformHook: *backupStorageHook,
+		SkipCompress:  !*backupStorageCompress,
+		BackupTime:    time.Now().UTC(),
+		BackupMethod:  xtrabackup,
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ExecuteRestore is part of the BackupEngine interface. It extracts the
+// backup in a temporary directory, prepares it, and moves the files to
+// the directories of cnf.
+func (be *XtrabackupEngine) ExecuteRestore(ctx context.Context, cnf *Mycnf, logger logutil.Logger, bh backupstorage.BackupHandle, bm *BackupManifest, restoreConcurrency int, hookExtraEnv map[string]string) error {
+	if len(bm.FileEntries) != 1 || bm.FileEntries[0].Name != xtrabackupStream {
+		return fmt.Errorf("unexpected files in %v backup: %v", xtrabackup, bm.FileEntries)
+	}
+	fe := bm.FileEntries[0]
+
+	tmpDir, err := ioutil.TempDir(cnf.TmpDir, xtrabackup)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	logger.Infof("Restore: extracting %v", xtrabackupStream)
+	cmd := exec.CommandContext(ctx, xtrabackupBinary("xbstream"), "-x", "-C", tmpDir)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	output := &bytes.Buffer{}
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("can't start xbstream: %v", err)
+	}
+	streamErr := restoreStream(ctx, bh, "0", fe.Name, fe.Hash, fe.RawHash, bm.TransformHook, !bm.SkipCompress, stdin, hookExtraEnv)
+	stdin.Close()
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("xbstream failed: %v, output: %v", err, output.String())
+	}
+	if streamErr != nil {
+		return streamErr
+	}
+
+	logger.Infof("Restore: preparing the backup")
+	args := append([]string{"--prepare", "--target-dir=" + tmpDir}, strings.Fields(*xtrabackupPrepareFlags)...)
+	if _, _, err := execCmd(xtrabackupBinary(xtrabackup), args, os.Environ(), tmpDir, nil); err != nil {
+		return err
+	}
+
+	logger.Infof("Restore: moving the files in place")
+	args = []string{"--defaults-file=" + cnf.path, "--move-back", "--target-dir=" + tmpDir}
+	_, _, err = execCmd(xtrabackupBinary(xtrabackup), args, os.Environ(), tmpDir, nil)
+	return err
+}
+
+// ShouldDrainForBackup is part of the BackupEngine interface. The
+// backup is taken while mysqld is serving.
+func (be *XtrabackupEngine) ShouldDrainForBackup() bool {
+	return false
+}
+
+// estimateBackupSize returns the total size of the files a builtin
+// backup would copy.
+func estimateBackupSize(cnf *Mycnf) (int64, error) {
+	fes, err := findFilesToBackup(cnf)
+	if err != nil {
+		return 0, fmt.Errorf("can't find files to backup: %v", err)
+	}
+	var size int64
+	for i := range fes {
+		fd, err := fes[i].open(cnf, true)
+		if err != nil {
+			return 0, err
+		}
+		fi, err := fd.Stat()
+		fd.Close()
+		if err != nil {
+			return 0, err
+		}
+		size += fi.Size()
+	}
+	return size, nil
+}
+
+func init() {
+	BackupEngineMap[xtrabackup] = &XtrabackupEngine{}
+}
@@ -109,7 +109,7 @@ func (mp *Proxy) doCommit(ctx context.Context, session *ProxySession) error {
 		return fmt.Errorf("commit: no open transaction")
 
 	}
-	err := mp.qs.Commit(ctx, mp.target, session.TransactionID)
+	_, err := mp.qs.Commit(ctx, mp.target, session.TransactionID, false /* includeGTIDExecuted */)
 	session.TransactionID = 0
 	return err
 }
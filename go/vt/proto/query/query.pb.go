@@ -66,6 +66,10 @@ It has these top-level messages:
 	StreamHealthResponse
 	UpdateStreamRequest
 	UpdateStreamResponse
+	RowChange
+	ChangeEvent
+	StreamChangesRequest
+	StreamChangesResponse
 	TransactionMetadata
 */
 package query
@@ -542,6 +546,37 @@ func (SplitQueryRequest_Algorithm) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor0, []int{49, 0}
 }
 
+// Type is the type of the change.
+type RowChange_Type int32
+
+const (
+	RowChange_INSERT       RowChange_Type = 0
+	RowChange_UPDATE       RowChange_Type = 1
+	RowChange_DELETE       RowChange_Type = 2
+	RowChange_DDL          RowChange_Type = 3
+	RowChange_UNRECOGNIZED RowChange_Type = 4
+)
+
+var RowChange_Type_name = map[int32]string{
+	0: "INSERT",
+	1: "UPDATE",
+	2: "DELETE",
+	3: "DDL",
+	4: "UNRECOGNIZED",
+}
+var RowChange_Type_value = map[string]int32{
+	"INSERT":       0,
+	"UPDATE":       1,
+	"DELETE":       2,
+	"DDL":          3,
+	"UNRECOGNIZED": 4,
+}
+
+func (x RowChange_Type) String() string {
+	return proto.EnumName(RowChange_Type_name, int32(x))
+}
+func (RowChange_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{58, 0} }
+
 // Target describes what the client expects the tablet is.
 // If the tablet does not match, an error is returned.
 type Target struct {
@@ -777,6 +812,13 @@ type ExecuteOptions struct {
 	// skip_query_plan_cache specifies if the query plan shoud be cached by vitess.
 	// By default all query plans are cached.
 	SkipQueryPlanCache bool `protobuf:"varint,10,opt,name=skip_query_plan_cache,json=skipQueryPlanCache" json:"skip_query_plan_cache,omitempty"`
+	// wait_for_gtid_set, if set, makes the tablet wait until MySQL has
+	// replicated up to this position, encoded with its flavor, before
+	// running the query.
+	WaitForGtidSet string `protobuf:"bytes,11,opt,name=wait_for_gtid_set,json=waitForGtidSet" json:"wait_for_gtid_set,omitempty"`
+	// wait_for_gtid_set_timeout_ms is how long the tablet waits for
+	// wait_for_gtid_set, in milliseconds.
+	WaitForGtidSetTimeoutMs int64 `protobuf:"varint,12,opt,name=wait_for_gtid_set_timeout_ms,json=waitForGtidSetTimeoutMs" json:"wait_for_gtid_set_timeout_ms,omitempty"`
 }
 
 func (m *ExecuteOptions) Reset()                    { *m = ExecuteOptions{} }
@@ -840,6 +882,20 @@ func (m *ExecuteOptions) GetSkipQueryPlanCache() bool {
 	return false
 }
 
+func (m *ExecuteOptions) GetWaitForGtidSet() string {
+	if m != nil {
+		return m.WaitForGtidSet
+	}
+	return ""
+}
+
+func (m *ExecuteOptions) GetWaitForGtidSetTimeoutMs() int64 {
+	if m != nil {
+		return m.WaitForGtidSetTimeoutMs
+	}
+	return 0
+}
+
 // Field describes a single column returned by a query
 type Field struct {
 	// name of the field as returned by mysql C API
@@ -1451,6 +1507,9 @@ type CommitRequest struct {
 	ImmediateCallerId *VTGateCallerID `protobuf:"bytes,2,opt,name=immediate_caller_id,json=immediateCallerId" json:"immediate_caller_id,omitempty"`
 	Target            *Target         `protobuf:"bytes,3,opt,name=target" json:"target,omitempty"`
 	TransactionId     int64           `protobuf:"varint,4,opt,name=transaction_id,json=transactionId" json:"transaction_id,omitempty"`
+	// include_gtid_executed asks for the replication position of
+	// MySQL after the commit.
+	IncludeGtidExecuted bool `protobuf:"varint,5,opt,name=include_gtid_executed,json=includeGtidExecuted" json:"include_gtid_executed,omitempty"`
 }
 
 func (m *CommitRequest) Reset()                    { *m = CommitRequest{} }
@@ -1486,8 +1545,18 @@ func (m *CommitRequest) GetTransactionId() int64 {
 	return 0
 }
 
+func (m *CommitRequest) GetIncludeGtidExecuted() bool {
+	if m != nil {
+		return m.IncludeGtidExecuted
+	}
+	return false
+}
+
 // CommitResponse is the returned value from Commit
 type CommitResponse struct {
+	// gtid_executed is the replication position of MySQL after the
+	// commit, encoded with its flavor, if include_gtid_executed was set.
+	GtidExecuted string `protobuf:"bytes,1,opt,name=gtid_executed,json=gtidExecuted" json:"gtid_executed,omitempty"`
 }
 
 func (m *CommitResponse) Reset()                    { *m = CommitResponse{} }
@@ -1495,6 +1564,13 @@ func (m *CommitResponse) String() string            { return proto.CompactTextSt
 func (*CommitResponse) ProtoMessage()               {}
 func (*CommitResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
 
+func (m *CommitResponse) GetGtidExecuted() string {
+	if m != nil {
+		return m.GtidExecuted
+	}
+	return ""
+}
+
 // RollbackRequest is the payload to Rollback
 type RollbackRequest struct {
 	EffectiveCallerId *vtrpc.CallerID `protobuf:"bytes,1,opt,name=effective_caller_id,json=effectiveCallerId" json:"effective_caller_id,omitempty"`
@@ -1609,6 +1685,9 @@ type CommitPreparedRequest struct {
 	ImmediateCallerId *VTGateCallerID `protobuf:"bytes,2,opt,name=immediate_caller_id,json=immediateCallerId" json:"immediate_caller_id,omitempty"`
 	Target            *Target         `protobuf:"bytes,3,opt,name=target" json:"target,omitempty"`
 	Dtid              string          `protobuf:"bytes,4,opt,name=dtid" json:"dtid,omitempty"`
+	// include_gtid_executed asks for the replication position of
+	// MySQL after the commit.
+	IncludeGtidExecuted bool `protobuf:"varint,5,opt,name=include_gtid_executed,json=includeGtidExecuted" json:"include_gtid_executed,omitempty"`
 }
 
 func (m *CommitPreparedRequest) Reset()                    { *m = CommitPreparedRequest{} }
@@ -1644,8 +1723,18 @@ func (m *CommitPreparedRequest) GetDtid() string {
 	return ""
 }
 
+func (m *CommitPreparedRequest) GetIncludeGtidExecuted() bool {
+	if m != nil {
+		return m.IncludeGtidExecuted
+	}
+	return false
+}
+
 // CommitPreparedResponse is the returned value from CommitPrepared
 type CommitPreparedResponse struct {
+	// gtid_executed is the replication position of MySQL after the
+	// commit, encoded with its flavor, if include_gtid_executed was set.
+	GtidExecuted string `protobuf:"bytes,1,opt,name=gtid_executed,json=gtidExecuted" json:"gtid_executed,omitempty"`
 }
 
 func (m *CommitPreparedResponse) Reset()                    { *m = CommitPreparedResponse{} }
@@ -1653,6 +1742,13 @@ func (m *CommitPreparedResponse) String() string            { return proto.Compa
 func (*CommitPreparedResponse) ProtoMessage()               {}
 func (*CommitPreparedResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
 
+func (m *CommitPreparedResponse) GetGtidExecuted() string {
+	if m != nil {
+		return m.GtidExecuted
+	}
+	return ""
+}
+
 // RollbackPreparedRequest is the payload to RollbackPrepared
 type RollbackPreparedRequest struct {
 	EffectiveCallerId *vtrpc.CallerID `protobuf:"bytes,1,opt,name=effective_caller_id,json=effectiveCallerId" json:"effective_caller_id,omitempty"`
@@ -1776,6 +1872,9 @@ type StartCommitRequest struct {
 	Target            *Target         `protobuf:"bytes,3,opt,name=target" json:"target,omitempty"`
 	TransactionId     int64           `protobuf:"varint,4,opt,name=transaction_id,json=transactionId" json:"transaction_id,omitempty"`
 	Dtid              string          `protobuf:"bytes,5,opt,name=dtid" json:"dtid,omitempty"`
+	// include_gtid_executed asks for the replication position of
+	// MySQL after the commit.
+	IncludeGtidExecuted bool `protobuf:"varint,6,opt,name=include_gtid_executed,json=includeGtidExecuted" json:"include_gtid_executed,omitempty"`
 }
 
 func (m *StartCommitRequest) Reset()                    { *m = StartCommitRequest{} }
@@ -1818,8 +1917,18 @@ func (m *StartCommitRequest) GetDtid() string {
 	return ""
 }
 
+func (m *StartCommitRequest) GetIncludeGtidExecuted() bool {
+	if m != nil {
+		return m.IncludeGtidExecuted
+	}
+	return false
+}
+
 // StartCommitResponse is the returned value from StartCommit
 type StartCommitResponse struct {
+	// gtid_executed is the replication position of MySQL after the
+	// commit, encoded with its flavor, if include_gtid_executed was set.
+	GtidExecuted string `protobuf:"bytes,1,opt,name=gtid_executed,json=gtidExecuted" json:"gtid_executed,omitempty"`
 }
 
 func (m *StartCommitResponse) Reset()                    { *m = StartCommitResponse{} }
@@ -1827,6 +1936,13 @@ func (m *StartCommitResponse) String() string            { return proto.CompactT
 func (*StartCommitResponse) ProtoMessage()               {}
 func (*StartCommitResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
 
+func (m *StartCommitResponse) GetGtidExecuted() string {
+	if m != nil {
+		return m.GtidExecuted
+	}
+	return ""
+}
+
 // SetRollbackRequest is the payload to SetRollback
 type SetRollbackRequest struct {
 	EffectiveCallerId *vtrpc.CallerID `protobuf:"bytes,1,opt,name=effective_caller_id,json=effectiveCallerId" json:"effective_caller_id,omitempty"`
@@ -2738,6 +2854,183 @@ func (m *UpdateStreamResponse) GetEvent() *StreamEvent {
 	return nil
 }
 
+// RowChange is a change made to a row, or a DDL, in a ChangeEvent.
+type RowChange struct {
+	Type  RowChange_Type `protobuf:"varint,1,opt,name=type,enum=query.RowChange_Type" json:"type,omitempty"`
+	Table string         `protobuf:"bytes,2,opt,name=table" json:"table,omitempty"`
+	// fields, before and after are only set for row based events with
+	// full row images. before is not set for inserts, and after is not
+	// set for deletes.
+	Fields []*Field `protobuf:"bytes,3,rep,name=fields" json:"fields,omitempty"`
+	Before *Row     `protobuf:"bytes,4,opt,name=before" json:"before,omitempty"`
+	After  *Row     `protobuf:"bytes,5,opt,name=after" json:"after,omitempty"`
+	// pk_fields and pk_values identify the changed rows. Statement based
+	// events can change several rows.
+	PkFields []*Field `protobuf:"bytes,6,rep,name=pk_fields,json=pkFields" json:"pk_fields,omitempty"`
+	PkValues []*Row   `protobuf:"bytes,7,rep,name=pk_values,json=pkValues" json:"pk_values,omitempty"`
+	// sql is the statement, for DDLs, and for DMLs without row images.
+	Sql []byte `protobuf:"bytes,8,opt,name=sql,proto3" json:"sql,omitempty"`
+}
+
+func (m *RowChange) Reset()                    { *m = RowChange{} }
+func (m *RowChange) String() string            { return proto.CompactTextString(m) }
+func (*RowChange) ProtoMessage()               {}
+func (*RowChange) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
+
+func (m *RowChange) GetType() RowChange_Type {
+	if m != nil {
+		return m.Type
+	}
+	return RowChange_INSERT
+}
+
+func (m *RowChange) GetTable() string {
+	if m != nil {
+		return m.Table
+	}
+	return ""
+}
+
+func (m *RowChange) GetFields() []*Field {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+func (m *RowChange) GetBefore() *Row {
+	if m != nil {
+		return m.Before
+	}
+	return nil
+}
+
+func (m *RowChange) GetAfter() *Row {
+	if m != nil {
+		return m.After
+	}
+	return nil
+}
+
+func (m *RowChange) GetPkFields() []*Field {
+	if m != nil {
+		return m.PkFields
+	}
+	return nil
+}
+
+func (m *RowChange) GetPkValues() []*Row {
+	if m != nil {
+		return m.PkValues
+	}
+	return nil
+}
+
+func (m *RowChange) GetSql() []byte {
+	if m != nil {
+		return m.Sql
+	}
+	return nil
+}
+
+// ChangeEvent is a transaction of a change data capture stream.
+type ChangeEvent struct {
+	// timestamp is the time of the transaction, in seconds since epoch.
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp" json:"timestamp,omitempty"`
+	// position is the replication position right after the transaction.
+	// A stream started from it resumes with the next transaction.
+	Position string `protobuf:"bytes,2,opt,name=position" json:"position,omitempty"`
+	// changes are the changes made by the transaction, in order.
+	Changes []*RowChange `protobuf:"bytes,3,rep,name=changes" json:"changes,omitempty"`
+}
+
+func (m *ChangeEvent) Reset()                    { *m = ChangeEvent{} }
+func (m *ChangeEvent) String() string            { return proto.CompactTextString(m) }
+func (*ChangeEvent) ProtoMessage()               {}
+func (*ChangeEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
+
+func (m *ChangeEvent) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *ChangeEvent) GetPosition() string {
+	if m != nil {
+		return m.Position
+	}
+	return ""
+}
+
+func (m *ChangeEvent) GetChanges() []*RowChange {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+// StreamChangesRequest is the payload for StreamChanges.
+type StreamChangesRequest struct {
+	EffectiveCallerId *vtrpc.CallerID `protobuf:"bytes,1,opt,name=effective_caller_id,json=effectiveCallerId" json:"effective_caller_id,omitempty"`
+	ImmediateCallerId *VTGateCallerID `protobuf:"bytes,2,opt,name=immediate_caller_id,json=immediateCallerId" json:"immediate_caller_id,omitempty"`
+	Target            *Target         `protobuf:"bytes,3,opt,name=target" json:"target,omitempty"`
+	// position is the replication position to start streaming from. If it
+	// is empty, the stream starts at the current position, which is sent
+	// first, in an event without changes.
+	Position string `protobuf:"bytes,4,opt,name=position" json:"position,omitempty"`
+}
+
+func (m *StreamChangesRequest) Reset()                    { *m = StreamChangesRequest{} }
+func (m *StreamChangesRequest) String() string            { return proto.CompactTextString(m) }
+func (*StreamChangesRequest) ProtoMessage()               {}
+func (*StreamChangesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
+
+func (m *StreamChangesRequest) GetEffectiveCallerId() *vtrpc.CallerID {
+	if m != nil {
+		return m.EffectiveCallerId
+	}
+	return nil
+}
+
+func (m *StreamChangesRequest) GetImmediateCallerId() *VTGateCallerID {
+	if m != nil {
+		return m.ImmediateCallerId
+	}
+	return nil
+}
+
+func (m *StreamChangesRequest) GetTarget() *Target {
+	if m != nil {
+		return m.Target
+	}
+	return nil
+}
+
+func (m *StreamChangesRequest) GetPosition() string {
+	if m != nil {
+		return m.Position
+	}
+	return ""
+}
+
+// StreamChangesResponse is returned by StreamChanges.
+type StreamChangesResponse struct {
+	Event *ChangeEvent `protobuf:"bytes,1,opt,name=event" json:"event,omitempty"`
+}
+
+func (m *StreamChangesResponse) Reset()                    { *m = StreamChangesResponse{} }
+func (m *StreamChangesResponse) String() string            { return proto.CompactTextString(m) }
+func (*StreamChangesResponse) ProtoMessage()               {}
+func (*StreamChangesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
+
+func (m *StreamChangesResponse) GetEvent() *ChangeEvent {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
 // TransactionMetadata contains the metadata for a distributed transaction.
 type TransactionMetadata struct {
 	Dtid         string           `protobuf:"bytes,1,opt,name=dtid" json:"dtid,omitempty"`
@@ -2749,7 +3042,7 @@ type TransactionMetadata struct {
 func (m *TransactionMetadata) Reset()                    { *m = TransactionMetadata{} }
 func (m *TransactionMetadata) String() string            { return proto.CompactTextString(m) }
 func (*TransactionMetadata) ProtoMessage()               {}
-func (*TransactionMetadata) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
+func (*TransactionMetadata) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
 
 func (m *TransactionMetadata) GetDtid() string {
 	if m != nil {
@@ -2839,6 +3132,10 @@ func init() {
 	proto.RegisterType((*StreamHealthResponse)(nil), "query.StreamHealthResponse")
 	proto.RegisterType((*UpdateStreamRequest)(nil), "query.UpdateStreamRequest")
 	proto.RegisterType((*UpdateStreamResponse)(nil), "query.UpdateStreamResponse")
+	proto.RegisterType((*RowChange)(nil), "query.RowChange")
+	proto.RegisterType((*ChangeEvent)(nil), "query.ChangeEvent")
+	proto.RegisterType((*StreamChangesRequest)(nil), "query.StreamChangesRequest")
+	proto.RegisterType((*StreamChangesResponse)(nil), "query.StreamChangesResponse")
 	proto.RegisterType((*TransactionMetadata)(nil), "query.TransactionMetadata")
 	proto.RegisterEnum("query.MySqlFlag", MySqlFlag_name, MySqlFlag_value)
 	proto.RegisterEnum("query.Flag", Flag_name, Flag_value)
@@ -2849,209 +3146,229 @@ func init() {
 	proto.RegisterEnum("query.ExecuteOptions_TransactionIsolation", ExecuteOptions_TransactionIsolation_name, ExecuteOptions_TransactionIsolation_value)
 	proto.RegisterEnum("query.StreamEvent_Statement_Category", StreamEvent_Statement_Category_name, StreamEvent_Statement_Category_value)
 	proto.RegisterEnum("query.SplitQueryRequest_Algorithm", SplitQueryRequest_Algorithm_name, SplitQueryRequest_Algorithm_value)
+	proto.RegisterEnum("query.RowChange_Type", RowChange_Type_name, RowChange_Type_value)
 }
 
 func init() { proto.RegisterFile("query.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 3183 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x5a, 0xcb, 0x73, 0x1b, 0xc7,
-	0x99, 0xd7, 0xe0, 0x41, 0x02, 0x1f, 0x08, 0xb0, 0xd9, 0x20, 0x25, 0x88, 0xf2, 0x83, 0x3b, 0xb6,
-	0x6c, 0x2e, 0xed, 0xe5, 0xca, 0x94, 0x56, 0xab, 0xb5, 0x77, 0xbd, 0x1a, 0x82, 0x43, 0x19, 0x16,
-	0x5e, 0x6a, 0x0c, 0x24, 0xcb, 0xe5, 0xaa, 0xa9, 0x21, 0xd0, 0x02, 0xa7, 0x38, 0xc0, 0x40, 0x33,
-	0x03, 0x51, 0xbc, 0x69, 0xd7, 0x71, 0xde, 0x0f, 0xe7, 0xe9, 0x38, 0xa9, 0x38, 0xa9, 0xca, 0x3d,
-	0x7f, 0x43, 0x2a, 0x7f, 0x40, 0x6e, 0x39, 0x24, 0x39, 0xe4, 0x90, 0x4a, 0xe5, 0x90, 0x2a, 0x57,
-	0x4e, 0x39, 0xe4, 0x90, 0x4a, 0xf5, 0x63, 0x06, 0x03, 0x12, 0x7a, 0x58, 0xc9, 0x85, 0xb2, 0x4f,
-	0xe8, 0xfe, 0xbe, 0xaf, 0x1f, 0xbf, 0xdf, 0xf7, 0xcd, 0xd7, 0x8d, 0xee, 0x86, 0xdc, 0xed, 0x11,
-	0xf5, 0x0e, 0xd6, 0x87, 0x9e, 0x1b, 0xb8, 0x38, 0xcd, 0x2b, 0xcb, 0x85, 0xc0, 0x1d, 0xba, 0x5d,
-	0x2b, 0xb0, 0x84, 0x78, 0x39, 0x77, 0x27, 0xf0, 0x86, 0x1d, 0x51, 0x51, 0xdf, 0x53, 0x60, 0xc6,
-	0xb0, 0xbc, 0x1e, 0x0d, 0xf0, 0x32, 0x64, 0xf6, 0xe8, 0x81, 0x3f, 0xb4, 0x3a, 0xb4, 0xa4, 0xac,
-	0x28, 0xab, 0x59, 0x12, 0xd5, 0xf1, 0x22, 0xa4, 0xfd, 0x5d, 0xcb, 0xeb, 0x96, 0x12, 0x5c, 0x21,
-	0x2a, 0xf8, 0x3f, 0x20, 0x17, 0x58, 0x3b, 0x0e, 0x0d, 0xcc, 0xe0, 0x60, 0x48, 0x4b, 0xc9, 0x15,
-	0x65, 0xb5, 0xb0, 0xb1, 0xb8, 0x1e, 0x8d, 0x67, 0x70, 0xa5, 0x71, 0x30, 0xa4, 0x04, 0x82, 0xa8,
-	0x8c, 0x31, 0xa4, 0x3a, 0xd4, 0x71, 0x4a, 0x29, 0xde, 0x17, 0x2f, 0xab, 0x5b, 0x50, 0xb8, 0x6e,
-	0x5c, 0xb1, 0x02, 0x5a, 0xb6, 0x1c, 0x87, 0x7a, 0x95, 0x2d, 0x36, 0x9d, 0x91, 0x4f, 0xbd, 0x81,
-	0xd5, 0x8f, 0xa6, 0x13, 0xd6, 0xf1, 0x49, 0x98, 0xe9, 0x79, 0xee, 0x68, 0xe8, 0x97, 0x12, 0x2b,
-	0xc9, 0xd5, 0x2c, 0x91, 0x35, 0xf5, 0x1d, 0x00, 0xfd, 0x0e, 0x1d, 0x04, 0x86, 0xbb, 0x47, 0x07,
-	0xf8, 0x29, 0xc8, 0x06, 0x76, 0x9f, 0xfa, 0x81, 0xd5, 0x1f, 0xf2, 0x2e, 0x92, 0x64, 0x2c, 0xb8,
-	0x0f, 0xa4, 0x65, 0xc8, 0x0c, 0x5d, 0xdf, 0x0e, 0x6c, 0x77, 0xc0, 0xf1, 0x64, 0x49, 0x54, 0x57,
-	0x5f, 0x87, 0xf4, 0x75, 0xcb, 0x19, 0x51, 0xfc, 0x2c, 0xa4, 0x38, 0x60, 0x85, 0x03, 0xce, 0xad,
-	0x0b, 0xd2, 0x39, 0x4e, 0xae, 0x60, 0x7d, 0xdf, 0x61, 0x96, 0xbc, 0xef, 0x39, 0x22, 0x2a, 0xea,
-	0x1e, 0xcc, 0x6d, 0xda, 0x83, 0xee, 0x75, 0xcb, 0xb3, 0x19, 0x19, 0x8f, 0xd9, 0x0d, 0x7e, 0x1e,
-	0x66, 0x78, 0xc1, 0x2f, 0x25, 0x57, 0x92, 0xab, 0xb9, 0x8d, 0x39, 0xd9, 0x90, 0xcf, 0x8d, 0x48,
-	0x9d, 0xfa, 0x0b, 0x05, 0x60, 0xd3, 0x1d, 0x0d, 0xba, 0xd7, 0x98, 0x12, 0x23, 0x48, 0xfa, 0xb7,
-	0x1d, 0x49, 0x24, 0x2b, 0xe2, 0xab, 0x50, 0xd8, 0xb1, 0x07, 0x5d, 0xf3, 0x8e, 0x9c, 0x8e, 0xe0,
-	0x32, 0xb7, 0xf1, 0xbc, 0xec, 0x6e, 0xdc, 0x78, 0x3d, 0x3e, 0x6b, 0x5f, 0x1f, 0x04, 0xde, 0x01,
-	0xc9, 0xef, 0xc4, 0x65, 0xcb, 0x6d, 0xc0, 0x47, 0x8d, 0xd8, 0xa0, 0x7b, 0xf4, 0x20, 0x1c, 0x74,
-	0x8f, 0x1e, 0xe0, 0x7f, 0x8d, 0x23, 0xca, 0x6d, 0x14, 0xc3, 0xb1, 0x62, 0x6d, 0x25, 0xcc, 0x57,
-	0x13, 0x97, 0x14, 0xf5, 0x4f, 0x69, 0x28, 0xe8, 0x77, 0x69, 0x67, 0x14, 0xd0, 0xc6, 0x90, 0xf9,
-	0xc0, 0xc7, 0xeb, 0x50, 0xb4, 0x07, 0x1d, 0x67, 0xd4, 0xa5, 0x26, 0x65, 0xae, 0x36, 0x03, 0xe6,
-	0x6b, 0xde, 0x5f, 0x86, 0x2c, 0x48, 0x55, 0x2c, 0x08, 0x34, 0x28, 0x76, 0xdc, 0xfe, 0xd0, 0xf2,
-	0x26, 0xed, 0x93, 0x7c, 0xfc, 0x05, 0x39, 0xfe, 0xd8, 0x9e, 0x2c, 0x48, 0xeb, 0x58, 0x17, 0x35,
-	0x98, 0x97, 0xfd, 0x76, 0xcd, 0x5b, 0x36, 0x75, 0xba, 0x3e, 0x0f, 0xdd, 0x42, 0x44, 0xd5, 0xe4,
-	0x14, 0xd7, 0x2b, 0xd2, 0x78, 0x9b, 0xdb, 0x92, 0x82, 0x3d, 0x51, 0xc7, 0x6b, 0xb0, 0xd0, 0x71,
-	0x6c, 0x36, 0x95, 0x5b, 0x8c, 0x62, 0xd3, 0x73, 0xf7, 0xfd, 0x52, 0x9a, 0xcf, 0x7f, 0x5e, 0x28,
-	0xb6, 0x99, 0x9c, 0xb8, 0xfb, 0x3e, 0x7e, 0x15, 0x32, 0xfb, 0xae, 0xb7, 0xe7, 0xb8, 0x56, 0xb7,
-	0x34, 0xc3, 0xc7, 0x7c, 0x66, 0xfa, 0x98, 0x37, 0xa4, 0x15, 0x89, 0xec, 0xf1, 0x2a, 0x20, 0xff,
-	0xb6, 0x63, 0xfa, 0xd4, 0xa1, 0x9d, 0xc0, 0x74, 0xec, 0xbe, 0x1d, 0x94, 0x32, 0xfc, 0x2b, 0x28,
-	0xf8, 0xb7, 0x9d, 0x16, 0x17, 0x57, 0x99, 0x14, 0x9b, 0xb0, 0x14, 0x78, 0xd6, 0xc0, 0xb7, 0x3a,
-	0xac, 0x33, 0xd3, 0xf6, 0x5d, 0xc7, 0xe2, 0x5f, 0x40, 0x96, 0x0f, 0xb9, 0x36, 0x7d, 0x48, 0x63,
-	0xdc, 0xa4, 0x12, 0xb6, 0x20, 0x8b, 0xc1, 0x14, 0x29, 0x7e, 0x05, 0x96, 0xfc, 0x3d, 0x7b, 0x68,
-	0xf2, 0x7e, 0xcc, 0xa1, 0x63, 0x0d, 0xcc, 0x8e, 0xd5, 0xd9, 0xa5, 0x25, 0xe0, 0xb0, 0x31, 0x53,
-	0xf2, 0x50, 0x6b, 0x3a, 0xd6, 0xa0, 0xcc, 0x34, 0xea, 0x6b, 0x50, 0x98, 0xe4, 0x11, 0x2f, 0x40,
-	0xde, 0xb8, 0xd9, 0xd4, 0x4d, 0xad, 0xbe, 0x65, 0xd6, 0xb5, 0x9a, 0x8e, 0x4e, 0xe0, 0x3c, 0x64,
-	0xb9, 0xa8, 0x51, 0xaf, 0xde, 0x44, 0x0a, 0x9e, 0x85, 0xa4, 0x56, 0xad, 0xa2, 0x84, 0x7a, 0x09,
-	0x32, 0x21, 0x21, 0x78, 0x1e, 0x72, 0xed, 0x7a, 0xab, 0xa9, 0x97, 0x2b, 0xdb, 0x15, 0x7d, 0x0b,
-	0x9d, 0xc0, 0x19, 0x48, 0x35, 0xaa, 0x46, 0x13, 0x29, 0xa2, 0xa4, 0x35, 0x51, 0x82, 0xb5, 0xdc,
-	0xda, 0xd4, 0x50, 0x52, 0x0d, 0x60, 0x71, 0x1a, 0x2e, 0x9c, 0x83, 0xd9, 0x2d, 0x7d, 0x5b, 0x6b,
-	0x57, 0x0d, 0x74, 0x02, 0x17, 0x61, 0x9e, 0xe8, 0x4d, 0x5d, 0x33, 0xb4, 0xcd, 0xaa, 0x6e, 0x12,
-	0x5d, 0xdb, 0x42, 0x0a, 0xc6, 0x50, 0x60, 0x25, 0xb3, 0xdc, 0xa8, 0xd5, 0x2a, 0x86, 0xa1, 0x6f,
-	0xa1, 0x04, 0x5e, 0x04, 0xc4, 0x65, 0xed, 0xfa, 0x58, 0x9a, 0xc4, 0x08, 0xe6, 0x5a, 0x3a, 0xa9,
-	0x68, 0xd5, 0xca, 0xdb, 0xac, 0x03, 0x94, 0x7a, 0x33, 0x95, 0x51, 0x50, 0x42, 0xfd, 0x20, 0x01,
-	0x69, 0x8e, 0x95, 0x65, 0xc8, 0x58, 0xde, 0xe3, 0xe5, 0x28, 0x5b, 0x24, 0x1e, 0x90, 0x2d, 0x78,
-	0x92, 0x95, 0x79, 0x4b, 0x54, 0xf0, 0x19, 0xc8, 0xba, 0x5e, 0xcf, 0x14, 0x1a, 0x91, 0x71, 0x33,
-	0xae, 0xd7, 0xe3, 0xa9, 0x99, 0x65, 0x3b, 0x96, 0xa8, 0x77, 0x2c, 0x9f, 0xf2, 0x08, 0xcc, 0x92,
-	0xa8, 0x8e, 0x4f, 0x03, 0xb3, 0x33, 0xf9, 0x3c, 0x66, 0xb8, 0x6e, 0xd6, 0xf5, 0x7a, 0x75, 0x36,
-	0x95, 0xe7, 0x20, 0xdf, 0x71, 0x9d, 0x51, 0x7f, 0x60, 0x3a, 0x74, 0xd0, 0x0b, 0x76, 0x4b, 0xb3,
-	0x2b, 0xca, 0x6a, 0x9e, 0xcc, 0x09, 0x61, 0x95, 0xcb, 0x70, 0x09, 0x66, 0x3b, 0xbb, 0x96, 0xe7,
-	0x53, 0x11, 0x75, 0x79, 0x12, 0x56, 0xf9, 0xa8, 0xb4, 0x63, 0xf7, 0x2d, 0xc7, 0xe7, 0x11, 0x96,
-	0x27, 0x51, 0x9d, 0x81, 0xb8, 0xe5, 0x58, 0x3d, 0x9f, 0x47, 0x46, 0x9e, 0x88, 0x8a, 0xfa, 0x9f,
-	0x90, 0x24, 0xee, 0x3e, 0xeb, 0x52, 0x0c, 0xe8, 0x97, 0x94, 0x95, 0xe4, 0x2a, 0x26, 0x61, 0x95,
-	0x2d, 0x08, 0x32, 0x27, 0x8a, 0x54, 0x19, 0x66, 0xc1, 0x77, 0x60, 0x8e, 0x50, 0x7f, 0xe4, 0x04,
-	0xfa, 0xdd, 0xc0, 0xb3, 0x7c, 0xbc, 0x01, 0xb9, 0x78, 0x16, 0x50, 0xee, 0x97, 0x05, 0x80, 0x8e,
-	0x3f, 0xff, 0x12, 0xcc, 0xde, 0xf2, 0xa8, 0xbf, 0x4b, 0x3d, 0x99, 0x65, 0xc2, 0x2a, 0xcb, 0xb1,
-	0x39, 0x1e, 0xb6, 0x62, 0x0c, 0x96, 0x99, 0x65, 0x7e, 0x50, 0x26, 0x32, 0x33, 0x77, 0x2a, 0x91,
-	0x3a, 0xc6, 0x1e, 0xfb, 0xe4, 0x4d, 0xeb, 0xd6, 0x2d, 0xda, 0x09, 0xa8, 0x58, 0x80, 0x52, 0x64,
-	0x8e, 0x09, 0x35, 0x29, 0x63, 0x6e, 0xb3, 0x07, 0x3e, 0xf5, 0x02, 0xd3, 0xee, 0x72, 0x87, 0xa6,
-	0x48, 0x46, 0x08, 0x2a, 0x5d, 0xfc, 0x0c, 0xa4, 0x78, 0xd2, 0x48, 0xf1, 0x51, 0x40, 0x8e, 0x42,
-	0xdc, 0x7d, 0xc2, 0xe5, 0xf8, 0x25, 0x98, 0xa1, 0x1c, 0x2f, 0x77, 0xea, 0x38, 0xcd, 0xc6, 0xa9,
-	0x20, 0xd2, 0x44, 0xfd, 0x49, 0x12, 0x72, 0xad, 0xc0, 0xa3, 0x56, 0x9f, 0xe3, 0xc7, 0xff, 0x0d,
-	0xe0, 0x07, 0x56, 0x40, 0xfb, 0x74, 0x10, 0x84, 0x40, 0x9e, 0x92, 0x1d, 0xc4, 0xec, 0xd6, 0x5b,
-	0xa1, 0x11, 0x89, 0xd9, 0x1f, 0x26, 0x38, 0xf1, 0x08, 0x04, 0x2f, 0x7f, 0x94, 0x80, 0x6c, 0xd4,
-	0x1b, 0xd6, 0x20, 0xd3, 0xb1, 0x02, 0xda, 0x73, 0xbd, 0x03, 0xb9, 0x32, 0x9e, 0x7d, 0xd0, 0xe8,
-	0xeb, 0x65, 0x69, 0x4c, 0xa2, 0x66, 0xf8, 0x69, 0x10, 0xdb, 0x0d, 0x11, 0xbc, 0x62, 0x7d, 0xcf,
-	0x72, 0x09, 0x0f, 0xdf, 0x57, 0x01, 0x0f, 0x3d, 0xbb, 0x6f, 0x79, 0x07, 0xe6, 0x1e, 0x3d, 0x08,
-	0x53, 0x7a, 0x72, 0x8a, 0xcb, 0x90, 0xb4, 0xbb, 0x4a, 0x0f, 0x64, 0x12, 0xba, 0x34, 0xd9, 0x56,
-	0x06, 0xdd, 0x51, 0x47, 0xc4, 0x5a, 0xf2, 0x75, 0xd9, 0x0f, 0x57, 0xe0, 0x34, 0x8f, 0x4f, 0x56,
-	0x54, 0x5f, 0x84, 0x4c, 0x38, 0x79, 0x9c, 0x85, 0xb4, 0xee, 0x79, 0xae, 0x87, 0x4e, 0xf0, 0x5c,
-	0x54, 0xab, 0x8a, 0x74, 0xb6, 0xb5, 0xc5, 0xd2, 0xd9, 0xcf, 0x13, 0xd1, 0x32, 0x48, 0xe8, 0xed,
-	0x11, 0xf5, 0x03, 0xfc, 0xbf, 0x50, 0xa4, 0x3c, 0x56, 0xec, 0x3b, 0xd4, 0xec, 0xf0, 0x3d, 0x13,
-	0x8b, 0x14, 0x11, 0xd0, 0xf3, 0xeb, 0x62, 0x8b, 0x17, 0xee, 0xa5, 0xc8, 0x42, 0x64, 0x2b, 0x45,
-	0x5d, 0xac, 0x43, 0xd1, 0xee, 0xf7, 0x69, 0xd7, 0xb6, 0x82, 0x78, 0x07, 0xc2, 0x61, 0x4b, 0xe1,
-	0x96, 0x62, 0x62, 0x4b, 0x46, 0x16, 0xa2, 0x16, 0x51, 0x37, 0x67, 0x61, 0x26, 0xe0, 0xdb, 0x47,
-	0xb9, 0xa2, 0xe6, 0xc3, 0xbc, 0xc4, 0x85, 0x44, 0x2a, 0xf1, 0x8b, 0x20, 0x36, 0xa3, 0x3c, 0x03,
-	0x8d, 0x03, 0x62, 0xbc, 0xc7, 0x20, 0x42, 0x8f, 0xcf, 0x42, 0x61, 0x62, 0x29, 0xea, 0x72, 0xc2,
-	0x92, 0x24, 0x1f, 0x5f, 0x57, 0xba, 0xf8, 0xdf, 0x61, 0xd6, 0x15, 0xcb, 0x10, 0xcf, 0x4d, 0xe3,
-	0x19, 0x4f, 0xae, 0x51, 0x24, 0xb4, 0x52, 0xff, 0x07, 0xe6, 0x23, 0x06, 0xfd, 0xa1, 0x3b, 0xf0,
-	0x29, 0x5e, 0x83, 0x19, 0x8f, 0x7f, 0x10, 0x92, 0x35, 0x2c, 0xbb, 0x88, 0x7d, 0xd1, 0x44, 0x5a,
-	0xa8, 0x5d, 0x98, 0x17, 0x92, 0x1b, 0x76, 0xb0, 0xcb, 0x1d, 0x85, 0xcf, 0x42, 0x9a, 0xb2, 0xc2,
-	0x21, 0xce, 0x49, 0xb3, 0xcc, 0xf5, 0x44, 0x68, 0x63, 0xa3, 0x24, 0x1e, 0x3a, 0xca, 0x9f, 0x13,
-	0x50, 0x94, 0xb3, 0xdc, 0xb4, 0x82, 0xce, 0xee, 0x31, 0x75, 0xf6, 0x4b, 0x30, 0xcb, 0xe4, 0x76,
-	0xf4, 0x61, 0x4c, 0x71, 0x77, 0x68, 0xc1, 0x1c, 0x6e, 0xf9, 0x66, 0xcc, 0xbb, 0x72, 0x2b, 0x94,
-	0xb7, 0xfc, 0xd8, 0x42, 0x3c, 0x25, 0x2e, 0x66, 0x1e, 0x12, 0x17, 0xb3, 0x8f, 0x14, 0x17, 0x5b,
-	0xb0, 0x38, 0xc9, 0xb8, 0x0c, 0x8e, 0x97, 0x61, 0x56, 0x38, 0x25, 0x4c, 0x81, 0xd3, 0xfc, 0x16,
-	0x9a, 0xa8, 0x3f, 0x4e, 0xc0, 0xa2, 0xcc, 0x4e, 0x9f, 0x8e, 0xcf, 0x34, 0xc6, 0x73, 0xfa, 0x91,
-	0x78, 0x2e, 0xc3, 0xd2, 0x21, 0x82, 0x1e, 0xe3, 0x2b, 0xfc, 0x58, 0x81, 0xb9, 0x4d, 0xda, 0xb3,
-	0x07, 0xc7, 0x94, 0xde, 0x18, 0x6b, 0xa9, 0x47, 0x62, 0xed, 0x22, 0xe4, 0x25, 0x5e, 0xc9, 0xd6,
-	0xd1, 0xcf, 0x40, 0x99, 0xf2, 0x19, 0xa8, 0x7f, 0x50, 0x20, 0x5f, 0x76, 0xfb, 0x7d, 0x3b, 0x38,
-	0xa6, 0x4c, 0x1d, 0xc5, 0x99, 0x9a, 0x86, 0x13, 0x41, 0x21, 0x84, 0x29, 0x08, 0x52, 0xff, 0xa8,
-	0xc0, 0x3c, 0x71, 0x1d, 0x67, 0xc7, 0xea, 0xec, 0x3d, 0xd9, 0xd8, 0x31, 0xa0, 0x31, 0x50, 0x89,
-	0xfe, 0xaf, 0x0a, 0x14, 0x9a, 0x1e, 0x65, 0xff, 0x5f, 0x9f, 0x68, 0xf0, 0xec, 0x0f, 0x52, 0x37,
-	0x90, 0x9b, 0x83, 0x2c, 0xe1, 0x65, 0x75, 0x01, 0xe6, 0x23, 0xec, 0x92, 0x8f, 0xdf, 0x28, 0xb0,
-	0x24, 0x02, 0x44, 0x6a, 0xba, 0xc7, 0x94, 0x96, 0x10, 0x6f, 0x2a, 0x86, 0xb7, 0x04, 0x27, 0x0f,
-	0x63, 0x93, 0xb0, 0xdf, 0x4d, 0xc0, 0xa9, 0x30, 0x36, 0x8e, 0x39, 0xf0, 0x7f, 0x20, 0x1e, 0x96,
-	0xa1, 0x74, 0x94, 0x04, 0xc9, 0xd0, 0xfb, 0x09, 0x28, 0x95, 0x3d, 0x6a, 0x05, 0x34, 0xb6, 0xc9,
-	0x78, 0x72, 0x62, 0x03, 0xbf, 0x02, 0x73, 0x43, 0xcb, 0x0b, 0xec, 0x8e, 0x3d, 0xb4, 0xd8, 0xdf,
-	0xb8, 0x34, 0xdf, 0xc3, 0x1c, 0xea, 0x60, 0xc2, 0x44, 0x3d, 0x03, 0xa7, 0xa7, 0x30, 0x22, 0xf9,
-	0xfa, 0x9b, 0x02, 0xb8, 0x15, 0x58, 0x5e, 0xf0, 0x29, 0x58, 0x55, 0xa6, 0x06, 0xd3, 0x12, 0x14,
-	0x27, 0xf0, 0xc7, 0x79, 0xa1, 0xc1, 0xa7, 0x62, 0xc5, 0xb9, 0x2f, 0x2f, 0x71, 0xfc, 0x92, 0x97,
-	0xdf, 0x29, 0xb0, 0x5c, 0x76, 0xc5, 0xf9, 0xdd, 0x13, 0xf9, 0x85, 0xa9, 0x4f, 0xc3, 0x99, 0xa9,
-	0x00, 0x25, 0x01, 0xbf, 0x55, 0xe0, 0x24, 0xa1, 0x56, 0xf7, 0xc9, 0x04, 0x7f, 0x0d, 0x4e, 0x1d,
-	0x01, 0x27, 0x77, 0xa8, 0x17, 0x21, 0xd3, 0xa7, 0x81, 0xd5, 0xb5, 0x02, 0x4b, 0x42, 0x5a, 0x0e,
-	0xfb, 0x1d, 0x5b, 0xd7, 0xa4, 0x05, 0x89, 0x6c, 0xd5, 0x8f, 0x12, 0x50, 0xe4, 0x7b, 0xdd, 0xcf,
-	0xfe, 0x41, 0x4d, 0xff, 0x2f, 0xf0, 0xbe, 0x02, 0x8b, 0x93, 0x04, 0x45, 0xff, 0x09, 0xfe, 0xd9,
-	0x07, 0x11, 0x53, 0x12, 0x42, 0x72, 0xda, 0x16, 0xf4, 0x97, 0x09, 0x28, 0xc5, 0xa7, 0xf4, 0xd9,
-	0xa1, 0xc5, 0xe4, 0xa1, 0xc5, 0x27, 0x3e, 0xa5, 0xfa, 0x40, 0x81, 0xd3, 0x53, 0x08, 0xfd, 0x64,
-	0x8e, 0x8e, 0x1d, 0x5d, 0x24, 0x1e, 0x7a, 0x74, 0xf1, 0xa8, 0xae, 0xfe, 0xb5, 0x02, 0x8b, 0x35,
-	0xea, 0xfb, 0x56, 0x8f, 0x8a, 0xff, 0xf1, 0xc7, 0x37, 0x9b, 0xf1, 0x43, 0xe1, 0xd4, 0xf8, 0x66,
-	0x45, 0x2d, 0xc3, 0xd2, 0x21, 0x68, 0x8f, 0x71, 0x36, 0xf1, 0x17, 0x05, 0x16, 0x64, 0x2f, 0xda,
-	0xb1, 0xdd, 0x08, 0x4c, 0x61, 0x07, 0x3f, 0x03, 0x49, 0xbb, 0x1b, 0xee, 0x20, 0x27, 0xef, 0x9a,
-	0x99, 0x42, 0xbd, 0x0c, 0x38, 0x8e, 0xfb, 0x31, 0xa8, 0xfb, 0x55, 0x12, 0x16, 0x5a, 0x43, 0xc7,
-	0x0e, 0xa4, 0xf2, 0xc9, 0x4e, 0xfc, 0xff, 0x02, 0x73, 0x3e, 0x03, 0x6b, 0x8a, 0xdb, 0x32, 0x4e,
-	0x6c, 0x96, 0xe4, 0xb8, 0xac, 0xcc, 0x45, 0xf8, 0x59, 0xc8, 0x85, 0x26, 0xa3, 0x41, 0x20, 0x4f,
-	0x3a, 0x41, 0x5a, 0x8c, 0x06, 0x01, 0xbe, 0x00, 0xa7, 0x06, 0xa3, 0x3e, 0xbf, 0x39, 0x36, 0x87,
-	0xd4, 0x0b, 0xef, 0x55, 0x2d, 0x2f, 0xbc, 0xe1, 0x2d, 0x0e, 0x46, 0x7d, 0xe2, 0xee, 0xfb, 0x4d,
-	0xea, 0x89, 0x7b, 0x55, 0xcb, 0x0b, 0xf0, 0x65, 0xc8, 0x5a, 0x4e, 0xcf, 0xf5, 0xec, 0x60, 0xb7,
-	0x2f, 0xaf, 0x76, 0xd5, 0xf0, 0x6a, 0xe5, 0x30, 0xfd, 0xeb, 0x5a, 0x68, 0x49, 0xc6, 0x8d, 0xd4,
-	0x97, 0x21, 0x1b, 0xc9, 0x31, 0x82, 0x39, 0xfd, 0x5a, 0x5b, 0xab, 0x9a, 0xad, 0x66, 0xb5, 0x62,
-	0xb4, 0xc4, 0x75, 0xec, 0x76, 0xbb, 0x5a, 0x35, 0x5b, 0x65, 0xad, 0x8e, 0x14, 0x95, 0x00, 0xf0,
-	0x2e, 0x79, 0xe7, 0x63, 0x82, 0x94, 0x87, 0x10, 0x74, 0x06, 0xb2, 0x9e, 0xbb, 0x2f, 0xb1, 0x27,
-	0x38, 0x9c, 0x8c, 0xe7, 0xee, 0x73, 0xe4, 0xaa, 0x06, 0x38, 0x3e, 0x57, 0x19, 0x6d, 0xb1, 0xe4,
-	0xad, 0x4c, 0x24, 0xef, 0xf1, 0xf8, 0x51, 0xf2, 0x16, 0x5b, 0x79, 0xf6, 0x9d, 0xbf, 0x41, 0x2d,
-	0x27, 0x08, 0xd7, 0x2b, 0xf5, 0xa7, 0x09, 0xc8, 0x13, 0x26, 0xb1, 0xfb, 0xb4, 0x15, 0x58, 0x81,
-	0xcf, 0x3c, 0xb5, 0xcb, 0x4d, 0xcc, 0x71, 0xda, 0xcd, 0x92, 0x9c, 0x90, 0x89, 0x4b, 0x80, 0x0d,
-	0x58, 0xf2, 0x69, 0xc7, 0x1d, 0x74, 0x7d, 0x73, 0x87, 0xee, 0xda, 0x83, 0xae, 0xd9, 0xb7, 0xfc,
-	0x40, 0xde, 0x14, 0xe6, 0x49, 0x51, 0x2a, 0x37, 0xb9, 0xae, 0xc6, 0x55, 0xf8, 0x1c, 0x2c, 0xee,
-	0xd8, 0x03, 0xc7, 0xed, 0x99, 0x43, 0xc7, 0x3a, 0xa0, 0x9e, 0x2f, 0xa1, 0xb2, 0xf0, 0x4a, 0x13,
-	0x2c, 0x74, 0x4d, 0xa1, 0x12, 0xee, 0x7e, 0x1b, 0xd6, 0xa6, 0x8e, 0x62, 0xde, 0xb2, 0x9d, 0x80,
-	0x7a, 0xb4, 0x6b, 0x7a, 0x74, 0xe8, 0xd8, 0x1d, 0x71, 0x69, 0x2f, 0xf6, 0xee, 0x2f, 0x4c, 0x19,
-	0x7a, 0x5b, 0x9a, 0x93, 0xb1, 0x35, 0x63, 0xbb, 0x33, 0x1c, 0x99, 0x23, 0xf6, 0x01, 0xf3, 0x55,
-	0x4c, 0x21, 0x99, 0xce, 0x70, 0xd4, 0x66, 0x75, 0x8c, 0x20, 0x79, 0x7b, 0x28, 0x16, 0x2f, 0x85,
-	0xb0, 0xa2, 0xfa, 0xb1, 0x02, 0x05, 0xad, 0xd7, 0xf3, 0x68, 0xcf, 0x0a, 0x24, 0x4d, 0xe7, 0x60,
-	0x51, 0x50, 0x72, 0x60, 0xca, 0xd7, 0x40, 0x02, 0x8f, 0x22, 0xf0, 0x48, 0x9d, 0x78, 0x0b, 0x14,
-	0x86, 0xef, 0xc9, 0xd1, 0x60, 0x6a, 0x9b, 0x04, 0x6f, 0xb3, 0x18, 0x69, 0xe3, 0xad, 0xfe, 0x0b,
-	0x4e, 0x4f, 0x67, 0xa1, 0x6f, 0x8b, 0xf7, 0x1c, 0x79, 0x72, 0x72, 0x0a, 0xe8, 0x9a, 0x3d, 0x78,
-	0x40, 0x53, 0xeb, 0x2e, 0xe7, 0xeb, 0x3e, 0x4d, 0xad, 0xbb, 0xea, 0xef, 0xa3, 0xa3, 0xfd, 0x30,
-	0x5c, 0xa2, 0xd5, 0x38, 0xcc, 0x0b, 0xca, 0x83, 0xf2, 0x42, 0x09, 0x66, 0x7d, 0xea, 0xdd, 0xb1,
-	0x07, 0xbd, 0xf0, 0xf6, 0x58, 0x56, 0x71, 0x0b, 0x5e, 0x90, 0xd8, 0xe9, 0xdd, 0x80, 0x7a, 0x03,
-	0xcb, 0x71, 0x0e, 0x4c, 0x71, 0x50, 0x31, 0x08, 0x68, 0xd7, 0x1c, 0xbf, 0x5d, 0x12, 0x2b, 0xf2,
-	0x73, 0xc2, 0x5a, 0x8f, 0x8c, 0x49, 0x64, 0x6b, 0x44, 0xaf, 0x9a, 0x5e, 0x83, 0x82, 0x27, 0x83,
-	0xd8, 0xf4, 0x99, 0x7b, 0x64, 0x3e, 0x5a, 0x8c, 0xae, 0x80, 0x63, 0x11, 0x4e, 0xf2, 0xde, 0x44,
-	0xc0, 0xbf, 0x0e, 0xf3, 0x56, 0xe8, 0x5b, 0xd9, 0x7a, 0x72, 0xdf, 0x32, 0xe9, 0x79, 0x52, 0xb0,
-	0x26, 0x23, 0xe1, 0x12, 0xcc, 0x49, 0x44, 0x96, 0x63, 0x5b, 0xe3, 0x8d, 0xed, 0xa1, 0x07, 0x61,
-	0x1a, 0x53, 0x12, 0xf9, 0x74, 0x8c, 0x57, 0xd8, 0xff, 0xe8, 0x62, 0x7b, 0xd8, 0xe5, 0x3d, 0x1d,
-	0xe3, 0xdd, 0x45, 0xfc, 0xf5, 0x58, 0x6a, 0xf2, 0xf5, 0xd8, 0xe4, 0x6b, 0xb4, 0xf4, 0xa1, 0xd7,
-	0x68, 0xea, 0x65, 0x58, 0x9c, 0xc4, 0x2f, 0xa3, 0x6c, 0x15, 0xd2, 0xfc, 0xa6, 0xfc, 0xd0, 0x32,
-	0x1a, 0xbb, 0x0a, 0x27, 0xc2, 0x40, 0xfd, 0x99, 0x02, 0xc5, 0x29, 0x7f, 0xb1, 0xa2, 0xff, 0x6f,
-	0x4a, 0xec, 0x78, 0xe8, 0xdf, 0x20, 0xcd, 0xef, 0xec, 0xe5, 0x63, 0x92, 0x53, 0x47, 0xff, 0xa1,
-	0xf1, 0xfb, 0x75, 0x22, 0xac, 0x58, 0x22, 0xe4, 0x01, 0xd5, 0xe1, 0xe7, 0x43, 0xe1, 0x0e, 0x31,
-	0xc7, 0x64, 0xe2, 0xc8, 0xe8, 0xe8, 0x81, 0x53, 0xea, 0xa1, 0x07, 0x4e, 0x6b, 0xdf, 0x4a, 0x42,
-	0xb6, 0x76, 0xd0, 0xba, 0xed, 0x6c, 0x3b, 0x56, 0x8f, 0x5f, 0x80, 0xd7, 0x9a, 0xc6, 0x4d, 0x74,
-	0x02, 0x2f, 0x40, 0xbe, 0xde, 0x30, 0xcc, 0x3a, 0x5b, 0x4a, 0xb6, 0xab, 0xda, 0x15, 0xa4, 0xb0,
-	0xb5, 0xa6, 0x49, 0x2a, 0xe6, 0x55, 0xfd, 0xa6, 0x90, 0x24, 0x70, 0x11, 0xe6, 0xdb, 0xf5, 0xca,
-	0xb5, 0xb6, 0x3e, 0x16, 0xa6, 0xf0, 0x12, 0x2c, 0xd4, 0xda, 0x55, 0xa3, 0xd2, 0xac, 0xc6, 0xc4,
-	0x19, 0xb6, 0x2e, 0x6d, 0x56, 0x1b, 0x9b, 0xa2, 0x8a, 0x58, 0xff, 0xed, 0x7a, 0xab, 0x72, 0xa5,
-	0xae, 0x6f, 0x09, 0xd1, 0x0a, 0x13, 0xbd, 0xad, 0x93, 0xc6, 0x76, 0x25, 0x1c, 0xf2, 0x32, 0x46,
-	0x90, 0xdb, 0xac, 0xd4, 0x35, 0x22, 0x7b, 0xb9, 0xa7, 0xe0, 0x02, 0x64, 0xf5, 0x7a, 0xbb, 0x26,
-	0xeb, 0x09, 0x5c, 0x82, 0xa2, 0xd6, 0x36, 0x1a, 0x66, 0xa5, 0x5e, 0x26, 0x7a, 0x4d, 0xaf, 0x1b,
-	0x52, 0x93, 0xc2, 0x45, 0x28, 0x18, 0x95, 0x9a, 0xde, 0x32, 0xb4, 0x5a, 0x53, 0x0a, 0xd9, 0x2c,
-	0x32, 0x2d, 0x3d, 0xb4, 0x41, 0x78, 0x19, 0x96, 0xea, 0x0d, 0x53, 0x3e, 0x2a, 0x32, 0xaf, 0x6b,
-	0xd5, 0xb6, 0x2e, 0x75, 0x2b, 0xf8, 0x14, 0xe0, 0x46, 0xdd, 0x6c, 0x37, 0xb7, 0x34, 0x43, 0x37,
-	0xeb, 0x8d, 0x1b, 0x52, 0x71, 0x19, 0x17, 0x20, 0x33, 0x9e, 0xc1, 0x3d, 0xc6, 0x42, 0xbe, 0xa9,
-	0x11, 0x63, 0x0c, 0xf6, 0xde, 0x3d, 0x46, 0x16, 0x5c, 0x21, 0x8d, 0x76, 0x73, 0x6c, 0xb6, 0x00,
-	0x39, 0x49, 0x96, 0x14, 0xa5, 0x98, 0x68, 0xb3, 0x52, 0x2f, 0x47, 0xf3, 0xbb, 0x97, 0x59, 0x4e,
-	0x20, 0x65, 0x6d, 0x0f, 0x52, 0xdc, 0x1d, 0x19, 0x48, 0xd5, 0x1b, 0x75, 0x1d, 0x9d, 0xc0, 0xf3,
-	0x00, 0x95, 0x56, 0xa5, 0x6e, 0xe8, 0x57, 0x88, 0x56, 0x65, 0xb0, 0xb9, 0x20, 0x24, 0x90, 0xa1,
-	0x9d, 0x83, 0xd9, 0x4a, 0x6b, 0xbb, 0xda, 0xd0, 0x0c, 0x09, 0xb3, 0xd2, 0xba, 0xd6, 0x6e, 0x18,
-	0x4c, 0x89, 0x70, 0x0e, 0x66, 0x2a, 0x2d, 0x43, 0x7f, 0xcb, 0x60, 0xb8, 0xb8, 0x4e, 0xb0, 0x8a,
-	0xee, 0x5d, 0x5e, 0xfb, 0x30, 0x09, 0x29, 0xfe, 0x22, 0x34, 0x0f, 0x59, 0xee, 0x6d, 0xe3, 0x66,
-	0x93, 0x0d, 0x99, 0x85, 0x54, 0xa5, 0x6e, 0x5c, 0x42, 0xff, 0x97, 0xc0, 0x00, 0xe9, 0x36, 0x2f,
-	0xff, 0xff, 0x0c, 0x2b, 0x57, 0xea, 0xc6, 0x2b, 0x17, 0xd1, 0xbb, 0x09, 0xd6, 0x6d, 0x5b, 0x54,
-	0x3e, 0x17, 0x2a, 0x36, 0x2e, 0xa0, 0xf7, 0x22, 0xc5, 0xc6, 0x05, 0xf4, 0xf9, 0x50, 0x71, 0x7e,
-	0x03, 0x7d, 0x21, 0x52, 0x9c, 0xdf, 0x40, 0x5f, 0x0c, 0x15, 0x17, 0x2f, 0xa0, 0x2f, 0x45, 0x8a,
-	0x8b, 0x17, 0xd0, 0x97, 0x67, 0x18, 0x16, 0x8e, 0xe4, 0xfc, 0x06, 0xfa, 0x4a, 0x26, 0xaa, 0x5d,
-	0xbc, 0x80, 0xbe, 0x9a, 0x61, 0xfe, 0x8f, 0xbc, 0x8a, 0xbe, 0x86, 0xd8, 0x34, 0x99, 0x83, 0xd0,
-	0xd7, 0x79, 0x91, 0xa9, 0xd0, 0x37, 0x10, 0xc3, 0xc8, 0xa4, 0xbc, 0xfa, 0x3e, 0xd7, 0xdc, 0xd4,
-	0x35, 0x82, 0xbe, 0x39, 0x23, 0xde, 0x90, 0x95, 0x2b, 0x35, 0xad, 0x8a, 0x30, 0x6f, 0xc1, 0x58,
-	0xf9, 0xf6, 0x39, 0x56, 0x64, 0xe1, 0x89, 0xbe, 0xd3, 0x64, 0x03, 0x5e, 0xd7, 0x48, 0xf9, 0x0d,
-	0x8d, 0xa0, 0xef, 0x9e, 0x63, 0x03, 0x5e, 0xd7, 0x88, 0xe4, 0xeb, 0x7b, 0x4d, 0x66, 0xc8, 0x55,
-	0x1f, 0x9c, 0x63, 0x93, 0x96, 0xf2, 0xef, 0x37, 0x71, 0x06, 0x92, 0x9b, 0x15, 0x03, 0x7d, 0xc8,
-	0x47, 0x63, 0x21, 0x8a, 0x7e, 0x80, 0x98, 0xb0, 0xa5, 0x1b, 0xe8, 0x87, 0x4c, 0x98, 0x36, 0xda,
-	0xcd, 0xaa, 0x8e, 0x9e, 0x62, 0x93, 0xbb, 0xa2, 0x37, 0x6a, 0xba, 0x41, 0x6e, 0xa2, 0x1f, 0x71,
-	0xf3, 0x37, 0x5b, 0x8d, 0x3a, 0xfa, 0x08, 0xe1, 0x02, 0x80, 0xfe, 0x56, 0x93, 0xe8, 0xad, 0x56,
-	0xa5, 0x51, 0x47, 0xcf, 0xae, 0x6d, 0x03, 0x3a, 0x9c, 0x0e, 0x18, 0x80, 0x76, 0xfd, 0x6a, 0xbd,
-	0x71, 0xa3, 0x8e, 0x4e, 0xb0, 0x4a, 0x93, 0xe8, 0x4d, 0x8d, 0xe8, 0x48, 0xc1, 0x00, 0x33, 0xe2,
-	0x85, 0x1b, 0x4a, 0xe0, 0x39, 0xc8, 0x90, 0x46, 0xb5, 0xba, 0xa9, 0x95, 0xaf, 0xa2, 0xe4, 0xe6,
-	0x02, 0xcc, 0xdb, 0xee, 0xfa, 0x1d, 0x3b, 0xa0, 0xbe, 0x2f, 0xde, 0x1c, 0xef, 0xcc, 0xf0, 0x9f,
-	0xf3, 0x7f, 0x0f, 0x00, 0x00, 0xff, 0xff, 0xde, 0xe3, 0xd2, 0x1e, 0xad, 0x2c, 0x00, 0x00,
+	// 3476 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x5b, 0xcd, 0x73, 0x23, 0x49,
+	0x56, 0xef, 0x2a, 0x7d, 0x58, 0x7a, 0xb2, 0xe4, 0x72, 0xda, 0xee, 0xd6, 0x78, 0x66, 0x67, 0x4c,
+	0xcd, 0xce, 0x4e, 0x8f, 0x77, 0x31, 0x3d, 0x9e, 0xde, 0xa6, 0x99, 0x65, 0x96, 0x2e, 0x4b, 0xe5,
+	0x5e, 0x6d, 0x4b, 0x25, 0x75, 0xaa, 0xd4, 0xb3, 0x3d, 0xb1, 0x11, 0x15, 0x65, 0x29, 0x2d, 0x57,
+	0xb8, 0xa4, 0x52, 0x57, 0xa5, 0xda, 0xed, 0x5b, 0xc3, 0xb2, 0x7c, 0xb3, 0x0c, 0x9f, 0xc3, 0x40,
+	0x30, 0x10, 0xc1, 0x9d, 0x03, 0x7f, 0x01, 0xc1, 0x1f, 0xc0, 0x6d, 0x0f, 0x04, 0x07, 0x82, 0x20,
+	0x08, 0x6e, 0x04, 0x27, 0x0e, 0x1c, 0x08, 0x22, 0x3f, 0xaa, 0x54, 0xb2, 0xd5, 0x9f, 0x70, 0x69,
+	0xcf, 0x9c, 0x94, 0xf9, 0xde, 0xcb, 0x8f, 0xf7, 0x7b, 0x2f, 0x5f, 0x66, 0x65, 0x3e, 0x41, 0xe9,
+	0xc1, 0x94, 0x84, 0xa7, 0x3b, 0x93, 0x30, 0xa0, 0x01, 0xca, 0xf1, 0xca, 0x66, 0x85, 0x06, 0x93,
+	0x60, 0xe0, 0x52, 0x57, 0x90, 0x37, 0x4b, 0x0f, 0x69, 0x38, 0xe9, 0x8b, 0x8a, 0xfe, 0x63, 0x05,
+	0xf2, 0xb6, 0x1b, 0x0e, 0x09, 0x45, 0x9b, 0x50, 0x38, 0x26, 0xa7, 0xd1, 0xc4, 0xed, 0x93, 0xaa,
+	0xb2, 0xa5, 0x5c, 0x2d, 0xe2, 0xa4, 0x8e, 0xd6, 0x21, 0x17, 0x1d, 0xb9, 0xe1, 0xa0, 0xaa, 0x72,
+	0x86, 0xa8, 0xa0, 0x6f, 0x43, 0x89, 0xba, 0x07, 0x3e, 0xa1, 0x0e, 0x3d, 0x9d, 0x90, 0x6a, 0x66,
+	0x4b, 0xb9, 0x5a, 0xd9, 0x5d, 0xdf, 0x49, 0xc6, 0xb3, 0x39, 0xd3, 0x3e, 0x9d, 0x10, 0x0c, 0x34,
+	0x29, 0x23, 0x04, 0xd9, 0x3e, 0xf1, 0xfd, 0x6a, 0x96, 0xf7, 0xc5, 0xcb, 0x7a, 0x1d, 0x2a, 0xf7,
+	0xec, 0xdb, 0x2e, 0x25, 0x35, 0xd7, 0xf7, 0x49, 0xd8, 0xa8, 0xb3, 0xe9, 0x4c, 0x23, 0x12, 0x8e,
+	0xdd, 0x51, 0x32, 0x9d, 0xb8, 0x8e, 0x2e, 0x43, 0x7e, 0x18, 0x06, 0xd3, 0x49, 0x54, 0x55, 0xb7,
+	0x32, 0x57, 0x8b, 0x58, 0xd6, 0xf4, 0x1f, 0x02, 0x98, 0x0f, 0xc9, 0x98, 0xda, 0xc1, 0x31, 0x19,
+	0xa3, 0x37, 0xa0, 0x48, 0xbd, 0x11, 0x89, 0xa8, 0x3b, 0x9a, 0xf0, 0x2e, 0x32, 0x78, 0x46, 0x78,
+	0x82, 0x4a, 0x9b, 0x50, 0x98, 0x04, 0x91, 0x47, 0xbd, 0x60, 0xcc, 0xf5, 0x29, 0xe2, 0xa4, 0xae,
+	0x7f, 0x17, 0x72, 0xf7, 0x5c, 0x7f, 0x4a, 0xd0, 0x5b, 0x90, 0xe5, 0x0a, 0x2b, 0x5c, 0xe1, 0xd2,
+	0x8e, 0x00, 0x9d, 0xeb, 0xc9, 0x19, 0xac, 0xef, 0x87, 0x4c, 0x92, 0xf7, 0xbd, 0x8c, 0x45, 0x45,
+	0x3f, 0x86, 0xe5, 0x3d, 0x6f, 0x3c, 0xb8, 0xe7, 0x86, 0x1e, 0x03, 0xe3, 0x25, 0xbb, 0x41, 0x5f,
+	0x87, 0x3c, 0x2f, 0x44, 0xd5, 0xcc, 0x56, 0xe6, 0x6a, 0x69, 0x77, 0x59, 0x36, 0xe4, 0x73, 0xc3,
+	0x92, 0xa7, 0xff, 0xbd, 0x02, 0xb0, 0x17, 0x4c, 0xc7, 0x83, 0xbb, 0x8c, 0x89, 0x34, 0xc8, 0x44,
+	0x0f, 0x7c, 0x09, 0x24, 0x2b, 0xa2, 0x3b, 0x50, 0x39, 0xf0, 0xc6, 0x03, 0xe7, 0xa1, 0x9c, 0x8e,
+	0xc0, 0xb2, 0xb4, 0xfb, 0x75, 0xd9, 0xdd, 0xac, 0xf1, 0x4e, 0x7a, 0xd6, 0x91, 0x39, 0xa6, 0xe1,
+	0x29, 0x2e, 0x1f, 0xa4, 0x69, 0x9b, 0x3d, 0x40, 0xe7, 0x85, 0xd8, 0xa0, 0xc7, 0xe4, 0x34, 0x1e,
+	0xf4, 0x98, 0x9c, 0xa2, 0xf7, 0xd2, 0x1a, 0x95, 0x76, 0xd7, 0xe2, 0xb1, 0x52, 0x6d, 0xa5, 0x9a,
+	0x1f, 0xaa, 0x37, 0x15, 0xfd, 0xa7, 0x79, 0xa8, 0x98, 0x8f, 0x48, 0x7f, 0x4a, 0x49, 0x7b, 0xc2,
+	0x6c, 0x10, 0xa1, 0x1d, 0x58, 0xf3, 0xc6, 0x7d, 0x7f, 0x3a, 0x20, 0x0e, 0x61, 0xa6, 0x76, 0x28,
+	0xb3, 0x35, 0xef, 0xaf, 0x80, 0x57, 0x25, 0x2b, 0xe5, 0x04, 0x06, 0xac, 0xf5, 0x83, 0xd1, 0xc4,
+	0x0d, 0xe7, 0xe5, 0x33, 0x7c, 0xfc, 0x55, 0x39, 0xfe, 0x4c, 0x1e, 0xaf, 0x4a, 0xe9, 0x54, 0x17,
+	0x2d, 0x58, 0x91, 0xfd, 0x0e, 0x9c, 0x43, 0x8f, 0xf8, 0x83, 0x88, 0xbb, 0x6e, 0x25, 0x81, 0x6a,
+	0x7e, 0x8a, 0x3b, 0x0d, 0x29, 0xbc, 0xcf, 0x65, 0x71, 0xc5, 0x9b, 0xab, 0xa3, 0x6d, 0x58, 0xed,
+	0xfb, 0x1e, 0x9b, 0xca, 0x21, 0x83, 0xd8, 0x09, 0x83, 0x93, 0xa8, 0x9a, 0xe3, 0xf3, 0x5f, 0x11,
+	0x8c, 0x7d, 0x46, 0xc7, 0xc1, 0x49, 0x84, 0x3e, 0x84, 0xc2, 0x49, 0x10, 0x1e, 0xfb, 0x81, 0x3b,
+	0xa8, 0xe6, 0xf9, 0x98, 0x6f, 0x2e, 0x1e, 0xf3, 0x63, 0x29, 0x85, 0x13, 0x79, 0x74, 0x15, 0xb4,
+	0xe8, 0x81, 0xef, 0x44, 0xc4, 0x27, 0x7d, 0xea, 0xf8, 0xde, 0xc8, 0xa3, 0xd5, 0x02, 0x5f, 0x05,
+	0x95, 0xe8, 0x81, 0xdf, 0xe5, 0xe4, 0x26, 0xa3, 0x22, 0x07, 0x36, 0x68, 0xe8, 0x8e, 0x23, 0xb7,
+	0xcf, 0x3a, 0x73, 0xbc, 0x28, 0xf0, 0x5d, 0x56, 0xaa, 0x16, 0xf9, 0x90, 0xdb, 0x8b, 0x87, 0xb4,
+	0x67, 0x4d, 0x1a, 0x71, 0x0b, 0xbc, 0x4e, 0x17, 0x50, 0xd1, 0xfb, 0xb0, 0x11, 0x1d, 0x7b, 0x13,
+	0x87, 0xf7, 0xe3, 0x4c, 0x7c, 0x77, 0xec, 0xf4, 0xdd, 0xfe, 0x11, 0xa9, 0x02, 0x57, 0x1b, 0x31,
+	0x26, 0x77, 0xb5, 0x8e, 0xef, 0x8e, 0x6b, 0x8c, 0x83, 0xde, 0x83, 0xd5, 0x13, 0xd7, 0x63, 0x18,
+	0x85, 0xce, 0x90, 0x7a, 0x03, 0x27, 0x22, 0xb4, 0x5a, 0xe2, 0x9e, 0x54, 0x61, 0x8c, 0xfd, 0x20,
+	0xbc, 0x4d, 0xbd, 0x41, 0x97, 0x50, 0xf4, 0x11, 0xbc, 0x71, 0x4e, 0xd4, 0x61, 0x0b, 0x3d, 0x98,
+	0x52, 0x67, 0x14, 0x55, 0x97, 0xb9, 0xd2, 0x57, 0xe6, 0x5b, 0xd9, 0x82, 0xdf, 0x8a, 0xf4, 0xef,
+	0x40, 0x65, 0xde, 0x62, 0x68, 0x15, 0xca, 0xf6, 0xfd, 0x8e, 0xe9, 0x18, 0x56, 0xdd, 0xb1, 0x8c,
+	0x96, 0xa9, 0x5d, 0x42, 0x65, 0x28, 0x72, 0x52, 0xdb, 0x6a, 0xde, 0xd7, 0x14, 0xb4, 0x04, 0x19,
+	0xa3, 0xd9, 0xd4, 0x54, 0xfd, 0x26, 0x14, 0x62, 0xe8, 0xd1, 0x0a, 0x94, 0x7a, 0x56, 0xb7, 0x63,
+	0xd6, 0x1a, 0xfb, 0x0d, 0xb3, 0xae, 0x5d, 0x42, 0x05, 0xc8, 0xb6, 0x9b, 0x76, 0x47, 0x53, 0x44,
+	0xc9, 0xe8, 0x68, 0x2a, 0x6b, 0x59, 0xdf, 0x33, 0xb4, 0x8c, 0x4e, 0x61, 0x7d, 0x11, 0x82, 0xa8,
+	0x04, 0x4b, 0x75, 0x73, 0xdf, 0xe8, 0x35, 0x6d, 0xed, 0x12, 0x5a, 0x83, 0x15, 0x6c, 0x76, 0x4c,
+	0xc3, 0x36, 0xf6, 0x9a, 0xa6, 0x83, 0x4d, 0xa3, 0xae, 0x29, 0x08, 0x41, 0x85, 0x95, 0x9c, 0x5a,
+	0xbb, 0xd5, 0x6a, 0xd8, 0xb6, 0x59, 0xd7, 0x54, 0xb4, 0x0e, 0x1a, 0xa7, 0xf5, 0xac, 0x19, 0x35,
+	0x83, 0x34, 0x58, 0xee, 0x9a, 0xb8, 0x61, 0x34, 0x1b, 0x9f, 0xb0, 0x0e, 0xb4, 0xec, 0xf7, 0xb3,
+	0x05, 0x45, 0x53, 0xf5, 0xcf, 0x54, 0xc8, 0x71, 0x5d, 0x59, 0x2c, 0x4e, 0x45, 0x58, 0x5e, 0x4e,
+	0xe2, 0x92, 0xfa, 0x94, 0xb8, 0xc4, 0xc3, 0xb9, 0x8c, 0x90, 0xa2, 0x82, 0x5e, 0x87, 0x62, 0x10,
+	0x0e, 0x1d, 0xc1, 0x11, 0xb1, 0xbd, 0x10, 0x84, 0x43, 0xbe, 0x09, 0xb0, 0xb8, 0xca, 0xb6, 0x84,
+	0x03, 0x37, 0x22, 0xdc, 0xd7, 0x8b, 0x38, 0xa9, 0xa3, 0xd7, 0x80, 0xc9, 0x39, 0x7c, 0x1e, 0x79,
+	0xce, 0x5b, 0x0a, 0xc2, 0xa1, 0xc5, 0xa6, 0xf2, 0x36, 0x94, 0xfb, 0x81, 0x3f, 0x1d, 0x8d, 0x1d,
+	0x9f, 0x8c, 0x87, 0xf4, 0xa8, 0xba, 0xb4, 0xa5, 0x5c, 0x2d, 0xe3, 0x65, 0x41, 0x6c, 0x72, 0x1a,
+	0xaa, 0xc2, 0x52, 0xff, 0xc8, 0x0d, 0x23, 0x22, 0xfc, 0xbb, 0x8c, 0xe3, 0x2a, 0x1f, 0x95, 0xf4,
+	0xbd, 0x91, 0xeb, 0x47, 0xdc, 0x97, 0xcb, 0x38, 0xa9, 0x33, 0x25, 0x0e, 0x7d, 0x77, 0x18, 0x71,
+	0x1f, 0x2c, 0x63, 0x51, 0xd1, 0x7f, 0x1e, 0x32, 0x38, 0x38, 0x61, 0x5d, 0x8a, 0x01, 0xa3, 0xaa,
+	0xb2, 0x95, 0xb9, 0x8a, 0x70, 0x5c, 0x65, 0x5b, 0x8f, 0x8c, 0xbe, 0x22, 0x28, 0xc7, 0xf1, 0xf6,
+	0x87, 0xb0, 0x8c, 0x49, 0x34, 0xf5, 0xa9, 0xf9, 0x88, 0x86, 0x6e, 0x84, 0x76, 0xa1, 0x94, 0x8e,
+	0x37, 0xca, 0x93, 0xe2, 0x0d, 0x90, 0xa4, 0xcc, 0x46, 0x3d, 0x0c, 0x49, 0x74, 0x44, 0x42, 0x19,
+	0xcf, 0xe2, 0x2a, 0x8b, 0xe6, 0x25, 0xbe, 0x40, 0xc4, 0x18, 0x6c, 0x0f, 0x90, 0x91, 0x48, 0x99,
+	0xdb, 0x03, 0xb8, 0x51, 0xb1, 0xe4, 0x31, 0xf4, 0x58, 0x70, 0x71, 0xdc, 0xc3, 0x43, 0xd2, 0xa7,
+	0x44, 0x6c, 0x75, 0x59, 0xbc, 0xcc, 0x88, 0x86, 0xa4, 0x31, 0xb3, 0x79, 0xe3, 0x88, 0x84, 0xd4,
+	0xf1, 0x06, 0xdc, 0xa0, 0x59, 0x5c, 0x10, 0x84, 0xc6, 0x00, 0xbd, 0x09, 0x59, 0x1e, 0x9e, 0xb2,
+	0x7c, 0x14, 0x90, 0xa3, 0xe0, 0xe0, 0x04, 0x73, 0x3a, 0xfa, 0x26, 0xe4, 0x09, 0xd7, 0xb7, 0x9a,
+	0x9b, 0x0b, 0xe8, 0x69, 0x28, 0xb0, 0x14, 0xd1, 0xff, 0x2a, 0x03, 0xa5, 0x2e, 0x0d, 0x89, 0x3b,
+	0xe2, 0xfa, 0xa3, 0x5f, 0x04, 0x88, 0xa8, 0x4b, 0xc9, 0x88, 0x8c, 0x69, 0xac, 0xc8, 0x1b, 0xb2,
+	0x83, 0x94, 0xdc, 0x4e, 0x37, 0x16, 0xc2, 0x29, 0xf9, 0xb3, 0x00, 0xab, 0xcf, 0x01, 0xf0, 0xe6,
+	0x17, 0x2a, 0x14, 0x93, 0xde, 0x90, 0x01, 0x85, 0xbe, 0x4b, 0xc9, 0x30, 0x08, 0x4f, 0xe5, 0x1e,
+	0xfc, 0xce, 0xd3, 0x46, 0xdf, 0xa9, 0x49, 0x61, 0x9c, 0x34, 0x43, 0x5f, 0x03, 0x71, 0xb0, 0x11,
+	0xce, 0x2b, 0x4e, 0x12, 0x45, 0x4e, 0xe1, 0xee, 0xfb, 0x21, 0xa0, 0x49, 0xe8, 0x8d, 0xdc, 0xf0,
+	0xd4, 0x39, 0x26, 0xa7, 0xf1, 0xe6, 0x91, 0x59, 0x60, 0x32, 0x4d, 0xca, 0xdd, 0x21, 0xa7, 0x32,
+	0x08, 0xdd, 0x9c, 0x6f, 0x2b, 0x9d, 0xee, 0xbc, 0x21, 0x52, 0x2d, 0xf9, 0x09, 0x20, 0x8a, 0xf7,
+	0xfa, 0x1c, 0xf7, 0x4f, 0x56, 0xd4, 0xdf, 0x85, 0x42, 0x3c, 0x79, 0x54, 0x84, 0x9c, 0x19, 0x86,
+	0x41, 0xa8, 0x5d, 0xe2, 0xb1, 0xa8, 0xd5, 0x14, 0xe1, 0xac, 0x5e, 0x67, 0xe1, 0xec, 0xef, 0xd4,
+	0x64, 0xc3, 0xc5, 0xe4, 0xc1, 0x94, 0x44, 0x14, 0xfd, 0x12, 0xac, 0x11, 0xee, 0x2b, 0xde, 0x43,
+	0xe2, 0xf4, 0xf9, 0xe9, 0x8c, 0x79, 0x8a, 0x70, 0xe8, 0x95, 0x1d, 0x71, 0x98, 0x8c, 0x4f, 0x6d,
+	0x78, 0x35, 0x91, 0x95, 0xa4, 0x01, 0x32, 0x61, 0xcd, 0x1b, 0x8d, 0xc8, 0xc0, 0x73, 0x69, 0xba,
+	0x03, 0x61, 0xb0, 0x8d, 0xf8, 0xf0, 0x32, 0x77, 0xf8, 0xc3, 0xab, 0x49, 0x8b, 0xa4, 0x9b, 0x77,
+	0x20, 0x4f, 0xf9, 0x41, 0x55, 0xee, 0xdd, 0xe5, 0x38, 0x2e, 0x71, 0x22, 0x96, 0x4c, 0xf4, 0x2e,
+	0x88, 0x63, 0x2f, 0x8f, 0x40, 0x33, 0x87, 0x98, 0x9d, 0x66, 0xb0, 0xe0, 0xa3, 0x77, 0xa0, 0x32,
+	0xb7, 0xe9, 0x0d, 0x38, 0x60, 0x19, 0x5c, 0x4e, 0x51, 0x1b, 0x03, 0xf4, 0x73, 0xb0, 0x14, 0x88,
+	0x0d, 0xaf, 0x9a, 0x9f, 0x9b, 0xf1, 0xfc, 0x6e, 0x88, 0x63, 0x29, 0xfd, 0x23, 0x58, 0x49, 0x10,
+	0x8c, 0x26, 0xc1, 0x38, 0x22, 0x68, 0x1b, 0xf2, 0x21, 0x5f, 0x10, 0x12, 0x35, 0x24, 0xbb, 0x48,
+	0xad, 0x68, 0x2c, 0x25, 0xf4, 0x01, 0xac, 0x08, 0xca, 0xc7, 0x1e, 0x3d, 0xe2, 0x86, 0x42, 0xef,
+	0x40, 0x8e, 0xb0, 0xc2, 0x19, 0xcc, 0x71, 0xa7, 0xc6, 0xf9, 0x58, 0x70, 0x53, 0xa3, 0xa8, 0xcf,
+	0x1c, 0xe5, 0x3f, 0x55, 0x58, 0x93, 0xb3, 0xdc, 0x73, 0x69, 0xff, 0xe8, 0x15, 0x35, 0xf6, 0x37,
+	0x61, 0x89, 0xd1, 0xbd, 0x64, 0x61, 0x2c, 0x30, 0x77, 0x2c, 0xc1, 0x0c, 0xee, 0x46, 0x4e, 0xca,
+	0xba, 0xf2, 0xd0, 0x55, 0x76, 0xa3, 0xd4, 0x46, 0xbc, 0xc0, 0x2f, 0xf2, 0xcf, 0xf0, 0x8b, 0xa5,
+	0xe7, 0xf2, 0x8b, 0x3a, 0xac, 0xcf, 0x23, 0x2e, 0x9d, 0xe3, 0x5b, 0xb0, 0x24, 0x8c, 0x12, 0x87,
+	0xc0, 0x45, 0x76, 0x8b, 0x45, 0xf4, 0xbf, 0x54, 0x61, 0x5d, 0x46, 0xa7, 0x2f, 0xc7, 0x32, 0x4d,
+	0xe1, 0x9c, 0x7b, 0x2e, 0x9c, 0x6b, 0xb0, 0x71, 0x06, 0xa0, 0x97, 0x58, 0x85, 0xff, 0xa1, 0xc0,
+	0xf2, 0x1e, 0x19, 0x7a, 0xe3, 0x57, 0x14, 0xde, 0x14, 0x6a, 0xd9, 0xe7, 0x42, 0xed, 0x06, 0x94,
+	0xa5, 0xbe, 0x12, 0xad, 0xf3, 0xcb, 0x40, 0x59, 0xb0, 0x0c, 0xf4, 0xcf, 0x55, 0x28, 0xd7, 0x82,
+	0xd1, 0xc8, 0xa3, 0xaf, 0x28, 0x52, 0xe7, 0xf5, 0xcc, 0x2e, 0x5a, 0xee, 0xbb, 0xb0, 0x11, 0x7f,
+	0x76, 0xf2, 0x4f, 0x0c, 0x22, 0x70, 0x1c, 0xc8, 0x18, 0x12, 0x7f, 0x93, 0xb2, 0x8f, 0x0b, 0x09,
+	0xf1, 0x40, 0xff, 0x36, 0x54, 0x62, 0x68, 0x24, 0xa8, 0x6f, 0x43, 0x79, 0xbe, 0xb5, 0x38, 0x76,
+	0x2f, 0x0f, 0xd3, 0xcd, 0xfe, 0x5d, 0x81, 0x15, 0x1c, 0xf8, 0xfe, 0x81, 0xdb, 0x3f, 0xbe, 0xd0,
+	0xa0, 0xea, 0x08, 0xb4, 0x99, 0xa2, 0x02, 0x22, 0xfd, 0xbf, 0x15, 0xa8, 0x74, 0x42, 0xc2, 0x3e,
+	0xc1, 0x2f, 0xb6, 0x47, 0x21, 0xc8, 0x0e, 0xa8, 0x3c, 0x75, 0x14, 0x31, 0x2f, 0xeb, 0xab, 0xb0,
+	0x92, 0xe8, 0x2e, 0xf1, 0xf8, 0x89, 0x0a, 0x1b, 0xc2, 0x8b, 0x24, 0x67, 0xf0, 0x8a, 0xc2, 0x12,
+	0xeb, 0x9b, 0x9d, 0xe9, 0xfb, 0x52, 0xab, 0xea, 0x23, 0xb8, 0x7c, 0x16, 0x8f, 0x17, 0x59, 0x5d,
+	0x3f, 0x52, 0xe1, 0x4a, 0xec, 0x74, 0xaf, 0x38, 0xa2, 0xff, 0x07, 0x47, 0xdb, 0x84, 0xea, 0x79,
+	0x10, 0xa4, 0xc7, 0x7d, 0xaa, 0x42, 0xb5, 0x16, 0x12, 0x97, 0x92, 0xd4, 0xb1, 0xe8, 0x02, 0x39,
+	0xdd, 0xfb, 0xb0, 0x3c, 0x71, 0x43, 0xea, 0xf5, 0xbd, 0x89, 0xcb, 0x3e, 0x3c, 0x73, 0x5b, 0x99,
+	0xf3, 0x1d, 0xcc, 0x89, 0xe8, 0xaf, 0xc3, 0x6b, 0x0b, 0x10, 0x91, 0x78, 0xfd, 0xad, 0x0a, 0xa8,
+	0x4b, 0xdd, 0x90, 0x7e, 0x19, 0xf6, 0xc1, 0x05, 0xce, 0xf4, 0xe4, 0x55, 0x9c, 0x7f, 0xf2, 0x2a,
+	0xfe, 0x10, 0xd6, 0xe6, 0x30, 0x7b, 0x91, 0x25, 0xfc, 0x3f, 0x0a, 0xa0, 0x2e, 0xa1, 0x5f, 0x8a,
+	0x3d, 0x72, 0xe1, 0xea, 0xdd, 0x80, 0xb5, 0x39, 0xfd, 0xa5, 0x23, 0xfe, 0xb3, 0x02, 0x9b, 0xb5,
+	0x40, 0x80, 0x7d, 0x21, 0x97, 0xae, 0xfe, 0x35, 0x78, 0x7d, 0xa1, 0x82, 0x12, 0x80, 0x7f, 0x52,
+	0xe0, 0x32, 0x26, 0xee, 0xe0, 0x62, 0x2a, 0x7f, 0x17, 0xae, 0x9c, 0x53, 0x4e, 0x2e, 0x9b, 0x1b,
+	0x50, 0x18, 0x11, 0xea, 0x0e, 0x5c, 0xea, 0x4a, 0x95, 0x36, 0xe3, 0x7e, 0x67, 0xd2, 0x2d, 0x29,
+	0x81, 0x13, 0x59, 0xfd, 0x0b, 0x15, 0xd6, 0xf8, 0xb1, 0xff, 0xab, 0x8f, 0xc9, 0xc5, 0x9f, 0x45,
+	0x9f, 0x2a, 0xb0, 0x3e, 0x0f, 0x50, 0xf2, 0x79, 0xf4, 0xff, 0x7d, 0x27, 0xb3, 0x20, 0x20, 0x64,
+	0x16, 0x1d, 0x9a, 0xff, 0x41, 0x85, 0x6a, 0x7a, 0x4a, 0x5f, 0xdd, 0xdf, 0xcc, 0xdf, 0xdf, 0xbc,
+	0xf0, 0x85, 0xdd, 0x67, 0x0a, 0xbc, 0xb6, 0x00, 0xd0, 0x17, 0x33, 0x74, 0xea, 0x16, 0x47, 0x7d,
+	0xe6, 0x2d, 0xce, 0xf3, 0x9a, 0xfa, 0x1f, 0x15, 0x58, 0x6f, 0x91, 0x28, 0x72, 0x87, 0x44, 0x5c,
+	0x69, 0xbc, 0xba, 0xd1, 0x8c, 0xdf, 0x8f, 0x67, 0x67, 0x8f, 0x4c, 0xec, 0x9a, 0xe6, 0x8c, 0x6a,
+	0x2f, 0x71, 0x4d, 0xf3, 0x5f, 0x0a, 0xac, 0xca, 0x5e, 0x8c, 0xfe, 0xf1, 0xc5, 0x41, 0x07, 0xbd,
+	0x09, 0x19, 0x6f, 0x10, 0x1f, 0x4d, 0xe7, 0x1f, 0xf8, 0x19, 0x43, 0xbf, 0x05, 0x28, 0xad, 0xf7,
+	0x4b, 0x40, 0xf7, 0xd3, 0x0c, 0xac, 0x76, 0x27, 0xbe, 0x47, 0x25, 0xf3, 0x62, 0x07, 0xfe, 0x9f,
+	0x81, 0xe5, 0x88, 0x29, 0xeb, 0x88, 0x87, 0x43, 0x0e, 0x6c, 0x11, 0x97, 0x38, 0xad, 0xc6, 0x49,
+	0xe8, 0x2d, 0x28, 0xc5, 0x22, 0xd3, 0x31, 0x95, 0x97, 0xbe, 0x20, 0x25, 0xa6, 0x63, 0x8a, 0xae,
+	0xc3, 0x95, 0xf1, 0x74, 0xc4, 0x9f, 0xeb, 0x9d, 0x09, 0x09, 0xe3, 0xc7, 0x6c, 0x37, 0x8c, 0x9f,
+	0xd5, 0xd7, 0xc6, 0xd3, 0x11, 0x7b, 0xb5, 0xef, 0x90, 0x50, 0x3c, 0x66, 0xbb, 0x21, 0x45, 0xb7,
+	0xa0, 0xe8, 0xfa, 0xc3, 0x20, 0xf4, 0xe8, 0xd1, 0x48, 0xbe, 0xa7, 0xeb, 0xf1, 0x2b, 0xd3, 0x59,
+	0xf8, 0x77, 0x8c, 0x58, 0x12, 0xcf, 0x1a, 0xe9, 0xdf, 0x82, 0x62, 0x42, 0x67, 0x2f, 0xba, 0xe6,
+	0xdd, 0x9e, 0xd1, 0x74, 0xba, 0x9d, 0x66, 0xc3, 0xee, 0x8a, 0x97, 0xe9, 0xfd, 0x5e, 0xb3, 0xe9,
+	0x74, 0x6b, 0x86, 0xa5, 0x29, 0x3a, 0x06, 0xe0, 0x5d, 0xf2, 0xce, 0x67, 0x00, 0x29, 0xcf, 0x00,
+	0xe8, 0x75, 0x28, 0x86, 0xc1, 0x89, 0xd4, 0x5d, 0xe5, 0xea, 0x14, 0xc2, 0xe0, 0x84, 0x6b, 0xae,
+	0x1b, 0x80, 0xd2, 0x73, 0x95, 0xde, 0x96, 0x0a, 0xde, 0xca, 0x5c, 0xf0, 0x9e, 0x8d, 0x9f, 0x04,
+	0x6f, 0x7e, 0x64, 0xe5, 0xeb, 0xfc, 0x7b, 0xc4, 0xf5, 0x69, 0xbc, 0x5f, 0xe9, 0x7f, 0xad, 0x42,
+	0x19, 0x33, 0x8a, 0x37, 0x22, 0xec, 0xa1, 0x2d, 0x62, 0x96, 0x3a, 0xe2, 0x22, 0xce, 0x2c, 0xec,
+	0x16, 0x71, 0x49, 0xd0, 0xc4, 0x7b, 0xc8, 0x2e, 0x6c, 0x44, 0xa4, 0x1f, 0x8c, 0x07, 0x91, 0x73,
+	0x40, 0x8e, 0x58, 0x0e, 0xcb, 0xc8, 0x8d, 0xa8, 0x7c, 0x34, 0x2d, 0xe3, 0x35, 0xc9, 0xdc, 0xe3,
+	0xbc, 0x16, 0x67, 0xa1, 0x6b, 0xb0, 0x7e, 0xe0, 0x8d, 0xfd, 0x60, 0xc8, 0xb2, 0x0f, 0x4e, 0x49,
+	0x18, 0x49, 0x55, 0x99, 0x7b, 0xe5, 0x30, 0x12, 0xbc, 0x8e, 0x60, 0x09, 0x73, 0x7f, 0x02, 0xdb,
+	0x0b, 0x47, 0x71, 0x0e, 0x3d, 0x9f, 0x92, 0x90, 0x0c, 0x9c, 0x90, 0x4c, 0x7c, 0xaf, 0x2f, 0x32,
+	0x25, 0xc4, 0xd9, 0xfd, 0x1b, 0x0b, 0x86, 0xde, 0x97, 0xe2, 0x78, 0x26, 0xcd, 0xd0, 0xee, 0x4f,
+	0xa6, 0xce, 0x94, 0x2d, 0x60, 0xbe, 0x8b, 0x29, 0xb8, 0xd0, 0x9f, 0x4c, 0x7b, 0xac, 0xce, 0x9e,
+	0xef, 0x1e, 0x4c, 0xc4, 0xe6, 0xa5, 0x60, 0x56, 0x64, 0xb7, 0xd1, 0x15, 0x63, 0x38, 0x0c, 0xc9,
+	0xd0, 0xa5, 0x12, 0xa6, 0x6b, 0xb0, 0x2e, 0x20, 0x39, 0x75, 0x64, 0x0a, 0x96, 0xd0, 0x47, 0x11,
+	0xfa, 0x48, 0x9e, 0x48, 0xc0, 0x8a, 0xdd, 0xf7, 0xf2, 0x74, 0xbc, 0xb0, 0x8d, 0xca, 0xdb, 0xac,
+	0x4f, 0xc7, 0x0b, 0x5a, 0xfd, 0x02, 0xbc, 0xb6, 0x18, 0x85, 0x91, 0x27, 0x92, 0x68, 0xca, 0xf8,
+	0xf2, 0x02, 0xa5, 0x5b, 0xde, 0xf8, 0x29, 0x4d, 0xdd, 0x47, 0xd5, 0xec, 0x93, 0x9b, 0xba, 0x8f,
+	0xf4, 0x7f, 0x4d, 0x5e, 0x39, 0x62, 0x77, 0x49, 0x76, 0xe3, 0x38, 0x2e, 0x28, 0x4f, 0x8b, 0x0b,
+	0x55, 0x58, 0x8a, 0x48, 0xf8, 0xd0, 0x1b, 0x0f, 0xe3, 0x87, 0x74, 0x59, 0x45, 0x5d, 0xf8, 0x86,
+	0xd4, 0x9d, 0x3c, 0xa2, 0x24, 0x1c, 0xbb, 0xbe, 0x7f, 0xea, 0x88, 0x1b, 0x90, 0x31, 0x25, 0x03,
+	0x67, 0x96, 0x30, 0x26, 0x76, 0xe4, 0xb7, 0x85, 0xb4, 0x99, 0x08, 0xe3, 0x44, 0xd6, 0x8e, 0x45,
+	0xd1, 0x77, 0xa0, 0x12, 0x4a, 0x27, 0x76, 0x22, 0x66, 0x1e, 0x19, 0x8f, 0xd6, 0x93, 0xd7, 0xf0,
+	0x94, 0x87, 0xe3, 0x72, 0x98, 0xae, 0xa2, 0xef, 0xc2, 0x8a, 0x1b, 0xdb, 0x56, 0xb6, 0x9e, 0x3f,
+	0xb7, 0xcc, 0x5b, 0x1e, 0x57, 0xdc, 0xb9, 0x3a, 0xba, 0x09, 0xcb, 0x52, 0x23, 0xd7, 0xf7, 0xdc,
+	0xd9, 0xc1, 0xf6, 0x4c, 0x16, 0x9e, 0xc1, 0x98, 0xb8, 0x44, 0x67, 0x15, 0xf6, 0x1d, 0xbd, 0xd6,
+	0x9b, 0x0c, 0x78, 0x4f, 0xaf, 0xf0, 0xe9, 0x22, 0x9d, 0xb2, 0x97, 0x9d, 0x4f, 0xd9, 0x9b, 0x4f,
+	0x01, 0xcc, 0x9d, 0x49, 0x01, 0xd4, 0x6f, 0xc1, 0xfa, 0xbc, 0xfe, 0xd2, 0xcb, 0xae, 0x42, 0x8e,
+	0x27, 0x0d, 0x9c, 0xd9, 0x46, 0x53, 0x59, 0x01, 0x58, 0x08, 0xe8, 0xff, 0xa6, 0x42, 0x11, 0x07,
+	0x27, 0xb5, 0x23, 0x77, 0x3c, 0x64, 0x39, 0x4b, 0xe9, 0x84, 0xbe, 0x8d, 0xd9, 0x23, 0xbd, 0xe0,
+	0x2f, 0x4c, 0xa1, 0x51, 0xd3, 0x29, 0x34, 0xb3, 0xb4, 0x8e, 0xcc, 0x53, 0xd2, 0x3a, 0x74, 0xc8,
+	0x1f, 0x90, 0xc3, 0x20, 0x24, 0xd2, 0xcd, 0xd2, 0xd9, 0x00, 0x92, 0x83, 0xb6, 0x20, 0xe7, 0x1e,
+	0xb2, 0x98, 0x98, 0x3b, 0x27, 0x22, 0x18, 0xe8, 0x3d, 0x28, 0x4e, 0x8e, 0xe3, 0x94, 0x84, 0xfc,
+	0x82, 0xe1, 0x0a, 0x93, 0xe3, 0x7d, 0x31, 0xe0, 0xbb, 0x5c, 0x54, 0x66, 0x20, 0x2c, 0x9d, 0xcb,
+	0x40, 0x28, 0x4c, 0x8e, 0xe7, 0x33, 0x0f, 0x0a, 0xb3, 0xcc, 0x03, 0x13, 0xb2, 0x4c, 0x6b, 0x04,
+	0x90, 0x6f, 0x58, 0x5d, 0x13, 0xb3, 0xa4, 0x26, 0x80, 0x7c, 0xaf, 0x53, 0x37, 0x6c, 0x53, 0x53,
+	0x58, 0xb9, 0x6e, 0x36, 0x4d, 0xdb, 0x94, 0xa9, 0x51, 0xf5, 0xa6, 0x48, 0x5b, 0xea, 0x59, 0xd8,
+	0xac, 0xb5, 0x6f, 0x5b, 0x8d, 0x4f, 0xcc, 0xba, 0x96, 0xd5, 0x23, 0x28, 0x09, 0x0c, 0x39, 0xfa,
+	0xcf, 0xc8, 0xec, 0x4c, 0x3b, 0x84, 0x7a, 0xc6, 0x21, 0xb6, 0x79, 0xae, 0xd0, 0x78, 0x98, 0x64,
+	0x4f, 0x6a, 0x67, 0xad, 0x84, 0x63, 0x01, 0xfd, 0x5f, 0x94, 0x38, 0x0a, 0x09, 0x4e, 0x74, 0xf1,
+	0x16, 0x88, 0x6e, 0xc0, 0xc6, 0x19, 0x15, 0x9f, 0xbe, 0x06, 0x52, 0x56, 0x88, 0xd7, 0xc0, 0xdf,
+	0x28, 0xb0, 0xb6, 0xe0, 0x9a, 0x21, 0xb9, 0xc3, 0x50, 0x52, 0x57, 0x85, 0x3f, 0x0b, 0x39, 0x9e,
+	0xc2, 0x23, 0x73, 0xcb, 0xae, 0x9c, 0xbf, 0xa5, 0xe0, 0xe9, 0x36, 0x58, 0x48, 0xb1, 0xc3, 0x00,
+	0x0f, 0xaa, 0x7d, 0x7e, 0xf9, 0x1a, 0x7f, 0x25, 0x95, 0x18, 0x4d, 0xdc, 0xc7, 0x9e, 0xbf, 0xcd,
+	0xcd, 0x3e, 0xf3, 0x36, 0x77, 0xfb, 0x0f, 0x32, 0x50, 0x6c, 0x9d, 0x76, 0x1f, 0xf8, 0xfb, 0xbe,
+	0x3b, 0xe4, 0xf9, 0x30, 0xad, 0x8e, 0x7d, 0x5f, 0xbb, 0xc4, 0xf2, 0xfe, 0xac, 0xb6, 0xed, 0x58,
+	0xec, 0x38, 0xb5, 0xdf, 0x34, 0x6e, 0x6b, 0x0a, 0x73, 0xc5, 0x0e, 0x6e, 0x38, 0x77, 0xcc, 0xfb,
+	0x82, 0xa2, 0xb2, 0x94, 0xbc, 0x9e, 0xd5, 0xb8, 0xdb, 0x33, 0x67, 0xc4, 0x2c, 0xda, 0x80, 0xd5,
+	0x56, 0xaf, 0x69, 0x37, 0x3a, 0xcd, 0x14, 0xb9, 0xc0, 0xce, 0x66, 0x7b, 0xcd, 0xf6, 0x9e, 0xa8,
+	0x6a, 0xac, 0xff, 0x9e, 0xd5, 0x6d, 0xdc, 0xb6, 0xcc, 0xba, 0x20, 0x6d, 0x31, 0xd2, 0x27, 0x26,
+	0x6e, 0xef, 0x37, 0xe2, 0x21, 0x6f, 0x21, 0x0d, 0x4a, 0x7b, 0x0d, 0xcb, 0xc0, 0xb2, 0x97, 0xc7,
+	0x0a, 0xaa, 0x40, 0xd1, 0xb4, 0x7a, 0x2d, 0x59, 0x57, 0x51, 0x15, 0xd6, 0x8c, 0x9e, 0xdd, 0x76,
+	0x1a, 0x56, 0x0d, 0x9b, 0x2d, 0xd3, 0xb2, 0x25, 0x27, 0x8b, 0xd6, 0xa0, 0x62, 0x37, 0x5a, 0x66,
+	0xd7, 0x36, 0x5a, 0x1d, 0x49, 0x64, 0xb3, 0x28, 0x74, 0xcd, 0x58, 0x46, 0x43, 0x9b, 0xb0, 0x61,
+	0xb5, 0x1d, 0x99, 0x63, 0xe8, 0xdc, 0x33, 0x9a, 0x3d, 0x53, 0xf2, 0xb6, 0xd0, 0x15, 0x40, 0x6d,
+	0xcb, 0x11, 0xab, 0xd3, 0xb1, 0xda, 0x1f, 0x4b, 0xc6, 0x2d, 0x54, 0x81, 0xc2, 0x6c, 0x06, 0x8f,
+	0x19, 0x0a, 0xe5, 0x8e, 0x81, 0xed, 0x99, 0xb2, 0x8f, 0x1f, 0x33, 0xb0, 0xe0, 0x36, 0x6e, 0xf7,
+	0x3a, 0x33, 0xb1, 0x55, 0x28, 0x49, 0xb0, 0x24, 0x29, 0xcb, 0x48, 0x7b, 0x0d, 0xab, 0x96, 0xcc,
+	0xef, 0x71, 0x61, 0x53, 0xd5, 0x94, 0xed, 0x63, 0xc8, 0x72, 0x73, 0x14, 0x20, 0x6b, 0xb5, 0x2d,
+	0x96, 0x72, 0xb9, 0x02, 0xd0, 0xe8, 0x36, 0x2c, 0xdb, 0xbc, 0x8d, 0x8d, 0x26, 0x53, 0x9b, 0x13,
+	0x62, 0x00, 0x99, 0xb6, 0xcb, 0xb0, 0xd4, 0xe8, 0xee, 0x37, 0xdb, 0x86, 0x2d, 0xd5, 0x6c, 0x74,
+	0xef, 0xf6, 0xda, 0x2c, 0xf5, 0xf1, 0xb1, 0x86, 0x4a, 0x90, 0x6f, 0x74, 0x6d, 0xf3, 0x07, 0x36,
+	0xd3, 0x8b, 0xf3, 0x04, 0xaa, 0xda, 0xe3, 0x5b, 0xdb, 0x9f, 0x67, 0x64, 0x58, 0x2a, 0x43, 0x91,
+	0x5b, 0x9b, 0xe5, 0x76, 0x6a, 0x97, 0x50, 0x11, 0xb2, 0x0d, 0xcb, 0xbe, 0xa9, 0xfd, 0xb2, 0x8a,
+	0x00, 0x72, 0x3d, 0x5e, 0xfe, 0x95, 0x3c, 0x2b, 0x37, 0x2c, 0xfb, 0xfd, 0x1b, 0xda, 0x8f, 0x54,
+	0xd6, 0x6d, 0x4f, 0x54, 0x7e, 0x35, 0x66, 0xec, 0x5e, 0xd7, 0x7e, 0x9c, 0x30, 0x76, 0xaf, 0x6b,
+	0xbf, 0x16, 0x33, 0x3e, 0xd8, 0xd5, 0x7e, 0x3d, 0x61, 0x7c, 0xb0, 0xab, 0xfd, 0x46, 0xcc, 0xb8,
+	0x71, 0x5d, 0xfb, 0xcd, 0x84, 0x71, 0xe3, 0xba, 0xf6, 0x5b, 0x79, 0xa6, 0x0b, 0xd7, 0xe4, 0x83,
+	0x5d, 0xed, 0xb7, 0x0b, 0x49, 0xed, 0xc6, 0x75, 0xed, 0x77, 0x0a, 0xcc, 0xfe, 0x89, 0x55, 0xb5,
+	0xdf, 0xd5, 0xd8, 0x34, 0x79, 0xf8, 0xfc, 0x09, 0x2f, 0x32, 0x96, 0xf6, 0x7b, 0x1a, 0xd3, 0x91,
+	0x51, 0x79, 0xf5, 0x53, 0xce, 0xb9, 0x6f, 0x1a, 0x58, 0xfb, 0xfd, 0xbc, 0x48, 0x29, 0xad, 0x35,
+	0x5a, 0x46, 0x53, 0x43, 0xbc, 0x05, 0x43, 0xe5, 0x0f, 0xaf, 0xb1, 0x22, 0x73, 0x4f, 0xed, 0x8f,
+	0x3a, 0x6c, 0xc0, 0x7b, 0x06, 0xae, 0x7d, 0xcf, 0xc0, 0xda, 0x1f, 0x5f, 0x63, 0x03, 0xde, 0x33,
+	0xb0, 0xc4, 0xeb, 0x4f, 0x3a, 0x4c, 0x90, 0xb3, 0x3e, 0xbb, 0xc6, 0x26, 0x2d, 0xe9, 0x7f, 0xda,
+	0x41, 0x05, 0xc8, 0xec, 0x35, 0x6c, 0xed, 0x73, 0x3e, 0x1a, 0x73, 0x51, 0xed, 0xcf, 0x34, 0x46,
+	0xec, 0x9a, 0xb6, 0xf6, 0xe7, 0x8c, 0x98, 0xb3, 0x7b, 0x9d, 0xa6, 0xa9, 0xbd, 0xc1, 0x26, 0x77,
+	0xdb, 0x6c, 0xb7, 0x4c, 0x1b, 0xdf, 0xd7, 0xfe, 0x82, 0x8b, 0x7f, 0xbf, 0xdb, 0xb6, 0xb4, 0x2f,
+	0x34, 0x54, 0x01, 0x30, 0x7f, 0xd0, 0xc1, 0x66, 0xb7, 0xdb, 0x68, 0x5b, 0xda, 0x5b, 0xdb, 0xfb,
+	0xa0, 0x9d, 0x0d, 0x07, 0x4c, 0x81, 0x9e, 0x75, 0xc7, 0x6a, 0x7f, 0x6c, 0x69, 0x97, 0x58, 0xa5,
+	0x83, 0xcd, 0x8e, 0x81, 0xe5, 0xfe, 0x21, 0x12, 0x5e, 0x35, 0x15, 0x2d, 0x43, 0x01, 0xb7, 0x9b,
+	0xcd, 0x3d, 0xa3, 0x76, 0x47, 0xcb, 0xec, 0xad, 0xc2, 0x8a, 0x17, 0xec, 0x3c, 0xf4, 0x28, 0x89,
+	0x22, 0xf1, 0x67, 0x87, 0x83, 0x3c, 0xff, 0xf9, 0xe0, 0x7f, 0x07, 0x00, 0x68, 0x55, 0xe8, 0x75,
+	0x26, 0x31, 0x00, 0x00,
 }
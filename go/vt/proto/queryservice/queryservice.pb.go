@@ -92,6 +92,9 @@ type QueryClient interface {
 	StreamHealth(ctx context.Context, in *query.StreamHealthRequest, opts ...grpc.CallOption) (Query_StreamHealthClient, error)
 	// UpdateStream asks the server to return a stream of the updates that have been applied to its database.
 	UpdateStream(ctx context.Context, in *query.UpdateStreamRequest, opts ...grpc.CallOption) (Query_UpdateStreamClient, error)
+	// StreamChanges streams the changes made to the database, with the
+	// row images if the binlogs have them.
+	StreamChanges(ctx context.Context, in *query.StreamChangesRequest, opts ...grpc.CallOption) (Query_StreamChangesClient, error)
 }
 
 type queryClient struct {
@@ -383,6 +386,38 @@ func (x *queryUpdateStreamClient) Recv() (*query.UpdateStreamResponse, error) {
 	return m, nil
 }
 
+func (c *queryClient) StreamChanges(ctx context.Context, in *query.StreamChangesRequest, opts ...grpc.CallOption) (Query_StreamChangesClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Query_serviceDesc.Streams[4], c.cc, "/queryservice.Query/StreamChanges", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &queryStreamChangesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Query_StreamChangesClient interface {
+	Recv() (*query.StreamChangesResponse, error)
+	grpc.ClientStream
+}
+
+type queryStreamChangesClient struct {
+	grpc.ClientStream
+}
+
+func (x *queryStreamChangesClient) Recv() (*query.StreamChangesResponse, error) {
+	m := new(query.StreamChangesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Query service
 
 type QueryServer interface {
@@ -435,6 +470,9 @@ type QueryServer interface {
 	StreamHealth(*query.StreamHealthRequest, Query_StreamHealthServer) error
 	// UpdateStream asks the server to return a stream of the updates that have been applied to its database.
 	UpdateStream(*query.UpdateStreamRequest, Query_UpdateStreamServer) error
+	// StreamChanges streams the changes made to the database, with the
+	// row images if the binlogs have them.
+	StreamChanges(*query.StreamChangesRequest, Query_StreamChangesServer) error
 }
 
 func RegisterQueryServer(s *grpc.Server, srv QueryServer) {
@@ -831,6 +869,27 @@ func (x *queryUpdateStreamServer) Send(m *query.UpdateStreamResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Query_StreamChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(query.StreamChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QueryServer).StreamChanges(m, &queryStreamChangesServer{stream})
+}
+
+type Query_StreamChangesServer interface {
+	Send(*query.StreamChangesResponse) error
+	grpc.ServerStream
+}
+
+type queryStreamChangesServer struct {
+	grpc.ServerStream
+}
+
+func (x *queryStreamChangesServer) Send(m *query.StreamChangesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Query_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "queryservice.Query",
 	HandlerType: (*QueryServer)(nil),
@@ -925,6 +984,11 @@ var _Query_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Query_UpdateStream_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamChanges",
+			Handler:       _Query_StreamChanges_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "queryservice.proto",
 }
@@ -932,36 +996,37 @@ var _Query_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("queryservice.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 491 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x95, 0xdb, 0x6e, 0xd4, 0x40,
-	0x0c, 0x86, 0xe1, 0xa2, 0x2d, 0x72, 0xc3, 0x69, 0x4a, 0x81, 0xa6, 0xa5, 0x2d, 0x7d, 0x80, 0x0a,
-	0x01, 0x12, 0x52, 0x25, 0x2e, 0xda, 0x08, 0x04, 0xaa, 0x38, 0x65, 0x59, 0x89, 0x2b, 0xa4, 0xd9,
-	0xc4, 0x5a, 0xa2, 0xcd, 0x26, 0xd9, 0xc9, 0x04, 0xc1, 0x13, 0xf1, 0x9a, 0x88, 0x4c, 0xec, 0xcc,
-	0x4c, 0x12, 0x2e, 0xfd, 0xff, 0xf6, 0x27, 0x67, 0xbc, 0xf6, 0x82, 0xd8, 0x34, 0xa8, 0x7e, 0xd7,
-	0xa8, 0x7e, 0x66, 0x09, 0x9e, 0x57, 0xaa, 0xd4, 0xa5, 0x08, 0x6c, 0x2d, 0xdc, 0x6d, 0x23, 0x63,
-	0x3d, 0xff, 0x13, 0xc0, 0xd6, 0x97, 0x7f, 0xb1, 0xb8, 0x80, 0x9d, 0x37, 0xbf, 0x30, 0x69, 0x34,
-	0x8a, 0xfd, 0x73, 0x93, 0xd2, 0xc5, 0x31, 0x6e, 0x1a, 0xac, 0x75, 0xf8, 0xd0, 0x97, 0xeb, 0xaa,
-	0x2c, 0x6a, 0x3c, 0xbb, 0x21, 0xde, 0x43, 0xd0, 0x89, 0x57, 0x52, 0x27, 0x3f, 0x44, 0xe8, 0x66,
-	0xb6, 0x22, 0x51, 0x0e, 0x47, 0x3d, 0x46, 0x7d, 0x84, 0xdb, 0x33, 0xad, 0x50, 0xae, 0xa9, 0x19,
-	0xca, 0x77, 0x54, 0x82, 0x1d, 0x8d, 0x9b, 0x44, 0x7b, 0x76, 0x53, 0xbc, 0x84, 0xad, 0x2b, 0x5c,
-	0x66, 0x85, 0xd8, 0xeb, 0x52, 0xdb, 0x88, 0xea, 0x1f, 0xb8, 0x22, 0x77, 0xf1, 0x0a, 0xb6, 0xa3,
-	0x72, 0xbd, 0xce, 0xb4, 0xa0, 0x0c, 0x13, 0x52, 0xdd, 0xbe, 0xa7, 0x72, 0xe1, 0x6b, 0xb8, 0x15,
-	0x97, 0x79, 0xbe, 0x90, 0xc9, 0x4a, 0xd0, 0x7b, 0x91, 0x40, 0xc5, 0x8f, 0x06, 0x3a, 0x97, 0x5f,
-	0xc0, 0xce, 0x67, 0x85, 0x95, 0x54, 0xfd, 0x10, 0xba, 0xd8, 0x1f, 0x02, 0xcb, 0x5c, 0xfb, 0x09,
-	0xee, 0x98, 0x76, 0x3a, 0x2b, 0x15, 0x47, 0x4e, 0x97, 0x24, 0x13, 0xe9, 0xc9, 0x84, 0xcb, 0xc0,
-	0x39, 0xdc, 0xa3, 0x16, 0x19, 0x79, 0xec, 0xf5, 0xee, 0x43, 0x4f, 0x26, 0x7d, 0xc6, 0x7e, 0x83,
-	0xfb, 0x91, 0x42, 0xa9, 0xf1, 0xab, 0x92, 0x45, 0x2d, 0x13, 0x9d, 0x95, 0x85, 0xa0, 0xba, 0x81,
-	0x43, 0xe0, 0xd3, 0xe9, 0x04, 0x26, 0xbf, 0x85, 0xdd, 0x99, 0x96, 0x4a, 0x77, 0xa3, 0x3b, 0xe0,
-	0x1f, 0x07, 0x6b, 0x44, 0x0b, 0xc7, 0x2c, 0x87, 0x83, 0x9a, 0xe7, 0xc8, 0x9c, 0x5e, 0x1b, 0x70,
-	0x6c, 0x8b, 0x39, 0xdf, 0x61, 0x2f, 0x2a, 0x8b, 0x24, 0x6f, 0x52, 0xe7, 0x5b, 0x9f, 0xf2, 0xc3,
-	0x0f, 0x3c, 0xe2, 0x9e, 0xfd, 0x2f, 0x85, 0xf9, 0x31, 0xdc, 0x8d, 0x51, 0xa6, 0x36, 0x9b, 0x86,
-	0xea, 0xe9, 0xc4, 0x3d, 0x9e, 0xb2, 0xed, 0x55, 0x6e, 0x97, 0x81, 0xd6, 0x2f, 0xb4, 0x37, 0xc4,
-	0xdb, 0xbe, 0xc3, 0x51, 0xcf, 0x1e, 0xb4, 0xed, 0x98, 0xd3, 0x70, 0x32, 0x52, 0xe3, 0xdc, 0x87,
-	0xd3, 0xe9, 0x04, 0xfb, 0x48, 0x7c, 0xc0, 0xba, 0x96, 0x4b, 0x34, 0x8b, 0xcf, 0x47, 0xc2, 0x51,
-	0xfd, 0x23, 0xe1, 0x99, 0xd6, 0x91, 0x88, 0x00, 0x3a, 0xf3, 0x32, 0x59, 0x89, 0xc7, 0x6e, 0xfe,
-	0x65, 0x3f, 0xee, 0x83, 0x11, 0x87, 0x9b, 0x8a, 0x00, 0x66, 0x55, 0x9e, 0x69, 0x73, 0x4e, 0x09,
-	0xd2, 0x4b, 0x3e, 0xc4, 0x76, 0x18, 0x72, 0x0d, 0x81, 0xe9, 0xef, 0x1d, 0xca, 0x5c, 0xf7, 0x97,
-	0xd4, 0x16, 0xfd, 0xe7, 0x77, 0x3d, 0xeb, 0xb3, 0xae, 0x21, 0x98, 0x57, 0xa9, 0xd4, 0xf4, 0x4a,
-	0x04, 0xb3, 0x45, 0x1f, 0xe6, 0x7a, 0x3d, 0x6c, 0xb1, 0xdd, 0xfe, 0x61, 0xbc, 0xf8, 0x1b, 0x00,
-	0x00, 0xff, 0xff, 0xc5, 0x90, 0xa5, 0x56, 0x61, 0x06, 0x00, 0x00,
+	// 505 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x95, 0xdf, 0x4f, 0xd4, 0x40,
+	0x10, 0xc7, 0xf5, 0x01, 0x30, 0x43, 0xfd, 0xb5, 0x88, 0x4a, 0x41, 0x40, 0xfe, 0x00, 0x62, 0xd4,
+	0xc4, 0x84, 0xc4, 0x07, 0x68, 0x34, 0x1a, 0xe2, 0xaf, 0x3b, 0x49, 0x7c, 0x32, 0x59, 0x7a, 0x93,
+	0xa3, 0xa1, 0xd7, 0xf6, 0x76, 0xf7, 0x8c, 0xfe, 0xe5, 0xbe, 0x1a, 0xbb, 0x3b, 0xd3, 0xdd, 0x6d,
+	0xcb, 0xe3, 0x7c, 0xbf, 0x33, 0x9f, 0x4c, 0x77, 0x6e, 0xe6, 0x40, 0x2c, 0x57, 0xa8, 0xfe, 0x68,
+	0x54, 0xbf, 0x8a, 0x1c, 0x8f, 0x1b, 0x55, 0x9b, 0x5a, 0x24, 0xbe, 0x96, 0x6e, 0xb6, 0x91, 0xb5,
+	0x5e, 0xfe, 0x4d, 0x60, 0xed, 0xdb, 0xff, 0x58, 0x9c, 0xc0, 0xc6, 0xbb, 0xdf, 0x98, 0xaf, 0x0c,
+	0x8a, 0xed, 0x63, 0x9b, 0xe2, 0xe2, 0x09, 0x2e, 0x57, 0xa8, 0x4d, 0xfa, 0x38, 0x96, 0x75, 0x53,
+	0x57, 0x1a, 0x8f, 0x6e, 0x89, 0x8f, 0x90, 0x38, 0xf1, 0x4c, 0x9a, 0xfc, 0x4a, 0xa4, 0x61, 0x66,
+	0x2b, 0x12, 0x65, 0x77, 0xd0, 0x63, 0xd4, 0x67, 0xb8, 0x3b, 0x35, 0x0a, 0xe5, 0x82, 0x9a, 0xa1,
+	0xfc, 0x40, 0x25, 0xd8, 0xde, 0xb0, 0x49, 0xb4, 0x17, 0xb7, 0xc5, 0x6b, 0x58, 0x3b, 0xc3, 0x79,
+	0x51, 0x89, 0x2d, 0x97, 0xda, 0x46, 0x54, 0xff, 0x28, 0x14, 0xb9, 0x8b, 0x37, 0xb0, 0x9e, 0xd5,
+	0x8b, 0x45, 0x61, 0x04, 0x65, 0xd8, 0x90, 0xea, 0xb6, 0x23, 0x95, 0x0b, 0xdf, 0xc2, 0x9d, 0x49,
+	0x5d, 0x96, 0x97, 0x32, 0xbf, 0x16, 0xf4, 0x5e, 0x24, 0x50, 0xf1, 0x93, 0x9e, 0xce, 0xe5, 0x27,
+	0xb0, 0xf1, 0x55, 0x61, 0x23, 0x55, 0x37, 0x04, 0x17, 0xc7, 0x43, 0x60, 0x99, 0x6b, 0xbf, 0xc0,
+	0x3d, 0xdb, 0x8e, 0xb3, 0x66, 0x62, 0x2f, 0xe8, 0x92, 0x64, 0x22, 0x3d, 0x1b, 0x71, 0x19, 0x78,
+	0x01, 0x0f, 0xa8, 0x45, 0x46, 0xee, 0x47, 0xbd, 0xc7, 0xd0, 0x83, 0x51, 0x9f, 0xb1, 0x3f, 0xe0,
+	0x61, 0xa6, 0x50, 0x1a, 0xfc, 0xae, 0x64, 0xa5, 0x65, 0x6e, 0x8a, 0xba, 0x12, 0x54, 0xd7, 0x73,
+	0x08, 0x7c, 0x38, 0x9e, 0xc0, 0xe4, 0xf7, 0xb0, 0x39, 0x35, 0x52, 0x19, 0x37, 0xba, 0x1d, 0xfe,
+	0x71, 0xb0, 0x46, 0xb4, 0x74, 0xc8, 0x0a, 0x38, 0x68, 0x78, 0x8e, 0xcc, 0xe9, 0xb4, 0x1e, 0xc7,
+	0xb7, 0x98, 0xf3, 0x13, 0xb6, 0xb2, 0xba, 0xca, 0xcb, 0xd5, 0x2c, 0xf8, 0xd6, 0xe7, 0xfc, 0xf0,
+	0x3d, 0x8f, 0xb8, 0x47, 0x37, 0xa5, 0x30, 0x7f, 0x02, 0xf7, 0x27, 0x28, 0x67, 0x3e, 0x9b, 0x86,
+	0x1a, 0xe9, 0xc4, 0xdd, 0x1f, 0xb3, 0xfd, 0x55, 0x6e, 0x97, 0x81, 0xd6, 0x2f, 0xf5, 0x37, 0x24,
+	0xda, 0xbe, 0xdd, 0x41, 0xcf, 0x1f, 0xb4, 0xef, 0xd8, 0xd3, 0x70, 0x30, 0x50, 0x13, 0xdc, 0x87,
+	0xc3, 0xf1, 0x04, 0xff, 0x48, 0x7c, 0x42, 0xad, 0xe5, 0x1c, 0xed, 0xe2, 0xf3, 0x91, 0x08, 0xd4,
+	0xf8, 0x48, 0x44, 0xa6, 0x77, 0x24, 0x32, 0x00, 0x67, 0x9e, 0xe6, 0xd7, 0xe2, 0x69, 0x98, 0x7f,
+	0xda, 0x8d, 0x7b, 0x67, 0xc0, 0xe1, 0xa6, 0x32, 0x80, 0x69, 0x53, 0x16, 0xc6, 0x9e, 0x53, 0x82,
+	0x74, 0x52, 0x0c, 0xf1, 0x1d, 0x86, 0x9c, 0x43, 0x62, 0xfb, 0xfb, 0x80, 0xb2, 0x34, 0xdd, 0x25,
+	0xf5, 0xc5, 0xf8, 0xf9, 0x43, 0xcf, 0xfb, 0xac, 0x73, 0x48, 0x2e, 0x9a, 0x99, 0x34, 0xf4, 0x4a,
+	0x04, 0xf3, 0xc5, 0x18, 0x16, 0x7a, 0x1e, 0x8c, 0x0f, 0x73, 0x76, 0x25, 0xab, 0x39, 0xea, 0xe8,
+	0x30, 0x3b, 0x75, 0xf8, 0x30, 0xb3, 0xd9, 0xf1, 0x2e, 0xd7, 0xdb, 0x3f, 0xa0, 0x57, 0xff, 0x06,
+	0x00, 0xd5, 0xad, 0x43, 0xba, 0xb1, 0x06, 0x00, 0x00,
 }
@@ -53,6 +53,12 @@ It has these top-level messages:
 	GetSrvKeyspaceResponse
 	UpdateStreamRequest
 	UpdateStreamResponse
+	ShardGtid
+	VGtid
+	StreamChangesRequest
+	StreamChangesResponse
+	MessageRequeueRequest
+	MessageRequeueResponse
 */
 package vtgate
 
@@ -138,6 +144,13 @@ type Session struct {
 	Options *query.ExecuteOptions `protobuf:"bytes,6,opt,name=options" json:"options,omitempty"`
 	// transaction_mode specifies the current transaction mode.
 	TransactionMode TransactionMode `protobuf:"varint,7,opt,name=transaction_mode,json=transactionMode,enum=vtgate.TransactionMode" json:"transaction_mode,omitempty"`
+	// read_your_writes makes the non-master reads of the session wait
+	// until the tablet has applied the last commit of the session on
+	// its shard. This is used only for V3.
+	ReadYourWrites bool `protobuf:"varint,8,opt,name=read_your_writes,json=readYourWrites" json:"read_your_writes,omitempty"`
+	// write_positions keep track of the position of the last commit
+	// on each shard, when read_your_writes is set.
+	WritePositions []*Session_WritePosition `protobuf:"bytes,9,rep,name=write_positions,json=writePositions" json:"write_positions,omitempty"`
 }
 
 func (m *Session) Reset()                    { *m = Session{} }
@@ -194,6 +207,20 @@ func (m *Session) GetTransactionMode() TransactionMode {
 	return TransactionMode_UNSPECIFIED
 }
 
+func (m *Session) GetReadYourWrites() bool {
+	if m != nil {
+		return m.ReadYourWrites
+	}
+	return false
+}
+
+func (m *Session) GetWritePositions() []*Session_WritePosition {
+	if m != nil {
+		return m.WritePositions
+	}
+	return nil
+}
+
 type Session_ShardSession struct {
 	Target        *query.Target `protobuf:"bytes,1,opt,name=target" json:"target,omitempty"`
 	TransactionId int64         `protobuf:"varint,2,opt,name=transaction_id,json=transactionId" json:"transaction_id,omitempty"`
@@ -218,6 +245,41 @@ func (m *Session_ShardSession) GetTransactionId() int64 {
 	return 0
 }
 
+type Session_WritePosition struct {
+	Keyspace string `protobuf:"bytes,1,opt,name=keyspace" json:"keyspace,omitempty"`
+	Shard    string `protobuf:"bytes,2,opt,name=shard" json:"shard,omitempty"`
+	// gtid_executed is the replication position of the master after
+	// the last commit of the session on the shard, encoded with its
+	// flavor.
+	GtidExecuted string `protobuf:"bytes,3,opt,name=gtid_executed,json=gtidExecuted" json:"gtid_executed,omitempty"`
+}
+
+func (m *Session_WritePosition) Reset()                    { *m = Session_WritePosition{} }
+func (m *Session_WritePosition) String() string            { return proto.CompactTextString(m) }
+func (*Session_WritePosition) ProtoMessage()               {}
+func (*Session_WritePosition) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0, 1} }
+
+func (m *Session_WritePosition) GetKeyspace() string {
+	if m != nil {
+		return m.Keyspace
+	}
+	return ""
+}
+
+func (m *Session_WritePosition) GetShard() string {
+	if m != nil {
+		return m.Shard
+	}
+	return ""
+}
+
+func (m *Session_WritePosition) GetGtidExecuted() string {
+	if m != nil {
+		return m.GtidExecuted
+	}
+	return ""
+}
+
 // ExecuteRequest is the payload to Execute.
 type ExecuteRequest struct {
 	// caller_id identifies the caller. This is the effective caller ID,
@@ -2362,9 +2424,214 @@ func (m *UpdateStreamResponse) GetResumeTimestamp() int64 {
 	return 0
 }
 
+// ShardGtid is the replication position of a shard in a VGtid.
+type ShardGtid struct {
+	Keyspace string `protobuf:"bytes,1,opt,name=keyspace" json:"keyspace,omitempty"`
+	Shard    string `protobuf:"bytes,2,opt,name=shard" json:"shard,omitempty"`
+	Gtid     string `protobuf:"bytes,3,opt,name=gtid" json:"gtid,omitempty"`
+}
+
+func (m *ShardGtid) Reset()                    { *m = ShardGtid{} }
+func (m *ShardGtid) String() string            { return proto.CompactTextString(m) }
+func (*ShardGtid) ProtoMessage()               {}
+func (*ShardGtid) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *ShardGtid) GetKeyspace() string {
+	if m != nil {
+		return m.Keyspace
+	}
+	return ""
+}
+
+func (m *ShardGtid) GetShard() string {
+	if m != nil {
+		return m.Shard
+	}
+	return ""
+}
+
+func (m *ShardGtid) GetGtid() string {
+	if m != nil {
+		return m.Gtid
+	}
+	return ""
+}
+
+// VGtid is the position of a StreamChanges stream: the replication
+// position of each of the shards of the keyspace.
+type VGtid struct {
+	ShardGtids []*ShardGtid `protobuf:"bytes,1,rep,name=shard_gtids,json=shardGtids" json:"shard_gtids,omitempty"`
+}
+
+func (m *VGtid) Reset()                    { *m = VGtid{} }
+func (m *VGtid) String() string            { return proto.CompactTextString(m) }
+func (*VGtid) ProtoMessage()               {}
+func (*VGtid) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+
+func (m *VGtid) GetShardGtids() []*ShardGtid {
+	if m != nil {
+		return m.ShardGtids
+	}
+	return nil
+}
+
+// StreamChangesRequest is the payload to StreamChanges.
+type StreamChangesRequest struct {
+	// caller_id identifies the caller. This is the effective caller ID,
+	// set by the application to further identify the caller.
+	CallerId *vtrpc.CallerID `protobuf:"bytes,1,opt,name=caller_id,json=callerId" json:"caller_id,omitempty"`
+	// keyspace to stream the changes of.
+	Keyspace string `protobuf:"bytes,2,opt,name=keyspace" json:"keyspace,omitempty"`
+	// tablet_type is the type of tablets the changes are read from.
+	TabletType topodata.TabletType `protobuf:"varint,3,opt,name=tablet_type,json=tabletType,enum=topodata.TabletType" json:"tablet_type,omitempty"`
+	// vgtid is the position to resume the stream from. If it is not set,
+	// the stream starts at the current position of the masters of the
+	// shards of the keyspace.
+	Vgtid *VGtid `protobuf:"bytes,4,opt,name=vgtid" json:"vgtid,omitempty"`
+}
+
+func (m *StreamChangesRequest) Reset()                    { *m = StreamChangesRequest{} }
+func (m *StreamChangesRequest) String() string            { return proto.CompactTextString(m) }
+func (*StreamChangesRequest) ProtoMessage()               {}
+func (*StreamChangesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+
+func (m *StreamChangesRequest) GetCallerId() *vtrpc.CallerID {
+	if m != nil {
+		return m.CallerId
+	}
+	return nil
+}
+
+func (m *StreamChangesRequest) GetKeyspace() string {
+	if m != nil {
+		return m.Keyspace
+	}
+	return ""
+}
+
+func (m *StreamChangesRequest) GetTabletType() topodata.TabletType {
+	if m != nil {
+		return m.TabletType
+	}
+	return topodata.TabletType_UNKNOWN
+}
+
+func (m *StreamChangesRequest) GetVgtid() *VGtid {
+	if m != nil {
+		return m.Vgtid
+	}
+	return nil
+}
+
+// StreamChangesResponse is streamed by StreamChanges.
+type StreamChangesResponse struct {
+	// shard is the shard the event comes from.
+	Shard string `protobuf:"bytes,1,opt,name=shard" json:"shard,omitempty"`
+	// event is one transaction of the shard.
+	Event *query.ChangeEvent `protobuf:"bytes,2,opt,name=event" json:"event,omitempty"`
+	// vgtid is the position of the stream right after the event. A
+	// stream started from it resumes with the next event.
+	Vgtid *VGtid `protobuf:"bytes,3,opt,name=vgtid" json:"vgtid,omitempty"`
+}
+
+func (m *StreamChangesResponse) Reset()                    { *m = StreamChangesResponse{} }
+func (m *StreamChangesResponse) String() string            { return proto.CompactTextString(m) }
+func (*StreamChangesResponse) ProtoMessage()               {}
+func (*StreamChangesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+
+func (m *StreamChangesResponse) GetShard() string {
+	if m != nil {
+		return m.Shard
+	}
+	return ""
+}
+
+func (m *StreamChangesResponse) GetEvent() *query.ChangeEvent {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *StreamChangesResponse) GetVgtid() *VGtid {
+	if m != nil {
+		return m.Vgtid
+	}
+	return nil
+}
+
+// MessageRequeueRequest is the request payload for MessageRequeue.
+type MessageRequeueRequest struct {
+	// caller_id identifies the caller. This is the effective caller ID,
+	// set by the application to further identify the caller.
+	CallerId *vtrpc.CallerID `protobuf:"bytes,1,opt,name=caller_id,json=callerId" json:"caller_id,omitempty"`
+	// keyspace to target the message to.
+	Keyspace string `protobuf:"bytes,2,opt,name=keyspace" json:"keyspace,omitempty"`
+	// name is the message table name, or the name of its dead letter
+	// table.
+	Name string `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	// ids is the list of ids to requeue. All the failed messages are
+	// requeued if it is empty.
+	Ids []*query.Value `protobuf:"bytes,4,rep,name=ids" json:"ids,omitempty"`
+}
+
+func (m *MessageRequeueRequest) Reset()                    { *m = MessageRequeueRequest{} }
+func (m *MessageRequeueRequest) String() string            { return proto.CompactTextString(m) }
+func (*MessageRequeueRequest) ProtoMessage()               {}
+func (*MessageRequeueRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+
+func (m *MessageRequeueRequest) GetCallerId() *vtrpc.CallerID {
+	if m != nil {
+		return m.CallerId
+	}
+	return nil
+}
+
+func (m *MessageRequeueRequest) GetKeyspace() string {
+	if m != nil {
+		return m.Keyspace
+	}
+	return ""
+}
+
+func (m *MessageRequeueRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *MessageRequeueRequest) GetIds() []*query.Value {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+// MessageRequeueResponse is the returned value from MessageRequeue.
+type MessageRequeueResponse struct {
+	// result contains the result of the requeue operation.
+	// Since this acts like a DML, only
+	// RowsAffected is returned in the result.
+	Result *query.QueryResult `protobuf:"bytes,1,opt,name=result" json:"result,omitempty"`
+}
+
+func (m *MessageRequeueResponse) Reset()                    { *m = MessageRequeueResponse{} }
+func (m *MessageRequeueResponse) String() string            { return proto.CompactTextString(m) }
+func (*MessageRequeueResponse) ProtoMessage()               {}
+func (*MessageRequeueResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+
+func (m *MessageRequeueResponse) GetResult() *query.QueryResult {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Session)(nil), "vtgate.Session")
 	proto.RegisterType((*Session_ShardSession)(nil), "vtgate.Session.ShardSession")
+	proto.RegisterType((*Session_WritePosition)(nil), "vtgate.Session.WritePosition")
 	proto.RegisterType((*ExecuteRequest)(nil), "vtgate.ExecuteRequest")
 	proto.RegisterType((*ExecuteResponse)(nil), "vtgate.ExecuteResponse")
 	proto.RegisterType((*ExecuteShardsRequest)(nil), "vtgate.ExecuteShardsRequest")
@@ -2413,126 +2680,145 @@ func init() {
 	proto.RegisterType((*GetSrvKeyspaceResponse)(nil), "vtgate.GetSrvKeyspaceResponse")
 	proto.RegisterType((*UpdateStreamRequest)(nil), "vtgate.UpdateStreamRequest")
 	proto.RegisterType((*UpdateStreamResponse)(nil), "vtgate.UpdateStreamResponse")
+	proto.RegisterType((*ShardGtid)(nil), "vtgate.ShardGtid")
+	proto.RegisterType((*VGtid)(nil), "vtgate.VGtid")
+	proto.RegisterType((*StreamChangesRequest)(nil), "vtgate.StreamChangesRequest")
+	proto.RegisterType((*StreamChangesResponse)(nil), "vtgate.StreamChangesResponse")
+	proto.RegisterType((*MessageRequeueRequest)(nil), "vtgate.MessageRequeueRequest")
+	proto.RegisterType((*MessageRequeueResponse)(nil), "vtgate.MessageRequeueResponse")
 	proto.RegisterEnum("vtgate.TransactionMode", TransactionMode_name, TransactionMode_value)
 }
 
 func init() { proto.RegisterFile("vtgate.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1838 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x5a, 0x5b, 0x8f, 0x23, 0x47,
-	0x15, 0xa6, 0xbb, 0x7d, 0x3d, 0xbe, 0x4e, 0xad, 0x77, 0xd7, 0x71, 0x86, 0x1d, 0xa7, 0x61, 0x14,
-	0x27, 0x59, 0x39, 0xc4, 0xe1, 0x26, 0x84, 0x04, 0x19, 0xef, 0x10, 0x59, 0xd9, 0xd9, 0x0c, 0x65,
-	0x6f, 0x02, 0x12, 0x51, 0xab, 0xc7, 0x2e, 0x79, 0x1a, 0xdb, 0xdd, 0x4e, 0x57, 0xd9, 0x61, 0x78,
-	0x40, 0xf9, 0x07, 0x11, 0x0f, 0x48, 0x68, 0x85, 0x84, 0x90, 0x90, 0x90, 0x90, 0x78, 0x45, 0x02,
-	0x5e, 0x78, 0xe3, 0x11, 0xf1, 0xc4, 0x3b, 0x7f, 0x00, 0x89, 0x5f, 0x80, 0xba, 0xaa, 0xfa, 0xe2,
-	0x9e, 0xb1, 0xc7, 0xe3, 0xb9, 0xc8, 0xfb, 0xe4, 0xae, 0x53, 0xb7, 0xaf, 0xbe, 0xf3, 0xd5, 0xa9,
-	0xd3, 0xd5, 0x86, 0xfc, 0x9c, 0x0d, 0x4d, 0x46, 0x9a, 0x53, 0xd7, 0x61, 0x0e, 0x4a, 0x89, 0x52,
-	0x2d, 0xf7, 0xe9, 0x8c, 0xb8, 0x67, 0xc2, 0x58, 0x2b, 0x32, 0x67, 0xea, 0x0c, 0x4c, 0x66, 0xca,
-	0x72, 0x6e, 0xce, 0xdc, 0x69, 0x5f, 0x14, 0xf4, 0x3f, 0x6a, 0x90, 0xee, 0x12, 0x4a, 0x2d, 0xc7,
-	0x46, 0xfb, 0x50, 0xb4, 0x6c, 0x83, 0xb9, 0xa6, 0x4d, 0xcd, 0x3e, 0xb3, 0x1c, 0xbb, 0xaa, 0xd4,
-	0x95, 0x46, 0x06, 0x17, 0x2c, 0xbb, 0x17, 0x1a, 0x51, 0x1b, 0x8a, 0xf4, 0xd4, 0x74, 0x07, 0x06,
-	0x15, 0xfd, 0x68, 0x55, 0xad, 0x6b, 0x8d, 0x5c, 0x6b, 0xb7, 0x29, 0xb1, 0xc8, 0xf1, 0x9a, 0x5d,
-	0xaf, 0x95, 0x2c, 0xe0, 0x02, 0x8d, 0x94, 0x28, 0x7a, 0x15, 0xb2, 0xd4, 0xb2, 0x87, 0x63, 0x62,
-	0x0c, 0x4e, 0xaa, 0x1a, 0x9f, 0x26, 0x23, 0x0c, 0x4f, 0x4e, 0xd0, 0x23, 0x00, 0x73, 0xc6, 0x9c,
-	0xbe, 0x33, 0x99, 0x58, 0xac, 0x9a, 0xe0, 0xb5, 0x11, 0x0b, 0xfa, 0x0a, 0x14, 0x98, 0xe9, 0x0e,
-	0x09, 0x33, 0x28, 0x73, 0x2d, 0x7b, 0x58, 0x4d, 0xd6, 0x95, 0x46, 0x16, 0xe7, 0x85, 0xb1, 0xcb,
-	0x6d, 0xe8, 0x6d, 0x48, 0x3b, 0x53, 0xc6, 0xf1, 0xa5, 0xea, 0x4a, 0x23, 0xd7, 0xba, 0xdf, 0x14,
-	0xac, 0x1c, 0xfe, 0x8c, 0xf4, 0x67, 0x8c, 0x7c, 0x28, 0x2a, 0xb1, 0xdf, 0x0a, 0x1d, 0x40, 0x39,
-	0xb2, 0x76, 0x63, 0xe2, 0x0c, 0x48, 0x35, 0x5d, 0x57, 0x1a, 0xc5, 0xd6, 0x43, 0x7f, 0x65, 0x11,
-	0x1a, 0x8e, 0x9c, 0x01, 0xc1, 0x25, 0xb6, 0x68, 0xa8, 0xfd, 0x04, 0xf2, 0xd1, 0x55, 0xa3, 0x7d,
-	0x48, 0x09, 0x50, 0x9c, 0xca, 0x5c, 0xab, 0x20, 0x31, 0xf4, 0xb8, 0x11, 0xcb, 0x4a, 0x8f, 0xf9,
-	0xe8, 0xd4, 0xd6, 0xa0, 0xaa, 0xd6, 0x95, 0x86, 0x86, 0x0b, 0x11, 0x6b, 0x67, 0xa0, 0xff, 0x53,
-	0x85, 0xa2, 0x44, 0x8f, 0xc9, 0xa7, 0x33, 0x42, 0x19, 0x7a, 0x0c, 0xd9, 0xbe, 0x39, 0x1e, 0x13,
-	0xd7, 0xeb, 0x24, 0xe6, 0x28, 0x35, 0x85, 0x83, 0xdb, 0xdc, 0xde, 0x79, 0x82, 0x33, 0xa2, 0x45,
-	0x67, 0x80, 0xde, 0x80, 0xb4, 0x74, 0x1a, 0x9f, 0x40, 0xb4, 0x8d, 0xfa, 0x0c, 0xfb, 0xf5, 0xe8,
-	0x75, 0x48, 0x72, 0xa8, 0xdc, 0x39, 0xb9, 0xd6, 0x8e, 0x04, 0x7e, 0xe0, 0xcc, 0xec, 0xc1, 0x0f,
-	0xbd, 0x47, 0x2c, 0xea, 0xd1, 0x37, 0x20, 0xc7, 0xcc, 0x93, 0x31, 0x61, 0x06, 0x3b, 0x9b, 0x12,
-	0xee, 0xad, 0x62, 0xab, 0xd2, 0x0c, 0x44, 0xd7, 0xe3, 0x95, 0xbd, 0xb3, 0x29, 0xc1, 0xc0, 0x82,
-	0x67, 0xf4, 0x18, 0x90, 0xed, 0x30, 0x23, 0x26, 0xb8, 0x24, 0xf7, 0x75, 0xd9, 0x76, 0x58, 0x67,
-	0x41, 0x73, 0xfb, 0x50, 0x1c, 0x91, 0x33, 0x3a, 0x35, 0xfb, 0xc4, 0xe0, 0x42, 0xe2, 0x3e, 0xcd,
-	0xe2, 0x82, 0x6f, 0xe5, 0xac, 0x47, 0x7d, 0x9e, 0x5e, 0xc7, 0xe7, 0xfa, 0x17, 0x0a, 0x94, 0x02,
-	0x46, 0xe9, 0xd4, 0xb1, 0x29, 0x41, 0xfb, 0x90, 0x24, 0xae, 0xeb, 0xb8, 0x31, 0x3a, 0xf1, 0x71,
-	0xfb, 0xd0, 0x33, 0x63, 0x51, 0x7b, 0x15, 0x2e, 0xdf, 0x84, 0x94, 0x4b, 0xe8, 0x6c, 0xcc, 0x24,
-	0x99, 0x48, 0xa2, 0x12, 0x3c, 0xf2, 0x1a, 0x2c, 0x5b, 0xe8, 0xff, 0x51, 0xa1, 0x22, 0x11, 0xf1,
-	0x35, 0xd1, 0xed, 0xf1, 0x74, 0x0d, 0x32, 0x3e, 0xdd, 0xdc, 0xcd, 0x59, 0x1c, 0x94, 0xd1, 0x03,
-	0x48, 0x71, 0xbf, 0xd0, 0x6a, 0xb2, 0xae, 0x35, 0xb2, 0x58, 0x96, 0xe2, 0xea, 0x48, 0x5d, 0x4b,
-	0x1d, 0xe9, 0x25, 0xea, 0x88, 0xb8, 0x3d, 0xb3, 0x96, 0xdb, 0x7f, 0xa5, 0xc0, 0xfd, 0x18, 0xc9,
-	0x5b, 0xe1, 0xfc, 0xff, 0xa9, 0xf0, 0x8a, 0xc4, 0xf5, 0x81, 0x64, 0xb6, 0xf3, 0xb2, 0x28, 0xe0,
-	0x35, 0xc8, 0x07, 0x5b, 0xd4, 0x92, 0x3a, 0xc8, 0xe3, 0xdc, 0x28, 0x5c, 0xc7, 0x96, 0x8a, 0xe1,
-	0x85, 0x02, 0xb5, 0x8b, 0x48, 0xdf, 0x0a, 0x45, 0x7c, 0xae, 0xc1, 0xc3, 0x10, 0x1c, 0x36, 0xed,
-	0x21, 0x79, 0x49, 0xf4, 0xf0, 0x0e, 0xc0, 0x88, 0x9c, 0x19, 0x2e, 0x87, 0xcc, 0xd5, 0xe0, 0xad,
-	0x34, 0xf0, 0xb5, 0xbf, 0x1a, 0x9c, 0x1d, 0xf9, 0xeb, 0xda, 0x52, 0x7d, 0xfc, 0x5a, 0x81, 0xea,
-	0x79, 0x17, 0x6c, 0x85, 0x3a, 0xfe, 0x92, 0x08, 0xd4, 0x71, 0x68, 0x33, 0x8b, 0x9d, 0xbd, 0x34,
-	0xd1, 0xe2, 0x31, 0x20, 0xc2, 0x11, 0x1b, 0x7d, 0x67, 0x3c, 0x9b, 0xd8, 0x86, 0x6d, 0x4e, 0x88,
-	0xcc, 0xe3, 0xca, 0xa2, 0xa6, 0xcd, 0x2b, 0x9e, 0x99, 0x13, 0x82, 0x7e, 0x04, 0xf7, 0x64, 0xeb,
-	0x85, 0x10, 0x93, 0xe2, 0xa2, 0x6a, 0xf8, 0x48, 0x97, 0x30, 0xd1, 0xf4, 0x0d, 0x78, 0x47, 0x0c,
-	0xf2, 0xc1, 0xf2, 0x90, 0x94, 0xbe, 0x96, 0xe4, 0x32, 0x97, 0x4b, 0x2e, 0xbb, 0x8e, 0xe4, 0x6a,
-	0x27, 0x90, 0xf1, 0x41, 0xa3, 0x3d, 0x48, 0x70, 0x68, 0x0a, 0x87, 0x96, 0xf3, 0x13, 0x48, 0x0f,
-	0x11, 0xaf, 0x40, 0x15, 0x48, 0xce, 0xcd, 0xf1, 0x8c, 0x70, 0xc7, 0xe5, 0xb1, 0x28, 0xa0, 0x3d,
-	0xc8, 0x45, 0xb8, 0xe2, 0xbe, 0xca, 0x63, 0x08, 0xa3, 0x71, 0x54, 0xd6, 0x11, 0xc6, 0xb6, 0x42,
-	0xd6, 0xff, 0x52, 0xe1, 0x9e, 0x84, 0x76, 0x60, 0xb2, 0xfe, 0xe9, 0xad, 0x4b, 0xfa, 0x2d, 0x48,
-	0x7b, 0x68, 0x2c, 0x42, 0xab, 0x1a, 0xd7, 0xd4, 0x05, 0xa2, 0xf6, 0x5b, 0x6c, 0x9a, 0xf0, 0xee,
-	0x43, 0xd1, 0xa4, 0x17, 0x24, 0xbb, 0x05, 0x93, 0xde, 0x45, 0xa6, 0xfb, 0x42, 0x09, 0xf2, 0x4a,
-	0xc9, 0xe9, 0xad, 0xb9, 0xfa, 0x6b, 0x90, 0x16, 0x8e, 0xf4, 0xd9, 0x7c, 0x20, 0xb1, 0x09, 0x37,
-	0x7f, 0x6c, 0xb1, 0x53, 0x31, 0xb4, 0xdf, 0x4c, 0xb7, 0xa1, 0xc4, 0x99, 0xe6, 0x6b, 0xe3, 0x74,
-	0x87, 0x51, 0x46, 0xb9, 0x42, 0x94, 0x51, 0x97, 0x66, 0xa5, 0x5a, 0x34, 0x2b, 0xd5, 0xff, 0x1c,
-	0xe6, 0x59, 0x9c, 0x8c, 0x3b, 0xca, 0xb4, 0xdf, 0x89, 0xcb, 0x2c, 0x78, 0xb1, 0x8c, 0xad, 0xfe,
-	0xae, 0xc4, 0x76, 0xd5, 0x77, 0x64, 0xfd, 0x37, 0x61, 0xae, 0xb4, 0x40, 0xdc, 0xad, 0x69, 0xe9,
-	0x71, 0x5c, 0x4b, 0x17, 0xc5, 0x8d, 0x40, 0x47, 0xbf, 0x80, 0x0a, 0x67, 0x32, 0x8c, 0xf0, 0x37,
-	0x28, 0xa6, 0x78, 0x82, 0xab, 0x9d, 0x4b, 0x70, 0xf5, 0xbf, 0xab, 0xf0, 0x28, 0x4a, 0xcf, 0x5d,
-	0x26, 0xf1, 0xdf, 0x8c, 0x8b, 0x6b, 0x77, 0x41, 0x5c, 0x31, 0x4a, 0xb6, 0x56, 0x61, 0xbf, 0x53,
-	0x60, 0x6f, 0x29, 0x85, 0x5b, 0x22, 0xb3, 0x3f, 0xa8, 0x50, 0xe9, 0x32, 0x97, 0x98, 0x93, 0x6b,
-	0xdd, 0xc6, 0x04, 0xaa, 0x54, 0xaf, 0x76, 0xc5, 0xa2, 0xad, 0xef, 0xa2, 0xd8, 0x51, 0x92, 0xb8,
-	0xe4, 0x28, 0x49, 0xae, 0x75, 0x51, 0x16, 0xe1, 0x35, 0xb5, 0x9a, 0x57, 0xbd, 0x0d, 0xf7, 0x63,
-	0x44, 0x49, 0x17, 0x86, 0xe9, 0x80, 0x72, 0x69, 0x3a, 0xf0, 0x85, 0x0a, 0xb5, 0x85, 0x51, 0xae,
-	0x13, 0xae, 0xd7, 0x26, 0x3d, 0x1a, 0x0a, 0xb4, 0xa5, 0xe7, 0x4a, 0x62, 0xd5, 0x6d, 0x47, 0x72,
-	0x4d, 0x47, 0x5d, 0x79, 0x93, 0x74, 0xe0, 0xd5, 0x0b, 0x09, 0xd9, 0x80, 0xdc, 0xdf, 0xaa, 0xb0,
-	0xb7, 0x30, 0xd6, 0xb5, 0x63, 0xd6, 0x8d, 0x30, 0x1c, 0x0f, 0xb6, 0x89, 0x4b, 0x6f, 0x13, 0x6e,
-	0x8d, 0xec, 0x67, 0x50, 0x5f, 0x4e, 0xd0, 0x06, 0x8c, 0xff, 0x49, 0x85, 0x2f, 0xc7, 0x07, 0xbc,
-	0xce, 0x8b, 0xfd, 0x8d, 0xf0, 0xbd, 0xf8, 0xb6, 0x9e, 0xd8, 0xe0, 0x6d, 0xfd, 0xd6, 0xf8, 0x7f,
-	0x0a, 0x8f, 0x96, 0xd1, 0xb5, 0x01, 0xfb, 0x3f, 0x86, 0xfc, 0x01, 0x19, 0x5a, 0xf6, 0x66, 0x5c,
-	0x2f, 0x7c, 0xb6, 0x50, 0x17, 0x3f, 0x5b, 0xe8, 0xdf, 0x81, 0x82, 0x1c, 0x5a, 0xe2, 0x8a, 0x04,
-	0x4a, 0xe5, 0x92, 0x40, 0xf9, 0xb9, 0x02, 0x85, 0x36, 0xff, 0xba, 0x71, 0xeb, 0x89, 0xc2, 0x03,
-	0x48, 0x99, 0xcc, 0x99, 0x58, 0x7d, 0xf9, 0xdd, 0x45, 0x96, 0xf4, 0x32, 0x14, 0x7d, 0x04, 0x02,
-	0xbf, 0xfe, 0x53, 0x28, 0x61, 0x67, 0x3c, 0x3e, 0x31, 0xfb, 0xa3, 0xdb, 0x46, 0xa5, 0x23, 0x28,
-	0x87, 0x73, 0xc9, 0xf9, 0x3f, 0x81, 0x57, 0x30, 0xa1, 0xce, 0x78, 0x4e, 0x22, 0x29, 0xc5, 0x66,
-	0x48, 0x10, 0x24, 0x06, 0x4c, 0x7e, 0x57, 0xc9, 0x62, 0xfe, 0xac, 0xff, 0x4d, 0x81, 0xca, 0x11,
-	0xa1, 0xd4, 0x1c, 0x12, 0x21, 0xb0, 0xcd, 0x86, 0x5e, 0x95, 0x33, 0x56, 0x20, 0x29, 0x4e, 0x5e,
-	0xb1, 0xdf, 0x44, 0x01, 0xbd, 0x0d, 0xd9, 0x60, 0xb3, 0xf1, 0x33, 0xf9, 0xe2, 0xbd, 0x96, 0xf1,
-	0xf7, 0x9a, 0x87, 0x3e, 0x72, 0x3f, 0xc2, 0x9f, 0xf5, 0x5f, 0x2a, 0xb0, 0x23, 0xd1, 0xbf, 0xb7,
-	0xa9, 0x7f, 0x56, 0x41, 0xf7, 0xe7, 0xd4, 0xc2, 0x39, 0xd1, 0x23, 0xd0, 0xfc, 0x60, 0x9c, 0x6b,
-	0xe5, 0xe5, 0x2e, 0xfb, 0xc8, 0x1c, 0xcf, 0x08, 0xf6, 0x2a, 0xf4, 0x23, 0xc8, 0x77, 0x22, 0x99,
-	0x26, 0xda, 0x05, 0x35, 0x80, 0xb1, 0xd8, 0x5c, 0xb5, 0x06, 0xf1, 0x2b, 0x0a, 0xf5, 0xdc, 0x15,
-	0xc5, 0x5f, 0x15, 0xd8, 0x0d, 0x97, 0x78, 0xed, 0x83, 0xe9, 0xaa, 0xab, 0xfd, 0x2e, 0x94, 0xac,
-	0x81, 0x71, 0xee, 0x18, 0xca, 0xb5, 0x2a, 0xbe, 0x8a, 0xa3, 0x8b, 0xc5, 0x05, 0x2b, 0x52, 0xa2,
-	0xfa, 0x2e, 0xd4, 0x2e, 0x12, 0xaf, 0x94, 0xf6, 0x7f, 0x55, 0xd8, 0xe9, 0x4e, 0xc7, 0x16, 0x93,
-	0x31, 0xea, 0xa6, 0xd7, 0xb3, 0xf6, 0x25, 0xdd, 0x6b, 0x90, 0xa7, 0x1e, 0x0e, 0x79, 0x0f, 0x27,
-	0x13, 0x9a, 0x1c, 0xb7, 0x89, 0x1b, 0x38, 0xcf, 0x4f, 0x7e, 0x93, 0x99, 0xcd, 0xb8, 0x08, 0x35,
-	0x0c, 0xb2, 0xc5, 0xcc, 0x66, 0xe8, 0xeb, 0xf0, 0xd0, 0x9e, 0x4d, 0x0c, 0xd7, 0xf9, 0x8c, 0x1a,
-	0x53, 0xe2, 0x1a, 0x7c, 0x64, 0x63, 0x6a, 0xba, 0x8c, 0x87, 0x78, 0x0d, 0xdf, 0xb3, 0x67, 0x13,
-	0xec, 0x7c, 0x46, 0x8f, 0x89, 0xcb, 0x27, 0x3f, 0x36, 0x5d, 0x86, 0xbe, 0x0f, 0x59, 0x73, 0x3c,
-	0x74, 0x5c, 0x8b, 0x9d, 0x4e, 0xe4, 0xc5, 0x9b, 0x2e, 0x61, 0x9e, 0x63, 0xa6, 0xf9, 0x9e, 0xdf,
-	0x12, 0x87, 0x9d, 0xd0, 0x5b, 0x80, 0x66, 0x94, 0x18, 0x02, 0x9c, 0x98, 0x74, 0xde, 0x92, 0xb7,
-	0x70, 0xa5, 0x19, 0x25, 0xe1, 0x30, 0x1f, 0xb5, 0xf4, 0x7f, 0x68, 0x80, 0xa2, 0xe3, 0xca, 0x18,
-	0xfd, 0x2d, 0x48, 0xf1, 0xfe, 0xb4, 0xaa, 0x70, 0xdf, 0xee, 0x05, 0x11, 0xea, 0x5c, 0xdb, 0xa6,
-	0x07, 0x1b, 0xcb, 0xe6, 0xb5, 0x4f, 0x20, 0xef, 0xef, 0x54, 0xbe, 0x9c, 0xa8, 0x37, 0x94, 0x95,
-	0xa7, 0xab, 0xba, 0xc6, 0xe9, 0x5a, 0xfb, 0x1e, 0x64, 0x79, 0x56, 0x77, 0xe9, 0xd8, 0x61, 0x2e,
-	0xaa, 0x46, 0x73, 0xd1, 0xda, 0xbf, 0x15, 0x48, 0xf0, 0xce, 0x6b, 0xbf, 0xfc, 0x1e, 0xf1, 0xf7,
-	0x05, 0x81, 0x52, 0x78, 0x4f, 0x04, 0xed, 0xd7, 0x57, 0x50, 0x12, 0xa5, 0x00, 0xe7, 0x47, 0x51,
-	0x42, 0xda, 0x00, 0xe2, 0x7f, 0x02, 0x7c, 0x28, 0xa1, 0xc3, 0xaf, 0xae, 0x18, 0x2a, 0x58, 0x2e,
-	0xce, 0xd2, 0x60, 0xe5, 0x08, 0x12, 0xd4, 0xfa, 0xb9, 0x88, 0x92, 0x1a, 0xe6, 0xcf, 0xfa, 0xbb,
-	0x70, 0xff, 0x7d, 0xc2, 0xba, 0xee, 0xdc, 0xdf, 0x6e, 0xfe, 0xf6, 0x59, 0x41, 0x93, 0x8e, 0xe1,
-	0x41, 0xbc, 0x93, 0x54, 0xc0, 0xb7, 0x21, 0x4f, 0xdd, 0xb9, 0xb1, 0xd0, 0xd3, 0xcb, 0x4a, 0x02,
-	0xf7, 0x44, 0x3b, 0xe5, 0x68, 0x58, 0xd0, 0x7f, 0xaf, 0xc2, 0xbd, 0xe7, 0xd3, 0x81, 0xc9, 0xb6,
-	0xfd, 0xfc, 0xd8, 0x30, 0x55, 0xdb, 0x85, 0x2c, 0xb3, 0x26, 0x84, 0x32, 0x73, 0x32, 0x95, 0x3b,
-	0x39, 0x34, 0x78, 0xba, 0x22, 0x73, 0x62, 0x33, 0x79, 0x01, 0xe9, 0xeb, 0xea, 0xd0, 0xb3, 0xf5,
-	0x9c, 0x11, 0xb1, 0xb1, 0xa8, 0xd7, 0x47, 0x50, 0x59, 0x64, 0x49, 0x12, 0xdf, 0xf0, 0x07, 0x58,
-	0xcc, 0xda, 0x64, 0xb2, 0xe7, 0xd5, 0xc8, 0x11, 0xd0, 0x1b, 0x50, 0xf6, 0xd2, 0xb7, 0x09, 0x31,
-	0x42, 0x3c, 0xe2, 0x1f, 0x12, 0x25, 0x61, 0xef, 0xf9, 0xe6, 0x37, 0x9f, 0x40, 0x29, 0xf6, 0x2f,
-	0x0d, 0x54, 0x82, 0xdc, 0xf3, 0x67, 0xdd, 0xe3, 0xc3, 0x76, 0xe7, 0x07, 0x9d, 0xc3, 0x27, 0xe5,
-	0x2f, 0x21, 0x80, 0x54, 0xb7, 0xf3, 0xec, 0xfd, 0xa7, 0x87, 0x65, 0x05, 0x65, 0x21, 0x79, 0xf4,
-	0xfc, 0x69, 0xaf, 0x53, 0x56, 0xbd, 0xc7, 0xde, 0xc7, 0x1f, 0x1e, 0xb7, 0xcb, 0xda, 0xc1, 0x0e,
-	0x94, 0x2c, 0xa7, 0x39, 0xb7, 0x18, 0xa1, 0x54, 0xfc, 0x53, 0xe6, 0x24, 0xc5, 0x7f, 0xde, 0xfd,
-	0x7f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xe8, 0x09, 0x06, 0xcb, 0x72, 0x23, 0x00, 0x00,
+	// 2048 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x5a, 0xcb, 0x6f, 0xe3, 0xc6,
+	0x19, 0x2f, 0x49, 0x3d, 0xac, 0x4f, 0x4f, 0xcf, 0xda, 0x5e, 0x45, 0x71, 0xd6, 0x0e, 0x53, 0x23,
+	0x4a, 0xb2, 0x50, 0x1a, 0xa5, 0x2f, 0xb4, 0x05, 0xda, 0x58, 0x76, 0x16, 0x42, 0xd6, 0x1b, 0x67,
+	0xec, 0xdd, 0x6d, 0x80, 0x06, 0x04, 0x2d, 0x4e, 0x6d, 0xd6, 0x12, 0xa9, 0x70, 0x86, 0xda, 0xba,
+	0x28, 0x8a, 0x1c, 0x7a, 0x0f, 0x7a, 0x28, 0x50, 0x04, 0x05, 0x8a, 0x02, 0x05, 0x7a, 0xea, 0xb5,
+	0x40, 0x1f, 0x87, 0xde, 0x7a, 0x2c, 0x7a, 0xea, 0xbd, 0xff, 0x40, 0x81, 0xfe, 0x05, 0x01, 0x67,
+	0x86, 0x4f, 0xbf, 0x64, 0xd9, 0x5e, 0x68, 0x4f, 0xe2, 0x7c, 0x33, 0x9c, 0xf9, 0x7d, 0xbf, 0xef,
+	0x37, 0x1f, 0x3f, 0x0d, 0x09, 0x95, 0x09, 0x3b, 0x34, 0x19, 0xe9, 0x8c, 0x3d, 0x97, 0xb9, 0xa8,
+	0x20, 0x5a, 0xad, 0xf2, 0xa7, 0x3e, 0xf1, 0x4e, 0x84, 0xb1, 0x55, 0x63, 0xee, 0xd8, 0xb5, 0x4c,
+	0x66, 0xca, 0x76, 0x79, 0xc2, 0xbc, 0xf1, 0x40, 0x34, 0xf4, 0x5f, 0xe6, 0xa1, 0xb8, 0x47, 0x28,
+	0xb5, 0x5d, 0x07, 0x6d, 0x40, 0xcd, 0x76, 0x0c, 0xe6, 0x99, 0x0e, 0x35, 0x07, 0xcc, 0x76, 0x9d,
+	0xa6, 0xb2, 0xae, 0xb4, 0x17, 0x70, 0xd5, 0x76, 0xf6, 0x63, 0x23, 0xea, 0x41, 0x8d, 0x1e, 0x99,
+	0x9e, 0x65, 0x50, 0x71, 0x1f, 0x6d, 0xaa, 0xeb, 0x5a, 0xbb, 0xdc, 0x5d, 0xed, 0x48, 0x2c, 0x72,
+	0xbe, 0xce, 0x5e, 0x30, 0x4a, 0x36, 0x70, 0x95, 0x26, 0x5a, 0x14, 0xbd, 0x0c, 0x25, 0x6a, 0x3b,
+	0x87, 0x43, 0x62, 0x58, 0x07, 0x4d, 0x8d, 0x2f, 0xb3, 0x20, 0x0c, 0x5b, 0x07, 0xe8, 0x1e, 0x80,
+	0xe9, 0x33, 0x77, 0xe0, 0x8e, 0x46, 0x36, 0x6b, 0xe6, 0x78, 0x6f, 0xc2, 0x82, 0x5e, 0x83, 0x2a,
+	0x33, 0xbd, 0x43, 0xc2, 0x0c, 0xca, 0x3c, 0xdb, 0x39, 0x6c, 0xe6, 0xd7, 0x95, 0x76, 0x09, 0x57,
+	0x84, 0x71, 0x8f, 0xdb, 0xd0, 0xdb, 0x50, 0x74, 0xc7, 0x8c, 0xe3, 0x2b, 0xac, 0x2b, 0xed, 0x72,
+	0x77, 0xb9, 0x23, 0x58, 0xd9, 0xfe, 0x29, 0x19, 0xf8, 0x8c, 0x7c, 0x28, 0x3a, 0x71, 0x38, 0x0a,
+	0x6d, 0x42, 0x23, 0xe1, 0xbb, 0x31, 0x72, 0x2d, 0xd2, 0x2c, 0xae, 0x2b, 0xed, 0x5a, 0xf7, 0x6e,
+	0xe8, 0x59, 0x82, 0x86, 0x1d, 0xd7, 0x22, 0xb8, 0xce, 0xd2, 0x06, 0xd4, 0x86, 0x86, 0x47, 0x4c,
+	0xcb, 0x38, 0x71, 0x7d, 0xcf, 0x78, 0xe6, 0xd9, 0x8c, 0xd0, 0xe6, 0x02, 0xc7, 0x5f, 0x0b, 0xec,
+	0x1f, 0xbb, 0xbe, 0xf7, 0x94, 0x5b, 0xd1, 0xfb, 0x50, 0xe7, 0xfd, 0xc6, 0xd8, 0xa5, 0xb6, 0x80,
+	0x59, 0xe2, 0x34, 0xbe, 0x92, 0xa5, 0x91, 0xdf, 0xb0, 0x2b, 0x47, 0xe1, 0xda, 0xb3, 0x64, 0x93,
+	0xb6, 0x7e, 0x04, 0x95, 0x24, 0xcf, 0x68, 0x03, 0x0a, 0x82, 0x06, 0x1e, 0xbc, 0x72, 0xb7, 0x2a,
+	0xbd, 0xde, 0xe7, 0x46, 0x2c, 0x3b, 0x83, 0x58, 0x27, 0x9d, 0xb5, 0xad, 0xa6, 0xba, 0xae, 0xb4,
+	0x35, 0x5c, 0x4d, 0x58, 0xfb, 0x56, 0xeb, 0xc7, 0x50, 0x4d, 0x2d, 0x8f, 0x5a, 0xb0, 0x70, 0x4c,
+	0x4e, 0xe8, 0xd8, 0x1c, 0x10, 0xbe, 0x40, 0x09, 0x47, 0x6d, 0xb4, 0x04, 0x79, 0x1e, 0x64, 0x3e,
+	0x55, 0x09, 0x8b, 0x46, 0x10, 0xac, 0x43, 0x66, 0x5b, 0x06, 0x11, 0xb4, 0x5b, 0x3c, 0xda, 0x25,
+	0x5c, 0x09, 0x8c, 0x32, 0x14, 0x96, 0xfe, 0x2f, 0x15, 0x6a, 0xb2, 0x81, 0xc9, 0xa7, 0x3e, 0xa1,
+	0x0c, 0xdd, 0x87, 0xd2, 0xc0, 0x1c, 0x0e, 0x89, 0x17, 0x80, 0x13, 0xbe, 0xd4, 0x3b, 0x42, 0xba,
+	0x3d, 0x6e, 0xef, 0x6f, 0xe1, 0x05, 0x31, 0xa2, 0x6f, 0xa1, 0x37, 0xa0, 0x28, 0xe5, 0xd8, 0x54,
+	0xa3, 0xb1, 0x49, 0x1a, 0x71, 0xd8, 0x8f, 0x5e, 0x87, 0x3c, 0xa7, 0x84, 0x03, 0x29, 0x77, 0x17,
+	0x25, 0x41, 0x9b, 0xae, 0xef, 0x58, 0x1f, 0x05, 0x97, 0x58, 0xf4, 0xa3, 0x6f, 0x40, 0x99, 0x99,
+	0x07, 0x43, 0xc2, 0x0c, 0x76, 0x32, 0x26, 0x5c, 0x87, 0xb5, 0xee, 0x52, 0x27, 0xda, 0x4e, 0xfb,
+	0xbc, 0x73, 0xff, 0x64, 0x4c, 0x30, 0xb0, 0xe8, 0x1a, 0xdd, 0x07, 0xe4, 0xb8, 0xcc, 0xc8, 0x6c,
+	0xa5, 0x3c, 0x57, 0x41, 0xc3, 0x71, 0x59, 0x3f, 0xb5, 0x9b, 0x36, 0xa0, 0x16, 0x12, 0x68, 0x08,
+	0xf6, 0x0a, 0x9c, 0x9f, 0x6a, 0x68, 0xe5, 0xd1, 0x4d, 0xaa, 0xb9, 0x38, 0x8d, 0x9a, 0xf5, 0xcf,
+	0x15, 0xa8, 0x47, 0x8c, 0xd2, 0xb1, 0xeb, 0x50, 0x82, 0x36, 0x20, 0x4f, 0x3c, 0xcf, 0xf5, 0x32,
+	0x74, 0xe2, 0xdd, 0xde, 0x76, 0x60, 0xc6, 0xa2, 0xf7, 0x2a, 0x5c, 0xbe, 0x09, 0x05, 0x8f, 0x50,
+	0x7f, 0xc8, 0x24, 0x99, 0x48, 0xa2, 0x12, 0x3c, 0xf2, 0x1e, 0x2c, 0x47, 0xe8, 0xff, 0x55, 0x61,
+	0x49, 0x22, 0xe2, 0x3e, 0xd1, 0xf9, 0x89, 0x74, 0x52, 0xd5, 0xb9, 0x8c, 0xaa, 0x57, 0xa0, 0xc0,
+	0xe3, 0x42, 0x9b, 0xf9, 0x75, 0xad, 0x5d, 0xc2, 0xb2, 0x95, 0x55, 0x47, 0xe1, 0x5a, 0xea, 0x28,
+	0x9e, 0xa3, 0x8e, 0x44, 0xd8, 0x17, 0xa6, 0x0a, 0xfb, 0xaf, 0x15, 0x58, 0xce, 0x90, 0x3c, 0x17,
+	0xc1, 0xff, 0xbf, 0x0a, 0x2f, 0x49, 0x5c, 0x1f, 0x48, 0x66, 0xfb, 0x2f, 0x8a, 0x02, 0x5e, 0x85,
+	0x4a, 0xb4, 0x45, 0x6d, 0xa9, 0x83, 0x0a, 0x2e, 0x1f, 0xc7, 0x7e, 0xcc, 0xa9, 0x18, 0xbe, 0x50,
+	0xa0, 0x75, 0x16, 0xe9, 0x73, 0xa1, 0x88, 0xcf, 0x34, 0xb8, 0x1b, 0x83, 0xc3, 0xa6, 0x73, 0x48,
+	0x5e, 0x10, 0x3d, 0xbc, 0x03, 0x70, 0x4c, 0x4e, 0x0c, 0x8f, 0x43, 0xe6, 0x6a, 0x08, 0x3c, 0x8d,
+	0x62, 0x1d, 0x7a, 0x83, 0x4b, 0xc7, 0xf2, 0x6a, 0x5e, 0xf5, 0xf1, 0x1b, 0x05, 0x9a, 0xa7, 0x43,
+	0x30, 0x17, 0xea, 0xf8, 0x4b, 0x2e, 0x52, 0xc7, 0xb6, 0xc3, 0x6c, 0x76, 0xf2, 0xc2, 0x64, 0x8b,
+	0xfb, 0x80, 0x08, 0x47, 0x6c, 0x0c, 0xdc, 0xa1, 0x3f, 0x72, 0x0c, 0xc7, 0x1c, 0x11, 0x59, 0xa1,
+	0x36, 0x44, 0x4f, 0x8f, 0x77, 0x3c, 0x32, 0x47, 0x04, 0xfd, 0x10, 0xee, 0xc8, 0xd1, 0xa9, 0x14,
+	0x53, 0xe0, 0xa2, 0x6a, 0x87, 0x48, 0xcf, 0x61, 0xa2, 0x13, 0x1a, 0xf0, 0xa2, 0x98, 0xe4, 0x83,
+	0xf3, 0x53, 0x52, 0xf1, 0x5a, 0x92, 0x5b, 0xb8, 0x5c, 0x72, 0xa5, 0x69, 0x24, 0xd7, 0x3a, 0x80,
+	0x85, 0x10, 0x34, 0x5a, 0x83, 0x1c, 0x87, 0xa6, 0x70, 0x68, 0xe5, 0xb0, 0x50, 0x0d, 0x10, 0xf1,
+	0x8e, 0xa0, 0xa0, 0x9c, 0x98, 0x43, 0x9f, 0xf0, 0xc0, 0x55, 0xb0, 0x68, 0xa0, 0x35, 0x28, 0x27,
+	0xb8, 0xe2, 0xb1, 0xaa, 0x60, 0x88, 0xb3, 0x71, 0x52, 0xd6, 0x09, 0xc6, 0xe6, 0x42, 0xd6, 0xff,
+	0x56, 0xe1, 0x8e, 0x84, 0xb6, 0x69, 0xb2, 0xc1, 0xd1, 0xad, 0x4b, 0xfa, 0x2d, 0x28, 0x06, 0x68,
+	0x6c, 0x42, 0x9b, 0xda, 0xba, 0x76, 0xb6, 0xa8, 0xc3, 0x11, 0xb3, 0x16, 0xbc, 0x1b, 0x50, 0x33,
+	0xe9, 0x19, 0xc5, 0x6e, 0xd5, 0xa4, 0xcf, 0xa3, 0xd2, 0xfd, 0x42, 0x81, 0xa5, 0x34, 0xa7, 0xb7,
+	0x16, 0xea, 0xaf, 0x41, 0x51, 0x04, 0x32, 0x64, 0x73, 0x45, 0x62, 0x13, 0x61, 0x7e, 0x6a, 0xb3,
+	0x23, 0x31, 0x75, 0x38, 0x4c, 0x77, 0xa0, 0xce, 0x99, 0xe6, 0xbe, 0x71, 0xba, 0xe3, 0x2c, 0xa3,
+	0x5c, 0x21, 0xcb, 0xa8, 0xe7, 0x56, 0xa5, 0x5a, 0xb2, 0x2a, 0xd5, 0xff, 0x1c, 0xd7, 0x59, 0x9c,
+	0x8c, 0xe7, 0x54, 0x69, 0xbf, 0x93, 0x95, 0x59, 0xf4, 0x97, 0x39, 0xe3, 0xfd, 0xf3, 0x12, 0xdb,
+	0x55, 0xff, 0xfd, 0xeb, 0xbf, 0x8d, 0x6b, 0xa5, 0x14, 0x71, 0xb7, 0xa6, 0xa5, 0xfb, 0x59, 0x2d,
+	0x9d, 0x95, 0x37, 0x22, 0x1d, 0xfd, 0x02, 0x96, 0x38, 0x93, 0x71, 0x86, 0xbf, 0x41, 0x31, 0x65,
+	0x0b, 0x5c, 0xed, 0x54, 0x81, 0xab, 0xff, 0x43, 0x85, 0x7b, 0x49, 0x7a, 0x9e, 0x67, 0x11, 0xff,
+	0xcd, 0xac, 0xb8, 0x56, 0x53, 0xe2, 0xca, 0x50, 0x32, 0xb7, 0x0a, 0xfb, 0xbd, 0x02, 0x6b, 0xe7,
+	0x52, 0x38, 0x27, 0x32, 0xfb, 0xa3, 0x0a, 0x4b, 0x7b, 0xcc, 0x23, 0xe6, 0xe8, 0x5a, 0xa7, 0x31,
+	0x91, 0x2a, 0xd5, 0xab, 0x1d, 0xb1, 0x68, 0xd3, 0x87, 0x28, 0xf3, 0x28, 0xc9, 0x5d, 0xf2, 0x28,
+	0xc9, 0x4f, 0x75, 0x04, 0x98, 0xe0, 0xb5, 0x70, 0x31, 0xaf, 0x7a, 0x0f, 0x96, 0x33, 0x44, 0xc9,
+	0x10, 0xc6, 0xe5, 0x80, 0x72, 0x69, 0x39, 0xf0, 0xb9, 0x0a, 0xad, 0xd4, 0x2c, 0xd7, 0x49, 0xd7,
+	0x53, 0x93, 0x9e, 0x4c, 0x05, 0xda, 0xb9, 0xcf, 0x95, 0xdc, 0x45, 0xa7, 0x1d, 0xf9, 0x29, 0x03,
+	0x75, 0xe5, 0x4d, 0xd2, 0x87, 0x97, 0xcf, 0x24, 0x64, 0x06, 0x72, 0x7f, 0xa7, 0xc2, 0x5a, 0x6a,
+	0xae, 0x6b, 0xe7, 0xac, 0x1b, 0x61, 0x38, 0x9b, 0x6c, 0x73, 0x97, 0x9e, 0x26, 0xdc, 0x1a, 0xd9,
+	0x8f, 0x60, 0xfd, 0x7c, 0x82, 0x66, 0x60, 0xfc, 0x4f, 0x2a, 0xbc, 0x92, 0x9d, 0xf0, 0x3a, 0x7f,
+	0xec, 0x6f, 0x84, 0xef, 0xf4, 0xbf, 0xf5, 0xdc, 0x0c, 0xff, 0xd6, 0x6f, 0x8d, 0xff, 0x87, 0x70,
+	0xef, 0x3c, 0xba, 0x66, 0x60, 0xff, 0x63, 0xa8, 0x6c, 0x92, 0x43, 0xdb, 0x99, 0x8d, 0xeb, 0xd4,
+	0x0b, 0x19, 0x35, 0xfd, 0x42, 0x46, 0xff, 0x0e, 0x54, 0xe5, 0xd4, 0x12, 0x57, 0x22, 0x51, 0x2a,
+	0x97, 0x24, 0xca, 0xcf, 0x14, 0xa8, 0xf6, 0xf8, 0x7b, 0x9b, 0x5b, 0x2f, 0x14, 0x56, 0xa0, 0x60,
+	0x32, 0x77, 0x64, 0x0f, 0xe4, 0x1b, 0x25, 0xd9, 0xd2, 0x1b, 0x50, 0x0b, 0x11, 0x08, 0xfc, 0xfa,
+	0x4f, 0xa0, 0x8e, 0xdd, 0xe1, 0xf0, 0xc0, 0x1c, 0x1c, 0xdf, 0x36, 0x2a, 0x1d, 0x41, 0x23, 0x5e,
+	0x4b, 0xae, 0xff, 0x09, 0xbc, 0x84, 0x09, 0x75, 0x87, 0x13, 0x92, 0x28, 0x29, 0x66, 0x43, 0x82,
+	0x20, 0x67, 0x31, 0x3b, 0x7c, 0xe9, 0xc2, 0xaf, 0xf5, 0xbf, 0x29, 0xb0, 0xb4, 0x43, 0x28, 0x35,
+	0x0f, 0x89, 0x10, 0xd8, 0x6c, 0x53, 0x5f, 0x54, 0x33, 0x46, 0x2f, 0x7b, 0xb4, 0xe4, 0xcb, 0x9e,
+	0xb7, 0xa1, 0x14, 0x6d, 0xb6, 0x66, 0x4e, 0x4a, 0xf6, 0xf4, 0x5e, 0x5b, 0x08, 0xf7, 0x5a, 0x80,
+	0x3e, 0x71, 0x3e, 0xc2, 0xaf, 0xf5, 0x5f, 0x29, 0xb0, 0x28, 0xd1, 0xbf, 0x37, 0x38, 0xbe, 0x79,
+	0xe8, 0xe1, 0x9a, 0x5a, 0xbc, 0x26, 0xba, 0x07, 0x5a, 0x98, 0x8c, 0xcb, 0xdd, 0x8a, 0xdc, 0x65,
+	0x4f, 0xcc, 0xa1, 0x4f, 0x70, 0xd0, 0xa1, 0xef, 0x40, 0xa5, 0x9f, 0xa8, 0x34, 0xd1, 0x2a, 0xa8,
+	0x11, 0x8c, 0xf4, 0x70, 0xd5, 0xb6, 0xb2, 0x47, 0x14, 0xea, 0xa9, 0x23, 0x8a, 0xbf, 0x2a, 0xb0,
+	0x1a, 0xbb, 0x78, 0xed, 0x07, 0xd3, 0x55, 0xbd, 0xfd, 0x1e, 0xd4, 0x6d, 0xcb, 0x38, 0xf5, 0x18,
+	0x2a, 0x77, 0x97, 0x42, 0x15, 0x27, 0x9d, 0xc5, 0x55, 0x3b, 0xd1, 0xa2, 0xfa, 0x2a, 0xb4, 0xce,
+	0x12, 0xaf, 0x94, 0xf6, 0xff, 0x54, 0x58, 0xdc, 0x1b, 0x0f, 0x6d, 0x26, 0x73, 0xd4, 0x4d, 0xfb,
+	0x33, 0xf5, 0x21, 0xdd, 0xab, 0x50, 0xa1, 0x01, 0x0e, 0x79, 0x0e, 0x27, 0x0b, 0x9a, 0x32, 0xb7,
+	0x89, 0x13, 0xb8, 0x20, 0x4e, 0xe1, 0x10, 0xdf, 0x61, 0x5c, 0x84, 0x1a, 0x06, 0x39, 0xc2, 0x77,
+	0x18, 0xfa, 0x3a, 0xdc, 0x75, 0xfc, 0x91, 0xe1, 0xb9, 0xcf, 0xa8, 0x31, 0x26, 0x9e, 0xc1, 0x67,
+	0x36, 0xc6, 0xa6, 0xc7, 0x78, 0x8a, 0xd7, 0xf0, 0x1d, 0xc7, 0x1f, 0x61, 0xf7, 0x19, 0xdd, 0x25,
+	0x1e, 0x5f, 0x7c, 0xd7, 0xf4, 0x18, 0xfa, 0x01, 0x94, 0xcc, 0xe1, 0xa1, 0xeb, 0xd9, 0xec, 0x68,
+	0x24, 0x0f, 0xde, 0x74, 0x09, 0xf3, 0x14, 0x33, 0x9d, 0xf7, 0xc2, 0x91, 0x38, 0xbe, 0x09, 0xbd,
+	0x05, 0xc8, 0xa7, 0xc4, 0x10, 0xe0, 0xc4, 0xa2, 0x93, 0xae, 0x3c, 0x85, 0xab, 0xfb, 0x94, 0xc4,
+	0xd3, 0x3c, 0xe9, 0xea, 0xff, 0xd4, 0x00, 0x25, 0xe7, 0x95, 0x39, 0xfa, 0x5b, 0x50, 0xe0, 0xf7,
+	0xd3, 0xa6, 0xc2, 0x63, 0xbb, 0x16, 0x65, 0xa8, 0x53, 0x63, 0x3b, 0x01, 0x6c, 0x2c, 0x87, 0xb7,
+	0x3e, 0x81, 0x4a, 0xb8, 0x53, 0xb9, 0x3b, 0x17, 0xbd, 0xf3, 0x4d, 0x3f, 0x5d, 0xd5, 0x29, 0x9e,
+	0xae, 0xad, 0xef, 0x43, 0x89, 0x57, 0x75, 0x97, 0xce, 0x1d, 0xd7, 0xa2, 0x6a, 0xb2, 0x16, 0x6d,
+	0xfd, 0x47, 0x81, 0x1c, 0xbf, 0x79, 0xea, 0x3f, 0xbf, 0x3b, 0x50, 0x8b, 0x50, 0x8a, 0xe8, 0x89,
+	0xa4, 0xfd, 0xfa, 0x05, 0x94, 0x24, 0x29, 0xc0, 0x95, 0xe3, 0x44, 0x0b, 0xf5, 0x00, 0xc4, 0x17,
+	0x10, 0x7c, 0x2a, 0xa1, 0xc3, 0xaf, 0x5e, 0x30, 0x55, 0xe4, 0x2e, 0x2e, 0xd1, 0xc8, 0x73, 0x04,
+	0x39, 0x6a, 0xff, 0x4c, 0x64, 0x49, 0x0d, 0xf3, 0x6b, 0xfd, 0x5d, 0x58, 0x7e, 0x40, 0xd8, 0x9e,
+	0x37, 0x09, 0xb7, 0x5b, 0xb8, 0x7d, 0x2e, 0xa0, 0x49, 0xc7, 0xb0, 0x92, 0xbd, 0x49, 0x2a, 0xe0,
+	0xdb, 0x50, 0xa1, 0xde, 0xc4, 0x48, 0xdd, 0x19, 0x54, 0x25, 0x51, 0x78, 0x92, 0x37, 0x95, 0x69,
+	0xdc, 0xd0, 0xff, 0xa0, 0xc2, 0x9d, 0xc7, 0x63, 0xcb, 0x64, 0xf3, 0xfe, 0xfc, 0x98, 0xb1, 0x54,
+	0x5b, 0x85, 0x12, 0xb3, 0x47, 0x84, 0x32, 0x73, 0x34, 0x96, 0x3b, 0x39, 0x36, 0x04, 0xba, 0x22,
+	0x13, 0xe2, 0xb0, 0x66, 0x31, 0xa5, 0xab, 0xed, 0xc0, 0xb6, 0xef, 0x1e, 0x13, 0x07, 0x8b, 0x7e,
+	0xfd, 0x18, 0x96, 0xd2, 0x2c, 0x49, 0xe2, 0xdb, 0xe1, 0x04, 0xe9, 0xaa, 0x4d, 0x16, 0x7b, 0x41,
+	0x8f, 0x9c, 0x01, 0xbd, 0x11, 0x7c, 0x30, 0x42, 0xfd, 0x11, 0x31, 0x62, 0x3c, 0xe2, 0x4b, 0x8c,
+	0xba, 0xb0, 0xef, 0x87, 0x66, 0xfd, 0x23, 0xb9, 0x6f, 0x1e, 0x30, 0xdb, 0x9a, 0xe1, 0x3b, 0x0c,
+	0x04, 0xb9, 0xe0, 0x93, 0x8b, 0xf0, 0x39, 0x10, 0x5c, 0xeb, 0xdf, 0x85, 0xfc, 0x13, 0x3e, 0x5d,
+	0x17, 0xca, 0x42, 0xd1, 0x81, 0x39, 0x4c, 0x18, 0x8b, 0x91, 0xa4, 0xc3, 0x65, 0x31, 0xd0, 0xf0,
+	0x92, 0xea, 0x7f, 0x57, 0xc2, 0xb3, 0x82, 0xde, 0xd1, 0x35, 0x8a, 0xfc, 0x8b, 0x44, 0x32, 0xe3,
+	0xf1, 0xc0, 0x6b, 0x90, 0x9f, 0x70, 0x5f, 0x73, 0xf2, 0x13, 0x18, 0xe9, 0x07, 0xf7, 0x15, 0x8b,
+	0x3e, 0xfd, 0xe7, 0xb0, 0x9c, 0x41, 0x2f, 0x83, 0x17, 0xd1, 0xa7, 0x24, 0xe9, 0x8b, 0x42, 0xaa,
+	0xa6, 0x42, 0x2a, 0x6e, 0x4e, 0x85, 0x34, 0x5a, 0x5d, 0xbb, 0x60, 0xf5, 0xe0, 0x3d, 0xbd, 0x2c,
+	0x00, 0x38, 0x6d, 0x3e, 0x99, 0x8f, 0x3a, 0x67, 0x0b, 0x56, 0xb2, 0xb0, 0xae, 0xfe, 0x57, 0xe4,
+	0xcd, 0x2d, 0xa8, 0x67, 0x3e, 0x95, 0x42, 0x75, 0x28, 0x3f, 0x7e, 0xb4, 0xb7, 0xbb, 0xdd, 0xeb,
+	0xbf, 0xdf, 0xdf, 0xde, 0x6a, 0x7c, 0x05, 0x01, 0x14, 0xf6, 0xfa, 0x8f, 0x1e, 0x3c, 0xdc, 0x6e,
+	0x28, 0xa8, 0x04, 0xf9, 0x9d, 0xc7, 0x0f, 0xf7, 0xfb, 0x0d, 0x35, 0xb8, 0xdc, 0x7f, 0xfa, 0xe1,
+	0x6e, 0xaf, 0xa1, 0x6d, 0x2e, 0x42, 0xdd, 0x76, 0x3b, 0x13, 0x9b, 0x11, 0x4a, 0xc5, 0xe7, 0x6a,
+	0x07, 0x05, 0xfe, 0xf3, 0xee, 0x97, 0x03, 0x00, 0x95, 0xbe, 0x8f, 0xc5, 0xf7, 0x26, 0x00, 0x00,
 }
@@ -109,6 +109,8 @@ type VitessClient interface {
 	// MessageAckKeyspaceIds routes Message Acks using the associated
 	// keyspace ids.
 	MessageAckKeyspaceIds(ctx context.Context, in *vtgate.MessageAckKeyspaceIdsRequest, opts ...grpc.CallOption) (*query.MessageAckResponse, error)
+	// MessageRequeue requeues the failed messages of a table.
+	MessageRequeue(ctx context.Context, in *vtgate.MessageRequeueRequest, opts ...grpc.CallOption) (*vtgate.MessageRequeueResponse, error)
 	// Split a query into non-overlapping sub queries
 	// API group: Map Reduce
 	SplitQuery(ctx context.Context, in *vtgate.SplitQueryRequest, opts ...grpc.CallOption) (*vtgate.SplitQueryResponse, error)
@@ -124,6 +126,10 @@ type VitessClient interface {
 	// UpdateStream asks the server for a stream of StreamEvent objects.
 	// API group: Update Stream
 	UpdateStream(ctx context.Context, in *vtgate.UpdateStreamRequest, opts ...grpc.CallOption) (Vitess_UpdateStreamClient, error)
+	// StreamChanges asks the server for the change data capture stream
+	// of a keyspace: the transactions of all its shards, merged.
+	// API group: Update Stream
+	StreamChanges(ctx context.Context, in *vtgate.StreamChangesRequest, opts ...grpc.CallOption) (Vitess_StreamChangesClient, error)
 }
 
 type vitessClient struct {
@@ -420,6 +426,15 @@ func (c *vitessClient) MessageAckKeyspaceIds(ctx context.Context, in *vtgate.Mes
 	return out, nil
 }
 
+func (c *vitessClient) MessageRequeue(ctx context.Context, in *vtgate.MessageRequeueRequest, opts ...grpc.CallOption) (*vtgate.MessageRequeueResponse, error) {
+	out := new(vtgate.MessageRequeueResponse)
+	err := grpc.Invoke(ctx, "/vtgateservice.Vitess/MessageRequeue", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *vitessClient) SplitQuery(ctx context.Context, in *vtgate.SplitQueryRequest, opts ...grpc.CallOption) (*vtgate.SplitQueryResponse, error) {
 	out := new(vtgate.SplitQueryResponse)
 	err := grpc.Invoke(ctx, "/vtgateservice.Vitess/SplitQuery", in, out, c.cc, opts...)
@@ -470,6 +485,38 @@ func (x *vitessUpdateStreamClient) Recv() (*vtgate.UpdateStreamResponse, error)
 	return m, nil
 }
 
+func (c *vitessClient) StreamChanges(ctx context.Context, in *vtgate.StreamChangesRequest, opts ...grpc.CallOption) (Vitess_StreamChangesClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Vitess_serviceDesc.Streams[6], c.cc, "/vtgateservice.Vitess/StreamChanges", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &vitessStreamChangesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Vitess_StreamChangesClient interface {
+	Recv() (*vtgate.StreamChangesResponse, error)
+	grpc.ClientStream
+}
+
+type vitessStreamChangesClient struct {
+	grpc.ClientStream
+}
+
+func (x *vitessStreamChangesClient) Recv() (*vtgate.StreamChangesResponse, error) {
+	m := new(vtgate.StreamChangesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Vitess service
 
 type VitessServer interface {
@@ -538,6 +585,8 @@ type VitessServer interface {
 	// MessageAckKeyspaceIds routes Message Acks using the associated
 	// keyspace ids.
 	MessageAckKeyspaceIds(context.Context, *vtgate.MessageAckKeyspaceIdsRequest) (*query.MessageAckResponse, error)
+	// MessageRequeue requeues the failed messages of a table.
+	MessageRequeue(context.Context, *vtgate.MessageRequeueRequest) (*vtgate.MessageRequeueResponse, error)
 	// Split a query into non-overlapping sub queries
 	// API group: Map Reduce
 	SplitQuery(context.Context, *vtgate.SplitQueryRequest) (*vtgate.SplitQueryResponse, error)
@@ -553,6 +602,10 @@ type VitessServer interface {
 	// UpdateStream asks the server for a stream of StreamEvent objects.
 	// API group: Update Stream
 	UpdateStream(*vtgate.UpdateStreamRequest, Vitess_UpdateStreamServer) error
+	// StreamChanges asks the server for the change data capture stream
+	// of a keyspace: the transactions of all its shards, merged.
+	// API group: Update Stream
+	StreamChanges(*vtgate.StreamChangesRequest, Vitess_StreamChangesServer) error
 }
 
 func RegisterVitessServer(s *grpc.Server, srv VitessServer) {
@@ -916,6 +969,24 @@ func _Vitess_MessageAckKeyspaceIds_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Vitess_MessageRequeue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(vtgate.MessageRequeueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VitessServer).MessageRequeue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vtgateservice.Vitess/MessageRequeue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VitessServer).MessageRequeue(ctx, req.(*vtgate.MessageRequeueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Vitess_SplitQuery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(vtgate.SplitQueryRequest)
 	if err := dec(in); err != nil {
@@ -973,6 +1044,27 @@ func (x *vitessUpdateStreamServer) Send(m *vtgate.UpdateStreamResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Vitess_StreamChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(vtgate.StreamChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VitessServer).StreamChanges(m, &vitessStreamChangesServer{stream})
+}
+
+type Vitess_StreamChangesServer interface {
+	Send(*vtgate.StreamChangesResponse) error
+	grpc.ServerStream
+}
+
+type vitessStreamChangesServer struct {
+	grpc.ServerStream
+}
+
+func (x *vitessStreamChangesServer) Send(m *vtgate.StreamChangesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Vitess_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "vtgateservice.Vitess",
 	HandlerType: (*VitessServer)(nil),
@@ -1033,6 +1125,10 @@ var _Vitess_serviceDesc = grpc.ServiceDesc{
 			MethodName: "MessageAckKeyspaceIds",
 			Handler:    _Vitess_MessageAckKeyspaceIds_Handler,
 		},
+		{
+			MethodName: "MessageRequeue",
+			Handler:    _Vitess_MessageRequeue_Handler,
+		},
 		{
 			MethodName: "SplitQuery",
 			Handler:    _Vitess_SplitQuery_Handler,
@@ -1073,6 +1169,11 @@ var _Vitess_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Vitess_UpdateStream_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamChanges",
+			Handler:       _Vitess_StreamChanges_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "vtgateservice.proto",
 }
@@ -1080,40 +1181,42 @@ var _Vitess_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("vtgateservice.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 559 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x95, 0xdb, 0x8b, 0xd3, 0x40,
-	0x14, 0xc6, 0xf5, 0xc1, 0x55, 0x8e, 0xed, 0x22, 0xb3, 0xbb, 0xdd, 0xdd, 0x7a, 0xdd, 0xaa, 0xbb,
-	0x3e, 0x15, 0x51, 0x10, 0x04, 0x41, 0x5a, 0x2d, 0x22, 0xcb, 0xaa, 0xdb, 0x7a, 0x01, 0xc1, 0x87,
-	0x69, 0x7a, 0xc8, 0x86, 0xa6, 0x49, 0x9a, 0x99, 0x06, 0xfb, 0x0f, 0xfa, 0x77, 0xc9, 0x26, 0x73,
-	0xcf, 0xa4, 0x7d, 0x4b, 0xbe, 0xef, 0x3b, 0xbf, 0xcc, 0x9c, 0x1c, 0x66, 0x60, 0xaf, 0xe0, 0x21,
-	0xe5, 0xc8, 0x30, 0x2f, 0xa2, 0x00, 0xfb, 0x59, 0x9e, 0xf2, 0x94, 0xb4, 0x2d, 0xb1, 0xdb, 0xaa,
-	0x5e, 0x2b, 0xb3, 0x7b, 0x77, 0xb9, 0xc2, 0x7c, 0x5d, 0xbd, 0xbc, 0xfa, 0xb7, 0x0b, 0x3b, 0x3f,
-	0x23, 0x8e, 0x8c, 0x91, 0x77, 0x70, 0x7b, 0xf4, 0x17, 0x83, 0x15, 0x47, 0xd2, 0xe9, 0x8b, 0x0a,
-	0x21, 0x8c, 0x71, 0xb9, 0x42, 0xc6, 0xbb, 0x87, 0x35, 0x9d, 0x65, 0x69, 0xc2, 0xb0, 0x77, 0x83,
-	0x9c, 0x43, 0x4b, 0x88, 0x43, 0xca, 0x83, 0x2b, 0x72, 0xdf, 0x89, 0x96, 0xaa, 0xe4, 0x3c, 0xf0,
-	0x9b, 0x0a, 0xf6, 0x0d, 0xda, 0x13, 0x9e, 0x23, 0x5d, 0xc8, 0x05, 0xa9, 0x02, 0x4b, 0x96, 0xb8,
-	0x87, 0x0d, 0xae, 0xe4, 0xbd, 0xbc, 0x49, 0xbe, 0x40, 0x5b, 0xc8, 0x93, 0x2b, 0x9a, 0xcf, 0x18,
-	0x71, 0x97, 0x50, 0xc9, 0x35, 0xa2, 0xe3, 0xaa, 0x15, 0xfe, 0x01, 0x22, 0xac, 0x73, 0x5c, 0xb3,
-	0x8c, 0x06, 0xf8, 0x79, 0xc6, 0xc8, 0x89, 0x53, 0x66, 0x78, 0x92, 0xdc, 0xdb, 0x14, 0x51, 0xf8,
-	0x5f, 0x70, 0x4f, 0xfb, 0x63, 0x9a, 0x84, 0xc8, 0xc8, 0xe3, 0x7a, 0x65, 0xe5, 0x48, 0xf4, 0x93,
-	0xe6, 0x80, 0x07, 0x3c, 0x4a, 0x78, 0xc4, 0xd7, 0xd7, 0xab, 0x76, 0xc1, 0xca, 0x69, 0x02, 0x1b,
-	0x01, 0x4f, 0x43, 0xca, 0x9f, 0x29, 0xba, 0x7c, 0xe2, 0xfb, 0xd1, 0x76, 0xab, 0x7b, 0x9b, 0x22,
-	0x0a, 0x1f, 0xc3, 0xa1, 0xe9, 0x9b, 0x4d, 0x3f, 0xf5, 0x01, 0x3c, 0x9d, 0x3f, 0xdb, 0x9a, 0x53,
-	0x5f, 0x9b, 0xc2, 0x9e, 0x35, 0x4a, 0x62, 0x37, 0x3d, 0xef, 0x9c, 0xd9, 0xdb, 0x79, 0xba, 0x31,
-	0x63, 0x4c, 0xe4, 0x12, 0x8e, 0xac, 0x88, 0xb9, 0xa5, 0x33, 0x2f, 0xc4, 0xb3, 0xa7, 0x17, 0xdb,
-	0x83, 0xc6, 0x27, 0xe7, 0xd0, 0x71, 0x73, 0x62, 0xb6, 0x9e, 0x37, 0x71, 0xec, 0x09, 0x3b, 0xdd,
-	0x16, 0x33, 0x3e, 0xf6, 0x06, 0x6e, 0x0d, 0x31, 0x8c, 0x12, 0xb2, 0x2f, 0x8b, 0xca, 0x57, 0x89,
-	0x3a, 0x70, 0x54, 0xd5, 0xfb, 0xb7, 0xb0, 0xf3, 0x21, 0x5d, 0x2c, 0x22, 0x4e, 0x54, 0xa4, 0x7a,
-	0x97, 0x95, 0x1d, 0x57, 0x56, 0xa5, 0xef, 0xe1, 0xce, 0x38, 0x8d, 0xe3, 0x29, 0x0d, 0xe6, 0x44,
-	0x1d, 0x55, 0x52, 0x91, 0xe5, 0x47, 0x75, 0xc3, 0x1c, 0xe2, 0x31, 0xb2, 0x34, 0x2e, 0xf0, 0x7b,
-	0x4e, 0x13, 0x46, 0x03, 0x1e, 0xa5, 0x89, 0x1e, 0xe2, 0xba, 0x57, 0x1b, 0x62, 0x5f, 0x44, 0xe1,
-	0xbf, 0x42, 0xfb, 0x02, 0x19, 0xa3, 0x21, 0x56, 0xfd, 0xd3, 0x87, 0x90, 0x25, 0xeb, 0x53, 0xb2,
-	0x3a, 0xa9, 0x1d, 0xd3, 0xe8, 0xf1, 0x47, 0x00, 0x61, 0x0e, 0x82, 0x39, 0x39, 0x76, 0x68, 0x03,
-	0xbd, 0xe9, 0x63, 0x1b, 0x35, 0xb0, 0x76, 0xfd, 0x1b, 0x0e, 0xb4, 0x6e, 0x8e, 0xe1, 0xb3, 0x3a,
-	0xd0, 0x33, 0x83, 0x1b, 0xd9, 0x23, 0x80, 0x49, 0x16, 0x47, 0xfc, 0xf2, 0x3a, 0xa2, 0x57, 0xa8,
-	0x35, 0x49, 0xe9, 0xfa, 0x2c, 0x85, 0xb9, 0x84, 0xdd, 0x4f, 0xc8, 0x27, 0x79, 0x21, 0xbf, 0x4f,
-	0xd4, 0x09, 0x6d, 0xeb, 0x12, 0xf7, 0xa8, 0xc9, 0x56, 0xc8, 0x0b, 0x68, 0xfd, 0xc8, 0x66, 0x94,
-	0xcb, 0x7f, 0xa1, 0x2e, 0x2c, 0x53, 0xad, 0x5d, 0x58, 0xb6, 0xa9, 0x7f, 0xc5, 0xb0, 0x03, 0xfb,
-	0x51, 0xda, 0x2f, 0xca, 0xab, 0xb4, 0xba, 0x5b, 0xfb, 0x61, 0x9e, 0x05, 0xd3, 0x9d, 0xf2, 0xf9,
-	0xf5, 0xff, 0x00, 0x00, 0x00, 0xff, 0xff, 0x58, 0xd7, 0x14, 0x4d, 0xa8, 0x07, 0x00, 0x00,
+	// 587 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x96, 0xdf, 0x6f, 0x12, 0x41,
+	0x10, 0xc7, 0xf5, 0xc1, 0x6a, 0x46, 0x50, 0xb3, 0x6d, 0x69, 0x8b, 0x56, 0x2d, 0x6a, 0xeb, 0x13,
+	0x31, 0x9a, 0x98, 0x98, 0x98, 0x18, 0xa8, 0xc4, 0x34, 0x4d, 0xd5, 0x82, 0x3f, 0x12, 0x13, 0x1f,
+	0x96, 0x63, 0x02, 0x17, 0xe0, 0xee, 0xb8, 0x5d, 0x88, 0xfc, 0xcd, 0xfe, 0x13, 0xc6, 0xbb, 0xfd,
+	0xbd, 0x7b, 0xf0, 0xd6, 0xfb, 0x7e, 0x67, 0x3e, 0xbb, 0x33, 0x3b, 0xdd, 0x05, 0x76, 0x57, 0x7c,
+	0x4c, 0x39, 0x32, 0xcc, 0x57, 0x71, 0x84, 0xed, 0x2c, 0x4f, 0x79, 0x4a, 0xea, 0x96, 0xd8, 0xac,
+	0x95, 0x9f, 0xa5, 0xd9, 0xbc, 0xbb, 0x58, 0x62, 0xbe, 0x2e, 0x3f, 0x5e, 0xff, 0xbd, 0x0f, 0x3b,
+	0x3f, 0x62, 0x8e, 0x8c, 0x91, 0xf7, 0x70, 0xbb, 0xf7, 0x07, 0xa3, 0x25, 0x47, 0xd2, 0x68, 0x8b,
+	0x0c, 0x21, 0xf4, 0x71, 0xb1, 0x44, 0xc6, 0x9b, 0x07, 0x9e, 0xce, 0xb2, 0x34, 0x61, 0xd8, 0xba,
+	0x41, 0x2e, 0xa1, 0x26, 0xc4, 0x2e, 0xe5, 0xd1, 0x84, 0x3c, 0x74, 0x42, 0x0b, 0x55, 0x72, 0x1e,
+	0x85, 0x4d, 0x05, 0xfb, 0x0a, 0xf5, 0x01, 0xcf, 0x91, 0xce, 0xe5, 0x86, 0x54, 0x82, 0x25, 0x4b,
+	0xdc, 0x71, 0x85, 0x2b, 0x79, 0xaf, 0x6e, 0x92, 0xcf, 0x50, 0x17, 0xf2, 0x60, 0x42, 0xf3, 0x11,
+	0x23, 0xee, 0x16, 0x4a, 0xd9, 0x23, 0x3a, 0xae, 0xda, 0xe1, 0x6f, 0x20, 0xc2, 0xba, 0xc4, 0x35,
+	0xcb, 0x68, 0x84, 0x17, 0x23, 0x46, 0x4e, 0x9c, 0x34, 0xc3, 0x93, 0xe4, 0xd6, 0xa6, 0x10, 0x85,
+	0xff, 0x09, 0x0f, 0xb4, 0xdf, 0xa7, 0xc9, 0x18, 0x19, 0x79, 0xe2, 0x67, 0x96, 0x8e, 0x44, 0x3f,
+	0xad, 0x0e, 0x08, 0x80, 0x7b, 0x09, 0x8f, 0xf9, 0xfa, 0x62, 0xe4, 0x83, 0x95, 0x53, 0x05, 0x36,
+	0x02, 0x02, 0x0d, 0x29, 0x0e, 0x53, 0x74, 0xf9, 0x24, 0x74, 0xd0, 0x76, 0xab, 0x5b, 0x9b, 0x42,
+	0x14, 0x7e, 0x06, 0x07, 0xa6, 0x6f, 0x36, 0xfd, 0x34, 0x04, 0x08, 0x74, 0xfe, 0x6c, 0x6b, 0x9c,
+	0x5a, 0x6d, 0x08, 0xbb, 0xd6, 0x28, 0x89, 0x6a, 0x5a, 0xc1, 0x39, 0xb3, 0xcb, 0x79, 0xb6, 0x31,
+	0xc6, 0x98, 0xc8, 0x05, 0x1c, 0x5a, 0x21, 0x66, 0x49, 0x67, 0x41, 0x48, 0xa0, 0xa6, 0x97, 0xdb,
+	0x03, 0x8d, 0x25, 0xa7, 0xd0, 0x70, 0xe3, 0xc4, 0x6c, 0xbd, 0xa8, 0xe2, 0xd8, 0x13, 0x76, 0xba,
+	0x2d, 0xcc, 0x58, 0xec, 0x2d, 0xdc, 0xea, 0xe2, 0x38, 0x4e, 0xc8, 0x9e, 0x4c, 0x2a, 0x3e, 0x25,
+	0x6a, 0xdf, 0x51, 0x55, 0xef, 0xdf, 0xc1, 0xce, 0x79, 0x3a, 0x9f, 0xc7, 0x9c, 0xa8, 0x90, 0xf2,
+	0x5b, 0x66, 0x36, 0x5c, 0x59, 0xa5, 0x7e, 0x80, 0x3b, 0xfd, 0x74, 0x36, 0x1b, 0xd2, 0x68, 0x4a,
+	0xd4, 0x55, 0x25, 0x15, 0x99, 0x7e, 0xe8, 0x1b, 0xe6, 0x10, 0xf7, 0x91, 0xa5, 0xb3, 0x15, 0x7e,
+	0xcb, 0x69, 0xc2, 0x68, 0xc4, 0xe3, 0x34, 0xd1, 0x43, 0xec, 0x7b, 0xde, 0x10, 0x87, 0x42, 0x14,
+	0xfe, 0x0b, 0xd4, 0xaf, 0x90, 0x31, 0x3a, 0xc6, 0xb2, 0x7f, 0xfa, 0x12, 0xb2, 0x64, 0x7d, 0x4b,
+	0x96, 0x37, 0xb5, 0x63, 0x1a, 0x3d, 0xfe, 0x08, 0x20, 0xcc, 0x4e, 0x34, 0x25, 0x47, 0x0e, 0xad,
+	0xa3, 0x8b, 0x3e, 0xb2, 0x51, 0x1d, 0xab, 0xea, 0x5f, 0xb0, 0xaf, 0x75, 0x73, 0x0c, 0x9f, 0xfb,
+	0xc0, 0xc0, 0x0c, 0x6e, 0x64, 0x5f, 0xc3, 0x3d, 0xa1, 0x17, 0xe1, 0x4b, 0x24, 0xc7, 0x0e, 0x54,
+	0xe8, 0x92, 0xf6, 0xb8, 0xca, 0x56, 0xc8, 0x1e, 0xc0, 0x20, 0x9b, 0xc5, 0xfc, 0xfa, 0xff, 0xaa,
+	0xba, 0x68, 0xad, 0x49, 0x54, 0x33, 0x64, 0x99, 0x3b, 0xfb, 0x84, 0x7c, 0x90, 0xaf, 0x64, 0x49,
+	0x7a, 0x67, 0xb6, 0xee, 0xed, 0xcc, 0xb5, 0x15, 0xf2, 0x0a, 0x6a, 0xdf, 0xb3, 0x11, 0xe5, 0xf2,
+	0x78, 0xd5, 0x1b, 0x68, 0xaa, 0xde, 0x1b, 0x68, 0x9b, 0xc6, 0xe9, 0xaa, 0x57, 0xf0, 0x7c, 0x52,
+	0xfe, 0x97, 0x3a, 0xaf, 0xa0, 0x90, 0x2b, 0x5e, 0x41, 0xe5, 0x6a, 0x62, 0xb7, 0x01, 0x7b, 0x71,
+	0xda, 0x5e, 0x15, 0xef, 0x7d, 0xf9, 0x03, 0xa0, 0x3d, 0xce, 0xb3, 0x68, 0xb8, 0x53, 0xfc, 0xfd,
+	0xe6, 0xdf, 0x00, 0x57, 0xf6, 0x22, 0xa8, 0x4d, 0x08, 0x00, 0x00,
 }
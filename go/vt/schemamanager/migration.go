@@ -0,0 +1,277 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemamanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// This file contains the schema migration queue. Migrations are saved in
+// the topology global cell, one file per migration in a directory per
+// keyspace, and are executed asynchronously by a MigrationScheduler.
+
+const schemaMigrationsPath = "schema_migrations"
+
+var (
+	// migrationHeartbeatInterval is how often the scheduler executing a
+	// migration saves its heartbeat.
+	migrationHeartbeatInterval = 10 * time.Second
+	// migrationStaleTimeout is how long a running migration can go
+	// without a heartbeat before it is considered abandoned, because its
+	// scheduler died or cannot reach the topology anymore.
+	migrationStaleTimeout = time.Minute
+)
+
+// MigrationState is the state of a migration, or of a migration on a shard.
+type MigrationState string
+
+const (
+	// MigrationQueued means the migration is waiting to be executed.
+	MigrationQueued MigrationState = "queued"
+	// MigrationRunning means the migration is being executed by the
+	// scheduler in Owner.
+	MigrationRunning MigrationState = "running"
+	// MigrationComplete means the migration was applied successfully.
+	MigrationComplete MigrationState = "complete"
+	// MigrationFailed means the migration failed. It can be retried.
+	MigrationFailed MigrationState = "failed"
+	// MigrationCancelled means the migration was cancelled before it ran,
+	// or while it was stale.
+	MigrationCancelled MigrationState = "cancelled"
+)
+
+// ShardMigration is the status of a migration on one shard.
+type ShardMigration struct {
+	Shard string
+	State MigrationState
+	// AppliedSqls is the number of statements of the migration
+	// already applied on the shard.
+	AppliedSqls int
+	// Position is the master replication position after the
+	// last statement was applied.
+	Position string `json:",omitempty"`
+	Error    string `json:",omitempty"`
+}
+
+// Migration is a schema change submitted to the migration queue of a keyspace.
+type Migration struct {
+	// ID identifies the migration in its keyspace. The IDs of a
+	// keyspace sort in submission order.
+	ID       string
+	Keyspace string
+	Sqls     []string
+	// AllowLongUnavailability disables the check for big schema
+	// changes, see TabletExecutor.AllowBigSchemaChange.
+	AllowLongUnavailability bool
+	State                   MigrationState
+	// Shards is the status of the migration per shard. It is
+	// populated when the migration runs for the first time.
+	Shards     []*ShardMigration `json:",omitempty"`
+	SubmitTime time.Time
+	StartTime  time.Time
+	EndTime    time.Time
+	// Owner identifies the scheduler executing the migration, and
+	// Heartbeat is the last time that scheduler saved it.
+	Owner     string `json:",omitempty"`
+	Heartbeat time.Time
+
+	version topo.Version
+}
+
+// isStale returns true if the migration is running, but its scheduler
+// has not saved a heartbeat for longer than migrationStaleTimeout.
+func (m *Migration) isStale(now time.Time) bool {
+	return m.State == MigrationRunning && now.Sub(m.Heartbeat) > migrationStaleTimeout
+}
+
+func pathForMigration(keyspace, id string) string {
+	return path.Join(schemaMigrationsPath, keyspace, id)
+}
+
+// SubmitMigration adds the semicolon-delimited schema change in sql
+// to the migration queue of the keyspace.
+func SubmitMigration(ctx context.Context, ts *topo.Server, keyspace, sql string, allowLongUnavailability bool) (*Migration, error) {
+	if _, err := ts.GetKeyspace(ctx, keyspace); err != nil {
+		return nil, err
+	}
+	pieces, err := sqlparser.SplitStatementToPieces(sql)
+	if err != nil {
+		return nil, err
+	}
+	var sqls []string
+	for _, piece := range pieces {
+		if s := strings.TrimSpace(piece); s != "" {
+			sqls = append(sqls, s)
+		}
+	}
+	if len(sqls) == 0 {
+		return nil, fmt.Errorf("no schema change to submit")
+	}
+	if _, err := parseDDLs(sqls); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	m := &Migration{
+		ID:                      fmt.Sprintf("%d", now.UnixNano()),
+		Keyspace:                keyspace,
+		Sqls:                    sqls,
+		AllowLongUnavailability: allowLongUnavailability,
+		State:                   MigrationQueued,
+		SubmitTime:              now,
+	}
+	contents, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return nil, err
+	}
+	m.version, err = conn.Create(ctx, pathForMigration(keyspace, m.ID), contents)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetMigration reads a migration from the queue of the keyspace.
+func GetMigration(ctx context.Context, ts *topo.Server, keyspace, id string) (*Migration, error) {
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return nil, err
+	}
+	contents, version, err := conn.Get(ctx, pathForMigration(keyspace, id))
+	if err != nil {
+		return nil, err
+	}
+	m := &Migration{}
+	if err := json.Unmarshal(contents, m); err != nil {
+		return nil, fmt.Errorf("bad schema migration %v/%v: %v", keyspace, id, err)
+	}
+	m.version = version
+	return m, nil
+}
+
+// GetMigrations returns all the migrations of the keyspace,
+// in submission order.
+func GetMigrations(ctx context.Context, ts *topo.Server, keyspace string) ([]*Migration, error) {
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := conn.ListDir(ctx, path.Join(schemaMigrationsPath, keyspace), false /*full*/)
+	switch err {
+	case topo.ErrNoNode:
+		return nil, nil
+	case nil:
+	default:
+		return nil, err
+	}
+	ids := topo.DirEntriesToStringArray(entries)
+	sort.Strings(ids)
+	migrations := make([]*Migration, 0, len(ids))
+	for _, id := range ids {
+		m, err := GetMigration(ctx, ts, keyspace, id)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, m)
+	}
+	return migrations, nil
+}
+
+// saveMigration saves the migration. If it was changed since it was
+// read, topo.ErrBadVersion is returned.
+func saveMigration(ctx context.Context, ts *topo.Server, m *Migration) error {
+	contents, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+	version, err := conn.Update(ctx, pathForMigration(m.Keyspace, m.ID), contents, m.version)
+	if err != nil {
+		return err
+	}
+	m.version = version
+	return nil
+}
+
+// CancelMigration cancels a migration that has not started yet, or
+// a stale running migration. The statements of a stale migration may
+// have been applied on some shards already.
+func CancelMigration(ctx context.Context, ts *topo.Server, keyspace, id string) (*Migration, error) {
+	m, err := GetMigration(ctx, ts, keyspace, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if m.State != MigrationQueued && !m.isStale(now) {
+		return nil, fmt.Errorf("cannot cancel schema migration %v/%v in state %v", keyspace, id, m.State)
+	}
+	for _, shard := range m.Shards {
+		if shard.State == MigrationRunning {
+			shard.State = MigrationCancelled
+		}
+	}
+	m.State = MigrationCancelled
+	m.Owner = ""
+	m.EndTime = now
+	if err := saveMigration(ctx, ts, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RetryMigration queues a failed or stale migration again. Only the
+// shards on which it did not complete will run it, starting from the
+// first statement they have not applied.
+func RetryMigration(ctx context.Context, ts *topo.Server, keyspace, id string) (*Migration, error) {
+	m, err := GetMigration(ctx, ts, keyspace, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.State != MigrationFailed && !m.isStale(time.Now()) {
+		return nil, fmt.Errorf("cannot retry schema migration %v/%v in state %v", keyspace, id, m.State)
+	}
+	for _, shard := range m.Shards {
+		if shard.State == MigrationFailed || shard.State == MigrationRunning {
+			shard.State = MigrationQueued
+			shard.Error = ""
+		}
+	}
+	m.State = MigrationQueued
+	m.Owner = ""
+	m.EndTime = time.Time{}
+	if err := saveMigration(ctx, ts, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemamanager
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/timer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/wrangler"
+)
+
+// MigrationScheduler periodically executes the queued migrations of all
+// keyspaces. The migrations of a keyspace are executed one at a time, in
+// submission order, and a failed migration blocks the ones after it until
+// it is retried or the failure is fixed.
+//
+// Several schedulers can run at the same time: a migration is claimed by
+// saving its running state, so only one of them executes it. The owner
+// saves a heartbeat while it executes the migration, and another
+// scheduler reclaims the migration once it is stale.
+type MigrationScheduler struct {
+	wr               *wrangler.Wrangler
+	waitSlaveTimeout time.Duration
+	timer            *timer.Timer
+	// owner identifies this scheduler in the migrations it executes.
+	owner string
+
+	// mu protects the migration being executed, which is saved
+	// concurrently by the heartbeat.
+	mu sync.Mutex
+}
+
+// NewMigrationScheduler creates a new MigrationScheduler, that checks
+// the migration queues at the given interval once opened.
+func NewMigrationScheduler(wr *wrangler.Wrangler, waitSlaveTimeout, interval time.Duration) *MigrationScheduler {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return &MigrationScheduler{
+		wr:               wr,
+		waitSlaveTimeout: waitSlaveTimeout,
+		timer:            timer.NewTimer(interval),
+		owner:            fmt.Sprintf("%v-%v-%v", hostname, os.Getpid(), time.Now().UnixNano()),
+	}
+}
+
+// Open starts checking the migration queues.
+func (s *MigrationScheduler) Open() {
+	s.timer.Start(func() {
+		if err := s.RunPending(context.Background()); err != nil {
+			log.Errorf("Schema migrations failed: %v", err)
+		}
+	})
+}
+
+// Close stops checking the migration queues. It waits for the
+// migration being executed, if any.
+func (s *MigrationScheduler) Close() {
+	s.timer.Stop()
+}
+
+// RunPending executes the queued migrations of all keyspaces.
+func (s *MigrationScheduler) RunPending(ctx context.Context) error {
+	keyspaces, err := s.wr.TopoServer().GetKeyspaces(ctx)
+	if err != nil {
+		return err
+	}
+	for _, keyspace := range keyspaces {
+		if err := s.runKeyspace(ctx, keyspace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MigrationScheduler) runKeyspace(ctx context.Context, keyspace string) error {
+	migrations, err := GetMigrations(ctx, s.wr.TopoServer(), keyspace)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		switch m.State {
+		case MigrationComplete, MigrationCancelled:
+			continue
+		case MigrationQueued, MigrationRunning:
+			if m.State == MigrationRunning {
+				if !m.isStale(time.Now()) {
+					// Another scheduler is executing it.
+					return nil
+				}
+				log.Warningf("Schema migration %v/%v owned by %v has no heartbeat since %v, reclaiming it", m.Keyspace, m.ID, m.Owner, m.Heartbeat)
+			}
+			if err := s.runMigration(ctx, m); err != nil {
+				return err
+			}
+			if m.State == MigrationComplete {
+				continue
+			}
+		}
+		// A migration is running or has failed, the following
+		// ones have to wait for it.
+		return nil
+	}
+	return nil
+}
+
+// runMigration executes a queued or stale migration on all the shards
+// that have not applied it yet.
+func (s *MigrationScheduler) runMigration(ctx context.Context, m *Migration) error {
+	ts := s.wr.TopoServer()
+	if len(m.Shards) == 0 {
+		shards, err := ts.GetShardNames(ctx, m.Keyspace)
+		if err != nil {
+			return err
+		}
+		sort.Strings(shards)
+		for _, shard := range shards {
+			m.Shards = append(m.Shards, &ShardMigration{
+				Shard: shard,
+				State: MigrationQueued,
+			})
+		}
+	}
+	m.State = MigrationRunning
+	m.Owner = s.owner
+	m.StartTime = time.Now()
+	m.Heartbeat = m.StartTime
+	if err := saveMigration(ctx, ts, m); err != nil {
+		if err == topo.ErrBadVersion {
+			// Another scheduler got it first.
+			return nil
+		}
+		return err
+	}
+	log.Infof("Running schema migration %v/%v: %v", m.Keyspace, m.ID, m.Sqls)
+
+	// The shards may have applied a different number of statements
+	// if the migration is retried, so they are grouped by the first
+	// statement they have to apply.
+	groups := make(map[int][]*ShardMigration)
+	var starts []int
+	for _, shard := range m.Shards {
+		if shard.State == MigrationComplete {
+			continue
+		}
+		shard.State = MigrationRunning
+		if _, ok := groups[shard.AppliedSqls]; !ok {
+			starts = append(starts, shard.AppliedSqls)
+		}
+		groups[shard.AppliedSqls] = append(groups[shard.AppliedSqls], shard)
+	}
+	sort.Ints(starts)
+
+	// runCtx is cancelled if the heartbeat finds out another process
+	// took the migration over.
+	runCtx, cancelRun := context.WithCancel(ctx)
+	defer cancelRun()
+	heartbeatCtx, stopHeartbeat := context.WithCancel(runCtx)
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		s.heartbeat(heartbeatCtx, cancelRun, m)
+	}()
+	for _, start := range starts {
+		s.runShards(runCtx, m, start, groups[start])
+	}
+	stopHeartbeat()
+	<-heartbeatDone
+	if runCtx.Err() != nil && ctx.Err() == nil {
+		return fmt.Errorf("schema migration %v/%v was taken over by another process", m.Keyspace, m.ID)
+	}
+
+	m.State = MigrationComplete
+	for _, shard := range m.Shards {
+		if shard.State != MigrationComplete {
+			m.State = MigrationFailed
+		}
+	}
+	m.EndTime = time.Now()
+	log.Infof("Schema migration %v/%v is %v", m.Keyspace, m.ID, m.State)
+	return saveMigration(ctx, ts, m)
+}
+
+// heartbeat saves the migration every migrationHeartbeatInterval until
+// ctx is done. If the migration was changed by another process, which
+// only happens once it is stale, cancelRun is called to stop executing it.
+func (s *MigrationScheduler) heartbeat(ctx context.Context, cancelRun context.CancelFunc, m *Migration) {
+	ticker := time.NewTicker(migrationHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		m.Heartbeat = time.Now()
+		err := saveMigration(ctx, s.wr.TopoServer(), m)
+		s.mu.Unlock()
+		switch {
+		case err == topo.ErrBadVersion:
+			log.Errorf("Schema migration %v/%v was changed by another process, stopping it", m.Keyspace, m.ID)
+			cancelRun()
+			return
+		case err != nil && ctx.Err() == nil:
+			log.Warningf("Cannot save the heartbeat of schema migration %v/%v: %v", m.Keyspace, m.ID, err)
+		}
+	}
+}
+
+// runShards executes the statements of the migration from start
+// on the given shards.
+func (s *MigrationScheduler) runShards(ctx context.Context, m *Migration, start int, shards []*ShardMigration) {
+	controller := &migrationController{
+		migration: m,
+		mu:        &s.mu,
+		ts:        s.wr.TopoServer(),
+		start:     start,
+		shards:    shards,
+	}
+	executor := NewTabletExecutor(s.wr, s.waitSlaveTimeout)
+	if m.AllowLongUnavailability {
+		executor.AllowBigSchemaChange()
+	}
+	names := make([]string, 0, len(shards))
+	for _, shard := range shards {
+		names = append(names, shard.Shard)
+	}
+	executor.SetShards(names)
+
+	if err := Run(ctx, controller, executor); err != nil {
+		// The shards not updated by OnExecutorComplete failed
+		// before the statements were executed.
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, shard := range shards {
+			if shard.State == MigrationRunning {
+				shard.State = MigrationFailed
+				shard.Error = err.Error()
+			}
+		}
+	}
+}
+
+// migrationController implements the Controller interface for
+// migrations of the queue. It records the result on every shard.
+type migrationController struct {
+	migration *Migration
+	// mu protects migration, see MigrationScheduler.mu.
+	mu *sync.Mutex
+	ts *topo.Server
+	// start is the index of the first statement to apply.
+	start  int
+	shards []*ShardMigration
+}
+
+// Open is part of the Controller interface.
+func (c *migrationController) Open(ctx context.Context) error {
+	return nil
+}
+
+// Read is part of the Controller interface.
+func (c *migrationController) Read(ctx context.Context) ([]string, error) {
+	return c.migration.Sqls[c.start:], nil
+}
+
+// Close is part of the Controller interface.
+func (c *migrationController) Close() {
+}
+
+// Keyspace is part of the Controller interface.
+func (c *migrationController) Keyspace() string {
+	return c.migration.Keyspace
+}
+
+// OnReadSuccess is part of the Controller interface.
+func (c *migrationController) OnReadSuccess(ctx context.Context) error {
+	return nil
+}
+
+// OnReadFail is part of the Controller interface.
+func (c *migrationController) OnReadFail(ctx context.Context, err error) error {
+	return err
+}
+
+// OnValidationSuccess is part of the Controller interface.
+func (c *migrationController) OnValidationSuccess(ctx context.Context) error {
+	return nil
+}
+
+// OnValidationFail is part of the Controller interface.
+func (c *migrationController) OnValidationFail(ctx context.Context, err error) error {
+	return err
+}
+
+// OnExecutorComplete is part of the Controller interface. It updates
+// the status of the shards and saves the migration.
+func (c *migrationController) OnExecutorComplete(ctx context.Context, result *ExecuteResult) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if result.ExecutorErr != "" {
+		for _, shard := range c.shards {
+			shard.State = MigrationFailed
+			shard.Error = result.ExecutorErr
+		}
+		return saveMigration(ctx, c.ts, c.migration)
+	}
+
+	// The statements before CurSQLIndex were applied on all the shards,
+	// and the one at CurSQLIndex on the successful shards only.
+	failed := make(map[string]string)
+	for _, f := range result.FailedShards {
+		failed[f.Shard] = f.Err
+	}
+	positions := make(map[string]string)
+	for _, r := range result.SuccessShards {
+		positions[r.Shard] = r.Position
+	}
+	for _, shard := range c.shards {
+		if err, ok := failed[shard.Shard]; ok {
+			shard.AppliedSqls = c.start + result.CurSQLIndex
+			shard.State = MigrationFailed
+			shard.Error = err
+			continue
+		}
+		shard.AppliedSqls = c.start + result.CurSQLIndex + 1
+		shard.Position = positions[shard.Shard]
+		if shard.AppliedSqls == len(c.migration.Sqls) {
+			shard.State = MigrationComplete
+		} else {
+			shard.State = MigrationFailed
+			shard.Error = fmt.Sprintf("stopped after statement %v because other shards failed", shard.AppliedSqls)
+		}
+	}
+	return saveMigration(ctx, c.ts, c.migration)
+}
+
+var _ Controller = (*migrationController)(nil)
@@ -0,0 +1,316 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemamanager
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func newFakeMigrationScheduler(t *testing.T, sqls ...string) (*MigrationScheduler, *fakeTabletManagerClient) {
+	fakeTmc := newFakeTabletManagerClient()
+	for _, sql := range sqls {
+		fakeTmc.AddSchemaChange(sql, &tabletmanagerdatapb.SchemaChangeResult{
+			BeforeSchema: &tabletmanagerdatapb.SchemaDefinition{},
+			AfterSchema: &tabletmanagerdatapb.SchemaDefinition{
+				DatabaseSchema: "CREATE DATABASE `{{.DatabaseName}}` /*!40100 DEFAULT CHARACTER SET utf8 */",
+				TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+					{
+						Name:   "test_table",
+						Schema: sql,
+						Type:   tmutils.TableBaseTable,
+					},
+				},
+			},
+		})
+	}
+	fakeTmc.AddSchemaDefinition("vt_test_keyspace", &tabletmanagerdatapb.SchemaDefinition{})
+	wr := wrangler.New(logutil.NewConsoleLogger(), newFakeTopo(t), fakeTmc)
+	return NewMigrationScheduler(wr, testWaitSlaveTimeout, time.Hour), fakeTmc
+}
+
+func checkMigration(t *testing.T, s *MigrationScheduler, id string, state MigrationState, applied int) *Migration {
+	t.Helper()
+	m, err := GetMigration(context.Background(), s.wr.TopoServer(), "test_keyspace", id)
+	if err != nil {
+		t.Fatalf("GetMigration failed: %v", err)
+	}
+	if m.State != state {
+		t.Fatalf("migration state: %v, want %v", m.State, state)
+	}
+	if state == MigrationQueued || state == MigrationCancelled {
+		return m
+	}
+	if len(m.Shards) != 3 {
+		t.Fatalf("migration shards: %v, want 3", m.Shards)
+	}
+	for _, shard := range m.Shards {
+		if shard.AppliedSqls != applied {
+			t.Errorf("shard %v applied %v statements, want %v", shard.Shard, shard.AppliedSqls, applied)
+		}
+	}
+	return m
+}
+
+func TestMigrationSubmitAndCancel(t *testing.T) {
+	s, _ := newFakeMigrationScheduler(t)
+	ctx := context.Background()
+	ts := s.wr.TopoServer()
+
+	if _, err := SubmitMigration(ctx, ts, "unknown_keyspace", "create table test_table (pk int)", false); err == nil {
+		t.Errorf("SubmitMigration on unknown keyspace should fail")
+	}
+	if _, err := SubmitMigration(ctx, ts, "test_keyspace", "select 1", false); err == nil || !strings.Contains(err.Error(), "non DDL statement") {
+		t.Errorf("SubmitMigration with a non DDL statement: %v", err)
+	}
+
+	m, err := SubmitMigration(ctx, ts, "test_keyspace", "create table test_table (pk int); alter table test_table add column c int;", false)
+	if err != nil {
+		t.Fatalf("SubmitMigration failed: %v", err)
+	}
+	if len(m.Sqls) != 2 {
+		t.Errorf("migration statements: %v, want 2", m.Sqls)
+	}
+	migrations, err := GetMigrations(ctx, ts, "test_keyspace")
+	if err != nil || len(migrations) != 1 || migrations[0].ID != m.ID {
+		t.Fatalf("GetMigrations: %v %v, want [%v]", migrations, err, m.ID)
+	}
+	checkMigration(t, s, m.ID, MigrationQueued, 0)
+
+	if _, err := RetryMigration(ctx, ts, "test_keyspace", m.ID); err == nil {
+		t.Errorf("RetryMigration of a queued migration should fail")
+	}
+	if _, err := CancelMigration(ctx, ts, "test_keyspace", m.ID); err != nil {
+		t.Fatalf("CancelMigration failed: %v", err)
+	}
+	checkMigration(t, s, m.ID, MigrationCancelled, 0)
+	if _, err := CancelMigration(ctx, ts, "test_keyspace", m.ID); err == nil {
+		t.Errorf("CancelMigration of a cancelled migration should fail")
+	}
+
+	// Cancelled migrations are skipped.
+	if err := s.RunPending(ctx); err != nil {
+		t.Fatalf("RunPending failed: %v", err)
+	}
+	checkMigration(t, s, m.ID, MigrationCancelled, 0)
+}
+
+func TestMigrationSchedulerRun(t *testing.T) {
+	sql := "create table test_table (pk int)"
+	s, _ := newFakeMigrationScheduler(t, sql)
+	ctx := context.Background()
+
+	m, err := SubmitMigration(ctx, s.wr.TopoServer(), "test_keyspace", sql, false)
+	if err != nil {
+		t.Fatalf("SubmitMigration failed: %v", err)
+	}
+	if err := s.RunPending(ctx); err != nil {
+		t.Fatalf("RunPending failed: %v", err)
+	}
+	m = checkMigration(t, s, m.ID, MigrationComplete, 1)
+	for _, shard := range m.Shards {
+		if shard.State != MigrationComplete {
+			t.Errorf("shard %v state: %v, want complete", shard.Shard, shard.State)
+		}
+	}
+}
+
+func TestMigrationSchedulerRetry(t *testing.T) {
+	sql1 := "create table test_table (pk int)"
+	sql2 := "create table test_table2 (pk int)"
+	s, fakeTmc := newFakeMigrationScheduler(t, sql1, sql2)
+	ctx := context.Background()
+	ts := s.wr.TopoServer()
+
+	m1, err := SubmitMigration(ctx, ts, "test_keyspace", sql1, false)
+	if err != nil {
+		t.Fatalf("SubmitMigration failed: %v", err)
+	}
+	m2, err := SubmitMigration(ctx, ts, "test_keyspace", sql2, false)
+	if err != nil {
+		t.Fatalf("SubmitMigration failed: %v", err)
+	}
+
+	// The first migration fails on all shards, and blocks the second one.
+	fakeTmc.EnableExecuteFetchAsDbaError = true
+	if err := s.RunPending(ctx); err != nil {
+		t.Fatalf("RunPending failed: %v", err)
+	}
+	m := checkMigration(t, s, m1.ID, MigrationFailed, 0)
+	for _, shard := range m.Shards {
+		if shard.State != MigrationFailed || !strings.Contains(shard.Error, "unknown error") {
+			t.Errorf("shard %v: %v %v, want failed", shard.Shard, shard.State, shard.Error)
+		}
+	}
+	checkMigration(t, s, m2.ID, MigrationQueued, 0)
+
+	// Once retried, both migrations run.
+	fakeTmc.EnableExecuteFetchAsDbaError = false
+	if _, err := RetryMigration(ctx, ts, "test_keyspace", m1.ID); err != nil {
+		t.Fatalf("RetryMigration failed: %v", err)
+	}
+	checkMigration(t, s, m1.ID, MigrationQueued, 0)
+	if err := s.RunPending(ctx); err != nil {
+		t.Fatalf("RunPending failed: %v", err)
+	}
+	checkMigration(t, s, m1.ID, MigrationComplete, 1)
+	checkMigration(t, s, m2.ID, MigrationComplete, 1)
+}
+
+// setRunning marks the migration as executed by another scheduler,
+// whose last heartbeat was at the given time.
+func setRunning(t *testing.T, s *MigrationScheduler, id string, heartbeat time.Time) {
+	t.Helper()
+	ctx := context.Background()
+	m, err := GetMigration(ctx, s.wr.TopoServer(), "test_keyspace", id)
+	if err != nil {
+		t.Fatalf("GetMigration failed: %v", err)
+	}
+	m.State = MigrationRunning
+	m.Owner = "other"
+	m.Heartbeat = heartbeat
+	m.Shards = []*ShardMigration{
+		{Shard: "0", State: MigrationRunning},
+		{Shard: "1", State: MigrationRunning},
+		{Shard: "2", State: MigrationRunning},
+	}
+	if err := saveMigration(ctx, s.wr.TopoServer(), m); err != nil {
+		t.Fatalf("saveMigration failed: %v", err)
+	}
+}
+
+func TestMigrationStale(t *testing.T) {
+	sql := "create table test_table (pk int)"
+	s, _ := newFakeMigrationScheduler(t, sql)
+	ctx := context.Background()
+	ts := s.wr.TopoServer()
+	stale := time.Now().Add(-2 * migrationStaleTimeout)
+
+	m1, err := SubmitMigration(ctx, ts, "test_keyspace", sql, false)
+	if err != nil {
+		t.Fatalf("SubmitMigration failed: %v", err)
+	}
+	m2, err := SubmitMigration(ctx, ts, "test_keyspace", sql, false)
+	if err != nil {
+		t.Fatalf("SubmitMigration failed: %v", err)
+	}
+
+	// A migration with a recent heartbeat cannot be cancelled, retried
+	// or executed by another scheduler.
+	setRunning(t, s, m1.ID, time.Now())
+	if _, err := CancelMigration(ctx, ts, "test_keyspace", m1.ID); err == nil {
+		t.Errorf("CancelMigration of a running migration should fail")
+	}
+	if _, err := RetryMigration(ctx, ts, "test_keyspace", m1.ID); err == nil {
+		t.Errorf("RetryMigration of a running migration should fail")
+	}
+	if err := s.RunPending(ctx); err != nil {
+		t.Fatalf("RunPending failed: %v", err)
+	}
+	checkMigration(t, s, m1.ID, MigrationRunning, 0)
+	checkMigration(t, s, m2.ID, MigrationQueued, 0)
+
+	// Once stale, it can be cancelled.
+	setRunning(t, s, m1.ID, stale)
+	m, err := CancelMigration(ctx, ts, "test_keyspace", m1.ID)
+	if err != nil {
+		t.Fatalf("CancelMigration of a stale migration failed: %v", err)
+	}
+	if m.Owner != "" || m.Shards[0].State != MigrationCancelled {
+		t.Errorf("cancelled migration: owner %v, shard state %v", m.Owner, m.Shards[0].State)
+	}
+
+	// Or retried.
+	setRunning(t, s, m1.ID, stale)
+	m, err = RetryMigration(ctx, ts, "test_keyspace", m1.ID)
+	if err != nil {
+		t.Fatalf("RetryMigration of a stale migration failed: %v", err)
+	}
+	if m.State != MigrationQueued || m.Owner != "" || m.Shards[0].State != MigrationQueued {
+		t.Errorf("retried migration: state %v, owner %v, shard state %v", m.State, m.Owner, m.Shards[0].State)
+	}
+
+	// Or reclaimed by another scheduler, which runs the following ones too.
+	setRunning(t, s, m1.ID, stale)
+	if err := s.RunPending(ctx); err != nil {
+		t.Fatalf("RunPending failed: %v", err)
+	}
+	m = checkMigration(t, s, m1.ID, MigrationComplete, 1)
+	if m.Owner != s.owner {
+		t.Errorf("migration owner: %v, want %v", m.Owner, s.owner)
+	}
+	checkMigration(t, s, m2.ID, MigrationComplete, 1)
+}
+
+func TestMigrationHeartbeat(t *testing.T) {
+	saved := migrationHeartbeatInterval
+	migrationHeartbeatInterval = 10 * time.Millisecond
+	defer func() {
+		migrationHeartbeatInterval = saved
+	}()
+	s, _ := newFakeMigrationScheduler(t)
+	ctx := context.Background()
+	ts := s.wr.TopoServer()
+
+	m, err := SubmitMigration(ctx, ts, "test_keyspace", "create table test_table (pk int)", false)
+	if err != nil {
+		t.Fatalf("SubmitMigration failed: %v", err)
+	}
+	runCtx, cancelRun := context.WithCancel(ctx)
+	defer cancelRun()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.heartbeat(runCtx, cancelRun, m)
+	}()
+
+	// The heartbeat is saved periodically.
+	start := m.SubmitTime
+	for {
+		saved, err := GetMigration(ctx, ts, "test_keyspace", m.ID)
+		if err != nil {
+			t.Fatalf("GetMigration failed: %v", err)
+		}
+		if saved.Heartbeat.After(start) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Once another process changes the migration, the heartbeat
+	// stops and cancels the execution.
+	if _, err := CancelMigration(ctx, ts, "test_keyspace", m.ID); err != nil {
+		t.Fatalf("CancelMigration failed: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("heartbeat did not stop")
+	}
+	if runCtx.Err() == nil {
+		t.Errorf("the execution of the migration was not cancelled")
+	}
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package onlineddl contains the online schema change workflow. It applies
+// an ALTER TABLE to all the shards of a keyspace without locking the table
+// for the duration of the change: the rows are copied to a shadow table
+// with the new schema, the ongoing changes are replayed from the binlogs,
+// and the tables are swapped at the end.
+package onlineddl
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/throttler"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/workflow"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	workflowpb "vitess.io/vitess/go/vt/proto/workflow"
+)
+
+var (
+	chunkSize = flag.Int("online_ddl_chunk_size", 1000,
+		"number of rows copied at a time to the shadow table during an online schema change")
+	maxRate = flag.Int64("online_ddl_max_rate", throttler.MaxRateModuleDisabled,
+		"maximum number of chunks copied per second and per shard during an online schema change, unlimited by default")
+	maxReplicationLag = flag.Int64("online_ddl_max_replication_lag", 10,
+		"maximum replication lag in seconds of the replicas of a shard before the copy of an online schema change is throttled")
+	catchUpPasses = flag.Int("online_ddl_catch_up_passes", 10,
+		"maximum number of passes replaying the ongoing changes before the cut-over of an online schema change")
+	drainTimeout = flag.Duration("online_ddl_drain_timeout", 30*time.Second,
+		"maximum time to wait for the transactions open on the master when the original table is blacklisted to end, before the cut-over of an online schema change fails")
+)
+
+// WorkflowFactoryName is the name of the online schema change workflow factory.
+const WorkflowFactoryName = "online_ddl"
+
+// Register registers the online schema change as a factory
+// in the workflow framework.
+func Register() {
+	workflow.Register(WorkflowFactoryName, &WorkflowFactory{})
+}
+
+// WorkflowFactory is the factory to create online schema change workflows.
+type WorkflowFactory struct{}
+
+// migrationData is the data saved in the workflow.
+type migrationData struct {
+	Keyspace, SQL string
+}
+
+// Init is part of the workflow.Factory interface.
+func (*WorkflowFactory) Init(_ *workflow.Manager, w *workflowpb.Workflow, args []string) error {
+	subFlags := flag.NewFlagSet(WorkflowFactoryName, flag.ContinueOnError)
+	keyspace := subFlags.String("keyspace", "", "Name of the keyspace to apply the schema change to")
+	sql := subFlags.String("sql", "", "ALTER TABLE statement to apply")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if *keyspace == "" || *sql == "" {
+		return fmt.Errorf("keyspace name and SQL statement must be provided for online schema change")
+	}
+	table, err := parseAlter(*sql)
+	if err != nil {
+		return err
+	}
+
+	w.Name = fmt.Sprintf("Online schema change of table %v in keyspace %v", table, *keyspace)
+	w.Data, err = json.Marshal(&migrationData{
+		Keyspace: *keyspace,
+		SQL:      *sql,
+	})
+	return err
+}
+
+// Instantiate is part of the workflow.Factory interface.
+func (*WorkflowFactory) Instantiate(_ *workflow.Manager, w *workflowpb.Workflow, rootNode *workflow.Node) (workflow.Workflow, error) {
+	data := &migrationData{}
+	if err := json.Unmarshal(w.Data, data); err != nil {
+		return nil, err
+	}
+	table, err := parseAlter(data.SQL)
+	if err != nil {
+		return nil, err
+	}
+	rootNode.Message = fmt.Sprintf("Online schema change is executed on the keyspace %v: %v", data.Keyspace, data.SQL)
+
+	return &Migration{
+		keyspace:   data.Keyspace,
+		sql:        data.SQL,
+		table:      table,
+		rootUINode: rootNode,
+		uiLogger:   logutil.NewMemoryLogger(),
+	}, nil
+}
+
+// Migration is the workflow running an online schema change on all the
+// shards of a keyspace. The shards are copied and caught up in parallel,
+// and the cut-over only starts once every shard is ready for it, so the
+// tables are swapped on all the shards within a short time.
+type Migration struct {
+	keyspace string
+	sql      string
+	// table is the name of the altered table.
+	table string
+
+	// rootUINode is the root node in the workflow UI representing
+	// this online schema change.
+	rootUINode *workflow.Node
+	// uiLogger is the logger collecting logs that will be displayed
+	// in the UI.
+	uiLogger *logutil.MemoryLogger
+
+	topoServer   *topo.Server
+	tabletClient tmclient.TabletManagerClient
+	wr           *wrangler.Wrangler
+
+	// shards contains the migration of each shard of the keyspace.
+	shards []*shardMigration
+
+	// healthCheck reports the replication lag of the replicas of the
+	// keyspace to the throttlers.
+	healthCheck discovery.HealthCheck
+	// shardWatchers add the tablets of each shard and cell to healthCheck.
+	shardWatchers []*discovery.TopologyWatcher
+
+	// throttlersMu guards throttlers.
+	throttlersMu sync.Mutex
+	// throttlers contains the throttler of the rows copy of each shard,
+	// keyed by shard name. A shard only has one while its rows are copied.
+	throttlers map[string]*throttler.Throttler
+}
+
+// Run is part of the workflow.Workflow interface.
+func (m *Migration) Run(ctx context.Context, manager *workflow.Manager, wi *topo.WorkflowInfo) error {
+	m.topoServer = manager.TopoServer()
+	m.tabletClient = tmclient.NewTabletManagerClient()
+	defer m.tabletClient.Close()
+	m.wr = wrangler.New(logutil.NewConsoleLogger(), m.topoServer, m.tabletClient)
+
+	if err := m.startHealthCheck(ctx); err != nil {
+		return err
+	}
+	defer m.stopHealthCheck()
+
+	log.Infof("Starting online schema change on keyspace %v with the following SQL: %v", m.keyspace, m.sql)
+	if err := m.run(ctx); err != nil {
+		m.setUIMessage(fmt.Sprintf("Error: %v", err))
+		return err
+	}
+	m.setUIMessage("Online schema change is finished")
+	return nil
+}
+
+func (m *Migration) run(ctx context.Context) error {
+	if err := m.createShardMigrations(ctx); err != nil {
+		return err
+	}
+
+	m.setUIMessage("Copying rows to the shadow tables")
+	if err := m.runOnAllShards(func(sm *shardMigration) error {
+		if err := sm.prepare(ctx); err != nil {
+			return err
+		}
+		if err := sm.copyRows(ctx); err != nil {
+			return err
+		}
+		if err := sm.catchUpUntilClose(ctx); err != nil {
+			return err
+		}
+		sm.setUIMessage("Waiting for the other shards to be ready for the cut-over")
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	m.setUIMessage("Swapping the original and the shadow tables")
+	return m.runOnAllShards(func(sm *shardMigration) error {
+		if err := sm.cutOver(ctx); err != nil {
+			return err
+		}
+		sm.setUIMessage("Schema change is applied")
+		sm.setState(workflowpb.WorkflowState_Done)
+		return nil
+	})
+}
+
+// createShardMigrations creates the migrations of all the shards
+// of the keyspace, and their UI nodes.
+func (m *Migration) createShardMigrations(ctx context.Context) error {
+	shards, err := m.topoServer.GetShardNames(ctx, m.keyspace)
+	if err != nil {
+		return err
+	}
+	m.shards = nil
+	m.rootUINode.Children = nil
+	for _, shard := range shards {
+		sm := &shardMigration{
+			parent: m,
+			shard:  shard,
+			uiNode: &workflow.Node{
+				Name:     fmt.Sprintf("Shard %v", shard),
+				PathName: shard,
+				State:    workflowpb.WorkflowState_Running,
+			},
+			uiLogger: logutil.NewMemoryLogger(),
+		}
+		m.shards = append(m.shards, sm)
+		m.rootUINode.Children = append(m.rootUINode.Children, sm.uiNode)
+	}
+	m.rootUINode.BroadcastChanges(true /* updateChildren */)
+	return nil
+}
+
+// runOnAllShards runs shardFunc on all the shards in parallel, and
+// returns an error if it failed on any of them.
+func (m *Migration) runOnAllShards(shardFunc func(sm *shardMigration) error) error {
+	var rec concurrency.AllErrorRecorder
+	var wg sync.WaitGroup
+	for _, sm := range m.shards {
+		wg.Add(1)
+		go func(sm *shardMigration) {
+			defer wg.Done()
+			if err := shardFunc(sm); err != nil {
+				sm.setUIMessage(fmt.Sprintf("Error: %v", err))
+				rec.RecordError(err)
+			}
+		}(sm)
+	}
+	wg.Wait()
+	return rec.Error()
+}
+
+// startHealthCheck starts watching the tablets of all the shards of the
+// keyspace in all the cells, so the replication lag of the replicas
+// throttles the rows copy.
+func (m *Migration) startHealthCheck(ctx context.Context) error {
+	cells, err := m.topoServer.GetKnownCells(ctx)
+	if err != nil {
+		return err
+	}
+	shards, err := m.topoServer.GetShardNames(ctx, m.keyspace)
+	if err != nil {
+		return err
+	}
+	m.healthCheck = discovery.NewDefaultHealthCheck()
+	m.healthCheck.SetListener(m, false /* sendDownEvents */)
+	for _, cell := range cells {
+		for _, shard := range shards {
+			watcher := discovery.NewShardReplicationWatcher(m.topoServer, m.healthCheck, cell, m.keyspace, shard,
+				discovery.DefaultTopologyWatcherRefreshInterval, discovery.DefaultTopoReadConcurrency)
+			m.shardWatchers = append(m.shardWatchers, watcher)
+		}
+	}
+	return nil
+}
+
+// stopHealthCheck stops the watchers and the health check started by
+// startHealthCheck.
+func (m *Migration) stopHealthCheck() {
+	// Stop the watchers first, so no tablet is added to the health check
+	// while it is closed.
+	for _, watcher := range m.shardWatchers {
+		watcher.Stop()
+	}
+	m.shardWatchers = nil
+	if m.healthCheck != nil {
+		if err := m.healthCheck.Close(); err != nil {
+			log.Warningf("HealthCheck.Close() failed: %v", err)
+		}
+		m.healthCheck = nil
+	}
+}
+
+// StatsUpdate is part of the discovery.HealthCheckStatsListener interface.
+// It records the replication lag of the replicas in the throttler of
+// their shard, if its rows are being copied.
+func (m *Migration) StatsUpdate(ts *discovery.TabletStats) {
+	if ts.Target.TabletType != topodatapb.TabletType_REPLICA && ts.Target.TabletType != topodatapb.TabletType_RDONLY {
+		return
+	}
+
+	// The lock also avoids that RecordReplicationLag() races with
+	// Throttler.Close() in closeThrottler().
+	m.throttlersMu.Lock()
+	defer m.throttlersMu.Unlock()
+	if t, ok := m.throttlers[ts.Target.Shard]; ok {
+		t.RecordReplicationLag(time.Now(), ts)
+	}
+}
+
+// createThrottler creates the throttler of the rows copy of a shard. It
+// enforces both --online_ddl_max_rate and --online_ddl_max_replication_lag.
+func (m *Migration) createThrottler(shard string) (*throttler.Throttler, error) {
+	name := fmt.Sprintf("OnlineDDL-%v-%v-%v", m.keyspace, shard, m.table)
+	t, err := throttler.NewThrottler(name, "chunks", 1 /* threadCount */, *maxRate, *maxReplicationLag)
+	if err != nil {
+		return nil, err
+	}
+
+	m.throttlersMu.Lock()
+	defer m.throttlersMu.Unlock()
+	if m.throttlers == nil {
+		m.throttlers = make(map[string]*throttler.Throttler)
+	}
+	m.throttlers[shard] = t
+	return t, nil
+}
+
+// closeThrottler closes the throttler created by createThrottler.
+func (m *Migration) closeThrottler(shard string) {
+	m.throttlersMu.Lock()
+	defer m.throttlersMu.Unlock()
+	if t, ok := m.throttlers[shard]; ok {
+		t.Close()
+		delete(m.throttlers, shard)
+	}
+}
+
+// setUIMessage updates the message of the root UI node and broadcasts changes.
+func (m *Migration) setUIMessage(message string) {
+	log.Infof("Online schema change on keyspace %v: %v", m.keyspace, message)
+	m.uiLogger.Infof("%v", message)
+	m.rootUINode.Log = m.uiLogger.String()
+	m.rootUINode.Message = message
+	m.rootUINode.BroadcastChanges(false /* updateChildren */)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/discovery"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	workflowpb "vitess.io/vitess/go/vt/proto/workflow"
+)
+
+func TestMigrationRun(t *testing.T) {
+	defer setChunkSize(2)()
+	// prepare, the catch up pass before the cut-over, and the one
+	// during the cut-over.
+	tmc := newFakeTabletManagerClient([]int64{1, 2, 3}, testPosition(1), testPosition(2), testPosition(3))
+	m := newTestMigration(t, tmc, "0")
+
+	fakeBinlog.reset([]*binlogdatapb.BinlogTransaction{
+		testTransaction(2,
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_SET, "SET INSERT_ID=4"),
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_INSERT, "insert into t(name) values ('x') /* _stream t (id ) (null ); */")),
+		testTransaction(3,
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_DELETE, "delete from t where id = 1 /* _stream t (id ) (1 ); */")),
+	}, nil)
+	defer fakeBinlog.reset(nil, nil)
+	// The changes replayed from the binlogs.
+	tmc.rows = []int64{2, 3, 4}
+
+	if err := m.run(context.Background()); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	if got, want := tmc.sortedPKs(), []int64{2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("rows of the shadow table: %v, want %v", got, want)
+	}
+	if got, want := tmc.queries[len(tmc.queries)-1], "rename table t to _t_old, _t_new to t"; got != want {
+		t.Errorf("last query: %v, want %v", got, want)
+	}
+	if len(m.shards) != 1 || len(m.rootUINode.Children) != 1 {
+		t.Fatalf("unexpected shard migrations: %v", m.shards)
+	}
+	if got, want := m.shards[0].uiNode.State, workflowpb.WorkflowState_Done; got != want {
+		t.Errorf("shard state: %v, want %v", got, want)
+	}
+}
+
+func TestMigrationRunError(t *testing.T) {
+	tmc := newFakeTabletManagerClient(nil, testPosition(1))
+	tmc.tables = nil
+	m := newTestMigration(t, tmc, "-80", "80-")
+
+	err := m.run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "table t does not exist") {
+		t.Fatalf("run returned %v, want table t does not exist", err)
+	}
+	if len(tmc.queries) != 0 {
+		t.Errorf("unexpected queries: %v", tmc.queries)
+	}
+	for _, sm := range m.shards {
+		if !strings.HasPrefix(sm.uiNode.Message, "Error: ") {
+			t.Errorf("shard %v message: %v, want an error", sm.shard, sm.uiNode.Message)
+		}
+	}
+}
+
+func TestRunOnAllShards(t *testing.T) {
+	m := newTestMigration(t, newFakeTabletManagerClient(nil, testPosition(1)), "-40", "40-80", "80-")
+	if err := m.createShardMigrations(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(m.rootUINode.Children), 3; got != want {
+		t.Fatalf("got %v UI nodes, want %v", got, want)
+	}
+
+	err := m.runOnAllShards(func(sm *shardMigration) error {
+		if sm.shard == "40-80" {
+			return errors.New("shard failed")
+		}
+		sm.setUIMessage("done")
+		return nil
+	})
+	if err == nil || err.Error() != "shard failed" {
+		t.Errorf("runOnAllShards returned %v, want shard failed", err)
+	}
+	for _, sm := range m.shards {
+		want := "done"
+		if sm.shard == "40-80" {
+			want = "Error: shard failed"
+		}
+		if sm.uiNode.Message != want {
+			t.Errorf("shard %v message: %v, want %v", sm.shard, sm.uiNode.Message, want)
+		}
+	}
+}
+
+func TestStatsUpdate(t *testing.T) {
+	m := newTestMigration(t, newFakeTabletManagerClient(nil, testPosition(1)), "0")
+	stats := func(tabletType topodatapb.TabletType, shard string) *discovery.TabletStats {
+		return &discovery.TabletStats{
+			Tablet: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 101},
+			},
+			Target: &querypb.Target{Keyspace: "ks", Shard: shard, TabletType: tabletType},
+			Up:     true,
+			Stats:  &querypb.RealtimeStats{SecondsBehindMaster: 30},
+		}
+	}
+
+	// No throttler yet, the update is ignored.
+	m.StatsUpdate(stats(topodatapb.TabletType_REPLICA, "0"))
+
+	tr, err := m.createThrottler("0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.throttlers["0"] != tr {
+		t.Fatalf("throttler of shard 0 was not registered: %v", m.throttlers)
+	}
+	m.StatsUpdate(stats(topodatapb.TabletType_MASTER, "0"))
+	m.StatsUpdate(stats(topodatapb.TabletType_REPLICA, "1"))
+	m.StatsUpdate(stats(topodatapb.TabletType_REPLICA, "0"))
+	m.StatsUpdate(stats(topodatapb.TabletType_RDONLY, "0"))
+
+	m.closeThrottler("0")
+	if len(m.throttlers) != 0 {
+		t.Errorf("throttler of shard 0 was not removed: %v", m.throttlers)
+	}
+	// Updates after the copy are ignored too.
+	m.StatsUpdate(stats(topodatapb.TabletType_REPLICA, "0"))
+}
@@ -0,0 +1,447 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/workflow"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	workflowpb "vitess.io/vitess/go/vt/proto/workflow"
+)
+
+// shardMigration runs the online schema change on a single shard.
+//
+// The rows of the original table are copied in chunks to a shadow table
+// created with the new schema, while the changes made to the original
+// table in the meantime are read from the binlogs of the master and
+// replayed on the shadow table. Once the shadow table has caught up, the
+// original table is briefly blacklisted on the master, the transactions
+// open at that time are waited for, the last changes are replayed and both tables are swapped with a single RENAME TABLE.
+type shardMigration struct {
+	parent *Migration
+	shard  string
+
+	// uiNode is the UI node representing this shard.
+	uiNode *workflow.Node
+	// uiLogger is the logger collecting logs that will be displayed
+	// in the UI for this shard.
+	uiLogger *logutil.MemoryLogger
+
+	// master is the master tablet of the shard. The migration is run
+	// on the master and replicated to the other tablets.
+	master *topodatapb.Tablet
+	// copier copies rows from the original table to the shadow table.
+	copier *copier
+	// rowCount is the approximate number of rows in the original table.
+	rowCount uint64
+	// rowsCopied is the number of rows copied so far.
+	rowsCopied uint64
+	// pos is the replication position up to which all the changes of
+	// the original table have been applied to the shadow table.
+	pos mysql.Position
+}
+
+func (sm *shardMigration) table() string {
+	return sm.parent.table
+}
+
+// prepare creates the shadow table and applies the schema change to it.
+func (sm *shardMigration) prepare(ctx context.Context) error {
+	sm.setUIMessage("Creating the shadow table")
+	si, err := sm.parent.topoServer.GetShard(ctx, sm.parent.keyspace, sm.shard)
+	if err != nil {
+		return err
+	}
+	if !si.HasMaster() {
+		return fmt.Errorf("shard %v/%v has no master", sm.parent.keyspace, sm.shard)
+	}
+	ti, err := sm.parent.topoServer.GetTablet(ctx, si.MasterAlias)
+	if err != nil {
+		return err
+	}
+	sm.master = ti.Tablet
+
+	td, err := sm.getTableDefinition(ctx, sm.table())
+	if err != nil {
+		return err
+	}
+	if td == nil {
+		return fmt.Errorf("table %v does not exist on shard %v/%v", sm.table(), sm.parent.keyspace, sm.shard)
+	}
+	if len(td.PrimaryKeyColumns) != 1 {
+		return fmt.Errorf("online schema change requires a single column primary key, table %v has %v", sm.table(), td.PrimaryKeyColumns)
+	}
+	old, err := sm.getTableDefinition(ctx, oldTableName(sm.table()))
+	if err != nil {
+		return err
+	}
+	if old != nil {
+		return fmt.Errorf("table %v left behind by a previous online schema change must be dropped first", old.Name)
+	}
+
+	shadow := shadowTableName(sm.table())
+	for _, sql := range []string{
+		fmt.Sprintf("drop table if exists %s", sqlescapeTable(shadow)),
+		fmt.Sprintf("create table %s like %s", sqlescapeTable(shadow), sqlescapeTable(sm.table())),
+		shadowAlter(sm.parent.sql, shadow),
+	} {
+		if _, err := sm.execute(ctx, sql, false /* reloadSchema */); err != nil {
+			return err
+		}
+	}
+
+	shadowTd, err := sm.getTableDefinition(ctx, shadow)
+	if err != nil {
+		return err
+	}
+	if shadowTd == nil {
+		return fmt.Errorf("shadow table %v was not created on shard %v/%v", shadow, sm.parent.keyspace, sm.shard)
+	}
+	if len(shadowTd.PrimaryKeyColumns) != 1 || !strings.EqualFold(shadowTd.PrimaryKeyColumns[0], td.PrimaryKeyColumns[0]) {
+		return fmt.Errorf("online schema change cannot change the primary key of table %v", sm.table())
+	}
+	columns := commonColumns(td.Columns, shadowTd.Columns)
+	sm.copier = newCopier(sm.table(), shadow, td.PrimaryKeyColumns[0], columns)
+	sm.rowCount = td.RowCount
+
+	// All the changes made after this position will be replayed
+	// on the shadow table.
+	sm.pos, err = sm.masterPosition(ctx)
+	return err
+}
+
+// copyRows copies the rows of the original table to the shadow table,
+// one chunk at a time, at the rate allowed by the throttler. The rate is
+// lowered when the replicas of the shard lag behind the master.
+func (sm *shardMigration) copyRows(ctx context.Context) error {
+	sm.setUIMessage("Copying rows to the shadow table")
+	t, err := sm.parent.createThrottler(sm.shard)
+	if err != nil {
+		return err
+	}
+	defer sm.parent.closeThrottler(sm.shard)
+
+	sm.uiNode.Display = workflow.NodeDisplayDeterminate
+	lastPK := sqltypes.NULL
+	for {
+		if err := t.Wait(ctx, 0 /* threadID */); err != nil {
+			return err
+		}
+		qr, err := sm.execute(ctx, sm.copier.chunkEndQuery(lastPK, *chunkSize), false /* reloadSchema */)
+		if err != nil {
+			return err
+		}
+		if len(qr.Rows) != 1 || qr.Rows[0][0].IsNull() {
+			break
+		}
+		endPK := qr.Rows[0][0]
+		qr, err = sm.execute(ctx, sm.copier.copyRangeQuery(lastPK, endPK), false /* reloadSchema */)
+		if err != nil {
+			return err
+		}
+		lastPK = endPK
+		sm.rowsCopied += qr.RowsAffected
+		sm.updateProgress()
+	}
+	sm.uiNode.Progress = 100
+	sm.uiNode.ProgressMessage = fmt.Sprintf("%v rows copied", sm.rowsCopied)
+	sm.uiNode.Display = workflow.NodeDisplayNone
+	sm.uiNode.BroadcastChanges(false /* updateChildren */)
+	return nil
+}
+
+// catchUp replays on the shadow table the changes made to the original
+// table since sm.pos, until the current position of the master is
+// reached. It returns the number of rows that were resynced.
+func (sm *shardMigration) catchUp(ctx context.Context) (int, error) {
+	target, err := sm.masterPosition(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if sm.pos.AtLeast(target) {
+		return 0, nil
+	}
+
+	client, err := binlogplayer.NewClient()
+	if err != nil {
+		return 0, err
+	}
+	if err := client.Dial(sm.master); err != nil {
+		return 0, fmt.Errorf("error dialing binlog server: %v", err)
+	}
+	defer client.Close()
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := client.StreamTables(streamCtx, mysql.EncodePosition(sm.pos), []string{sm.table()}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error sending streaming query to binlog server: %v", err)
+	}
+
+	resynced := 0
+	pending := make(map[string]sqltypes.Value)
+	flush := func(pos mysql.Position) error {
+		if len(pending) != 0 {
+			pks := make([]sqltypes.Value, 0, len(pending))
+			for _, pk := range pending {
+				pks = append(pks, pk)
+			}
+			for _, sql := range sm.copier.resyncQueries(pks) {
+				if _, err := sm.execute(ctx, sql, false /* reloadSchema */); err != nil {
+					return err
+				}
+			}
+			resynced += len(pks)
+			pending = make(map[string]sqltypes.Value)
+		}
+		sm.pos = pos
+		return nil
+	}
+
+	for {
+		tx, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				err = fmt.Errorf("binlog stream ended before reaching %v", target)
+			}
+			return resynced, err
+		}
+		if tx.EventToken == nil {
+			continue
+		}
+		pos, err := mysql.DecodePosition(tx.EventToken.Position)
+		if err != nil {
+			return resynced, err
+		}
+
+		var insertid int64
+		for _, stmt := range tx.Statements {
+			switch stmt.Category {
+			case binlogdatapb.BinlogTransaction_Statement_BL_SET:
+				if id, ok := binlog.ParseInsertID(string(stmt.Sql)); ok {
+					insertid = id
+				}
+			case binlogdatapb.BinlogTransaction_Statement_BL_INSERT,
+				binlogdatapb.BinlogTransaction_Statement_BL_UPDATE,
+				binlogdatapb.BinlogTransaction_Statement_BL_DELETE:
+				table, pks, nextid, err := parseStreamComment(string(stmt.Sql), insertid)
+				if err != nil {
+					return resynced, fmt.Errorf("cannot replay statement %q: %v", stmt.Sql, err)
+				}
+				insertid = nextid
+				if table != sm.table() {
+					continue
+				}
+				for _, pk := range pks {
+					pending[pk.ToString()] = pk
+				}
+			}
+		}
+
+		if pos.AtLeast(target) {
+			return resynced, flush(pos)
+		}
+		if len(pending) >= *chunkSize {
+			if err := flush(pos); err != nil {
+				return resynced, err
+			}
+		}
+	}
+}
+
+// catchUpUntilClose runs catch up passes until the number of changes
+// replayed by a pass is small enough to run the cut-over.
+func (sm *shardMigration) catchUpUntilClose(ctx context.Context) error {
+	sm.setUIMessage("Replaying ongoing changes on the shadow table")
+	for i := 0; i < *catchUpPasses; i++ {
+		resynced, err := sm.catchUp(ctx)
+		if err != nil {
+			return err
+		}
+		sm.uiLogger.Infof("Catch up pass %v replayed %v rows", i+1, resynced)
+		if resynced < *chunkSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// cutOver swaps the original and the shadow tables. The original table
+// is blacklisted on the master while the last changes are replayed, so
+// no write can be lost during the swap.
+func (sm *shardMigration) cutOver(ctx context.Context) (err error) {
+	sm.setUIMessage("Blacklisting the original table on the master")
+	if err := sm.setBlacklisted(ctx, false /* remove */); err != nil {
+		return err
+	}
+	defer func() {
+		if rerr := sm.setBlacklisted(ctx, true /* remove */); rerr != nil && err == nil {
+			err = rerr
+		}
+	}()
+
+	// The transactions started before the table was blacklisted can
+	// still write to it: they must be over before the last changes are
+	// read from the binlogs.
+	sm.setUIMessage("Waiting for the open transactions to end")
+	if err := sm.drainTransactions(ctx); err != nil {
+		return err
+	}
+
+	sm.setUIMessage("Replaying the last changes on the shadow table")
+	if _, err := sm.catchUp(ctx); err != nil {
+		return err
+	}
+
+	sm.setUIMessage("Swapping the original and the shadow tables")
+	rename := fmt.Sprintf("rename table %s to %s, %s to %s",
+		sqlescapeTable(sm.table()), sqlescapeTable(oldTableName(sm.table())),
+		sqlescapeTable(shadowTableName(sm.table())), sqlescapeTable(sm.table()))
+	if _, err := sm.execute(ctx, rename, true /* reloadSchema */); err != nil {
+		return err
+	}
+	sm.uiLogger.Infof("The original table was renamed to %v and can be dropped", oldTableName(sm.table()))
+	return nil
+}
+
+// drainTransactionsPollInterval is how often the open transactions are
+// listed by drainTransactions.
+var drainTransactionsPollInterval = 100 * time.Millisecond
+
+// openTransactionsQuery lists the InnoDB transactions open on the master,
+// except the one of the connection running it.
+const openTransactionsQuery = "select trx_id from information_schema.innodb_trx where trx_mysql_thread_id != connection_id()"
+
+// drainTransactions waits until the transactions open on the master when
+// it is called have ended, for at most --online_ddl_drain_timeout.
+func (sm *shardMigration) drainTransactions(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, *drainTimeout)
+	defer cancel()
+
+	open, err := sm.openTransactions(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for len(open) > 0 {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%v transactions are still open on shard %v/%v after %v, the original table cannot be swapped", len(open), sm.parent.keyspace, sm.shard, *drainTimeout)
+		case <-time.After(drainTransactionsPollInterval):
+		}
+		if open, err = sm.openTransactions(ctx, open); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openTransactions returns the ids of the transactions open on the
+// master. If among is not nil, only the ones it contains are returned.
+func (sm *shardMigration) openTransactions(ctx context.Context, among map[string]bool) (map[string]bool, error) {
+	qr, err := sm.execute(ctx, openTransactionsQuery, false /* reloadSchema */)
+	if err != nil {
+		return nil, err
+	}
+	open := make(map[string]bool)
+	for _, row := range qr.Rows {
+		id := row[0].ToString()
+		if among == nil || among[id] {
+			open[id] = true
+		}
+	}
+	return open, nil
+}
+
+// setBlacklisted adds or removes the original table to the blacklisted
+// tables of the master, and refreshes the master so it takes effect.
+func (sm *shardMigration) setBlacklisted(ctx context.Context, remove bool) error {
+	if err := sm.parent.wr.SetShardTabletControl(ctx, sm.parent.keyspace, sm.shard, topodatapb.TabletType_MASTER, nil, remove, false /* disableQueryService */, []string{sm.table()}); err != nil {
+		return err
+	}
+	return sm.parent.tabletClient.RefreshState(ctx, sm.master)
+}
+
+func (sm *shardMigration) getTableDefinition(ctx context.Context, table string) (*tabletmanagerdatapb.TableDefinition, error) {
+	sd, err := sm.parent.tabletClient.GetSchema(ctx, sm.master, []string{table}, nil /* excludeTables */, false /* includeViews */)
+	if err != nil {
+		return nil, err
+	}
+	for _, td := range sd.TableDefinitions {
+		if td.Name == table {
+			return td, nil
+		}
+	}
+	return nil, nil
+}
+
+func (sm *shardMigration) masterPosition(ctx context.Context) (mysql.Position, error) {
+	pos, err := sm.parent.tabletClient.MasterPosition(ctx, sm.master)
+	if err != nil {
+		return mysql.Position{}, err
+	}
+	return mysql.DecodePosition(pos)
+}
+
+// execute runs a query on the master. Binlogs are kept enabled so that
+// the statements are replicated to the other tablets of the shard.
+func (sm *shardMigration) execute(ctx context.Context, sql string, reloadSchema bool) (*sqltypes.Result, error) {
+	qr, err := sm.parent.tabletClient.ExecuteFetchAsDba(ctx, sm.master, false /* usePool */, []byte(sql), 10000 /* maxRows */, false /* disableBinlogs */, reloadSchema)
+	if err != nil {
+		return nil, fmt.Errorf("%v failed on shard %v/%v: %v", sql, sm.parent.keyspace, sm.shard, err)
+	}
+	return sqltypes.Proto3ToResult(qr), nil
+}
+
+func (sm *shardMigration) updateProgress() {
+	if sm.rowCount > 0 {
+		progress := int(sm.rowsCopied * 100 / sm.rowCount)
+		if progress > 99 {
+			progress = 99
+		}
+		sm.uiNode.Progress = progress
+	}
+	sm.uiNode.ProgressMessage = fmt.Sprintf("%v/~%v rows copied", sm.rowsCopied, sm.rowCount)
+	sm.uiNode.BroadcastChanges(false /* updateChildren */)
+}
+
+func (sm *shardMigration) setUIMessage(message string) {
+	log.Infof("Online schema change on %v/%v: %v", sm.parent.keyspace, sm.shard, message)
+	sm.uiLogger.Infof("%v", message)
+	sm.uiNode.Log = sm.uiLogger.String()
+	sm.uiNode.Message = message
+	sm.uiNode.BroadcastChanges(false /* updateChildren */)
+}
+
+func (sm *shardMigration) setState(state workflowpb.WorkflowState) {
+	sm.uiNode.State = state
+	sm.uiNode.BroadcastChanges(false /* updateChildren */)
+}
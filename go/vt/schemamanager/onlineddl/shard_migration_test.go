@@ -0,0 +1,702 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/workflow"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const fakeBinlogPlayerProtocol = "onlineddl_test"
+
+// fakeBinlog is the binlog server of the master tablet, shared by all
+// the clients created by the fake binlog player protocol.
+var fakeBinlog = &fakeBinlogClient{}
+
+func init() {
+	binlogplayer.RegisterClientFactory(fakeBinlogPlayerProtocol, func() binlogplayer.Client {
+		return fakeBinlog
+	})
+}
+
+// testPosition returns the position of the master after n transactions.
+func testPosition(n int) string {
+	return fmt.Sprintf("MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-%d", n)
+}
+
+// fakeBinlogClient implements binlogplayer.Client. It streams the
+// transactions that come after the requested position.
+type fakeBinlogClient struct {
+	mu           sync.Mutex
+	transactions []*binlogdatapb.BinlogTransaction
+	// onStream is called every time a stream is started.
+	onStream func()
+}
+
+func (fbc *fakeBinlogClient) reset(transactions []*binlogdatapb.BinlogTransaction, onStream func()) {
+	fbc.mu.Lock()
+	defer fbc.mu.Unlock()
+	fbc.transactions = transactions
+	fbc.onStream = onStream
+}
+
+// Dial is part of the binlogplayer.Client interface.
+func (fbc *fakeBinlogClient) Dial(tablet *topodatapb.Tablet) error {
+	return nil
+}
+
+// Close is part of the binlogplayer.Client interface.
+func (fbc *fakeBinlogClient) Close() {
+}
+
+// StreamTables is part of the binlogplayer.Client interface.
+func (fbc *fakeBinlogClient) StreamTables(ctx context.Context, position string, tables []string, charset *binlogdatapb.Charset) (binlogplayer.BinlogTransactionStream, error) {
+	fbc.mu.Lock()
+	defer fbc.mu.Unlock()
+	if fbc.onStream != nil {
+		fbc.onStream()
+	}
+	start, err := mysql.DecodePosition(position)
+	if err != nil {
+		return nil, err
+	}
+	stream := &fakeBinlogStream{}
+	for _, tx := range fbc.transactions {
+		pos, err := mysql.DecodePosition(tx.EventToken.Position)
+		if err != nil {
+			return nil, err
+		}
+		if !start.AtLeast(pos) {
+			stream.transactions = append(stream.transactions, tx)
+		}
+	}
+	return stream, nil
+}
+
+// StreamKeyRange is part of the binlogplayer.Client interface.
+func (fbc *fakeBinlogClient) StreamKeyRange(ctx context.Context, position string, keyRange *topodatapb.KeyRange, charset *binlogdatapb.Charset) (binlogplayer.BinlogTransactionStream, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+type fakeBinlogStream struct {
+	transactions []*binlogdatapb.BinlogTransaction
+}
+
+// Recv is part of the binlogplayer.BinlogTransactionStream interface.
+func (fbs *fakeBinlogStream) Recv() (*binlogdatapb.BinlogTransaction, error) {
+	if len(fbs.transactions) == 0 {
+		return nil, io.EOF
+	}
+	tx := fbs.transactions[0]
+	fbs.transactions = fbs.transactions[1:]
+	return tx, nil
+}
+
+// testTransaction returns a transaction made of the given statements,
+// that brings the master to testPosition(n).
+func testTransaction(n int, statements ...*binlogdatapb.BinlogTransaction_Statement) *binlogdatapb.BinlogTransaction {
+	return &binlogdatapb.BinlogTransaction{
+		Statements: statements,
+		EventToken: &querypb.EventToken{Position: testPosition(n)},
+	}
+}
+
+func testStatement(category binlogdatapb.BinlogTransaction_Statement_Category, sql string) *binlogdatapb.BinlogTransaction_Statement {
+	return &binlogdatapb.BinlogTransaction_Statement{
+		Category: category,
+		Sql:      []byte(sql),
+	}
+}
+
+var (
+	lastPKRE = regexp.MustCompile(`id > (\d+)`)
+	endPKRE  = regexp.MustCompile(`id <= (\d+)`)
+	limitRE  = regexp.MustCompile(`limit (\d+)`)
+	inRE     = regexp.MustCompile(`id in \(([^)]*)\)`)
+)
+
+// fakeTabletManagerClient simulates the master of a shard holding
+// the table "t", with the primary key "id".
+type fakeTabletManagerClient struct {
+	tmclient.TabletManagerClient
+
+	mu sync.Mutex
+	// tables contains the definitions returned by GetSchema.
+	tables map[string]*tabletmanagerdatapb.TableDefinition
+	// rows contains the primary keys of the rows of "t".
+	rows []int64
+	// shadow contains the primary keys of the rows of the shadow table.
+	shadow map[int64]bool
+	// positions are returned by MasterPosition, the last one is
+	// returned once all the others have been.
+	positions []string
+	// transactions are the ids of the open transactions returned by
+	// successive openTransactionsQuery, none once all have been.
+	transactions [][]string
+	// queries records the statements run with ExecuteFetchAsDba.
+	queries []string
+	// refreshes is the number of RefreshState calls.
+	refreshes int
+}
+
+func newFakeTabletManagerClient(rows []int64, positions ...string) *fakeTabletManagerClient {
+	return &fakeTabletManagerClient{
+		tables: map[string]*tabletmanagerdatapb.TableDefinition{
+			"t": {
+				Name:              "t",
+				Columns:           []string{"id", "name"},
+				PrimaryKeyColumns: []string{"id"},
+				RowCount:          uint64(len(rows)),
+			},
+		},
+		rows:      rows,
+		shadow:    make(map[int64]bool),
+		positions: positions,
+	}
+}
+
+// GetSchema is part of the tmclient.TabletManagerClient interface.
+func (client *fakeTabletManagerClient) GetSchema(ctx context.Context, tablet *topodatapb.Tablet, tables, excludeTables []string, includeViews bool) (*tabletmanagerdatapb.SchemaDefinition, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	sd := &tabletmanagerdatapb.SchemaDefinition{}
+	for _, table := range tables {
+		if td, ok := client.tables[table]; ok {
+			sd.TableDefinitions = append(sd.TableDefinitions, td)
+		}
+	}
+	return sd, nil
+}
+
+// MasterPosition is part of the tmclient.TabletManagerClient interface.
+func (client *fakeTabletManagerClient) MasterPosition(ctx context.Context, tablet *topodatapb.Tablet) (string, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	pos := client.positions[0]
+	if len(client.positions) > 1 {
+		client.positions = client.positions[1:]
+	}
+	return pos, nil
+}
+
+// RefreshState is part of the tmclient.TabletManagerClient interface.
+func (client *fakeTabletManagerClient) RefreshState(ctx context.Context, tablet *topodatapb.Tablet) error {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.refreshes++
+	return nil
+}
+
+// ExecuteFetchAsDba is part of the tmclient.TabletManagerClient interface.
+func (client *fakeTabletManagerClient) ExecuteFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, query []byte, maxRows int, disableBinlogs, reloadSchema bool) (*querypb.QueryResult, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	sql := string(query)
+	client.queries = append(client.queries, sql)
+
+	result := &sqltypes.Result{}
+	switch {
+	case strings.HasPrefix(sql, "create table _t_new like t"):
+		td := *client.tables["t"]
+		td.Name = "_t_new"
+		client.tables[td.Name] = &td
+	case strings.HasPrefix(sql, "alter table _t_new add column "):
+		td := client.tables["_t_new"]
+		td.Columns = append(td.Columns, strings.Fields(sql)[5])
+	case strings.HasPrefix(sql, "select max("):
+		var lastPK int64
+		if m := lastPKRE.FindStringSubmatch(sql); m != nil {
+			lastPK, _ = strconv.ParseInt(m[1], 10, 64)
+		}
+		limit, _ := strconv.Atoi(limitRE.FindStringSubmatch(sql)[1])
+		end := sqltypes.NULL
+		for _, pk := range client.rows {
+			if pk > lastPK && limit > 0 {
+				end = sqltypes.NewInt64(pk)
+				limit--
+			}
+		}
+		result.Fields = []*querypb.Field{{Name: "max(id)", Type: sqltypes.Int64}}
+		result.Rows = [][]sqltypes.Value{{end}}
+	case strings.HasPrefix(sql, "insert ignore into _t_new"):
+		var lastPK int64
+		if m := lastPKRE.FindStringSubmatch(sql); m != nil {
+			lastPK, _ = strconv.ParseInt(m[1], 10, 64)
+		}
+		endPK, _ := strconv.ParseInt(endPKRE.FindStringSubmatch(sql)[1], 10, 64)
+		for _, pk := range client.rows {
+			if pk > lastPK && pk <= endPK && !client.shadow[pk] {
+				client.shadow[pk] = true
+				result.RowsAffected++
+			}
+		}
+	case strings.HasPrefix(sql, "replace into _t_new"), strings.HasPrefix(sql, "delete from _t_new"):
+		for _, v := range strings.Split(inRE.FindStringSubmatch(sql)[1], ", ") {
+			pk, _ := strconv.ParseInt(v, 10, 64)
+			exists := false
+			for _, row := range client.rows {
+				if row == pk {
+					exists = true
+				}
+			}
+			if exists && strings.HasPrefix(sql, "replace") {
+				client.shadow[pk] = true
+			}
+			if !exists && strings.HasPrefix(sql, "delete") {
+				delete(client.shadow, pk)
+			}
+		}
+	case sql == openTransactionsQuery:
+		result.Fields = []*querypb.Field{{Name: "trx_id", Type: sqltypes.VarChar}}
+		if len(client.transactions) > 0 {
+			for _, id := range client.transactions[0] {
+				result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewVarChar(id)})
+			}
+			client.transactions = client.transactions[1:]
+		}
+	}
+	return sqltypes.ResultToProto3(result), nil
+}
+
+// newTestMigration returns a migration of the "t" table of the "ks"
+// keyspace, whose shards have a master served by tmc.
+func newTestMigration(t *testing.T, tmc tmclient.TabletManagerClient, shards ...string) *Migration {
+	if err := flag.Set("binlog_player_protocol", fakeBinlogPlayerProtocol); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	if err := ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}); err != nil {
+		t.Fatal(err)
+	}
+	for i, shard := range shards {
+		if err := ts.CreateShard(ctx, "ks", shard); err != nil {
+			t.Fatal(err)
+		}
+		master := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uint32(100 + i)},
+			Keyspace: "ks",
+			Shard:    shard,
+			Type:     topodatapb.TabletType_MASTER,
+		}
+		if err := ts.CreateTablet(ctx, master); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ts.UpdateShardFields(ctx, "ks", shard, func(si *topo.ShardInfo) error {
+			si.MasterAlias = master.Alias
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rootNode := workflow.NewNode()
+	rootNode.PathName = "online_ddl"
+	if err := workflow.NewNodeManager().AddRootNode(rootNode); err != nil {
+		t.Fatal(err)
+	}
+	sql := "alter table t add column c int"
+	table, err := parseAlter(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Migration{
+		keyspace:     "ks",
+		sql:          sql,
+		table:        table,
+		rootUINode:   rootNode,
+		uiLogger:     logutil.NewMemoryLogger(),
+		topoServer:   ts,
+		tabletClient: tmc,
+		wr:           wrangler.New(logutil.NewConsoleLogger(), ts, tmc),
+	}
+}
+
+// setChunkSize sets --online_ddl_chunk_size and returns a function
+// restoring its value.
+func setChunkSize(size int) func() {
+	saved := *chunkSize
+	*chunkSize = size
+	return func() {
+		*chunkSize = saved
+	}
+}
+
+func TestShardMigrationPrepare(t *testing.T) {
+	tmc := newFakeTabletManagerClient([]int64{1, 2, 3}, testPosition(7))
+	m := newTestMigration(t, tmc, "0")
+	if err := m.createShardMigrations(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	sm := m.shards[0]
+	if err := sm.prepare(context.Background()); err != nil {
+		t.Fatalf("prepare failed: %v", err)
+	}
+
+	wantQueries := []string{
+		"drop table if exists _t_new",
+		"create table _t_new like t",
+		"alter table _t_new add column c int",
+	}
+	if !reflect.DeepEqual(tmc.queries, wantQueries) {
+		t.Errorf("queries:\n%v, want\n%v", strings.Join(tmc.queries, "\n"), strings.Join(wantQueries, "\n"))
+	}
+	if got, want := sm.master.Alias.Uid, uint32(100); got != want {
+		t.Errorf("master uid: %v, want %v", got, want)
+	}
+	if got, want := mysql.EncodePosition(sm.pos), testPosition(7); got != want {
+		t.Errorf("pos: %v, want %v", got, want)
+	}
+	if got, want := sm.copier.columns, (sqlparser.Columns{sqlparser.NewColIdent("id"), sqlparser.NewColIdent("name")}); !reflect.DeepEqual(got, want) {
+		t.Errorf("copied columns: %v, want %v", got, want)
+	}
+}
+
+func TestShardMigrationPrepareErrors(t *testing.T) {
+	testcases := []struct {
+		name   string
+		tables map[string]*tabletmanagerdatapb.TableDefinition
+		err    string
+	}{{
+		name:   "missing table",
+		tables: map[string]*tabletmanagerdatapb.TableDefinition{},
+		err:    "table t does not exist",
+	}, {
+		name: "composite primary key",
+		tables: map[string]*tabletmanagerdatapb.TableDefinition{
+			"t": {Name: "t", Columns: []string{"a", "b"}, PrimaryKeyColumns: []string{"a", "b"}},
+		},
+		err: "requires a single column primary key",
+	}, {
+		name: "leftover table",
+		tables: map[string]*tabletmanagerdatapb.TableDefinition{
+			"t":      {Name: "t", Columns: []string{"id"}, PrimaryKeyColumns: []string{"id"}},
+			"_t_old": {Name: "_t_old", Columns: []string{"id"}, PrimaryKeyColumns: []string{"id"}},
+		},
+		err: "table _t_old left behind",
+	}}
+	for _, tcase := range testcases {
+		tmc := newFakeTabletManagerClient(nil, testPosition(1))
+		tmc.tables = tcase.tables
+		m := newTestMigration(t, tmc, "0")
+		if err := m.createShardMigrations(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.shards[0].prepare(context.Background()); err == nil || !strings.Contains(err.Error(), tcase.err) {
+			t.Errorf("%v: prepare returned %v, want %v", tcase.name, err, tcase.err)
+		}
+		if len(tmc.queries) != 0 {
+			t.Errorf("%v: unexpected queries: %v", tcase.name, tmc.queries)
+		}
+	}
+}
+
+func TestShardMigrationPrepareNoMaster(t *testing.T) {
+	tmc := newFakeTabletManagerClient(nil, testPosition(1))
+	m := newTestMigration(t, tmc, "0")
+	if err := m.topoServer.CreateShard(context.Background(), "ks", "1"); err != nil {
+		t.Fatal(err)
+	}
+	sm := &shardMigration{
+		parent:   m,
+		shard:    "1",
+		uiNode:   m.rootUINode,
+		uiLogger: logutil.NewMemoryLogger(),
+	}
+	want := "shard ks/1 has no master"
+	if err := sm.prepare(context.Background()); err == nil || err.Error() != want {
+		t.Errorf("prepare returned %v, want %v", err, want)
+	}
+}
+
+func TestShardMigrationCopyRows(t *testing.T) {
+	defer setChunkSize(2)()
+	tmc := newFakeTabletManagerClient([]int64{1, 2, 5, 8, 9}, testPosition(1))
+	m := newTestMigration(t, tmc, "0")
+	if err := m.createShardMigrations(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	sm := m.shards[0]
+	if err := sm.prepare(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	tmc.queries = nil
+
+	if err := sm.copyRows(context.Background()); err != nil {
+		t.Fatalf("copyRows failed: %v", err)
+	}
+	wantQueries := []string{
+		"select max(id) from (select id from t order by id limit 2) as chunk",
+		"insert ignore into _t_new (id, name) select id, name from t where id <= 2",
+		"select max(id) from (select id from t where id > 2 order by id limit 2) as chunk",
+		"insert ignore into _t_new (id, name) select id, name from t where id > 2 and id <= 8",
+		"select max(id) from (select id from t where id > 8 order by id limit 2) as chunk",
+		"insert ignore into _t_new (id, name) select id, name from t where id > 8 and id <= 9",
+		"select max(id) from (select id from t where id > 9 order by id limit 2) as chunk",
+	}
+	if !reflect.DeepEqual(tmc.queries, wantQueries) {
+		t.Errorf("queries:\n%v, want\n%v", strings.Join(tmc.queries, "\n"), strings.Join(wantQueries, "\n"))
+	}
+	if got, want := sm.rowsCopied, uint64(5); got != want {
+		t.Errorf("rowsCopied: %v, want %v", got, want)
+	}
+	if got, want := len(tmc.shadow), 5; got != want {
+		t.Errorf("rows in the shadow table: %v, want %v", got, want)
+	}
+	if got, want := sm.uiNode.Progress, 100; got != want {
+		t.Errorf("progress: %v, want %v", got, want)
+	}
+	if len(m.throttlers) != 0 {
+		t.Errorf("throttler was not closed: %v", m.throttlers)
+	}
+}
+
+func TestShardMigrationCatchUp(t *testing.T) {
+	defer setChunkSize(10)()
+	tmc := newFakeTabletManagerClient([]int64{1, 2, 3, 4}, testPosition(1), testPosition(4))
+	m := newTestMigration(t, tmc, "0")
+	if err := m.createShardMigrations(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	sm := m.shards[0]
+	if err := sm.prepare(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	tmc.queries = nil
+
+	fakeBinlog.reset([]*binlogdatapb.BinlogTransaction{
+		testTransaction(1,
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_UPDATE, "update t set name = 'x' where id = 1 /* _stream t (id ) (1 ); */")),
+		testTransaction(2,
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_UPDATE, "update t set name = 'y' where id in (2, 3) /* _stream t (id ) (2 ) (3 ); */"),
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_UPDATE, "update u set name = 'y' where id = 7 /* _stream u (id ) (7 ); */")),
+		testTransaction(3,
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_SET, "SET INSERT_ID=4"),
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_INSERT, "insert into t(name) values ('z') /* _stream t (id ) (null ); */")),
+		testTransaction(4,
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_DELETE, "delete from t where id = 3 /* _stream t (id ) (3 ); */")),
+		testTransaction(5,
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_DELETE, "delete from t where id = 1 /* _stream t (id ) (1 ); */")),
+	}, nil)
+	defer fakeBinlog.reset(nil, nil)
+
+	resynced, err := sm.catchUp(context.Background())
+	if err != nil {
+		t.Fatalf("catchUp failed: %v", err)
+	}
+	// The pks 2, 3 and 4 were changed after testPosition(1), but not 1
+	// since it was changed before and the change after testPosition(4)
+	// is replayed by the next pass.
+	if resynced != 3 {
+		t.Errorf("catchUp resynced %v rows, want 3", resynced)
+	}
+	if len(tmc.queries) != 2 {
+		t.Fatalf("unexpected queries: %v", tmc.queries)
+	}
+	for _, pk := range []string{"2", "3", "4"} {
+		if !strings.Contains(tmc.queries[0], pk) || !strings.Contains(tmc.queries[1], pk) {
+			t.Errorf("pk %v is not resynced by %v", pk, tmc.queries)
+		}
+	}
+	if !strings.HasPrefix(tmc.queries[0], "replace into _t_new (id, name) select id, name from t where id in (") || !strings.HasPrefix(tmc.queries[1], "delete from _t_new where id in (") {
+		t.Errorf("unexpected resync queries: %v", tmc.queries)
+	}
+	if got, want := mysql.EncodePosition(sm.pos), testPosition(4); got != want {
+		t.Errorf("pos: %v, want %v", got, want)
+	}
+
+	// The master did not move, the next pass has nothing to replay.
+	tmc.queries = nil
+	resynced, err = sm.catchUp(context.Background())
+	if err != nil || resynced != 0 || len(tmc.queries) != 0 {
+		t.Errorf("second catchUp: %v, %v, queries %v, want 0 rows and no query", resynced, err, tmc.queries)
+	}
+}
+
+func TestShardMigrationCatchUpStreamEnded(t *testing.T) {
+	tmc := newFakeTabletManagerClient(nil, testPosition(1), testPosition(3))
+	m := newTestMigration(t, tmc, "0")
+	if err := m.createShardMigrations(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	sm := m.shards[0]
+	if err := sm.prepare(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	fakeBinlog.reset([]*binlogdatapb.BinlogTransaction{
+		testTransaction(2,
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_UPDATE, "update t set name = 'x' where id = 1 /* _stream t (id ) (1 ); */")),
+	}, nil)
+	defer fakeBinlog.reset(nil, nil)
+
+	want := "binlog stream ended before reaching"
+	if _, err := sm.catchUp(context.Background()); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("catchUp returned %v, want %v", err, want)
+	}
+}
+
+func TestShardMigrationCutOver(t *testing.T) {
+	tmc := newFakeTabletManagerClient([]int64{1, 2}, testPosition(1), testPosition(2))
+	m := newTestMigration(t, tmc, "0")
+	ctx := context.Background()
+	if err := m.createShardMigrations(ctx); err != nil {
+		t.Fatal(err)
+	}
+	sm := m.shards[0]
+	if err := sm.prepare(ctx); err != nil {
+		t.Fatal(err)
+	}
+	tmc.queries = nil
+	// Transaction 1 is still open after the first poll, 3 was started
+	// after the table was blacklisted and is not waited for.
+	tmc.transactions = [][]string{{"1", "2"}, {"1", "3"}, {"3"}}
+	defer setDrainTransactionsPollInterval(time.Millisecond)()
+
+	// The last changes must be replayed while the table is blacklisted.
+	blacklisted := false
+	fakeBinlog.reset([]*binlogdatapb.BinlogTransaction{
+		testTransaction(2,
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_UPDATE, "update t set name = 'x' where id = 2 /* _stream t (id ) (2 ); */")),
+	}, func() {
+		si, err := m.topoServer.GetShard(ctx, "ks", "0")
+		if err != nil {
+			t.Errorf("GetShard failed: %v", err)
+			return
+		}
+		tc := si.GetTabletControl(topodatapb.TabletType_MASTER)
+		blacklisted = tc != nil && reflect.DeepEqual(tc.BlacklistedTables, []string{"t"})
+	})
+	defer fakeBinlog.reset(nil, nil)
+
+	if err := sm.cutOver(ctx); err != nil {
+		t.Fatalf("cutOver failed: %v", err)
+	}
+	if !blacklisted {
+		t.Errorf("the table was not blacklisted while the last changes were replayed")
+	}
+	wantQueries := []string{
+		openTransactionsQuery,
+		openTransactionsQuery,
+		openTransactionsQuery,
+		"replace into _t_new (id, name) select id, name from t where id in (2)",
+		"delete from _t_new where id in (2) and id not in (select id from t where id in (2))",
+		"rename table t to _t_old, _t_new to t",
+	}
+	if !reflect.DeepEqual(tmc.queries, wantQueries) {
+		t.Errorf("queries:\n%v, want\n%v", strings.Join(tmc.queries, "\n"), strings.Join(wantQueries, "\n"))
+	}
+	si, err := m.topoServer.GetShard(ctx, "ks", "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tc := si.GetTabletControl(topodatapb.TabletType_MASTER); tc != nil && len(tc.BlacklistedTables) != 0 {
+		t.Errorf("the table is still blacklisted after the cut-over: %v", tc)
+	}
+	if tmc.refreshes != 2 {
+		t.Errorf("RefreshState was called %v times, want 2", tmc.refreshes)
+	}
+}
+
+func TestShardMigrationCutOverDrainTimeout(t *testing.T) {
+	tmc := newFakeTabletManagerClient([]int64{1}, testPosition(1))
+	m := newTestMigration(t, tmc, "0")
+	ctx := context.Background()
+	if err := m.createShardMigrations(ctx); err != nil {
+		t.Fatal(err)
+	}
+	sm := m.shards[0]
+	if err := sm.prepare(ctx); err != nil {
+		t.Fatal(err)
+	}
+	tmc.queries = nil
+	// Transaction 1 never ends.
+	for i := 0; i < 1000; i++ {
+		tmc.transactions = append(tmc.transactions, []string{"1"})
+	}
+	defer setDrainTransactionsPollInterval(time.Millisecond)()
+	savedTimeout := *drainTimeout
+	*drainTimeout = 50 * time.Millisecond
+	defer func() { *drainTimeout = savedTimeout }()
+
+	want := "1 transactions are still open on shard ks/0"
+	if err := sm.cutOver(ctx); err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("cutOver returned %v, want %v", err, want)
+	}
+	for _, sql := range tmc.queries {
+		if sql != openTransactionsQuery {
+			t.Errorf("unexpected query after the drain timeout: %v", sql)
+		}
+	}
+	si, err := m.topoServer.GetShard(ctx, "ks", "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tc := si.GetTabletControl(topodatapb.TabletType_MASTER); tc != nil && len(tc.BlacklistedTables) != 0 {
+		t.Errorf("the table is still blacklisted after the failed cut-over: %v", tc)
+	}
+}
+
+// setDrainTransactionsPollInterval sets drainTransactionsPollInterval
+// and returns a function restoring its value.
+func setDrainTransactionsPollInterval(interval time.Duration) func() {
+	saved := drainTransactionsPollInterval
+	drainTransactionsPollInterval = interval
+	return func() {
+		drainTransactionsPollInterval = saved
+	}
+}
+
+// sortedPKs returns the primary keys of the shadow table.
+func (client *fakeTabletManagerClient) sortedPKs() []int64 {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	var pks []int64
+	for pk := range client.shadow {
+		pks = append(pks, pk)
+	}
+	sort.Slice(pks, func(i, j int) bool { return pks[i] < pks[j] })
+	return pks
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// alterTablePrefix matches the beginning of an ALTER TABLE statement up to
+// and including the table name.
+var alterTablePrefix = regexp.MustCompile("(?is)^\\s*alter\\s+(ignore\\s+)?table\\s+(`[^`]+`|[a-z0-9_$]+)")
+
+// parseAlter checks that sql is a single ALTER TABLE statement that can be
+// executed as a shadow-table copy, and returns the name of the altered table.
+func parseAlter(sql string) (string, error) {
+	stmt, err := sqlparser.Parse(sql)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse %q: %v", sql, err)
+	}
+	ddl, ok := stmt.(*sqlparser.DDL)
+	if !ok || ddl.Action != sqlparser.AlterStr {
+		return "", fmt.Errorf("online schema change only supports ALTER TABLE statements, got: %q", sql)
+	}
+	if !ddl.Table.Qualifier.IsEmpty() {
+		return "", fmt.Errorf("online schema change does not support qualified table names: %q", sql)
+	}
+	if !alterTablePrefix.MatchString(sql) {
+		return "", fmt.Errorf("cannot find the table name in: %q", sql)
+	}
+	return ddl.Table.Name.String(), nil
+}
+
+// shadowAlter rewrites an ALTER TABLE statement validated by parseAlter
+// so that it applies to the shadow table instead.
+func shadowAlter(sql, shadow string) string {
+	loc := alterTablePrefix.FindStringSubmatchIndex(sql)
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("alter table %v", sqlparser.NewTableIdent(shadow))
+	return buf.String() + sql[loc[1]:]
+}
+
+// shadowTableName returns the name of the table the rows are copied to.
+func shadowTableName(table string) string {
+	return fmt.Sprintf("_%s_new", table)
+}
+
+// oldTableName returns the name the original table is renamed to
+// during the cut-over.
+func oldTableName(table string) string {
+	return fmt.Sprintf("_%s_old", table)
+}
+
+// sqlescapeTable returns the table name escaped for use in a query.
+func sqlescapeTable(name string) string {
+	return sqlparser.String(sqlparser.NewTableIdent(name))
+}
+
+// commonColumns returns the columns of from that also exist in to, in
+// the order of from. Those are the columns copied to the shadow table.
+func commonColumns(from, to []string) []string {
+	exists := make(map[string]bool, len(to))
+	for _, col := range to {
+		exists[strings.ToLower(col)] = true
+	}
+	var result []string
+	for _, col := range from {
+		if exists[strings.ToLower(col)] {
+			result = append(result, col)
+		}
+	}
+	return result
+}
+
+// copier generates the statements used to copy rows from one table to another.
+type copier struct {
+	source  sqlparser.TableIdent
+	target  sqlparser.TableIdent
+	pk      sqlparser.ColIdent
+	columns sqlparser.Columns
+}
+
+func newCopier(source, target, pk string, columns []string) *copier {
+	c := &copier{
+		source: sqlparser.NewTableIdent(source),
+		target: sqlparser.NewTableIdent(target),
+		pk:     sqlparser.NewColIdent(pk),
+	}
+	for _, col := range columns {
+		c.columns = append(c.columns, sqlparser.NewColIdent(col))
+	}
+	return c
+}
+
+// chunkEndQuery returns the query that fetches the last pk of the chunk
+// of at most size rows that starts right after lastPK. A NULL lastPK
+// means the chunk starts at the beginning of the table.
+func (c *copier) chunkEndQuery(lastPK sqltypes.Value, size int) string {
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("select max(%v) from (select %v from %v", c.pk, c.pk, c.source)
+	if !lastPK.IsNull() {
+		buf.Myprintf(" where %v > ", c.pk)
+		lastPK.EncodeSQL(buf)
+	}
+	buf.Myprintf(" order by %v limit %s) as chunk", c.pk, strconv.Itoa(size))
+	return buf.String()
+}
+
+// copyRangeQuery returns the query that copies the rows with a pk in
+// (lastPK, endPK] from the source to the target table.
+func (c *copier) copyRangeQuery(lastPK, endPK sqltypes.Value) string {
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("insert ignore into %v %v select %v from %v where ", c.target, c.columns, sqlparser.SelectExprs(c.selectExprs()), c.source)
+	if !lastPK.IsNull() {
+		buf.Myprintf("%v > ", c.pk)
+		lastPK.EncodeSQL(buf)
+		buf.Myprintf(" and ")
+	}
+	buf.Myprintf("%v <= ", c.pk)
+	endPK.EncodeSQL(buf)
+	return buf.String()
+}
+
+// resyncQueries returns the statements that make the rows of the target
+// table identical to the ones of the source table for the given pks:
+// the rows still in the source are copied over the target ones with a
+// REPLACE, and the others are deleted from the target. Each statement is
+// atomic, so a row is never missing from the target while it exists in
+// the source.
+func (c *copier) resyncQueries(pks []sqltypes.Value) []string {
+	in := sqlparser.NewTrackedBuffer(nil)
+	in.Myprintf("%v in (", c.pk)
+	for i, pk := range pks {
+		if i != 0 {
+			in.Myprintf(", ")
+		}
+		pk.EncodeSQL(in)
+	}
+	in.Myprintf(")")
+
+	repl := sqlparser.NewTrackedBuffer(nil)
+	repl.Myprintf("replace into %v %v select %v from %v where %s", c.target, c.columns, sqlparser.SelectExprs(c.selectExprs()), c.source, in.String())
+	del := sqlparser.NewTrackedBuffer(nil)
+	del.Myprintf("delete from %v where %s and %v not in (select %v from %v where %s)", c.target, in.String(), c.pk, c.pk, c.source, in.String())
+	return []string{repl.String(), del.String()}
+}
+
+func (c *copier) selectExprs() []sqlparser.SelectExpr {
+	exprs := make([]sqlparser.SelectExpr, 0, len(c.columns))
+	for _, col := range c.columns {
+		exprs = append(exprs, &sqlparser.AliasedExpr{Expr: &sqlparser.ColName{Name: col}})
+	}
+	return exprs
+}
+
+// parseStreamComment extracts the table name and the values of the single
+// column primary key from the stream comment vttablet appends to every DML,
+// like "/* _stream t (id ) (1 ) (2 ); */". See binlog.ParseStreamComment.
+func parseStreamComment(sql string, insertid int64) (string, []sqltypes.Value, int64, error) {
+	table, columns, pks, nextid, err := binlog.ParseStreamComment(sql, insertid)
+	if err != nil {
+		return "", nil, nextid, err
+	}
+	if len(columns) != 1 {
+		return "", nil, nextid, fmt.Errorf("expecting a single primary key column, got %d", len(columns))
+	}
+	values := make([]sqltypes.Value, 0, len(pks))
+	for _, pk := range pks {
+		values = append(values, pk[0])
+	}
+	return table, values, nextid, nil
+}
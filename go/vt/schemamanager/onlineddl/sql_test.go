@@ -0,0 +1,166 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+func TestParseAlter(t *testing.T) {
+	testcases := []struct {
+		sql    string
+		table  string
+		shadow string
+		err    string
+	}{{
+		sql:    "alter table t add column c int",
+		table:  "t",
+		shadow: "alter table _t_new add column c int",
+	}, {
+		sql:    "  ALTER IGNORE TABLE `my table` ADD INDEX (c)",
+		table:  "my table",
+		shadow: "alter table `_my table_new` ADD INDEX (c)",
+	}, {
+		sql: "create table t (id int)",
+		err: "only supports ALTER TABLE",
+	}, {
+		sql: "alter table t rename to u",
+		err: "only supports ALTER TABLE",
+	}, {
+		sql: "alter table ks.t add column c int",
+		err: "qualified table names",
+	}, {
+		sql: "alter table",
+		err: "cannot parse",
+	}}
+	for _, tcase := range testcases {
+		table, err := parseAlter(tcase.sql)
+		if tcase.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tcase.err) {
+				t.Errorf("parseAlter(%q): %v, want %v", tcase.sql, err, tcase.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseAlter(%q): %v", tcase.sql, err)
+			continue
+		}
+		if table != tcase.table {
+			t.Errorf("parseAlter(%q): %v, want %v", tcase.sql, table, tcase.table)
+		}
+		if got := shadowAlter(tcase.sql, shadowTableName(table)); got != tcase.shadow {
+			t.Errorf("shadowAlter(%q): %v, want %v", tcase.sql, got, tcase.shadow)
+		}
+	}
+}
+
+func TestCommonColumns(t *testing.T) {
+	got := commonColumns([]string{"id", "a", "b", "c"}, []string{"C", "id", "d", "b"})
+	want := []string{"id", "b", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("commonColumns: %v, want %v", got, want)
+	}
+}
+
+func TestCopier(t *testing.T) {
+	c := newCopier("t", "_t_new", "id", []string{"id", "name"})
+
+	got := c.chunkEndQuery(sqltypes.NULL, 10)
+	want := "select max(id) from (select id from t order by id limit 10) as chunk"
+	if got != want {
+		t.Errorf("chunkEndQuery: %v, want %v", got, want)
+	}
+	got = c.chunkEndQuery(sqltypes.NewInt64(10), 10)
+	want = "select max(id) from (select id from t where id > 10 order by id limit 10) as chunk"
+	if got != want {
+		t.Errorf("chunkEndQuery: %v, want %v", got, want)
+	}
+
+	got = c.copyRangeQuery(sqltypes.NULL, sqltypes.NewInt64(10))
+	want = "insert ignore into _t_new (id, name) select id, name from t where id <= 10"
+	if got != want {
+		t.Errorf("copyRangeQuery: %v, want %v", got, want)
+	}
+	got = c.copyRangeQuery(sqltypes.NewInt64(10), sqltypes.NewInt64(20))
+	want = "insert ignore into _t_new (id, name) select id, name from t where id > 10 and id <= 20"
+	if got != want {
+		t.Errorf("copyRangeQuery: %v, want %v", got, want)
+	}
+
+	gotQueries := c.resyncQueries([]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarBinary("a'b")})
+	wantQueries := []string{
+		"replace into _t_new (id, name) select id, name from t where id in (1, 'a\\'b')",
+		"delete from _t_new where id in (1, 'a\\'b') and id not in (select id from t where id in (1, 'a\\'b'))",
+	}
+	if !reflect.DeepEqual(gotQueries, wantQueries) {
+		t.Errorf("resyncQueries: %v, want %v", gotQueries, wantQueries)
+	}
+}
+
+func TestParseStreamComment(t *testing.T) {
+	testcases := []struct {
+		sql      string
+		insertid int64
+		table    string
+		pks      []sqltypes.Value
+		nextid   int64
+		err      string
+	}{{
+		sql:   "update t set a=1 where id in (1, 2) /* _stream t (id ) (1 ) (2 ); */",
+		table: "t",
+		pks:   []sqltypes.Value{sqltypes.NewUint64(1), sqltypes.NewUint64(2)},
+	}, {
+		sql:      "insert into t(a) values (1), (2) /* _stream t (id ) (null ) (null ); */",
+		insertid: 5,
+		table:    "t",
+		pks:      []sqltypes.Value{sqltypes.NewInt64(5), sqltypes.NewInt64(6)},
+		nextid:   7,
+	}, {
+		sql:   "delete from t where name = 'a' /* _stream t (name ) ('YQ==' ) (-3 ); */",
+		table: "t",
+		pks:   []sqltypes.Value{sqltypes.NewVarBinary("a"), sqltypes.NewInt64(-3)},
+	}, {
+		sql: "delete from t",
+		err: "missing stream comment",
+	}, {
+		sql: "delete from t where a=1 and b=2 /* _stream t (a b ) (1 2 ); */",
+		err: "single primary key column",
+	}, {
+		sql: "delete from t where id=1 /* _stream t (id ) (1 2 ); */",
+		err: "length mismatch in values",
+	}}
+	for _, tcase := range testcases {
+		table, pks, nextid, err := parseStreamComment(tcase.sql, tcase.insertid)
+		if tcase.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tcase.err) {
+				t.Errorf("parseStreamComment(%q): %v, want %v", tcase.sql, err, tcase.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseStreamComment(%q): %v", tcase.sql, err)
+			continue
+		}
+		if table != tcase.table || !reflect.DeepEqual(pks, tcase.pks) || nextid != tcase.nextid {
+			t.Errorf("parseStreamComment(%q): %v %v %v, want %v %v %v", tcase.sql, table, pks, nextid, tcase.table, tcase.pks, tcase.nextid)
+		}
+	}
+}
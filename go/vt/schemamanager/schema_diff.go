@@ -0,0 +1,540 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemamanager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+// This file computes the DDLs that change the schema of a shard into a
+// desired schema, described by the CREATE TABLE statements of all its
+// tables. The desired statements are best written in the form returned by
+// SHOW CREATE TABLE, with named indexes, as the comparison is done on the
+// parsed definitions and only a few of the MySQL defaults are normalized.
+//
+// The generated statements are minimal in the sense that tables are only
+// created, dropped or altered when needed, and an ALTER TABLE only changes
+// the columns, indexes and options that differ. Renames cannot be detected:
+// a renamed column or table is dropped and added again.
+
+// defaultIntWidths are the display widths MySQL uses for the integer
+// types when none is specified, indexed by type and then by signedness.
+var defaultIntWidths = map[string][2]string{
+	"tinyint":   {"4", "3"},
+	"smallint":  {"6", "5"},
+	"mediumint": {"9", "8"},
+	"int":       {"11", "10"},
+	"bigint":    {"20", "20"},
+}
+
+// ReadSchemaDir reads the desired schema from the .sql files of dir. Each
+// file contains one or more CREATE TABLE statements. It returns the
+// statements indexed by table name.
+func ReadSchemaDir(dir string) (map[string]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	tables := make(map[string]string)
+	tableFiles := make(map[string]string)
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".sql") {
+			continue
+		}
+		data, err := ioutil.ReadFile(path.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		pieces, err := sqlparser.SplitStatementToPieces(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("cannot split %v: %v", f.Name(), err)
+		}
+		for _, piece := range pieces {
+			sql := strings.TrimSpace(piece)
+			if sql == "" {
+				continue
+			}
+			ddl, err := parseCreateTable(sql)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %v", f.Name(), err)
+			}
+			name := ddl.NewName.Name.String()
+			if other, ok := tableFiles[name]; ok {
+				return nil, fmt.Errorf("table %v is defined in both %v and %v", name, other, f.Name())
+			}
+			tables[name] = sql
+			tableFiles[name] = f.Name()
+		}
+	}
+	return tables, nil
+}
+
+// parseCreateTable parses a CREATE TABLE statement, and checks the
+// definition of the table could be parsed entirely.
+func parseCreateTable(sql string) (*sqlparser.DDL, error) {
+	stmt, err := sqlparser.Parse(sql)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %q: %v", sql, err)
+	}
+	ddl, ok := stmt.(*sqlparser.DDL)
+	if !ok || ddl.Action != sqlparser.CreateStr {
+		return nil, fmt.Errorf("expected a CREATE TABLE statement, got: %q", sql)
+	}
+	if ddl.TableSpec == nil {
+		return nil, fmt.Errorf("unsupported table definition, use the form returned by SHOW CREATE TABLE: %q", sql)
+	}
+	if !ddl.NewName.Qualifier.IsEmpty() {
+		return nil, fmt.Errorf("qualified table names are not supported: %q", sql)
+	}
+	return ddl, nil
+}
+
+// DiffSchemaToDDLs returns the statements that change the current
+// schema of a shard into the desired schema, as returned by
+// ReadSchemaDir. Views are ignored. The tables are created first,
+// then altered, then dropped, each in alphabetical order.
+func DiffSchemaToDDLs(current *tabletmanagerdatapb.SchemaDefinition, desired map[string]string) ([]string, error) {
+	currentTables := make(map[string]*tabletmanagerdatapb.TableDefinition)
+	for _, td := range current.TableDefinitions {
+		if td.Type == tmutils.TableView {
+			continue
+		}
+		currentTables[td.Name] = td
+	}
+
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var creates, alters, drops []string
+	for _, name := range names {
+		td, ok := currentTables[name]
+		if !ok {
+			creates = append(creates, desired[name])
+			continue
+		}
+		from, err := parseCreateTable(td.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("table %v: %v", name, err)
+		}
+		to, err := parseCreateTable(desired[name])
+		if err != nil {
+			return nil, fmt.Errorf("table %v: %v", name, err)
+		}
+		alter, err := diffTable(name, from.TableSpec, to.TableSpec)
+		if err != nil {
+			return nil, err
+		}
+		if alter != "" {
+			alters = append(alters, alter)
+		}
+	}
+
+	for _, td := range current.TableDefinitions {
+		if _, ok := currentTables[td.Name]; !ok {
+			continue
+		}
+		if _, ok := desired[td.Name]; !ok {
+			buf := sqlparser.NewTrackedBuffer(nil)
+			buf.Myprintf("drop table %v", sqlparser.NewTableIdent(td.Name))
+			drops = append(drops, buf.String())
+		}
+	}
+	sort.Strings(drops)
+
+	return append(append(creates, alters...), drops...), nil
+}
+
+// diffTable returns the ALTER TABLE statement that changes the table
+// from one definition to the other, or "" if they are equivalent. The
+// indexes are dropped before the columns, and added after them.
+func diffTable(name string, from, to *sqlparser.TableSpec) (string, error) {
+	var specs []string
+	spec := func(format string, values ...interface{}) {
+		buf := sqlparser.NewTrackedBuffer(nil)
+		buf.Myprintf(format, values...)
+		specs = append(specs, buf.String())
+	}
+
+	fromNames := indexNames(from)
+	fromIndexes := make(map[string]*sqlparser.IndexDefinition)
+	for _, idx := range from.Indexes {
+		fromIndexes[fromNames[idx]] = idx
+	}
+	toNames := indexNames(to)
+	toIndexes := make(map[string]*sqlparser.IndexDefinition)
+	for _, idx := range to.Indexes {
+		toIndexes[toNames[idx]] = idx
+	}
+	for _, idx := range from.Indexes {
+		name := fromNames[idx]
+		if other, ok := toIndexes[name]; ok && normalizeIndex(idx, name) == normalizeIndex(other, name) {
+			continue
+		}
+		if idx.Info.Primary {
+			spec("drop primary key")
+		} else {
+			spec("drop index %v", sqlparser.NewColIdent(name))
+		}
+	}
+
+	fromPK := primaryKeyColumns(from)
+	toPK := primaryKeyColumns(to)
+	fromColumns := make(map[string]*sqlparser.ColumnDefinition)
+	for _, col := range from.Columns {
+		fromColumns[col.Name.Lowered()] = col
+	}
+	toColumns := make(map[string]*sqlparser.ColumnDefinition)
+	for _, col := range to.Columns {
+		toColumns[col.Name.Lowered()] = col
+	}
+	for _, col := range from.Columns {
+		if _, ok := toColumns[col.Name.Lowered()]; !ok {
+			spec("drop column %v", col.Name)
+		}
+	}
+	for i, col := range to.Columns {
+		if _, ok := fromColumns[col.Name.Lowered()]; ok {
+			continue
+		}
+		if i == 0 {
+			spec("add column %v first", col)
+		} else {
+			spec("add column %v after %v", col, to.Columns[i-1].Name)
+		}
+	}
+	for _, col := range to.Columns {
+		other, ok := fromColumns[col.Name.Lowered()]
+		if !ok {
+			continue
+		}
+		if normalizeColumn(other, fromPK[other.Name.Lowered()]) != normalizeColumn(col, toPK[col.Name.Lowered()]) {
+			spec("modify column %v", col)
+		}
+	}
+
+	for _, idx := range to.Indexes {
+		name := toNames[idx]
+		if other, ok := fromIndexes[name]; ok && normalizeIndex(idx, name) == normalizeIndex(other, name) {
+			continue
+		}
+		if idx.Info.Name.IsEmpty() {
+			// Add it with the name MySQL would generate, so the
+			// next diff finds it.
+			named := *idx.Info
+			named.Name = sqlparser.NewColIdent(name)
+			idx = &sqlparser.IndexDefinition{Info: &named, Columns: idx.Columns, Options: idx.Options}
+		}
+		spec("add %v", idx)
+	}
+
+	options, err := diffOptions(from.Options, to.Options)
+	if err != nil {
+		return "", fmt.Errorf("table %v: %v", name, err)
+	}
+	specs = append(specs, options...)
+
+	if len(specs) == 0 {
+		return "", nil
+	}
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("alter table %v ", sqlparser.NewTableIdent(name))
+	return buf.String() + strings.Join(specs, ", "), nil
+}
+
+// indexNames returns the lowered names of the indexes of the table. An
+// index without a name gets the one MySQL generates for it: the name of
+// its first column, suffixed with _2, _3... if that name is taken.
+func indexNames(ts *sqlparser.TableSpec) map[*sqlparser.IndexDefinition]string {
+	names := make(map[*sqlparser.IndexDefinition]string, len(ts.Indexes))
+	taken := make(map[string]bool)
+	for _, idx := range ts.Indexes {
+		if !idx.Info.Name.IsEmpty() {
+			names[idx] = idx.Info.Name.Lowered()
+			taken[names[idx]] = true
+		}
+	}
+	for _, idx := range ts.Indexes {
+		if !idx.Info.Name.IsEmpty() || len(idx.Columns) == 0 {
+			continue
+		}
+		base := idx.Columns[0].Column.Lowered()
+		name := base
+		for i := 2; taken[name]; i++ {
+			name = fmt.Sprintf("%v_%v", base, i)
+		}
+		names[idx] = name
+		taken[name] = true
+	}
+	return names
+}
+
+// primaryKeyColumns returns the set of the lowered names of the
+// primary key columns.
+func primaryKeyColumns(ts *sqlparser.TableSpec) map[string]bool {
+	pk := make(map[string]bool)
+	for _, idx := range ts.Indexes {
+		if !idx.Info.Primary {
+			continue
+		}
+		for _, col := range idx.Columns {
+			pk[col.Column.Lowered()] = true
+		}
+	}
+	return pk
+}
+
+// normalizeColumn returns the definition of a column with the defaults
+// MySQL applies filled in, so that equivalent definitions are equal.
+func normalizeColumn(col *sqlparser.ColumnDefinition, primary bool) string {
+	ct := col.Type
+	ct.Type = strings.ToLower(ct.Type)
+	switch ct.Type {
+	case "integer":
+		ct.Type = "int"
+	case "bool", "boolean":
+		ct.Type = "tinyint"
+		ct.Length = sqlparser.NewIntVal([]byte("1"))
+	}
+	if ct.Zerofill {
+		ct.Unsigned = true
+	}
+	if widths, ok := defaultIntWidths[ct.Type]; ok && ct.Length == nil {
+		width := widths[0]
+		if ct.Unsigned {
+			width = widths[1]
+		}
+		ct.Length = sqlparser.NewIntVal([]byte(width))
+	}
+	if primary {
+		ct.NotNull = true
+	}
+	if ct.Default != nil {
+		if ct.Default.Type == sqlparser.ValArg {
+			ct.Default = sqlparser.NewValArg([]byte(strings.ToLower(string(ct.Default.Val))))
+			if !ct.NotNull && string(ct.Default.Val) == "null" {
+				ct.Default = nil
+			}
+		} else {
+			// SHOW CREATE TABLE quotes all the default values.
+			ct.Default = sqlparser.NewStrVal(ct.Default.Val)
+		}
+	}
+	if ct.OnUpdate != nil {
+		ct.OnUpdate = sqlparser.NewValArg([]byte(strings.ToLower(string(ct.OnUpdate.Val))))
+	}
+	ct.Charset = strings.ToLower(ct.Charset)
+	ct.Collate = strings.ToLower(ct.Collate)
+	return sqlparser.String(&sqlparser.ColumnDefinition{
+		Name: sqlparser.NewColIdent(col.Name.Lowered()),
+		Type: ct,
+	})
+}
+
+// normalizeIndex returns the definition of an index without its options
+// and with the given name, so that equivalent definitions are equal.
+func normalizeIndex(idx *sqlparser.IndexDefinition, name string) string {
+	typ := strings.ToLower(idx.Info.Type)
+	switch {
+	case idx.Info.Primary:
+		typ = "primary key"
+	case strings.Contains(typ, "fulltext"):
+		typ = "fulltext key"
+	case idx.Info.Spatial || strings.Contains(typ, "spatial"):
+		typ = "spatial key"
+	case idx.Info.Unique:
+		typ = "unique key"
+	default:
+		typ = "key"
+	}
+	norm := &sqlparser.IndexDefinition{
+		Info: &sqlparser.IndexInfo{
+			Type: typ,
+			Name: sqlparser.NewColIdent(name),
+		},
+	}
+	for _, col := range idx.Columns {
+		norm.Columns = append(norm.Columns, &sqlparser.IndexColumn{
+			Column: sqlparser.NewColIdent(col.Column.Lowered()),
+			Length: col.Length,
+		})
+	}
+	return sqlparser.String(norm)
+}
+
+// tableOption is an option of a CREATE TABLE statement.
+type tableOption struct {
+	// name is the lowered name of the option, with the synonyms of
+	// the charset option replaced by "charset" and without "default".
+	name  string
+	value string
+}
+
+// equal returns true if both options have the same value. Only the
+// strings are case sensitive.
+func (o tableOption) equal(other tableOption) bool {
+	if strings.HasPrefix(o.value, "'") {
+		return o.value == other.value
+	}
+	return strings.EqualFold(o.value, other.value)
+}
+
+// optionResets are the values that reset the table options to their
+// default, used when an option is removed from the definition. ENGINE,
+// DEFAULT CHARSET and COLLATE are handled by diffOptions.
+var optionResets = map[string]string{
+	"avg_row_length":     "avg_row_length=0",
+	"checksum":           "checksum=0",
+	"comment":            "comment=''",
+	"compression":        "compression='None'",
+	"delay_key_write":    "delay_key_write=0",
+	"key_block_size":     "key_block_size=0",
+	"max_rows":           "max_rows=0",
+	"min_rows":           "min_rows=0",
+	"pack_keys":          "pack_keys=default",
+	"row_format":         "row_format=default",
+	"stats_auto_recalc":  "stats_auto_recalc=default",
+	"stats_persistent":   "stats_persistent=default",
+	"stats_sample_pages": "stats_sample_pages=default",
+}
+
+// parseTableOptions splits the table options of a CREATE TABLE statement,
+// as returned by the parser, into name and value. AUTO_INCREMENT is not
+// returned, since it is the next value of the counter and not part of
+// the schema.
+func parseTableOptions(options string) ([]tableOption, error) {
+	var result []tableOption
+	var words []string
+	rest := strings.TrimSpace(strings.Replace(options, ", ", " ", -1))
+	for rest != "" {
+		var word string
+		if i := strings.IndexAny(rest, " ="); i == -1 {
+			word, rest = rest, ""
+		} else if rest[i] == ' ' {
+			word, rest = rest[:i], strings.TrimSpace(rest[i+1:])
+		} else {
+			// The value of the option follows the '='. Strings are
+			// quoted by the parser and may contain spaces.
+			words = append(words, rest[:i])
+			rest = rest[i+1:]
+			var value string
+			if strings.HasPrefix(rest, "'") {
+				end := strings.Index(rest[1:], "'")
+				if end == -1 {
+					return nil, fmt.Errorf("unterminated string in table options: %v", options)
+				}
+				value, rest = rest[:end+2], rest[end+2:]
+			} else if end := strings.IndexByte(rest, ' '); end == -1 {
+				value, rest = rest, ""
+			} else {
+				value, rest = rest[:end], rest[end:]
+			}
+			rest = strings.TrimSpace(rest)
+
+			name := strings.ToLower(strings.Join(words, " "))
+			words = nil
+			name = strings.TrimPrefix(name, "default ")
+			if name == "character set" {
+				name = "charset"
+			}
+			if name != "auto_increment" {
+				result = append(result, tableOption{name: name, value: value})
+			}
+			continue
+		}
+		words = append(words, word)
+	}
+	if len(words) != 0 {
+		return nil, fmt.Errorf("table option without a value: %v", strings.Join(words, " "))
+	}
+	return result, nil
+}
+
+// diffOptions returns the ALTER TABLE specifications that change the table
+// options from one definition to the other. The options removed from the
+// definition are reset to their default value.
+func diffOptions(from, to string) ([]string, error) {
+	fromOptions, err := parseTableOptions(from)
+	if err != nil {
+		return nil, err
+	}
+	toOptions, err := parseTableOptions(to)
+	if err != nil {
+		return nil, err
+	}
+	fromValues := make(map[string]tableOption)
+	for _, option := range fromOptions {
+		fromValues[option.name] = option
+	}
+	toValues := make(map[string]tableOption)
+	for _, option := range toOptions {
+		toValues[option.name] = option
+	}
+
+	var specs []string
+	for _, option := range fromOptions {
+		if _, ok := toValues[option.name]; ok {
+			continue
+		}
+		switch option.name {
+		case "engine", "charset":
+			// SHOW CREATE TABLE always returns them, so leaving them
+			// out of the desired definition keeps the current value.
+			continue
+		case "collate":
+			// The collation goes back to the default of the charset,
+			// unless the charset is changed anyway.
+			if charset, ok := toValues["charset"]; ok && !charset.equal(fromValues["charset"]) {
+				continue
+			}
+			charset, ok := fromValues["charset"]
+			if !ok {
+				return nil, fmt.Errorf("cannot reset the table collation without a charset, set it explicitly")
+			}
+			specs = append(specs, fmt.Sprintf("default charset=%v", charset.value))
+			continue
+		}
+		reset, ok := optionResets[option.name]
+		if !ok {
+			return nil, fmt.Errorf("cannot reset the table option %v, set it explicitly", option.name)
+		}
+		specs = append(specs, reset)
+	}
+	for _, option := range toOptions {
+		if other, ok := fromValues[option.name]; ok && other.equal(option) {
+			continue
+		}
+		switch option.name {
+		case "charset", "collate":
+			specs = append(specs, fmt.Sprintf("default %v=%v", option.name, option.value))
+		default:
+			specs = append(specs, fmt.Sprintf("%v=%v", option.name, option.value))
+		}
+	}
+	return specs, nil
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemamanager
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+const currentT1 = "CREATE TABLE `t1` (\n" +
+	"  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,\n" +
+	"  `name` varchar(64) DEFAULT NULL,\n" +
+	"  `count` int(11) NOT NULL DEFAULT '0',\n" +
+	"  `old` varchar(10) DEFAULT NULL,\n" +
+	"  PRIMARY KEY (`id`),\n" +
+	"  KEY `name_idx` (`name`(10)) USING BTREE\n" +
+	") ENGINE=InnoDB AUTO_INCREMENT=12 DEFAULT CHARSET=utf8"
+
+func TestDiffSchemaToDDLs(t *testing.T) {
+	current := &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{Name: "t1", Schema: currentT1, Type: tmutils.TableBaseTable},
+			{Name: "t2", Schema: "CREATE TABLE `t2` (\n  `id` int(11) NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB", Type: tmutils.TableBaseTable},
+			{Name: "v1", Schema: "CREATE VIEW `v1` AS SELECT 1", Type: tmutils.TableView},
+		},
+	}
+
+	testcases := []struct {
+		desc    string
+		desired map[string]string
+		want    []string
+	}{{
+		desc: "same schema written differently",
+		desired: map[string]string{
+			"t1": "create table t1 (id bigint unsigned auto_increment, name varchar(64), count int not null default 0, old varchar(10) default null, primary key (id), index name_idx (name(10))) engine=innodb default charset=utf8",
+			"t2": "create table t2 (id integer, primary key (id))",
+		},
+	}, {
+		desc: "table changes",
+		desired: map[string]string{
+			"t1": "create table t1 (id bigint unsigned auto_increment, name varchar(128), created timestamp not null default current_timestamp, count int not null default 0, primary key (id), unique key name_idx (name)) engine=InnoDB default charset=utf8",
+			"t3": "create table t3 (id int, primary key (id))",
+		},
+		want: []string{
+			"create table t3 (id int, primary key (id))",
+			"alter table t1 drop index name_idx, drop column old, add column created timestamp not null default current_timestamp after name, modify column name varchar(128), add unique key name_idx (name)",
+			"drop table t2",
+		},
+	}, {
+		desc: "primary key and options",
+		desired: map[string]string{
+			"t1": currentT1,
+			"t2": "create table t2 (pk int not null, id int not null, primary key (id, pk)) engine=MyISAM",
+		},
+		want: []string{
+			"alter table t2 drop primary key, add column pk int not null first, add primary key (id, pk), engine=MyISAM",
+		},
+	}, {
+		desc: "removed options",
+		desired: map[string]string{
+			"t1": "create table t1 (id bigint unsigned auto_increment, name varchar(64), count int not null default 0, old varchar(10) default null, primary key (id), index name_idx (name(10))) comment='the t1 table'",
+			"t2": "create table t2 (id int, primary key (id)) engine=InnoDB row_format=compressed key_block_size=8",
+		},
+		want: []string{
+			"alter table t1 comment='the t1 table'",
+			"alter table t2 row_format=compressed, key_block_size=8",
+		},
+	}}
+	for _, tc := range testcases {
+		got, err := DiffSchemaToDDLs(current, tc.desired)
+		if err != nil {
+			t.Errorf("%v: DiffSchemaToDDLs failed: %v", tc.desc, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%v: DiffSchemaToDDLs:\n%q, want\n%q", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestDiffTableOptions(t *testing.T) {
+	testcases := []struct {
+		from, to string
+		want     string
+		err      string
+	}{{
+		from: "engine=InnoDB default charset=utf8 row_format=COMPRESSED key_block_size=8 comment='t'",
+		to:   "engine=innodb charset=utf8",
+		want: "alter table t row_format=default, key_block_size=0, comment=''",
+	}, {
+		from: "engine=InnoDB default charset=utf8 comment='t'",
+		to:   "engine=InnoDB default charset=utf8 comment='T'",
+		want: "alter table t comment='T'",
+	}, {
+		from: "engine=InnoDB default charset=utf8 collate=utf8_bin",
+		to:   "engine=InnoDB",
+		want: "alter table t default charset=utf8",
+	}, {
+		from: "engine=InnoDB default charset=utf8 collate=utf8_bin",
+		to:   "engine=InnoDB default character set=latin1",
+		want: "alter table t default charset=latin1",
+	}, {
+		from: "engine=InnoDB auto_increment=12 default charset=utf8",
+		to:   "",
+	}, {
+		from: "engine=InnoDB union=(t1)",
+		to:   "engine=InnoDB",
+		err:  "cannot reset the table option union",
+	}}
+	for _, tc := range testcases {
+		from := &sqlparser.TableSpec{Options: tc.from}
+		to := &sqlparser.TableSpec{Options: tc.to}
+		got, err := diffTable("t", from, to)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("diffTable(%q, %q): %v, want %v", tc.from, tc.to, err, tc.err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("diffTable(%q, %q): %q, %v, want %q", tc.from, tc.to, got, err, tc.want)
+		}
+	}
+}
+
+func TestDiffTableUnnamedIndexes(t *testing.T) {
+	// The parser requires index names, so the names are removed from
+	// the parsed definition.
+	parse := func(sql string, unnamed ...int) *sqlparser.TableSpec {
+		ddl, err := parseCreateTable(sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, i := range unnamed {
+			ddl.TableSpec.Indexes[i].Info.Name = sqlparser.NewColIdent("")
+		}
+		return ddl.TableSpec
+	}
+	from := parse("create table t (a int, b int, key a (a), key a_2 (a, b), key b (b))")
+
+	testcases := []struct {
+		to   *sqlparser.TableSpec
+		want string
+	}{{
+		to: parse("create table t (a int, b int, key x (a), key x (a, b), key x (b))", 0, 1, 2),
+	}, {
+		to:   parse("create table t (a int, b int, key x (b, a), key x (a))", 0, 1),
+		want: "alter table t drop index a_2, drop index b, add key b (b, a)",
+	}}
+	for _, tc := range testcases {
+		got, err := diffTable("t", from, tc.to)
+		if err != nil || got != tc.want {
+			t.Errorf("diffTable(%v): %q, %v, want %q", sqlparser.String(tc.to), got, err, tc.want)
+		}
+	}
+}
+
+func TestReadSchemaDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "schema_diff_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"t1.sql":    "create table t1 (id int, primary key (id));\ncreate table t2 (id int, primary key (id));\n",
+		"README.md": "not a schema file",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(path.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := ReadSchemaDir(dir)
+	if err != nil {
+		t.Fatalf("ReadSchemaDir failed: %v", err)
+	}
+	want := map[string]string{
+		"t1": "create table t1 (id int, primary key (id))",
+		"t2": "create table t2 (id int, primary key (id))",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadSchemaDir: %v, want %v", got, want)
+	}
+
+	for _, content := range []string{
+		"create table t1 (id int, primary key (id))",
+		"alter table t1 add column name varchar(64)",
+		"create table t3 (id int, key (id))",
+	} {
+		if err := ioutil.WriteFile(path.Join(dir, "bad.sql"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadSchemaDir(dir); err == nil || !strings.Contains(err.Error(), "bad.sql") {
+			t.Errorf("ReadSchemaDir(%q): %v, want error on bad.sql", content, err)
+		}
+	}
+}
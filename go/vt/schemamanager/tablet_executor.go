@@ -40,6 +40,7 @@ type TabletExecutor struct {
 	isClosed             bool
 	allowBigSchemaChange bool
 	keyspace             string
+	shards               []string
 	waitSlaveTimeout     time.Duration
 }
 
@@ -65,15 +66,25 @@ func (exec *TabletExecutor) DisallowBigSchemaChange() {
 	exec.allowBigSchemaChange = false
 }
 
+// SetShards restricts the schema changes to the given shards of the
+// keyspace. By default, they are applied to all the shards.
+func (exec *TabletExecutor) SetShards(shards []string) {
+	exec.shards = shards
+}
+
 // Open opens a connection to the master for every shard.
 func (exec *TabletExecutor) Open(ctx context.Context, keyspace string) error {
 	if !exec.isClosed {
 		return nil
 	}
 	exec.keyspace = keyspace
-	shardNames, err := exec.wr.TopoServer().GetShardNames(ctx, keyspace)
-	if err != nil {
-		return fmt.Errorf("unable to get shard names for keyspace: %s, error: %v", keyspace, err)
+	shardNames := exec.shards
+	if len(shardNames) == 0 {
+		var err error
+		shardNames, err = exec.wr.TopoServer().GetShardNames(ctx, keyspace)
+		if err != nil {
+			return fmt.Errorf("unable to get shard names for keyspace: %s, error: %v", keyspace, err)
+		}
 	}
 	exec.tablets = make([]*topodatapb.Tablet, len(shardNames))
 	for i, shardName := range shardNames {
@@ -135,6 +135,12 @@ func createGRPCServer() {
 		opts = append(opts, grpc.KeepaliveParams(ka))
 	}
 
+	// gRPC accepts a single interceptor of each type, so they are
+	// chained. The trace interceptors go first, so the server span
+	// covers the whole call.
+	streamInterceptors := []grpc.StreamServerInterceptor{traceStreamInterceptor}
+	unaryInterceptors := []grpc.UnaryServerInterceptor{traceUnaryInterceptor}
+
 	if *GRPCAuth != "" {
 		log.Infof("enabling auth plugin %v", *GRPCAuth)
 		pluginInitializer := GetAuthenticator(*GRPCAuth)
@@ -143,15 +149,18 @@ func createGRPCServer() {
 			log.Fatalf("Failed to load auth plugin: %v", err)
 		}
 		authPlugin = authPluginImpl
-		opts = append(opts, grpc.StreamInterceptor(streamInterceptor))
-		opts = append(opts, grpc.UnaryInterceptor(unaryInterceptor))
+		streamInterceptors = append(streamInterceptors, streamInterceptor)
+		unaryInterceptors = append(unaryInterceptors, unaryInterceptor)
 	}
 
 	if *grpccommon.EnableGRPCPrometheus {
-		opts = append(opts, grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor))
-		opts = append(opts, grpc.UnaryInterceptor(grpc_prometheus.UnaryServerInterceptor))
+		streamInterceptors = append(streamInterceptors, grpc_prometheus.StreamServerInterceptor)
+		unaryInterceptors = append(unaryInterceptors, grpc_prometheus.UnaryServerInterceptor)
 	}
 
+	opts = append(opts, grpc.StreamInterceptor(chainStreamInterceptors(streamInterceptors)))
+	opts = append(opts, grpc.UnaryInterceptor(chainUnaryInterceptors(unaryInterceptors)))
+
 	GRPCServer = grpc.NewServer(opts...)
 }
 
@@ -221,6 +230,36 @@ func unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServ
 	return handler(newCtx, req)
 }
 
+// chainStreamInterceptors returns a stream interceptor calling the
+// given ones in order.
+func chainStreamInterceptors(interceptors []grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(srv interface{}, stream grpc.ServerStream) error {
+				return interceptor(srv, stream, info, next)
+			}
+		}
+		return chained(srv, stream)
+	}
+}
+
+// chainUnaryInterceptors returns a unary interceptor calling the
+// given ones in order.
+func chainUnaryInterceptors(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return chained(ctx, req)
+	}
+}
+
 // WrappedServerStream is based on the service stream wrapper from: https://github.com/grpc-ecosystem/go-grpc-middleware
 type WrappedServerStream struct {
 	grpc.ServerStream
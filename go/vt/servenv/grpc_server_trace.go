@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servenv
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"vitess.io/vitess/go/trace"
+)
+
+// traceContext returns a context continuing the trace of the client,
+// if its span context was sent in the gRPC metadata.
+func traceContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	values := md[trace.SpanContextHeader]
+	if len(values) == 0 {
+		return ctx
+	}
+	return trace.WithSpanContext(ctx, values[0])
+}
+
+// traceUnaryInterceptor records a server span for each unary call.
+func traceUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx = traceContext(ctx)
+	span := trace.NewSpanFromContext(ctx)
+	span.StartServer(info.FullMethod)
+	defer span.Finish()
+	return handler(trace.NewContext(ctx, span), req)
+}
+
+// traceStreamInterceptor records a server span for each streaming call.
+func traceStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := traceContext(stream.Context())
+	span := trace.NewSpanFromContext(ctx)
+	span.StartServer(info.FullMethod)
+	defer span.Finish()
+
+	wrapped := WrapServerStream(stream)
+	wrapped.WrappedContext = trace.NewContext(ctx, span)
+	return handler(srv, wrapped)
+}
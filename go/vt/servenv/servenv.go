@@ -32,6 +32,7 @@ import (
 	"flag"
 	"net/url"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"sync"
@@ -44,6 +45,7 @@ import (
 	"vitess.io/vitess/go/event"
 	"vitess.io/vitess/go/netutil"
 	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/log"
 
 	// register the proper init and shutdown hooks for logging
@@ -101,6 +103,11 @@ func Init() {
 	fdl := stats.NewGauge("MaxFds", "File descriptor limit")
 	fdl.Set(int64(fdLimit.Cur))
 
+	// Install the tracer selected by the -tracer flag. Its pending
+	// spans are flushed when the process exits.
+	tracingCloser := trace.StartTracing(filepath.Base(os.Args[0]))
+	OnClose(func() { tracingCloser.Close() })
+
 	onInitHooks.Fire()
 }
 
@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlannotation
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// The span context is passed through the MySQL protocol in a leading
+// comment of the query: /*VT_SPAN_CONTEXT=<span context>*/.
+const (
+	spanContextCommentPrefix = "/*VT_SPAN_CONTEXT="
+	spanContextCommentSuffix = "*/"
+)
+
+// SpanContextComment returns a comment carrying the span context of
+// ctx, to add to a query sent over the MySQL protocol. It returns "" if
+// there is no span context to propagate, or if its trace is not
+// recorded: the comment is only useful to match the query with a
+// recorded trace.
+func SpanContextComment(ctx context.Context) string {
+	value := trace.SampledSpanContext(ctx)
+	if value == "" {
+		return ""
+	}
+	return spanContextCommentPrefix + value + spanContextCommentSuffix
+}
+
+// ExtractSpanContext looks for a comment carrying a span context among
+// the leading comments of query, as added by SpanContextComment. It
+// returns a context based on ctx that continues that trace, and the
+// query without the comment, the rest being unchanged. ctx and query
+// are returned unchanged if query carries no valid span context.
+func ExtractSpanContext(ctx context.Context, query string) (context.Context, string) {
+	_, comments := sqlparser.SplitMarginComments(query)
+	pos := len(query) - len(strings.TrimLeftFunc(query, unicode.IsSpace))
+	leadingEnd := pos + len(comments.Leading)
+	for {
+		// The leading comments are /* */ comments separated by spaces.
+		offset := strings.IndexFunc(query[pos:leadingEnd], isNonSpace)
+		if offset == -1 {
+			break
+		}
+		pos += offset
+		end := pos + 2 + strings.Index(query[pos+2:leadingEnd], spanContextCommentSuffix) + len(spanContextCommentSuffix)
+		if strings.HasPrefix(query[pos:end], spanContextCommentPrefix) {
+			spanCtx, ok := trace.ContinueSpanContext(ctx, strings.TrimSpace(query[pos+len(spanContextCommentPrefix):end-len(spanContextCommentSuffix)]))
+			if !ok {
+				return ctx, query
+			}
+			return spanCtx, query[:pos] + query[end:]
+		}
+		pos = end
+	}
+	return ctx, query
+}
+
+func isNonSpace(r rune) bool {
+	return !unicode.IsSpace(r)
+}
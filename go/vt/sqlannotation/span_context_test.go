@@ -0,0 +1,117 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlannotation
+
+import (
+	"sync"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/trace"
+)
+
+// memoryExporter keeps the exported spans in memory.
+type memoryExporter struct {
+	mu    sync.Mutex
+	spans []*trace.SpanData
+}
+
+func (me *memoryExporter) ExportSpan(data *trace.SpanData) {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	me.spans = append(me.spans, data)
+}
+
+func (me *memoryExporter) Close() error { return nil }
+
+func TestSpanContextComment(t *testing.T) {
+	exporter := &memoryExporter{}
+	trace.RegisterSpanFactory(trace.NewTracer("test", exporter, 1))
+	defer trace.RegisterSpanFactory(nil)
+
+	span := trace.NewSpanFromContext(context.Background())
+	span.StartClient("client")
+	ctx := trace.NewContext(context.Background(), span)
+
+	comment := SpanContextComment(ctx)
+	if want := "/*VT_SPAN_CONTEXT=" + trace.SpanContext(ctx) + "*/"; comment != want {
+		t.Errorf("SpanContextComment: got %q, want %q", comment, want)
+	}
+	commentCtx, query := ExtractSpanContext(context.Background(), comment+" select 1 from t")
+	if query != " select 1 from t" {
+		t.Errorf("ExtractSpanContext: got query %q, want %q", query, " select 1 from t")
+	}
+	// The comment can follow other leading comments.
+	commentCtx2, query := ExtractSpanContext(context.Background(), "/* app */ "+comment+"\nselect 1 from t")
+	if query != "/* app */ \nselect 1 from t" {
+		t.Errorf("ExtractSpanContext: got query %q, want %q", query, "/* app */ \nselect 1 from t")
+	}
+	for _, remoteCtx := range []context.Context{commentCtx, commentCtx2} {
+		server := trace.NewSpanFromContext(remoteCtx)
+		server.StartServer("server")
+		server.Finish()
+	}
+	span.Finish()
+
+	if len(exporter.spans) != 3 {
+		t.Fatalf("got %v spans, want 3", len(exporter.spans))
+	}
+	client := exporter.spans[2]
+	for _, server := range exporter.spans[:2] {
+		if server.TraceID != client.TraceID || server.ParentID != client.SpanID {
+			t.Errorf("server %+v does not continue client %+v", server, client)
+		}
+	}
+
+	// Only a valid leading comment is extracted: the comment is left
+	// in the query otherwise.
+	for _, query := range []string{
+		"select 1 from t",
+		"/*VT_SPAN_CONTEXT=1:2:0:1 select 1",
+		"/*VT_SPAN_CONTEXT=junk*/ select 1",
+		"select 1 /*VT_SPAN_CONTEXT=1:2:0:1*/",
+		"select '/*VT_SPAN_CONTEXT=1:2:0:1*/' from t",
+	} {
+		ctx, got := ExtractSpanContext(context.Background(), query)
+		if _, ok := trace.FromContext(ctx); ok {
+			t.Errorf("unexpected span in context for %q", query)
+		}
+		if got != query {
+			t.Errorf("ExtractSpanContext: got query %q, want %q", got, query)
+		}
+	}
+}
+
+func TestSpanContextCommentSampling(t *testing.T) {
+	trace.RegisterSpanFactory(trace.NewTracer("test", &memoryExporter{}, 0))
+	defer trace.RegisterSpanFactory(nil)
+
+	span := trace.NewSpanFromContext(context.Background())
+	span.StartClient("client")
+	defer span.Finish()
+
+	// MySQL doesn't need a comment for a trace that isn't recorded.
+	if got := SpanContextComment(trace.NewContext(context.Background(), span)); got != "" {
+		t.Errorf("SpanContextComment: got %v, want empty", got)
+	}
+	// Nor when there is no trace at all.
+	trace.RegisterSpanFactory(nil)
+	if got := SpanContextComment(context.Background()); got != "" {
+		t.Errorf("SpanContextComment: got %v, want empty", got)
+	}
+}
@@ -19,6 +19,8 @@ limitations under the License.
 // comments and parsing them. These annotations
 // are used during filtered-replication to route
 // the DML statement to the correct shard.
+// It also passes the span context of a trace
+// through the MySQL protocol in query comments.
 // TOOD(erez): Move the code for the "_stream" annotations
 // from vttablet to here.
 package sqlannotation
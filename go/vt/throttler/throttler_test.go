@@ -21,6 +21,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"golang.org/x/net/context"
 )
 
 // The main purpose of the benchmarks below is to demonstrate the functionality
@@ -344,6 +346,25 @@ func TestThrottle_MaxRateIsZero(t *testing.T) {
 	}
 }
 
+func TestWait(t *testing.T) {
+	fc := &fakeClock{}
+	// 1 Thread, 2 QPS.
+	throttler, _ := newThrottlerWithClock("test", "queries", 1, 2, ReplicationLagModuleDisabled, fc.now)
+	defer throttler.Close()
+
+	fc.setNow(1000 * time.Millisecond)
+	if err := throttler.Wait(context.Background(), 0); err != nil {
+		t.Fatalf("Wait should not have blocked: %v", err)
+	}
+
+	// The next request is throttled until ctx is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := throttler.Wait(ctx, 0); err != context.DeadlineExceeded {
+		t.Fatalf("Wait should have returned when ctx expired: %v", err)
+	}
+}
+
 func TestThrottle_MaxRateDisabled(t *testing.T) {
 	fc := &fakeClock{}
 	throttler, _ := newThrottlerWithClock("test", "queries", 1, MaxRateModuleDisabled, ReplicationLagModuleDisabled, fc.now)
@@ -0,0 +1,682 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/syslog"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/cache"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+// This file contains the audit log of the topology changes. When it is
+// enabled, every file created, updated or deleted through a Server is
+// recorded, with the caller and the difference between the old and the
+// new contents. The entries are stored in the global topology, under
+// AuditPath, in one directory per day. They are never updated, only
+// created. Each process can also export the entries it records to a
+// file or to syslog. The entries are written asynchronously: a change
+// never waits for its entry, and never fails because of it. They can be
+// deleted by day with PruneAuditLog.
+
+var (
+	auditLog       = flag.Bool("topo_audit_log", false, "record all the changes made to the topology by this process in the audit log, in the global topology")
+	auditLogFile   = flag.String("topo_audit_log_file", "", "also append the audit log entries of this process to this file, as JSON lines")
+	auditLogSyslog = flag.Bool("topo_audit_log_syslog", false, "also send the audit log entries of this process to syslog")
+)
+
+const (
+	// AuditPath is the path of the audit log in the global cell.
+	AuditPath = "audit"
+
+	// auditDayFormat is the format of the day directories.
+	auditDayFormat = "20060102"
+)
+
+// Operations of the audit log entries.
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// AuditEntry is a change to a topology file.
+type AuditEntry struct {
+	// Time is when the change was made.
+	Time time.Time `json:"time"`
+	// Cell and Path are the changed file.
+	Cell string `json:"cell"`
+	Path string `json:"path"`
+	// Operation is AuditCreate, AuditUpdate or AuditDelete.
+	Operation string `json:"operation"`
+	// Function is the Server method that made the change, if any,
+	// e.g. UpdateShardFields.
+	Function string `json:"function,omitempty"`
+	// OldVersion and NewVersion are the versions of the file
+	// before and after the change.
+	OldVersion string `json:"old_version,omitempty"`
+	NewVersion string `json:"new_version,omitempty"`
+	// Diff is the difference between the old and new contents,
+	// decoded as text for the proto files.
+	Diff string `json:"diff,omitempty"`
+	// Principal, Component and Subcomponent are the effective
+	// caller, and Username the immediate caller, from the context.
+	Principal    string `json:"principal,omitempty"`
+	Component    string `json:"component,omitempty"`
+	Subcomponent string `json:"subcomponent,omitempty"`
+	Username     string `json:"username,omitempty"`
+	// Command is the vtctl command that made the change, if any.
+	Command string `json:"command,omitempty"`
+	// Hostname and Process identify the process that made the
+	// change.
+	Hostname string `json:"hostname"`
+	Process  string `json:"process"`
+}
+
+// auditCommandKey is the context key for the vtctl command.
+type auditCommandKey struct{}
+
+// NewAuditContext returns a context that records command as the vtctl
+// command in the audit log entries.
+func NewAuditContext(ctx context.Context, command string) context.Context {
+	return context.WithValue(ctx, auditCommandKey{}, command)
+}
+
+// auditCommandFromContext returns the vtctl command of a context.
+func auditCommandFromContext(ctx context.Context) string {
+	command, _ := ctx.Value(auditCommandKey{}).(string)
+	return command
+}
+
+// auditRecorder records the audit log entries of a Server. The entries
+// are written by a goroutine, so the changes don't wait for them, and
+// are not failed when they can't be recorded.
+type auditRecorder struct {
+	// global is the connection to the global cell, not audited.
+	global   Conn
+	hostname string
+	process  string
+
+	// contents has the last known contents of the files read or
+	// written through the audited connections, to compute the
+	// diffs without reading the files again.
+	contents *cache.LRUCache
+
+	// mu protects closed, and the sends to queue.
+	mu     sync.Mutex
+	closed bool
+	queue  chan *auditRecord
+	done   chan struct{}
+
+	// The rest is only used by the writer goroutine.
+	seq    uint64
+	file   *os.File
+	syslog *syslog.Writer
+}
+
+// auditRecord is an entry waiting to be written, with the contents to
+// compute its diff.
+type auditRecord struct {
+	entry       *AuditEntry
+	oldContents []byte
+	newContents []byte
+}
+
+// auditContents are the contents of a file at a version.
+type auditContents struct {
+	version  Version
+	contents []byte
+}
+
+// Size is part of the cache.Value interface. Empty files count too.
+func (ac *auditContents) Size() int {
+	return len(ac.contents) + 1
+}
+
+const (
+	// auditQueueSize is the number of entries waiting to be
+	// written, above which the new entries are dropped.
+	auditQueueSize = 1000
+
+	// auditContentsCacheSize is the size of the cached contents.
+	auditContentsCacheSize = 16 * 1024 * 1024
+
+	// auditWriteTimeout is the timeout to write an entry in the
+	// global topology.
+	auditWriteTimeout = 30 * time.Second
+)
+
+var (
+	auditDropped = stats.NewCounter("TopoAuditLogDropped", "Number of topo audit log entries dropped because too many were waiting to be written")
+	auditFailed  = stats.NewCounter("TopoAuditLogFailed", "Number of topo audit log entries that could not be written to the global topology")
+
+	auditDroppedLog = logutil.NewThrottledLogger("TopoAuditLogDropped", 5*time.Second)
+)
+
+// newAuditRecorder returns the audit recorder of a Server, if the
+// audit log is enabled, and starts its writer.
+func newAuditRecorder(global Conn) *auditRecorder {
+	if !*auditLog {
+		return nil
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	ar := &auditRecorder{
+		global:   global,
+		hostname: hostname,
+		process:  filepath.Base(os.Args[0]),
+		contents: cache.NewLRUCache(auditContentsCacheSize),
+		queue:    make(chan *auditRecord, auditQueueSize),
+		done:     make(chan struct{}),
+	}
+	if *auditLogFile != "" {
+		ar.file, err = os.OpenFile(*auditLogFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			log.Errorf("cannot open topo audit log file %v: %v", *auditLogFile, err)
+		}
+	}
+	if *auditLogSyslog {
+		ar.syslog, err = syslog.New(syslog.LOG_INFO|syslog.LOG_USER, "vitess-topo-audit")
+		if err != nil {
+			log.Errorf("cannot connect to syslog for the topo audit log: %v", err)
+		}
+	}
+	go ar.writeLoop()
+	return ar
+}
+
+// close writes the waiting entries, and closes the exporters. It must
+// be called before the global connection is closed.
+func (ar *auditRecorder) close() {
+	ar.mu.Lock()
+	if ar.closed {
+		ar.mu.Unlock()
+		return
+	}
+	ar.closed = true
+	close(ar.queue)
+	ar.mu.Unlock()
+	<-ar.done
+
+	if ar.file != nil {
+		ar.file.Close()
+		ar.file = nil
+	}
+	if ar.syslog != nil {
+		ar.syslog.Close()
+		ar.syslog = nil
+	}
+}
+
+// record queues a change to be recorded. The caller and the function
+// are found now, the rest is done by the writer.
+func (ar *auditRecorder) record(ctx context.Context, cell, filePath, operation string, oldVersion Version, oldContents []byte, newVersion Version, newContents []byte) {
+	entry := &AuditEntry{
+		Time:      time.Now().UTC(),
+		Cell:      cell,
+		Path:      filePath,
+		Operation: operation,
+		Function:  auditFunction(),
+		Command:   auditCommandFromContext(ctx),
+		Hostname:  ar.hostname,
+		Process:   ar.process,
+	}
+	if oldVersion != nil {
+		entry.OldVersion = oldVersion.String()
+	}
+	if newVersion != nil {
+		entry.NewVersion = newVersion.String()
+	}
+	if ef := callerid.EffectiveCallerIDFromContext(ctx); ef != nil {
+		entry.Principal = callerid.GetPrincipal(ef)
+		entry.Component = callerid.GetComponent(ef)
+		entry.Subcomponent = callerid.GetSubcomponent(ef)
+	}
+	if im := callerid.ImmediateCallerIDFromContext(ctx); im != nil {
+		entry.Username = callerid.GetUsername(im)
+	}
+
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+	if ar.closed {
+		return
+	}
+	select {
+	case ar.queue <- &auditRecord{entry: entry, oldContents: oldContents, newContents: newContents}:
+	default:
+		auditDropped.Add(1)
+		auditDroppedLog.Warningf("too many topo audit log entries waiting, dropped change of %v:%v", cell, filePath)
+	}
+}
+
+// writeLoop writes the queued entries, until the recorder is closed.
+func (ar *auditRecorder) writeLoop() {
+	defer close(ar.done)
+	for r := range ar.queue {
+		ar.write(r)
+	}
+}
+
+// write exports an entry, and records it in the global topology.
+// Errors are only logged: the change is made already.
+func (ar *auditRecorder) write(r *auditRecord) {
+	entry := r.entry
+	entry.Diff = auditDiff(entry.Path, r.oldContents, r.newContents)
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Errorf("cannot encode topo audit log entry: %v", err)
+		return
+	}
+
+	// The entries are named after their time, so they are
+	// listed in order. The rest makes the name unique.
+	ar.seq++
+	name := fmt.Sprintf("%020d-%v-%v-%v", entry.Time.UnixNano(), ar.hostname, os.Getpid(), ar.seq)
+	if ar.file != nil {
+		if _, err := ar.file.Write(append(data, '\n')); err != nil {
+			log.Errorf("cannot write to topo audit log file: %v", err)
+		}
+	}
+	if ar.syslog != nil {
+		if err := ar.syslog.Info(string(data)); err != nil {
+			log.Errorf("cannot send topo audit log entry to syslog: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), auditWriteTimeout)
+	defer cancel()
+	entryPath := path.Join(AuditPath, entry.Time.Format(auditDayFormat), name)
+	if _, err := ar.global.Create(ctx, entryPath, data); err != nil {
+		auditFailed.Add(1)
+		log.Errorf("cannot record change of %v:%v in the topo audit log: %v", entry.Cell, entry.Path, err)
+	}
+}
+
+// cached returns the last known contents of a file, if its version is
+// the given one. With no version given, they may be out of date.
+func (ar *auditRecorder) cached(cell, filePath string, version Version) (Version, []byte) {
+	v, ok := ar.contents.Get(cell + ":" + filePath)
+	if !ok {
+		return nil, nil
+	}
+	ac := v.(*auditContents)
+	if version != nil && version.String() != ac.version.String() {
+		return nil, nil
+	}
+	return ac.version, ac.contents
+}
+
+// setCached remembers the contents of a file, or forgets them if
+// contents is nil.
+func (ar *auditRecorder) setCached(cell, filePath string, version Version, contents []byte) {
+	key := cell + ":" + filePath
+	if contents == nil {
+		ar.contents.Delete(key)
+		return
+	}
+	ar.contents.Set(key, &auditContents{version: version, contents: contents})
+}
+
+// auditFunction returns the outermost Server method in the stack, e.g.
+// UpdateShardFields when it calls UpdateShard.
+func auditFunction() string {
+	const prefix = "vitess.io/vitess/go/vt/topo.(*Server)."
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	result := ""
+	for {
+		frame, more := frames.Next()
+		if strings.HasPrefix(frame.Function, prefix) {
+			result = frame.Function[len(prefix):]
+			// Remove the closure suffixes.
+			if i := strings.Index(result, "."); i >= 0 {
+				result = result[:i]
+			}
+		}
+		if !more {
+			return result
+		}
+	}
+}
+
+// auditText returns the contents of a file as text: decoded for the
+// proto files, as is for text files.
+func auditText(filePath string, data []byte) string {
+	if data == nil {
+		return ""
+	}
+	var p proto.Message
+	switch path.Base(filePath) {
+	case CellInfoFile:
+		p = new(topodatapb.CellInfo)
+	case KeyspaceFile:
+		p = new(topodatapb.Keyspace)
+	case ShardFile:
+		p = new(topodatapb.Shard)
+	case VSchemaFile:
+		p = new(vschemapb.Keyspace)
+	case ShardReplicationFile:
+		p = new(topodatapb.ShardReplication)
+	case TabletFile:
+		p = new(topodatapb.Tablet)
+	case SrvVSchemaFile:
+		p = new(vschemapb.SrvVSchema)
+	case SrvKeyspaceFile:
+		p = new(topodatapb.SrvKeyspace)
+	}
+	if p != nil && proto.Unmarshal(data, p) == nil {
+		return proto.MarshalTextString(p)
+	}
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	return fmt.Sprintf("<%v bytes of binary data>\n", len(data))
+}
+
+// auditDiff returns the lines removed from the old contents, prefixed
+// with "-", and added to the new contents, prefixed with "+".
+func auditDiff(filePath string, oldData, newData []byte) string {
+	oldLines := auditLines(auditText(filePath, oldData))
+	newLines := auditLines(auditText(filePath, newData))
+
+	// Longest common subsequence of the lines. The files are
+	// small, this is good enough.
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			switch {
+			case oldLines[i] == newLines[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var b strings.Builder
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			i++
+			j++
+		case j == len(newLines) || (i < len(oldLines) && lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&b, "-%v\n", oldLines[i])
+			i++
+		default:
+			fmt.Fprintf(&b, "+%v\n", newLines[j])
+			j++
+		}
+	}
+	return b.String()
+}
+
+// auditLines splits a text into lines.
+func auditLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// auditConn is a Conn that records the changes in the audit log.
+type auditConn struct {
+	Conn
+	cell     string
+	recorder *auditRecorder
+}
+
+// Get is part of the Conn interface. It remembers the contents, for
+// the diff of the next change.
+func (c *auditConn) Get(ctx context.Context, filePath string) ([]byte, Version, error) {
+	contents, version, err := c.Conn.Get(ctx, filePath)
+	switch err {
+	case nil:
+		c.recorder.setCached(c.cell, filePath, version, contents)
+	case ErrNoNode:
+		c.recorder.setCached(c.cell, filePath, nil, nil)
+	}
+	return contents, version, err
+}
+
+// Create is part of the Conn interface.
+func (c *auditConn) Create(ctx context.Context, filePath string, contents []byte) (Version, error) {
+	version, err := c.Conn.Create(ctx, filePath, contents)
+	if err == nil {
+		c.recorder.setCached(c.cell, filePath, version, contents)
+		c.recorder.record(ctx, c.cell, filePath, AuditCreate, nil, nil, version, contents)
+	}
+	return version, err
+}
+
+// Update is part of the Conn interface.
+// The old contents are the last ones this process read or wrote, when
+// they are still at the given version. With no version given, they may
+// be out of date. When they are unknown, the entry has no old version,
+// and its diff has all the new contents.
+func (c *auditConn) Update(ctx context.Context, filePath string, contents []byte, version Version) (Version, error) {
+	oldVersion, oldContents := c.recorder.cached(c.cell, filePath, version)
+	newVersion, err := c.Conn.Update(ctx, filePath, contents, version)
+	if err == nil {
+		c.recorder.setCached(c.cell, filePath, newVersion, contents)
+		c.recorder.record(ctx, c.cell, filePath, AuditUpdate, oldVersion, oldContents, newVersion, contents)
+	}
+	return newVersion, err
+}
+
+// Delete is part of the Conn interface.
+// The old contents are found as in Update.
+func (c *auditConn) Delete(ctx context.Context, filePath string, version Version) error {
+	oldVersion, oldContents := c.recorder.cached(c.cell, filePath, version)
+	err := c.Conn.Delete(ctx, filePath, version)
+	if err == nil {
+		c.recorder.setCached(c.cell, filePath, nil, nil)
+		c.recorder.record(ctx, c.cell, filePath, AuditDelete, oldVersion, oldContents, nil, nil)
+	}
+	return err
+}
+
+// auditConnForCell wraps a Conn to record its changes, if the audit log
+// is enabled.
+func (ts *Server) auditConnForCell(cell string, conn Conn) Conn {
+	if ts.audit == nil {
+		return conn
+	}
+	return &auditConn{
+		Conn:     conn,
+		cell:     cell,
+		recorder: ts.audit,
+	}
+}
+
+// AuditFilter selects entries of the audit log.
+type AuditFilter struct {
+	// Since and Until limit the time of the entries. Zero values
+	// are not limits.
+	Since time.Time
+	Until time.Time
+	// Cell is the cell of the changed files. Empty for all.
+	Cell string
+	// Path is the changed file, or a directory containing it.
+	// Empty for all.
+	Path string
+	// Caller is the principal or username of the caller. Empty
+	// for all.
+	Caller string
+	// Limit is the maximum number of entries, the most recent
+	// ones are returned. 0 for no limit.
+	Limit int
+}
+
+// match returns true if an entry is selected by the filter.
+func (f *AuditFilter) match(e *AuditEntry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Time.After(f.Until) {
+		return false
+	}
+	if f.Cell != "" && e.Cell != f.Cell {
+		return false
+	}
+	if f.Path != "" {
+		p := strings.Trim(f.Path, "/")
+		ep := strings.Trim(e.Path, "/")
+		if ep != p && !strings.HasPrefix(ep, p+"/") {
+			return false
+		}
+	}
+	if f.Caller != "" && e.Principal != f.Caller && e.Username != f.Caller {
+		return false
+	}
+	return true
+}
+
+// GetAuditLog returns the entries of the audit log selected by the
+// filter, oldest first.
+func (ts *Server) GetAuditLog(ctx context.Context, filter AuditFilter) ([]*AuditEntry, error) {
+	days, err := ts.globalCell.ListDir(ctx, AuditPath, false /*full*/)
+	switch err {
+	case nil:
+	case ErrNoNode:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	var result []*AuditEntry
+	// Start with the most recent days, for the limit.
+	for i := len(days) - 1; i >= 0; i-- {
+		day, err := time.Parse(auditDayFormat, days[i].Name)
+		if err != nil {
+			continue
+		}
+		if !filter.Since.IsZero() && day.Add(24*time.Hour).Before(filter.Since) {
+			break
+		}
+		if !filter.Until.IsZero() && day.After(filter.Until) {
+			continue
+		}
+
+		dayPath := path.Join(AuditPath, days[i].Name)
+		entries, err := ts.globalCell.ListDir(ctx, dayPath, false /*full*/)
+		if err != nil && err != ErrNoNode {
+			return nil, err
+		}
+		for j := len(entries) - 1; j >= 0; j-- {
+			data, _, err := ts.globalCell.Get(ctx, path.Join(dayPath, entries[j].Name))
+			if err != nil {
+				if err == ErrNoNode {
+					continue
+				}
+				return nil, err
+			}
+			e := &AuditEntry{}
+			if err := json.Unmarshal(data, e); err != nil {
+				log.Warningf("invalid topo audit log entry %v: %v", entries[j].Name, err)
+				continue
+			}
+			if !filter.match(e) {
+				continue
+			}
+			result = append(result, e)
+			if filter.Limit > 0 && len(result) == filter.Limit {
+				break
+			}
+		}
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			break
+		}
+	}
+
+	// We read them backwards.
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result, nil
+}
+
+// PruneAuditLog deletes the entries of the audit log recorded on the
+// days before the day of the given time, and returns how many were
+// deleted. The deletions are not audited.
+func (ts *Server) PruneAuditLog(ctx context.Context, before time.Time) (int, error) {
+	conn := ts.globalCell
+	if ac, ok := conn.(*auditConn); ok {
+		conn = ac.Conn
+	}
+	days, err := conn.ListDir(ctx, AuditPath, false /*full*/)
+	switch err {
+	case nil:
+	case ErrNoNode:
+		return 0, nil
+	default:
+		return 0, err
+	}
+
+	last := before.UTC().Format(auditDayFormat)
+	count := 0
+	for _, d := range days {
+		if _, err := time.Parse(auditDayFormat, d.Name); err != nil || d.Name >= last {
+			continue
+		}
+		dayPath := path.Join(AuditPath, d.Name)
+		entries, err := conn.ListDir(ctx, dayPath, false /*full*/)
+		if err != nil {
+			if err == ErrNoNode {
+				continue
+			}
+			return count, err
+		}
+		for _, e := range entries {
+			if err := conn.Delete(ctx, path.Join(dayPath, e.Name), nil); err != nil && err != ErrNoNode {
+				return count, fmt.Errorf("cannot delete audit log entry %v: %v", path.Join(dayPath, e.Name), err)
+			}
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctopo
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/vt/vttopo"
+
+	vttoposervicepb "vitess.io/vitess/go/vt/proto/vttoposervice"
+)
+
+var (
+	retryDelay = flag.Duration("topo_vttopo_retry_delay", 100*time.Millisecond, "Delay before trying all the vttopo servers again, when none of them is the leader.")
+)
+
+// client sends the requests to the vttopo leader. It remembers which
+// server answered last, and tries the next one when a server is not
+// the leader or is not reachable.
+type client struct {
+	conns   []*grpc.ClientConn
+	clients []vttoposervicepb.TopoClient
+
+	// mu protects current.
+	mu      sync.Mutex
+	current int
+}
+
+func newClient(serverAddr string) (*client, error) {
+	c := &client{}
+	for _, addr := range strings.Split(serverAddr, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		cc, err := vttopo.Dial(addr)
+		if err != nil {
+			c.close()
+			return nil, err
+		}
+		c.conns = append(c.conns, cc)
+		c.clients = append(c.clients, vttoposervicepb.NewTopoClient(cc))
+	}
+	if len(c.clients) == 0 {
+		return nil, fmt.Errorf("no vttopo server address in %q", serverAddr)
+	}
+	return c, nil
+}
+
+// pick returns the server we think is the leader, and its index.
+func (c *client) pick() (int, vttoposervicepb.TopoClient) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.current, c.clients[c.current]
+}
+
+// failed moves to the next server, after server i failed. It waits for
+// retryDelay after each round over all the servers.
+func (c *client) failed(ctx context.Context, i int) error {
+	c.mu.Lock()
+	if c.current == i {
+		c.current = (i + 1) % len(c.clients)
+	}
+	wait := c.current == 0
+	c.mu.Unlock()
+
+	if !wait {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(*retryDelay):
+		return nil
+	}
+}
+
+// call runs f with the leader, until it doesn't fail with Unavailable.
+func (c *client) call(ctx context.Context, f func(vttoposervicepb.TopoClient) error) error {
+	for {
+		i, cli := c.pick()
+		err := f(cli)
+		if status.Code(err) != codes.Unavailable {
+			return err
+		}
+		if err := c.failed(ctx, i); err != nil {
+			return err
+		}
+	}
+}
+
+// close closes all the connections.
+func (c *client) close() {
+	for _, cc := range c.conns {
+		cc.Close()
+	}
+}
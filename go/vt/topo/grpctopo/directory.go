@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctopo
+
+import (
+	"path"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+	vttoposervicepb "vitess.io/vitess/go/vt/proto/vttoposervice"
+)
+
+// ListDir is part of the topo.Conn interface.
+func (s *Server) ListDir(ctx context.Context, dirPath string, full bool) ([]topo.DirEntry, error) {
+	nodePath := path.Join(s.root, dirPath)
+
+	var resp *vttopopb.ListDirResponse
+	err := s.cli.call(ctx, func(c vttoposervicepb.TopoClient) error {
+		var err error
+		resp, err = c.ListDir(ctx, &vttopopb.ListDirRequest{
+			Path: nodePath,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	// The entries are sorted by the server already.
+	result := make([]topo.DirEntry, len(resp.Entries))
+	for i, e := range resp.Entries {
+		result[i].Name = e.Name
+		if !full {
+			continue
+		}
+		result[i].Type = topo.TypeFile
+		if e.Directory {
+			result[i].Type = topo.TypeDirectory
+		}
+		result[i].Ephemeral = e.Ephemeral
+	}
+	return result, nil
+}
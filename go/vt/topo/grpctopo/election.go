@@ -0,0 +1,125 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctopo
+
+import (
+	"path"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+	vttoposervicepb "vitess.io/vitess/go/vt/proto/vttoposervice"
+)
+
+// NewMasterParticipation is part of the topo.Server interface
+func (s *Server) NewMasterParticipation(name, id string) (topo.MasterParticipation, error) {
+	return &vttopoMasterParticipation{
+		s:    s,
+		name: name,
+		id:   id,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}, nil
+}
+
+// vttopoMasterParticipation implements topo.MasterParticipation.
+//
+// We use a lock in the global election path, with the name. The
+// contents of the lock file is the id of the master.
+type vttopoMasterParticipation struct {
+	// s is our parent vttopo topo Server
+	s *Server
+
+	// name is the name of this MasterParticipation
+	name string
+
+	// id is the process's current id.
+	id string
+
+	// stop is a channel closed when Stop is called.
+	stop chan struct{}
+
+	// done is a channel closed when we're done processing the Stop
+	done chan struct{}
+}
+
+// WaitForMastership is part of the topo.MasterParticipation interface.
+func (mp *vttopoMasterParticipation) WaitForMastership() (context.Context, error) {
+	// If Stop was already called, mp.done is closed, so we are interrupted.
+	select {
+	case <-mp.done:
+		return nil, topo.ErrInterrupted
+	default:
+	}
+
+	electionPath := path.Join(electionsPath, mp.name)
+	lockChan := make(chan *vttopoLockDescriptor, 1)
+
+	// We use a cancelable context here. If stop is closed,
+	// we just cancel that context.
+	lockCtx, lockCancel := context.WithCancel(context.Background())
+	go func() {
+		<-mp.stop
+		lockCancel()
+		if ld := <-lockChan; ld != nil {
+			if err := ld.Unlock(context.Background()); err != nil {
+				log.Errorf("failed to unlock electionPath %v: %v", electionPath, err)
+			}
+		}
+		close(mp.done)
+	}()
+
+	// Try to get the mastership, by getting a lock.
+	ld, err := mp.s.lock(lockCtx, electionPath, mp.id)
+	lockChan <- ld
+	if err != nil {
+		// It can be that we were interrupted.
+		return nil, err
+	}
+
+	// We got the lock. Return the lockContext. If Stop() is called,
+	// it will cancel the lockCtx, and cancel the returned context.
+	return lockCtx, nil
+}
+
+// Stop is part of the topo.MasterParticipation interface
+func (mp *vttopoMasterParticipation) Stop() {
+	close(mp.stop)
+	<-mp.done
+}
+
+// GetCurrentMasterID is part of the topo.MasterParticipation interface
+func (mp *vttopoMasterParticipation) GetCurrentMasterID(ctx context.Context) (string, error) {
+	electionPath := path.Join(mp.s.root, electionsPath, mp.name)
+
+	var resp *vttopopb.LockHolderResponse
+	err := mp.s.cli.call(ctx, func(c vttoposervicepb.TopoClient) error {
+		var err error
+		resp, err = c.LockHolder(ctx, &vttopopb.LockHolderRequest{
+			Path: electionPath,
+		})
+		return err
+	})
+	if err != nil {
+		return "", convertError(err)
+	}
+	// Nobody is the master if the lock is not held.
+	return resp.Contents, nil
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctopo
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// convertError converts a vttopo RPC error into a topo error.
+func convertError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch err {
+	case context.Canceled:
+		return topo.ErrInterrupted
+	case context.DeadlineExceeded:
+		return topo.ErrTimeout
+	}
+
+	switch status.Code(err) {
+	case codes.NotFound:
+		return topo.ErrNoNode
+	case codes.AlreadyExists:
+		return topo.ErrNodeExists
+	case codes.Aborted:
+		return topo.ErrBadVersion
+	case codes.Canceled:
+		return topo.ErrInterrupted
+	case codes.DeadlineExceeded:
+		return topo.ErrTimeout
+	}
+	return err
+}
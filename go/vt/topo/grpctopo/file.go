@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctopo
+
+import (
+	"path"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+	vttoposervicepb "vitess.io/vitess/go/vt/proto/vttoposervice"
+)
+
+// Create is part of the topo.Conn interface.
+func (s *Server) Create(ctx context.Context, filePath string, contents []byte) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	var resp *vttopopb.CreateResponse
+	err := s.cli.call(ctx, func(c vttoposervicepb.TopoClient) error {
+		var err error
+		resp, err = c.Create(ctx, &vttopopb.CreateRequest{
+			Path:     nodePath,
+			Contents: contents,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return VTTopoVersion(resp.Version), nil
+}
+
+// Update is part of the topo.Conn interface.
+func (s *Server) Update(ctx context.Context, filePath string, contents []byte, version topo.Version) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	// Without a version, the file is created if needed.
+	req := &vttopopb.UpdateRequest{
+		Path:     nodePath,
+		Contents: contents,
+	}
+	if version != nil {
+		req.Version = uint64(version.(VTTopoVersion))
+	}
+	var resp *vttopopb.UpdateResponse
+	err := s.cli.call(ctx, func(c vttoposervicepb.TopoClient) error {
+		var err error
+		resp, err = c.Update(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return VTTopoVersion(resp.Version), nil
+}
+
+// Get is part of the topo.Conn interface.
+func (s *Server) Get(ctx context.Context, filePath string) ([]byte, topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	var resp *vttopopb.GetResponse
+	err := s.cli.call(ctx, func(c vttoposervicepb.TopoClient) error {
+		var err error
+		resp, err = c.Get(ctx, &vttopopb.GetRequest{
+			Path: nodePath,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, nil, convertError(err)
+	}
+	return resp.Contents, VTTopoVersion(resp.Version), nil
+}
+
+// Delete is part of the topo.Conn interface.
+func (s *Server) Delete(ctx context.Context, filePath string, version topo.Version) error {
+	nodePath := path.Join(s.root, filePath)
+
+	req := &vttopopb.DeleteRequest{
+		Path: nodePath,
+	}
+	if version != nil {
+		req.Version = uint64(version.(VTTopoVersion))
+	}
+	return convertError(s.cli.call(ctx, func(c vttoposervicepb.TopoClient) error {
+		_, err := c.Delete(ctx, req)
+		return err
+	}))
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctopo
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+	vttoposervicepb "vitess.io/vitess/go/vt/proto/vttoposervice"
+)
+
+var (
+	leaseTTL = flag.Duration("topo_vttopo_lease_ttl", 30*time.Second, "Lease TTL for locks and master election. The holder keeps its lease alive every third of the TTL, and the vttopo leader releases the locks of a lease that was not kept alive for the whole TTL.")
+)
+
+// vttopoLockDescriptor implements topo.LockDescriptor.
+type vttopoLockDescriptor struct {
+	s *Server
+
+	// nodePath is the path of the locked directory.
+	nodePath string
+	// lease is the lease of the lock file.
+	lease uint64
+
+	// stop is closed to stop keeping the lease alive, and done is
+	// closed when the keep-alive goroutine has exited.
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// Lock is part of the topo.Conn interface.
+func (s *Server) Lock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	// We list the directory first to make sure it exists.
+	if _, err := s.ListDir(ctx, dirPath, false /*full*/); err != nil {
+		return nil, err
+	}
+
+	ld, err := s.lock(ctx, dirPath, contents)
+	if err != nil {
+		return nil, err
+	}
+	return ld, nil
+}
+
+// lock is used by both Lock() and master election.
+// It grants a lease, keeps it alive, and waits on the server for the
+// lock file of the lease to be the oldest one.
+func (s *Server) lock(ctx context.Context, dirPath, contents string) (*vttopoLockDescriptor, error) {
+	nodePath := path.Join(s.root, dirPath)
+
+	var grant *vttopopb.GrantResponse
+	err := s.cli.call(ctx, func(c vttoposervicepb.TopoClient) error {
+		var err error
+		grant, err = c.Grant(ctx, &vttopopb.GrantRequest{
+			Ttl: int64(*leaseTTL / time.Millisecond),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+	ld := &vttopoLockDescriptor{
+		s:        s,
+		nodePath: nodePath,
+		lease:    grant.Lease,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go ld.keepAlive()
+
+	err = s.cli.call(ctx, func(c vttoposervicepb.TopoClient) error {
+		_, err := c.Lock(ctx, &vttopopb.LockRequest{
+			Path:     nodePath,
+			Contents: contents,
+			Lease:    ld.lease,
+		})
+		return err
+	})
+	if err != nil {
+		// Release our place in the queue. Our context may be
+		// done already.
+		if uerr := ld.Unlock(context.Background()); uerr != nil {
+			log.Warningf("cannot revoke lease %v for %v: %v", ld.lease, nodePath, uerr)
+		}
+		return nil, convertError(err)
+	}
+	return ld, nil
+}
+
+// keepAlive keeps the lease alive every third of the lease TTL.
+func (ld *vttopoLockDescriptor) keepAlive() {
+	defer close(ld.done)
+
+	ticker := time.NewTicker(*leaseTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ld.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), *leaseTTL/3)
+		err := ld.s.cli.call(ctx, func(c vttoposervicepb.TopoClient) error {
+			_, err := c.KeepAlive(ctx, &vttopopb.KeepAliveRequest{
+				Lease: ld.lease,
+			})
+			return err
+		})
+		cancel()
+
+		switch err := convertError(err); err {
+		case nil:
+			// Kept alive.
+		case topo.ErrNoNode:
+			// Our lease expired, Check will report it.
+			log.Errorf("lost lock %v: lease %v expired", ld.nodePath, ld.lease)
+			return
+		default:
+			// We will try again on the next tick,
+			// before the lease expires.
+			log.Warningf("cannot keep lease %v alive for lock %v: %v", ld.lease, ld.nodePath, err)
+		}
+	}
+}
+
+// Check is part of the topo.LockDescriptor interface.
+// We make sure our lease is still there.
+func (ld *vttopoLockDescriptor) Check(ctx context.Context) error {
+	err := ld.s.cli.call(ctx, func(c vttoposervicepb.TopoClient) error {
+		_, err := c.KeepAlive(ctx, &vttopopb.KeepAliveRequest{
+			Lease: ld.lease,
+		})
+		return err
+	})
+	if err := convertError(err); err != nil {
+		if err == topo.ErrNoNode {
+			return fmt.Errorf("lock %v was lost: lease %v expired", ld.nodePath, ld.lease)
+		}
+		return err
+	}
+	return nil
+}
+
+// Unlock is part of the topo.LockDescriptor interface.
+// Revoking the lease deletes the lock file.
+func (ld *vttopoLockDescriptor) Unlock(ctx context.Context) error {
+	ld.stopOnce.Do(func() {
+		close(ld.stop)
+	})
+	<-ld.done
+
+	return convertError(ld.s.cli.call(ctx, func(c vttoposervicepb.TopoClient) error {
+		_, err := c.Revoke(ctx, &vttopopb.RevokeRequest{
+			Lease: ld.lease,
+		})
+		return err
+	}))
+}
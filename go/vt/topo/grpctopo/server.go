@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package grpctopo implements topo.Server with vttopo, the embedded topology
+server of go/vt/vttopo, as the backend.
+
+The server address is a comma-separated list of the addresses of all the
+vttopo servers of the cluster. The requests go to the leader: the client
+remembers it, and tries the other servers when it changes.
+
+We follow these conventions within this package:
+
+  - The version of a file is the index of its last change in the
+    replicated log.
+  - Locks and master election use leases, kept alive by the client while
+    the lock is held. If the process goes away, its lease expires and the
+    lock is released.
+  - Call convertError(err) on any errors returned from the vttopo client.
+    Functions defined in this package can be assumed to have already
+    converted errors as necessary.
+*/
+package grpctopo
+
+import (
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// Factory is the vttopo topo.Factory implementation.
+type Factory struct{}
+
+// HasGlobalReadOnlyCell is part of the topo.Factory interface.
+func (f Factory) HasGlobalReadOnlyCell(serverAddr, root string) bool {
+	return false
+}
+
+// Create is part of the topo.Factory interface.
+func (f Factory) Create(cell, serverAddr, root string) (topo.Conn, error) {
+	return NewServer(serverAddr, root)
+}
+
+// Server is the implementation of topo.Server for vttopo.
+type Server struct {
+	// cli is the client to the vttopo servers.
+	cli *client
+
+	// root is the root path for this client.
+	root string
+}
+
+// Close implements topo.Server.Close.
+// It will nil out the client, so any attempt to
+// re-use this server will panic.
+func (s *Server) Close() {
+	s.cli.close()
+	s.cli = nil
+}
+
+// NewServer returns a new grpctopo.Server.
+// serverAddr is the comma-separated list of the vttopo servers.
+func NewServer(serverAddr, root string) (*Server, error) {
+	cli, err := newClient(serverAddr)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		cli:  cli,
+		root: root,
+	}, nil
+}
+
+func init() {
+	topo.RegisterFactory("vttopo", Factory{})
+}
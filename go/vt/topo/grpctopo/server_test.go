@@ -0,0 +1,185 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctopo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/test"
+	"vitess.io/vitess/go/vt/vttopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// startVTTopo starts a vttopo cluster of three servers in the
+// background, and returns the server address to use, and the function
+// to stop it.
+func startVTTopo(t *testing.T) (string, func()) {
+	dataDir, err := ioutil.TempDir("", "vttopo")
+	if err != nil {
+		t.Fatalf("cannot create temp dir: %v", err)
+	}
+
+	peers := make(map[string]string)
+	listeners := make(map[string]net.Listener)
+	var addrs []string
+	for i := 0; i < 3; i++ {
+		id := fmt.Sprintf("s%v", i)
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("cannot listen: %v", err)
+		}
+		listeners[id] = listener
+		peers[id] = listener.Addr().String()
+		addrs = append(addrs, peers[id])
+	}
+
+	var stops []func()
+	for id, listener := range listeners {
+		s, err := vttopo.NewServer(vttopo.Config{
+			ID:                id,
+			Peers:             peers,
+			DataDir:           path.Join(dataDir, id),
+			HeartbeatInterval: 20 * time.Millisecond,
+			ElectionTimeout:   200 * time.Millisecond,
+			SnapshotThreshold: 1000,
+		})
+		if err != nil {
+			t.Fatalf("vttopo.NewServer(%v) failed: %v", id, err)
+		}
+		grpcServer := grpc.NewServer()
+		s.Register(grpcServer)
+		go grpcServer.Serve(listener)
+		stops = append(stops, func() {
+			grpcServer.Stop()
+			s.Close()
+		})
+	}
+
+	return strings.Join(addrs, ","), func() {
+		for _, stop := range stops {
+			stop()
+		}
+		os.RemoveAll(dataDir)
+	}
+}
+
+func TestVTTopo(t *testing.T) {
+	// Start a vttopo cluster in the background.
+	serverAddr, cleanup := startVTTopo(t)
+	defer cleanup()
+
+	// Short lease TTL, so abandoned locks are released quickly.
+	savedTTL := *leaseTTL
+	defer func() {
+		*leaseTTL = savedTTL
+	}()
+	*leaseTTL = 600 * time.Millisecond
+
+	testIndex := 0
+	newServer := func() *topo.Server {
+		// Each test will use its own sub-directories.
+		testRoot := fmt.Sprintf("/test-%v", testIndex)
+		testIndex++
+
+		// Create the server on the new root.
+		ts, err := topo.OpenServer("vttopo", serverAddr, path.Join(testRoot, topo.GlobalCell))
+		if err != nil {
+			t.Fatalf("OpenServer() failed: %v", err)
+		}
+
+		// Create the CellInfo.
+		if err := ts.CreateCellInfo(context.Background(), test.LocalCellName, &topodatapb.CellInfo{
+			ServerAddress: serverAddr,
+			Root:          path.Join(testRoot, test.LocalCellName),
+		}); err != nil {
+			t.Fatalf("CreateCellInfo() failed: %v", err)
+		}
+
+		return ts
+	}
+
+	// Run the TopoServerTestSuite tests.
+	test.TopoServerTestSuite(t, func() *topo.Server {
+		return newServer()
+	})
+
+	// Run vttopo-specific tests.
+	ts := newServer()
+	testAbandonedLock(t, ts)
+	ts.Close()
+}
+
+// testAbandonedLock tests that the lock of a process that went away is
+// released after the lease TTL.
+func testAbandonedLock(t *testing.T, ts *topo.Server) {
+	ctx := context.Background()
+	keyspacePath := path.Join(topo.KeyspacesPath, "test_keyspace")
+	if err := ts.CreateKeyspace(ctx, "test_keyspace", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace: %v", err)
+	}
+
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		t.Fatalf("ConnForCell failed: %v", err)
+	}
+
+	// The lease is kept alive while we hold the lock.
+	lockDescriptor, err := conn.Lock(ctx, keyspacePath, "held")
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	fastCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	if _, err := conn.Lock(fastCtx, keyspacePath, "again"); err != topo.ErrTimeout {
+		t.Fatalf("Lock(again): %v", err)
+	}
+	cancel()
+	if err := lockDescriptor.Check(ctx); err != nil {
+		t.Errorf("Check(): %v", err)
+	}
+
+	// Simulate a process that went away: stop keeping the lease
+	// alive without revoking it.
+	ld := lockDescriptor.(*vttopoLockDescriptor)
+	close(ld.stop)
+	<-ld.done
+	start := time.Now()
+	lockDescriptor2, err := conn.Lock(ctx, keyspacePath, "takeover")
+	if err != nil {
+		t.Fatalf("Lock(takeover) failed: %v", err)
+	}
+	if d := time.Since(start); d < *leaseTTL/2 {
+		t.Errorf("abandoned lock was released after %v, expected about %v", d, *leaseTTL)
+	}
+	if err := lockDescriptor.Check(ctx); err == nil {
+		t.Errorf("Check() of the abandoned lock succeeded")
+	}
+	if err := lockDescriptor2.Unlock(ctx); err != nil {
+		t.Errorf("Unlock failed: %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctopo
+
+import (
+	"fmt"
+)
+
+// VTTopoVersion is the version of a vttopo file: the index of its last
+// change in the replicated log.
+// It implements topo.Version.
+type VTTopoVersion uint64
+
+// String is part of the topo.Version interface.
+func (v VTTopoVersion) String() string {
+	return fmt.Sprintf("%v", uint64(v))
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctopo
+
+import (
+	"path"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+)
+
+// Watch is part of the topo.Conn interface.
+func (s *Server) Watch(ctx context.Context, filePath string) (*topo.WatchData, <-chan *topo.WatchData, topo.CancelFunc) {
+	nodePath := path.Join(s.root, filePath)
+
+	// Get the initial version of the file.
+	contents, version, err := s.Get(ctx, filePath)
+	if err != nil {
+		return &topo.WatchData{Err: err}, nil, nil
+	}
+	wd := &topo.WatchData{
+		Contents: contents,
+		Version:  version,
+	}
+
+	// Create a context, will be used to cancel the watch.
+	watchCtx, watchCancel := context.WithCancel(context.Background())
+
+	// Create the notifications channel, send updates to it.
+	notifications := make(chan *topo.WatchData, 10)
+	go func() {
+		defer close(notifications)
+
+		// The final notification is either the deletion, or
+		// the interruption.
+		err := s.watch(watchCtx, nodePath, uint64(version.(VTTopoVersion)), notifications)
+		notifications <- &topo.WatchData{
+			Err: convertError(err),
+		}
+	}()
+
+	return wd, notifications, topo.CancelFunc(watchCancel)
+}
+
+// watch sends the changes of a file after a version to notifications.
+// The server ends the stream when it stops being the leader, so we
+// start over from the last version we saw on the new leader. It
+// returns when the file is deleted, or the context is done.
+func (s *Server) watch(ctx context.Context, nodePath string, version uint64, notifications chan<- *topo.WatchData) error {
+	for {
+		i, c := s.cli.pick()
+		stream, err := c.Watch(ctx, &vttopopb.WatchRequest{
+			Path:    nodePath,
+			Version: version,
+		})
+		for err == nil {
+			var resp *vttopopb.WatchResponse
+			resp, err = stream.Recv()
+			if err != nil {
+				break
+			}
+			version = resp.Version
+			notifications <- &topo.WatchData{
+				Contents: resp.Contents,
+				Version:  VTTopoVersion(resp.Version),
+			}
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if status.Code(err) == codes.NotFound {
+			return err
+		}
+		if status.Code(err) != codes.Unavailable {
+			log.Warningf("watch on %v failed, will retry: %v", nodePath, err)
+		}
+		if err := s.cli.failed(ctx, i); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// This file contains the point-in-time snapshots of the topology data:
+// all the files of the global cell and of the local cells, with their
+// versions. Snapshots are written as gzip-compressed JSON, so they can
+// be read back with any topo implementation.
+//
+// Ephemeral files (locks and master elections) are not part of the
+// snapshots, they only make sense while their owner is running.
+
+// snapshotFormat is the format version of the snapshots we write.
+const snapshotFormat = 1
+
+// Snapshot is the content of the topology at a point in time.
+type Snapshot struct {
+	// Format is the version of the snapshot format.
+	Format int `json:"format"`
+	// Time is when the snapshot was taken.
+	Time time.Time `json:"time"`
+	// Files are all the files, sorted by cell and path.
+	Files []*SnapshotFile `json:"files"`
+}
+
+// SnapshotFile is a file in a Snapshot.
+type SnapshotFile struct {
+	// Cell is the cell the file is in, topo.GlobalCell for the
+	// global topology.
+	Cell string `json:"cell"`
+	// Path is the path of the file in the cell.
+	Path string `json:"path"`
+	// Version is the version of the file when the snapshot was
+	// taken. It is only informative: restoring a file creates a
+	// new version.
+	Version string `json:"version"`
+	// Contents are the raw contents of the file.
+	Contents []byte `json:"contents"`
+}
+
+// TakeSnapshot reads all the files of the global cell and of the given
+// cells. If cells is empty, all the cells are included. The global cell
+// is always included, so []string{topo.GlobalCell} only includes it.
+func TakeSnapshot(ctx context.Context, ts *topo.Server, cells []string) (*Snapshot, error) {
+	if len(cells) == 0 {
+		var err error
+		cells, err = ts.GetCellInfoNames(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("GetCellInfoNames failed: %v", err)
+		}
+	}
+
+	snapshot := &Snapshot{
+		Format: snapshotFormat,
+		Time:   time.Now().UTC(),
+	}
+	for i, cell := range append([]string{topo.GlobalCell}, cells...) {
+		if i > 0 && cell == topo.GlobalCell {
+			continue
+		}
+		conn, err := ts.ConnForCell(ctx, cell)
+		if err != nil {
+			return nil, fmt.Errorf("ConnForCell(%v) failed: %v", cell, err)
+		}
+		if err := snapshotDir(ctx, conn, cell, "/", snapshot); err != nil {
+			return nil, err
+		}
+	}
+	snapshot.sort()
+	return snapshot, nil
+}
+
+// snapshotDir adds the files of a directory, recursively.
+func snapshotDir(ctx context.Context, conn topo.Conn, cell, dirPath string, snapshot *Snapshot) error {
+	entries, err := conn.ListDir(ctx, dirPath, true /*full*/)
+	switch err {
+	case nil:
+	case topo.ErrNoNode:
+		// Empty cell, or directory deleted meanwhile.
+		return nil
+	default:
+		return fmt.Errorf("ListDir(%v, %v) failed: %v", cell, dirPath, err)
+	}
+
+	for _, e := range entries {
+		if e.Ephemeral {
+			continue
+		}
+		p := path.Join(dirPath, e.Name)
+		if e.Type == topo.TypeDirectory {
+			if err := snapshotDir(ctx, conn, cell, p, snapshot); err != nil {
+				return err
+			}
+			continue
+		}
+		contents, version, err := conn.Get(ctx, p)
+		switch err {
+		case nil:
+			snapshot.Files = append(snapshot.Files, &SnapshotFile{
+				Cell:     cell,
+				Path:     p,
+				Version:  version.String(),
+				Contents: contents,
+			})
+		case topo.ErrNoNode:
+			// Deleted meanwhile.
+		default:
+			return fmt.Errorf("Get(%v, %v) failed: %v", cell, p, err)
+		}
+	}
+	return nil
+}
+
+// sort sorts the files by cell and path, with the global cell first.
+func (s *Snapshot) sort() {
+	sort.Slice(s.Files, func(i, j int) bool {
+		return fileLess(s.Files[i], s.Files[j])
+	})
+}
+
+// fileLess orders files by cell and path, with the global cell first.
+func fileLess(a, b *SnapshotFile) bool {
+	if a.Cell != b.Cell {
+		if a.Cell == topo.GlobalCell || b.Cell == topo.GlobalCell {
+			return a.Cell == topo.GlobalCell
+		}
+		return a.Cell < b.Cell
+	}
+	return a.Path < b.Path
+}
+
+// Write writes the snapshot.
+func (s *Snapshot) Write(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	if err := json.NewEncoder(gz).Encode(s); err != nil {
+		return fmt.Errorf("cannot encode snapshot: %v", err)
+	}
+	return gz.Close()
+}
+
+// ReadSnapshot reads a snapshot written by Snapshot.Write.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a topology snapshot: %v", err)
+	}
+	defer gz.Close()
+	snapshot := &Snapshot{}
+	if err := json.NewDecoder(gz).Decode(snapshot); err != nil {
+		return nil, fmt.Errorf("cannot decode snapshot: %v", err)
+	}
+	if snapshot.Format != snapshotFormat {
+		return nil, fmt.Errorf("unsupported snapshot format %v", snapshot.Format)
+	}
+	snapshot.sort()
+	return snapshot, nil
+}
+
+// Kinds of SnapshotDiff.
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// SnapshotDiff is a difference between two snapshots, for one file.
+type SnapshotDiff struct {
+	// Kind is DiffAdded, DiffRemoved or DiffChanged.
+	Kind string
+	// From and To are the file in the two snapshots. From is nil
+	// for an added file, and To for a removed one.
+	From *SnapshotFile
+	To   *SnapshotFile
+}
+
+// String returns a one-line description of the difference.
+func (d *SnapshotDiff) String() string {
+	switch d.Kind {
+	case DiffAdded:
+		return fmt.Sprintf("added   %v:%v (version %v)", d.To.Cell, d.To.Path, d.To.Version)
+	case DiffRemoved:
+		return fmt.Sprintf("removed %v:%v (version %v)", d.From.Cell, d.From.Path, d.From.Version)
+	default:
+		return fmt.Sprintf("changed %v:%v (version %v -> %v)", d.From.Cell, d.From.Path, d.From.Version, d.To.Version)
+	}
+}
+
+// DiffSnapshots returns the files that are different between two
+// snapshots, sorted by cell and path. Only the contents are compared,
+// not the versions.
+func DiffSnapshots(from, to *Snapshot) []*SnapshotDiff {
+	var result []*SnapshotDiff
+	i, j := 0, 0
+	for i < len(from.Files) || j < len(to.Files) {
+		switch {
+		case j == len(to.Files) || (i < len(from.Files) && fileLess(from.Files[i], to.Files[j])):
+			result = append(result, &SnapshotDiff{Kind: DiffRemoved, From: from.Files[i]})
+			i++
+		case i == len(from.Files) || fileLess(to.Files[j], from.Files[i]):
+			result = append(result, &SnapshotDiff{Kind: DiffAdded, To: to.Files[j]})
+			j++
+		default:
+			if string(from.Files[i].Contents) != string(to.Files[j].Contents) {
+				result = append(result, &SnapshotDiff{Kind: DiffChanged, From: from.Files[i], To: to.Files[j]})
+			}
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// RestoreOptions select what RestoreSnapshot restores.
+type RestoreOptions struct {
+	// Cell is the cell to restore.
+	Cell string
+	// Paths are the paths to restore: each one is a file, or a
+	// directory to restore recursively. "/" restores the whole cell.
+	Paths []string
+	// Delete also deletes the files under Paths that are not in
+	// the snapshot.
+	Delete bool
+	// DryRun only returns what would be changed.
+	DryRun bool
+}
+
+// RestoreSnapshot restores the files selected by the options to their
+// contents in the snapshot. It only writes the files that are
+// different. It returns the changes, as differences from the current
+// topology to the snapshot.
+func RestoreSnapshot(ctx context.Context, ts *topo.Server, snapshot *Snapshot, options RestoreOptions) ([]*SnapshotDiff, error) {
+	if len(options.Paths) == 0 {
+		return nil, fmt.Errorf("no path to restore")
+	}
+	conn, err := ts.ConnForCell(ctx, options.Cell)
+	if err != nil {
+		return nil, fmt.Errorf("ConnForCell(%v) failed: %v", options.Cell, err)
+	}
+
+	// Compare the current files with the snapshot ones. Note the
+	// current snapshot is only taken on the selected cell.
+	current := &Snapshot{}
+	for _, p := range options.Paths {
+		if err := snapshotPath(ctx, conn, options.Cell, path.Clean("/"+p), current); err != nil {
+			return nil, err
+		}
+	}
+	current.sort()
+	wanted := &Snapshot{}
+	for _, f := range snapshot.Files {
+		if f.Cell == options.Cell && selected(f.Path, options.Paths) {
+			wanted.Files = append(wanted.Files, f)
+		}
+	}
+
+	diffs := DiffSnapshots(current, wanted)
+	var result []*SnapshotDiff
+	for _, d := range diffs {
+		if d.Kind == DiffRemoved && !options.Delete {
+			continue
+		}
+		result = append(result, d)
+		if options.DryRun {
+			continue
+		}
+
+		switch d.Kind {
+		case DiffRemoved:
+			if err := conn.Delete(ctx, d.From.Path, nil); err != nil && err != topo.ErrNoNode {
+				return result, fmt.Errorf("Delete(%v, %v) failed: %v", options.Cell, d.From.Path, err)
+			}
+		default:
+			if _, err := conn.Update(ctx, d.To.Path, d.To.Contents, nil); err != nil {
+				return result, fmt.Errorf("Update(%v, %v) failed: %v", options.Cell, d.To.Path, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// snapshotPath adds a file, or the files of a directory, to a snapshot.
+func snapshotPath(ctx context.Context, conn topo.Conn, cell, p string, snapshot *Snapshot) error {
+	// Some implementations can Get a directory, so we try to
+	// list it first.
+	if _, err := conn.ListDir(ctx, p, false /*full*/); err == nil {
+		return snapshotDir(ctx, conn, cell, p, snapshot)
+	}
+	contents, version, err := conn.Get(ctx, p)
+	switch err {
+	case nil:
+		snapshot.Files = append(snapshot.Files, &SnapshotFile{
+			Cell:     cell,
+			Path:     p,
+			Version:  version.String(),
+			Contents: contents,
+		})
+		return nil
+	case topo.ErrNoNode:
+		return nil
+	default:
+		return fmt.Errorf("Get(%v, %v) failed: %v", cell, p, err)
+	}
+}
+
+// selected returns true if a file is one of the paths, or under one of
+// them.
+func selected(filePath string, paths []string) bool {
+	for _, p := range paths {
+		p = path.Clean("/" + p)
+		if p == "/" || filePath == p || strings.HasPrefix(filePath, p+"/") {
+			return true
+		}
+	}
+	return false
+}
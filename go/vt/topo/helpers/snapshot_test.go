@@ -0,0 +1,181 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+// snapshotFiles returns the files of a snapshot as cell:path.
+func snapshotFiles(s *Snapshot) map[string]string {
+	result := make(map[string]string)
+	for _, f := range s.Files {
+		result[f.Cell+":"+f.Path] = string(f.Contents)
+	}
+	return result
+}
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1", "cell2")
+
+	if err := ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+	if err := ts.CreateShard(ctx, "ks", "0"); err != nil {
+		t.Fatalf("CreateShard failed: %v", err)
+	}
+	if err := ts.SaveVSchema(ctx, "ks", &vschemapb.Keyspace{Tables: map[string]*vschemapb.Table{"t1": {}}}); err != nil {
+		t.Fatalf("SaveVSchema failed: %v", err)
+	}
+	tablet := &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: 100},
+		Keyspace: "ks",
+		Shard:    "0",
+		Type:     topodatapb.TabletType_MASTER,
+	}
+	if err := ts.CreateTablet(ctx, tablet); err != nil {
+		t.Fatalf("CreateTablet failed: %v", err)
+	}
+	if err := ts.UpdateSrvKeyspace(ctx, "cell1", "ks", &topodatapb.SrvKeyspace{}); err != nil {
+		t.Fatalf("UpdateSrvKeyspace failed: %v", err)
+	}
+	if err := ts.UpdateSrvVSchema(ctx, "cell2", &vschemapb.SrvVSchema{}); err != nil {
+		t.Fatalf("UpdateSrvVSchema failed: %v", err)
+	}
+
+	// Take a snapshot, it has all the files.
+	snapshot, err := TakeSnapshot(ctx, ts, nil)
+	if err != nil {
+		t.Fatalf("TakeSnapshot failed: %v", err)
+	}
+	files := snapshotFiles(snapshot)
+	for _, f := range []string{
+		"global:/cells/cell1/CellInfo",
+		"global:/keyspaces/ks/Keyspace",
+		"global:/keyspaces/ks/VSchema",
+		"global:/keyspaces/ks/shards/0/Shard",
+		"cell1:/tablets/cell1-0000000100/Tablet",
+		"cell1:/keyspaces/ks/shards/0/ShardReplication",
+		"cell1:/keyspaces/ks/SrvKeyspace",
+		"cell2:/SrvVSchema",
+	} {
+		if _, ok := files[f]; !ok {
+			t.Errorf("snapshot doesn't have %v: %v", f, files)
+		}
+	}
+	if snapshot.Files[0].Cell != topo.GlobalCell {
+		t.Errorf("global cell is not first: %v", snapshot.Files[0])
+	}
+
+	// Write and read it back.
+	buf := &bytes.Buffer{}
+	if err := snapshot.Write(buf); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	read, err := ReadSnapshot(buf)
+	if err != nil {
+		t.Fatalf("ReadSnapshot failed: %v", err)
+	}
+	if diffs := DiffSnapshots(snapshot, read); len(diffs) != 0 {
+		t.Errorf("read snapshot is different: %v", diffs)
+	}
+
+	// Make a few mistakes.
+	if err := ts.SaveVSchema(ctx, "ks", &vschemapb.Keyspace{Tables: map[string]*vschemapb.Table{"t2": {}}}); err != nil {
+		t.Fatalf("SaveVSchema failed: %v", err)
+	}
+	if err := ts.DeleteTablet(ctx, tablet.Alias); err != nil {
+		t.Fatalf("DeleteTablet failed: %v", err)
+	}
+	if err := ts.CreateKeyspace(ctx, "ks2", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+	after, err := TakeSnapshot(ctx, ts, []string{"cell1"})
+	if err != nil {
+		t.Fatalf("TakeSnapshot failed: %v", err)
+	}
+	diffs := DiffSnapshots(snapshot, after)
+	got := make(map[string]string)
+	for _, d := range diffs {
+		f := d.From
+		if f == nil {
+			f = d.To
+		}
+		got[f.Cell+":"+f.Path] = d.Kind
+	}
+	want := map[string]string{
+		"global:/keyspaces/ks/VSchema":           DiffChanged,
+		"global:/keyspaces/ks2/Keyspace":         DiffAdded,
+		"cell1:/tablets/cell1-0000000100/Tablet": DiffRemoved,
+		// cell2 is not in the second snapshot.
+		"cell2:/SrvVSchema": DiffRemoved,
+	}
+	if len(got) != len(want) {
+		t.Errorf("DiffSnapshots returned %v, expected %v", got, want)
+	}
+	for f, kind := range want {
+		if got[f] != kind {
+			t.Errorf("DiffSnapshots for %v returned %v, expected %v", f, got[f], kind)
+		}
+	}
+
+	// A dry run doesn't change anything.
+	changes, err := RestoreSnapshot(ctx, ts, snapshot, RestoreOptions{
+		Cell:   topo.GlobalCell,
+		Paths:  []string{"keyspaces"},
+		Delete: true,
+		DryRun: true,
+	})
+	if err != nil || len(changes) != 2 {
+		t.Fatalf("RestoreSnapshot(dry run) returned %v %v", changes, err)
+	}
+	if _, err := ts.GetKeyspace(ctx, "ks2"); err != nil {
+		t.Errorf("dry run deleted ks2: %v", err)
+	}
+
+	// Restore the keyspaces, and the tablet.
+	if _, err := RestoreSnapshot(ctx, ts, snapshot, RestoreOptions{
+		Cell:   topo.GlobalCell,
+		Paths:  []string{"keyspaces"},
+		Delete: true,
+	}); err != nil {
+		t.Fatalf("RestoreSnapshot(global) failed: %v", err)
+	}
+	if _, err := RestoreSnapshot(ctx, ts, snapshot, RestoreOptions{
+		Cell:  "cell1",
+		Paths: []string{"/tablets/cell1-0000000100"},
+	}); err != nil {
+		t.Fatalf("RestoreSnapshot(cell1) failed: %v", err)
+	}
+	restored, err := TakeSnapshot(ctx, ts, nil)
+	if err != nil {
+		t.Fatalf("TakeSnapshot failed: %v", err)
+	}
+	if diffs := DiffSnapshots(snapshot, restored); len(diffs) != 0 {
+		t.Errorf("restored topology is different: %v", diffs)
+	}
+}
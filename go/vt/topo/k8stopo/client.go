@@ -0,0 +1,342 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	// apiVersion and kind of the VitessTopoNode custom resource.
+	apiVersion = "topo.vitess.io/v1beta1"
+	kind       = "VitessTopoNode"
+
+	// resource is the plural name of the VitessTopoNode resource.
+	resource = "vitesstoponodes"
+
+	// dirLabelPrefix is the prefix of the labels set on each node
+	// for each of its parent directories.
+	dirLabelPrefix = "dir.topo.vitess.io/"
+
+	// tokenRefreshInterval is how often the bearer token file is
+	// read again, as service account tokens are rotated.
+	tokenRefreshInterval = time.Minute
+)
+
+// topoNode is a VitessTopoNode object.
+type topoNode struct {
+	APIVersion string       `json:"apiVersion"`
+	Kind       string       `json:"kind"`
+	Metadata   objectMeta   `json:"metadata"`
+	Data       topoNodeData `json:"data"`
+}
+
+// objectMeta is the subset of the Kubernetes ObjectMeta we use.
+type objectMeta struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace,omitempty"`
+	ResourceVersion string            `json:"resourceVersion,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+}
+
+// topoNodeData is the contents of a VitessTopoNode.
+type topoNodeData struct {
+	// Key is the full path of the node.
+	Key string `json:"key"`
+
+	// Value is the file contents. It is base64-encoded in JSON.
+	Value []byte `json:"value,omitempty"`
+
+	// Ephemeral is set for locks and master elections.
+	Ephemeral bool `json:"ephemeral,omitempty"`
+
+	// RenewTime is updated by the holder of a lock, to show
+	// it is still alive.
+	RenewTime string `json:"renewTime,omitempty"`
+}
+
+// topoNodeList is the result of a list call.
+type topoNodeList struct {
+	Items []topoNode `json:"items"`
+}
+
+// watchEvent is one event of a watch stream.
+type watchEvent struct {
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// apiError is an error returned by the API server, as described
+// by a Kubernetes Status object.
+type apiError struct {
+	Code    int    `json:"code"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// Error is part of the error interface.
+func (e *apiError) Error() string {
+	return fmt.Sprintf("kubernetes API server error %v (%v): %v", e.Code, e.Reason, e.Message)
+}
+
+// nodeName returns the name of the object storing a path.
+// Object names are limited in length and characters, so we use a hash.
+func nodeName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "vt-" + hex.EncodeToString(sum[:])
+}
+
+// dirLabel returns the name of the label set on all the nodes
+// below a directory. Label names are limited to 63 characters after
+// the prefix, so we use a shorter hash.
+func dirLabel(dir string) string {
+	sum := sha256.Sum224([]byte(dir))
+	return dirLabelPrefix + hex.EncodeToString(sum[:])
+}
+
+// newNode returns a new topoNode for a path, with the right name and labels.
+func newNode(key string, contents []byte, ephemeral bool) *topoNode {
+	labels := make(map[string]string)
+	for dir := path.Dir(key); ; dir = path.Dir(dir) {
+		labels[dirLabel(dir)] = "true"
+		if dir == "/" || dir == "." {
+			break
+		}
+	}
+	return &topoNode{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Metadata: objectMeta{
+			Name:   nodeName(key),
+			Labels: labels,
+		},
+		Data: topoNodeData{
+			Key:       key,
+			Value:     contents,
+			Ephemeral: ephemeral,
+		},
+	}
+}
+
+// client is a minimal REST client for the VitessTopoNode resources
+// of a namespace.
+type client struct {
+	httpClient *http.Client
+
+	// resourceURL is the URL of the VitessTopoNode collection.
+	resourceURL string
+
+	// tokenFile is the bearer token file. Empty if no
+	// authentication is needed.
+	tokenFile string
+
+	// mu protects the following fields.
+	mu        sync.Mutex
+	token     string
+	tokenRead time.Time
+}
+
+func newClient(transport *http.Transport, serverAddr, namespace, tokenFile string) *client {
+	return &client{
+		// No global timeout: watches are long requests.
+		// We use the contexts instead.
+		httpClient:  &http.Client{Transport: transport},
+		resourceURL: fmt.Sprintf("%v/apis/%v/namespaces/%v/%v", strings.TrimSuffix(serverAddr, "/"), apiVersion, namespace, resource),
+		tokenFile:   tokenFile,
+	}
+}
+
+func (c *client) close() {
+	if t, ok := c.httpClient.Transport.(*http.Transport); ok {
+		t.CloseIdleConnections()
+	}
+}
+
+// bearerToken returns the current token, reading the token file
+// again if it is too old.
+func (c *client) bearerToken() (string, error) {
+	if c.tokenFile == "" {
+		return "", nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Since(c.tokenRead) < tokenRefreshInterval {
+		return c.token, nil
+	}
+	data, err := ioutil.ReadFile(c.tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("cannot read token file %v: %v", c.tokenFile, err)
+	}
+	c.token = strings.TrimSpace(string(data))
+	c.tokenRead = time.Now()
+	return c.token, nil
+}
+
+// send sends a request, and returns the response if it was successful.
+// Error responses are returned as *apiError. If the context is done, its
+// error is returned.
+func (c *client) send(ctx context.Context, method, u string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	token, err := c.bearerToken()
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	apiErr := &apiError{}
+	data, _ := ioutil.ReadAll(resp.Body)
+	if err := json.Unmarshal(data, apiErr); err != nil || apiErr.Code == 0 {
+		apiErr.Code = resp.StatusCode
+		apiErr.Reason = resp.Status
+		apiErr.Message = string(data)
+	}
+	return nil, apiErr
+}
+
+// do sends a request, and decodes the response into result.
+func (c *client) do(ctx context.Context, method, u string, body, result interface{}) error {
+	resp, err := c.send(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("cannot decode response from %v: %v", u, err)
+	}
+	return nil
+}
+
+// create creates a new object. It fails if it exists already.
+func (c *client) create(ctx context.Context, node *topoNode) (*topoNode, error) {
+	result := &topoNode{}
+	if err := c.do(ctx, http.MethodPost, c.resourceURL, node, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// get returns an object.
+func (c *client) get(ctx context.Context, name string) (*topoNode, error) {
+	result := &topoNode{}
+	if err := c.do(ctx, http.MethodGet, c.resourceURL+"/"+name, nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// update replaces an object. If node has a resourceVersion, it must match
+// the current one.
+func (c *client) update(ctx context.Context, node *topoNode) (*topoNode, error) {
+	result := &topoNode{}
+	if err := c.do(ctx, http.MethodPut, c.resourceURL+"/"+node.Metadata.Name, node, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// delete deletes an object. If resourceVersion is set, it must match
+// the current one.
+func (c *client) delete(ctx context.Context, name, resourceVersion string) error {
+	var body interface{}
+	if resourceVersion != "" {
+		body = map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "DeleteOptions",
+			"preconditions": map[string]string{
+				"resourceVersion": resourceVersion,
+			},
+		}
+	}
+	resp, err := c.send(ctx, http.MethodDelete, c.resourceURL+"/"+name, body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// list returns all the objects with a label.
+func (c *client) list(ctx context.Context, label string) (*topoNodeList, error) {
+	result := &topoNodeList{}
+	u := c.resourceURL + "?" + url.Values{"labelSelector": []string{label}}.Encode()
+	if err := c.do(ctx, http.MethodGet, u, nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// watch starts watching an object, from a resourceVersion. It returns
+// the stream of JSON watchEvent objects. The stream ends when the server
+// times it out, or when the context is done.
+func (c *client) watch(ctx context.Context, name, resourceVersion string) (io.ReadCloser, error) {
+	u := c.resourceURL + "?" + url.Values{
+		"watch":           []string{"true"},
+		"fieldSelector":   []string{"metadata.name=" + name},
+		"resourceVersion": []string{resourceVersion},
+	}.Encode()
+	resp, err := c.send(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// ListDir is part of the topo.Conn interface.
+func (s *Server) ListDir(ctx context.Context, dirPath string, full bool) ([]topo.DirEntry, error) {
+	nodePath := path.Join(s.root, dirPath)
+
+	// All the nodes below the directory have its label.
+	list, err := s.cli.list(ctx, dirLabel(nodePath))
+	if err != nil {
+		return nil, convertError(err)
+	}
+	if len(list.Items) == 0 {
+		// No node below the directory, means it doesn't exist.
+		return nil, topo.ErrNoNode
+	}
+
+	prefix := nodePath + "/"
+	if nodePath == "/" {
+		prefix = "/"
+	}
+	entries := make(map[string]*topo.DirEntry)
+	for _, node := range list.Items {
+		p := node.Data.Key
+		if !strings.HasPrefix(p, prefix) {
+			// A hash collision on the label, very unlikely.
+			continue
+		}
+		p = p[len(prefix):]
+
+		// Keep only the part until the first '/'.
+		t := topo.TypeFile
+		if i := strings.Index(p, "/"); i >= 0 {
+			p = p[:i]
+			t = topo.TypeDirectory
+		}
+
+		// A directory is ephemeral if all the files below
+		// it are ephemeral.
+		e, ok := entries[p]
+		if !ok {
+			e = &topo.DirEntry{
+				Name:      p,
+				Type:      t,
+				Ephemeral: true,
+			}
+			entries[p] = e
+		}
+		e.Ephemeral = e.Ephemeral && node.Data.Ephemeral
+	}
+
+	result := make([]topo.DirEntry, 0, len(entries))
+	for _, e := range entries {
+		if !full {
+			e = &topo.DirEntry{
+				Name: e.Name,
+			}
+		}
+		result = append(result, *e)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
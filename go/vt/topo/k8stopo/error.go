@@ -0,0 +1,58 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// convertError converts a Kubernetes API error into a topo error. All
+// errors are either API server errors, or context errors.
+func convertError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch err {
+	case context.Canceled:
+		return topo.ErrInterrupted
+	case context.DeadlineExceeded:
+		return topo.ErrTimeout
+	}
+
+	if apiErr, ok := err.(*apiError); ok {
+		switch apiErr.Code {
+		case http.StatusNotFound:
+			return topo.ErrNoNode
+		case http.StatusConflict:
+			// The API server uses Conflict both for objects
+			// that exist already, and for resourceVersion
+			// mismatches.
+			if apiErr.Reason == "AlreadyExists" {
+				return topo.ErrNodeExists
+			}
+			return topo.ErrBadVersion
+		case http.StatusGatewayTimeout:
+			return topo.ErrTimeout
+		}
+	}
+	return err
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fakeAPIServer is an in-memory Kubernetes API server, that only
+// serves the VitessTopoNode resources. It implements the parts of the
+// API we use: create, get, update, delete with preconditions, list by
+// label, and watch by name.
+type fakeAPIServer struct {
+	*httptest.Server
+
+	// watchTimeout is how long watch streams last, before the
+	// server closes them, like the real API server does.
+	watchTimeout time.Duration
+
+	// closed is closed when the server stops, to end the watches.
+	closed chan struct{}
+
+	// mu protects the following fields.
+	mu sync.Mutex
+	// version is the last resourceVersion.
+	version int64
+	// objects is indexed by name.
+	objects map[string]*topoNode
+	// events is the history of all changes, for watches.
+	events []*fakeEvent
+	// changed is closed and replaced on each change.
+	changed chan struct{}
+}
+
+// fakeEvent is a change in the history of the fake server.
+type fakeEvent struct {
+	version int64
+	typ     string
+	node    topoNode
+}
+
+func newFakeAPIServer() *fakeAPIServer {
+	f := &fakeAPIServer{
+		watchTimeout: time.Second,
+		closed:       make(chan struct{}),
+		objects:      make(map[string]*topoNode),
+		changed:      make(chan struct{}),
+	}
+	f.Server = httptest.NewServer(f)
+	return f
+}
+
+// Close stops the server.
+func (f *fakeAPIServer) Close() {
+	close(f.closed)
+	f.Server.Close()
+}
+
+// writeStatus writes a Status object.
+func writeStatus(w http.ResponseWriter, code int, reason, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	status := "Failure"
+	if code == http.StatusOK {
+		status = "Success"
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Status",
+		"status":     status,
+		"reason":     reason,
+		"message":    message,
+		"code":       code,
+	})
+}
+
+func writeObject(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(obj)
+}
+
+// recordLocked adds an event to the history, and wakes up the watches.
+// f.mu must be held.
+func (f *fakeAPIServer) recordLocked(typ string, node *topoNode) {
+	f.events = append(f.events, &fakeEvent{
+		version: f.version,
+		typ:     typ,
+		node:    *node,
+	})
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// ServeHTTP is part of the http.Handler interface.
+func (f *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	prefix := fmt.Sprintf("/apis/%v/namespaces/default/%v", apiVersion, resource)
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		writeStatus(w, http.StatusNotFound, "NotFound", "unknown path "+r.URL.Path)
+		return
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	switch {
+	case name == "" && r.Method == http.MethodPost:
+		f.create(w, r)
+	case name == "" && r.Method == http.MethodGet && r.URL.Query().Get("watch") == "true":
+		f.watch(w, r)
+	case name == "" && r.Method == http.MethodGet:
+		f.list(w, r)
+	case name != "" && r.Method == http.MethodGet:
+		f.get(w, name)
+	case name != "" && r.Method == http.MethodPut:
+		f.update(w, r, name)
+	case name != "" && r.Method == http.MethodDelete:
+		f.delete(w, r, name)
+	default:
+		writeStatus(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method+" "+r.URL.Path)
+	}
+}
+
+func (f *fakeAPIServer) create(w http.ResponseWriter, r *http.Request) {
+	node := &topoNode{}
+	if err := json.NewDecoder(r.Body).Decode(node); err != nil {
+		writeStatus(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.objects[node.Metadata.Name]; ok {
+		writeStatus(w, http.StatusConflict, "AlreadyExists", node.Metadata.Name+" already exists")
+		return
+	}
+	f.version++
+	node.Metadata.Namespace = "default"
+	node.Metadata.ResourceVersion = strconv.FormatInt(f.version, 10)
+	f.objects[node.Metadata.Name] = node
+	f.recordLocked("ADDED", node)
+	writeObject(w, http.StatusCreated, node)
+}
+
+func (f *fakeAPIServer) get(w http.ResponseWriter, name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	node, ok := f.objects[name]
+	if !ok {
+		writeStatus(w, http.StatusNotFound, "NotFound", name+" not found")
+		return
+	}
+	writeObject(w, http.StatusOK, node)
+}
+
+func (f *fakeAPIServer) list(w http.ResponseWriter, r *http.Request) {
+	// We only support selectors on the existence of a label.
+	label := r.URL.Query().Get("labelSelector")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	list := &topoNodeList{
+		Items: []topoNode{},
+	}
+	for _, node := range f.objects {
+		if _, ok := node.Metadata.Labels[label]; ok {
+			list.Items = append(list.Items, *node)
+		}
+	}
+	writeObject(w, http.StatusOK, list)
+}
+
+func (f *fakeAPIServer) update(w http.ResponseWriter, r *http.Request, name string) {
+	node := &topoNode{}
+	if err := json.NewDecoder(r.Body).Decode(node); err != nil {
+		writeStatus(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	current, ok := f.objects[name]
+	switch {
+	case !ok:
+		writeStatus(w, http.StatusNotFound, "NotFound", name+" not found")
+		return
+	case node.Metadata.ResourceVersion == "":
+		// Like for all custom resources.
+		writeStatus(w, http.StatusUnprocessableEntity, "Invalid", "metadata.resourceVersion must be specified for an update")
+		return
+	case node.Metadata.ResourceVersion != current.Metadata.ResourceVersion:
+		writeStatus(w, http.StatusConflict, "Conflict", "the object has been modified")
+		return
+	}
+	f.version++
+	node.Metadata.Namespace = "default"
+	node.Metadata.ResourceVersion = strconv.FormatInt(f.version, 10)
+	f.objects[name] = node
+	f.recordLocked("MODIFIED", node)
+	writeObject(w, http.StatusOK, node)
+}
+
+func (f *fakeAPIServer) delete(w http.ResponseWriter, r *http.Request, name string) {
+	options := struct {
+		Preconditions struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"preconditions"`
+	}{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+			writeStatus(w, http.StatusBadRequest, "BadRequest", err.Error())
+			return
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	current, ok := f.objects[name]
+	switch {
+	case !ok:
+		writeStatus(w, http.StatusNotFound, "NotFound", name+" not found")
+		return
+	case options.Preconditions.ResourceVersion != "" && options.Preconditions.ResourceVersion != current.Metadata.ResourceVersion:
+		writeStatus(w, http.StatusConflict, "Conflict", "precondition failed")
+		return
+	}
+	f.version++
+	delete(f.objects, name)
+	deleted := *current
+	deleted.Metadata.ResourceVersion = strconv.FormatInt(f.version, 10)
+	f.recordLocked("DELETED", &deleted)
+	writeStatus(w, http.StatusOK, "", "")
+}
+
+func (f *fakeAPIServer) watch(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Query().Get("fieldSelector"), "metadata.name=")
+	version, err := strconv.ParseInt(r.URL.Query().Get("resourceVersion"), 10, 64)
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher := w.(http.Flusher)
+	flusher.Flush()
+	encoder := json.NewEncoder(w)
+	timeout := time.After(f.watchTimeout)
+	next := 0
+	for {
+		f.mu.Lock()
+		var events []*fakeEvent
+		for ; next < len(f.events); next++ {
+			ev := f.events[next]
+			if ev.version > version && ev.node.Metadata.Name == name {
+				events = append(events, ev)
+			}
+		}
+		changed := f.changed
+		f.mu.Unlock()
+
+		for _, ev := range events {
+			data, _ := json.Marshal(&ev.node)
+			encoder.Encode(&watchEvent{
+				Type:   ev.typ,
+				Object: data,
+			})
+		}
+		flusher.Flush()
+
+		select {
+		case <-changed:
+		case <-timeout:
+			return
+		case <-r.Context().Done():
+			return
+		case <-f.closed:
+			return
+		}
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"path"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// Create is part of the topo.Conn interface.
+func (s *Server) Create(ctx context.Context, filePath string, contents []byte) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	// The API server refuses to create an object that
+	// exists already.
+	node, err := s.cli.create(ctx, newNode(nodePath, contents, false /*ephemeral*/))
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return KubernetesVersion(node.Metadata.ResourceVersion), nil
+}
+
+// Update is part of the topo.Conn interface.
+func (s *Server) Update(ctx context.Context, filePath string, contents []byte, version topo.Version) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+	node := newNode(nodePath, contents, false /*ephemeral*/)
+
+	if version != nil {
+		// The update only succeeds if the current resourceVersion
+		// is the one we expect.
+		node.Metadata.ResourceVersion = string(version.(KubernetesVersion))
+		result, err := s.cli.update(ctx, node)
+		if err != nil {
+			return nil, convertError(err)
+		}
+		return KubernetesVersion(result.Metadata.ResourceVersion), nil
+	}
+
+	// No version specified. Replacing an object always needs
+	// its resourceVersion, so we read it first, and retry if
+	// it changed in between.
+	for {
+		current, err := s.cli.get(ctx, node.Metadata.Name)
+		switch err := convertError(err); err {
+		case nil:
+			node.Metadata.ResourceVersion = current.Metadata.ResourceVersion
+			result, err := s.cli.update(ctx, node)
+			switch err := convertError(err); err {
+			case nil:
+				return KubernetesVersion(result.Metadata.ResourceVersion), nil
+			case topo.ErrBadVersion, topo.ErrNoNode:
+				continue
+			default:
+				return nil, err
+			}
+		case topo.ErrNoNode:
+			node.Metadata.ResourceVersion = ""
+			result, err := s.cli.create(ctx, node)
+			switch err := convertError(err); err {
+			case nil:
+				return KubernetesVersion(result.Metadata.ResourceVersion), nil
+			case topo.ErrNodeExists:
+				continue
+			default:
+				return nil, err
+			}
+		default:
+			return nil, err
+		}
+	}
+}
+
+// Get is part of the topo.Conn interface.
+func (s *Server) Get(ctx context.Context, filePath string) ([]byte, topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	node, err := s.cli.get(ctx, nodeName(nodePath))
+	if err != nil {
+		return nil, nil, convertError(err)
+	}
+	return node.Data.Value, KubernetesVersion(node.Metadata.ResourceVersion), nil
+}
+
+// Delete is part of the topo.Conn interface.
+func (s *Server) Delete(ctx context.Context, filePath string, version topo.Version) error {
+	nodePath := path.Join(s.root, filePath)
+
+	// With a version, the deletion uses it as a precondition.
+	resourceVersion := ""
+	if version != nil {
+		resourceVersion = string(version.(KubernetesVersion))
+	}
+	return convertError(s.cli.delete(ctx, nodeName(nodePath), resourceVersion))
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+var (
+	leaseTTL = flag.Duration("topo_k8s_lease_ttl", 30*time.Second, "Lease TTL for locks and master election. The holder renews its lease every third of the TTL, and other processes break a lease that was not renewed for the whole TTL.")
+)
+
+// k8sLockDescriptor implements topo.LockDescriptor.
+type k8sLockDescriptor struct {
+	s *Server
+
+	// nodePath is the path of the lock file.
+	nodePath string
+
+	// stop is closed to stop renewing the lease, and done is
+	// closed when the renewal goroutine has exited.
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+
+	// mu protects node. It is held while the lease is renewed.
+	mu sync.Mutex
+	// node is the last version of the lock object we wrote.
+	node *topoNode
+}
+
+// Lock is part of the topo.Conn interface.
+func (s *Server) Lock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	// We list the directory first to make sure it exists.
+	if _, err := s.ListDir(ctx, dirPath, false /*full*/); err != nil {
+		return nil, err
+	}
+
+	ld, err := s.lock(ctx, dirPath, contents)
+	if err != nil {
+		return nil, err
+	}
+	return ld, nil
+}
+
+// lock is used by both Lock() and master election.
+// The lock is an ephemeral file in the directory. Whoever creates it
+// has the lock, the others wait for it to be deleted.
+func (s *Server) lock(ctx context.Context, dirPath, contents string) (*k8sLockDescriptor, error) {
+	nodePath := path.Join(s.root, dirPath, locksFilename)
+
+	for {
+		node := newNode(nodePath, []byte(contents), true /*ephemeral*/)
+		node.Data.RenewTime = time.Now().UTC().Format(time.RFC3339Nano)
+		created, err := s.cli.create(ctx, node)
+		switch err := convertError(err); err {
+		case nil:
+			ld := &k8sLockDescriptor{
+				s:        s,
+				nodePath: nodePath,
+				stop:     make(chan struct{}),
+				done:     make(chan struct{}),
+				node:     created,
+			}
+			go ld.renew()
+			return ld, nil
+		case topo.ErrNodeExists:
+			// Someone else has the lock, wait for them.
+			if err := s.waitForUnlock(ctx, nodePath); err != nil {
+				return nil, err
+			}
+		default:
+			// If our context was canceled as we were
+			// creating the lock, we don't know if it was
+			// created. If it was, nobody renews it and it
+			// will be broken after the lease TTL.
+			return nil, err
+		}
+	}
+}
+
+// waitForUnlock waits until the lock file is deleted. It breaks the lock
+// if its holder didn't renew it for the lease TTL. It uses its own clock
+// for that, as the clocks of the different processes may not agree.
+func (s *Server) waitForUnlock(ctx context.Context, nodePath string) error {
+	name := nodeName(nodePath)
+	node, err := s.cli.get(ctx, name)
+	if err != nil {
+		err = convertError(err)
+		if err == topo.ErrNoNode {
+			// Already gone, try again.
+			return nil
+		}
+		return err
+	}
+
+	// Cancel the watch when we exit this function.
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := s.watchNode(watchCtx, name, node.Metadata.ResourceVersion)
+
+	expired := time.NewTimer(*leaseTTL)
+	defer expired.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return convertError(ctx.Err())
+		case ev, ok := <-events:
+			if !ok {
+				return convertError(ctx.Err())
+			}
+			if ev.err != nil {
+				// The lock was released.
+				return nil
+			}
+			// The lock was renewed.
+			node = ev.node
+			if !expired.Stop() {
+				<-expired.C
+			}
+			expired.Reset(*leaseTTL)
+		case <-expired.C:
+			log.Warningf("lock %v held by %q was not renewed for %v, breaking it", nodePath, node.Data.Value, *leaseTTL)
+			err := convertError(s.cli.delete(ctx, name, node.Metadata.ResourceVersion))
+			switch err {
+			case nil, topo.ErrNoNode, topo.ErrBadVersion:
+				// Either way, the lock is not held by the
+				// same version any more, try again.
+				return nil
+			default:
+				return err
+			}
+		}
+	}
+}
+
+// renew updates the lock file every third of the lease TTL, so the
+// other processes know we still hold it.
+func (ld *k8sLockDescriptor) renew() {
+	defer close(ld.done)
+
+	ticker := time.NewTicker(*leaseTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ld.stop:
+			return
+		case <-ticker.C:
+		}
+
+		// We hold mu during the update, so Check doesn't see
+		// a version we haven't recorded yet.
+		ld.mu.Lock()
+		node := *ld.node
+		node.Data.RenewTime = time.Now().UTC().Format(time.RFC3339Nano)
+		ctx, cancel := context.WithTimeout(context.Background(), *leaseTTL/3)
+		result, err := ld.s.cli.update(ctx, &node)
+		cancel()
+		if err == nil {
+			ld.node = result
+		}
+		ld.mu.Unlock()
+
+		switch err := convertError(err); err {
+		case nil:
+			// Renewed.
+		case topo.ErrNoNode, topo.ErrBadVersion:
+			// Our lease was broken, Check will report it.
+			log.Errorf("lost lock %v: %v", ld.nodePath, err)
+			return
+		default:
+			// We will try again on the next tick,
+			// before the lease expires.
+			log.Warningf("cannot renew lock %v: %v", ld.nodePath, err)
+		}
+	}
+}
+
+// Check is part of the topo.LockDescriptor interface.
+// We make sure the lock file is still the one we wrote last.
+func (ld *k8sLockDescriptor) Check(ctx context.Context) error {
+	ld.mu.Lock()
+	defer ld.mu.Unlock()
+
+	node, err := ld.s.cli.get(ctx, ld.node.Metadata.Name)
+	if err != nil {
+		return convertError(err)
+	}
+	if node.Metadata.ResourceVersion != ld.node.Metadata.ResourceVersion {
+		return fmt.Errorf("lock %v was broken and taken by %q", ld.nodePath, node.Data.Value)
+	}
+	return nil
+}
+
+// Unlock is part of the topo.LockDescriptor interface.
+func (ld *k8sLockDescriptor) Unlock(ctx context.Context) error {
+	ld.stopOnce.Do(func() {
+		close(ld.stop)
+	})
+	<-ld.done
+
+	// The renewal goroutine is done, we can use ld.node.
+	return convertError(ld.s.cli.delete(ctx, ld.node.Metadata.Name, ld.node.Metadata.ResourceVersion))
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package k8stopo implements topo.Server with the Kubernetes API server as the
+backend, so a Vitess cluster running in Kubernetes doesn't need its own etcd.
+
+Each topology file is stored as a VitessTopoNode custom resource (group
+topo.vitess.io, version v1beta1), in the namespace set by the
+-topo_k8s_namespace flag. The CustomResourceDefinition has to be created
+beforehand, see doc/TopologyService.md.
+
+We follow these conventions within this package:
+
+  - Objects are named after a hash of their full path, so any path can be
+    stored. The path itself is in the object data.
+  - Objects are labeled with a hash of each of their parent directories, so a
+    directory can be listed with a label selector.
+  - The resourceVersion of an object is its topo.Version. All the updates
+    and deletions with a version use it as a precondition.
+  - Call convertError(err) on any errors returned from the client. Functions
+    defined in this package can be assumed to have already converted errors
+    as necessary.
+*/
+package k8stopo
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+const (
+	// serviceAccountDir is where Kubernetes mounts the credentials
+	// of the pod service account.
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+)
+
+var (
+	namespace = flag.String("topo_k8s_namespace", "", "Kubernetes namespace of the VitessTopoNode objects. Defaults to the namespace of the pod when running in Kubernetes.")
+	tokenFile = flag.String("topo_k8s_token_file", "", "File containing the bearer token to authenticate to the Kubernetes API server. Defaults to the pod service account token when running in Kubernetes.")
+	caFile    = flag.String("topo_k8s_ca_file", "", "File containing the CA certificates of the Kubernetes API server. Defaults to the pod service account CA when running in Kubernetes.")
+)
+
+// Factory is the Kubernetes topo.Factory implementation.
+type Factory struct{}
+
+// HasGlobalReadOnlyCell is part of the topo.Factory interface.
+func (f Factory) HasGlobalReadOnlyCell(serverAddr, root string) bool {
+	return false
+}
+
+// Create is part of the topo.Factory interface.
+func (f Factory) Create(cell, serverAddr, root string) (topo.Conn, error) {
+	return NewServer(serverAddr, root)
+}
+
+// Server is the implementation of topo.Server for Kubernetes.
+type Server struct {
+	// cli is the client for the VitessTopoNode resources.
+	cli *client
+
+	// root is the root path for this client.
+	root string
+}
+
+// Close implements topo.Server.Close.
+// It will nil out the client, so any attempt to
+// re-use this server will panic.
+func (s *Server) Close() {
+	s.cli.close()
+	s.cli = nil
+}
+
+// NewServer returns a new k8stopo.Server.
+// serverAddr is the URL of the API server. If it is empty, and we are
+// running in a pod, the in-cluster API server and service account are used.
+func NewServer(serverAddr, root string) (*Server, error) {
+	ns := *namespace
+	token := *tokenFile
+	ca := *caFile
+	if serverAddr == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return nil, fmt.Errorf("no Kubernetes API server address given, and not running in a Kubernetes pod")
+		}
+		serverAddr = "https://" + host + ":" + port
+		if token == "" {
+			token = serviceAccountDir + "/token"
+		}
+		if ca == "" {
+			ca = serviceAccountDir + "/ca.crt"
+		}
+		if ns == "" {
+			data, err := ioutil.ReadFile(serviceAccountDir + "/namespace")
+			if err != nil {
+				return nil, fmt.Errorf("cannot read the pod namespace: %v", err)
+			}
+			ns = strings.TrimSpace(string(data))
+		}
+	}
+	if ns == "" {
+		ns = "default"
+	}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+	}
+	if ca != "" {
+		data, err := ioutil.ReadFile(ca)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CA file %v: %v", ca, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificate found in CA file %v", ca)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	return &Server{
+		cli:  newClient(transport, serverAddr, ns, token),
+		root: root,
+	}, nil
+}
+
+func init() {
+	topo.RegisterFactory("k8s", Factory{})
+}
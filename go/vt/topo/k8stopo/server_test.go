@@ -0,0 +1,131 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"fmt"
+	"path"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/test"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestK8sTopo(t *testing.T) {
+	// Start a fake API server in the background.
+	apiServer := newFakeAPIServer()
+	defer apiServer.Close()
+
+	testIndex := 0
+	newServer := func() *topo.Server {
+		// Each test will use its own sub-directories.
+		testRoot := fmt.Sprintf("/test-%v", testIndex)
+		testIndex++
+
+		// Create the server on the new root.
+		ts, err := topo.OpenServer("k8s", apiServer.URL, path.Join(testRoot, topo.GlobalCell))
+		if err != nil {
+			t.Fatalf("OpenServer() failed: %v", err)
+		}
+
+		// Create the CellInfo.
+		if err := ts.CreateCellInfo(context.Background(), test.LocalCellName, &topodatapb.CellInfo{
+			ServerAddress: apiServer.URL,
+			Root:          path.Join(testRoot, test.LocalCellName),
+		}); err != nil {
+			t.Fatalf("CreateCellInfo() failed: %v", err)
+		}
+
+		return ts
+	}
+
+	// Run the TopoServerTestSuite tests.
+	test.TopoServerTestSuite(t, func() *topo.Server {
+		return newServer()
+	})
+
+	// Run k8s-specific tests.
+	ts := newServer()
+	testKeyspaceLock(t, ts)
+	ts.Close()
+}
+
+// testKeyspaceLock tests the lease renewal, and that the lock of a
+// process that went away is broken after the lease TTL.
+func testKeyspaceLock(t *testing.T, ts *topo.Server) {
+	ctx := context.Background()
+	keyspacePath := path.Join(topo.KeyspacesPath, "test_keyspace")
+	if err := ts.CreateKeyspace(ctx, "test_keyspace", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace: %v", err)
+	}
+
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		t.Fatalf("ConnForCell failed: %v", err)
+	}
+
+	// Short TTL, make sure it doesn't expire while we hold it.
+	savedTTL := *leaseTTL
+	defer func() {
+		*leaseTTL = savedTTL
+	}()
+	*leaseTTL = 300 * time.Millisecond
+	lockDescriptor, err := conn.Lock(ctx, keyspacePath, "short ttl")
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	fastCtx, cancel := context.WithTimeout(ctx, time.Second)
+	if _, err := conn.Lock(fastCtx, keyspacePath, "again"); err != topo.ErrTimeout {
+		t.Fatalf("Lock(again): %v", err)
+	}
+	cancel()
+	if err := lockDescriptor.Check(ctx); err != nil {
+		t.Errorf("Check(): %v", err)
+	}
+	if err := lockDescriptor.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+
+	// Simulate a process that went away: stop renewing the lease
+	// without removing the lock. The lock is broken after the TTL.
+	lockDescriptor, err = conn.Lock(ctx, keyspacePath, "abandoned")
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	ld := lockDescriptor.(*k8sLockDescriptor)
+	close(ld.stop)
+	<-ld.done
+	start := time.Now()
+	lockDescriptor2, err := conn.Lock(ctx, keyspacePath, "takeover")
+	if err != nil {
+		t.Fatalf("Lock(takeover) failed: %v", err)
+	}
+	if waited := time.Since(start); waited < *leaseTTL {
+		t.Errorf("Lock(takeover) only waited %v, expected at least %v", waited, *leaseTTL)
+	}
+	if err := lockDescriptor.Check(ctx); err == nil {
+		t.Errorf("Check() on broken lock worked")
+	}
+	if err := lockDescriptor2.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock(takeover) failed: %v", err)
+	}
+}
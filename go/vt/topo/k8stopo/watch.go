@@ -0,0 +1,187 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stopo
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// nodeEvent is a change of a watched node. If the node was deleted,
+// err is topo.ErrNoNode.
+type nodeEvent struct {
+	node *topoNode
+	err  error
+}
+
+// Watch is part of the topo.Conn interface.
+func (s *Server) Watch(ctx context.Context, filePath string) (*topo.WatchData, <-chan *topo.WatchData, topo.CancelFunc) {
+	nodePath := path.Join(s.root, filePath)
+
+	// Get the initial version of the file.
+	initial, err := s.cli.get(ctx, nodeName(nodePath))
+	if err != nil {
+		return &topo.WatchData{Err: convertError(err)}, nil, nil
+	}
+	wd := &topo.WatchData{
+		Contents: initial.Data.Value,
+		Version:  KubernetesVersion(initial.Metadata.ResourceVersion),
+	}
+
+	// Create a context, will be used to cancel the watch.
+	watchCtx, watchCancel := context.WithCancel(context.Background())
+
+	// We start watching from the version we got, so we don't
+	// miss any change.
+	events := s.watchNode(watchCtx, initial.Metadata.Name, initial.Metadata.ResourceVersion)
+
+	// Create the notifications channel, send updates to it.
+	notifications := make(chan *topo.WatchData, 10)
+	go func() {
+		defer close(notifications)
+
+		for ev := range events {
+			if ev.err != nil {
+				// Node is gone, send a final notice.
+				notifications <- &topo.WatchData{
+					Err: ev.err,
+				}
+				return
+			}
+			notifications <- &topo.WatchData{
+				Contents: ev.node.Data.Value,
+				Version:  KubernetesVersion(ev.node.Metadata.ResourceVersion),
+			}
+		}
+
+		// The events channel is only closed without a final
+		// event when the watch is canceled.
+		notifications <- &topo.WatchData{
+			Err: convertError(watchCtx.Err()),
+		}
+	}()
+
+	return wd, notifications, topo.CancelFunc(watchCancel)
+}
+
+// watchNode watches a node from a resourceVersion, until it is deleted
+// or the context is done. The API server closes watch streams
+// periodically, so they are re-established from the last version seen.
+// The returned channel is closed after the deletion event, or when the
+// context is done.
+func (s *Server) watchNode(ctx context.Context, name, resourceVersion string) <-chan *nodeEvent {
+	events := make(chan *nodeEvent, 10)
+	go func() {
+		defer close(events)
+
+		var watchRetries int
+		for ctx.Err() == nil {
+			stream, err := s.cli.watch(ctx, name, resourceVersion)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				watchRetries++
+				log.Warningf("watch on %v failed, will retry: %v", name, err)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Duration(watchRetries) * 100 * time.Millisecond):
+				}
+				continue
+			}
+			watchRetries = 0
+
+			var done bool
+			resourceVersion, done = s.readWatchStream(ctx, stream, name, resourceVersion, events)
+			stream.Close()
+			if done {
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// readWatchStream sends the events of a watch stream to the events
+// channel. It returns the last resourceVersion seen, and true if the
+// watch is over.
+func (s *Server) readWatchStream(ctx context.Context, stream io.Reader, name, resourceVersion string, events chan<- *nodeEvent) (string, bool) {
+	send := func(ev *nodeEvent) bool {
+		select {
+		case events <- ev:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	decoder := json.NewDecoder(stream)
+	for {
+		var ev watchEvent
+		if err := decoder.Decode(&ev); err != nil {
+			// The stream ended, or was interrupted.
+			return resourceVersion, ctx.Err() != nil
+		}
+
+		switch ev.Type {
+		case "ADDED", "MODIFIED":
+			node := &topoNode{}
+			if err := json.Unmarshal(ev.Object, node); err != nil {
+				log.Warningf("watch on %v returned an invalid object: %v", name, err)
+				continue
+			}
+			resourceVersion = node.Metadata.ResourceVersion
+			if !send(&nodeEvent{node: node}) {
+				return resourceVersion, true
+			}
+		case "DELETED":
+			send(&nodeEvent{err: topo.ErrNoNode})
+			return resourceVersion, true
+		default:
+			// This is an error, usually because the
+			// resourceVersion we watch from is too old and
+			// was compacted. Start over from the current
+			// version of the node.
+			log.Infof("watch on %v returned %v, reading it again: %s", name, ev.Type, ev.Object)
+			node, err := s.cli.get(ctx, name)
+			switch err := convertError(err); err {
+			case nil:
+				if node.Metadata.ResourceVersion != resourceVersion && !send(&nodeEvent{node: node}) {
+					return resourceVersion, true
+				}
+				return node.Metadata.ResourceVersion, false
+			case topo.ErrNoNode:
+				send(&nodeEvent{err: topo.ErrNoNode})
+				return resourceVersion, true
+			default:
+				if ctx.Err() != nil {
+					return resourceVersion, true
+				}
+				log.Warningf("cannot read %v after watch error: %v", name, err)
+				return resourceVersion, false
+			}
+		}
+	}
+}
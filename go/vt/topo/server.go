@@ -165,6 +165,10 @@ type Server struct {
 	// will read the list of addresses for that cell from the
 	// global cluster and create clients as needed.
 	cells map[string]Conn
+
+	// audit records the changes in the audit log. It is nil if
+	// the audit log is disabled. See audit.go.
+	audit *auditRecorder
 }
 
 type cellsToRegionsMap struct {
@@ -221,12 +225,17 @@ func NewWithFactory(factory Factory, serverAddress, root string) (*Server, error
 		connReadOnly = conn
 	}
 
-	return &Server{
-		globalCell:         conn,
-		globalReadOnlyCell: connReadOnly,
-		factory:            factory,
-		cells:              make(map[string]Conn),
-	}, nil
+	ts := &Server{
+		factory: factory,
+		cells:   make(map[string]Conn),
+		audit:   newAuditRecorder(conn),
+	}
+	ts.globalCell = ts.auditConnForCell(GlobalCell, conn)
+	ts.globalReadOnlyCell = connReadOnly
+	if connReadOnly == conn {
+		ts.globalReadOnlyCell = ts.globalCell
+	}
+	return ts, nil
 }
 
 // OpenServer returns a Server using the provided implementation,
@@ -289,6 +298,7 @@ func (ts *Server) ConnForCell(ctx context.Context, cell string) (Conn, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create topo connection to %v, %v: %v", ci.ServerAddress, ci.Root, err)
 	}
+	conn = ts.auditConnForCell(cell, conn)
 	ts.cells[cell] = conn
 	return conn, nil
 }
@@ -322,6 +332,10 @@ func UpdateCellsToRegionsForTests(cellsToRegions map[string]string) {
 // Close will close all connections to underlying topo Server.
 // It will nil all member variables, so any further access will panic.
 func (ts *Server) Close() {
+	// The waiting audit log entries are written first.
+	if ts.audit != nil {
+		ts.audit.close()
+	}
 	ts.globalCell.Close()
 	if ts.globalReadOnlyCell != ts.globalCell {
 		ts.globalReadOnlyCell.Close()
@@ -35,6 +35,11 @@ import (
 const (
 	// Default name for databases is the prefix plus keyspace
 	vtDbPrefix = "vt_"
+
+	// DelayedReplicaTag is the tag of the tablets that replicate with
+	// a delay, as a protection against bad writes. Its value is the
+	// delay. Such tablets are not used to serve queries.
+	DelayedReplicaTag = "delayed_replica"
 )
 
 // cache the conversion from tablet type enum to lower case string.
@@ -266,3 +271,12 @@ func TabletDbName(tablet *topodatapb.Tablet) string {
 func TabletIsAssigned(tablet *topodatapb.Tablet) bool {
 	return tablet != nil && tablet.Keyspace != "" && tablet.Shard != ""
 }
+
+// IsDelayedReplica returns true if the tablet replicates with a delay.
+func IsDelayedReplica(tablet *topodatapb.Tablet) bool {
+	if tablet == nil {
+		return false
+	}
+	_, ok := tablet.Tags[DelayedReplicaTag]
+	return ok
+}
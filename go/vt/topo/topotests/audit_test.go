@@ -0,0 +1,232 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotests
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// This file contains tests for the audit.go file.
+
+func TestAuditLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "topoaudit")
+	if err != nil {
+		t.Fatalf("TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	auditFile := path.Join(dir, "audit.log")
+
+	flag.Set("topo_audit_log", "true")
+	flag.Set("topo_audit_log_file", auditFile)
+	defer func() {
+		flag.Set("topo_audit_log", "false")
+		flag.Set("topo_audit_log_file", "")
+	}()
+
+	ts := memorytopo.NewServer("cell1")
+	defer ts.Close()
+	ctx := callerid.NewContext(context.Background(), callerid.NewEffectiveCallerID("alice", "test", ""), nil)
+	ctx = topo.NewAuditContext(ctx, "SetKeyspaceShardingInfo ks1 col1")
+
+	if err := ts.CreateKeyspace(ctx, "ks1", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+	ki, err := ts.GetKeyspace(ctx, "ks1")
+	if err != nil {
+		t.Fatalf("GetKeyspace failed: %v", err)
+	}
+	ki.ShardingColumnName = "col1"
+	lockCtx, unlock, err := ts.LockKeyspace(ctx, "ks1", "test")
+	if err != nil {
+		t.Fatalf("LockKeyspace failed: %v", err)
+	}
+	if err := ts.UpdateKeyspace(lockCtx, ki); err != nil {
+		t.Fatalf("UpdateKeyspace failed: %v", err)
+	}
+	unlock(&err)
+	if err := ts.DeleteKeyspace(ctx, "ks1"); err != nil {
+		t.Fatalf("DeleteKeyspace failed: %v", err)
+	}
+
+	// The changes of the keyspace are in the audit log.
+	entries := waitForAuditLog(ctx, t, ts, topo.AuditFilter{
+		Path: "keyspaces/ks1",
+	}, 3)
+	for i, want := range []struct {
+		operation, function string
+	}{
+		{topo.AuditCreate, "CreateKeyspace"},
+		{topo.AuditUpdate, "UpdateKeyspace"},
+		{topo.AuditDelete, "DeleteKeyspace"},
+	} {
+		e := entries[i]
+		if e.Operation != want.operation || e.Function != want.function || e.Cell != topo.GlobalCell || e.Path != "keyspaces/ks1/Keyspace" {
+			t.Errorf("entry %v is %+v, expected %v by %v on global:keyspaces/ks1/Keyspace", i, e, want.operation, want.function)
+		}
+		if e.Principal != "alice" || e.Command != "SetKeyspaceShardingInfo ks1 col1" {
+			t.Errorf("entry %v has caller %v and command %q", i, e.Principal, e.Command)
+		}
+	}
+	if !strings.Contains(entries[1].Diff, `+sharding_column_name: "col1"`) {
+		t.Errorf("update diff is %q", entries[1].Diff)
+	}
+	if entries[1].OldVersion != entries[0].NewVersion || entries[2].OldVersion != entries[1].NewVersion {
+		t.Errorf("versions don't follow: %+v", entries)
+	}
+
+	// Filters.
+	entries, err = ts.GetAuditLog(ctx, topo.AuditFilter{Caller: "bob"})
+	if err != nil || len(entries) != 0 {
+		t.Errorf("GetAuditLog(bob) returned %v %v", entries, err)
+	}
+	entries, err = ts.GetAuditLog(ctx, topo.AuditFilter{Since: time.Now().Add(time.Hour)})
+	if err != nil || len(entries) != 0 {
+		t.Errorf("GetAuditLog(future) returned %v %v", entries, err)
+	}
+	entries, err = ts.GetAuditLog(ctx, topo.AuditFilter{Limit: 1})
+	if err != nil || len(entries) != 1 || entries[0].Operation != topo.AuditDelete {
+		t.Errorf("GetAuditLog(limit 1) returned %v %v", entries, err)
+	}
+
+	// The local cells are audited too.
+	if err := ts.UpdateSrvKeyspace(ctx, "cell1", "ks1", &topodatapb.SrvKeyspace{}); err != nil {
+		t.Fatalf("UpdateSrvKeyspace failed: %v", err)
+	}
+	entries = waitForAuditLog(ctx, t, ts, topo.AuditFilter{Cell: "cell1"}, 1)
+	if entries[0].Path != "keyspaces/ks1/SrvKeyspace" || entries[0].Function != "UpdateSrvKeyspace" {
+		t.Errorf("GetAuditLog(cell1) returned %v", entries)
+	}
+
+	// And all the entries were exported to the file.
+	f, err := os.Open(auditFile)
+	if err != nil {
+		t.Fatalf("cannot open audit file: %v", err)
+	}
+	defer f.Close()
+	var exported []*topo.AuditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		e := &topo.AuditEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), e); err != nil {
+			t.Fatalf("invalid line in audit file: %v", err)
+		}
+		exported = append(exported, e)
+	}
+	all, err := ts.GetAuditLog(ctx, topo.AuditFilter{})
+	if err != nil {
+		t.Fatalf("GetAuditLog failed: %v", err)
+	}
+	if len(exported) != len(all) {
+		t.Errorf("exported %v entries, recorded %v", len(exported), len(all))
+	}
+}
+
+func TestPruneAuditLog(t *testing.T) {
+	flag.Set("topo_audit_log", "true")
+	defer flag.Set("topo_audit_log", "false")
+
+	ts := memorytopo.NewServer("cell1")
+	defer ts.Close()
+	ctx := context.Background()
+
+	// An old entry, and a change today.
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		t.Fatalf("ConnForCell failed: %v", err)
+	}
+	old, err := json.Marshal(&topo.AuditEntry{
+		Time:      time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC),
+		Cell:      topo.GlobalCell,
+		Path:      "keyspaces/ks0/Keyspace",
+		Operation: topo.AuditCreate,
+	})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if _, err := conn.Create(ctx, "audit/20180102/old", old); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := ts.CreateKeyspace(ctx, "ks1", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+	waitForAuditLog(ctx, t, ts, topo.AuditFilter{Path: "keyspaces/ks1"}, 1)
+	before, err := ts.GetAuditLog(ctx, topo.AuditFilter{})
+	if err != nil {
+		t.Fatalf("GetAuditLog failed: %v", err)
+	}
+
+	// Only the old day is deleted.
+	count, err := ts.PruneAuditLog(ctx, time.Now().Add(-24*time.Hour))
+	if err != nil || count != 1 {
+		t.Fatalf("PruneAuditLog returned %v %v, expected 1 entry", count, err)
+	}
+	count, err = ts.PruneAuditLog(ctx, time.Now().Add(-24*time.Hour))
+	if err != nil || count != 0 {
+		t.Errorf("second PruneAuditLog returned %v %v, expected nothing", count, err)
+	}
+
+	// The deletions were not audited: the next entry follows the
+	// ones from before.
+	if err := ts.DeleteKeyspace(ctx, "ks1"); err != nil {
+		t.Fatalf("DeleteKeyspace failed: %v", err)
+	}
+	after := waitForAuditLog(ctx, t, ts, topo.AuditFilter{}, len(before))
+	if after[len(after)-1].Operation != topo.AuditDelete || after[len(after)-1].Path != "keyspaces/ks1/Keyspace" {
+		t.Errorf("unexpected entries after PruneAuditLog: %+v", after)
+	}
+	for _, e := range after {
+		if e.Path == "keyspaces/ks0/Keyspace" {
+			t.Errorf("old entry was not deleted: %+v", e)
+		}
+	}
+}
+
+// waitForAuditLog waits until the audit log has count entries selected
+// by the filter, as they are written in the background.
+func waitForAuditLog(ctx context.Context, t *testing.T, ts *topo.Server, filter topo.AuditFilter, count int) []*topo.AuditEntry {
+	t.Helper()
+	timeout := time.Now().Add(10 * time.Second)
+	for {
+		entries, err := ts.GetAuditLog(ctx, filter)
+		if err != nil {
+			t.Fatalf("GetAuditLog failed: %v", err)
+		}
+		if len(entries) == count {
+			return entries
+		}
+		if len(entries) > count || time.Now().After(timeout) {
+			t.Fatalf("GetAuditLog returned %v entries, expected %v: %v", len(entries), count, entries)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
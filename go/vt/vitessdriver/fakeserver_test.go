@@ -216,6 +216,10 @@ func (f *fakeVTGateService) MessageAckKeyspaceIds(ctx context.Context, keyspace
 	return 0, nil
 }
 
+func (f *fakeVTGateService) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	return 0, nil
+}
+
 // SplitQuery is part of the VTGateService interface
 func (f *fakeVTGateService) SplitQuery(
 	ctx context.Context,
@@ -240,6 +244,11 @@ func (f *fakeVTGateService) UpdateStream(ctx context.Context, keyspace string, s
 	return nil
 }
 
+// StreamChanges is part of the VTGateService interface
+func (f *fakeVTGateService) StreamChanges(ctx context.Context, keyspace string, tabletType topodatapb.TabletType, vgtid *vtgatepb.VGtid, callback func(*vtgatepb.StreamChangesResponse) error) error {
+	return nil
+}
+
 // HandlePanic is part of the VTGateService interface
 func (f *fakeVTGateService) HandlePanic(err *error) {
 	if x := recover(); x != nil {
@@ -331,9 +331,9 @@ func (itc *internalTabletConn) Begin(ctx context.Context, target *querypb.Target
 }
 
 // Commit is part of queryservice.QueryService
-func (itc *internalTabletConn) Commit(ctx context.Context, target *querypb.Target, transactionID int64) error {
-	err := itc.tablet.qsc.QueryService().Commit(ctx, target, transactionID)
-	return tabletconn.ErrorFromGRPC(vterrors.ToGRPC(err))
+func (itc *internalTabletConn) Commit(ctx context.Context, target *querypb.Target, transactionID int64, includeGTIDExecuted bool) (string, error) {
+	gtidExecuted, err := itc.tablet.qsc.QueryService().Commit(ctx, target, transactionID, includeGTIDExecuted)
+	return gtidExecuted, tabletconn.ErrorFromGRPC(vterrors.ToGRPC(err))
 }
 
 // Rollback is part of queryservice.QueryService
@@ -349,9 +349,9 @@ func (itc *internalTabletConn) Prepare(ctx context.Context, target *querypb.Targ
 }
 
 // CommitPrepared is part of queryservice.QueryService
-func (itc *internalTabletConn) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string) error {
-	err := itc.tablet.qsc.QueryService().CommitPrepared(ctx, target, dtid)
-	return tabletconn.ErrorFromGRPC(vterrors.ToGRPC(err))
+func (itc *internalTabletConn) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string, includeGTIDExecuted bool) (string, error) {
+	gtidExecuted, err := itc.tablet.qsc.QueryService().CommitPrepared(ctx, target, dtid, includeGTIDExecuted)
+	return gtidExecuted, tabletconn.ErrorFromGRPC(vterrors.ToGRPC(err))
 }
 
 // RollbackPrepared is part of queryservice.QueryService
@@ -367,9 +367,9 @@ func (itc *internalTabletConn) CreateTransaction(ctx context.Context, target *qu
 }
 
 // StartCommit is part of queryservice.QueryService
-func (itc *internalTabletConn) StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string) error {
-	err := itc.tablet.qsc.QueryService().StartCommit(ctx, target, transactionID, dtid)
-	return tabletconn.ErrorFromGRPC(vterrors.ToGRPC(err))
+func (itc *internalTabletConn) StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string, includeGTIDExecuted bool) (string, error) {
+	gtidExecuted, err := itc.tablet.qsc.QueryService().StartCommit(ctx, target, transactionID, dtid, includeGTIDExecuted)
+	return gtidExecuted, tabletconn.ErrorFromGRPC(vterrors.ToGRPC(err))
 }
 
 // SetRollback is part of queryservice.QueryService
@@ -472,6 +472,12 @@ func (itc *internalTabletConn) UpdateStream(ctx context.Context, target *querypb
 	return tabletconn.ErrorFromGRPC(vterrors.ToGRPC(err))
 }
 
+// StreamChanges is part of queryservice.QueryService.
+func (itc *internalTabletConn) StreamChanges(ctx context.Context, target *querypb.Target, position string, callback func(*querypb.ChangeEvent) error) error {
+	err := itc.tablet.qsc.QueryService().StreamChanges(ctx, target, position, callback)
+	return tabletconn.ErrorFromGRPC(vterrors.ToGRPC(err))
+}
+
 //
 // TabletManagerClient implementation
 //
@@ -22,7 +22,9 @@ import (
 	"io"
 
 	"golang.org/x/net/context"
+	"vitess.io/vitess/go/vt/dbconfigs"
 	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/wrangler"
@@ -39,6 +41,16 @@ func init() {
 		commandRemoveBackup,
 		"<keyspace/shard> <backup name>",
 		"Removes a backup for the BackupStorage."})
+	addCommand("Shards", command{
+		"VerifyBackup",
+		commandVerifyBackup,
+		"[-tablet_uid <uid>] [-mysql_port <port>] [-db_name <name>] [-restore_concurrency <n>] <keyspace/shard> <backup name>",
+		"Restores a backup into a temporary mysqld started by this process, checksums the tables of the database, compares them with the checksums recorded when the backup was taken, and records the result in the backup MANIFEST. The temporary mysqld is removed when done. The -db-config-dba-* flags give the credentials to use on the restored data."})
+	addCommand("Shards", command{
+		"PruneBackups",
+		commandPruneBackups,
+		"[-keep_daily <n>] [-keep_weekly <n>] [-dry_run] <keyspace/shard>",
+		"Removes the backups of a shard from the BackupStorage, except the newest backup of each of the last keep_daily days and keep_weekly weeks (UTC). The newest backup is always kept, and incomplete backups are never removed. Binlog archives are removed once they are older than all the full backups kept."})
 
 	addCommand("Tablets", command{
 		"RestoreFromBackup",
@@ -99,6 +111,77 @@ func commandRemoveBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *f
 	return bs.RemoveBackup(ctx, bucket, name)
 }
 
+func commandVerifyBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	tabletUID := subFlags.Int("tablet_uid", 99999, "uid of the temporary mysqld, its data goes in $VTDATAROOT/vt_<uid>")
+	mysqlPort := subFlags.Int("mysql_port", 0, "port of the temporary mysqld (0 means 17100 + tablet_uid)")
+	dbName := subFlags.String("db_name", "", "database to check (defaults to vt_<keyspace>)")
+	restoreConcurrency := subFlags.Int("restore_concurrency", 4, "how many concurrent files to restore at once")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 2 {
+		return fmt.Errorf("action VerifyBackup requires <keyspace/shard> <backup name>")
+	}
+
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	bucket := fmt.Sprintf("%v/%v", keyspace, shard)
+	name := subFlags.Arg(1)
+	if *dbName == "" {
+		*dbName = "vt_" + keyspace
+	}
+	if *mysqlPort == 0 {
+		*mysqlPort = 17100 + *tabletUID
+	}
+
+	mysqld, err := mysqlctl.CreateMysqld(uint32(*tabletUID), "", int32(*mysqlPort), dbconfigs.DbaConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create temporary mysqld: %v", err)
+	}
+	defer mysqld.Close()
+
+	v, err := mysqlctl.VerifyBackup(ctx, mysqld, wr.Logger(), bucket, name, *dbName, *restoreConcurrency)
+	if err != nil {
+		return err
+	}
+	if v.Error != "" {
+		return fmt.Errorf("backup %v failed verification: %v", name, v.Error)
+	}
+	for _, t := range v.Tables {
+		wr.Logger().Printf("%v: %v rows, checksum %v\n", t.Name, t.Rows, t.Checksum)
+	}
+	return nil
+}
+
+func commandPruneBackups(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	keepDaily := subFlags.Int("keep_daily", 7, "number of recent days to keep the newest backup of")
+	keepWeekly := subFlags.Int("keep_weekly", 4, "number of recent weeks to keep the newest backup of")
+	dryRun := subFlags.Bool("dry_run", false, "only display the backups that would be removed")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("action PruneBackups requires <keyspace/shard>")
+	}
+
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	bucket := fmt.Sprintf("%v/%v", keyspace, shard)
+
+	names, err := mysqlctl.PruneBackups(ctx, wr.Logger(), bucket, *keepDaily, *keepWeekly, *dryRun)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		wr.Logger().Printf("%v\n", name)
+	}
+	return nil
+}
+
 func commandRestoreFromBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	if err := subFlags.Parse(args); err != nil {
 		return err
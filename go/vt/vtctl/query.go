@@ -23,8 +23,10 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/olekukonko/tablewriter"
@@ -33,6 +35,7 @@ import (
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/grpcclient"
 	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
 	"vitess.io/vitess/go/vt/vttablet/tabletconn"
@@ -106,6 +109,13 @@ func init() {
 		commandVtTabletUpdateStream,
 		"[-count <count, default 1>] [-position <position>] [-timestamp <timestamp>] <tablet alias>",
 		"Executes the UpdateStream streaming query to a vttablet process. Will stop after getting <count> answers."})
+
+	// Message commands
+	addCommand(queriesGroupName, command{
+		"RequeueMessages",
+		commandRequeueMessages,
+		"[-username <TableACL user>] [-ids <id1>,<id2>,...] <keyspace> <table>",
+		"Requeues the failed messages of a message table on all the shards of the keyspace, or only the ones with the given ids. If the message table has a dead letter table, its name must be given instead: the dead letters are then moved back to the message table by vttablet."})
 }
 
 type bindvars map[string]interface{}
@@ -536,11 +546,12 @@ func commandVtTabletCommit(ctx context.Context, wr *wrangler.Wrangler, subFlags
 	}
 	defer conn.Close(ctx)
 
-	return conn.Commit(ctx, &querypb.Target{
+	_, err = conn.Commit(ctx, &querypb.Target{
 		Keyspace:   tabletInfo.Tablet.Keyspace,
 		Shard:      tabletInfo.Tablet.Shard,
 		TabletType: tabletInfo.Tablet.Type,
-	}, transactionID)
+	}, transactionID, false /* includeGTIDExecuted */)
+	return err
 }
 
 func commandVtTabletRollback(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
@@ -726,3 +737,80 @@ func printQueryResult(writer io.Writer, qr *sqltypes.Result) {
 	// Print table.
 	table.Render()
 }
+
+func commandRequeueMessages(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	if !*enableQueries {
+		return fmt.Errorf("query commands are disabled (set the -enable_queries flag to enable)")
+	}
+
+	username := subFlags.String("username", "", "If set, value is set as immediate caller id in the request and used by vttablet for TableACL check")
+	ids := subFlags.String("ids", "", "Comma-separated list of the ids of the messages to requeue. All the failed messages are requeued by default")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 2 {
+		return fmt.Errorf("the <keyspace> and <table> arguments are required for the RequeueMessages command")
+	}
+	keyspace := subFlags.Arg(0)
+
+	// Failed messages have neither time_acked nor time_next. Setting
+	// time_next makes them due, and an epoch of 0 gives them all
+	// their attempts again.
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("update %v set time_next = :time_next, epoch = 0 where time_acked is null and time_next is null", sqlparser.NewTableIdent(subFlags.Arg(1)))
+	bindVars := map[string]*querypb.BindVariable{
+		"time_next": sqltypes.Int64BindVariable(time.Now().UnixNano()),
+	}
+	if *ids != "" {
+		buf.Myprintf(" and id in ::ids")
+		idbvs := &querypb.BindVariable{Type: querypb.Type_TUPLE}
+		for _, id := range strings.Split(*ids, ",") {
+			idbvs.Values = append(idbvs.Values, &querypb.Value{
+				Type:  querypb.Type_VARCHAR,
+				Value: []byte(strings.TrimSpace(id)),
+			})
+		}
+		bindVars["ids"] = idbvs
+	}
+	query := buf.String()
+
+	if *username != "" {
+		ctx = callerid.NewContext(ctx,
+			callerid.NewEffectiveCallerID("vtctl", "" /* component */, "" /* subComponent */),
+			callerid.NewImmediateCallerID(*username))
+	}
+
+	shards, err := wr.TopoServer().GetShardNames(ctx, keyspace)
+	if err != nil {
+		return err
+	}
+	sort.Strings(shards)
+	for _, shard := range shards {
+		si, err := wr.TopoServer().GetShard(ctx, keyspace, shard)
+		if err != nil {
+			return err
+		}
+		if !si.HasMaster() {
+			return fmt.Errorf("no master in shard %v/%v", keyspace, shard)
+		}
+		tabletInfo, err := wr.TopoServer().GetTablet(ctx, si.MasterAlias)
+		if err != nil {
+			return err
+		}
+		conn, err := tabletconn.GetDialer()(tabletInfo.Tablet, grpcclient.FailFast(false))
+		if err != nil {
+			return fmt.Errorf("cannot connect to tablet %v: %v", topoproto.TabletAliasString(si.MasterAlias), err)
+		}
+		qr, err := conn.Execute(ctx, &querypb.Target{
+			Keyspace:   keyspace,
+			Shard:      shard,
+			TabletType: topodatapb.TabletType_MASTER,
+		}, query, bindVars, 0, nil)
+		conn.Close(ctx)
+		if err != nil {
+			return fmt.Errorf("requeue failed on shard %v/%v: %v", keyspace, shard, err)
+		}
+		wr.Logger().Printf("%v/%v: %v messages requeued\n", keyspace, shard, qr.RowsAffected)
+	}
+	return nil
+}
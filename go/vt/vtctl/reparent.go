@@ -49,8 +49,8 @@ func init() {
 	addCommand("Shards", command{
 		"EmergencyReparentShard",
 		commandEmergencyReparentShard,
-		"-keyspace_shard=<keyspace/shard> -new_master=<tablet alias>",
-		"Reparents the shard to the new master. Assumes the old master is dead and not responsding."})
+		"-keyspace_shard=<keyspace/shard> [-new_master=<tablet alias>] [-quarantine_errant_replicas]",
+		"Reparents the shard to the new master. Assumes the old master is dead and not responsding. Without -new_master, the most advanced replica is chosen, preferably in the cell of the old master and a semi-sync acker. Replicas with errant GTIDs make it fail, unless -quarantine_errant_replicas is set."})
 }
 
 func commandReparentTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
@@ -145,7 +145,8 @@ func commandEmergencyReparentShard(ctx context.Context, wr *wrangler.Wrangler, s
 
 	waitSlaveTimeout := subFlags.Duration("wait_slave_timeout", 30*time.Second, "time to wait for slaves to catch up in reparenting")
 	keyspaceShard := subFlags.String("keyspace_shard", "", "keyspace/shard of the shard that needs to be reparented")
-	newMaster := subFlags.String("new_master", "", "alias of a tablet that should be the new master")
+	newMaster := subFlags.String("new_master", "", "alias of a tablet that should be the new master, the most advanced replica if empty")
+	quarantineErrantReplicas := subFlags.Bool("quarantine_errant_replicas", false, "change the replicas with errant GTIDs to drained instead of failing, when choosing the new master")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -157,16 +158,19 @@ func commandEmergencyReparentShard(ctx context.Context, wr *wrangler.Wrangler, s
 		*keyspaceShard = subFlags.Arg(0)
 		*newMaster = subFlags.Arg(1)
 	} else if subFlags.NArg() != 0 {
-		return fmt.Errorf("action EmergencyReparentShard requires -keyspace_shard=<keyspace/shard> -new_master=<tablet alias>")
+		return fmt.Errorf("action EmergencyReparentShard requires -keyspace_shard=<keyspace/shard> [-new_master=<tablet alias>]")
 	}
 
 	keyspace, shard, err := topoproto.ParseKeyspaceShard(*keyspaceShard)
 	if err != nil {
 		return err
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(*newMaster)
-	if err != nil {
-		return err
+	var tabletAlias *topodatapb.TabletAlias
+	if *newMaster != "" {
+		tabletAlias, err = topoproto.ParseTabletAlias(*newMaster)
+		if err != nil {
+			return err
+		}
 	}
-	return wr.EmergencyReparentShard(ctx, keyspace, shard, tabletAlias, *waitSlaveTimeout)
+	return wr.EmergencyReparentShard(ctx, keyspace, shard, tabletAlias, *waitSlaveTimeout, *quarantineErrantReplicas)
 }
@@ -17,15 +17,20 @@ limitations under the License.
 package vtctl
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"golang.org/x/net/context"
 
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/helpers"
 	"vitess.io/vitess/go/vt/wrangler"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -50,6 +55,36 @@ func init() {
 		commandTopoCp,
 		"[-cell <cell>] [-to_topo] <src> <dst>",
 		"Copies a file from topo to local file structure, or the other way around"})
+
+	addCommand(topoGroupName, command{
+		"TopoSnapshot",
+		commandTopoSnapshot,
+		"[-cells <cell1>,<cell2>,...] <file>",
+		"Writes all the files of the global topology and of the cells (all of them by default) to a local snapshot file, with their versions. Locks and master elections are not included."})
+
+	addCommand(topoGroupName, command{
+		"TopoSnapshotDiff",
+		commandTopoSnapshotDiff,
+		"<from file> [<to file>]",
+		"Displays the files that are different between two snapshot files. With only one snapshot file, compares it with the current topology, for the same cells."})
+
+	addCommand(topoGroupName, command{
+		"TopoRestore",
+		commandTopoRestore,
+		"[-cell <cell>] [-delete] [-dry_run] <file> <path> [<path>...]",
+		"Restores files from a snapshot file. Each path is a file, or a directory to restore recursively ('/' for the whole cell). Only the files that are different are written. With -delete, the files under the paths that are not in the snapshot are deleted."})
+
+	addCommand(topoGroupName, command{
+		"TopoAuditLog",
+		commandTopoAuditLog,
+		"[-since <duration>] [-cell <cell>] [-path <path>] [-caller <caller>] [-limit <count>] [-diff] [-json]",
+		"Displays the changes recorded in the topology audit log, oldest first. The audit log is written by the processes started with -topo_audit_log."})
+
+	addCommand(topoGroupName, command{
+		"TopoPruneAuditLog",
+		commandTopoPruneAuditLog,
+		"[-older_than <duration>]",
+		"Deletes the days of the topology audit log older than the duration. The current day is never deleted."})
 }
 
 // DecodeContent uses the filename to imply a type, and proto-decodes
@@ -179,3 +214,182 @@ func copyFileToTopo(ctx context.Context, ts *topo.Server, cell, from, to string)
 	_, err = conn.Update(ctx, to, data, nil)
 	return err
 }
+
+func commandTopoSnapshot(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	cellsStr := subFlags.String("cells", "", "comma-separated list of the cells to include. Defaults to all the cells.")
+	subFlags.Parse(args)
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("TopoSnapshot: need the snapshot file")
+	}
+	var cells []string
+	if *cellsStr != "" {
+		cells = strings.Split(*cellsStr, ",")
+	}
+
+	snapshot, err := helpers.TakeSnapshot(ctx, wr.TopoServer(), cells)
+	if err != nil {
+		return fmt.Errorf("TopoSnapshot: %v", err)
+	}
+	if err := writeSnapshotFile(subFlags.Arg(0), snapshot); err != nil {
+		return fmt.Errorf("TopoSnapshot: %v", err)
+	}
+	wr.Logger().Printf("Wrote %v files to %v\n", len(snapshot.Files), subFlags.Arg(0))
+	return nil
+}
+
+func commandTopoSnapshotDiff(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	subFlags.Parse(args)
+	if subFlags.NArg() != 1 && subFlags.NArg() != 2 {
+		return fmt.Errorf("TopoSnapshotDiff: need one or two snapshot files")
+	}
+	from, err := readSnapshotFile(subFlags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("TopoSnapshotDiff: %v", err)
+	}
+
+	var to *helpers.Snapshot
+	if subFlags.NArg() == 2 {
+		to, err = readSnapshotFile(subFlags.Arg(1))
+	} else {
+		// Compare with the current topology, for the cells
+		// of the snapshot.
+		cellSet := make(map[string]bool)
+		for _, f := range from.Files {
+			cellSet[f.Cell] = true
+		}
+		cells := make([]string, 0, len(cellSet))
+		for cell := range cellSet {
+			cells = append(cells, cell)
+		}
+		to, err = helpers.TakeSnapshot(ctx, wr.TopoServer(), cells)
+	}
+	if err != nil {
+		return fmt.Errorf("TopoSnapshotDiff: %v", err)
+	}
+
+	for _, d := range helpers.DiffSnapshots(from, to) {
+		wr.Logger().Printf("%v\n", d)
+	}
+	return nil
+}
+
+func commandTopoRestore(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	cell := subFlags.String("cell", topo.GlobalCell, "topology cell to restore. Defaults to global cell.")
+	deleteFiles := subFlags.Bool("delete", false, "also delete the files under the paths that are not in the snapshot")
+	dryRun := subFlags.Bool("dry_run", false, "only display the changes, don't make them")
+	subFlags.Parse(args)
+	if subFlags.NArg() < 2 {
+		return fmt.Errorf("TopoRestore: need the snapshot file, and the paths to restore")
+	}
+	snapshot, err := readSnapshotFile(subFlags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("TopoRestore: %v", err)
+	}
+
+	changes, err := helpers.RestoreSnapshot(ctx, wr.TopoServer(), snapshot, helpers.RestoreOptions{
+		Cell:   *cell,
+		Paths:  subFlags.Args()[1:],
+		Delete: *deleteFiles,
+		DryRun: *dryRun,
+	})
+	// The changes are the differences from the current topology
+	// to the snapshot.
+	for _, d := range changes {
+		switch d.Kind {
+		case helpers.DiffAdded:
+			wr.Logger().Printf("restore %v:%v\n", d.To.Cell, d.To.Path)
+		case helpers.DiffChanged:
+			wr.Logger().Printf("restore %v:%v (replacing version %v)\n", d.To.Cell, d.To.Path, d.From.Version)
+		case helpers.DiffRemoved:
+			wr.Logger().Printf("delete  %v:%v\n", d.From.Cell, d.From.Path)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("TopoRestore: %v", err)
+	}
+	return nil
+}
+
+func writeSnapshotFile(fileName string, snapshot *helpers.Snapshot) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if err := snapshot.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func readSnapshotFile(fileName string) (*helpers.Snapshot, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return helpers.ReadSnapshot(f)
+}
+
+func commandTopoAuditLog(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	since := subFlags.Duration("since", 24*time.Hour, "only display the changes made during that time")
+	cell := subFlags.String("cell", "", "only display the changes of this cell")
+	filePath := subFlags.String("path", "", "only display the changes of this file, or of the files in this directory")
+	caller := subFlags.String("caller", "", "only display the changes made by this caller (principal or username)")
+	limit := subFlags.Int("limit", 100, "maximum number of changes to display, the most recent ones. 0 for no limit.")
+	showDiff := subFlags.Bool("diff", false, "also display the differences between the old and new contents")
+	asJSON := subFlags.Bool("json", false, "display the entries as JSON, one per line")
+	subFlags.Parse(args)
+	if subFlags.NArg() != 0 {
+		return fmt.Errorf("TopoAuditLog doesn't take any parameter")
+	}
+
+	entries, err := wr.TopoServer().GetAuditLog(ctx, topo.AuditFilter{
+		Since:  time.Now().Add(-*since),
+		Cell:   *cell,
+		Path:   *filePath,
+		Caller: *caller,
+		Limit:  *limit,
+	})
+	if err != nil {
+		return fmt.Errorf("TopoAuditLog: %v", err)
+	}
+	for _, e := range entries {
+		if *asJSON {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			wr.Logger().Printf("%s\n", data)
+			continue
+		}
+
+		caller := e.Principal
+		if caller == "" {
+			caller = e.Username
+		}
+		if caller == "" {
+			caller = "unknown"
+		}
+		wr.Logger().Printf("%v %v %v:%v version=%v->%v function=%v caller=%v process=%v@%v command=%q\n", e.Time.Format(time.RFC3339), e.Operation, e.Cell, e.Path, e.OldVersion, e.NewVersion, e.Function, caller, e.Process, e.Hostname, e.Command)
+		if *showDiff && e.Diff != "" {
+			wr.Logger().Printf("%v", e.Diff)
+		}
+	}
+	return nil
+}
+
+func commandTopoPruneAuditLog(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	olderThan := subFlags.Duration("older_than", 30*24*time.Hour, "delete the days of the audit log before this duration ago")
+	subFlags.Parse(args)
+	if subFlags.NArg() != 0 {
+		return fmt.Errorf("TopoPruneAuditLog doesn't take any parameter")
+	}
+
+	count, err := wr.TopoServer().PruneAuditLog(ctx, time.Now().Add(-*olderThan))
+	if err != nil {
+		return fmt.Errorf("TopoPruneAuditLog: deleted %v entries before error: %v", count, err)
+	}
+	wr.Logger().Printf("Deleted %v topology audit log entries.\n", count)
+	return nil
+}
@@ -118,6 +118,7 @@ import (
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/schemamanager"
+	"vitess.io/vitess/go/vt/schemamanager/onlineddl"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
@@ -183,6 +184,9 @@ var commands = []commandGroup{
 			{"StopSlave", commandStopSlave,
 				"<tablet alias>",
 				"Stops replication on the specified slave."},
+			{"FastForwardDelayedReplica", commandFastForwardDelayedReplica,
+				"[-wait_timeout=<duration>] <tablet alias> <position>",
+				"Makes a delayed replica apply its relay logs up to the specified position, ignoring its delay, and stop replication there. Use it to recover the data right before a bad write. Replication is delayed again when the tablet is reparented."},
 			{"ChangeSlaveType", commandChangeSlaveType,
 				"[-dry-run] <tablet alias> <tablet type>",
 				"Changes the db type for the specified tablet, if possible. This command is used primarily to arrange replicas, and it will not convert a master.\n" +
@@ -355,8 +359,20 @@ var commands = []commandGroup{
 				"[-exclude_tables=''] [-include-views] <keyspace name>",
 				"Validates that the master schema from shard 0 matches the schema on all of the other tablets in the keyspace."},
 			{"ApplySchema", commandApplySchema,
-				"[-allow_long_unavailability] [-wait_slave_timeout=10s] {-sql=<sql> || -sql-file=<filename>} <keyspace>",
-				"Applies the schema change to the specified keyspace on every master, running in parallel on all shards. The changes are then propagated to slaves via replication. If -allow_long_unavailability is set, schema changes affecting a large number of rows (and possibly incurring a longer period of unavailability) will not be rejected."},
+				"[-allow_long_unavailability] [-online] [-async] [-wait_slave_timeout=10s] {-sql=<sql> || -sql-file=<filename>} <keyspace>",
+				"Applies the schema change to the specified keyspace on every master, running in parallel on all shards. The changes are then propagated to slaves via replication. If -allow_long_unavailability is set, schema changes affecting a large number of rows (and possibly incurring a longer period of unavailability) will not be rejected. If -online is set, a single ALTER TABLE is applied by the online schema change workflow, which copies the rows to a shadow table and swaps the tables without locking the original one; the uuid of the workflow is printed. If -async is set, the schema change is added to the migration queue of the keyspace and executed later by vtctld; the id of the migration is printed."},
+			{"GetSchemaMigrations", commandGetSchemaMigrations,
+				"<keyspace>",
+				"Displays the schema migrations queued with 'ApplySchema -async' for the specified keyspace, with their status on every shard."},
+			{"CancelSchemaMigration", commandCancelSchemaMigration,
+				"<keyspace> <migration id>",
+				"Cancels a schema migration that has not started yet, or a running schema migration whose vtctld stopped sending heartbeats. The statements of such a migration may have been applied on some shards already."},
+			{"RetrySchemaMigration", commandRetrySchemaMigration,
+				"<keyspace> <migration id>",
+				"Queues a failed schema migration, or a running schema migration whose vtctld stopped sending heartbeats, again. It is only executed on the shards where it did not complete, starting from the first statement they have not applied."},
+			{"SyncSchema", commandSyncSchema,
+				"[-apply] [-allow_long_unavailability] [-wait_slave_timeout=10s] -schema_dir=<dir> <keyspace>",
+				"Compares the schema of every shard master of the keyspace with the CREATE TABLE statements of the .sql files in -schema_dir, and displays the CREATE, ALTER and DROP TABLE statements that make them identical, grouped by shards needing the same changes. Views are ignored. If -apply is set, the statements are applied to the shards with the same checks as ApplySchema."},
 			{"CopySchemaShard", commandCopySchemaShard,
 				"[-tables=<table1>,<table2>,...] [-exclude_tables=<table1>,<table2>,...] [-include-views] [-wait_slave_timeout=10s] {<source keyspace/shard> || <source tablet alias>} <destination keyspace/shard>",
 				"Copies the schema from a source shard's master (or a specific tablet) to a destination shard. The schema is applied directly on the master of the destination shard, and it is propagated to the replicas through binlogs."},
@@ -835,6 +851,24 @@ func commandStopSlave(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag
 	return wr.TabletManagerClient().StopSlave(ctx, ti.Tablet)
 }
 
+func commandFastForwardDelayedReplica(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	waitTimeout := subFlags.Duration("wait_timeout", time.Hour, "time to wait for the replica to reach the position")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 2 {
+		return fmt.Errorf("action FastForwardDelayedReplica requires <tablet alias> <position>")
+	}
+
+	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, *waitTimeout)
+	defer cancel()
+	return wr.FastForwardDelayedReplica(ctx, tabletAlias, subFlags.Arg(1))
+}
+
 func commandChangeSlaveType(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	dryRun := subFlags.Bool("dry-run", false, "Lists the proposed change without actually executing it")
 
@@ -1890,6 +1924,8 @@ func commandApplySchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *fl
 	allowLongUnavailability := subFlags.Bool("allow_long_unavailability", false, "Allow large schema changes which incur a longer unavailability of the database.")
 	sql := subFlags.String("sql", "", "A list of semicolon-delimited SQL commands")
 	sqlFile := subFlags.String("sql-file", "", "Identifies the file that contains the SQL commands")
+	online := subFlags.Bool("online", false, "Applies the ALTER TABLE as an online schema change workflow, without locking the table.")
+	async := subFlags.Bool("async", false, "Adds the schema change to the migration queue of the keyspace instead of applying it right away.")
 	waitSlaveTimeout := subFlags.Duration("wait_slave_timeout", wrangler.DefaultWaitSlaveTimeout, "The amount of time to wait for slaves to receive the schema change via replication.")
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -1904,6 +1940,30 @@ func commandApplySchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *fl
 		return err
 	}
 
+	if *online && *async {
+		return fmt.Errorf("-online and -async cannot be used together")
+	}
+	if *online {
+		if WorkflowManager == nil {
+			return fmt.Errorf("no workflow.Manager registered, online schema changes can only be run from vtctld")
+		}
+		uuid, err := WorkflowManager.Create(ctx, onlineddl.WorkflowFactoryName, []string{"-keyspace", keyspace, "-sql", change})
+		if err != nil {
+			return err
+		}
+		wr.Logger().Printf("uuid: %v\n", uuid)
+		return WorkflowManager.Start(ctx, uuid)
+	}
+
+	if *async {
+		m, err := schemamanager.SubmitMigration(ctx, wr.TopoServer(), keyspace, change, *allowLongUnavailability)
+		if err != nil {
+			return err
+		}
+		wr.Logger().Printf("migration id: %v\n", m.ID)
+		return nil
+	}
+
 	executor := schemamanager.NewTabletExecutor(wr, *waitSlaveTimeout)
 	if *allowLongUnavailability {
 		executor.AllowBigSchemaChange()
@@ -1915,6 +1975,123 @@ func commandApplySchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *fl
 	)
 }
 
+func commandGetSchemaMigrations(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace> argument is required for the GetSchemaMigrations command")
+	}
+	migrations, err := schemamanager.GetMigrations(ctx, wr.TopoServer(), subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	return printJSON(wr.Logger(), migrations)
+}
+
+func commandCancelSchemaMigration(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 2 {
+		return fmt.Errorf("the <keyspace> and <migration id> arguments are required for the CancelSchemaMigration command")
+	}
+	_, err := schemamanager.CancelMigration(ctx, wr.TopoServer(), subFlags.Arg(0), subFlags.Arg(1))
+	return err
+}
+
+func commandRetrySchemaMigration(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 2 {
+		return fmt.Errorf("the <keyspace> and <migration id> arguments are required for the RetrySchemaMigration command")
+	}
+	_, err := schemamanager.RetryMigration(ctx, wr.TopoServer(), subFlags.Arg(0), subFlags.Arg(1))
+	return err
+}
+
+func commandSyncSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	schemaDir := subFlags.String("schema_dir", "", "The directory containing the CREATE TABLE statements of the desired schema, in .sql files")
+	apply := subFlags.Bool("apply", false, "Applies the schema changes instead of only displaying them")
+	allowLongUnavailability := subFlags.Bool("allow_long_unavailability", false, "Allow large schema changes which incur a longer unavailability of the database.")
+	waitSlaveTimeout := subFlags.Duration("wait_slave_timeout", wrangler.DefaultWaitSlaveTimeout, "The amount of time to wait for slaves to receive the schema change via replication.")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 || *schemaDir == "" {
+		return fmt.Errorf("the <keyspace> argument and -schema_dir are required for the SyncSchema command")
+	}
+	keyspace := subFlags.Arg(0)
+	desired, err := schemamanager.ReadSchemaDir(*schemaDir)
+	if err != nil {
+		return err
+	}
+
+	shards, err := wr.TopoServer().GetShardNames(ctx, keyspace)
+	if err != nil {
+		return err
+	}
+	sort.Strings(shards)
+	// The shards needing the same statements are grouped together, so
+	// the statements are displayed and applied once per group.
+	groups := make(map[string][]string)
+	groupSqls := make(map[string][]string)
+	var keys []string
+	for _, shard := range shards {
+		si, err := wr.TopoServer().GetShard(ctx, keyspace, shard)
+		if err != nil {
+			return err
+		}
+		if !si.HasMaster() {
+			return fmt.Errorf("no master in shard %v/%v", keyspace, shard)
+		}
+		sd, err := wr.GetSchema(ctx, si.MasterAlias, nil, nil, false)
+		if err != nil {
+			return fmt.Errorf("cannot get the schema of %v: %v", topoproto.TabletAliasString(si.MasterAlias), err)
+		}
+		sqls, err := schemamanager.DiffSchemaToDDLs(sd, desired)
+		if err != nil {
+			return fmt.Errorf("shard %v/%v: %v", keyspace, shard, err)
+		}
+		key := strings.Join(sqls, ";\n")
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+			groupSqls[key] = sqls
+		}
+		groups[key] = append(groups[key], shard)
+	}
+
+	for _, key := range keys {
+		if len(groupSqls[key]) == 0 {
+			wr.Logger().Printf("Shards %v: schema is up to date\n", strings.Join(groups[key], ", "))
+			continue
+		}
+		wr.Logger().Printf("Shards %v:\n", strings.Join(groups[key], ", "))
+		for _, sql := range groupSqls[key] {
+			wr.Logger().Printf("  %v;\n", sql)
+		}
+	}
+	if !*apply {
+		return nil
+	}
+
+	for _, key := range keys {
+		if len(groupSqls[key]) == 0 {
+			continue
+		}
+		executor := schemamanager.NewTabletExecutor(wr, *waitSlaveTimeout)
+		if *allowLongUnavailability {
+			executor.AllowBigSchemaChange()
+		}
+		executor.SetShards(groups[key])
+		if err := schemamanager.Run(ctx, schemamanager.NewPlainController(key, keyspace), executor); err != nil {
+			return fmt.Errorf("cannot apply the schema changes to shards %v: %v", strings.Join(groups[key], ", "), err)
+		}
+	}
+	return nil
+}
+
 func commandCopySchemaShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	tables := subFlags.String("tables", "", "Specifies a comma-separated list of tables to copy. Each is either an exact match, or a regular expression of the form /regexp/")
 	excludeTables := subFlags.String("exclude_tables", "", "Specifies a comma-separated list of tables to exclude. Each is either an exact match, or a regular expression of the form /regexp/")
@@ -2329,6 +2506,9 @@ func RunCommand(ctx context.Context, wr *wrangler.Wrangler, args []string) error
 		return fmt.Errorf("no command was specified")
 	}
 
+	// Record the command in the topology audit log entries.
+	ctx = topo.NewAuditContext(ctx, strings.Join(args, " "))
+
 	action := args[0]
 	actionLowerCase := strings.ToLower(action)
 	for _, group := range commands {
@@ -24,11 +24,13 @@ import (
 
 	"vitess.io/vitess/go/flagutil"
 	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/schemamanager/onlineddl"
 	"vitess.io/vitess/go/vt/schemamanager/schemaswap"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vtctl"
 	"vitess.io/vitess/go/vt/workflow"
+	"vitess.io/vitess/go/vt/workflow/materializer"
 	"vitess.io/vitess/go/vt/workflow/resharding"
 	"vitess.io/vitess/go/vt/workflow/topovalidator"
 )
@@ -56,9 +58,15 @@ func initWorkflowManager(ts *topo.Server) {
 		// Register the Schema Swap workflow.
 		schemaswap.RegisterWorkflowFactory()
 
+		// Register the Online Schema Change workflow.
+		onlineddl.Register()
+
 		// Register the Horizontal Resharding workflow.
 		resharding.Register()
 
+		// Register the Materialization workflow.
+		materializer.Register()
+
 		// Unregister the blacklisted workflows.
 		for _, name := range workflowManagerDisable {
 			workflow.Unregister(name)
@@ -126,11 +126,11 @@ func (t *explainTablet) Begin(ctx context.Context, target *querypb.Target, optio
 }
 
 // Commit is part of the QueryService interface.
-func (t *explainTablet) Commit(ctx context.Context, target *querypb.Target, transactionID int64) error {
+func (t *explainTablet) Commit(ctx context.Context, target *querypb.Target, transactionID int64, includeGTIDExecuted bool) (string, error) {
 	t.mu.Lock()
 	t.currentTime = batchTime.Wait()
 	t.mu.Unlock()
-	return t.tsv.Commit(ctx, target, transactionID)
+	return t.tsv.Commit(ctx, target, transactionID, includeGTIDExecuted)
 }
 
 // Rollback is part of the QueryService interface.
@@ -168,11 +168,11 @@ func (t *explainTablet) Prepare(ctx context.Context, target *querypb.Target, tra
 }
 
 // CommitPrepared commits the prepared transaction.
-func (t *explainTablet) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string) (err error) {
+func (t *explainTablet) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string, includeGTIDExecuted bool) (string, error) {
 	t.mu.Lock()
 	t.currentTime = batchTime.Wait()
 	t.mu.Unlock()
-	return t.tsv.CommitPrepared(ctx, target, dtid)
+	return t.tsv.CommitPrepared(ctx, target, dtid, includeGTIDExecuted)
 }
 
 // CreateTransaction is part of the QueryService interface.
@@ -184,11 +184,11 @@ func (t *explainTablet) CreateTransaction(ctx context.Context, target *querypb.T
 }
 
 // StartCommit is part of the QueryService interface.
-func (t *explainTablet) StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string) (err error) {
+func (t *explainTablet) StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string, includeGTIDExecuted bool) (string, error) {
 	t.mu.Lock()
 	t.currentTime = batchTime.Wait()
 	t.mu.Unlock()
-	return t.tsv.StartCommit(ctx, target, transactionID, dtid)
+	return t.tsv.StartCommit(ctx, target, transactionID, dtid, includeGTIDExecuted)
 }
 
 // SetRollback is part of the QueryService interface.
@@ -0,0 +1,274 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdc
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+var (
+	retryDelay         = flag.Duration("cdc_retry_delay", 5*time.Second, "time to wait before resuming the change stream of a shard after an error")
+	shardCheckInterval = flag.Duration("cdc_shard_check_interval", 30*time.Second, "how often a change stream checks the serving shards of its keyspace did not change, a stream fails when they do since it cannot follow resharding")
+)
+
+// errStopStream stops the stream that reads the current position of a
+// master.
+var errStopStream = errors.New("stop stream")
+
+// shardEvent is an event read from a shard.
+type shardEvent struct {
+	shard string
+	event *querypb.ChangeEvent
+}
+
+// Streamer merges the change streams of the shards of a keyspace. The
+// events of a shard are in order, and are read through the gateway
+// from a tablet of the given type. When the stream of a shard fails,
+// for instance during a reparent, it resumes from the last position
+// sent, on whichever tablet the gateway picks.
+//
+// A stream does not follow resharding: the positions of the source
+// shards mean nothing on the destination shards. A stream fails if the
+// serving shards of the keyspace are not the ones it started with, and
+// a VGtid can only be used while its shards are serving.
+type Streamer struct {
+	srvTopo    srvtopo.Server
+	gateway    queryservice.QueryService
+	cell       string
+	keyspace   string
+	tabletType topodatapb.TabletType
+}
+
+// NewStreamer returns a Streamer for the given keyspace.
+func NewStreamer(srvTopo srvtopo.Server, gateway queryservice.QueryService, cell, keyspace string, tabletType topodatapb.TabletType) *Streamer {
+	return &Streamer{
+		srvTopo:    srvTopo,
+		gateway:    gateway,
+		cell:       cell,
+		keyspace:   keyspace,
+		tabletType: tabletType,
+	}
+}
+
+// Stream streams the changes made after vgtid until ctx is done or send
+// returns an error. If vgtid is empty, the stream starts at the current
+// position of the masters of the serving shards. Those positions are
+// all read before anything is sent, so every VGtid sent has a position
+// for each shard. A tablet that is behind its master fails to stream
+// from the master position until it catches up: its shard is retried
+// every -cdc_retry_delay. The stream fails if the serving shards of the
+// keyspace change, which is checked every -cdc_shard_check_interval.
+func (s *Streamer) Stream(ctx context.Context, vgtid *vtgatepb.VGtid, send func(*vtgatepb.StreamChangesResponse) error) error {
+	shards, err := s.servingShards(ctx)
+	if err != nil {
+		return err
+	}
+	var positions map[string]string
+	if len(vgtid.GetShardGtids()) == 0 {
+		positions, err = s.masterPositions(ctx, shards)
+	} else {
+		positions, err = vgtidPositions(s.keyspace, vgtid)
+		if err == nil && !sameShards(positions, shards) {
+			err = fmt.Errorf("the shards of vgtid %v are not the serving %v shards of keyspace %v in cell %v (%v): the keyspace was resharded, start a new stream without vgtid", vgtid, topoproto.TabletTypeLString(s.tabletType), s.keyspace, s.cell, strings.Join(shards, ", "))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	// The shard streams are stopped before returning.
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	events := make(chan *shardEvent)
+	for shard, position := range positions {
+		wg.Add(1)
+		go func(shard, position string) {
+			defer wg.Done()
+			s.streamShard(ctx, shard, position, events)
+		}(shard, position)
+	}
+
+	shardCheck := time.NewTicker(*shardCheckInterval)
+	defer shardCheck.Stop()
+	for {
+		select {
+		case <-shardCheck.C:
+			current, err := s.servingShards(ctx)
+			if err != nil {
+				log.Warningf("Cannot check the serving shards of %v, keeping streaming: %v", s.keyspace, err)
+				continue
+			}
+			if !sameShards(positions, current) {
+				return fmt.Errorf("the serving %v shards of keyspace %v in cell %v changed to %v: the change stream cannot follow resharding, start a new stream without vgtid", topoproto.TabletTypeLString(s.tabletType), s.keyspace, s.cell, strings.Join(current, ", "))
+			}
+		case ev := <-events:
+			positions[ev.shard] = ev.event.Position
+			if err := send(&vtgatepb.StreamChangesResponse{
+				Shard: ev.shard,
+				Event: ev.event,
+				Vgtid: newVGtid(s.keyspace, positions),
+			}); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// servingShards returns the shards of the keyspace serving the tablet
+// type of the stream in its cell, sorted.
+func (s *Streamer) servingShards(ctx context.Context) ([]string, error) {
+	srvKeyspace, err := s.srvTopo.GetSrvKeyspace(ctx, s.cell, s.keyspace)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the serving keyspace %v in cell %v: %v", s.keyspace, s.cell, err)
+	}
+	partition := topoproto.SrvKeyspaceGetPartition(srvKeyspace, s.tabletType)
+	if partition == nil {
+		return nil, fmt.Errorf("keyspace %v has no %v shards in cell %v", s.keyspace, topoproto.TabletTypeLString(s.tabletType), s.cell)
+	}
+	shards := make([]string, 0, len(partition.ShardReferences))
+	for _, sr := range partition.ShardReferences {
+		shards = append(shards, sr.Name)
+	}
+	sort.Strings(shards)
+	return shards, nil
+}
+
+// sameShards returns true if positions has a position for each of
+// shards, and for no other shard.
+func sameShards(positions map[string]string, shards []string) bool {
+	if len(positions) != len(shards) {
+		return false
+	}
+	for _, shard := range shards {
+		if _, ok := positions[shard]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// masterPositions returns the current position of the masters of
+// shards.
+func (s *Streamer) masterPositions(ctx context.Context, shards []string) (map[string]string, error) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	rec := concurrency.AllErrorRecorder{}
+	positions := make(map[string]string)
+	for _, shard := range shards {
+		wg.Add(1)
+		go func(shard string) {
+			defer wg.Done()
+			position, err := s.masterPosition(ctx, shard)
+			if err != nil {
+				rec.RecordError(err)
+				return
+			}
+			mu.Lock()
+			positions[shard] = position
+			mu.Unlock()
+		}(shard)
+	}
+	wg.Wait()
+	if rec.HasErrors() {
+		return nil, rec.Error()
+	}
+	return positions, nil
+}
+
+// masterPosition returns the current position of the master of a shard:
+// a stream started without a position sends it first.
+func (s *Streamer) masterPosition(ctx context.Context, shard string) (string, error) {
+	target := &querypb.Target{
+		Keyspace:   s.keyspace,
+		Shard:      shard,
+		TabletType: topodatapb.TabletType_MASTER,
+	}
+	var position string
+	err := s.gateway.StreamChanges(ctx, target, "", func(event *querypb.ChangeEvent) error {
+		position = event.Position
+		return errStopStream
+	})
+	if err != nil && err != errStopStream {
+		return "", fmt.Errorf("cannot read the position of the master of %v/%v: %v", s.keyspace, shard, err)
+	}
+	if position == "" {
+		return "", fmt.Errorf("the master of %v/%v did not send its position", s.keyspace, shard)
+	}
+	return position, nil
+}
+
+// streamShard streams the changes of a shard to events, starting at
+// position, until ctx is done. An event is handed over to the merging
+// loop before the next one is read, so after an error the stream
+// resumes right after the last event sent.
+func (s *Streamer) streamShard(ctx context.Context, shard, position string, events chan<- *shardEvent) {
+	target := &querypb.Target{
+		Keyspace:   s.keyspace,
+		Shard:      shard,
+		TabletType: s.tabletType,
+	}
+	for {
+		err := s.gateway.StreamChanges(ctx, target, position, func(event *querypb.ChangeEvent) error {
+			if event.Position == "" {
+				return fmt.Errorf("event without position")
+			}
+			select {
+			case events <- &shardEvent{shard: shard, event: event}:
+				position = event.Position
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err == nil {
+			err = fmt.Errorf("the tablet ended the stream")
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warningf("Change stream of %v/%v stopped at %v, resuming in %v: %v", s.keyspace, shard, position, *retryDelay, err)
+		select {
+		case <-time.After(*retryDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+// fakeGateway serves the changes of the shards of a keyspace. The
+// position of an event is its index in its shard, and the masters are
+// at position 0. The first stream of each shard fails after one event.
+type fakeGateway struct {
+	queryservice.QueryService
+
+	mu            sync.Mutex
+	streams       map[string]int
+	requests      []string
+	failingMaster string
+}
+
+func (fg *fakeGateway) StreamChanges(ctx context.Context, target *querypb.Target, position string, callback func(*querypb.ChangeEvent) error) error {
+	fg.mu.Lock()
+	fg.requests = append(fg.requests, fmt.Sprintf("%v/%v@%q", target.Shard, target.TabletType, position))
+	fg.mu.Unlock()
+
+	if position == "" {
+		if target.TabletType != topodatapb.TabletType_MASTER {
+			return fmt.Errorf("stream of a %v without a position", target.TabletType)
+		}
+		if target.Shard == fg.failingMaster {
+			return fmt.Errorf("no master")
+		}
+		return callback(&querypb.ChangeEvent{Position: "0"})
+	}
+
+	fg.mu.Lock()
+	fg.streams[target.Shard]++
+	first := fg.streams[target.Shard] == 1
+	fg.mu.Unlock()
+
+	var start int
+	fmt.Sscanf(position, "%d", &start)
+	for i := start + 1; i <= 3; i++ {
+		if err := callback(&querypb.ChangeEvent{
+			Position: fmt.Sprintf("%d", i),
+			Changes: []*querypb.RowChange{{
+				Type: querypb.RowChange_INSERT,
+				Sql:  []byte(fmt.Sprintf("%v-%d", target.Shard, i)),
+			}},
+		}); err != nil {
+			return err
+		}
+		if first {
+			return fmt.Errorf("reparented")
+		}
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// fakeSrvTopo serves the SrvKeyspace of keyspace ks in cell1, with
+// shards -80 and 80- until setShards changes them.
+type fakeSrvTopo struct {
+	srvtopo.Server
+
+	mu     sync.Mutex
+	shards []string
+}
+
+func newFakeSrvTopo() *fakeSrvTopo {
+	return &fakeSrvTopo{shards: []string{"-80", "80-"}}
+}
+
+func (fst *fakeSrvTopo) setShards(shards ...string) {
+	fst.mu.Lock()
+	defer fst.mu.Unlock()
+	fst.shards = shards
+}
+
+func (fst *fakeSrvTopo) GetSrvKeyspace(ctx context.Context, cell, keyspace string) (*topodatapb.SrvKeyspace, error) {
+	if cell != "cell1" || keyspace != "ks" {
+		return nil, fmt.Errorf("no keyspace %v in cell %v", keyspace, cell)
+	}
+	fst.mu.Lock()
+	defer fst.mu.Unlock()
+	var srs []*topodatapb.ShardReference
+	for _, shard := range fst.shards {
+		srs = append(srs, &topodatapb.ShardReference{Name: shard})
+	}
+	return &topodatapb.SrvKeyspace{
+		Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{{
+			ServedType:      topodatapb.TabletType_REPLICA,
+			ShardReferences: srs,
+		}},
+	}, nil
+}
+
+func TestStreamer(t *testing.T) {
+	*retryDelay = 0
+	fg := &fakeGateway{streams: make(map[string]int)}
+	s := NewStreamer(newFakeSrvTopo(), fg, "cell1", "ks", topodatapb.TabletType_REPLICA)
+
+	// Stream until all the events of both shards are received.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var responses []*vtgatepb.StreamChangesResponse
+	got := make(map[string][]string)
+	err := s.Stream(ctx, nil, func(response *vtgatepb.StreamChangesResponse) error {
+		responses = append(responses, response)
+		got[response.Shard] = append(got[response.Shard], string(response.Event.Changes[0].Sql))
+		// Every vgtid has the position of both shards.
+		for _, sg := range response.Vgtid.ShardGtids {
+			if sg.Gtid == "" {
+				t.Errorf("vgtid without position: %v", response.Vgtid)
+			}
+		}
+		if len(responses) == 6 {
+			cancel()
+		}
+		return nil
+	})
+	if err != context.Canceled {
+		t.Fatalf("Stream: %v, want %v", err, context.Canceled)
+	}
+
+	// The events of each shard are in order, despite the errors.
+	for _, shard := range []string{"-80", "80-"} {
+		want := fmt.Sprintf("%v-1 %v-2 %v-3", shard, shard, shard)
+		if strings.Join(got[shard], " ") != want {
+			t.Errorf("events of %v: %v, want %v", shard, got[shard], want)
+		}
+	}
+
+	// The positions of the masters were read first, and the replicas
+	// streamed from there, resuming from the last position.
+	requests := strings.Join(fg.requests, " ")
+	for _, want := range []string{`-80/MASTER@""`, `-80/REPLICA@"0"`, `-80/REPLICA@"1"`, `80-/MASTER@""`, `80-/REPLICA@"0"`, `80-/REPLICA@"1"`} {
+		if !strings.Contains(requests, want) {
+			t.Errorf("requests: %v, want %v", requests, want)
+		}
+	}
+	if strings.Contains(requests, `REPLICA@""`) {
+		t.Errorf("requests: %v, want no stream without a position", requests)
+	}
+
+	// The last VGtid has the position of both shards, and resumes
+	// from there.
+	last := responses[len(responses)-1].Vgtid
+	if got, want := last.String(), newVGtid("ks", map[string]string{"-80": "3", "80-": "3"}).String(); got != want {
+		t.Errorf("last vgtid: %v, want %v", got, want)
+	}
+
+	// Resuming from a VGtid skips the events before it.
+	vgtid := newVGtid("ks", map[string]string{"-80": "2", "80-": "1"})
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	got = make(map[string][]string)
+	count := 0
+	s.Stream(ctx, vgtid, func(response *vtgatepb.StreamChangesResponse) error {
+		got[response.Shard] = append(got[response.Shard], string(response.Event.Changes[0].Sql))
+		count++
+		if count == 3 {
+			cancel()
+		}
+		return nil
+	})
+	if len(got["-80"]) != 1 || len(got["80-"]) != 2 {
+		t.Errorf("resumed events: %v", got)
+	}
+}
+
+func TestStreamerErrors(t *testing.T) {
+	fg := &fakeGateway{streams: make(map[string]int), failingMaster: "80-"}
+	ctx := context.Background()
+	send := func(*vtgatepb.StreamChangesResponse) error {
+		t.Errorf("unexpected event")
+		return nil
+	}
+
+	s := NewStreamer(newFakeSrvTopo(), fg, "cell1", "ks", topodatapb.TabletType_RDONLY)
+	if err := s.Stream(ctx, nil, send); err == nil || !strings.Contains(err.Error(), "keyspace ks has no rdonly shards in cell cell1") {
+		t.Errorf("Stream: %v", err)
+	}
+
+	// Nothing is streamed if the position of a master is unknown.
+	s = NewStreamer(newFakeSrvTopo(), fg, "cell1", "ks", topodatapb.TabletType_REPLICA)
+	if err := s.Stream(ctx, nil, send); err == nil || !strings.Contains(err.Error(), "cannot read the position of the master of ks/80-: no master") {
+		t.Errorf("Stream: %v", err)
+	}
+	if len(fg.streams) != 0 {
+		t.Errorf("streams: %v, want none", fg.streams)
+	}
+
+	vgtid := newVGtid("other", map[string]string{"0": "1"})
+	if err := s.Stream(ctx, vgtid, send); err == nil || !strings.Contains(err.Error(), "vgtid of keyspace other cannot be used to stream keyspace ks") {
+		t.Errorf("Stream: %v", err)
+	}
+}
+
+func TestStreamerResharding(t *testing.T) {
+	*retryDelay = 0
+	defer func(interval time.Duration) { *shardCheckInterval = interval }(*shardCheckInterval)
+	*shardCheckInterval = 10 * time.Millisecond
+	fst := newFakeSrvTopo()
+	fg := &fakeGateway{streams: make(map[string]int)}
+	s := NewStreamer(fst, fg, "cell1", "ks", topodatapb.TabletType_REPLICA)
+	ctx := context.Background()
+
+	// The stream fails once the serving shards change.
+	count := 0
+	err := s.Stream(ctx, nil, func(response *vtgatepb.StreamChangesResponse) error {
+		count++
+		if count == 1 {
+			fst.setShards("-40", "40-80", "80-")
+		}
+		return nil
+	})
+	want := "the serving replica shards of keyspace ks in cell cell1 changed to -40, 40-80, 80-: the change stream cannot follow resharding"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Stream: %v, want %v", err, want)
+	}
+
+	// A vgtid of shards that are no longer serving is rejected.
+	vgtid := newVGtid("ks", map[string]string{"-80": "1", "80-": "1"})
+	err = s.Stream(ctx, vgtid, func(*vtgatepb.StreamChangesResponse) error {
+		t.Errorf("unexpected event")
+		return nil
+	})
+	want = "are not the serving replica shards of keyspace ks in cell cell1 (-40, 40-80, 80-): the keyspace was resharded"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Stream: %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cdc merges the change data capture streams of the shards of a
+// keyspace, served by the vttablets, into a single stream.
+package cdc
+
+import (
+	"fmt"
+	"sort"
+
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+// vgtidPositions returns the positions of the shards of a VGtid. All
+// the shards must be in keyspace, and have a position.
+func vgtidPositions(keyspace string, vgtid *vtgatepb.VGtid) (map[string]string, error) {
+	positions := make(map[string]string)
+	for _, sg := range vgtid.GetShardGtids() {
+		if sg.Keyspace != keyspace {
+			return nil, fmt.Errorf("vgtid of keyspace %v cannot be used to stream keyspace %v", sg.Keyspace, keyspace)
+		}
+		if sg.Shard == "" || sg.Gtid == "" {
+			return nil, fmt.Errorf("invalid vgtid %v: shard and gtid are required", vgtid)
+		}
+		if _, ok := positions[sg.Shard]; ok {
+			return nil, fmt.Errorf("invalid vgtid %v: duplicate shard %v", vgtid, sg.Shard)
+		}
+		positions[sg.Shard] = sg.Gtid
+	}
+	return positions, nil
+}
+
+// newVGtid returns the VGtid of the positions of the shards of a
+// keyspace, sorted by shard.
+func newVGtid(keyspace string, positions map[string]string) *vtgatepb.VGtid {
+	vgtid := &vtgatepb.VGtid{
+		ShardGtids: make([]*vtgatepb.ShardGtid, 0, len(positions)),
+	}
+	for shard, gtid := range positions {
+		vgtid.ShardGtids = append(vgtid.ShardGtids, &vtgatepb.ShardGtid{
+			Keyspace: keyspace,
+			Shard:    shard,
+			Gtid:     gtid,
+		})
+	}
+	sort.Slice(vgtid.ShardGtids, func(i, j int) bool {
+		return vgtid.ShardGtids[i].Shard < vgtid.ShardGtids[j].Shard
+	})
+	return vgtid
+}
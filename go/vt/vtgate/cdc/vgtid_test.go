@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+func TestVGtid(t *testing.T) {
+	positions := map[string]string{
+		"80-": "MariaDB/0-1-2",
+		"-80": "MariaDB/0-1-1",
+	}
+	vgtid := newVGtid("ks", positions)
+	want := &vtgatepb.VGtid{
+		ShardGtids: []*vtgatepb.ShardGtid{{
+			Keyspace: "ks",
+			Shard:    "-80",
+			Gtid:     "MariaDB/0-1-1",
+		}, {
+			Keyspace: "ks",
+			Shard:    "80-",
+			Gtid:     "MariaDB/0-1-2",
+		}},
+	}
+	if !proto.Equal(vgtid, want) {
+		t.Errorf("newVGtid: %v, want %v", vgtid, want)
+	}
+	got, err := vgtidPositions("ks", vgtid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got["-80"] != positions["-80"] || got["80-"] != positions["80-"] {
+		t.Errorf("vgtidPositions: %v, want %v", got, positions)
+	}
+
+	testcases := []struct {
+		in  []*vtgatepb.ShardGtid
+		err string
+	}{{
+		in:  []*vtgatepb.ShardGtid{{Keyspace: "other", Shard: "0", Gtid: "MariaDB/0-1-1"}},
+		err: "vgtid of keyspace other cannot be used to stream keyspace ks",
+	}, {
+		in:  []*vtgatepb.ShardGtid{{Keyspace: "ks", Gtid: "MariaDB/0-1-1"}},
+		err: "shard and gtid are required",
+	}, {
+		in:  []*vtgatepb.ShardGtid{{Keyspace: "ks", Shard: "0"}},
+		err: "shard and gtid are required",
+	}, {
+		in: []*vtgatepb.ShardGtid{
+			{Keyspace: "ks", Shard: "0", Gtid: "MariaDB/0-1-1"},
+			{Keyspace: "ks", Shard: "0", Gtid: "MariaDB/0-1-2"},
+		},
+		err: "duplicate shard 0",
+	}}
+	for _, tcase := range testcases {
+		vgtid := &vtgatepb.VGtid{ShardGtids: tcase.in}
+		if _, err := vgtidPositions("ks", vgtid); err == nil || !strings.Contains(err.Error(), tcase.err) {
+			t.Errorf("vgtidPositions(%v): %v, want %v", vgtid, err, tcase.err)
+		}
+	}
+}
@@ -50,6 +50,7 @@ type Join struct {
 
 // Execute performs a non-streaming exec.
 func (jn *Join) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	defer startSpan(vcursor, "Join.Execute").Finish()
 	joinVars := make(map[string]*querypb.BindVariable)
 	lresult, err := jn.Left.Execute(vcursor, bindVars, wantfields)
 	if err != nil {
@@ -94,6 +95,7 @@ func (jn *Join) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariab
 
 // StreamExecute performs a streaming exec.
 func (jn *Join) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	defer startSpan(vcursor, "Join.StreamExecute").Finish()
 	joinVars := make(map[string]*querypb.BindVariable)
 	err := jn.Left.StreamExecute(vcursor, bindVars, wantfields, func(lresult *sqltypes.Result) error {
 		for _, lrow := range lresult.Rows {
@@ -151,6 +153,7 @@ func (jn *Join) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.Bind
 
 // GetFields fetches the field info.
 func (jn *Join) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	defer startSpan(vcursor, "Join.GetFields").Finish()
 	joinVars := make(map[string]*querypb.BindVariable)
 	lresult, err := jn.Left.GetFields(vcursor, bindVars)
 	if err != nil {
@@ -54,6 +54,7 @@ func (l *Limit) MarshalJSON() ([]byte, error) {
 
 // Execute satisfies the Primtive interface.
 func (l *Limit) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	defer startSpan(vcursor, "Limit.Execute").Finish()
 	count, err := l.fetchCount(bindVars)
 	if err != nil {
 		return nil, err
@@ -91,6 +92,7 @@ func (l *Limit) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariab
 
 // StreamExecute satisfies the Primtive interface.
 func (l *Limit) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	defer startSpan(vcursor, "Limit.StreamExecute").Finish()
 	count, err := l.fetchCount(bindVars)
 	if err != nil {
 		return err
@@ -143,6 +145,7 @@ func (l *Limit) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.Bind
 
 // GetFields satisfies the Primtive interface.
 func (l *Limit) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	defer startSpan(vcursor, "Limit.GetFields").Finish()
 	return l.Input.GetFields(vcursor, bindVars)
 }
 
@@ -93,6 +93,7 @@ func (code AggregateOpcode) MarshalJSON() ([]byte, error) {
 
 // Execute is a Primitive function.
 func (oa *OrderedAggregate) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	defer startSpan(vcursor, "OrderedAggregate.Execute").Finish()
 	qr, err := oa.execute(vcursor, bindVars, wantfields)
 	if err != nil {
 		return nil, err
@@ -142,6 +143,7 @@ func (oa *OrderedAggregate) execute(vcursor VCursor, bindVars map[string]*queryp
 
 // StreamExecute is a Primitive function.
 func (oa *OrderedAggregate) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	defer startSpan(vcursor, "OrderedAggregate.StreamExecute").Finish()
 	var current []sqltypes.Value
 	var fields []*querypb.Field
 
@@ -196,6 +198,7 @@ func (oa *OrderedAggregate) StreamExecute(vcursor VCursor, bindVars map[string]*
 
 // GetFields is a Primitive function.
 func (oa *OrderedAggregate) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	defer startSpan(vcursor, "OrderedAggregate.GetFields").Finish()
 	qr, err := oa.Input.GetFields(vcursor, bindVars)
 	if err != nil {
 		return nil, err
@@ -154,6 +154,7 @@ func (code RouteOpcode) MarshalJSON() ([]byte, error) {
 
 // Execute performs a non-streaming exec.
 func (route *Route) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	defer startSpan(vcursor, "Route.Execute").Finish()
 	qr, err := route.execute(vcursor, bindVars, wantfields)
 	if err != nil {
 		return nil, err
@@ -207,6 +208,7 @@ func (route *Route) execute(vcursor VCursor, bindVars map[string]*querypb.BindVa
 
 // StreamExecute performs a streaming exec.
 func (route *Route) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	defer startSpan(vcursor, "Route.StreamExecute").Finish()
 	var rss []*srvtopo.ResolvedShard
 	var bvs []map[string]*querypb.BindVariable
 	var err error
@@ -249,6 +251,7 @@ func (route *Route) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.
 
 // GetFields fetches the field info.
 func (route *Route) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	defer startSpan(vcursor, "Route.GetFields").Finish()
 	rss, _, err := vcursor.ResolveDestinations(route.Keyspace.Name, nil, []key.Destination{key.DestinationAnyShard{}})
 	if err != nil {
 		return nil, err
@@ -33,6 +33,7 @@ type Subquery struct {
 
 // Execute performs a non-streaming exec.
 func (sq *Subquery) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	defer startSpan(vcursor, "Subquery.Execute").Finish()
 	inner, err := sq.Subquery.Execute(vcursor, bindVars, wantfields)
 	if err != nil {
 		return nil, err
@@ -42,6 +43,7 @@ func (sq *Subquery) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVa
 
 // StreamExecute performs a streaming exec.
 func (sq *Subquery) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	defer startSpan(vcursor, "Subquery.StreamExecute").Finish()
 	return sq.Subquery.StreamExecute(vcursor, bindVars, wantfields, func(inner *sqltypes.Result) error {
 		return callback(sq.buildResult(inner))
 	})
@@ -49,6 +51,7 @@ func (sq *Subquery) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.
 
 // GetFields fetches the field info.
 func (sq *Subquery) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	defer startSpan(vcursor, "Subquery.GetFields").Finish()
 	inner, err := sq.Subquery.GetFields(vcursor, bindVars)
 	if err != nil {
 		return nil, err
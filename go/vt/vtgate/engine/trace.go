@@ -0,0 +1,36 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"vitess.io/vitess/go/trace"
+)
+
+// startSpan starts a span for the execution of a primitive. The span is
+// a child of the span of the request, from the context of vcursor.
+// Primitives that do not need a VCursor may get a nil one, in which case
+// the span starts a new trace.
+func startSpan(vcursor VCursor, label string) trace.Span {
+	var span trace.Span
+	if vcursor == nil {
+		span = trace.NewSpan(nil)
+	} else {
+		span = trace.NewSpanFromContext(vcursor.Context())
+	}
+	span.StartLocal(label)
+	return span
+}
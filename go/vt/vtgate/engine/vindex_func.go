@@ -81,11 +81,13 @@ func (code VindexOpcode) MarshalJSON() ([]byte, error) {
 
 // Execute performs a non-streaming exec.
 func (vf *VindexFunc) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	defer startSpan(vcursor, "VindexFunc.Execute").Finish()
 	return vf.mapVindex(vcursor, bindVars)
 }
 
 // StreamExecute performs a streaming exec.
 func (vf *VindexFunc) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	defer startSpan(vcursor, "VindexFunc.StreamExecute").Finish()
 	r, err := vf.mapVindex(vcursor, bindVars)
 	if err != nil {
 		return err
@@ -98,6 +100,7 @@ func (vf *VindexFunc) StreamExecute(vcursor VCursor, bindVars map[string]*queryp
 
 // GetFields fetches the field info.
 func (vf *VindexFunc) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	defer startSpan(vcursor, "VindexFunc.GetFields").Finish()
 	return &sqltypes.Result{Fields: vf.Fields}, nil
 }
 
@@ -34,6 +34,7 @@ import (
 	"vitess.io/vitess/go/cache"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/log"
@@ -119,9 +120,20 @@ func NewExecutor(ctx context.Context, serv srvtopo.Server, cell, statsName strin
 
 // Execute executes a non-streaming query.
 func (e *Executor) Execute(ctx context.Context, method string, safeSession *SafeSession, sql string, bindVars map[string]*querypb.BindVariable) (result *sqltypes.Result, err error) {
+	span := trace.NewSpanFromContext(ctx)
+	span.StartLocal("Executor.Execute")
+	span.Annotate("method", method)
+	defer span.Finish()
+	ctx = trace.NewContext(ctx, span)
+
 	logStats := NewLogStats(ctx, method, sql, bindVars)
 	result, err = e.execute(ctx, safeSession, sql, bindVars, logStats)
 	logStats.Error = err
+	span.Annotate("stmt_type", logStats.StmtType)
+	span.Annotate("shard_queries", logStats.ShardQueries)
+	if err != nil {
+		span.Annotate("error", err)
+	}
 
 	// The mysql plugin runs an implicit rollback whenever a connection closes.
 	// To avoid spamming the log with no-op rollback records, ignore it if
@@ -186,7 +198,9 @@ func (e *Executor) execute(ctx context.Context, safeSession *SafeSession, sql st
 		// do is likely not final.
 		// The control flow is such that autocommitable can only be turned on
 		// at the beginning, but never after.
-		safeSession.SetAutocommitable(mustCommit)
+		// A session that reads its writes needs the position of the commit,
+		// which only a separate Commit returns.
+		safeSession.SetAutocommitable(mustCommit && !safeSession.ReadsYourWrites())
 
 		qr, err := e.handleExec(ctx, safeSession, sql, bindVars, destKeyspace, destTabletType, dest, logStats)
 		if err != nil {
@@ -597,6 +611,20 @@ func (e *Executor) handleSet(ctx context.Context, safeSession *SafeSession, sql
 			default:
 				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unexpected value for skip_query_plan_cache: %d", val)
 			}
+		case "read_your_writes":
+			val, ok := v.(int64)
+			if !ok {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unexpected value type for read_your_writes: %T", v)
+			}
+			switch val {
+			case 0:
+				safeSession.ReadYourWrites = false
+				safeSession.WritePositions = nil
+			case 1:
+				safeSession.ReadYourWrites = true
+			default:
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unexpected value for read_your_writes: %d", val)
+			}
 		case "transaction_mode":
 			val, ok := v.(string)
 			if !ok {
@@ -942,8 +970,15 @@ func (e *Executor) handleComment(sql string) (*sqltypes.Result, error) {
 
 // StreamExecute executes a streaming query.
 func (e *Executor) StreamExecute(ctx context.Context, method string, safeSession *SafeSession, sql string, bindVars map[string]*querypb.BindVariable, target querypb.Target, callback func(*sqltypes.Result) error) (err error) {
+	span := trace.NewSpanFromContext(ctx)
+	span.StartLocal("Executor.StreamExecute")
+	span.Annotate("method", method)
+	defer span.Finish()
+	ctx = trace.NewContext(ctx, span)
+
 	logStats := NewLogStats(ctx, method, sql, bindVars)
 	logStats.StmtType = sqlparser.StmtType(sqlparser.Preview(sql))
+	span.Annotate("stmt_type", logStats.StmtType)
 	defer logStats.Send()
 
 	if bindVars == nil {
@@ -1117,6 +1152,79 @@ func (e *Executor) MessageAck(ctx context.Context, keyspace, name string, ids []
 	return e.scatterConn.MessageAck(ctx, rss, rssValues, name)
 }
 
+// MessageRequeue requeues the failed messages of a message table, or
+// moves back the dead letters of its dead letter table if name is the
+// dead letter table. Only the messages with the given ids are requeued,
+// or all of them if ids is empty.
+func (e *Executor) MessageRequeue(ctx context.Context, keyspace, name string, ids []*querypb.Value) (int64, error) {
+	table, err := e.VSchema().FindTable(keyspace, name)
+	if err != nil {
+		return 0, err
+	}
+
+	// Failed messages have neither time_acked nor time_next. Setting
+	// time_next makes them due, and an epoch of 0 gives them all
+	// their attempts again.
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("update %v set time_next = :time_next, epoch = 0 where time_acked is null and time_next is null", table.Name)
+	if len(ids) != 0 {
+		buf.Myprintf(" and id in ::ids")
+	}
+	timeNext := sqltypes.Int64BindVariable(time.Now().UnixNano())
+
+	var rss []*srvtopo.ResolvedShard
+	var rssValues [][]*querypb.Value
+	switch {
+	case len(ids) == 0:
+		rss, err = e.resolver.resolver.ResolveDestination(ctx, table.Keyspace.Name, topodatapb.TabletType_MASTER, key.DestinationAllShards{})
+	case table.Keyspace.Sharded:
+		vcursor := newVCursorImpl(
+			ctx,
+			NewSafeSession(&vtgatepb.Session{}),
+			table.Keyspace.Name,
+			topodatapb.TabletType_MASTER,
+			sqlparser.MarginComments{},
+			e,
+			nil,
+		)
+		values := make([]sqltypes.Value, 0, len(ids))
+		for _, id := range ids {
+			values = append(values, sqltypes.ProtoToValue(id))
+		}
+		// The ID must be the primary vindex for message tables, and
+		// for their dead letter tables too.
+		var destinations []key.Destination
+		destinations, err = table.ColumnVindexes[0].Vindex.Map(vcursor, values)
+		if err != nil {
+			return 0, err
+		}
+		rss, rssValues, err = e.resolver.resolver.ResolveDestinations(ctx, table.Keyspace.Name, topodatapb.TabletType_MASTER, ids, destinations)
+	default:
+		rss, err = e.resolver.resolver.ResolveDestination(ctx, table.Keyspace.Name, topodatapb.TabletType_MASTER, key.DestinationAnyShard{})
+		rssValues = [][]*querypb.Value{ids}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	queries := make([]*querypb.BoundQuery, len(rss))
+	for i := range rss {
+		bindVars := map[string]*querypb.BindVariable{"time_next": timeNext}
+		if rssValues != nil {
+			bindVars["ids"] = &querypb.BindVariable{Type: querypb.Type_TUPLE, Values: rssValues[i]}
+		}
+		queries[i] = &querypb.BoundQuery{
+			Sql:           buf.String(),
+			BindVariables: bindVars,
+		}
+	}
+	qr, err := e.scatterConn.ExecuteMultiShard(ctx, rss, queries, topodatapb.TabletType_MASTER, NewAutocommitSession(&vtgatepb.Session{}), false /* notInTransaction */, true /* autocommit */)
+	if err != nil {
+		return 0, err
+	}
+	return int64(qr.RowsAffected), nil
+}
+
 // IsKeyspaceRangeBasedSharded returns true if the keyspace in the vschema is
 // marked as sharded.
 func (e *Executor) IsKeyspaceRangeBasedSharded(keyspace string) bool {
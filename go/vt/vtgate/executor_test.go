@@ -32,6 +32,7 @@ import (
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vtgate/vschemaacl"
+	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -320,6 +321,18 @@ func TestExecutorSet(t *testing.T) {
 	}, {
 		in:  "set transaction_mode = 1",
 		err: "unexpected value type for transaction_mode: int64",
+	}, {
+		in:  "set read_your_writes = 1",
+		out: &vtgatepb.Session{Autocommit: true, ReadYourWrites: true},
+	}, {
+		in:  "set read_your_writes = 0",
+		out: &vtgatepb.Session{Autocommit: true},
+	}, {
+		in:  "set read_your_writes = 2",
+		err: "unexpected value for read_your_writes: 2",
+	}, {
+		in:  "set read_your_writes = 'on'",
+		err: "unexpected value type for read_your_writes: string",
 	}, {
 		in:  "set workload = 'unspecified'",
 		out: &vtgatepb.Session{Autocommit: true, Options: &querypb.ExecuteOptions{Workload: querypb.ExecuteOptions_UNSPECIFIED}},
@@ -1615,6 +1628,83 @@ func TestExecutorMessageAckSharded(t *testing.T) {
 	}
 }
 
+func TestExecutorMessageRequeueSharded(t *testing.T) {
+	executor, sbc1, sbc2, _ := createExecutorEnv()
+
+	// The ids are routed to their shard.
+	ids := []*querypb.Value{{
+		Type:  sqltypes.VarChar,
+		Value: []byte("1"),
+	}, {
+		Type:  sqltypes.VarChar,
+		Value: []byte("3"),
+	}}
+	count, err := executor.MessageRequeue(context.Background(), "", "user", ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("count: %d, want 2", count)
+	}
+	wantSQL := "update user set time_next = :time_next, epoch = 0 where time_acked is null and time_next is null and id in ::ids"
+	for i, sbc := range []*sandboxconn.SandboxConn{sbc1, sbc2} {
+		if len(sbc.BatchQueries) != 1 || len(sbc.BatchQueries[0]) != 1 {
+			t.Fatalf("sbc%d.BatchQueries: %v, want one query", i+1, sbc.BatchQueries)
+		}
+		query := sbc.BatchQueries[0][0]
+		if query.Sql != wantSQL {
+			t.Errorf("sbc%d query: %v, want %v", i+1, query.Sql, wantSQL)
+		}
+		if got, want := query.BindVariables["ids"].Values, ids[i:i+1]; !reflect.DeepEqual(got, want) {
+			t.Errorf("sbc%d ids: %v, want %v", i+1, got, want)
+		}
+		if query.BindVariables["time_next"] == nil {
+			t.Errorf("sbc%d query has no time_next: %v", i+1, query.BindVariables)
+		}
+	}
+	if got := sbc1.AsTransactionCount.Get(); got != 1 {
+		t.Errorf("sbc1.AsTransactionCount: %d, want 1", got)
+	}
+
+	// Without ids, the failed messages of all the shards are requeued.
+	sbc1.BatchQueries = nil
+	sbc2.BatchQueries = nil
+	count, err = executor.MessageRequeue(context.Background(), "", "user", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 8 {
+		t.Errorf("count: %d, want 8", count)
+	}
+	wantSQL = "update user set time_next = :time_next, epoch = 0 where time_acked is null and time_next is null"
+	for i, sbc := range []*sandboxconn.SandboxConn{sbc1, sbc2} {
+		if len(sbc.BatchQueries) != 1 || sbc.BatchQueries[0][0].Sql != wantSQL {
+			t.Errorf("sbc%d.BatchQueries: %v, want %v", i+1, sbc.BatchQueries, wantSQL)
+		}
+	}
+}
+
+func TestExecutorMessageRequeueUnsharded(t *testing.T) {
+	executor, sbc1, _, sbclookup := createExecutorEnv()
+
+	ids := []*querypb.Value{{
+		Type:  sqltypes.VarChar,
+		Value: []byte("1"),
+	}}
+	if _, err := executor.MessageRequeue(context.Background(), KsTestUnsharded, "main1", ids); err != nil {
+		t.Fatal(err)
+	}
+	if len(sbclookup.BatchQueries) != 1 {
+		t.Fatalf("sbclookup.BatchQueries: %v, want one batch", sbclookup.BatchQueries)
+	}
+	if got := sbclookup.BatchQueries[0][0].BindVariables["ids"].Values; !reflect.DeepEqual(got, ids) {
+		t.Errorf("ids: %v, want %v", got, ids)
+	}
+	if sbc1.BatchQueries != nil {
+		t.Errorf("sbc1.BatchQueries: %v, want nil", sbc1.BatchQueries)
+	}
+}
+
 // TestVSchemaStats makes sure the building and displaying of the
 // VSchemaStats works.
 func TestVSchemaStats(t *testing.T) {
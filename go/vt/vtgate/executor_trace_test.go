@@ -0,0 +1,93 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"sync"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/trace"
+
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+// spanRecorder is a trace.Exporter keeping the spans in memory.
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []*trace.SpanData
+}
+
+func (sr *spanRecorder) ExportSpan(data *trace.SpanData) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.spans = append(sr.spans, data)
+}
+
+func (sr *spanRecorder) Close() error { return nil }
+
+func (sr *spanRecorder) find(label string) *trace.SpanData {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	for _, span := range sr.spans {
+		if span.Label == label {
+			return span
+		}
+	}
+	return nil
+}
+
+func TestExecutorTrace(t *testing.T) {
+	recorder := &spanRecorder{}
+	trace.RegisterSpanFactory(trace.NewTracer("vtgate", recorder, 1))
+	defer trace.RegisterSpanFactory(nil)
+
+	executor, _, _, _ := createExecutorEnv()
+	// The client sent the span context of its own trace.
+	ctx := trace.WithSpanContext(context.Background(), "1:2:0:1")
+	session := NewSafeSession(&vtgatepb.Session{TargetString: "@master"})
+	if _, err := executor.Execute(ctx, "TestExecute", session, "select id from user where id = 1", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	execute := recorder.find("Executor.Execute")
+	if execute == nil {
+		t.Fatalf("no Executor.Execute span in %+v", recorder.spans)
+	}
+	if execute.TraceID != 1 || execute.ParentID != 2 {
+		t.Errorf("Executor.Execute does not continue the client trace: %+v", execute)
+	}
+	if got := execute.Annotations["stmt_type"]; got != "SELECT" {
+		t.Errorf("stmt_type: got %v, want SELECT", got)
+	}
+	for _, label := range []string{"Route.Execute", "ScatterConn.Execute"} {
+		span := recorder.find(label)
+		if span == nil {
+			t.Errorf("no %v span in %+v", label, recorder.spans)
+			continue
+		}
+		if span.TraceID != 1 || span.ParentID != execute.SpanID {
+			t.Errorf("%v is not a child of Executor.Execute: %+v", label, span)
+		}
+	}
+	if shard := recorder.find("ScatterConn.Execute"); shard != nil {
+		if shard.Kind != trace.KindClient || shard.Annotations["shard"] != "-20" {
+			t.Errorf("ScatterConn.Execute: got %+v, want a client span for shard -20", shard)
+		}
+	}
+}
@@ -355,6 +355,11 @@ func (conn *FakeVTGateConn) MessageAckKeyspaceIds(ctx context.Context, keyspace
 	panic("not implemented")
 }
 
+// MessageRequeue is part of the vtgate service API.
+func (conn *FakeVTGateConn) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	panic("not implemented")
+}
+
 // SplitQuery please see vtgateconn.Impl.SplitQuery
 func (conn *FakeVTGateConn) SplitQuery(
 	ctx context.Context,
@@ -393,6 +398,11 @@ func (conn *FakeVTGateConn) UpdateStream(ctx context.Context, keyspace string, s
 	return nil, fmt.Errorf("NYI")
 }
 
+// StreamChanges please see vtgateconn.Impl.StreamChanges
+func (conn *FakeVTGateConn) StreamChanges(ctx context.Context, keyspace string, tabletType topodatapb.TabletType, vgtid *vtgatepb.VGtid) (vtgateconn.StreamChangesReader, error) {
+	return nil, fmt.Errorf("NYI")
+}
+
 // Close please see vtgateconn.Impl.Close
 func (conn *FakeVTGateConn) Close() {
 }
@@ -19,7 +19,6 @@ package gateway
 import (
 	"flag"
 	"fmt"
-	"math/rand"
 	"sort"
 	"strings"
 	"sync"
@@ -28,6 +27,7 @@ import (
 	"golang.org/x/net/context"
 
 	"vitess.io/vitess/go/flagutil"
+	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/srvtopo"
@@ -48,7 +48,15 @@ var (
 	refreshInterval     = flag.Duration("tablet_refresh_interval", 1*time.Minute, "tablet refresh interval")
 	refreshKnownTablets = flag.Bool("tablet_refresh_known_tablets", true, "tablet refresh reloads the tablet address/port map from topo in case it changes")
 	topoReadConcurrency = flag.Int("topo_read_concurrency", 32, "concurrent topo reads")
+	routingPolicies     = flag.String("tablet_routing_policy", "", "semicolon-separated list of per tablet type routing policies, like 'replica:cell,region,remote;rdonly:cell'. A policy lists the tiers queries can be sent to, in order of preference: 'cell' for the local cell, 'region' for the other cells of its region, 'remote' for the other regions. Tablet types without a policy use 'cell,region'")
 	allowedTabletTypes  []topodatapb.TabletType
+
+	// routingTierCounts counts the queries sent to tablets,
+	// by the routing tier of the tablet.
+	routingTierCounts = stats.NewCountersWithMultiLabels(
+		"GatewayRoutingTier",
+		"Queries sent to tablets per keyspace, shard, tablet type and routing tier",
+		[]string{"Keyspace", "ShardName", "TabletType", "Tier"})
 )
 
 const (
@@ -69,6 +77,13 @@ type discoveryGateway struct {
 	localCell     string
 	retryCount    int
 
+	// tabletSelection is how we pick a tablet, see the
+	// tablet_selection flag.
+	tabletSelection string
+	// hedgedReads has the hedging delay of each keyspace, see
+	// the hedged_reads flag.
+	hedgedReads map[string]*hedgingSpec
+
 	// tabletsWatchers contains a list of all the watchers we use.
 	// We create one per cell.
 	tabletsWatchers []*discovery.TopologyWatcher
@@ -78,6 +93,9 @@ type discoveryGateway struct {
 	// statusAggregators is a map indexed by the key
 	// keyspace/shard/tablet_type.
 	statusAggregators map[string]*TabletStatusAggregator
+	// tabletLoads is a map of the tablet loads, indexed by
+	// TabletStats.Key.
+	tabletLoads map[string]*tabletLoad
 
 	// buffer, if enabled, buffers requests during a detected MASTER failover.
 	buffer *buffer.Buffer
@@ -95,10 +113,27 @@ func createDiscoveryGateway(hc discovery.HealthCheck, serv srvtopo.Server, cell
 		localCell:         cell,
 		retryCount:        retryCount,
 		tabletsWatchers:   make([]*discovery.TopologyWatcher, 0, 1),
+		tabletSelection:   *tabletSelection,
 		statusAggregators: make(map[string]*TabletStatusAggregator),
+		tabletLoads:       make(map[string]*tabletLoad),
 		buffer:            buffer.New(),
 	}
 
+	if err := checkTabletSelection(dg.tabletSelection); err != nil {
+		log.Exitf("Cannot parse tablet_selection parameter: %v", err)
+	}
+	hedged, err := parseHedgedReads(hedgedReads)
+	if err != nil {
+		log.Exitf("Cannot parse hedged_reads parameter: %v", err)
+	}
+	dg.hedgedReads = hedged
+
+	policies, err := discovery.ParseRoutingPolicies(*routingPolicies)
+	if err != nil {
+		log.Exitf("Cannot parse tablet_routing_policy parameter: %v", err)
+	}
+	dg.tsc.SetRoutingPolicies(policies)
+
 	// Set listener which will update TabletStatsCache and MasterBuffer.
 	// We set sendDownEvents=true because it's required by TabletStatsCache.
 	hc.SetListener(dg, true /* sendDownEvents */)
@@ -128,6 +163,9 @@ func createDiscoveryGateway(hc discovery.HealthCheck, serv srvtopo.Server, cell
 // It is part of the discovery.HealthCheckStatsListener interface.
 func (dg *discoveryGateway) StatsUpdate(ts *discovery.TabletStats) {
 	dg.tsc.StatsUpdate(ts)
+	if !ts.Up {
+		dg.removeTabletLoad(ts.Key)
+	}
 
 	if ts.Target.TabletType == topodatapb.TabletType_MASTER {
 		dg.buffer.StatsUpdate(ts)
@@ -242,23 +280,18 @@ func (dg *discoveryGateway) withRetry(ctx context.Context, target *querypb.Targe
 			}
 		}
 
-		tablets := dg.tsc.GetHealthyTabletStats(target.Keyspace, target.Shard, target.TabletType)
+		// tablets are ordered by routing tier, so when all the
+		// tablets of a tier failed we fall back to the next one.
+		tablets := dg.tsc.GetRoutedTabletStats(target.Keyspace, target.Shard, target.TabletType)
 		if len(tablets) == 0 {
 			// fail fast if there is no tablet
 			err = vterrors.New(vtrpcpb.Code_UNAVAILABLE, "no valid tablet")
 			break
 		}
-		shuffleTablets(dg.localCell, tablets)
 
 		// skip tablets we tried before
-		var ts *discovery.TabletStats
-		for _, t := range tablets {
-			if _, ok := invalidTablets[t.Key]; !ok {
-				ts = &t
-				break
-			}
-		}
-		if ts == nil {
+		routed := dg.pickTablet(tablets, invalidTablets)
+		if routed == nil {
 			if err == nil {
 				// do not override error from last attempt.
 				err = vterrors.New(vtrpcpb.Code_UNAVAILABLE, "no available connection")
@@ -267,20 +300,25 @@ func (dg *discoveryGateway) withRetry(ctx context.Context, target *querypb.Targe
 		}
 
 		// execute
-		tabletLastUsed = ts.Tablet
-		conn := dg.hc.GetConnection(ts.Key)
+		tabletLastUsed = routed.Tablet
+		conn := dg.hc.GetConnection(routed.Key)
 		if conn == nil {
-			err = vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "no connection for key %v tablet %+v", ts.Key, ts.Tablet)
-			invalidTablets[ts.Key] = true
+			err = vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "no connection for key %v tablet %+v", routed.Key, routed.Tablet)
+			invalidTablets[routed.Key] = true
 			continue
 		}
 
-		startTime := time.Now()
-		var canRetry bool
-		err, canRetry = inner(ctx, ts.Target, conn)
-		dg.updateStats(target, startTime, err)
-		if canRetry {
-			invalidTablets[ts.Key] = true
+		var r *attemptResult
+		if delay, ok := dg.hedgingDelay(ctx, target, name, inTransaction, routed.Key); ok {
+			r = dg.runHedgedAttempt(ctx, target, name, routed, conn, delay, tablets, invalidTablets, inner)
+		} else {
+			r = dg.runAttempt(ctx, target, name, routed, conn, inner)
+		}
+		tabletLastUsed = r.routed.Tablet
+		err = r.err
+		dg.updateStats(target, r.startTime, err)
+		if r.canRetry {
+			invalidTablets[r.routed.Key] = true
 			continue
 		}
 		break
@@ -288,53 +326,6 @@ func (dg *discoveryGateway) withRetry(ctx context.Context, target *querypb.Targe
 	return NewShardError(err, target, tabletLastUsed, inTransaction)
 }
 
-func shuffleTablets(cell string, tablets []discovery.TabletStats) {
-	sameCell, diffCell, sameCellMax := 0, 0, -1
-	length := len(tablets)
-
-	// move all same cell tablets to the front, this is O(n)
-	for {
-		sameCellMax = diffCell - 1
-		sameCell = nextTablet(cell, tablets, sameCell, length, true)
-		diffCell = nextTablet(cell, tablets, diffCell, length, false)
-		// either no more diffs or no more same cells should stop the iteration
-		if sameCell < 0 || diffCell < 0 {
-			break
-		}
-
-		if sameCell < diffCell {
-			// fast forward the `sameCell` lookup to `diffCell + 1`, `diffCell` unchanged
-			sameCell = diffCell + 1
-		} else {
-			// sameCell > diffCell, swap needed
-			tablets[sameCell], tablets[diffCell] = tablets[diffCell], tablets[sameCell]
-			sameCell++
-			diffCell++
-		}
-	}
-
-	//shuffle in same cell tablets
-	for i := sameCellMax; i > 0; i-- {
-		swap := rand.Intn(i + 1)
-		tablets[i], tablets[swap] = tablets[swap], tablets[i]
-	}
-
-	//shuffle in diff cell tablets
-	for i, diffCellMin := length-1, sameCellMax+1; i > diffCellMin; i-- {
-		swap := rand.Intn(i-sameCellMax) + diffCellMin
-		tablets[i], tablets[swap] = tablets[swap], tablets[i]
-	}
-}
-
-func nextTablet(cell string, tablets []discovery.TabletStats, offset, length int, sameCell bool) int {
-	for ; offset < length; offset++ {
-		if (tablets[offset].Tablet.Alias.Cell == cell) == sameCell {
-			return offset
-		}
-	}
-	return -1
-}
-
 func (dg *discoveryGateway) updateStats(target *querypb.Target, startTime time.Time, err error) {
 	elapsed := time.Now().Sub(startTime)
 	aggr := dg.getStatsAggregator(target)
@@ -75,7 +75,8 @@ func TestDiscoveryGatewayBegin(t *testing.T) {
 
 func TestDiscoveryGatewayCommit(t *testing.T) {
 	testDiscoveryGatewayTransact(t, false, func(dg Gateway, target *querypb.Target) error {
-		return dg.Commit(context.Background(), target, 1)
+		_, err := dg.Commit(context.Background(), target, 1, false)
+		return err
 	})
 }
 
@@ -127,83 +128,74 @@ func TestDiscoveryGatewayGetTablets(t *testing.T) {
 	}
 }
 
-func TestShuffleTablets(t *testing.T) {
+func TestDiscoveryGatewayRoutingTiers(t *testing.T) {
+	keyspace := "ks"
+	shard := "0"
+	target := &querypb.Target{
+		Keyspace:   keyspace,
+		Shard:      shard,
+		TabletType: topodatapb.TabletType_REPLICA,
+	}
 	defer topo.UpdateCellsToRegionsForTests(map[string]string{})
 	topo.UpdateCellsToRegionsForTests(map[string]string{
-		"cell1": "region1",
-		"cell2": "region1",
+		"local":      "local",
+		"local-west": "local",
+		"remote":     "remote",
 	})
-
-	ts1 := discovery.TabletStats{
-		Key:     "t1",
-		Tablet:  topo.NewTablet(10, "cell1", "host1"),
-		Target:  &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA},
-		Up:      true,
-		Serving: true,
-		Stats:   &querypb.RealtimeStats{SecondsBehindMaster: 1, CpuUsage: 0.2},
+	hc := discovery.NewFakeHealthCheck()
+	dg := createDiscoveryGateway(hc, nil, "local", 2).(*discoveryGateway)
+	dg.tsc.SetRoutingPolicies(map[topodatapb.TabletType]discovery.RoutingPolicy{
+		topodatapb.TabletType_REPLICA: {discovery.RoutingTierCell, discovery.RoutingTierRegion, discovery.RoutingTierRemote},
+	})
+	tierCount := func(tier string) int64 {
+		return routingTierCounts.Counts()["ks.0.replica."+tier]
 	}
 
-	ts2 := discovery.TabletStats{
-		Key:     "t2",
-		Tablet:  topo.NewTablet(10, "cell1", "host2"),
-		Target:  &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA},
-		Up:      true,
-		Serving: true,
-		Stats:   &querypb.RealtimeStats{SecondsBehindMaster: 1, CpuUsage: 0.2},
+	// the local cell is used first
+	hc.Reset()
+	dg.tsc.ResetForTesting()
+	scLocal := hc.AddTestTablet("local", "1.1.1.1", 1001, keyspace, shard, topodatapb.TabletType_REPLICA, true, 10, nil)
+	scRegion := hc.AddTestTablet("local-west", "2.2.2.2", 1001, keyspace, shard, topodatapb.TabletType_REPLICA, true, 10, nil)
+	scRemote := hc.AddTestTablet("remote", "3.3.3.3", 1001, keyspace, shard, topodatapb.TabletType_REPLICA, true, 10, nil)
+	cell, region, remote := tierCount("cell"), tierCount("region"), tierCount("remote")
+	if _, err := dg.Execute(context.Background(), target, "query", nil, 0, nil); err != nil {
+		t.Fatalf("Execute failed: %v", err)
 	}
-
-	ts3 := discovery.TabletStats{
-		Key:     "t3",
-		Tablet:  topo.NewTablet(10, "cell2", "host3"),
-		Target:  &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA},
-		Up:      true,
-		Serving: true,
-		Stats:   &querypb.RealtimeStats{SecondsBehindMaster: 1, CpuUsage: 0.2},
+	if got := scLocal.ExecCount.Get(); got != 1 {
+		t.Errorf("local tablet executed %v queries, want 1", got)
 	}
-
-	ts4 := discovery.TabletStats{
-		Key:     "t4",
-		Tablet:  topo.NewTablet(10, "cell2", "host4"),
-		Target:  &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA},
-		Up:      true,
-		Serving: true,
-		Stats:   &querypb.RealtimeStats{SecondsBehindMaster: 1, CpuUsage: 0.2},
+	if got := tierCount("cell") - cell; got != 1 {
+		t.Errorf("cell tier counted %v queries, want 1", got)
 	}
 
-	sameCellTablets := []discovery.TabletStats{ts1, ts2}
-	diffCellTablets := []discovery.TabletStats{ts3, ts4}
-	mixedTablets := []discovery.TabletStats{ts1, ts2, ts3, ts4}
-	// repeat shuffling 10 times and everytime the same cell tablets should be in the front
-	for i := 0; i < 10; i++ {
-		shuffleTablets("cell1", sameCellTablets)
-		if (len(sameCellTablets) != 2) ||
-			(sameCellTablets[0].Key != "t1" && sameCellTablets[0].Key != "t2") ||
-			(sameCellTablets[1].Key != "t1" && sameCellTablets[1].Key != "t2") {
-			t.Errorf("should shuffle in only same cell tablets, got %+v", sameCellTablets)
-		}
-
-		shuffleTablets("cell1", diffCellTablets)
-		if (len(diffCellTablets) != 2) ||
-			(diffCellTablets[0].Key != "t3" && diffCellTablets[0].Key != "t4") ||
-			(diffCellTablets[1].Key != "t3" && diffCellTablets[1].Key != "t4") {
-			t.Errorf("should shuffle in only diff cell tablets, got %+v", diffCellTablets)
-		}
-
-		shuffleTablets("cell1", mixedTablets)
-		if len(mixedTablets) != 4 {
-			t.Errorf("should have 4 tablets, got %+v", mixedTablets)
-		}
-
-		if (mixedTablets[0].Key != "t1" && mixedTablets[0].Key != "t2") ||
-			(mixedTablets[1].Key != "t1" && mixedTablets[1].Key != "t2") {
-			t.Errorf("should have same cell tablets in the front, got %+v", mixedTablets)
-		}
-
-		if (mixedTablets[2].Key != "t3" && mixedTablets[2].Key != "t4") ||
-			(mixedTablets[3].Key != "t3" && mixedTablets[3].Key != "t4") {
-			t.Errorf("should have diff cell tablets in the rear, got %+v", mixedTablets)
-		}
+	// and when the tablets of a tier fail, we fall back to the next tier
+	scLocal.MustFailCodes[vtrpcpb.Code_FAILED_PRECONDITION] = 1
+	scRegion.MustFailCodes[vtrpcpb.Code_FAILED_PRECONDITION] = 1
+	if _, err := dg.Execute(context.Background(), target, "query", nil, 0, nil); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if got := scRemote.ExecCount.Get(); got != 1 {
+		t.Errorf("remote tablet executed %v queries, want 1", got)
 	}
+	if got := tierCount("region") - region; got != 1 {
+		t.Errorf("region tier counted %v queries, want 1", got)
+	}
+	if got := tierCount("remote") - remote; got != 1 {
+		t.Errorf("remote tier counted %v queries, want 1", got)
+	}
+
+	// tiers that are not in the policy are never used
+	dg.tsc.SetRoutingPolicies(map[topodatapb.TabletType]discovery.RoutingPolicy{
+		topodatapb.TabletType_REPLICA: {discovery.RoutingTierCell},
+	})
+	hc.Reset()
+	dg.tsc.ResetForTesting()
+	scLocal = hc.AddTestTablet("local", "1.1.1.1", 1001, keyspace, shard, topodatapb.TabletType_REPLICA, true, 10, nil)
+	hc.AddTestTablet("local-west", "2.2.2.2", 1001, keyspace, shard, topodatapb.TabletType_REPLICA, true, 10, nil)
+	scLocal.MustFailCodes[vtrpcpb.Code_FAILED_PRECONDITION] = 1
+	want := fmt.Sprintf(`target: ks.0.replica, used tablet: %s, FAILED_PRECONDITION error`, topotools.TabletIdent(scLocal.Tablet()))
+	_, err := dg.Execute(context.Background(), target, "query", nil, 0, nil)
+	verifyShardError(t, err, want, vtrpcpb.Code_FAILED_PRECONDITION)
 }
 
 func TestDiscoveryGatewayGetTabletsWithRegion(t *testing.T) {
@@ -0,0 +1,261 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/flagutil"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+var (
+	hedgedReads flagutil.StringMapValue
+
+	hedgedRequests = stats.NewCountersWithMultiLabels(
+		"GatewayHedgedRequests",
+		"Hedged requests sent to a second tablet, per keyspace, shard and tablet type",
+		[]string{"Keyspace", "ShardName", "TabletType"})
+	hedgedRequestsWon = stats.NewCountersWithMultiLabels(
+		"GatewayHedgedRequestsWon",
+		"Hedged requests that returned before the original request, per keyspace, shard and tablet type",
+		[]string{"Keyspace", "ShardName", "TabletType"})
+)
+
+const (
+	// directiveHedgeDelay is the query comment directive that sets
+	// the hedging delay of a query, like /*vt+ HEDGE_DELAY=p95 */.
+	directiveHedgeDelay = "HEDGE_DELAY"
+
+	// minHedgingSamples is the number of latency samples of a tablet
+	// needed to use a percentile of its latency as hedging delay.
+	minHedgingSamples = 20
+)
+
+func init() {
+	flag.Var(&hedgedReads, "hedged_reads", "comma-separated list of keyspace:delay pairs, to send the non-master reads of a keyspace to a second tablet when the first one did not answer after the delay, and use the first answer. The delay is either a duration, like 20ms, or a percentile of the latency of the tablet, like p95. Queries can override it with a /*vt+ HEDGE_DELAY=<delay> */ comment, with 0 to disable hedging")
+}
+
+// hedgingSpec is the hedging delay of requests.
+type hedgingSpec struct {
+	// delay is a fixed delay.
+	delay time.Duration
+	// percentile, if set, is the percentile of the latency of the
+	// tablet to use as delay.
+	percentile float64
+}
+
+// parseHedgingSpec parses a hedging delay. It returns nil if hedging
+// is disabled.
+func parseHedgingSpec(value string) (*hedgingSpec, error) {
+	switch strings.ToLower(value) {
+	case "", "0", "off", "false":
+		return nil, nil
+	}
+	if strings.HasPrefix(value, "p") {
+		percentile, err := strconv.ParseFloat(value[1:], 64)
+		if err != nil || percentile <= 0 || percentile > 100 {
+			return nil, fmt.Errorf("invalid hedging percentile %v", value)
+		}
+		return &hedgingSpec{percentile: percentile}, nil
+	}
+	delay, err := time.ParseDuration(value)
+	if err != nil || delay <= 0 {
+		return nil, fmt.Errorf("invalid hedging delay %v", value)
+	}
+	return &hedgingSpec{delay: delay}, nil
+}
+
+// parseHedgedReads parses the hedged_reads flag.
+func parseHedgedReads(value map[string]string) (map[string]*hedgingSpec, error) {
+	result := make(map[string]*hedgingSpec)
+	for keyspace, delay := range value {
+		spec, err := parseHedgingSpec(delay)
+		if err != nil {
+			return nil, fmt.Errorf("keyspace %v: %v", keyspace, err)
+		}
+		if spec != nil {
+			result[keyspace] = spec
+		}
+	}
+	return result, nil
+}
+
+type hedgingKey struct{}
+
+// withHedgingDirective returns a context with the hedging delay of
+// the HEDGE_DELAY directive of a query, if any. Invalid directives
+// are ignored.
+func withHedgingDirective(ctx context.Context, sql string) context.Context {
+	_, comments := sqlparser.SplitMarginComments(sql)
+	if !strings.Contains(comments.Leading, directiveHedgeDelay) {
+		return ctx
+	}
+	var parsed sqlparser.Comments
+	for _, c := range strings.SplitAfter(comments.Leading, "*/") {
+		if i := strings.Index(c, "/*"); i >= 0 {
+			parsed = append(parsed, []byte(strings.TrimSpace(c[i:])))
+		}
+	}
+	value, ok := sqlparser.ExtractCommentDirectives(parsed)[directiveHedgeDelay]
+	if !ok {
+		return ctx
+	}
+	spec, err := parseHedgingSpec(fmt.Sprint(value))
+	if err != nil {
+		log.Warningf("ignoring query directive %v: %v", directiveHedgeDelay, err)
+		return ctx
+	}
+	return context.WithValue(ctx, hedgingKey{}, spec)
+}
+
+// Execute is part of the queryservice.QueryService interface. It reads
+// the hedging directive of the query before calling withRetry.
+func (dg *discoveryGateway) Execute(ctx context.Context, target *querypb.Target, sql string, bindVariables map[string]*querypb.BindVariable, transactionID int64, options *querypb.ExecuteOptions) (*sqltypes.Result, error) {
+	return dg.QueryService.Execute(withHedgingDirective(ctx, sql), target, sql, bindVariables, transactionID, options)
+}
+
+// ExecuteBatch is part of the queryservice.QueryService interface. It
+// reads the hedging directive of the first query before calling
+// withRetry.
+func (dg *discoveryGateway) ExecuteBatch(ctx context.Context, target *querypb.Target, queries []*querypb.BoundQuery, asTransaction bool, transactionID int64, options *querypb.ExecuteOptions) ([]sqltypes.Result, error) {
+	if len(queries) > 0 {
+		ctx = withHedgingDirective(ctx, queries[0].Sql)
+	}
+	return dg.QueryService.ExecuteBatch(ctx, target, queries, asTransaction, transactionID, options)
+}
+
+// hedgingDelay returns the delay after which a request to a tablet
+// should be hedged, and false if it should not.
+// Only the non-master reads outside of a transaction are hedged, as
+// they can run twice.
+func (dg *discoveryGateway) hedgingDelay(ctx context.Context, target *querypb.Target, name string, inTransaction bool, key string) (time.Duration, bool) {
+	if inTransaction || target.TabletType == topodatapb.TabletType_MASTER || (name != "Execute" && name != "ExecuteBatch") {
+		return 0, false
+	}
+	spec, ok := ctx.Value(hedgingKey{}).(*hedgingSpec)
+	if !ok {
+		spec = dg.hedgedReads[target.Keyspace]
+	}
+	if spec == nil {
+		return 0, false
+	}
+	if spec.percentile == 0 {
+		return spec.delay, true
+	}
+	return dg.getTabletLoad(key).percentile(spec.percentile)
+}
+
+// attemptResult is the result of a request to a tablet.
+type attemptResult struct {
+	routed    *discovery.RoutedTabletStats
+	startTime time.Time
+	err       error
+	canRetry  bool
+}
+
+// runAttempt sends a request to a tablet, and updates its load.
+func (dg *discoveryGateway) runAttempt(ctx context.Context, target *querypb.Target, name string, routed *discovery.RoutedTabletStats, conn queryservice.QueryService, inner func(ctx context.Context, target *querypb.Target, conn queryservice.QueryService) (error, bool)) *attemptResult {
+	routingTierCounts.Add([]string{target.Keyspace, target.Shard, topoproto.TabletTypeLString(target.TabletType), routed.Tier.String()}, 1)
+	load := dg.getTabletLoad(routed.Key)
+	load.inFlight.Add(1)
+	r := &attemptResult{
+		routed:    routed,
+		startTime: time.Now(),
+	}
+	r.err, r.canRetry = inner(ctx, routed.Target, conn)
+	load.inFlight.Add(-1)
+	if r.err == nil && latencySampledCalls[name] {
+		now := time.Now()
+		load.observe(now.Sub(r.startTime), now)
+	}
+	return r
+}
+
+// runHedgedAttempt sends a request to a tablet, and if it did not
+// answer after the delay, sends it to a second tablet too. It returns
+// the first successful result, once the other request is canceled and
+// returned, so inner never runs after runHedgedAttempt returns. If the
+// first result is an error, it waits for the other request.
+// The stats of the errors that are not returned are updated here.
+func (dg *discoveryGateway) runHedgedAttempt(ctx context.Context, target *querypb.Target, name string, routed *discovery.RoutedTabletStats, conn queryservice.QueryService, delay time.Duration, tablets []discovery.RoutedTabletStats, invalidTablets map[string]bool, inner func(ctx context.Context, target *querypb.Target, conn queryservice.QueryService) (error, bool)) *attemptResult {
+	// Cancels the request that didn't return when we're done.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan *attemptResult, 2)
+	go func() {
+		results <- dg.runAttempt(ctx, target, name, routed, conn, inner)
+	}()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case r := <-results:
+		return r
+	case <-timer.C:
+	}
+
+	// The tablet is slow, find another one.
+	excluded := map[string]bool{routed.Key: true}
+	for key := range invalidTablets {
+		excluded[key] = true
+	}
+	hedged := dg.pickTablet(tablets, excluded)
+	if hedged == nil {
+		return <-results
+	}
+	hedgedConn := dg.hc.GetConnection(hedged.Key)
+	if hedgedConn == nil {
+		return <-results
+	}
+	statsKey := []string{target.Keyspace, target.Shard, topoproto.TabletTypeLString(target.TabletType)}
+	hedgedRequests.Add(statsKey, 1)
+	go func() {
+		results <- dg.runAttempt(ctx, target, name, hedged, hedgedConn, inner)
+	}()
+
+	r := <-results
+	if r.err != nil {
+		dg.updateStats(target, r.startTime, r.err)
+		if r.canRetry {
+			invalidTablets[r.routed.Key] = true
+		}
+		r = <-results
+	} else {
+		cancel()
+		<-results
+	}
+	if r.err == nil && r.routed == hedged {
+		hedgedRequestsWon.Add(statsKey, 1)
+	}
+	return r
+}
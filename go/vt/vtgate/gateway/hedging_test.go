@@ -0,0 +1,216 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/sync2"
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestParseHedgingSpec(t *testing.T) {
+	testcases := []struct {
+		in   string
+		want *hedgingSpec
+		err  bool
+	}{
+		{in: "", want: nil},
+		{in: "0", want: nil},
+		{in: "off", want: nil},
+		{in: "20ms", want: &hedgingSpec{delay: 20 * time.Millisecond}},
+		{in: "p95", want: &hedgingSpec{percentile: 95}},
+		{in: "p99.9", want: &hedgingSpec{percentile: 99.9}},
+		{in: "p0", err: true},
+		{in: "p101", err: true},
+		{in: "-1s", err: true},
+		{in: "soon", err: true},
+	}
+	for _, tcase := range testcases {
+		got, err := parseHedgingSpec(tcase.in)
+		if (err != nil) != tcase.err {
+			t.Errorf("parseHedgingSpec(%v) returned error %v", tcase.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tcase.want) {
+			t.Errorf("parseHedgingSpec(%v): got %+v, want %+v", tcase.in, got, tcase.want)
+		}
+	}
+}
+
+func TestWithHedgingDirective(t *testing.T) {
+	testcases := []struct {
+		sql  string
+		want *hedgingSpec
+		ok   bool
+	}{
+		{sql: "select 1 from t", ok: false},
+		{sql: "/* comment */ select 1 from t", ok: false},
+		{sql: "/*vt+ HEDGE_DELAY=20ms */ select 1 from t", want: &hedgingSpec{delay: 20 * time.Millisecond}, ok: true},
+		{sql: "/* comment */ /*vt+ SKIP_QUERY_PLAN_CACHE=1 HEDGE_DELAY=p90 */ select 1 from t", want: &hedgingSpec{percentile: 90}, ok: true},
+		{sql: "/*vt+ HEDGE_DELAY=0 */ select 1 from t", want: nil, ok: true},
+		{sql: "/*vt+ HEDGE_DELAY=soon */ select 1 from t", ok: false},
+	}
+	for _, tcase := range testcases {
+		got, ok := withHedgingDirective(context.Background(), tcase.sql).Value(hedgingKey{}).(*hedgingSpec)
+		if ok != tcase.ok || !reflect.DeepEqual(got, tcase.want) {
+			t.Errorf("withHedgingDirective(%v): got %+v, %v, want %+v, %v", tcase.sql, got, ok, tcase.want, tcase.ok)
+		}
+	}
+}
+
+// slowConn is a SandboxConn that answers after a delay. If
+// ignoreCancel is set, it answers even if the request is cancelled.
+type slowConn struct {
+	*sandboxconn.SandboxConn
+	delay        time.Duration
+	ignoreCancel bool
+	cancelled    chan struct{}
+	returned     sync2.AtomicBool
+}
+
+func (sc *slowConn) Execute(ctx context.Context, target *querypb.Target, query string, bindVars map[string]*querypb.BindVariable, transactionID int64, options *querypb.ExecuteOptions) (*sqltypes.Result, error) {
+	defer sc.returned.Set(true)
+	done := ctx.Done()
+	if sc.ignoreCancel {
+		done = nil
+	}
+	select {
+	case <-time.After(sc.delay):
+		return sc.SandboxConn.Execute(ctx, target, query, bindVars, transactionID, options)
+	case <-done:
+		close(sc.cancelled)
+		return nil, ctx.Err()
+	}
+}
+
+func TestDiscoveryGatewayHedging(t *testing.T) {
+	keyspace := "ks"
+	shard := "0"
+	target := &querypb.Target{
+		Keyspace:   keyspace,
+		Shard:      shard,
+		TabletType: topodatapb.TabletType_REPLICA,
+	}
+	statsKey := "ks.0.replica"
+	hc := discovery.NewFakeHealthCheck()
+	dg := createDiscoveryGateway(hc, nil, "cell", 2).(*discoveryGateway)
+	dg.hedgedReads = map[string]*hedgingSpec{
+		keyspace: {delay: 10 * time.Millisecond},
+	}
+	// With these latencies, the slow tablet is used first.
+	dg.tabletSelection = tabletSelectionLeastLoaded
+	addTablet := func(host string, delay, latency time.Duration) *slowConn {
+		conn := hc.AddFakeTablet("cell", host, 1001, keyspace, shard, topodatapb.TabletType_REPLICA, true, 10, nil, func(tablet *topodatapb.Tablet) queryservice.QueryService {
+			return &slowConn{
+				SandboxConn: sandboxconn.NewSandboxConn(tablet),
+				delay:       delay,
+				cancelled:   make(chan struct{}),
+			}
+		}).(*slowConn)
+		dg.getTabletLoad(discovery.TabletToMapKey(conn.Tablet())).observe(latency, time.Now())
+		return conn
+	}
+
+	// the request to the slow tablet is hedged, and cancelled
+	hc.Reset()
+	dg.tsc.ResetForTesting()
+	slow := addTablet("1.1.1.1", time.Hour, time.Millisecond)
+	fast := addTablet("2.2.2.2", 0, 5*time.Millisecond)
+	hedged, won := hedgedRequests.Counts()[statsKey], hedgedRequestsWon.Counts()[statsKey]
+	if _, err := dg.Execute(context.Background(), target, "select 1 from t", nil, 0, nil); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if got := fast.ExecCount.Get(); got != 1 {
+		t.Errorf("fast tablet executed %v queries, want 1", got)
+	}
+	select {
+	case <-slow.cancelled:
+	case <-time.After(5 * time.Second):
+		t.Errorf("the request to the slow tablet was not cancelled")
+	}
+	if got := hedgedRequests.Counts()[statsKey] - hedged; got != 1 {
+		t.Errorf("GatewayHedgedRequests: got %v, want 1", got)
+	}
+	if got := hedgedRequestsWon.Counts()[statsKey] - won; got != 1 {
+		t.Errorf("GatewayHedgedRequestsWon: got %v, want 1", got)
+	}
+
+	// the result of the hedged request is returned, even if the
+	// cancelled request succeeds too, once it returned
+	hc.Reset()
+	dg.tsc.ResetForTesting()
+	slow = addTablet("1.1.1.1", 50*time.Millisecond, time.Millisecond)
+	slow.ignoreCancel = true
+	slow.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("tablet", "varchar"), "slow")})
+	fast = addTablet("2.2.2.2", 0, 5*time.Millisecond)
+	want := sqltypes.MakeTestResult(sqltypes.MakeTestFields("tablet", "varchar"), "fast")
+	fast.SetResults([]*sqltypes.Result{want})
+	qr, err := dg.Execute(context.Background(), target, "select 1 from t", nil, 0, nil)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !slow.returned.Get() {
+		t.Errorf("Execute returned before the request to the slow tablet")
+	}
+	if !reflect.DeepEqual(qr, want) {
+		t.Errorf("Execute: got %v, want the result of the fast tablet %v", qr, want)
+	}
+
+	// a fast answer is not hedged, and a query comment disables hedging
+	for _, sql := range []string{
+		"select 1 from t",
+		"/*vt+ HEDGE_DELAY=0 */ select 1 from t",
+	} {
+		hc.Reset()
+		dg.tsc.ResetForTesting()
+		first := addTablet("1.1.1.1", 20*time.Millisecond, time.Millisecond)
+		if sql == "select 1 from t" {
+			first.delay = 0
+		}
+		other := addTablet("2.2.2.2", 0, 5*time.Millisecond)
+		hedged = hedgedRequests.Counts()[statsKey]
+		if _, err := dg.Execute(context.Background(), target, sql, nil, 0, nil); err != nil {
+			t.Fatalf("Execute(%v) failed: %v", sql, err)
+		}
+		if first.ExecCount.Get() != 1 || other.ExecCount.Get() != 0 {
+			t.Errorf("Execute(%v): tablets executed %v and %v queries, want 1 and 0", sql, first.ExecCount.Get(), other.ExecCount.Get())
+		}
+		if got := hedgedRequests.Counts()[statsKey] - hedged; got != 0 {
+			t.Errorf("Execute(%v): GatewayHedgedRequests: got %v, want 0", sql, got)
+		}
+	}
+
+	// masters are never hedged
+	if delay, ok := dg.hedgingDelay(context.Background(), &querypb.Target{Keyspace: keyspace, Shard: shard, TabletType: topodatapb.TabletType_MASTER}, "Execute", false, "key"); ok {
+		t.Errorf("hedgingDelay(master) = %v, want no hedging", delay)
+	}
+	// and transactions neither
+	if delay, ok := dg.hedgingDelay(context.Background(), target, "Execute", true, "key"); ok {
+		t.Errorf("hedgingDelay(in transaction) = %v, want no hedging", delay)
+	}
+}
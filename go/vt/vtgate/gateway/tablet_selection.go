@@ -0,0 +1,249 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sync2"
+	"vitess.io/vitess/go/vt/discovery"
+)
+
+var (
+	tabletSelection = flag.String("tablet_selection", tabletSelectionRandom, "how the discovery gateway picks a tablet among the healthy tablets of the best routing tier: 'random', 'least_loaded' (the tablet with the lowest expected latency, computed from the latency observed by vtgate, the QPS of the tablet and the requests in flight), or 'power_of_two' (the least loaded of two random tablets)")
+	latencyDecay    = flag.Duration("tablet_latency_decay", 10*time.Second, "time constant of the moving average of the latency of each tablet, used by the least_loaded and power_of_two tablet selections")
+)
+
+const (
+	// latencySampleCount is the number of latency samples kept
+	// for each tablet.
+	latencySampleCount = 100
+
+	tabletSelectionRandom      = "random"
+	tabletSelectionLeastLoaded = "least_loaded"
+	tabletSelectionPowerOfTwo  = "power_of_two"
+)
+
+// latencySampledCalls are the calls whose duration is a latency sample.
+// The duration of streaming calls depends on the amount of data more
+// than on the load of the tablet.
+var latencySampledCalls = map[string]bool{
+	"Execute":           true,
+	"ExecuteBatch":      true,
+	"BeginExecute":      true,
+	"BeginExecuteBatch": true,
+}
+
+func checkTabletSelection(selection string) error {
+	switch selection {
+	case tabletSelectionRandom, tabletSelectionLeastLoaded, tabletSelectionPowerOfTwo:
+		return nil
+	}
+	return fmt.Errorf("unknown tablet selection %v", selection)
+}
+
+// tabletLoad is the load of a tablet, as seen by this vtgate.
+type tabletLoad struct {
+	// inFlight is the number of requests in flight.
+	inFlight sync2.AtomicInt64
+
+	// mu protects the following fields.
+	mu sync.Mutex
+	// latency is the exponentially weighted moving average of the
+	// latency of the successful requests, in seconds.
+	latency float64
+	// lastUpdate is the time of the last sample.
+	lastUpdate time.Time
+	// samples is a ring buffer of the last latency samples.
+	samples [latencySampleCount]time.Duration
+	// sampleCount is the total number of samples.
+	sampleCount int
+}
+
+// observe adds a latency sample. The weight of the previous average
+// decreases with the time since the last sample, so the average
+// doesn't depend on the rate of the requests.
+func (l *tabletLoad) observe(elapsed time.Duration, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.lastUpdate.IsZero() {
+		l.latency = elapsed.Seconds()
+	} else {
+		w := math.Exp(-float64(now.Sub(l.lastUpdate)) / float64(*latencyDecay))
+		l.latency = l.latency*w + elapsed.Seconds()*(1-w)
+	}
+	l.lastUpdate = now
+	l.samples[l.sampleCount%latencySampleCount] = elapsed
+	l.sampleCount++
+}
+
+// percentile returns a percentile of the last latency samples, and
+// false if there are not enough samples.
+func (l *tabletLoad) percentile(p float64) (time.Duration, bool) {
+	l.mu.Lock()
+	if l.sampleCount < minHedgingSamples {
+		l.mu.Unlock()
+		return 0, false
+	}
+	n := l.sampleCount
+	if n > latencySampleCount {
+		n = latencySampleCount
+	}
+	samples := make([]time.Duration, n)
+	copy(samples, l.samples[:n])
+	l.mu.Unlock()
+
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i] < samples[j]
+	})
+	i := int(math.Ceil(p/100*float64(n))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return samples[i], true
+}
+
+// averageLatency returns the moving average of the latency, in
+// seconds, and false if there is no sample.
+func (l *tabletLoad) averageLatency() (float64, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.latency, !l.lastUpdate.IsZero()
+}
+
+// cost returns the expected latency of a new request, in seconds. It
+// is the average latency, multiplied by the number of requests the
+// new one will likely have to share the tablet with: the ones in
+// flight from this vtgate, and the ones of the other clients, from
+// the QPS of the tablet (by Little's law). The average decays towards
+// the prior when no sample was added for a while, and is the prior
+// without samples: a tablet that was slow once gets tried again
+// eventually, but an idle tablet doesn't look faster than the others.
+func (l *tabletLoad) cost(qps, prior float64, now time.Time) float64 {
+	l.mu.Lock()
+	latency := prior
+	if !l.lastUpdate.IsZero() {
+		w := math.Exp(-float64(now.Sub(l.lastUpdate)) / float64(*latencyDecay))
+		latency += (l.latency - prior) * w
+	}
+	l.mu.Unlock()
+	return latency * (1 + float64(l.inFlight.Get()) + qps*latency)
+}
+
+// getTabletLoad returns the load of a tablet, creating it if needed.
+func (dg *discoveryGateway) getTabletLoad(key string) *tabletLoad {
+	dg.mu.RLock()
+	load, ok := dg.tabletLoads[key]
+	dg.mu.RUnlock()
+	if ok {
+		return load
+	}
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	load, ok = dg.tabletLoads[key]
+	if ok {
+		return load
+	}
+	load = &tabletLoad{}
+	dg.tabletLoads[key] = load
+	return load
+}
+
+// removeTabletLoad forgets the load of a tablet.
+func (dg *discoveryGateway) removeTabletLoad(key string) {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	delete(dg.tabletLoads, key)
+}
+
+// latencyPrior returns the median of the average latencies of the
+// tablets with samples, in seconds, or 0 if none has samples.
+func (dg *discoveryGateway) latencyPrior(tablets []discovery.RoutedTabletStats) float64 {
+	var latencies []float64
+	for i := range tablets {
+		if latency, ok := dg.getTabletLoad(tablets[i].Key).averageLatency(); ok {
+			latencies = append(latencies, latency)
+		}
+	}
+	n := len(latencies)
+	if n == 0 {
+		return 0
+	}
+	sort.Float64s(latencies)
+	if n%2 == 0 {
+		return (latencies[n/2-1] + latencies[n/2]) / 2
+	}
+	return latencies[n/2]
+}
+
+// tabletCost returns the cost of sending a request to a tablet.
+func (dg *discoveryGateway) tabletCost(ts *discovery.TabletStats, prior float64, now time.Time) float64 {
+	var qps float64
+	if ts.Stats != nil {
+		qps = ts.Stats.Qps
+	}
+	return dg.getTabletLoad(ts.Key).cost(qps, prior, now)
+}
+
+// pickTablet returns the tablet to use among the tablets we didn't try
+// yet, or nil if there is none. The tablets are ordered by routing
+// tier, and shuffled within a tier: only the tablets of the first
+// tier with a valid tablet are candidates.
+func (dg *discoveryGateway) pickTablet(tablets []discovery.RoutedTabletStats, invalidTablets map[string]bool) *discovery.RoutedTabletStats {
+	var candidates []*discovery.RoutedTabletStats
+	for i := range tablets {
+		t := &tablets[i]
+		if _, ok := invalidTablets[t.Key]; ok {
+			continue
+		}
+		if len(candidates) > 0 && t.Tier != candidates[0].Tier {
+			break
+		}
+		candidates = append(candidates, t)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	switch dg.tabletSelection {
+	case tabletSelectionLeastLoaded:
+		// All the candidates are compared.
+	case tabletSelectionPowerOfTwo:
+		// The candidates are shuffled, so the first two are
+		// random choices.
+		if len(candidates) > 2 {
+			candidates = candidates[:2]
+		}
+	default:
+		return candidates[0]
+	}
+	// The prior latency is the one of the whole shard.
+	prior := dg.latencyPrior(tablets)
+	now := time.Now()
+	best, bestCost := candidates[0], dg.tabletCost(&candidates[0].TabletStats, prior, now)
+	for _, t := range candidates[1:] {
+		if c := dg.tabletCost(&t.TabletStats, prior, now); c < bestCost {
+			best, bestCost = t, c
+		}
+	}
+	return best
+}
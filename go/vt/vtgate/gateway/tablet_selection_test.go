@@ -0,0 +1,204 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"vitess.io/vitess/go/vt/discovery"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestTabletLoad(t *testing.T) {
+	now := time.Now()
+	l := &tabletLoad{}
+	prior := 0.0
+	checkCost := func(qps float64, now time.Time, want float64) {
+		t.Helper()
+		if got := l.cost(qps, prior, now); math.Abs(got-want) > 1e-9 {
+			t.Errorf("cost(%v, %v): got %v, want %v", qps, prior, got, want)
+		}
+	}
+
+	// no sample
+	checkCost(10, now, 0)
+	prior = 0.05
+	checkCost(0, now, 0.05)
+	prior = 0
+
+	// the first sample is the average
+	l.observe(100*time.Millisecond, now)
+	checkCost(0, now, 0.1)
+
+	// requests in flight and QPS make it more expensive
+	l.inFlight.Add(1)
+	checkCost(0, now, 0.2)
+	checkCost(10, now, 0.3)
+	l.inFlight.Add(-1)
+
+	// the average decays without samples
+	checkCost(0, now.Add(*latencyDecay), 0.1*math.Exp(-1))
+
+	// towards the prior, when there is one
+	prior = 0.05
+	checkCost(0, now.Add(*latencyDecay), 0.05+0.05*math.Exp(-1))
+	checkCost(0, now.Add(100**latencyDecay), 0.05)
+	prior = 0
+
+	// a sample right after the previous one doesn't count much,
+	// a sample much later replaces the average
+	l.observe(time.Second, now.Add(time.Millisecond))
+	if l.latency > 0.2 {
+		t.Errorf("latency: got %v, want close to 0.1", l.latency)
+	}
+	l.observe(time.Second, now.Add(100**latencyDecay))
+	if l.latency < 0.99 {
+		t.Errorf("latency: got %v, want close to 1", l.latency)
+	}
+}
+
+func TestPickTablet(t *testing.T) {
+	if err := checkTabletSelection("fastest"); err == nil {
+		t.Errorf("checkTabletSelection(fastest) worked, expected an error")
+	}
+
+	hc := discovery.NewFakeHealthCheck()
+	dg := createDiscoveryGateway(hc, nil, "cell", 2).(*discoveryGateway)
+	now := time.Now()
+	newTablet := func(key string, tier discovery.RoutingTier, latency time.Duration, qps float64) discovery.RoutedTabletStats {
+		dg.getTabletLoad(key).observe(latency, now)
+		return discovery.RoutedTabletStats{
+			TabletStats: discovery.TabletStats{
+				Key:   key,
+				Stats: &querypb.RealtimeStats{Qps: qps},
+			},
+			Tier: tier,
+		}
+	}
+	tablets := []discovery.RoutedTabletStats{
+		newTablet("t1", discovery.RoutingTierCell, 50*time.Millisecond, 0),
+		newTablet("t2", discovery.RoutingTierCell, 10*time.Millisecond, 0),
+		newTablet("t3", discovery.RoutingTierCell, 5*time.Millisecond, 0),
+		newTablet("t4", discovery.RoutingTierRegion, time.Millisecond, 0),
+	}
+	pick := func(invalidTablets map[string]bool) string {
+		ts := dg.pickTablet(tablets, invalidTablets)
+		if ts == nil {
+			return ""
+		}
+		return ts.Key
+	}
+
+	testcases := []struct {
+		selection string
+		invalid   map[string]bool
+		want      string
+	}{{
+		selection: tabletSelectionRandom,
+		want:      "t1",
+	}, {
+		selection: tabletSelectionRandom,
+		invalid:   map[string]bool{"t1": true},
+		want:      "t2",
+	}, {
+		selection: tabletSelectionLeastLoaded,
+		want:      "t3",
+	}, {
+		selection: tabletSelectionLeastLoaded,
+		invalid:   map[string]bool{"t3": true},
+		want:      "t2",
+	}, {
+		// only the tablets of the best tier are candidates
+		selection: tabletSelectionLeastLoaded,
+		invalid:   map[string]bool{"t1": true, "t2": true, "t3": true},
+		want:      "t4",
+	}, {
+		selection: tabletSelectionPowerOfTwo,
+		want:      "t2",
+	}, {
+		selection: tabletSelectionPowerOfTwo,
+		invalid:   map[string]bool{"t2": true},
+		want:      "t3",
+	}, {
+		selection: tabletSelectionPowerOfTwo,
+		invalid:   map[string]bool{"t1": true, "t2": true, "t3": true, "t4": true},
+		want:      "",
+	}}
+	for _, tcase := range testcases {
+		dg.tabletSelection = tcase.selection
+		if got := pick(tcase.invalid); got != tcase.want {
+			t.Errorf("%v with invalid tablets %v: got %v, want %v", tcase.selection, tcase.invalid, got, tcase.want)
+		}
+	}
+
+	// a busy tablet is avoided
+	dg.tabletSelection = tabletSelectionLeastLoaded
+	tablets[2].Stats.Qps = 10000
+	if got := pick(nil); got != "t2" {
+		t.Errorf("least_loaded with a busy t3: got %v, want t2", got)
+	}
+	tablets[2].Stats.Qps = 0
+	dg.getTabletLoad("t3").inFlight.Add(10)
+	if got := pick(nil); got != "t2" {
+		t.Errorf("least_loaded with requests in flight on t3: got %v, want t2", got)
+	}
+	dg.getTabletLoad("t3").inFlight.Add(-10)
+
+	// a tablet without samples is not free: its latency is the
+	// median of the shard, 7.5ms
+	t5 := discovery.RoutedTabletStats{
+		TabletStats: discovery.TabletStats{
+			Key:   "t5",
+			Stats: &querypb.RealtimeStats{},
+		},
+		Tier: discovery.RoutingTierCell,
+	}
+	tablets = []discovery.RoutedTabletStats{tablets[0], tablets[1], tablets[2], t5, tablets[3]}
+	if got := pick(nil); got != "t3" {
+		t.Errorf("least_loaded with an idle t5: got %v, want t3", got)
+	}
+	if got := pick(map[string]bool{"t3": true}); got != "t5" {
+		t.Errorf("least_loaded with an idle t5 and without t3: got %v, want t5", got)
+	}
+}
+
+func TestTabletLoadPercentile(t *testing.T) {
+	l := &tabletLoad{}
+	now := time.Now()
+	for i := 1; i < minHedgingSamples; i++ {
+		l.observe(time.Duration(i)*time.Millisecond, now)
+	}
+	if _, ok := l.percentile(95); ok {
+		t.Errorf("percentile worked without enough samples")
+	}
+	// 1ms to 100ms, then 101ms to 200ms overwrite them.
+	for i := minHedgingSamples; i <= 2*latencySampleCount; i++ {
+		l.observe(time.Duration(i)*time.Millisecond, now)
+	}
+	for p, want := range map[float64]time.Duration{
+		50:  150 * time.Millisecond,
+		95:  195 * time.Millisecond,
+		100: 200 * time.Millisecond,
+	} {
+		if got, ok := l.percentile(p); !ok || got != want {
+			t.Errorf("percentile(%v): got %v, %v, want %v", p, got, ok, want)
+		}
+	}
+}
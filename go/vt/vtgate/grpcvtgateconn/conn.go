@@ -466,6 +466,20 @@ func (conn *vtgateConn) MessageAckKeyspaceIds(ctx context.Context, keyspace stri
 	return int64(r.Result.RowsAffected), nil
 }
 
+func (conn *vtgateConn) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	request := &vtgatepb.MessageRequeueRequest{
+		CallerId: callerid.EffectiveCallerIDFromContext(ctx),
+		Keyspace: keyspace,
+		Name:     name,
+		Ids:      ids,
+	}
+	r, err := conn.c.MessageRequeue(ctx, request)
+	if err != nil {
+		return 0, vterrors.FromGRPC(err)
+	}
+	return int64(r.Result.RowsAffected), nil
+}
+
 func (conn *vtgateConn) SplitQuery(
 	ctx context.Context,
 	keyspace string,
@@ -537,6 +551,34 @@ func (conn *vtgateConn) UpdateStream(ctx context.Context, keyspace string, shard
 	}, nil
 }
 
+type streamChangesAdapter struct {
+	stream vtgateservicepb.Vitess_StreamChangesClient
+}
+
+func (a *streamChangesAdapter) Recv() (*vtgatepb.StreamChangesResponse, error) {
+	r, err := a.stream.Recv()
+	if err != nil {
+		return nil, vterrors.FromGRPC(err)
+	}
+	return r, nil
+}
+
+func (conn *vtgateConn) StreamChanges(ctx context.Context, keyspace string, tabletType topodatapb.TabletType, vgtid *vtgatepb.VGtid) (vtgateconn.StreamChangesReader, error) {
+	req := &vtgatepb.StreamChangesRequest{
+		CallerId:   callerid.EffectiveCallerIDFromContext(ctx),
+		Keyspace:   keyspace,
+		TabletType: tabletType,
+		Vgtid:      vgtid,
+	}
+	stream, err := conn.c.StreamChanges(ctx, req)
+	if err != nil {
+		return nil, vterrors.FromGRPC(err)
+	}
+	return &streamChangesAdapter{
+		stream: stream,
+	}, nil
+}
+
 func (conn *vtgateConn) Close() {
 	conn.cc.Close()
 }
@@ -452,6 +452,21 @@ func (vtg *VTGate) MessageAckKeyspaceIds(ctx context.Context, request *vtgatepb.
 	}, nil
 }
 
+// MessageRequeue is the RPC version of vtgateservice.VTGateService method
+func (vtg *VTGate) MessageRequeue(ctx context.Context, request *vtgatepb.MessageRequeueRequest) (response *vtgatepb.MessageRequeueResponse, err error) {
+	defer vtg.server.HandlePanic(&err)
+	ctx = withCallerIDContext(ctx, request.CallerId)
+	count, vtgErr := vtg.server.MessageRequeue(ctx, request.Keyspace, request.Name, request.Ids)
+	if vtgErr != nil {
+		return nil, vterrors.ToGRPC(vtgErr)
+	}
+	return &vtgatepb.MessageRequeueResponse{
+		Result: &querypb.QueryResult{
+			RowsAffected: uint64(count),
+		},
+	}, nil
+}
+
 // SplitQuery is the RPC version of vtgateservice.VTGateService method
 func (vtg *VTGate) SplitQuery(ctx context.Context, request *vtgatepb.SplitQueryRequest) (response *vtgatepb.SplitQueryResponse, err error) {
 
@@ -508,6 +523,22 @@ func (vtg *VTGate) UpdateStream(request *vtgatepb.UpdateStreamRequest, stream vt
 	return vterrors.ToGRPC(vtgErr)
 }
 
+// StreamChanges is the RPC version of vtgateservice.VTGateService method
+func (vtg *VTGate) StreamChanges(request *vtgatepb.StreamChangesRequest, stream vtgateservicepb.Vitess_StreamChangesServer) (err error) {
+	defer vtg.server.HandlePanic(&err)
+	ctx := withCallerIDContext(stream.Context(), request.CallerId)
+	vtgErr := vtg.server.StreamChanges(ctx,
+		request.Keyspace,
+		request.TabletType,
+		request.Vgtid,
+		func(response *vtgatepb.StreamChangesResponse) error {
+			// Send is not safe to call concurrently, but vtgate
+			// guarantees that it's not.
+			return stream.Send(response)
+		})
+	return vterrors.ToGRPC(vtgErr)
+}
+
 func init() {
 	vtgate.RegisterVTGates = append(vtgate.RegisterVTGates, func(vtGate vtgateservice.VTGateService) {
 		if servenv.GRPCCheckServiceMap("vtgateservice") {
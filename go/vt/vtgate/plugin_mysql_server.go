@@ -32,6 +32,7 @@ import (
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/sqlannotation"
 	"vitess.io/vitess/go/vt/vttls"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -117,6 +118,9 @@ func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sq
 		"VTGate MySQL Connector" /* subcomponent: part of the client */)
 	ctx = callerid.NewContext(ctx, ef, im)
 
+	// MySQL clients pass the span context of their trace in a comment.
+	ctx, query = sqlannotation.ExtractSpanContext(ctx, query)
+
 	session, _ := c.ClientData.(*vtgatepb.Session)
 	if session == nil {
 		session = &vtgatepb.Session{
@@ -0,0 +1,114 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"flag"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var (
+	readYourWritesTimeout = flag.Duration("read_your_writes_timeout", 1*time.Second, "how long a replica waits to apply the last commit of a read_your_writes session before vtgate sends the read to the master instead")
+
+	readYourWritesFallbacks = stats.NewCountersWithMultiLabels(
+		"VtgateReadYourWritesFallbacks",
+		"Read-your-writes reads sent to the master because the replica did not catch up in time",
+		[]string{"Keyspace", "ShardName"})
+)
+
+// readYourWritesOptions returns the options of a read outside of a
+// transaction, with the GTID set the tablet has to wait for if the
+// session reads its writes. It returns nil options if there is nothing
+// to wait for: the read goes to the master, or the session didn't
+// write to the shard.
+func readYourWritesOptions(session *SafeSession, target *querypb.Target, options *querypb.ExecuteOptions) *querypb.ExecuteOptions {
+	if target.TabletType == topodatapb.TabletType_MASTER || !session.ReadsYourWrites() {
+		return nil
+	}
+	gtidSet := session.WritePosition(target.Keyspace, target.Shard)
+	if gtidSet == "" {
+		return nil
+	}
+	waitOptions := &querypb.ExecuteOptions{}
+	if options != nil {
+		waitOptions = proto.Clone(options).(*querypb.ExecuteOptions)
+	}
+	waitOptions.WaitForGtidSet = gtidSet
+	waitOptions.WaitForGtidSetTimeoutMs = int64(*readYourWritesTimeout / time.Millisecond)
+	return waitOptions
+}
+
+// readYourWrites runs a read outside of a transaction for a session
+// that reads its writes. A read on a replica runs once the replica has
+// applied the last commit of the session on the shard. If the replica
+// doesn't catch up in time, the read is sent to the master instead.
+// The replica waits before it returns any result, so a read that timed
+// out waiting didn't call back with results yet.
+func (stc *ScatterConn) readYourWrites(ctx context.Context, rs *srvtopo.ResolvedShard, options *querypb.ExecuteOptions, session *SafeSession, read func(target *querypb.Target, options *querypb.ExecuteOptions) error) error {
+	waitOptions := readYourWritesOptions(session, rs.Target, options)
+	if waitOptions == nil {
+		return read(rs.Target, options)
+	}
+	err := read(rs.Target, waitOptions)
+	if err == nil || vterrors.Code(err) != vtrpcpb.Code_DEADLINE_EXCEEDED || ctx.Err() != nil {
+		return err
+	}
+
+	readYourWritesFallbacks.Add([]string{rs.Target.Keyspace, rs.Target.Shard}, 1)
+	target := proto.Clone(rs.Target).(*querypb.Target)
+	target.TabletType = topodatapb.TabletType_MASTER
+	return read(target, options)
+}
+
+// executeReadYourWrites executes a query outside of a transaction,
+// with readYourWrites.
+func (stc *ScatterConn) executeReadYourWrites(ctx context.Context, rs *srvtopo.ResolvedShard, sql string, bindVariables map[string]*querypb.BindVariable, options *querypb.ExecuteOptions, session *SafeSession) (qr *sqltypes.Result, err error) {
+	err = stc.readYourWrites(ctx, rs, options, session, func(target *querypb.Target, options *querypb.ExecuteOptions) error {
+		qr, err = rs.QueryService.Execute(ctx, target, sql, bindVariables, 0, options)
+		return err
+	})
+	return qr, err
+}
+
+// executeBatchReadYourWrites executes a batch of queries outside of a
+// transaction, with readYourWrites.
+func (stc *ScatterConn) executeBatchReadYourWrites(ctx context.Context, rs *srvtopo.ResolvedShard, queries []*querypb.BoundQuery, asTransaction bool, options *querypb.ExecuteOptions, session *SafeSession) (qrs []sqltypes.Result, err error) {
+	err = stc.readYourWrites(ctx, rs, options, session, func(target *querypb.Target, options *querypb.ExecuteOptions) error {
+		qrs, err = rs.QueryService.ExecuteBatch(ctx, target, queries, asTransaction, 0, options)
+		return err
+	})
+	return qrs, err
+}
+
+// streamExecuteReadYourWrites streams a query, with readYourWrites.
+func (stc *ScatterConn) streamExecuteReadYourWrites(ctx context.Context, rs *srvtopo.ResolvedShard, sql string, bindVariables map[string]*querypb.BindVariable, options *querypb.ExecuteOptions, session *SafeSession, callback func(*sqltypes.Result) error) error {
+	return stc.readYourWrites(ctx, rs, options, session, func(target *querypb.Target, options *querypb.ExecuteOptions) error {
+		return rs.QueryService.StreamExecute(ctx, target, sql, bindVariables, options, callback)
+	})
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/srvtopo"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestExecuteMultiShardReadYourWrites(t *testing.T) {
+	name := "TestReadYourWrites"
+	createSandbox(name)
+	hc := discovery.NewFakeHealthCheck()
+	sc := newTestScatterConn(hc, new(sandboxTopo), "aa")
+	master := hc.AddTestTablet("aa", "0", 1, name, "0", topodatapb.TabletType_MASTER, true, 1, nil)
+	replica0 := hc.AddTestTablet("aa", "1", 1, name, "0", topodatapb.TabletType_REPLICA, true, 1, nil)
+	replica1 := hc.AddTestTablet("aa", "2", 1, name, "1", topodatapb.TabletType_REPLICA, true, 1, nil)
+	res := srvtopo.NewResolver(&sandboxTopo{}, sc.gateway, "aa")
+	rss, err := res.ResolveDestination(context.Background(), name, topodatapb.TabletType_REPLICA, key.DestinationShards([]string{"0", "1"}))
+	if err != nil {
+		t.Fatalf("ResolveDestination failed: %v", err)
+	}
+	queries := []*querypb.BoundQuery{{Sql: "query1"}, {Sql: "query1"}}
+	session := NewSafeSession(&vtgatepb.Session{
+		ReadYourWrites: true,
+		WritePositions: []*vtgatepb.Session_WritePosition{{
+			Keyspace:     name,
+			Shard:        "0",
+			GtidExecuted: "gtid:1-10",
+		}},
+	})
+
+	// The replica of shard 0 waits for the last commit, the
+	// replica of shard 1 doesn't have to wait.
+	if _, err := sc.ExecuteMultiShard(context.Background(), rss, queries, topodatapb.TabletType_REPLICA, session, false, false); err != nil {
+		t.Fatalf("ExecuteMultiShard failed: %v", err)
+	}
+	if len(replica0.Options) != 1 || replica0.Options[0].GetWaitForGtidSet() != "gtid:1-10" {
+		t.Errorf("replica0.Options: %v, want a wait for gtid:1-10", replica0.Options)
+	}
+	if got, want := replica0.Options[0].GetWaitForGtidSetTimeoutMs(), int64(1000); got != want {
+		t.Errorf("WaitForGtidSetTimeoutMs: %v, want %v", got, want)
+	}
+	if len(replica1.Options) != 1 || replica1.Options[0].GetWaitForGtidSet() != "" {
+		t.Errorf("replica1.Options: %v, want no wait", replica1.Options)
+	}
+
+	// If the replica doesn't catch up, the read goes to the master.
+	replica0.MustFailCodes[vtrpcpb.Code_DEADLINE_EXCEEDED] = 1
+	if _, err := sc.ExecuteMultiShard(context.Background(), rss[:1], queries[:1], topodatapb.TabletType_REPLICA, session, false, false); err != nil {
+		t.Fatalf("ExecuteMultiShard failed: %v", err)
+	}
+	if execCount := master.ExecCount.Get(); execCount != 1 {
+		t.Errorf("master.ExecCount: %v, want 1", execCount)
+	}
+	if len(master.Options) != 1 || master.Options[0].GetWaitForGtidSet() != "" {
+		t.Errorf("master.Options: %v, want no wait", master.Options)
+	}
+
+	// Without read_your_writes, the positions are ignored.
+	session.ReadYourWrites = false
+	replica0.Options = nil
+	if _, err := sc.ExecuteMultiShard(context.Background(), rss[:1], queries[:1], topodatapb.TabletType_REPLICA, session, false, false); err != nil {
+		t.Fatalf("ExecuteMultiShard failed: %v", err)
+	}
+	if len(replica0.Options) != 1 || replica0.Options[0].GetWaitForGtidSet() != "" {
+		t.Errorf("replica0.Options: %v, want no wait", replica0.Options)
+	}
+}
+
+func TestReadYourWritesPaths(t *testing.T) {
+	name := "TestReadYourWritesPaths"
+	createSandbox(name)
+	hc := discovery.NewFakeHealthCheck()
+	sc := newTestScatterConn(hc, new(sandboxTopo), "aa")
+	master := hc.AddTestTablet("aa", "0", 1, name, "0", topodatapb.TabletType_MASTER, true, 1, nil)
+	replica := hc.AddTestTablet("aa", "1", 1, name, "0", topodatapb.TabletType_REPLICA, true, 1, nil)
+	res := srvtopo.NewResolver(&sandboxTopo{}, sc.gateway, "aa")
+	rss, err := res.ResolveDestination(context.Background(), name, topodatapb.TabletType_REPLICA, key.DestinationShard("0"))
+	if err != nil {
+		t.Fatalf("ResolveDestination failed: %v", err)
+	}
+	session := NewSafeSession(&vtgatepb.Session{
+		ReadYourWrites: true,
+		WritePositions: []*vtgatepb.Session_WritePosition{{
+			Keyspace:     name,
+			Shard:        "0",
+			GtidExecuted: "gtid:1-10",
+		}},
+	})
+	callback := func(*sqltypes.Result) error { return nil }
+
+	testcases := []struct {
+		name string
+		run  func() error
+	}{{
+		name: "Execute",
+		run: func() error {
+			_, err := sc.Execute(context.Background(), "query1", nil, rss, topodatapb.TabletType_REPLICA, session, false, nil)
+			return err
+		},
+	}, {
+		name: "ExecuteEntityIds",
+		run: func() error {
+			_, err := sc.ExecuteEntityIds(context.Background(), rss, []string{"query1"}, []map[string]*querypb.BindVariable{nil}, topodatapb.TabletType_REPLICA, session, false, nil)
+			return err
+		},
+	}, {
+		name: "ExecuteBatch",
+		run: func() error {
+			batchRequest := &scatterBatchRequest{
+				length: 1,
+				requests: map[string]*shardBatchRequest{
+					name + ":0": {
+						rs:            rss[0],
+						queries:       []*querypb.BoundQuery{{Sql: "query1"}},
+						resultIndexes: []int{0},
+					},
+				},
+			}
+			_, err := sc.ExecuteBatch(context.Background(), batchRequest, topodatapb.TabletType_REPLICA, false, session, nil)
+			return err
+		},
+	}, {
+		name: "StreamExecute",
+		run: func() error {
+			return sc.StreamExecute(context.Background(), "query1", nil, rss, topodatapb.TabletType_REPLICA, session, nil, callback)
+		},
+	}, {
+		name: "StreamExecuteMulti",
+		run: func() error {
+			return sc.StreamExecuteMulti(context.Background(), "query1", rss, []map[string]*querypb.BindVariable{nil}, topodatapb.TabletType_REPLICA, session, nil, callback)
+		},
+	}}
+	for _, tcase := range testcases {
+		// The replica waits for the last commit.
+		replica.Options = nil
+		if err := tcase.run(); err != nil {
+			t.Errorf("%v failed: %v", tcase.name, err)
+			continue
+		}
+		if len(replica.Options) != 1 || replica.Options[0].GetWaitForGtidSet() != "gtid:1-10" {
+			t.Errorf("%v: replica.Options: %v, want a wait for gtid:1-10", tcase.name, replica.Options)
+		}
+
+		// If the replica doesn't catch up, the read goes to the master.
+		master.ExecCount.Set(0)
+		master.Options = nil
+		replica.MustFailCodes[vtrpcpb.Code_DEADLINE_EXCEEDED] = 1
+		if err := tcase.run(); err != nil {
+			t.Errorf("%v failed: %v", tcase.name, err)
+			continue
+		}
+		if execCount := master.ExecCount.Get(); execCount != 1 {
+			t.Errorf("%v: master.ExecCount: %v, want 1", tcase.name, execCount)
+		}
+		if len(master.Options) != 1 || master.Options[0].GetWaitForGtidSet() != "" {
+			t.Errorf("%v: master.Options: %v, want no wait", tcase.name, master.Options)
+		}
+	}
+}
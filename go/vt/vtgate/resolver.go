@@ -245,6 +245,7 @@ func (res *Resolver) StreamExecute(
 	keyspace string,
 	tabletType topodatapb.TabletType,
 	destination key.Destination,
+	session *SafeSession,
 	options *querypb.ExecuteOptions,
 	callback func(*sqltypes.Result) error,
 ) error {
@@ -258,6 +259,7 @@ func (res *Resolver) StreamExecute(
 		bindVars,
 		rss,
 		tabletType,
+		session,
 		options,
 		callback)
 	return err
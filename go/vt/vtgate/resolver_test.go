@@ -134,6 +134,7 @@ func TestResolverStreamExecuteKeyspaceIds(t *testing.T) {
 			topodatapb.TabletType_MASTER,
 			key.DestinationKeyspaceIDs([][]byte{{0x10}, {0x15}}),
 			nil,
+			nil,
 			func(r *sqltypes.Result) error {
 				qr.AppendResult(r)
 				return nil
@@ -149,6 +150,7 @@ func TestResolverStreamExecuteKeyspaceIds(t *testing.T) {
 			topodatapb.TabletType_MASTER,
 			key.DestinationKeyspaceIDs([][]byte{{0x10}, {0x15}, {0x25}}),
 			nil,
+			nil,
 			func(r *sqltypes.Result) error {
 				qr.AppendResult(r)
 				return nil
@@ -169,6 +171,7 @@ func TestResolverStreamExecuteKeyRanges(t *testing.T) {
 			topodatapb.TabletType_MASTER,
 			key.DestinationKeyRanges([]*topodatapb.KeyRange{{Start: []byte{0x10}, End: []byte{0x15}}}),
 			nil,
+			nil,
 			func(r *sqltypes.Result) error {
 				qr.AppendResult(r)
 				return nil
@@ -185,6 +188,7 @@ func TestResolverStreamExecuteKeyRanges(t *testing.T) {
 			topodatapb.TabletType_MASTER,
 			key.DestinationKeyRanges([]*topodatapb.KeyRange{{Start: []byte{0x10}, End: []byte{0x25}}}),
 			nil,
+			nil,
 			func(r *sqltypes.Result) error {
 				qr.AppendResult(r)
 				return nil
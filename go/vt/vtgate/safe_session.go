@@ -182,6 +182,51 @@ func (session *SafeSession) MustRollback() bool {
 	return session.mustRollback
 }
 
+// ReadsYourWrites returns true if the non-master reads of the session
+// must see its own commits.
+func (session *SafeSession) ReadsYourWrites() bool {
+	if session == nil || session.Session == nil {
+		return false
+	}
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.ReadYourWrites
+}
+
+// WritePosition returns the GTID set executed by the master of a shard
+// after the last commit of the session on it, or "" if there is none.
+func (session *SafeSession) WritePosition(keyspace, shard string) string {
+	if session == nil || session.Session == nil {
+		return ""
+	}
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	for _, writePosition := range session.WritePositions {
+		if keyspace == writePosition.Keyspace && shard == writePosition.Shard {
+			return writePosition.GtidExecuted
+		}
+	}
+	return ""
+}
+
+// SetWritePosition records the GTID set executed by the master of a
+// shard after a commit of the session.
+func (session *SafeSession) SetWritePosition(keyspace, shard, gtidExecuted string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	for _, writePosition := range session.WritePositions {
+		if keyspace == writePosition.Keyspace && shard == writePosition.Shard {
+			writePosition.GtidExecuted = gtidExecuted
+			return
+		}
+	}
+	session.WritePositions = append(session.WritePositions, &vtgatepb.Session_WritePosition{
+		Keyspace:     keyspace,
+		Shard:        shard,
+		GtidExecuted: gtidExecuted,
+	})
+}
+
 // Reset clears the session
 func (session *SafeSession) Reset() {
 	if session == nil || session.Session == nil {
@@ -27,6 +27,7 @@ import (
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/key"
@@ -61,7 +62,7 @@ type ScatterConn struct {
 // return an error if any.  multiGo is capable of executing
 // multiple shardActionFunc actions in parallel and
 // consolidating the results and errors for the caller.
-type shardActionFunc func(rs *srvtopo.ResolvedShard, i int) error
+type shardActionFunc func(ctx context.Context, rs *srvtopo.ResolvedShard, i int) error
 
 // shardActionTransactionFunc defines the contract for a shard action
 // that may be in a transaction. Every such function executes the
@@ -70,7 +71,7 @@ type shardActionFunc func(rs *srvtopo.ResolvedShard, i int) error
 // multiGoTransaction is capable of executing multiple
 // shardActionTransactionFunc actions in parallel and consolidating
 // the results and errors for the caller.
-type shardActionTransactionFunc func(rs *srvtopo.ResolvedShard, i int, shouldBegin bool, transactionID int64) (int64, error)
+type shardActionTransactionFunc func(ctx context.Context, rs *srvtopo.ResolvedShard, i int, shouldBegin bool, transactionID int64) (int64, error)
 
 // NewScatterConn creates a new ScatterConn.
 func NewScatterConn(statsName string, txConn *TxConn, gw gateway.Gateway, hc discovery.HealthCheck) *ScatterConn {
@@ -116,6 +117,25 @@ func (stc *ScatterConn) endAction(startTime time.Time, allErrors *concurrency.Al
 	stc.timings.Record(statsKey, startTime)
 }
 
+// startShardSpan starts the client span of a call to a shard. The
+// returned context carries the span, for the tablet to continue the
+// trace.
+func startShardSpan(ctx context.Context, name string, target *querypb.Target) (trace.Span, context.Context) {
+	span := trace.NewSpanFromContext(ctx)
+	span.StartClient("ScatterConn." + name)
+	span.Annotate("keyspace", target.Keyspace)
+	span.Annotate("shard", target.Shard)
+	span.Annotate("tablet_type", topoproto.TabletTypeLString(target.TabletType))
+	return span, trace.NewContext(ctx, span)
+}
+
+func finishShardSpan(span trace.Span, err *error) {
+	if *err != nil {
+		span.Annotate("error", *err)
+	}
+	span.Finish()
+}
+
 // Execute executes a non-streaming query on the specified shards.
 func (stc *ScatterConn) Execute(
 	ctx context.Context,
@@ -139,7 +159,7 @@ func (stc *ScatterConn) Execute(
 		tabletType,
 		session,
 		notInTransaction,
-		func(rs *srvtopo.ResolvedShard, i int, shouldBegin bool, transactionID int64) (int64, error) {
+		func(ctx context.Context, rs *srvtopo.ResolvedShard, i int, shouldBegin bool, transactionID int64) (int64, error) {
 			var innerqr *sqltypes.Result
 			if shouldBegin {
 				var err error
@@ -147,6 +167,12 @@ func (stc *ScatterConn) Execute(
 				if err != nil {
 					return transactionID, err
 				}
+			} else if transactionID == 0 && session.ReadsYourWrites() {
+				var err error
+				innerqr, err = stc.executeReadYourWrites(ctx, rs, query, bindVars, options, session)
+				if err != nil {
+					return transactionID, err
+				}
 			} else {
 				var err error
 				innerqr, err = rs.QueryService.Execute(ctx, rs.Target, query, bindVars, transactionID, options)
@@ -186,7 +212,7 @@ func (stc *ScatterConn) ExecuteMultiShard(
 		tabletType,
 		session,
 		notInTransaction,
-		func(rs *srvtopo.ResolvedShard, i int, shouldBegin bool, transactionID int64) (int64, error) {
+		func(ctx context.Context, rs *srvtopo.ResolvedShard, i int, shouldBegin bool, transactionID int64) (int64, error) {
 			var (
 				innerqr *sqltypes.Result
 				err     error
@@ -201,6 +227,8 @@ func (stc *ScatterConn) ExecuteMultiShard(
 				innerqr, err = stc.executeAutocommit(ctx, rs, queries[i].Sql, queries[i].BindVariables, opts)
 			case shouldBegin:
 				innerqr, transactionID, err = rs.QueryService.BeginExecute(ctx, rs.Target, queries[i].Sql, queries[i].BindVariables, opts)
+			case transactionID == 0 && session.ReadsYourWrites():
+				innerqr, err = stc.executeReadYourWrites(ctx, rs, queries[i].Sql, queries[i].BindVariables, opts, session)
 			default:
 				innerqr, err = rs.QueryService.Execute(ctx, rs.Target, queries[i].Sql, queries[i].BindVariables, transactionID, opts)
 			}
@@ -253,13 +281,16 @@ func (stc *ScatterConn) ExecuteEntityIds(
 		tabletType,
 		session,
 		notInTransaction,
-		func(rs *srvtopo.ResolvedShard, i int, shouldBegin bool, transactionID int64) (int64, error) {
+		func(ctx context.Context, rs *srvtopo.ResolvedShard, i int, shouldBegin bool, transactionID int64) (int64, error) {
 			var innerqr *sqltypes.Result
 			var err error
 
-			if shouldBegin {
+			switch {
+			case shouldBegin:
 				innerqr, transactionID, err = rs.QueryService.BeginExecute(ctx, rs.Target, sqls[i], bindVars[i], options)
-			} else {
+			case transactionID == 0 && session.ReadsYourWrites():
+				innerqr, err = stc.executeReadYourWrites(ctx, rs, sqls[i], bindVars[i], options, session)
+			default:
 				innerqr, err = rs.QueryService.Execute(ctx, rs.Target, sqls[i], bindVars[i], transactionID, options)
 			}
 			if err != nil {
@@ -388,6 +419,11 @@ func (stc *ScatterConn) ExecuteBatch(
 				if err != nil {
 					return
 				}
+			} else if transactionID == 0 && session.ReadsYourWrites() {
+				innerqrs, err = stc.executeBatchReadYourWrites(ctx, req.rs, req.queries, asTransaction, options, session)
+				if err != nil {
+					return
+				}
 			} else {
 				innerqrs, err = req.rs.QueryService.ExecuteBatch(ctx, req.rs.Target, req.queries, asTransaction, transactionID, options)
 				if err != nil {
@@ -441,6 +477,7 @@ func (stc *ScatterConn) StreamExecute(
 	bindVars map[string]*querypb.BindVariable,
 	rss []*srvtopo.ResolvedShard,
 	tabletType topodatapb.TabletType,
+	session *SafeSession,
 	options *querypb.ExecuteOptions,
 	callback func(reply *sqltypes.Result) error,
 ) error {
@@ -449,8 +486,8 @@ func (stc *ScatterConn) StreamExecute(
 	var mu sync.Mutex
 	fieldSent := false
 
-	allErrors := stc.multiGo(ctx, "StreamExecute", rss, tabletType, func(rs *srvtopo.ResolvedShard, i int) error {
-		return rs.QueryService.StreamExecute(ctx, rs.Target, query, bindVars, options, func(qr *sqltypes.Result) error {
+	allErrors := stc.multiGo(ctx, "StreamExecute", rss, tabletType, func(ctx context.Context, rs *srvtopo.ResolvedShard, i int) error {
+		return stc.streamExecuteReadYourWrites(ctx, rs, query, bindVars, options, session, func(qr *sqltypes.Result) error {
 			return stc.processOneStreamingResult(&mu, &fieldSent, qr, callback)
 		})
 	})
@@ -468,6 +505,7 @@ func (stc *ScatterConn) StreamExecuteMulti(
 	rss []*srvtopo.ResolvedShard,
 	bindVars []map[string]*querypb.BindVariable,
 	tabletType topodatapb.TabletType,
+	session *SafeSession,
 	options *querypb.ExecuteOptions,
 	callback func(reply *sqltypes.Result) error,
 ) error {
@@ -475,8 +513,8 @@ func (stc *ScatterConn) StreamExecuteMulti(
 	var mu sync.Mutex
 	fieldSent := false
 
-	allErrors := stc.multiGo(ctx, "StreamExecute", rss, tabletType, func(rs *srvtopo.ResolvedShard, i int) error {
-		return rs.QueryService.StreamExecute(ctx, rs.Target, query, bindVars[i], options, func(qr *sqltypes.Result) error {
+	allErrors := stc.multiGo(ctx, "StreamExecute", rss, tabletType, func(ctx context.Context, rs *srvtopo.ResolvedShard, i int) error {
+		return stc.streamExecuteReadYourWrites(ctx, rs, query, bindVars[i], options, session, func(qr *sqltypes.Result) error {
 			return stc.processOneStreamingResult(&mu, &fieldSent, qr, callback)
 		})
 	})
@@ -529,7 +567,7 @@ func (stc *ScatterConn) MessageStream(ctx context.Context, rss []*srvtopo.Resolv
 	var mu sync.Mutex
 	fieldSent := false
 	lastErrors := newTimeTracker()
-	allErrors := stc.multiGo(ctx, "MessageStream", rss, topodatapb.TabletType_MASTER, func(rs *srvtopo.ResolvedShard, i int) error {
+	allErrors := stc.multiGo(ctx, "MessageStream", rss, topodatapb.TabletType_MASTER, func(ctx context.Context, rs *srvtopo.ResolvedShard, i int) error {
 		// This loop handles the case where a reparent happens, which can cause
 		// an individual stream to end. If we don't succeed on the retries for
 		// messageStreamGracePeriod, we abort and return an error.
@@ -575,7 +613,7 @@ func (stc *ScatterConn) MessageStream(ctx context.Context, rss []*srvtopo.Resolv
 func (stc *ScatterConn) MessageAck(ctx context.Context, rss []*srvtopo.ResolvedShard, values [][]*querypb.Value, name string) (int64, error) {
 	var mu sync.Mutex
 	var totalCount int64
-	allErrors := stc.multiGo(ctx, "MessageAck", rss, topodatapb.TabletType_MASTER, func(rs *srvtopo.ResolvedShard, i int) error {
+	allErrors := stc.multiGo(ctx, "MessageAck", rss, topodatapb.TabletType_MASTER, func(ctx context.Context, rs *srvtopo.ResolvedShard, i int) error {
 		count, err := rs.QueryService.MessageAck(ctx, rs.Target, name, values[i])
 		if err != nil {
 			return err
@@ -622,7 +660,7 @@ func (stc *ScatterConn) SplitQuery(
 		"SplitQuery",
 		rss,
 		tabletType,
-		func(rs *srvtopo.ResolvedShard, i int) error {
+		func(ctx context.Context, rs *srvtopo.ResolvedShard, i int) error {
 			// Get all splits from this shard
 			query := &querypb.BoundQuery{
 				Sql:           sql,
@@ -732,7 +770,9 @@ func (stc *ScatterConn) multiGo(
 		var err error
 		startTime, statsKey := stc.startAction(name, rs.Target)
 		defer stc.endAction(startTime, allErrors, statsKey, &err, nil)
-		err = action(rs, i)
+		span, ctx := startShardSpan(ctx, name, rs.Target)
+		defer finishShardSpan(span, &err)
+		err = action(ctx, rs, i)
 	}
 
 	if len(rss) == 1 {
@@ -777,9 +817,11 @@ func (stc *ScatterConn) multiGoTransaction(
 		var err error
 		startTime, statsKey := stc.startAction(name, rs.Target)
 		defer stc.endAction(startTime, allErrors, statsKey, &err, session)
+		span, ctx := startShardSpan(ctx, name, rs.Target)
+		defer finishShardSpan(span, &err)
 
 		shouldBegin, transactionID := transactionInfo(rs.Target, session, notInTransaction)
-		transactionID, err = action(rs, i, shouldBegin, transactionID)
+		transactionID, err = action(ctx, rs, i, shouldBegin, transactionID)
 		if shouldBegin && transactionID != 0 {
 			if appendErr := session.Append(&vtgatepb.Session_ShardSession{
 				Target:        rs.Target,
@@ -102,13 +102,32 @@ func (txc *TxConn) commitNormal(ctx context.Context, session *SafeSession) error
 			txc.gateway.Rollback(ctx, shardSession.Target, shardSession.TransactionId)
 			continue
 		}
-		if err = txc.gateway.Commit(ctx, shardSession.Target, shardSession.TransactionId); err != nil {
+		if err = txc.commitShard(ctx, session, shardSession); err != nil {
 			committing = false
 		}
 	}
 	return err
 }
 
+// commitShard commits the transaction of a shard. If the session
+// reads its writes, it also records the position of the commit.
+func (txc *TxConn) commitShard(ctx context.Context, session *SafeSession, shardSession *vtgatepb.Session_ShardSession) error {
+	gtidExecuted, err := txc.gateway.Commit(ctx, shardSession.Target, shardSession.TransactionId, session.ReadsYourWrites())
+	if err != nil {
+		return err
+	}
+	setWritePosition(session, shardSession.Target, gtidExecuted)
+	return nil
+}
+
+// setWritePosition records the position of a commit on a shard, if the
+// tablet returned one: the session reads its writes.
+func setWritePosition(session *SafeSession, target *querypb.Target, gtidExecuted string) {
+	if gtidExecuted != "" {
+		session.SetWritePosition(target.Keyspace, target.Shard, gtidExecuted)
+	}
+}
+
 func (txc *TxConn) commit2PC(ctx context.Context, session *SafeSession) error {
 	// If the number of participants is one or less, then it's a normal commit.
 	if len(session.ShardSessions) <= 1 {
@@ -141,13 +160,21 @@ func (txc *TxConn) commit2PC(ctx context.Context, session *SafeSession) error {
 		return err
 	}
 
-	err = txc.gateway.StartCommit(ctx, mmShard.Target, mmShard.TransactionId, dtid)
+	// The positions of the commits are recorded like in commitShard.
+	readYourWrites := session.ReadsYourWrites()
+	gtidExecuted, err := txc.gateway.StartCommit(ctx, mmShard.Target, mmShard.TransactionId, dtid, readYourWrites)
 	if err != nil {
 		return err
 	}
+	setWritePosition(session, mmShard.Target, gtidExecuted)
 
 	err = txc.runSessions(session.ShardSessions[1:], func(s *vtgatepb.Session_ShardSession) error {
-		return txc.gateway.CommitPrepared(ctx, s.Target, dtid)
+		gtidExecuted, err := txc.gateway.CommitPrepared(ctx, s.Target, dtid, readYourWrites)
+		if err != nil {
+			return err
+		}
+		setWritePosition(session, s.Target, gtidExecuted)
+		return nil
 	})
 	if err != nil {
 		return err
@@ -218,7 +245,8 @@ func (txc *TxConn) resumeRollback(ctx context.Context, target *querypb.Target, t
 
 func (txc *TxConn) resumeCommit(ctx context.Context, target *querypb.Target, transaction *querypb.TransactionMetadata) error {
 	err := txc.runTargets(transaction.Participants, func(t *querypb.Target) error {
-		return txc.gateway.CommitPrepared(ctx, t, transaction.Dtid)
+		_, err := txc.gateway.CommitPrepared(ctx, t, transaction.Dtid, false /* includeGTIDExecuted */)
+		return err
 	})
 	if err != nil {
 		return err
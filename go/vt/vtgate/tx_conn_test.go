@@ -162,6 +162,48 @@ func TestTxConnCommitSuccess(t *testing.T) {
 	}
 }
 
+func TestTxConnCommitReadYourWrites(t *testing.T) {
+	sc, sbc0, sbc1, rss0, _, rss01 := newTestTxConnEnv(t, "TestTxConn")
+	sc.txConn.mode = vtgatepb.TransactionMode_MULTI
+	sbc0.GTIDExecuted = "gtid0:1-10"
+	sbc1.GTIDExecuted = "gtid1:1-20"
+
+	// Sequence the executes to ensure commit order
+	session := NewSafeSession(&vtgatepb.Session{InTransaction: true, ReadYourWrites: true})
+	sc.Execute(context.Background(), "query1", nil, rss0, topodatapb.TabletType_MASTER, session, false, nil)
+	sc.Execute(context.Background(), "query1", nil, rss01, topodatapb.TabletType_MASTER, session, false, nil)
+	if err := sc.txConn.Commit(context.Background(), session); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	wantSession := vtgatepb.Session{
+		ReadYourWrites: true,
+		WritePositions: []*vtgatepb.Session_WritePosition{{
+			Keyspace:     "TestTxConn",
+			Shard:        "0",
+			GtidExecuted: "gtid0:1-10",
+		}, {
+			Keyspace:     "TestTxConn",
+			Shard:        "1",
+			GtidExecuted: "gtid1:1-20",
+		}},
+	}
+	if !proto.Equal(session.Session, &wantSession) {
+		t.Errorf("Session:\n%+v, want\n%+v", *session.Session, wantSession)
+	}
+
+	// A later commit on a shard replaces its position.
+	sbc0.GTIDExecuted = "gtid0:1-11"
+	session.Session.InTransaction = true
+	sc.Execute(context.Background(), "query1", nil, rss0, topodatapb.TabletType_MASTER, session, false, nil)
+	if err := sc.txConn.Commit(context.Background(), session); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	wantSession.WritePositions[0].GtidExecuted = "gtid0:1-11"
+	if !proto.Equal(session.Session, &wantSession) {
+		t.Errorf("Session:\n%+v, want\n%+v", *session.Session, wantSession)
+	}
+}
+
 func TestTxConnCommit2PC(t *testing.T) {
 	sc, sbc0, sbc1, rss0, _, rss01 := newTestTxConnEnv(t, "TestTxConnCommit2PC")
 
@@ -189,6 +231,36 @@ func TestTxConnCommit2PC(t *testing.T) {
 	}
 }
 
+func TestTxConnCommit2PCReadYourWrites(t *testing.T) {
+	sc, sbc0, sbc1, rss0, _, rss01 := newTestTxConnEnv(t, "TestTxConnCommit2PCReadYourWrites")
+	sbc0.GTIDExecuted = "gtid0:1-10"
+	sbc1.GTIDExecuted = "gtid1:1-20"
+
+	session := NewSafeSession(&vtgatepb.Session{InTransaction: true, ReadYourWrites: true})
+	sc.Execute(context.Background(), "query1", nil, rss0, topodatapb.TabletType_MASTER, session, false, nil)
+	sc.Execute(context.Background(), "query1", nil, rss01, topodatapb.TabletType_MASTER, session, false, nil)
+	session.TransactionMode = vtgatepb.TransactionMode_TWOPC
+	if err := sc.txConn.Commit(context.Background(), session); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	wantSession := vtgatepb.Session{
+		TransactionMode: vtgatepb.TransactionMode_TWOPC,
+		ReadYourWrites:  true,
+		WritePositions: []*vtgatepb.Session_WritePosition{{
+			Keyspace:     "TestTxConnCommit2PCReadYourWrites",
+			Shard:        "0",
+			GtidExecuted: "gtid0:1-10",
+		}, {
+			Keyspace:     "TestTxConnCommit2PCReadYourWrites",
+			Shard:        "1",
+			GtidExecuted: "gtid1:1-20",
+		}},
+	}
+	if !proto.Equal(session.Session, &wantSession) {
+		t.Errorf("Session:\n%+v, want\n%+v", *session.Session, wantSession)
+	}
+}
+
 func TestTxConnCommit2PCOneParticipant(t *testing.T) {
 	sc, sbc0, _, rss0, _, _ := newTestTxConnEnv(t, "TestTxConnCommit2PCOneParticipant")
 	session := NewSafeSession(&vtgatepb.Session{InTransaction: true})
@@ -43,6 +43,7 @@ import (
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/cdc"
 
 	"vitess.io/vitess/go/vt/vtgate/gateway"
 	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
@@ -140,6 +141,7 @@ type VTGate struct {
 	logStreamExecuteShards      *logutil.ThrottledLogger
 	logUpdateStream             *logutil.ThrottledLogger
 	logMessageStream            *logutil.ThrottledLogger
+	logStreamChanges            *logutil.ThrottledLogger
 }
 
 // RegisterVTGate defines the type of registration mechanism.
@@ -226,6 +228,7 @@ func Init(ctx context.Context, hc discovery.HealthCheck, serv srvtopo.Server, ce
 		logStreamExecuteShards:      logutil.NewThrottledLogger("StreamExecuteShards", 5*time.Second),
 		logUpdateStream:             logutil.NewThrottledLogger("UpdateStream", 5*time.Second),
 		logMessageStream:            logutil.NewThrottledLogger("MessageStream", 5*time.Second),
+		logStreamChanges:            logutil.NewThrottledLogger("StreamChanges", 5*time.Second),
 	}
 
 	errorCounts = stats.NewCountersWithMultiLabels("VtgateApiErrorCounts", "Vtgate API error counts per error type", []string{"Operation", "Keyspace", "DbType", "Code"})
@@ -368,6 +371,7 @@ func (vtg *VTGate) StreamExecute(ctx context.Context, session *vtgatepb.Session,
 			destKeyspace,
 			destTabletType,
 			dest,
+			NewSafeSession(session),
 			session.Options,
 			func(reply *sqltypes.Result) error {
 				vtg.rowsReturned.Add(statsKey, int64(len(reply.Rows)))
@@ -696,6 +700,7 @@ func (vtg *VTGate) StreamExecuteKeyspaceIds(ctx context.Context, sql string, bin
 		keyspace,
 		tabletType,
 		key.DestinationKeyspaceIDs(keyspaceIds),
+		nil, /* session */
 		options,
 		func(reply *sqltypes.Result) error {
 			vtg.rowsReturned.Add(statsKey, int64(len(reply.Rows)))
@@ -745,6 +750,7 @@ func (vtg *VTGate) StreamExecuteKeyRanges(ctx context.Context, sql string, bindV
 		keyspace,
 		tabletType,
 		key.DestinationKeyRanges(keyRanges),
+		nil, /* session */
 		options,
 		func(reply *sqltypes.Result) error {
 			vtg.rowsReturned.Add(statsKey, int64(len(reply.Rows)))
@@ -789,6 +795,7 @@ func (vtg *VTGate) StreamExecuteShards(ctx context.Context, sql string, bindVari
 		keyspace,
 		tabletType,
 		key.DestinationShards(shards),
+		nil, /* session */
 		options,
 		func(reply *sqltypes.Result) error {
 			vtg.rowsReturned.Add(statsKey, int64(len(reply.Rows)))
@@ -1023,6 +1030,26 @@ func (vtg *VTGate) MessageAckKeyspaceIds(ctx context.Context, keyspace string, n
 	return count, formatError(err)
 }
 
+// MessageRequeue is part of the vtgate service API. Like MessageAck, the
+// table name is resolved using V3 rules, and the ids are routed with the
+// vindexes of sharded keyspaces. Without ids, the failed messages of all
+// the shards are requeued.
+func (vtg *VTGate) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	startTime := time.Now()
+	ltt := topoproto.TabletTypeLString(topodatapb.TabletType_MASTER)
+	statsKey := []string{"MessageRequeue", keyspace, ltt}
+	defer vtg.timings.Record(statsKey, startTime)
+
+	for _, id := range ids {
+		if _, err := sqltypes.NewValue(id.Type, id.Value); err != nil {
+			return 0, formatError(err)
+		}
+	}
+
+	count, err := vtg.executor.MessageRequeue(ctx, keyspace, name, ids)
+	return count, formatError(err)
+}
+
 // UpdateStream is part of the vtgate service API.
 // Note we guarantee the callback will not be called concurrently
 // by mutiple go routines, as the current implementation can only target
@@ -1056,6 +1083,28 @@ func (vtg *VTGate) UpdateStream(ctx context.Context, keyspace string, shard stri
 	return formatError(err)
 }
 
+// StreamChanges is part of the vtgate service API.
+// It streams the changes of all the shards of the keyspace, merged. The
+// callback is not called concurrently.
+func (vtg *VTGate) StreamChanges(ctx context.Context, keyspace string, tabletType topodatapb.TabletType, vgtid *vtgatepb.VGtid, callback func(*vtgatepb.StreamChangesResponse) error) error {
+	startTime := time.Now()
+	ltt := topoproto.TabletTypeLString(tabletType)
+	statsKey := []string{"StreamChanges", keyspace, ltt}
+	defer vtg.timings.Record(statsKey, startTime)
+
+	s := cdc.NewStreamer(vtg.resolver.toposerv, vtg.gw, vtg.resolver.cell, keyspace, tabletType)
+	err := s.Stream(ctx, vgtid, callback)
+	if err != nil {
+		request := map[string]interface{}{
+			"Keyspace":   keyspace,
+			"TabletType": ltt,
+			"Vgtid":      vgtid,
+		}
+		recordAndAnnotateError(err, statsKey, request, vtg.logStreamChanges)
+	}
+	return formatError(err)
+}
+
 // GetGatewayCacheStatus returns a displayable version of the Gateway cache.
 func (vtg *VTGate) GetGatewayCacheStatus() gateway.TabletCacheStatusList {
 	return vtg.resolver.GetGatewayCacheStatus()
@@ -140,6 +140,12 @@ func (conn *VTGateConn) MessageAckKeyspaceIds(ctx context.Context, keyspace stri
 	return conn.impl.MessageAckKeyspaceIds(ctx, keyspace, name, idKeyspaceIDs)
 }
 
+// MessageRequeue requeues the failed messages with the given ids, or
+// all of them if ids is empty.
+func (conn *VTGateConn) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	return conn.impl.MessageRequeue(ctx, keyspace, name, ids)
+}
+
 // Begin starts a transaction and returns a VTGateTX.
 func (conn *VTGateConn) Begin(ctx context.Context) (*VTGateTx, error) {
 	session, err := conn.impl.Begin(ctx, false /* singledb */)
@@ -186,6 +192,22 @@ func (conn *VTGateConn) UpdateStream(ctx context.Context, keyspace, shard string
 	return conn.impl.UpdateStream(ctx, keyspace, shard, keyRange, tabletType, timestamp, event)
 }
 
+// StreamChangesReader is returned by StreamChanges.
+type StreamChangesReader interface {
+	// Recv returns the next result on the stream.
+	// It will return io.EOF if the stream ended.
+	Recv() (*vtgatepb.StreamChangesResponse, error)
+}
+
+// StreamChanges asks vtgate for the change data capture stream of a
+// keyspace, starting after vgtid, or at the current position of its
+// masters if vgtid is nil. It returns a StreamChangesReader and an
+// error. First check the error. Then you can pull values from the
+// StreamChangesReader until io.EOF, or another error.
+func (conn *VTGateConn) StreamChanges(ctx context.Context, keyspace string, tabletType topodatapb.TabletType, vgtid *vtgatepb.VGtid) (StreamChangesReader, error) {
+	return conn.impl.StreamChanges(ctx, keyspace, tabletType, vgtid)
+}
+
 // VTGateSession exposes the V3 API to the clients.
 // The object maintains client-side state and is comparable to a native MySQL connection.
 // For example, if you enable autocommit on a Session object, all subsequent calls will respect this.
@@ -366,6 +388,7 @@ type Impl interface {
 	MessageStream(ctx context.Context, keyspace string, shard string, keyRange *topodatapb.KeyRange, name string, callback func(*sqltypes.Result) error) error
 	MessageAck(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error)
 	MessageAckKeyspaceIds(ctx context.Context, keyspace string, name string, idKeyspaceIDs []*vtgatepb.IdKeyspaceId) (int64, error)
+	MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error)
 
 	// SplitQuery splits a query into smaller queries. It is mostly used by batch job frameworks
 	// such as MapReduce. See the documentation for the vtgate.SplitQueryRequest protocol buffer
@@ -378,6 +401,9 @@ type Impl interface {
 	// UpdateStream asks for a stream of StreamEvent.
 	UpdateStream(ctx context.Context, keyspace string, shard string, keyRange *topodatapb.KeyRange, tabletType topodatapb.TabletType, timestamp int64, event *querypb.EventToken) (UpdateStreamReader, error)
 
+	// StreamChanges asks for the change data capture stream of a keyspace.
+	StreamChanges(ctx context.Context, keyspace string, tabletType topodatapb.TabletType, vgtid *vtgatepb.VGtid) (StreamChangesReader, error)
+
 	// Close must be called for releasing resources.
 	Close()
 }
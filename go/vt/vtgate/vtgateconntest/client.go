@@ -815,6 +815,23 @@ func (f *fakeVTGateService) MessageAckKeyspaceIds(ctx context.Context, keyspace
 	return messageAckRowsAffected, nil
 }
 
+func (f *fakeVTGateService) MessageRequeue(ctx context.Context, keyspace string, name string, ids []*querypb.Value) (int64, error) {
+	if f.hasError {
+		return 0, errTestVtGateError
+	}
+	if f.panics {
+		panic(fmt.Errorf("test forced panic"))
+	}
+	f.checkCallerID(ctx, "MessageRequeue")
+	if name != messageName {
+		return 0, errors.New("MessageRequeue name mismatch")
+	}
+	if !sqltypes.Proto3ValuesEqual(ids, messageids) {
+		return 0, errors.New("MessageRequeue ids mismatch")
+	}
+	return messageAckRowsAffected, nil
+}
+
 // querySplitQuery contains all the fields we use to test SplitQuery
 type querySplitQuery struct {
 	Keyspace            string
@@ -959,6 +976,50 @@ func (f *fakeVTGateService) UpdateStream(ctx context.Context, keyspace string, s
 	return nil
 }
 
+var streamChangesVGtid = &vtgatepb.VGtid{
+	ShardGtids: []*vtgatepb.ShardGtid{{
+		Keyspace: "ks",
+		Shard:    "-80",
+		Gtid:     "MariaDB/0-1-1",
+	}},
+}
+
+var streamChangesResponses = []*vtgatepb.StreamChangesResponse{{
+	Shard: "-80",
+	Event: &querypb.ChangeEvent{
+		Timestamp: 123,
+		Position:  "MariaDB/0-1-2",
+		Changes: []*querypb.RowChange{{
+			Type:  querypb.RowChange_INSERT,
+			Table: "t",
+		}},
+	},
+	Vgtid: &vtgatepb.VGtid{
+		ShardGtids: []*vtgatepb.ShardGtid{{
+			Keyspace: "ks",
+			Shard:    "-80",
+			Gtid:     "MariaDB/0-1-2",
+		}},
+	},
+}}
+
+// StreamChanges is part of the VTGateService interface
+func (f *fakeVTGateService) StreamChanges(ctx context.Context, keyspace string, tabletType topodatapb.TabletType, vgtid *vtgatepb.VGtid, callback func(*vtgatepb.StreamChangesResponse) error) error {
+	if f.panics {
+		panic(fmt.Errorf("test forced panic"))
+	}
+	f.checkCallerID(ctx, "StreamChanges")
+	if keyspace != "ks" || tabletType != topodatapb.TabletType_REPLICA || !proto.Equal(vgtid, streamChangesVGtid) {
+		return fmt.Errorf("no match for: %v %v %v", keyspace, tabletType, vgtid)
+	}
+	for _, response := range streamChangesResponses {
+		if err := callback(response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CreateFakeServer returns the fake server for the tests
 func CreateFakeServer(t *testing.T) vtgateservice.VTGateService {
 	return &fakeVTGateService{
@@ -1016,9 +1077,11 @@ func TestSuite(t *testing.T, impl vtgateconn.Impl, fakeServer vtgateservice.VTGa
 	testMessageStream(t, conn)
 	testMessageAck(t, conn)
 	testMessageAckKeyspaceIds(t, conn)
+	testMessageRequeue(t, conn)
 	testSplitQuery(t, conn)
 	testGetSrvKeyspace(t, conn)
 	testUpdateStream(t, conn)
+	testStreamChanges(t, conn)
 
 	// force a panic at every call, then test that works
 	fs.panics = true
@@ -1041,9 +1104,11 @@ func TestSuite(t *testing.T, impl vtgateconn.Impl, fakeServer vtgateservice.VTGa
 	testMessageStreamPanic(t, conn)
 	testMessageAckPanic(t, conn)
 	testMessageAckKeyspaceIdsPanic(t, conn)
+	testMessageRequeuePanic(t, conn)
 	testSplitQueryPanic(t, conn)
 	testGetSrvKeyspacePanic(t, conn)
 	testUpdateStreamPanic(t, conn)
+	testStreamChangesPanic(t, conn)
 	fs.panics = false
 }
 
@@ -1078,6 +1143,7 @@ func TestErrorSuite(t *testing.T, fakeServer vtgateservice.VTGateService) {
 	testMessageStreamError(t, conn)
 	testMessageAckError(t, conn)
 	testMessageAckKeyspaceIdsError(t, conn)
+	testMessageRequeueError(t, conn)
 	testSplitQueryError(t, conn)
 	testGetSrvKeyspaceError(t, conn)
 	testUpdateStreamError(t, conn, fs)
@@ -2034,6 +2100,29 @@ func testMessageAckKeyspaceIdsPanic(t *testing.T, conn *vtgateconn.VTGateConn) {
 	expectPanic(t, err)
 }
 
+func testMessageRequeue(t *testing.T, conn *vtgateconn.VTGateConn) {
+	ctx := newContext()
+	got, err := conn.MessageRequeue(ctx, "", messageName, messageids)
+	if got != messageAckRowsAffected {
+		t.Errorf("MessageRequeue: %d, want %d", got, messageAckRowsAffected)
+	}
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func testMessageRequeueError(t *testing.T, conn *vtgateconn.VTGateConn) {
+	ctx := newContext()
+	_, err := conn.MessageRequeue(ctx, "", messageName, messageids)
+	verifyError(t, err, "MessageRequeue")
+}
+
+func testMessageRequeuePanic(t *testing.T, conn *vtgateconn.VTGateConn) {
+	ctx := newContext()
+	_, err := conn.MessageRequeue(ctx, "", messageName, messageids)
+	expectPanic(t, err)
+}
+
 func testSplitQuery(t *testing.T, conn *vtgateconn.VTGateConn) {
 	ctx := newContext()
 	qsl, err := conn.SplitQuery(ctx,
@@ -2187,6 +2276,51 @@ func testUpdateStreamPanic(t *testing.T, conn *vtgateconn.VTGateConn) {
 	expectPanic(t, err)
 }
 
+func testStreamChanges(t *testing.T, conn *vtgateconn.VTGateConn) {
+	ctx := newContext()
+	stream, err := conn.StreamChanges(ctx, "ks", topodatapb.TabletType_REPLICA, streamChangesVGtid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*vtgatepb.StreamChangesResponse
+	for {
+		response, err := stream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				t.Error(err)
+			}
+			break
+		}
+		got = append(got, response)
+	}
+	if len(got) != len(streamChangesResponses) || !proto.Equal(got[0], streamChangesResponses[0]) {
+		t.Errorf("Unexpected result from StreamChanges: got %v want %v", got, streamChangesResponses)
+	}
+
+	stream, err = conn.StreamChanges(ctx, "none", topodatapb.TabletType_REPLICA, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = stream.Recv()
+	want := "no match for: none"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("none request: %v, want %v", err, want)
+	}
+}
+
+func testStreamChangesPanic(t *testing.T, conn *vtgateconn.VTGateConn) {
+	ctx := newContext()
+	stream, err := conn.StreamChanges(ctx, "ks", topodatapb.TabletType_REPLICA, streamChangesVGtid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = stream.Recv()
+	if err == nil {
+		t.Fatalf("Received packets instead of panic?")
+	}
+	expectPanic(t, err)
+}
+
 var testCallerID = &vtrpcpb.CallerID{
 	Principal:    "test_principal",
 	Component:    "test_component",
@@ -279,6 +279,19 @@ func (mr *MockVTGateServiceMockRecorder) MessageAckKeyspaceIds(ctx, keyspace, na
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MessageAckKeyspaceIds", reflect.TypeOf((*MockVTGateService)(nil).MessageAckKeyspaceIds), ctx, keyspace, name, idKeyspaceIDs)
 }
 
+// MessageRequeue mocks base method
+func (m *MockVTGateService) MessageRequeue(ctx context.Context, keyspace, name string, ids []*query.Value) (int64, error) {
+	ret := m.ctrl.Call(m, "MessageRequeue", ctx, keyspace, name, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MessageRequeue indicates an expected call of MessageRequeue
+func (mr *MockVTGateServiceMockRecorder) MessageRequeue(ctx, keyspace, name, ids interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MessageRequeue", reflect.TypeOf((*MockVTGateService)(nil).MessageRequeue), ctx, keyspace, name, ids)
+}
+
 // SplitQuery mocks base method
 func (m *MockVTGateService) SplitQuery(ctx context.Context, keyspace, sql string, bindVariables map[string]*query.BindVariable, splitColumns []string, splitCount, numRowsPerQueryPart int64, algorithm query.SplitQueryRequest_Algorithm) ([]*vtgate.SplitQueryResponse_Part, error) {
 	ret := m.ctrl.Call(m, "SplitQuery", ctx, keyspace, sql, bindVariables, splitColumns, splitCount, numRowsPerQueryPart, algorithm)
@@ -317,6 +330,18 @@ func (mr *MockVTGateServiceMockRecorder) UpdateStream(ctx, keyspace, shard, keyR
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStream", reflect.TypeOf((*MockVTGateService)(nil).UpdateStream), ctx, keyspace, shard, keyRange, tabletType, timestamp, event, callback)
 }
 
+// StreamChanges mocks base method
+func (m *MockVTGateService) StreamChanges(ctx context.Context, keyspace string, tabletType topodata.TabletType, vgtid *vtgate.VGtid, callback func(*vtgate.StreamChangesResponse) error) error {
+	ret := m.ctrl.Call(m, "StreamChanges", ctx, keyspace, tabletType, vgtid, callback)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamChanges indicates an expected call of StreamChanges
+func (mr *MockVTGateServiceMockRecorder) StreamChanges(ctx, keyspace, tabletType, vgtid, callback interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamChanges", reflect.TypeOf((*MockVTGateService)(nil).StreamChanges), ctx, keyspace, tabletType, vgtid, callback)
+}
+
 // HandlePanic mocks base method
 func (m *MockVTGateService) HandlePanic(err *error) {
 	m.ctrl.Call(m, "HandlePanic", err)
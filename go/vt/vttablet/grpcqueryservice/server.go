@@ -109,10 +109,13 @@ func (q *query) Commit(ctx context.Context, request *querypb.CommitRequest) (res
 		request.EffectiveCallerId,
 		request.ImmediateCallerId,
 	)
-	if err := q.server.Commit(ctx, request.Target, request.TransactionId); err != nil {
+	gtidExecuted, err := q.server.Commit(ctx, request.Target, request.TransactionId, request.IncludeGtidExecuted)
+	if err != nil {
 		return nil, vterrors.ToGRPC(err)
 	}
-	return &querypb.CommitResponse{}, nil
+	return &querypb.CommitResponse{
+		GtidExecuted: gtidExecuted,
+	}, nil
 }
 
 // Rollback is part of the queryservice.QueryServer interface
@@ -150,11 +153,14 @@ func (q *query) CommitPrepared(ctx context.Context, request *querypb.CommitPrepa
 		request.EffectiveCallerId,
 		request.ImmediateCallerId,
 	)
-	if err := q.server.CommitPrepared(ctx, request.Target, request.Dtid); err != nil {
+	gtidExecuted, err := q.server.CommitPrepared(ctx, request.Target, request.Dtid, request.IncludeGtidExecuted)
+	if err != nil {
 		return nil, vterrors.ToGRPC(err)
 	}
 
-	return &querypb.CommitPreparedResponse{}, nil
+	return &querypb.CommitPreparedResponse{
+		GtidExecuted: gtidExecuted,
+	}, nil
 }
 
 // RollbackPrepared is part of the queryservice.QueryServer interface
@@ -192,11 +198,14 @@ func (q *query) StartCommit(ctx context.Context, request *querypb.StartCommitReq
 		request.EffectiveCallerId,
 		request.ImmediateCallerId,
 	)
-	if err := q.server.StartCommit(ctx, request.Target, request.TransactionId, request.Dtid); err != nil {
+	gtidExecuted, err := q.server.StartCommit(ctx, request.Target, request.TransactionId, request.Dtid, request.IncludeGtidExecuted)
+	if err != nil {
 		return nil, vterrors.ToGRPC(err)
 	}
 
-	return &querypb.StartCommitResponse{}, nil
+	return &querypb.StartCommitResponse{
+		GtidExecuted: gtidExecuted,
+	}, nil
 }
 
 // SetRollback is part of the queryservice.QueryServer interface
@@ -375,6 +384,23 @@ func (q *query) UpdateStream(request *querypb.UpdateStreamRequest, stream querys
 	return nil
 }
 
+// StreamChanges is part of the queryservice.QueryServer interface
+func (q *query) StreamChanges(request *querypb.StreamChangesRequest, stream queryservicepb.Query_StreamChangesServer) (err error) {
+	defer q.server.HandlePanic(&err)
+	ctx := callerid.NewContext(callinfo.GRPCCallInfo(stream.Context()),
+		request.EffectiveCallerId,
+		request.ImmediateCallerId,
+	)
+	if err := q.server.StreamChanges(ctx, request.Target, request.Position, func(reply *querypb.ChangeEvent) error {
+		return stream.Send(&querypb.StreamChangesResponse{
+			Event: reply,
+		})
+	}); err != nil {
+		return vterrors.ToGRPC(err)
+	}
+	return nil
+}
+
 // Register registers the implementation on the provide gRPC Server.
 func Register(s *grpc.Server, server queryservice.QueryService) {
 	queryservicepb.RegisterQueryServer(s, &query{server})
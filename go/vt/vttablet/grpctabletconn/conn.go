@@ -216,24 +216,25 @@ func (conn *gRPCQueryClient) Begin(ctx context.Context, target *querypb.Target,
 }
 
 // Commit commits the ongoing transaction.
-func (conn *gRPCQueryClient) Commit(ctx context.Context, target *querypb.Target, transactionID int64) error {
+func (conn *gRPCQueryClient) Commit(ctx context.Context, target *querypb.Target, transactionID int64, includeGTIDExecuted bool) (string, error) {
 	conn.mu.RLock()
 	defer conn.mu.RUnlock()
 	if conn.cc == nil {
-		return tabletconn.ConnClosed
+		return "", tabletconn.ConnClosed
 	}
 
 	req := &querypb.CommitRequest{
-		Target:            target,
-		EffectiveCallerId: callerid.EffectiveCallerIDFromContext(ctx),
-		ImmediateCallerId: callerid.ImmediateCallerIDFromContext(ctx),
-		TransactionId:     transactionID,
+		Target:              target,
+		EffectiveCallerId:   callerid.EffectiveCallerIDFromContext(ctx),
+		ImmediateCallerId:   callerid.ImmediateCallerIDFromContext(ctx),
+		TransactionId:       transactionID,
+		IncludeGtidExecuted: includeGTIDExecuted,
 	}
-	_, err := conn.c.Commit(ctx, req)
+	res, err := conn.c.Commit(ctx, req)
 	if err != nil {
-		return tabletconn.ErrorFromGRPC(err)
+		return "", tabletconn.ErrorFromGRPC(err)
 	}
-	return nil
+	return res.GtidExecuted, nil
 }
 
 // Rollback rolls back the ongoing transaction.
@@ -280,24 +281,25 @@ func (conn *gRPCQueryClient) Prepare(ctx context.Context, target *querypb.Target
 }
 
 // CommitPrepared commits the prepared transaction.
-func (conn *gRPCQueryClient) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string) error {
+func (conn *gRPCQueryClient) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string, includeGTIDExecuted bool) (string, error) {
 	conn.mu.RLock()
 	defer conn.mu.RUnlock()
 	if conn.cc == nil {
-		return tabletconn.ConnClosed
+		return "", tabletconn.ConnClosed
 	}
 
 	req := &querypb.CommitPreparedRequest{
-		Target:            target,
-		EffectiveCallerId: callerid.EffectiveCallerIDFromContext(ctx),
-		ImmediateCallerId: callerid.ImmediateCallerIDFromContext(ctx),
-		Dtid:              dtid,
+		Target:              target,
+		EffectiveCallerId:   callerid.EffectiveCallerIDFromContext(ctx),
+		ImmediateCallerId:   callerid.ImmediateCallerIDFromContext(ctx),
+		Dtid:                dtid,
+		IncludeGtidExecuted: includeGTIDExecuted,
 	}
-	_, err := conn.c.CommitPrepared(ctx, req)
+	res, err := conn.c.CommitPrepared(ctx, req)
 	if err != nil {
-		return tabletconn.ErrorFromGRPC(err)
+		return "", tabletconn.ErrorFromGRPC(err)
 	}
-	return nil
+	return res.GtidExecuted, nil
 }
 
 // RollbackPrepared rolls back the prepared transaction.
@@ -346,25 +348,26 @@ func (conn *gRPCQueryClient) CreateTransaction(ctx context.Context, target *quer
 
 // StartCommit atomically commits the transaction along with the
 // decision to commit the associated 2pc transaction.
-func (conn *gRPCQueryClient) StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string) error {
+func (conn *gRPCQueryClient) StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string, includeGTIDExecuted bool) (string, error) {
 	conn.mu.RLock()
 	defer conn.mu.RUnlock()
 	if conn.cc == nil {
-		return tabletconn.ConnClosed
+		return "", tabletconn.ConnClosed
 	}
 
 	req := &querypb.StartCommitRequest{
-		Target:            target,
-		EffectiveCallerId: callerid.EffectiveCallerIDFromContext(ctx),
-		ImmediateCallerId: callerid.ImmediateCallerIDFromContext(ctx),
-		TransactionId:     transactionID,
-		Dtid:              dtid,
+		Target:              target,
+		EffectiveCallerId:   callerid.EffectiveCallerIDFromContext(ctx),
+		ImmediateCallerId:   callerid.ImmediateCallerIDFromContext(ctx),
+		TransactionId:       transactionID,
+		Dtid:                dtid,
+		IncludeGtidExecuted: includeGTIDExecuted,
 	}
-	_, err := conn.c.StartCommit(ctx, req)
+	res, err := conn.c.StartCommit(ctx, req)
 	if err != nil {
-		return tabletconn.ErrorFromGRPC(err)
+		return "", tabletconn.ErrorFromGRPC(err)
 	}
-	return nil
+	return res.GtidExecuted, nil
 }
 
 // SetRollback transitions the 2pc transaction to the Rollback state.
@@ -668,6 +671,48 @@ func (conn *gRPCQueryClient) UpdateStream(ctx context.Context, target *querypb.T
 	}
 }
 
+// StreamChanges starts a change data capture stream from VTTablet.
+func (conn *gRPCQueryClient) StreamChanges(ctx context.Context, target *querypb.Target, position string, callback func(*querypb.ChangeEvent) error) error {
+	// Please see comments in StreamExecute to see how this works.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := func() (queryservicepb.Query_StreamChangesClient, error) {
+		conn.mu.RLock()
+		defer conn.mu.RUnlock()
+		if conn.cc == nil {
+			return nil, tabletconn.ConnClosed
+		}
+
+		req := &querypb.StreamChangesRequest{
+			Target:            target,
+			EffectiveCallerId: callerid.EffectiveCallerIDFromContext(ctx),
+			ImmediateCallerId: callerid.ImmediateCallerIDFromContext(ctx),
+			Position:          position,
+		}
+		stream, err := conn.c.StreamChanges(ctx, req)
+		if err != nil {
+			return nil, tabletconn.ErrorFromGRPC(err)
+		}
+		return stream, nil
+	}()
+	if err != nil {
+		return err
+	}
+	for {
+		r, err := stream.Recv()
+		if err != nil {
+			return tabletconn.ErrorFromGRPC(err)
+		}
+		if err := callback(r.Event); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
 // HandlePanic is a no-op.
 func (conn *gRPCQueryClient) HandlePanic(err *error) {
 }
@@ -42,8 +42,10 @@ type QueryService interface {
 	// Begin returns the transaction id to use for further operations
 	Begin(ctx context.Context, target *querypb.Target, options *querypb.ExecuteOptions) (int64, error)
 
-	// Commit commits the current transaction
-	Commit(ctx context.Context, target *querypb.Target, transactionID int64) error
+	// Commit commits the current transaction. If includeGTIDExecuted
+	// is true, it also returns the replication position of MySQL after
+	// the commit, encoded with its flavor.
+	Commit(ctx context.Context, target *querypb.Target, transactionID int64, includeGTIDExecuted bool) (gtidExecuted string, err error)
 
 	// Rollback aborts the current transaction
 	Rollback(ctx context.Context, target *querypb.Target, transactionID int64) error
@@ -51,8 +53,9 @@ type QueryService interface {
 	// Prepare prepares the specified transaction.
 	Prepare(ctx context.Context, target *querypb.Target, transactionID int64, dtid string) (err error)
 
-	// CommitPrepared commits the prepared transaction.
-	CommitPrepared(ctx context.Context, target *querypb.Target, dtid string) (err error)
+	// CommitPrepared commits the prepared transaction. It returns the
+	// replication position like Commit.
+	CommitPrepared(ctx context.Context, target *querypb.Target, dtid string, includeGTIDExecuted bool) (gtidExecuted string, err error)
 
 	// RollbackPrepared rolls back the prepared transaction.
 	RollbackPrepared(ctx context.Context, target *querypb.Target, dtid string, originalID int64) (err error)
@@ -61,8 +64,9 @@ type QueryService interface {
 	CreateTransaction(ctx context.Context, target *querypb.Target, dtid string, participants []*querypb.Target) (err error)
 
 	// StartCommit atomically commits the transaction along with the
-	// decision to commit the associated 2pc transaction.
-	StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string) (err error)
+	// decision to commit the associated 2pc transaction. It returns
+	// the replication position like Commit.
+	StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string, includeGTIDExecuted bool) (gtidExecuted string, err error)
 
 	// SetRollback transitions the 2pc transaction to the Rollback state.
 	// If a transaction id is provided, that transaction is also rolled back.
@@ -99,6 +103,10 @@ type QueryService interface {
 	// UpdateStream streams updates from the provided position or timestamp.
 	UpdateStream(ctx context.Context, target *querypb.Target, position string, timestamp int64, callback func(*querypb.StreamEvent) error) error
 
+	// StreamChanges streams the changes made to the database from the
+	// provided position, or from the current position if it is empty.
+	StreamChanges(ctx context.Context, target *querypb.Target, position string, callback func(*querypb.ChangeEvent) error) error
+
 	// StreamHealth streams health status.
 	StreamHealth(ctx context.Context, callback func(*querypb.StreamHealthResponse) error) error
 
@@ -17,6 +17,8 @@ limitations under the License.
 package queryservice
 
 import (
+	"sync"
+
 	"golang.org/x/net/context"
 
 	"vitess.io/vitess/go/sqltypes"
@@ -33,6 +35,10 @@ var _ QueryService = &wrappedService{}
 // The inner function returns err and canRetry.
 // If canRetry is true, the error is specific to the current vttablet and can be retried elsewhere.
 // The flag will be false if there was no error.
+// For Execute and ExecuteBatch outside of a transaction, inner can be called
+// concurrently (for hedged requests), and the result of the first successful
+// call is returned. The wrapper must not return before all the calls of
+// inner returned.
 type WrapperFunc func(ctx context.Context, target *querypb.Target, conn QueryService, name string, inTransaction bool, inner func(context.Context, *querypb.Target, QueryService) (err error, canRetry bool)) error
 
 // Wrap returns a wrapped version of the original QueryService implementation.
@@ -91,11 +97,13 @@ func (ws *wrappedService) Begin(ctx context.Context, target *querypb.Target, opt
 	return transactionID, err
 }
 
-func (ws *wrappedService) Commit(ctx context.Context, target *querypb.Target, transactionID int64) error {
-	return ws.wrapper(ctx, target, ws.impl, "Commit", true, func(ctx context.Context, target *querypb.Target, conn QueryService) (error, bool) {
-		innerErr := conn.Commit(ctx, target, transactionID)
+func (ws *wrappedService) Commit(ctx context.Context, target *querypb.Target, transactionID int64, includeGTIDExecuted bool) (gtidExecuted string, err error) {
+	err = ws.wrapper(ctx, target, ws.impl, "Commit", true, func(ctx context.Context, target *querypb.Target, conn QueryService) (error, bool) {
+		var innerErr error
+		gtidExecuted, innerErr = conn.Commit(ctx, target, transactionID, includeGTIDExecuted)
 		return innerErr, canRetry(ctx, innerErr)
 	})
+	return gtidExecuted, err
 }
 
 func (ws *wrappedService) Rollback(ctx context.Context, target *querypb.Target, transactionID int64) error {
@@ -112,11 +120,13 @@ func (ws *wrappedService) Prepare(ctx context.Context, target *querypb.Target, t
 	})
 }
 
-func (ws *wrappedService) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string) (err error) {
-	return ws.wrapper(ctx, target, ws.impl, "CommitPrepared", true, func(ctx context.Context, target *querypb.Target, conn QueryService) (error, bool) {
-		innerErr := conn.CommitPrepared(ctx, target, dtid)
+func (ws *wrappedService) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string, includeGTIDExecuted bool) (gtidExecuted string, err error) {
+	err = ws.wrapper(ctx, target, ws.impl, "CommitPrepared", true, func(ctx context.Context, target *querypb.Target, conn QueryService) (error, bool) {
+		var innerErr error
+		gtidExecuted, innerErr = conn.CommitPrepared(ctx, target, dtid, includeGTIDExecuted)
 		return innerErr, canRetry(ctx, innerErr)
 	})
+	return gtidExecuted, err
 }
 
 func (ws *wrappedService) RollbackPrepared(ctx context.Context, target *querypb.Target, dtid string, originalID int64) (err error) {
@@ -133,11 +143,13 @@ func (ws *wrappedService) CreateTransaction(ctx context.Context, target *querypb
 	})
 }
 
-func (ws *wrappedService) StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string) (err error) {
-	return ws.wrapper(ctx, target, ws.impl, "StartCommit", true, func(ctx context.Context, target *querypb.Target, conn QueryService) (error, bool) {
-		innerErr := conn.StartCommit(ctx, target, transactionID, dtid)
+func (ws *wrappedService) StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string, includeGTIDExecuted bool) (gtidExecuted string, err error) {
+	err = ws.wrapper(ctx, target, ws.impl, "StartCommit", true, func(ctx context.Context, target *querypb.Target, conn QueryService) (error, bool) {
+		var innerErr error
+		gtidExecuted, innerErr = conn.StartCommit(ctx, target, transactionID, dtid, includeGTIDExecuted)
 		return innerErr, canRetry(ctx, innerErr)
 	})
+	return gtidExecuted, err
 }
 
 func (ws *wrappedService) SetRollback(ctx context.Context, target *querypb.Target, dtid string, transactionID int64) (err error) {
@@ -165,13 +177,23 @@ func (ws *wrappedService) ReadTransaction(ctx context.Context, target *querypb.T
 
 func (ws *wrappedService) Execute(ctx context.Context, target *querypb.Target, query string, bindVars map[string]*querypb.BindVariable, transactionID int64, options *querypb.ExecuteOptions) (qr *sqltypes.Result, err error) {
 	inTransaction := (transactionID != 0)
+	// With hedged requests, the first successful result is kept.
+	var mu sync.Mutex
+	succeeded := false
 	err = ws.wrapper(ctx, target, ws.impl, "Execute", inTransaction, func(ctx context.Context, target *querypb.Target, conn QueryService) (error, bool) {
-		var innerErr error
-		qr, innerErr = conn.Execute(ctx, target, query, bindVars, transactionID, options)
+		innerQr, innerErr := conn.Execute(ctx, target, query, bindVars, transactionID, options)
+		mu.Lock()
+		if !succeeded {
+			qr = innerQr
+			succeeded = innerErr == nil
+		}
+		mu.Unlock()
 		// You cannot retry if you're in a transaction.
 		retryable := canRetry(ctx, innerErr) && (!inTransaction)
 		return innerErr, retryable
 	})
+	mu.Lock()
+	defer mu.Unlock()
 	return qr, err
 }
 
@@ -190,13 +212,23 @@ func (ws *wrappedService) StreamExecute(ctx context.Context, target *querypb.Tar
 
 func (ws *wrappedService) ExecuteBatch(ctx context.Context, target *querypb.Target, queries []*querypb.BoundQuery, asTransaction bool, transactionID int64, options *querypb.ExecuteOptions) (qrs []sqltypes.Result, err error) {
 	inTransaction := (transactionID != 0)
+	// With hedged requests, the first successful result is kept.
+	var mu sync.Mutex
+	succeeded := false
 	err = ws.wrapper(ctx, target, ws.impl, "ExecuteBatch", inTransaction, func(ctx context.Context, target *querypb.Target, conn QueryService) (error, bool) {
-		var innerErr error
-		qrs, innerErr = conn.ExecuteBatch(ctx, target, queries, asTransaction, transactionID, options)
+		innerQrs, innerErr := conn.ExecuteBatch(ctx, target, queries, asTransaction, transactionID, options)
+		mu.Lock()
+		if !succeeded {
+			qrs = innerQrs
+			succeeded = innerErr == nil
+		}
+		mu.Unlock()
 		// You cannot retry if you're in a transaction.
 		retryable := canRetry(ctx, innerErr) && (!inTransaction)
 		return innerErr, retryable
 	})
+	mu.Lock()
+	defer mu.Unlock()
 	return qrs, err
 }
 
@@ -250,6 +282,13 @@ func (ws *wrappedService) UpdateStream(ctx context.Context, target *querypb.Targ
 	})
 }
 
+func (ws *wrappedService) StreamChanges(ctx context.Context, target *querypb.Target, position string, callback func(*querypb.ChangeEvent) error) error {
+	return ws.wrapper(ctx, target, ws.impl, "StreamChanges", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (error, bool) {
+		innerErr := conn.StreamChanges(ctx, target, position, callback)
+		return innerErr, canRetry(ctx, innerErr)
+	})
+}
+
 func (ws *wrappedService) StreamHealth(ctx context.Context, callback func(*querypb.StreamHealthResponse) error) error {
 	return ws.wrapper(ctx, nil, ws.impl, "StreamHealth", false, func(ctx context.Context, target *querypb.Target, conn QueryService) (error, bool) {
 		innerErr := conn.StreamHealth(ctx, callback)
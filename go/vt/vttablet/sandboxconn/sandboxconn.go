@@ -85,6 +85,10 @@ type SandboxConn struct {
 
 	MessageIDs []*querypb.Value
 
+	// GTIDExecuted is returned by the commits to the callers that
+	// ask for it.
+	GTIDExecuted string
+
 	// transaction id generator
 	TransactionID sync2.AtomicInt64
 }
@@ -181,9 +185,20 @@ func (sbc *SandboxConn) Begin(ctx context.Context, target *querypb.Target, optio
 }
 
 // Commit is part of the QueryService interface.
-func (sbc *SandboxConn) Commit(ctx context.Context, target *querypb.Target, transactionID int64) error {
+func (sbc *SandboxConn) Commit(ctx context.Context, target *querypb.Target, transactionID int64, includeGTIDExecuted bool) (string, error) {
 	sbc.CommitCount.Add(1)
-	return sbc.getError()
+	if err := sbc.getError(); err != nil {
+		return "", err
+	}
+	return sbc.gtidExecuted(includeGTIDExecuted), nil
+}
+
+// gtidExecuted returns GTIDExecuted, if the caller asked for it.
+func (sbc *SandboxConn) gtidExecuted(includeGTIDExecuted bool) string {
+	if !includeGTIDExecuted {
+		return ""
+	}
+	return sbc.GTIDExecuted
 }
 
 // Rollback is part of the QueryService interface.
@@ -203,13 +218,16 @@ func (sbc *SandboxConn) Prepare(ctx context.Context, target *querypb.Target, tra
 }
 
 // CommitPrepared commits the prepared transaction.
-func (sbc *SandboxConn) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string) (err error) {
+func (sbc *SandboxConn) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string, includeGTIDExecuted bool) (string, error) {
 	sbc.CommitPreparedCount.Add(1)
 	if sbc.MustFailCommitPrepared > 0 {
 		sbc.MustFailCommitPrepared--
-		return vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, "error: err")
+		return "", vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, "error: err")
 	}
-	return sbc.getError()
+	if err := sbc.getError(); err != nil {
+		return "", err
+	}
+	return sbc.gtidExecuted(includeGTIDExecuted), nil
 }
 
 // RollbackPrepared rolls back the prepared transaction.
@@ -234,13 +252,16 @@ func (sbc *SandboxConn) CreateTransaction(ctx context.Context, target *querypb.T
 
 // StartCommit atomically commits the transaction along with the
 // decision to commit the associated 2pc transaction.
-func (sbc *SandboxConn) StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string) (err error) {
+func (sbc *SandboxConn) StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string, includeGTIDExecuted bool) (string, error) {
 	sbc.StartCommitCount.Add(1)
 	if sbc.MustFailStartCommit > 0 {
 		sbc.MustFailStartCommit--
-		return vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, "error: err")
+		return "", vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, "error: err")
 	}
-	return sbc.getError()
+	if err := sbc.getError(); err != nil {
+		return "", err
+	}
+	return sbc.gtidExecuted(includeGTIDExecuted), nil
 }
 
 // SetRollback transitions the 2pc transaction to the Rollback state.
@@ -358,6 +379,11 @@ func (sbc *SandboxConn) UpdateStream(ctx context.Context, target *querypb.Target
 	return fmt.Errorf("Not implemented in test")
 }
 
+// StreamChanges is part of the QueryService interface.
+func (sbc *SandboxConn) StreamChanges(ctx context.Context, target *querypb.Target, position string, callback func(*querypb.ChangeEvent) error) error {
+	return fmt.Errorf("Not implemented in test")
+}
+
 // HandlePanic is part of the QueryService interface.
 func (sbc *SandboxConn) HandlePanic(err *error) {
 }
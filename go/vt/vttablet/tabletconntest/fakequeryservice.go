@@ -150,10 +150,21 @@ func (f *FakeQueryService) Begin(ctx context.Context, target *querypb.Target, op
 // CommitTransactionID is a test transaction id for Commit.
 const CommitTransactionID int64 = 999044
 
+// GTIDExecuted is a test replication position returned by the commits.
+const GTIDExecuted = "MySQL56/8bc65c84-3fe4-11e6-a87a-0242ac110003:1-10"
+
+// gtidExecuted returns GTIDExecuted, if the caller asked for it.
+func gtidExecuted(includeGTIDExecuted bool) string {
+	if !includeGTIDExecuted {
+		return ""
+	}
+	return GTIDExecuted
+}
+
 // Commit is part of the queryservice.QueryService interface
-func (f *FakeQueryService) Commit(ctx context.Context, target *querypb.Target, transactionID int64) error {
+func (f *FakeQueryService) Commit(ctx context.Context, target *querypb.Target, transactionID int64, includeGTIDExecuted bool) (string, error) {
 	if f.HasError {
-		return f.TabletError
+		return "", f.TabletError
 	}
 	if f.Panics {
 		panic(fmt.Errorf("test-triggered panic"))
@@ -162,7 +173,7 @@ func (f *FakeQueryService) Commit(ctx context.Context, target *querypb.Target, t
 	if transactionID != CommitTransactionID {
 		f.t.Errorf("Commit: invalid TransactionId: got %v expected %v", transactionID, CommitTransactionID)
 	}
-	return nil
+	return gtidExecuted(includeGTIDExecuted), nil
 }
 
 // RollbackTransactionID is a test transactin id for Rollback.
@@ -205,9 +216,9 @@ func (f *FakeQueryService) Prepare(ctx context.Context, target *querypb.Target,
 }
 
 // CommitPrepared is part of the queryservice.QueryService interface
-func (f *FakeQueryService) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string) (err error) {
+func (f *FakeQueryService) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string, includeGTIDExecuted bool) (string, error) {
 	if f.HasError {
-		return f.TabletError
+		return "", f.TabletError
 	}
 	if f.Panics {
 		panic(fmt.Errorf("test-triggered panic"))
@@ -216,7 +227,7 @@ func (f *FakeQueryService) CommitPrepared(ctx context.Context, target *querypb.T
 	if dtid != Dtid {
 		f.t.Errorf("CommitPrepared: invalid dtid: got %s expected %s", dtid, Dtid)
 	}
-	return nil
+	return gtidExecuted(includeGTIDExecuted), nil
 }
 
 // RollbackPrepared is part of the queryservice.QueryService interface
@@ -278,9 +289,9 @@ func (f *FakeQueryService) CreateTransaction(ctx context.Context, target *queryp
 }
 
 // StartCommit is part of the queryservice.QueryService interface
-func (f *FakeQueryService) StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string) (err error) {
+func (f *FakeQueryService) StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string, includeGTIDExecuted bool) (string, error) {
 	if f.HasError {
-		return f.TabletError
+		return "", f.TabletError
 	}
 	if f.Panics {
 		panic(fmt.Errorf("test-triggered panic"))
@@ -292,7 +303,7 @@ func (f *FakeQueryService) StartCommit(ctx context.Context, target *querypb.Targ
 	if dtid != Dtid {
 		f.t.Errorf("StartCommit: invalid dtid: got %s expected %s", dtid, Dtid)
 	}
-	return nil
+	return gtidExecuted(includeGTIDExecuted), nil
 }
 
 // SetRollback is part of the queryservice.QueryService interface
@@ -850,6 +861,54 @@ func (f *FakeQueryService) UpdateStream(ctx context.Context, target *querypb.Tar
 	return nil
 }
 
+// StreamChangesPosition is a test change stream position.
+const StreamChangesPosition = "change stream position"
+
+// StreamChangesEvent1 is a test change stream event.
+var StreamChangesEvent1 = querypb.ChangeEvent{
+	Timestamp: 789654,
+	Position:  "change stream position 1",
+	Changes: []*querypb.RowChange{{
+		Type:  querypb.RowChange_INSERT,
+		Table: "table1",
+	}},
+}
+
+// StreamChangesEvent2 is a test change stream event.
+var StreamChangesEvent2 = querypb.ChangeEvent{
+	Timestamp: 789655,
+	Position:  "change stream position 2",
+	Changes: []*querypb.RowChange{{
+		Type: querypb.RowChange_DDL,
+		Sql:  []byte("alter table table1 add column c int"),
+	}},
+}
+
+// StreamChanges is part of the queryservice.QueryService interface
+func (f *FakeQueryService) StreamChanges(ctx context.Context, target *querypb.Target, position string, callback func(*querypb.ChangeEvent) error) error {
+	if f.Panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	if position != StreamChangesPosition {
+		f.t.Errorf("invalid StreamChanges.position: got %v expected %v", position, StreamChangesPosition)
+	}
+	f.checkTargetCallerID(ctx, "StreamChanges", target)
+	if err := callback(&StreamChangesEvent1); err != nil {
+		f.t.Errorf("callback1 failed: %v", err)
+	}
+	if f.HasError {
+		// wait until the client has the response, since all
+		// streaming implementation may not send previous
+		// messages if an error has been triggered.
+		<-f.ErrorWait
+		return f.TabletError
+	}
+	if err := callback(&StreamChangesEvent2); err != nil {
+		f.t.Errorf("callback2 failed: %v", err)
+	}
+	return nil
+}
+
 // CreateFakeServer returns the fake server for the tests
 func CreateFakeServer(t *testing.T) *FakeQueryService {
 	return &FakeQueryService{
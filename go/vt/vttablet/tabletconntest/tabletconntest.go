@@ -129,17 +129,25 @@ func testCommit(t *testing.T, conn queryservice.QueryService, f *FakeQueryServic
 	t.Log("testCommit")
 	ctx := context.Background()
 	ctx = callerid.NewContext(ctx, TestCallerID, TestVTGateCallerID)
-	err := conn.Commit(ctx, TestTarget, CommitTransactionID)
+	gtidExecuted, err := conn.Commit(ctx, TestTarget, CommitTransactionID, true)
 	if err != nil {
 		t.Fatalf("Commit failed: %v", err)
 	}
+	if gtidExecuted != GTIDExecuted {
+		t.Errorf("Commit returned position %v, expected %v", gtidExecuted, GTIDExecuted)
+	}
+	gtidExecuted, err = conn.Commit(ctx, TestTarget, CommitTransactionID, false)
+	if err != nil || gtidExecuted != "" {
+		t.Errorf("Commit without position returned %v, %v", gtidExecuted, err)
+	}
 }
 
 func testCommitError(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
 	t.Log("testCommitError")
 	f.HasError = true
 	testErrorHelper(t, f, "Commit", func(ctx context.Context) error {
-		return conn.Commit(ctx, TestTarget, CommitTransactionID)
+		_, err := conn.Commit(ctx, TestTarget, CommitTransactionID, false)
+		return err
 	})
 	f.HasError = false
 }
@@ -147,7 +155,8 @@ func testCommitError(t *testing.T, conn queryservice.QueryService, f *FakeQueryS
 func testCommitPanics(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
 	t.Log("testCommitPanics")
 	testPanicHelper(t, f, "Commit", func(ctx context.Context) error {
-		return conn.Commit(ctx, TestTarget, CommitTransactionID)
+		_, err := conn.Commit(ctx, TestTarget, CommitTransactionID, false)
+		return err
 	})
 }
 
@@ -207,17 +216,25 @@ func testCommitPrepared(t *testing.T, conn queryservice.QueryService, f *FakeQue
 	t.Log("testCommitPrepared")
 	ctx := context.Background()
 	ctx = callerid.NewContext(ctx, TestCallerID, TestVTGateCallerID)
-	err := conn.CommitPrepared(ctx, TestTarget, Dtid)
+	gtidExecuted, err := conn.CommitPrepared(ctx, TestTarget, Dtid, true)
 	if err != nil {
 		t.Fatalf("CommitPrepared failed: %v", err)
 	}
+	if gtidExecuted != GTIDExecuted {
+		t.Errorf("CommitPrepared returned position %v, expected %v", gtidExecuted, GTIDExecuted)
+	}
+	gtidExecuted, err = conn.CommitPrepared(ctx, TestTarget, Dtid, false)
+	if err != nil || gtidExecuted != "" {
+		t.Errorf("CommitPrepared without position returned %v, %v", gtidExecuted, err)
+	}
 }
 
 func testCommitPreparedError(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
 	t.Log("testCommitPreparedError")
 	f.HasError = true
 	testErrorHelper(t, f, "CommitPrepared", func(ctx context.Context) error {
-		return conn.CommitPrepared(ctx, TestTarget, Dtid)
+		_, err := conn.CommitPrepared(ctx, TestTarget, Dtid, false)
+		return err
 	})
 	f.HasError = false
 }
@@ -225,7 +242,8 @@ func testCommitPreparedError(t *testing.T, conn queryservice.QueryService, f *Fa
 func testCommitPreparedPanics(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
 	t.Log("testCommitPreparedPanics")
 	testPanicHelper(t, f, "CommitPrepared", func(ctx context.Context) error {
-		return conn.CommitPrepared(ctx, TestTarget, Dtid)
+		_, err := conn.CommitPrepared(ctx, TestTarget, Dtid, false)
+		return err
 	})
 }
 
@@ -285,17 +303,25 @@ func testStartCommit(t *testing.T, conn queryservice.QueryService, f *FakeQueryS
 	t.Log("testStartCommit")
 	ctx := context.Background()
 	ctx = callerid.NewContext(ctx, TestCallerID, TestVTGateCallerID)
-	err := conn.StartCommit(ctx, TestTarget, CommitTransactionID, Dtid)
+	gtidExecuted, err := conn.StartCommit(ctx, TestTarget, CommitTransactionID, Dtid, true)
 	if err != nil {
 		t.Fatalf("StartCommit failed: %v", err)
 	}
+	if gtidExecuted != GTIDExecuted {
+		t.Errorf("StartCommit returned position %v, expected %v", gtidExecuted, GTIDExecuted)
+	}
+	gtidExecuted, err = conn.StartCommit(ctx, TestTarget, CommitTransactionID, Dtid, false)
+	if err != nil || gtidExecuted != "" {
+		t.Errorf("StartCommit without position returned %v, %v", gtidExecuted, err)
+	}
 }
 
 func testStartCommitError(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
 	t.Log("testStartCommitError")
 	f.HasError = true
 	testErrorHelper(t, f, "StartCommit", func(ctx context.Context) error {
-		return conn.StartCommit(ctx, TestTarget, CommitTransactionID, Dtid)
+		_, err := conn.StartCommit(ctx, TestTarget, CommitTransactionID, Dtid, false)
+		return err
 	})
 	f.HasError = false
 }
@@ -303,7 +329,8 @@ func testStartCommitError(t *testing.T, conn queryservice.QueryService, f *FakeQ
 func testStartCommitPanics(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
 	t.Log("testStartCommitPanics")
 	testPanicHelper(t, f, "StartCommit", func(ctx context.Context) error {
-		return conn.StartCommit(ctx, TestTarget, CommitTransactionID, Dtid)
+		_, err := conn.StartCommit(ctx, TestTarget, CommitTransactionID, Dtid, false)
+		return err
 	})
 }
 
@@ -878,6 +905,66 @@ func testUpdateStreamError(t *testing.T, conn queryservice.QueryService, f *Fake
 	f.HasError = false
 }
 
+func testStreamChanges(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
+	t.Log("testStreamChanges")
+	ctx := context.Background()
+	ctx = callerid.NewContext(ctx, TestCallerID, TestVTGateCallerID)
+	i := 0
+	err := conn.StreamChanges(ctx, TestTarget, StreamChangesPosition, func(event *querypb.ChangeEvent) error {
+		switch i {
+		case 0:
+			if !proto.Equal(event, &StreamChangesEvent1) {
+				t.Errorf("Unexpected result1 from StreamChanges: got %v wanted %v", event, StreamChangesEvent1)
+			}
+		case 1:
+			if !proto.Equal(event, &StreamChangesEvent2) {
+				t.Errorf("Unexpected result2 from StreamChanges: got %v wanted %v", event, StreamChangesEvent2)
+			}
+		default:
+			t.Fatal("callback should not be called any more")
+		}
+		i++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamChanges failed: %v", err)
+	}
+}
+
+func testStreamChangesError(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
+	t.Log("testStreamChangesError")
+	f.HasError = true
+	testErrorHelper(t, f, "StreamChanges", func(ctx context.Context) error {
+		f.ErrorWait = make(chan struct{})
+		ctx = callerid.NewContext(ctx, TestCallerID, TestVTGateCallerID)
+		return conn.StreamChanges(ctx, TestTarget, StreamChangesPosition, func(event *querypb.ChangeEvent) error {
+			// For some errors, the call can be retried.
+			select {
+			case <-f.ErrorWait:
+				return nil
+			default:
+			}
+			if !proto.Equal(event, &StreamChangesEvent1) {
+				t.Errorf("Unexpected result1 from StreamChanges: got %v wanted %v", event, StreamChangesEvent1)
+			}
+			// signal to the server that the first result has been received
+			close(f.ErrorWait)
+			return nil
+		})
+	})
+	f.HasError = false
+}
+
+func testStreamChangesPanics(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
+	t.Log("testStreamChangesPanics")
+	testPanicHelper(t, f, "StreamChanges", func(ctx context.Context) error {
+		ctx = callerid.NewContext(ctx, TestCallerID, TestVTGateCallerID)
+		return conn.StreamChanges(ctx, TestTarget, StreamChangesPosition, func(event *querypb.ChangeEvent) error {
+			return nil
+		})
+	})
+}
+
 func testUpdateStreamPanics(t *testing.T, conn queryservice.QueryService, f *FakeQueryService) {
 	t.Log("testUpdateStreamPanics")
 	// early panic is before sending the Fields, that is returned
@@ -945,6 +1032,7 @@ func TestSuite(t *testing.T, protocol string, tablet *topodatapb.Tablet, fake *F
 		testMessageAck,
 		testSplitQuery,
 		testUpdateStream,
+		testStreamChanges,
 
 		// error test cases
 		testBeginError,
@@ -969,6 +1057,7 @@ func TestSuite(t *testing.T, protocol string, tablet *topodatapb.Tablet, fake *F
 		testMessageAckError,
 		testSplitQueryError,
 		testUpdateStreamError,
+		testStreamChangesError,
 
 		// panic test cases
 		testBeginPanics,
@@ -991,6 +1080,7 @@ func TestSuite(t *testing.T, protocol string, tablet *topodatapb.Tablet, fake *F
 		testMessageAckPanics,
 		testSplitQueryPanics,
 		testUpdateStreamPanics,
+		testStreamChangesPanics,
 	}
 
 	if !fake.TestingGateway {
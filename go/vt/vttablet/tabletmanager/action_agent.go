@@ -316,6 +316,9 @@ func NewActionAgent(
 		go agent.orc.DiscoverLoop(agent)
 	}
 
+	// Start periodic binlog archiving, if configured.
+	agent.initBinlogArchiver()
+
 	return agent, nil
 }
 
@@ -0,0 +1,86 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+// This file handles the periodic archiving of closed binlog files to
+// the BackupStorage, as incremental backups. It is only enabled if
+// binlog_archive_interval is set, and only the master of the shard
+// archives its binlogs. The archived binlogs are used to restore to a
+// point in time, see restore_to_timestamp and restore_to_pos.
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/timer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+var (
+	binlogArchiveInterval = flag.Duration("binlog_archive_interval", 0, "if set, the master uploads its closed binlog files to the backup storage at this interval, for point-in-time recovery")
+	binlogArchiveTimeout  = flag.Duration("binlog_archive_timeout", time.Hour, "timeout for one upload of closed binlog files to the backup storage")
+)
+
+// initBinlogArchiver starts the periodic binlog archiving, if enabled.
+func (agent *ActionAgent) initBinlogArchiver() {
+	if *binlogArchiveInterval == 0 {
+		return
+	}
+
+	log.Infof("Starting periodic binlog archiving every %v", *binlogArchiveInterval)
+	t := timer.NewTimer(*binlogArchiveInterval)
+	servenv.OnTermSync(func() {
+		log.Info("Stopping periodic binlog archiving")
+		t.Stop()
+	})
+	t.Start(func() {
+		agent.archiveBinlogs()
+	})
+}
+
+// archiveBinlogs uploads the binlog files closed since the last backup
+// or archive, if the tablet is the master.
+func (agent *ActionAgent) archiveBinlogs() {
+	tablet := agent.Tablet()
+	if tablet.Type != topodatapb.TabletType_MASTER {
+		// The replicas have the same transactions in their binlogs,
+		// archiving them too would only add copies. After a reparent,
+		// the new master goes on from the last archived position.
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(agent.batchCtx, *binlogArchiveTimeout)
+	defer cancel()
+	dir := fmt.Sprintf("%v/%v", tablet.Keyspace, tablet.Shard)
+	name := fmt.Sprintf("%v.%v", time.Now().UTC().Format(mysqlctl.BackupNameTimeFormat), topoproto.TabletAliasString(tablet.Alias))
+	switch err := mysqlctl.ArchiveBinlogs(ctx, agent.MysqlDaemon, logutil.NewConsoleLogger(), dir, name, agent.hookExtraEnv()); err {
+	case nil, mysqlctl.ErrNoBinlogsToArchive:
+	case mysqlctl.ErrNoBackup:
+		log.Infof("Not archiving binlogs: no backup in %v yet", dir)
+	default:
+		log.Warningf("Binlog archiving failed: %v", err)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"vitess.io/vitess/go/flagutil"
 	"vitess.io/vitess/go/netutil"
 	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 
@@ -149,6 +150,19 @@ func (agent *ActionAgent) InitTablet(port, gRPCPort int32) error {
 		log.Infof("Using detected machine hostname: %v To change this, fix your machine network configuration or override it with -tablet_hostname.", hostname)
 	}
 
+	// A delayed replica is tagged, so it is not used to serve
+	// queries.
+	tags := make(map[string]string)
+	for k, v := range initTags {
+		tags[k] = v
+	}
+	if *mysqlctl.MasterDelay > 0 {
+		if tabletType == topodatapb.TabletType_MASTER {
+			return fmt.Errorf("a master tablet cannot be started with -master_delay")
+		}
+		tags[topoproto.DelayedReplicaTag] = mysqlctl.MasterDelay.String()
+	}
+
 	// create and populate tablet record
 	tablet := &topodatapb.Tablet{
 		Alias:          agent.TabletAlias,
@@ -159,7 +173,7 @@ func (agent *ActionAgent) InitTablet(port, gRPCPort int32) error {
 		KeyRange:       keyRange,
 		Type:           tabletType,
 		DbNameOverride: *initDbNameOverride,
-		Tags:           initTags,
+		Tags:           tags,
 	}
 	if port != 0 {
 		tablet.PortMap["vt"] = port
@@ -95,8 +95,16 @@ func (r *replicationReporter) Report(isSlaveType, shouldQueryServiceBeRunning bo
 		return elapsed + r.lastKnownValue, nil
 	}
 
-	// we got a real value, save it.
+	// we got a real value, save it. The lag of a delayed replica
+	// includes its delay, which is not reported: it is only behind
+	// if it is later than that.
 	r.lastKnownValue = time.Duration(status.SecondsBehindMaster) * time.Second
+	if *mysqlctl.MasterDelay > 0 {
+		r.lastKnownValue -= *mysqlctl.MasterDelay
+		if r.lastKnownValue < 0 {
+			r.lastKnownValue = 0
+		}
+	}
 	r.lastKnownTime = r.now()
 	return r.lastKnownValue, nil
 }
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"vitess.io/vitess/go/vt/health"
+	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/fakemysqldaemon"
 )
 
@@ -41,6 +42,34 @@ func TestBasicMySQLReplicationLag(t *testing.T) {
 	}
 }
 
+func TestDelayedReplicaReplicationLag(t *testing.T) {
+	*mysqlctl.MasterDelay = time.Hour
+	defer func() { *mysqlctl.MasterDelay = 0 }()
+
+	mysqld := fakemysqldaemon.NewFakeMysqlDaemon(nil)
+	mysqld.Replicating = true
+	slaveStopped := true
+	rep := &replicationReporter{
+		agent: &ActionAgent{MysqlDaemon: mysqld, _slaveStopped: &slaveStopped},
+		now:   time.Now,
+	}
+
+	// Only the lag beyond the delay is reported.
+	for _, tcase := range []struct {
+		secondsBehindMaster uint
+		want                time.Duration
+	}{
+		{3610, 10 * time.Second},
+		{3500, 0},
+	} {
+		mysqld.SecondsBehindMaster = tcase.secondsBehindMaster
+		dur, err := rep.Report(true, true)
+		if err != nil || dur != tcase.want {
+			t.Errorf("Report with %vs behind master: %v %v, want %v", tcase.secondsBehindMaster, dur, err, tcase.want)
+		}
+	}
+}
+
 func TestNoKnownMySQLReplicationLag(t *testing.T) {
 	mysqld := fakemysqldaemon.NewFakeMysqlDaemon(nil)
 	mysqld.Replicating = false
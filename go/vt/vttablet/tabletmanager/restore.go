@@ -19,6 +19,7 @@ package tabletmanager
 import (
 	"flag"
 	"fmt"
+	"time"
 
 	"golang.org/x/net/context"
 	"vitess.io/vitess/go/vt/log"
@@ -37,8 +38,30 @@ import (
 var (
 	restoreFromBackup  = flag.Bool("restore_from_backup", false, "(init restore parameter) will check BackupStorage for a recent backup at startup and start there")
 	restoreConcurrency = flag.Int("restore_concurrency", 4, "(init restore parameter) how many concurrent files to restore at once")
+	restoreToTimestamp = flag.String("restore_to_timestamp", "", "(restore parameter) if set, restore to this point in time (UTC, in the 2006-01-02.150405 format of backup names) by replaying archived binlogs on top of the last full backup taken before it. The tablet is then DRAINED and does not replicate.")
+	restoreToPos       = flag.String("restore_to_pos", "", "(restore parameter) if set, restore to this replication position (e.g. MySQL56/<gtid set>) by replaying archived binlogs on top of the last full backup taken before it. The tablet is then DRAINED and does not replicate.")
 )
 
+// restoreTarget parses the point-in-time restore flags.
+func restoreTarget() (time.Time, mysql.Position, error) {
+	var restoreTime time.Time
+	var restorePos mysql.Position
+	var err error
+	if *restoreToTimestamp != "" {
+		restoreTime, err = time.Parse(mysqlctl.BackupNameTimeFormat, *restoreToTimestamp)
+		if err != nil {
+			return restoreTime, restorePos, fmt.Errorf("invalid -restore_to_timestamp: %v", err)
+		}
+	}
+	if *restoreToPos != "" {
+		restorePos, err = mysql.DecodePosition(*restoreToPos)
+		if err != nil {
+			return restoreTime, restorePos, fmt.Errorf("invalid -restore_to_pos: %v", err)
+		}
+	}
+	return restoreTime, restorePos, nil
+}
+
 // RestoreData is the main entry point for backup restore.
 // It will either work, fail gracefully, or return
 // an error in case of a non-recoverable error.
@@ -52,6 +75,12 @@ func (agent *ActionAgent) RestoreData(ctx context.Context, logger logutil.Logger
 }
 
 func (agent *ActionAgent) restoreDataLocked(ctx context.Context, logger logutil.Logger, deleteBeforeRestore bool) error {
+	restoreTime, restorePos, err := restoreTarget()
+	if err != nil {
+		return err
+	}
+	pointInTime := !restoreTime.IsZero() || !restorePos.IsZero()
+
 	// change type to RESTORE (using UpdateTabletFields so it's
 	// always authorized)
 	var originalType topodatapb.TabletType
@@ -75,12 +104,21 @@ func (agent *ActionAgent) restoreDataLocked(ctx context.Context, logger logutil.
 	localMetadata := agent.getLocalMetadataValues(originalType)
 	tablet := agent.Tablet()
 	dir := fmt.Sprintf("%v/%v", tablet.Keyspace, tablet.Shard)
-	pos, err := mysqlctl.Restore(ctx, agent.MysqlDaemon, dir, *restoreConcurrency, agent.hookExtraEnv(), localMetadata, logger, deleteBeforeRestore, topoproto.TabletDbName(tablet))
+	pos, err := mysqlctl.Restore(ctx, agent.MysqlDaemon, dir, *restoreConcurrency, agent.hookExtraEnv(), localMetadata, logger, deleteBeforeRestore, topoproto.TabletDbName(tablet), restoreTime, restorePos)
 	switch err {
 	case nil:
 		// Starting from here we won't be able to recover if we get stopped by a cancelled
 		// context. Thus we use the background context to get through to the finish.
 
+		if pointInTime {
+			// The data is in the past on purpose: don't serve it
+			// in the shard, and don't let replication catch up.
+			logger.Infof("Restored to %v, not starting replication", pos)
+			agent.setSlaveStopped(true)
+			originalType = topodatapb.TabletType_DRAINED
+			break
+		}
+
 		// Reconnect to master.
 		if err := agent.startReplication(context.Background(), pos, originalType); err != nil {
 			return err
@@ -104,7 +142,7 @@ func (agent *ActionAgent) restoreDataLocked(ctx context.Context, logger logutil.
 
 	// If we had type BACKUP or RESTORE it's better to set our type to the init_tablet_type to make result of the restore
 	// similar to completely clean start from scratch.
-	if (originalType == topodatapb.TabletType_BACKUP || originalType == topodatapb.TabletType_RESTORE) && *initTabletType != "" {
+	if (originalType == topodatapb.TabletType_BACKUP || originalType == topodatapb.TabletType_RESTORE) && *initTabletType != "" && !pointInTime {
 		initType, err := topoproto.ParseTabletType(*initTabletType)
 		if err == nil {
 			originalType = initType
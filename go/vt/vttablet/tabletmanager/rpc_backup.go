@@ -36,7 +36,6 @@ func (agent *ActionAgent) Backup(ctx context.Context, concurrency int, logger lo
 	}
 	defer agent.unlock()
 
-	// update our type to BACKUP
 	tablet, err := agent.TopoServer.GetTablet(ctx, agent.TabletAlias)
 	if err != nil {
 		return err
@@ -44,6 +43,22 @@ func (agent *ActionAgent) Backup(ctx context.Context, concurrency int, logger lo
 	if tablet.Type == topodatapb.TabletType_MASTER {
 		return fmt.Errorf("type MASTER cannot take backup, if you really need to do this, restart vttablet in replica mode")
 	}
+	engine, err := mysqlctl.GetBackupEngine()
+	if err != nil {
+		return err
+	}
+
+	// create the loggers: tee to console and source
+	l := logutil.NewTeeLogger(logutil.NewConsoleLogger(), logger)
+
+	// hot backups can be taken while serving
+	dir := fmt.Sprintf("%v/%v", tablet.Keyspace, tablet.Shard)
+	name := fmt.Sprintf("%v.%v", time.Now().UTC().Format(mysqlctl.BackupNameTimeFormat), topoproto.TabletAliasString(tablet.Alias))
+	if !engine.ShouldDrainForBackup() {
+		return mysqlctl.Backup(ctx, agent.MysqlDaemon, l, dir, name, concurrency, agent.hookExtraEnv())
+	}
+
+	// update our type to BACKUP
 	originalType := tablet.Type
 	if _, err := topotools.ChangeType(ctx, agent.TopoServer, tablet.Alias, topodatapb.TabletType_BACKUP); err != nil {
 		return err
@@ -54,12 +69,7 @@ func (agent *ActionAgent) Backup(ctx context.Context, concurrency int, logger lo
 		return err
 	}
 
-	// create the loggers: tee to console and source
-	l := logutil.NewTeeLogger(logutil.NewConsoleLogger(), logger)
-
 	// now we can run the backup
-	dir := fmt.Sprintf("%v/%v", tablet.Keyspace, tablet.Shard)
-	name := fmt.Sprintf("%v.%v", time.Now().UTC().Format("2006-01-02.150405"), topoproto.TabletAliasString(tablet.Alias))
 	returnErr := mysqlctl.Backup(ctx, agent.MysqlDaemon, l, dir, name, concurrency, agent.hookExtraEnv())
 
 	// change our type back to the original value
@@ -17,6 +17,7 @@ limitations under the License.
 package tabletmanager
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"time"
@@ -36,6 +37,10 @@ import (
 
 var (
 	enableSemiSync = flag.Bool("enable_semi_sync", false, "Enable semi-sync when configuring replication, on master and replica tablets only (rdonly tablets will not ack).")
+
+	// errDelayedReplica is returned when a delayed replica is asked
+	// to become the master.
+	errDelayedReplica = errors.New("a delayed replica (-master_delay) cannot become the master")
 )
 
 // SlaveStatus returns the replication status
@@ -161,6 +166,10 @@ func (agent *ActionAgent) ResetReplication(ctx context.Context) error {
 
 // InitMaster enables writes and returns the replication position.
 func (agent *ActionAgent) InitMaster(ctx context.Context) (string, error) {
+	if *mysqlctl.MasterDelay > 0 {
+		return "", errDelayedReplica
+	}
+
 	if err := agent.lock(ctx); err != nil {
 		return "", err
 	}
@@ -334,6 +343,10 @@ func (agent *ActionAgent) DemoteMaster(ctx context.Context) (string, error) {
 // replication up to the provided point, and then makes the slave the
 // shard master.
 func (agent *ActionAgent) PromoteSlaveWhenCaughtUp(ctx context.Context, position string) (string, error) {
+	if *mysqlctl.MasterDelay > 0 {
+		return "", errDelayedReplica
+	}
+
 	if err := agent.lock(ctx); err != nil {
 		return "", err
 	}
@@ -544,6 +557,10 @@ func (agent *ActionAgent) StopReplicationAndGetStatus(ctx context.Context) (*rep
 
 // PromoteSlave makes the current tablet the master
 func (agent *ActionAgent) PromoteSlave(ctx context.Context) (string, error) {
+	if *mysqlctl.MasterDelay > 0 {
+		return "", errDelayedReplica
+	}
+
 	if err := agent.lock(ctx); err != nil {
 		return "", err
 	}
@@ -30,6 +30,7 @@ import (
 	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/dbconnpool"
 	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlannotation"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -95,6 +96,8 @@ func (dbc *DBConn) Exec(ctx context.Context, query string, maxrows int, wantfiel
 	span := trace.NewSpanFromContext(ctx)
 	span.StartClient("DBConn.Exec")
 	defer span.Finish()
+	ctx = trace.NewContext(ctx, span)
+	query = withTraceComment(ctx, query)
 
 	for attempt := 1; attempt <= 2; attempt++ {
 		r, err := dbc.execOnce(ctx, query, maxrows, wantfields)
@@ -162,6 +165,8 @@ func (dbc *DBConn) Stream(ctx context.Context, query string, callback func(*sqlt
 	span := trace.NewSpanFromContext(ctx)
 	span.StartClient("DBConn.Stream")
 	defer span.Finish()
+	ctx = trace.NewContext(ctx, span)
+	query = withTraceComment(ctx, query)
 
 	resultSent := false
 	for attempt := 1; attempt <= 2; attempt++ {
@@ -221,6 +226,18 @@ func (dbc *DBConn) streamOnce(ctx context.Context, query string, callback func(*
 	return dbc.conn.ExecuteStreamFetch(query, callback, streamBufferSize)
 }
 
+// withTraceComment prefixes query with the span context of ctx, if its
+// trace is recorded, so the statements seen by MySQL can be matched
+// with their trace. The comment goes first so a trailing line comment in query
+// cannot swallow it.
+func withTraceComment(ctx context.Context, query string) string {
+	comment := sqlannotation.SpanContextComment(ctx)
+	if comment == "" {
+		return query
+	}
+	return comment + " " + query
+}
+
 var (
 	getModeSQL    = "select @@global.sql_mode"
 	getAutocommit = "select @@autocommit"
@@ -285,6 +302,19 @@ func (dbc *DBConn) VerifyMode(strictTransTables bool) (BinlogFormat, error) {
 	return 0, fmt.Errorf("unexpected binlog format for %s: %s", showBinlog, qr.Rows[0][1].ToString())
 }
 
+// MasterPosition returns the current replication position of MySQL,
+// read the way its flavor requires.
+func (dbc *DBConn) MasterPosition() (mysql.Position, error) {
+	return dbc.conn.MasterPosition()
+}
+
+// WaitUntilPositionCommand returns the query that waits until MySQL
+// has replicated up to a position, for its flavor. The timeout is the
+// deadline of the context, if any.
+func (dbc *DBConn) WaitUntilPositionCommand(ctx context.Context, pos mysql.Position) (string, error) {
+	return dbc.conn.WaitUntilPositionCommand(ctx, pos)
+}
+
 // Close closes the DBConn.
 func (dbc *DBConn) Close() {
 	dbc.conn.Close()
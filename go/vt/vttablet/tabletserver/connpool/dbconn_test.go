@@ -29,6 +29,7 @@ import (
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/mysql/fakesqldb"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/trace"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
@@ -322,3 +323,42 @@ func TestDBConnStream(t *testing.T) {
 		t.Errorf("Error: '%v', must contain '%s'", err, want)
 	}
 }
+
+// spanRecorder is a trace.Exporter keeping the spans in memory.
+type spanRecorder struct {
+	spans []*trace.SpanData
+}
+
+func (sr *spanRecorder) ExportSpan(data *trace.SpanData) { sr.spans = append(sr.spans, data) }
+func (sr *spanRecorder) Close() error                    { return nil }
+
+func TestDBConnExecTrace(t *testing.T) {
+	recorder := &spanRecorder{}
+	trace.RegisterSpanFactory(trace.NewTracer("vttablet", recorder, 1))
+	defer trace.RegisterSpanFactory(nil)
+
+	db := fakesqldb.New(t)
+	defer db.Close()
+	// The span context is passed to MySQL in a comment.
+	db.AddQueryPattern(`/\*VT_SPAN_CONTEXT=1:[0-9a-f]+:2:1\*/ select \* from test_table limit 1000`, &sqltypes.Result{})
+	connPool := newPool()
+	connPool.Open(db.ConnParams(), db.ConnParams(), db.ConnParams())
+	defer connPool.Close()
+	dbConn, err := NewDBConn(connPool, db.ConnParams())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbConn.Close()
+
+	ctx := trace.WithSpanContext(context.Background(), "1:2:0:1")
+	if _, err := dbConn.Exec(ctx, "select * from test_table limit 1000", 1, false); err != nil {
+		t.Fatalf("should not get an error, err: %v", err)
+	}
+	if len(recorder.spans) != 1 {
+		t.Fatalf("got spans %+v, want one", recorder.spans)
+	}
+	span := recorder.spans[0]
+	if span.Label != "DBConn.Exec" || span.Kind != trace.KindClient || span.TraceID != 1 || span.ParentID != 2 {
+		t.Errorf("got span %+v, want a DBConn.Exec client span continuing the trace", span)
+	}
+}
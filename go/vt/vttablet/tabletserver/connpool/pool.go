@@ -25,6 +25,7 @@ import (
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/pools"
 	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/dbconnpool"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -56,6 +57,7 @@ type MySQLChecker interface {
 // Other than the connection type, ConnPool maintains an additional
 // pool of dba connections that are used to kill connections.
 type Pool struct {
+	name           string
 	mu             sync.Mutex
 	connections    *pools.ResourcePool
 	capacity       int
@@ -73,6 +75,7 @@ func New(
 	idleTimeout time.Duration,
 	checker MySQLChecker) *Pool {
 	cp := &Pool{
+		name:        name,
 		capacity:    capacity,
 		idleTimeout: idleTimeout,
 		dbaPool:     dbconnpool.NewConnectionPool("", 1, idleTimeout),
@@ -134,6 +137,11 @@ func (cp *Pool) Close() {
 // Get returns a connection.
 // You must call Recycle on DBConn once done.
 func (cp *Pool) Get(ctx context.Context) (*DBConn, error) {
+	span := trace.NewSpanFromContext(ctx)
+	span.StartLocal("Pool.Get")
+	span.Annotate("pool", cp.name)
+	defer span.Finish()
+
 	if cp.isCallerIDAppDebug(ctx) {
 		return NewDBConnNoPool(cp.appDebugParams, cp.dbaPool)
 	}
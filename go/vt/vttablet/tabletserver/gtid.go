@@ -0,0 +1,140 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/dbconnpool"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// gtidExecuted returns the replication position of MySQL, encoded with
+// its flavor: gtid_executed for MySQL, gtid_binlog_pos for MariaDB.
+func (tsv *TabletServer) gtidExecuted(ctx context.Context) (string, error) {
+	conn, err := tsv.qe.getQueryConn(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Recycle()
+
+	pos, err := conn.MasterPosition()
+	if err != nil {
+		return "", err
+	}
+	return mysql.EncodePosition(pos), nil
+}
+
+// gtidExecutedAfterCommit returns the replication position of MySQL
+// after a commit, if the caller asked for it. The transaction is
+// committed already, so a failure only means the caller won't know
+// its position.
+func (tsv *TabletServer) gtidExecutedAfterCommit(ctx context.Context, includeGTIDExecuted bool) string {
+	if !includeGTIDExecuted {
+		return ""
+	}
+	gtidExecuted, err := tsv.gtidExecuted(ctx)
+	if err != nil {
+		log.Warningf("Cannot get the replication position after commit: %v", err)
+		tabletenv.InternalErrors.Add("GTIDExecuted", 1)
+		return ""
+	}
+	return gtidExecuted
+}
+
+// waitForGTIDSet waits until MySQL has replicated up to the position
+// of the options, if there is one, with the wait function of its
+// flavor: WAIT_UNTIL_SQL_THREAD_AFTER_GTIDS for MySQL, MASTER_GTID_WAIT
+// for MariaDB. It returns a DEADLINE_EXCEEDED error if MySQL didn't
+// catch up within the timeout of the options.
+// The wait runs on its own connection, so that reads waiting for a
+// lagging replica don't exhaust the query pool.
+func (tsv *TabletServer) waitForGTIDSet(ctx context.Context, options *querypb.ExecuteOptions) error {
+	gtidSet := options.GetWaitForGtidSet()
+	if gtidSet == "" {
+		return nil
+	}
+	defer tabletenv.QueryStats.Record("WAIT_FOR_GTID_SET", time.Now())
+
+	pos, err := mysql.DecodePosition(gtidSet)
+	if err != nil {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid position to wait for: %v", err)
+	}
+	waitCtx := ctx
+	if timeoutMs := options.GetWaitForGtidSetTimeoutMs(); timeoutMs > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, time.Duration(timeoutMs)*time.Millisecond)
+		defer cancel()
+	}
+
+	conn, err := dbconnpool.NewDBConnection(&tsv.dbconfigs.App, tabletenv.MySQLStats)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	sql, err := conn.WaitUntilPositionCommand(waitCtx, pos)
+	if err != nil {
+		// The timeout expired already.
+		return vterrors.Errorf(vtrpcpb.Code_DEADLINE_EXCEEDED, "timed out waiting for position %v", gtidSet)
+	}
+	qr, err := conn.ExecuteFetch(sql, 1, false)
+	if err != nil {
+		return err
+	}
+	if len(qr.Rows) != 1 || len(qr.Rows[0]) != 1 || qr.Rows[0][0].IsNull() {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot wait for position %v: GTIDs are not enabled, or replication is not running", gtidSet)
+	}
+	if qr.Rows[0][0].ToString() == "-1" {
+		return vterrors.Errorf(vtrpcpb.Code_DEADLINE_EXCEEDED, "timed out waiting for position %v", gtidSet)
+	}
+	return nil
+}
+
+// waitForGTIDSetRequest waits for the position of the options, like
+// waitForGTIDSet, before a request that takes a connection for longer
+// than a single query: a batch, or a new transaction. It returns the
+// options without the position, for the queries of the request.
+func (tsv *TabletServer) waitForGTIDSetRequest(ctx context.Context, target *querypb.Target, options *querypb.ExecuteOptions) (*querypb.ExecuteOptions, error) {
+	if options.GetWaitForGtidSet() == "" {
+		return options, nil
+	}
+	err := tsv.execRequest(
+		ctx, tsv.QueryTimeout.Get(),
+		"WaitForGTIDSet", "wait_for_gtid_set", nil,
+		target, options, false /* isBegin */, false, /* allowOnShutdown */
+		func(ctx context.Context, logStats *tabletenv.LogStats) error {
+			return tsv.waitForGTIDSet(ctx, options)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	options = proto.Clone(options).(*querypb.ExecuteOptions)
+	options.WaitForGtidSet = ""
+	options.WaitForGtidSetTimeoutMs = 0
+	return options, nil
+}
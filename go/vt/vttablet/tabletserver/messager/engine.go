@@ -43,6 +43,8 @@ type TabletService interface {
 	CheckMySQL()
 	PostponeMessages(ctx context.Context, target *querypb.Target, name string, ids []string) (count int64, err error)
 	PurgeMessages(ctx context.Context, target *querypb.Target, name string, timeCutoff int64) (count int64, err error)
+	FailMessages(ctx context.Context, target *querypb.Target, name string, ids []string) (count int64, err error)
+	RequeueMessages(ctx context.Context, target *querypb.Target, name string) (count int64, err error)
 }
 
 // Engine is the engine for handling messages.
@@ -248,6 +250,51 @@ func (me *Engine) GeneratePurgeQuery(name string, timeCutoff int64) (string, map
 	return query, bv, nil
 }
 
+// GenerateFailQueries returns the queries and bind vars for failing messages.
+func (me *Engine) GenerateFailQueries(name string, ids []string) ([]string, map[string]*querypb.BindVariable, error) {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	mm := me.managers[name]
+	if mm == nil {
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "message table %s not found in schema", name)
+	}
+	queries, bv := mm.GenerateFailQueries(ids)
+	if len(queries) == 0 {
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "message table %s has no max epoch", name)
+	}
+	return queries, bv, nil
+}
+
+// GenerateReadDeadLettersQuery returns the query for reading the dead letters to requeue.
+func (me *Engine) GenerateReadDeadLettersQuery(name string) (string, error) {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	mm := me.managers[name]
+	if mm == nil {
+		return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "message table %s not found in schema", name)
+	}
+	query := mm.GenerateReadDeadLettersQuery()
+	if query == "" {
+		return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "message table %s has no dead letter table", name)
+	}
+	return query, nil
+}
+
+// GenerateRequeueQueries returns the queries and bind vars for requeuing dead letters.
+func (me *Engine) GenerateRequeueQueries(name string, rows [][]sqltypes.Value) ([]string, map[string]*querypb.BindVariable, error) {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	mm := me.managers[name]
+	if mm == nil {
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "message table %s not found in schema", name)
+	}
+	if mm.deleteDeadLettersQuery == nil {
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "message table %s has no dead letter table", name)
+	}
+	queries, bv := mm.GenerateRequeueQueries(rows)
+	return queries, bv, nil
+}
+
 func (me *Engine) schemaChanged(tables map[string]*schema.Table, created, altered, dropped []string) {
 	me.mu.Lock()
 	defer me.mu.Unlock()
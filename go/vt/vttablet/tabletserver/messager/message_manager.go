@@ -162,6 +162,14 @@ type receiverWithStatus struct {
 // The Purge thread
 // This thread is mostly independent. It wakes up periodically
 // to delete old rows that were successfully acked.
+//
+// Failed messages
+// If a max epoch is set, the poller does not resend messages that
+// were already sent that many times. They are moved to the dead
+// letter table if there is one, or else they stay in the message
+// table with a NULL time_next. Dead letters are requeued by setting
+// their time_next: the purge thread moves them back to the message
+// table, where they are sent again.
 type messageManager struct {
 	DBLock sync.Mutex
 	tsv    TabletService
@@ -171,6 +179,8 @@ type messageManager struct {
 	ackWaitTime  time.Duration
 	purgeAfter   time.Duration
 	batchSize    int
+	maxEpoch     int64
+	deadLetter   sqlparser.TableIdent
 	pollerTicks  *timer.Timer
 	purgeTicks   *timer.Timer
 	conns        *connpool.Pool
@@ -198,6 +208,18 @@ type messageManager struct {
 	ackQuery          *sqlparser.ParsedQuery
 	postponeQuery     *sqlparser.ParsedQuery
 	purgeQuery        *sqlparser.ParsedQuery
+
+	// The queries for failed messages. Only the ones
+	// relevant to the configuration are set.
+	failQuery              *sqlparser.ParsedQuery
+	deadLetterQuery        *sqlparser.ParsedQuery
+	deleteFailedQuery      *sqlparser.ParsedQuery
+	readDeadLettersQuery   *sqlparser.ParsedQuery
+	deleteDeadLettersQuery *sqlparser.ParsedQuery
+	// columnList is the list of columns returned to the
+	// subscribers, which are also the ones a requeued
+	// message is inserted with.
+	columnList string
 }
 
 // newMessageManager creates a new message manager.
@@ -213,6 +235,8 @@ func newMessageManager(tsv TabletService, table *schema.Table, conns *connpool.P
 		ackWaitTime:  table.MessageInfo.AckWaitDuration,
 		purgeAfter:   table.MessageInfo.PurgeAfterDuration,
 		batchSize:    table.MessageInfo.BatchSize,
+		maxEpoch:     int64(table.MessageInfo.MaxEpoch),
+		deadLetter:   table.MessageInfo.DeadLetterTable,
 		cache:        newCache(table.MessageInfo.CacheSize),
 		pollerTicks:  timer.NewTimer(table.MessageInfo.PollInterval),
 		purgeTicks:   timer.NewTimer(table.MessageInfo.PollInterval),
@@ -222,6 +246,7 @@ func newMessageManager(tsv TabletService, table *schema.Table, conns *connpool.P
 	mm.cond.L = &mm.mu
 
 	columnList := buildSelectColumnList(table)
+	mm.columnList = columnList
 	mm.readByTimeNext = sqlparser.BuildParsedQuery(
 		"select time_next, epoch, time_created, %s from %v where time_next < %a order by time_next desc limit %a",
 		columnList, mm.name, ":time_next", ":max")
@@ -237,6 +262,27 @@ func newMessageManager(tsv TabletService, table *schema.Table, conns *connpool.P
 	mm.purgeQuery = sqlparser.BuildParsedQuery(
 		"delete from %v where time_scheduled < %a and time_acked is not null limit 500",
 		mm.name, ":time_scheduled")
+	switch {
+	case mm.maxEpoch == 0:
+	case mm.deadLetter.IsEmpty():
+		mm.failQuery = sqlparser.BuildParsedQuery(
+			"update %v set time_next = null where id in %a and time_acked is null and epoch >= %a",
+			mm.name, "::ids", ":max_epoch")
+	default:
+		all, deadLetters := buildDeadLetterColumnLists(table)
+		mm.deadLetterQuery = sqlparser.BuildParsedQuery(
+			"insert into %v(%s) select %s from %v where id in %a and time_acked is null and epoch >= %a for update",
+			mm.deadLetter, all, deadLetters, mm.name, "::ids", ":max_epoch")
+		mm.deleteFailedQuery = sqlparser.BuildParsedQuery(
+			"delete from %v where id in %a and time_acked is null and epoch >= %a",
+			mm.name, "::ids", ":max_epoch")
+		mm.readDeadLettersQuery = sqlparser.BuildParsedQuery(
+			"select %s from %v where time_next is not null limit 500 for update",
+			columnList, mm.deadLetter)
+		mm.deleteDeadLettersQuery = sqlparser.BuildParsedQuery(
+			"delete from %v where id in %a",
+			mm.deadLetter, "::ids")
+	}
 	return mm
 }
 
@@ -255,6 +301,27 @@ func buildSelectColumnList(t *schema.Table) string {
 	return buf.String()
 }
 
+// buildDeadLetterColumnLists returns the list of all the columns of
+// a message table, and the same list with time_next replaced by NULL.
+// The latter is used to copy failed messages to the dead letter table.
+func buildDeadLetterColumnLists(t *schema.Table) (string, string) {
+	all := sqlparser.NewTrackedBuffer(nil)
+	deadLetters := sqlparser.NewTrackedBuffer(nil)
+	for i, c := range t.Columns {
+		if i != 0 {
+			all.Myprintf(", ")
+			deadLetters.Myprintf(", ")
+		}
+		all.Myprintf("%v", c.Name)
+		if c.Name.EqualString("time_next") {
+			deadLetters.Myprintf("null")
+		} else {
+			deadLetters.Myprintf("%v", c.Name)
+		}
+	}
+	return all.String(), deadLetters.String()
+}
+
 // Open starts the messageManager service.
 func (mm *messageManager) Open() {
 	mm.mu.Lock()
@@ -590,6 +657,12 @@ func (mm *messageManager) runPoller() {
 			// Wake up the sender.
 			defer mm.cond.Broadcast()
 		}
+		var failed []string
+		defer func() {
+			if len(failed) != 0 {
+				go fail(mm.tsv, mm.name.String(), failed, mm.pollerTicks.Interval())
+			}
+		}()
 		for _, row := range qr.Rows {
 			mr, err := BuildMessageRow(row)
 			if err != nil {
@@ -597,6 +670,11 @@ func (mm *messageManager) runPoller() {
 				log.Errorf("Error reading message row: %v", err)
 				continue
 			}
+			if mm.maxEpoch != 0 && mr.Epoch >= mm.maxEpoch {
+				// The message was sent too many times.
+				failed = append(failed, mr.Row[0].ToString())
+				continue
+			}
 			if !mm.cache.Add(mr) {
 				mm.messagesPending = true
 				return
@@ -607,6 +685,9 @@ func (mm *messageManager) runPoller() {
 
 func (mm *messageManager) runPurge() {
 	go purge(mm.tsv, mm.name.String(), mm.purgeAfter, mm.purgeTicks.Interval())
+	if !mm.deadLetter.IsEmpty() {
+		go requeue(mm.tsv, mm.name.String(), mm.purgeTicks.Interval())
+	}
 }
 
 // purge is a non-member because it should be called asynchronously and should
@@ -632,18 +713,49 @@ func purge(tsv TabletService, name string, purgeAfter, purgeInterval time.Durati
 	}
 }
 
-// GenerateAckQuery returns the query and bind vars for acking a message.
-func (mm *messageManager) GenerateAckQuery(ids []string) (string, map[string]*querypb.BindVariable) {
-	idbvs := &querypb.BindVariable{
-		Type:   querypb.Type_TUPLE,
-		Values: make([]*querypb.Value, 0, len(ids)),
+// fail is a non-member because it should be called asynchronously and should
+// not rely on members of messageManager.
+func fail(tsv TabletService, name string, ids []string, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(tabletenv.LocalContext(), timeout)
+	defer func() {
+		tabletenv.LogError()
+		cancel()
+	}()
+	count, err := tsv.FailMessages(ctx, nil, name, ids)
+	if err != nil {
+		MessageStats.Add([]string{name, "FailFailed"}, 1)
+		log.Errorf("Unable to fail messages: %v", err)
+		return
 	}
-	for _, id := range ids {
-		idbvs.Values = append(idbvs.Values, &querypb.Value{
-			Type:  querypb.Type_VARCHAR,
-			Value: []byte(id),
-		})
+	MessageStats.Add([]string{name, "Failed"}, count)
+}
+
+// requeue is a non-member because it should be called asynchronously and should
+// not rely on members of messageManager.
+func requeue(tsv TabletService, name string, requeueInterval time.Duration) {
+	ctx, cancel := context.WithTimeout(tabletenv.LocalContext(), requeueInterval)
+	defer func() {
+		tabletenv.LogError()
+		cancel()
+	}()
+	for {
+		count, err := tsv.RequeueMessages(ctx, nil, name)
+		if err != nil {
+			MessageStats.Add([]string{name, "RequeueFailed"}, 1)
+			log.Errorf("Unable to requeue messages: %v", err)
+			return
+		}
+		MessageStats.Add([]string{name, "Requeued"}, count)
+		// If requeued 500 or more, we should continue.
+		if count < 500 {
+			return
+		}
 	}
+}
+
+// GenerateAckQuery returns the query and bind vars for acking a message.
+func (mm *messageManager) GenerateAckQuery(ids []string) (string, map[string]*querypb.BindVariable) {
+	idbvs := idsBindVariable(ids)
 	return mm.ackQuery.Query, map[string]*querypb.BindVariable{
 		"time_acked": sqltypes.Int64BindVariable(time.Now().UnixNano()),
 		"ids":        idbvs,
@@ -652,16 +764,7 @@ func (mm *messageManager) GenerateAckQuery(ids []string) (string, map[string]*qu
 
 // GeneratePostponeQuery returns the query and bind vars for postponing a message.
 func (mm *messageManager) GeneratePostponeQuery(ids []string) (string, map[string]*querypb.BindVariable) {
-	idbvs := &querypb.BindVariable{
-		Type:   querypb.Type_TUPLE,
-		Values: make([]*querypb.Value, 0, len(ids)),
-	}
-	for _, id := range ids {
-		idbvs.Values = append(idbvs.Values, &querypb.Value{
-			Type:  querypb.Type_VARCHAR,
-			Value: []byte(id),
-		})
-	}
+	idbvs := idsBindVariable(ids)
 	return mm.postponeQuery.Query, map[string]*querypb.BindVariable{
 		"time_now":  sqltypes.Int64BindVariable(time.Now().UnixNano()),
 		"wait_time": sqltypes.Int64BindVariable(int64(mm.ackWaitTime)),
@@ -676,6 +779,71 @@ func (mm *messageManager) GeneratePurgeQuery(timeCutoff int64) (string, map[stri
 	}
 }
 
+// GenerateFailQueries returns the queries and bind vars for failing messages
+// that reached the max epoch. They are executed in the same transaction.
+func (mm *messageManager) GenerateFailQueries(ids []string) ([]string, map[string]*querypb.BindVariable) {
+	bv := map[string]*querypb.BindVariable{
+		"ids":       idsBindVariable(ids),
+		"max_epoch": sqltypes.Int64BindVariable(mm.maxEpoch),
+	}
+	switch {
+	case mm.failQuery != nil:
+		return []string{mm.failQuery.Query}, bv
+	case mm.deadLetterQuery != nil:
+		return []string{mm.deadLetterQuery.Query, mm.deleteFailedQuery.Query}, bv
+	}
+	return nil, bv
+}
+
+// GenerateReadDeadLettersQuery returns the query for reading the dead letters
+// that must be requeued. The results of the query can be used in a
+// GenerateRequeueQueries call.
+func (mm *messageManager) GenerateReadDeadLettersQuery() string {
+	if mm.readDeadLettersQuery == nil {
+		return ""
+	}
+	return mm.readDeadLettersQuery.Query
+}
+
+// GenerateRequeueQueries returns the queries and bind vars for moving dead
+// letters back to the message table. They are executed in the same transaction.
+func (mm *messageManager) GenerateRequeueQueries(rows [][]sqltypes.Value) ([]string, map[string]*querypb.BindVariable) {
+	ids := make([]string, 0, len(rows))
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("insert into %v(%s) values ", mm.name, mm.columnList)
+	for i, row := range rows {
+		ids = append(ids, row[0].ToString())
+		if i != 0 {
+			buf.Myprintf(", ")
+		}
+		buf.Myprintf("(")
+		for j, val := range row {
+			if j != 0 {
+				buf.Myprintf(", ")
+			}
+			val.EncodeSQL(buf)
+		}
+		buf.Myprintf(")")
+	}
+	return []string{buf.String(), mm.deleteDeadLettersQuery.Query}, map[string]*querypb.BindVariable{
+		"ids": idsBindVariable(ids),
+	}
+}
+
+func idsBindVariable(ids []string) *querypb.BindVariable {
+	idbvs := &querypb.BindVariable{
+		Type:   querypb.Type_TUPLE,
+		Values: make([]*querypb.Value, 0, len(ids)),
+	}
+	for _, id := range ids {
+		idbvs.Values = append(idbvs.Values, &querypb.Value{
+			Type:  querypb.Type_VARCHAR,
+			Value: []byte(id),
+		})
+	}
+	return idbvs
+}
+
 // BuildMessageRow builds a MessageRow for a db row.
 func BuildMessageRow(row []sqltypes.Value) (*MessageRow, error) {
 	timeNext, err := sqltypes.ToInt64(row[0])
@@ -552,6 +552,75 @@ func TestMessageManagerPoller(t *testing.T) {
 	}
 }
 
+func TestMessageManagerMaxEpoch(t *testing.T) {
+	db := fakesqldb.New(t)
+	defer db.Close()
+	db.AddQueryPattern(
+		"select time_next, epoch, time_created, id, time_scheduled, message from foo.*",
+		&sqltypes.Result{
+			Fields: []*querypb.Field{
+				{Type: sqltypes.Int64},
+				{Type: sqltypes.Int64},
+				{Type: sqltypes.Int64},
+				{Type: sqltypes.Int64},
+				{Type: sqltypes.Int64},
+				{Type: sqltypes.VarBinary},
+			},
+			Rows: [][]sqltypes.Value{{
+				sqltypes.NewInt64(1),
+				sqltypes.NewInt64(1),
+				sqltypes.NewInt64(0),
+				sqltypes.NewInt64(1),
+				sqltypes.NewInt64(10),
+				sqltypes.NewVarBinary("01"),
+			}, {
+				sqltypes.NewInt64(1),
+				sqltypes.NewInt64(2),
+				sqltypes.NewInt64(0),
+				sqltypes.NewInt64(2),
+				sqltypes.NewInt64(20),
+				sqltypes.NewVarBinary("02"),
+			}},
+		},
+	)
+	tsv := newFakeTabletServer()
+	ch := make(chan string, 20)
+	tsv.SetChannel(ch)
+	ti := newMMTable()
+	ti.MessageInfo.PollInterval = 20 * time.Second
+	ti.MessageInfo.MaxEpoch = 2
+	mm := newMessageManager(tsv, ti, newMMConnPool(db), sync2.NewSemaphore(1, 0))
+	mm.Open()
+	defer mm.Close()
+	r1 := newTestReceiver(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mm.Subscribe(ctx, r1.rcv)
+	<-r1.ch
+	mm.pollerTicks.Trigger()
+
+	// Only the message below the max epoch is sent.
+	want := [][]sqltypes.Value{{
+		sqltypes.NewInt64(1),
+		sqltypes.NewInt64(10),
+		sqltypes.NewVarBinary("01"),
+	}}
+	qr := <-r1.ch
+	if !reflect.DeepEqual(qr.Rows, want) {
+		t.Errorf("rows:\n%+v, want\n%+v", qr.Rows, want)
+	}
+	for got := range ch {
+		if got == "fail" {
+			break
+		}
+	}
+	tsv.mu.Lock()
+	defer tsv.mu.Unlock()
+	if want := []string{"2"}; !reflect.DeepEqual(tsv.failedIDs, want) {
+		t.Errorf("failed ids: %v, want %v", tsv.failedIDs, want)
+	}
+}
+
 // TestMessagesPending1 tests for the case where you can't
 // add items because the cache is full.
 func TestMessagesPending1(t *testing.T) {
@@ -688,6 +757,30 @@ func TestMessageManagerPurge(t *testing.T) {
 	}
 }
 
+func TestMessageManagerRequeue(t *testing.T) {
+	db := fakesqldb.New(t)
+	defer db.Close()
+	tsv := newFakeTabletServer()
+
+	// Make a buffered channel so the thread doesn't block on repeated calls.
+	ch := make(chan string, 20)
+	tsv.SetChannel(ch)
+
+	ti := newMMTable()
+	ti.MessageInfo.PollInterval = 1 * time.Millisecond
+	ti.MessageInfo.MaxEpoch = 2
+	ti.MessageInfo.DeadLetterTable = sqlparser.NewTableIdent("foo_dead")
+	mm := newMessageManager(tsv, ti, newMMConnPool(db), sync2.NewSemaphore(1, 0))
+	mm.Open()
+	defer mm.Close()
+	// Ensure Requeue got called.
+	for got := range ch {
+		if got == "requeue" {
+			break
+		}
+	}
+}
+
 func TestMMGenerate(t *testing.T) {
 	db := fakesqldb.New(t)
 	defer db.Close()
@@ -747,8 +840,9 @@ type fakeTabletServer struct {
 	postponeCount sync2.AtomicInt64
 	purgeCount    sync2.AtomicInt64
 
-	mu sync.Mutex
-	ch chan string
+	mu        sync.Mutex
+	ch        chan string
+	failedIDs []string
 }
 
 func newFakeTabletServer() *fakeTabletServer { return &fakeTabletServer{} }
@@ -783,6 +877,27 @@ func (fts *fakeTabletServer) PurgeMessages(ctx context.Context, target *querypb.
 	return 0, nil
 }
 
+func (fts *fakeTabletServer) FailMessages(ctx context.Context, target *querypb.Target, name string, ids []string) (count int64, err error) {
+	fts.mu.Lock()
+	fts.failedIDs = append(fts.failedIDs, ids...)
+	ch := fts.ch
+	fts.mu.Unlock()
+	if ch != nil {
+		ch <- "fail"
+	}
+	return int64(len(ids)), nil
+}
+
+func (fts *fakeTabletServer) RequeueMessages(ctx context.Context, target *querypb.Target, name string) (count int64, err error) {
+	fts.mu.Lock()
+	ch := fts.ch
+	fts.mu.Unlock()
+	if ch != nil {
+		ch <- "requeue"
+	}
+	return 0, nil
+}
+
 func newMMConnPool(db *fakesqldb.DB) *connpool.Pool {
 	pool := connpool.New("", 20, time.Duration(10*time.Minute), newFakeTabletServer())
 	dbconfigs := dbconfigs.DBConfigs{
@@ -792,3 +907,65 @@ func newMMConnPool(db *fakesqldb.DB) *connpool.Pool {
 	pool.Open(&dbconfigs.App, &dbconfigs.Dba, &dbconfigs.AppDebug)
 	return pool
 }
+
+func TestMMGenerateFail(t *testing.T) {
+	db := fakesqldb.New(t)
+	defer db.Close()
+	ti := newMMTable()
+	ti.MessageInfo.MaxEpoch = 3
+	mm := newMessageManager(newFakeTabletServer(), ti, newMMConnPool(db), sync2.NewSemaphore(1, 0))
+	queries, bv := mm.GenerateFailQueries([]string{"1", "2"})
+	wantQueries := []string{"update foo set time_next = null where id in ::ids and time_acked is null and epoch >= :max_epoch"}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Errorf("GenerateFailQueries queries: %v, want %v", queries, wantQueries)
+	}
+	wantbv := map[string]*querypb.BindVariable{
+		"ids":       sqltypes.TestBindVariable([]interface{}{"1", "2"}),
+		"max_epoch": sqltypes.Int64BindVariable(3),
+	}
+	if !reflect.DeepEqual(bv, wantbv) {
+		t.Errorf("GenerateFailQueries bv: %v, want %v", bv, wantbv)
+	}
+	if query := mm.GenerateReadDeadLettersQuery(); query != "" {
+		t.Errorf("GenerateReadDeadLettersQuery: %s, want empty", query)
+	}
+
+	ti.MessageInfo.DeadLetterTable = sqlparser.NewTableIdent("foo_dead")
+	ti.Columns = []schema.TableColumn{
+		{Name: sqlparser.NewColIdent("id")},
+		{Name: sqlparser.NewColIdent("time_scheduled")},
+		{Name: sqlparser.NewColIdent("time_next")},
+		{Name: sqlparser.NewColIdent("message")},
+	}
+	mm = newMessageManager(newFakeTabletServer(), ti, newMMConnPool(db), sync2.NewSemaphore(1, 0))
+	queries, _ = mm.GenerateFailQueries([]string{"1", "2"})
+	wantQueries = []string{
+		"insert into foo_dead(id, time_scheduled, time_next, message) select id, time_scheduled, null, message from foo where id in ::ids and time_acked is null and epoch >= :max_epoch for update",
+		"delete from foo where id in ::ids and time_acked is null and epoch >= :max_epoch",
+	}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Errorf("GenerateFailQueries queries:\n%v, want\n%v", queries, wantQueries)
+	}
+
+	wantQuery := "select id, time_scheduled, message from foo_dead where time_next is not null limit 500 for update"
+	if query := mm.GenerateReadDeadLettersQuery(); query != wantQuery {
+		t.Errorf("GenerateReadDeadLettersQuery: %s, want %s", query, wantQuery)
+	}
+	queries, bv = mm.GenerateRequeueQueries([][]sqltypes.Value{
+		{sqltypes.NewVarBinary("1"), sqltypes.NewInt64(10), sqltypes.NewVarBinary("01")},
+		{sqltypes.NewVarBinary("2"), sqltypes.NewInt64(20), sqltypes.NewVarBinary("02")},
+	})
+	wantQueries = []string{
+		"insert into foo(id, time_scheduled, message) values ('1', 10, '01'), ('2', 20, '02')",
+		"delete from foo_dead where id in ::ids",
+	}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Errorf("GenerateRequeueQueries queries:\n%v, want\n%v", queries, wantQueries)
+	}
+	wantbv = map[string]*querypb.BindVariable{
+		"ids": sqltypes.TestBindVariable([]interface{}{"1", "2"}),
+	}
+	if !reflect.DeepEqual(bv, wantbv) {
+		t.Errorf("GenerateRequeueQueries bv: %v, want %v", bv, wantbv)
+	}
+}
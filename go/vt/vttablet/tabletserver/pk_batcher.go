@@ -0,0 +1,132 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/sync2"
+)
+
+// pkBatcher batches concurrent point lookups which differ only in
+// their primary key value. The first lookup for a batch query becomes
+// the leader of a new batch. It waits for the batch interval and then
+// executes a single query for all pk values which joined the batch in
+// the meantime. The other lookups wait for the leader to broadcast the
+// result and pick their rows out of it.
+//
+// It complements the sync2.Consolidator, which can only merge queries
+// that are identical.
+type pkBatcher struct {
+	batcher *sync2.Batcher
+	maxSize int
+
+	// mu protects batches and the contents of all open batches.
+	mu      sync.Mutex
+	batches map[string]*pkBatch
+}
+
+// newPKBatcher creates a new pkBatcher. Batches are executed after
+// interval. A batch stops accepting lookups once it contains maxSize
+// distinct pk values, and the following lookups start a new batch.
+func newPKBatcher(interval time.Duration, maxSize int) *pkBatcher {
+	return &pkBatcher{
+		batcher: sync2.NewBatcher(interval),
+		maxSize: maxSize,
+		batches: make(map[string]*pkBatch),
+	}
+}
+
+// pkBatch is a set of lookups which will be executed together.
+type pkBatch struct {
+	pb    *pkBatcher
+	query string
+	pks   []sqltypes.Value
+	seen  map[string]bool
+	done  chan struct{}
+
+	// Result and Err are set by the leader before Broadcast is called.
+	Result *sqltypes.Result
+	Err    error
+}
+
+// Add adds pk to the open batch for query. If there is no open
+// batch, a new one is created and leader is true. The leader must
+// have Wait called, the batch executed and then Broadcast called,
+// even if it stops waiting for the result itself. All others must
+// wait for Done before reading the result.
+func (pb *pkBatcher) Add(query string, pk sqltypes.Value) (b *pkBatch, leader bool) {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	if b, ok := pb.batches[query]; ok {
+		if b.add(pk) {
+			return b, false
+		}
+		// The batch is full. It will be executed when its
+		// leader wakes up, but we can't join it anymore.
+		delete(pb.batches, query)
+	}
+	b = &pkBatch{
+		pb:    pb,
+		query: query,
+		seen:  make(map[string]bool),
+		done:  make(chan struct{}),
+	}
+	b.add(pk)
+	pb.batches[query] = b
+	return b, true
+}
+
+// add adds pk to the batch. It returns false if the batch is full.
+// pb.mu must be held.
+func (b *pkBatch) add(pk sqltypes.Value) bool {
+	key := pk.ToString()
+	if b.seen[key] {
+		return true
+	}
+	if len(b.pks) >= b.pb.maxSize {
+		return false
+	}
+	b.seen[key] = true
+	b.pks = append(b.pks, pk)
+	return true
+}
+
+// Wait blocks until the batch interval has elapsed.
+// It then closes the batch and returns the pk values to fetch.
+func (b *pkBatch) Wait() []sqltypes.Value {
+	b.pb.batcher.Wait()
+
+	b.pb.mu.Lock()
+	defer b.pb.mu.Unlock()
+	if b.pb.batches[b.query] == b {
+		delete(b.pb.batches, b.query)
+	}
+	return b.pks
+}
+
+// Broadcast publishes Result and Err to all lookups of the batch.
+func (b *pkBatch) Broadcast() {
+	close(b.done)
+}
+
+// Done returns a channel which is closed once the result is available.
+func (b *pkBatch) Done() <-chan struct{} {
+	return b.done
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+func TestPKBatcher(t *testing.T) {
+	pb := newPKBatcher(10*time.Millisecond, 3)
+
+	b1, leader := pb.Add("q1", sqltypes.NewInt64(1))
+	if !leader {
+		t.Errorf("Add(q1, 1): leader = false, want true")
+	}
+	b, leader := pb.Add("q1", sqltypes.NewInt64(2))
+	if leader || b != b1 {
+		t.Errorf("Add(q1, 2): %p, %v, want %p, false", b, leader, b1)
+	}
+	// Duplicate pks are fetched only once.
+	b, leader = pb.Add("q1", sqltypes.NewInt64(2))
+	if leader || b != b1 {
+		t.Errorf("Add(q1, 2): %p, %v, want %p, false", b, leader, b1)
+	}
+	// Different queries don't share a batch.
+	b2, leader := pb.Add("q2", sqltypes.NewInt64(1))
+	if !leader || b2 == b1 {
+		t.Errorf("Add(q2, 1): %p, %v, want new batch", b2, leader)
+	}
+	b, leader = pb.Add("q1", sqltypes.NewInt64(3))
+	if leader || b != b1 {
+		t.Errorf("Add(q1, 3): %p, %v, want %p, false", b, leader, b1)
+	}
+	// b1 is full. So, a new batch must be started.
+	b3, leader := pb.Add("q1", sqltypes.NewInt64(4))
+	if !leader || b3 == b1 {
+		t.Errorf("Add(q1, 4): %p, %v, want new batch", b3, leader)
+	}
+
+	got := b1.Wait()
+	want := []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2), sqltypes.NewInt64(3)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("b1.Wait(): %v, want %v", got, want)
+	}
+	// The closing of a full batch must not affect its successor.
+	if b, _ := pb.Add("q1", sqltypes.NewInt64(5)); b != b3 {
+		t.Errorf("Add(q1, 5): %p, want %p", b, b3)
+	}
+	got = b3.Wait()
+	want = []sqltypes.Value{sqltypes.NewInt64(4), sqltypes.NewInt64(5)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("b3.Wait(): %v, want %v", got, want)
+	}
+	// b3 is closed now.
+	if b, leader := pb.Add("q1", sqltypes.NewInt64(6)); !leader || b == b3 {
+		t.Errorf("Add(q1, 6): %p, %v, want new batch", b, leader)
+	}
+
+	select {
+	case <-b1.Done():
+		t.Errorf("b1.Done() is closed before Broadcast")
+	default:
+	}
+	b1.Broadcast()
+	<-b1.Done()
+}
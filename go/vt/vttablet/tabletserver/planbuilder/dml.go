@@ -209,10 +209,74 @@ func analyzeSelect(sel *sqlparser.Select, tables map[string]*schema.Table) (plan
 		plan.PKValues = []sqltypes.PlanValue{v}
 		plan.FieldQuery = nil
 		plan.FullQuery = nil
+		return plan, nil
+	}
+
+	// The batch query relies on the field info to be known
+	// up-front, which is why FieldQuery must be set.
+	if plan.PlanID == PlanPassSelect && plan.FieldQuery != nil {
+		if pkValue, ok := analyzePointLookup(sel, table); ok {
+			plan.PKValues = []sqltypes.PlanValue{pkValue}
+			plan.BatchQuery = GenerateBatchQuery(sel, table.GetPKColumn(0).Name)
+		}
 	}
 	return plan, nil
 }
 
+// analyzePointLookup returns the pk value if sel fetches a single row
+// by the primary key of the table. Only tables with a single integral
+// pk column qualify because the rows of a batched lookup are matched
+// back to their requests by comparing the pk values.
+func analyzePointLookup(sel *sqlparser.Select, table *schema.Table) (sqltypes.PlanValue, bool) {
+	if table.Type != schema.NoType || len(table.PKColumns) != 1 || table.PKColumns[0] < 0 {
+		return sqltypes.PlanValue{}, false
+	}
+	pkColumn := table.GetPKColumn(0)
+	if !sqltypes.IsIntegral(pkColumn.Type) {
+		return sqltypes.PlanValue{}, false
+	}
+	if sel.Distinct != "" || sel.GroupBy != nil || sel.Having != nil || sel.OrderBy != nil || sel.Limit != nil || sel.Where == nil {
+		return sqltypes.PlanValue{}, false
+	}
+	// Locking reads must run on their own, in their transaction.
+	if sel.Lock != "" {
+		return sqltypes.PlanValue{}, false
+	}
+	conditions := analyzeBoolean(sel.Where.Expr)
+	if len(conditions) != 1 || conditions[0].Operator != sqlparser.EqualStr {
+		return sqltypes.PlanValue{}, false
+	}
+	if !conditions[0].Left.(*sqlparser.ColName).Name.Equal(pkColumn.Name) {
+		return sqltypes.PlanValue{}, false
+	}
+	if hasAggregateOrSubquery(sel.SelectExprs) {
+		return sqltypes.PlanValue{}, false
+	}
+	pkValue, err := sqlparser.NewPlanValue(conditions[0].Right)
+	if err != nil || pkValue.IsNull() {
+		return sqltypes.PlanValue{}, false
+	}
+	return pkValue, true
+}
+
+func hasAggregateOrSubquery(exprs sqlparser.SelectExprs) bool {
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.FuncExpr:
+			if node.IsAggregate() {
+				found = true
+				return false, nil
+			}
+		case *sqlparser.GroupConcatExpr, *sqlparser.Subquery:
+			found = true
+			return false, nil
+		}
+		return true, nil
+	}, exprs)
+	return found
+}
+
 func analyzeFrom(tableExprs sqlparser.TableExprs) sqlparser.TableIdent {
 	if len(tableExprs) > 1 {
 		return sqlparser.NewTableIdent("")
@@ -234,6 +234,7 @@ type Plan struct {
 	// PlanDMLPK: where clause values.
 	// PlanInsertPK: values clause.
 	// PlanNextVal: increment.
+	// PlanPassSelect: point lookup value if BatchQuery is set.
 	PKValues []sqltypes.PlanValue
 
 	// For update: set clause if pk is changing.
@@ -245,6 +246,12 @@ type Plan struct {
 
 	// For PlanInsertSubquery: pk columns in the subquery result.
 	SubqueryPKColumns []int
+
+	// BatchQuery is set for PlanPassSelect if the query is a point
+	// lookup on a single integral primary key column. It fetches the
+	// same select expressions followed by the pk column for all values
+	// in the :#pks list, which allows concurrent lookups to be batched.
+	BatchQuery *sqlparser.ParsedQuery
 }
 
 // TableName returns the table name for the plan.
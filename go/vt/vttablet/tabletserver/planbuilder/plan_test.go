@@ -55,6 +55,7 @@ func (p *Plan) MarshalJSON() ([]byte, error) {
 		SecondaryPKValues []sqltypes.PlanValue   `json:",omitempty"`
 		WhereClause       *sqlparser.ParsedQuery `json:",omitempty"`
 		SubqueryPKColumns []int                  `json:",omitempty"`
+		BatchQuery        *sqlparser.ParsedQuery `json:",omitempty"`
 	}{
 		PlanID:            p.PlanID,
 		Reason:            p.Reason,
@@ -70,6 +71,7 @@ func (p *Plan) MarshalJSON() ([]byte, error) {
 		SecondaryPKValues: p.SecondaryPKValues,
 		WhereClause:       p.WhereClause,
 		SubqueryPKColumns: p.SubqueryPKColumns,
+		BatchQuery:        p.BatchQuery,
 	}
 	return json.Marshal(&mplan)
 }
@@ -66,6 +66,24 @@ func GenerateLimitQuery(selStmt sqlparser.SelectStatement) *sqlparser.ParsedQuer
 	return buf.ParsedQuery()
 }
 
+// GenerateBatchQuery generates the query for a batch of point lookups.
+// The pk column is appended to the select expressions so that the rows
+// can be routed back to the individual lookups.
+func GenerateBatchQuery(sel *sqlparser.Select, pkColumn sqlparser.ColIdent) *sqlparser.ParsedQuery {
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("select %v%s%s%v, %v from %v where %v in %a",
+		sel.Comments,
+		sel.Cache,
+		sel.Hints,
+		sel.SelectExprs,
+		pkColumn,
+		sel.From,
+		pkColumn,
+		"::#pks",
+	)
+	return buf.ParsedQuery()
+}
+
 // GenerateInsertOuterQuery generates the outer query for inserts.
 func GenerateInsertOuterQuery(ins *sqlparser.Insert) *sqlparser.ParsedQuery {
 	buf := sqlparser.NewTrackedBuffer(nil)
@@ -132,6 +132,8 @@ type QueryEngine struct {
 
 	// Services
 	consolidator *sync2.Consolidator
+	// pkBatcher is only set if pk lookup batching is enabled.
+	pkBatcher *pkBatcher
 	// txSerializer protects vttablet from applications which try to concurrently
 	// UPDATE (or DELETE) a "hot" row (or range of rows).
 	// Such queries would be serialized by MySQL anyway. This serializer prevents
@@ -198,6 +200,9 @@ func NewQueryEngine(checker connpool.MySQLChecker, se *schema.Engine, config tab
 	)
 
 	qe.consolidator = sync2.NewConsolidator()
+	if config.EnablePKLookupBatching {
+		qe.pkBatcher = newPKBatcher(config.PKLookupBatchingInterval, config.PKLookupBatchingMaxSize)
+	}
 	qe.txSerializer = txserializer.New(config.EnableHotRowProtectionDryRun,
 		config.HotRowProtectionMaxQueueSize,
 		config.HotRowProtectionMaxGlobalQueueSize,
@@ -28,6 +28,7 @@ import (
 	"vitess.io/vitess/go/hack"
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/tb"
 	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/callinfo"
@@ -74,6 +75,12 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 	qre.logStats.TransactionID = qre.transactionID
 	planName := qre.plan.PlanID.String()
 	qre.logStats.PlanType = planName
+
+	span := trace.NewSpanFromContext(qre.ctx)
+	span.StartLocal("QueryExecutor.Execute")
+	span.Annotate("plan", planName)
+	defer span.Finish()
+	qre.ctx = trace.NewContext(qre.ctx, span)
 	defer func(start time.Time) {
 		duration := time.Now().Sub(start)
 		tabletenv.QueryStats.Add(planName, duration)
@@ -192,6 +199,12 @@ func (qre *QueryExecutor) Stream(callback func(*sqltypes.Result) error) error {
 	qre.logStats.OriginalSQL = qre.query
 	qre.logStats.PlanType = qre.plan.PlanID.String()
 
+	span := trace.NewSpanFromContext(qre.ctx)
+	span.StartLocal("QueryExecutor.Stream")
+	span.Annotate("plan", qre.logStats.PlanType)
+	defer span.Finish()
+	qre.ctx = trace.NewContext(qre.ctx, span)
+
 	defer func(start time.Time) {
 		tabletenv.QueryStats.Record(qre.plan.PlanID.String(), start)
 		tabletenv.RecordUserQuery(qre.ctx, qre.plan.TableName(), "Stream", int64(time.Now().Sub(start)))
@@ -499,6 +512,18 @@ func (qre *QueryExecutor) execDirect(conn *TxConnection) (*sqltypes.Result, erro
 // execSelect sends a query to mysql only if another identical query is not running. Otherwise, it waits and
 // reuses the result. If the plan is missng field info, it sends the query to mysql requesting full info.
 func (qre *QueryExecutor) execSelect() (*sqltypes.Result, error) {
+	if qre.plan.BatchQuery != nil && qre.tsv.qe.pkBatcher != nil {
+		pk, err := qre.plan.PKValues[0].ResolveValue(qre.bindVars)
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%s", err)
+		}
+		// Values which are not valid integers are left for MySQL
+		// to deal with. They can't be matched against the rows of
+		// a batch.
+		if pk, ok := normalizeIntegralPK(pk); ok {
+			return qre.execBatchedSelect(pk)
+		}
+	}
 	if qre.plan.Fields != nil {
 		result, err := qre.qFetch(qre.logStats, qre.plan.FullQuery, qre.bindVars)
 		if err != nil {
@@ -517,6 +542,103 @@ func (qre *QueryExecutor) execSelect() (*sqltypes.Result, error) {
 	return qre.dbConnFetch(conn, qre.plan.FullQuery, qre.bindVars, nil, true)
 }
 
+// execBatchedSelect executes a point lookup for pk as part of a batch
+// of concurrent lookups on the same table.
+func (qre *QueryExecutor) execBatchedSelect(pk sqltypes.Value) (*sqltypes.Result, error) {
+	b, leader := qre.tsv.qe.pkBatcher.Add(qre.plan.BatchQuery.Query, pk)
+	startTime := time.Now()
+	if leader {
+		// The batch runs on its own: like the other lookups, the
+		// leader stops waiting for it if its context is done.
+		go qre.runBatch(b)
+	} else {
+		qre.logStats.QuerySources |= tabletenv.QuerySourcePKBatcher
+	}
+	select {
+	case <-b.Done():
+	case <-qre.ctx.Done():
+		return nil, vterrors.Errorf(vtrpcpb.Code_DEADLINE_EXCEEDED, "waiting for pk lookup batch: %v", qre.ctx.Err())
+	}
+	if !leader {
+		tabletenv.WaitStats.Record("PKLookupBatchWaits", startTime)
+	}
+	if b.Err != nil {
+		return nil, b.Err
+	}
+
+	// The batch result is shared. So, we build a new result
+	// that only references the rows for pk.
+	key := pk.ToString()
+	result := &sqltypes.Result{Fields: qre.plan.Fields}
+	for _, row := range b.Result.Rows {
+		last := len(row) - 1
+		if row[last].ToString() == key {
+			result.Rows = append(result.Rows, row[:last])
+		}
+	}
+	result.RowsAffected = uint64(len(result.Rows))
+	return result, nil
+}
+
+// runBatch waits for the batch b to close, executes it and broadcasts
+// its result. The batch serves all its lookups, so it runs under a
+// context of its own, bounded by the query timeout: the other lookups
+// must not fail because the leader was cancelled or reached its
+// deadline.
+func (qre *QueryExecutor) runBatch(b *pkBatch) {
+	defer func() {
+		if x := recover(); x != nil {
+			log.Errorf("Uncaught panic in pk lookup batch %v:\n%v\n%s", b.query, x, tb.Stack(4))
+			tabletenv.InternalErrors.Add("Panic", 1)
+			b.Result, b.Err = nil, vterrors.Errorf(vtrpcpb.Code_UNKNOWN, "uncaught panic in pk lookup batch: %v", x)
+		}
+		b.Broadcast()
+	}()
+
+	startTime := time.Now()
+	pks := b.Wait()
+	tabletenv.WaitStats.Record("PKLookupBatches", startTime)
+
+	ctx := callerid.NewContext(context.Background(), callerid.EffectiveCallerIDFromContext(qre.ctx), callerid.ImmediateCallerIDFromContext(qre.ctx))
+	ctx = trace.CopySpan(ctx, qre.ctx)
+	ctx, cancel := withTimeout(ctx, qre.tsv.QueryTimeout.Get(), qre.options)
+	defer cancel()
+
+	batchQre := *qre
+	batchQre.ctx = ctx
+	batchQre.logStats = tabletenv.NewLogStats(ctx, "PKLookupBatch")
+	b.Result, b.Err = batchQre.fetchBatch(pks)
+}
+
+// fetchBatch executes the batch query of the plan for all pks.
+func (qre *QueryExecutor) fetchBatch(pks []sqltypes.Value) (*sqltypes.Result, error) {
+	values := make([]*querypb.Value, len(pks))
+	for i, pk := range pks {
+		values[i] = sqltypes.ValueToProto(pk)
+	}
+	bindVars := map[string]*querypb.BindVariable{
+		"#pks": {Type: querypb.Type_TUPLE, Values: values},
+	}
+	conn, err := qre.getConn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Recycle()
+	return qre.dbConnFetch(conn, qre.plan.BatchQuery, bindVars, nil, false)
+}
+
+// normalizeIntegralPK converts v into its canonical integral
+// representation, which is the way MySQL returns it in a row.
+func normalizeIntegralPK(v sqltypes.Value) (sqltypes.Value, bool) {
+	if ival, err := sqltypes.ToInt64(v); err == nil {
+		return sqltypes.NewInt64(ival), true
+	}
+	if uval, err := sqltypes.ToUint64(v); err == nil {
+		return sqltypes.NewUint64(uval), true
+	}
+	return sqltypes.NULL, false
+}
+
 func (qre *QueryExecutor) execInsertPK(conn *TxConnection) (*sqltypes.Result, error) {
 	pkRows, err := buildValueList(qre.plan.Table, qre.plan.PKValues, qre.bindVars)
 	if err != nil {
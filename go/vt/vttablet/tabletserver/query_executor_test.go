@@ -23,6 +23,7 @@ import (
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -1242,6 +1243,143 @@ func TestQueryExecutorPlanPassSelect(t *testing.T) {
 	}
 }
 
+func TestQueryExecutorPlanPassSelectBatched(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+	fields := []*querypb.Field{
+		{Name: "pk", Type: sqltypes.Int32},
+		{Name: "name", Type: sqltypes.Int32},
+	}
+	db.AddQuery("select pk, name from test_table where 1 != 1", &sqltypes.Result{
+		Fields: fields,
+	})
+	// The order of the pks in the batch depends on the order in which
+	// the lookups arrive. They may also end up in separate batches.
+	db.AddQueryPattern(`select pk, name, pk from test_table where pk in \(.*\)`, &sqltypes.Result{
+		Fields: append(fields, &querypb.Field{Name: "pk", Type: sqltypes.Int32}),
+		Rows: [][]sqltypes.Value{
+			{sqltypes.NewInt32(1), sqltypes.NewInt32(10), sqltypes.NewInt32(1)},
+			{sqltypes.NewInt32(2), sqltypes.NewInt32(20), sqltypes.NewInt32(2)},
+		},
+		RowsAffected: 2,
+	})
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, enablePKLookupBatching, db)
+	defer tsv.StopService()
+
+	queries := []string{
+		"select pk, name from test_table where pk = 1",
+		"select pk, name from test_table where pk = 2",
+		"select pk, name from test_table where pk = 3",
+	}
+	wantRows := [][][]sqltypes.Value{
+		{{sqltypes.NewInt32(1), sqltypes.NewInt32(10)}},
+		{{sqltypes.NewInt32(2), sqltypes.NewInt32(20)}},
+		nil,
+	}
+	qres := make([]*QueryExecutor, len(queries))
+	for i, query := range queries {
+		qres[i] = newTestQueryExecutor(ctx, tsv, query, 0)
+		checkPlanID(t, planbuilder.PlanPassSelect, qres[i].plan.PlanID)
+		if qres[i].plan.BatchQuery == nil {
+			t.Fatalf("plan for %s has no batch query", query)
+		}
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*sqltypes.Result, len(queries))
+	errs := make([]error, len(queries))
+	for i := range qres {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = qres[i].Execute()
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range queries {
+		if errs[i] != nil {
+			t.Fatalf("Execute(%s) = %v, want nil", queries[i], errs[i])
+		}
+		want := &sqltypes.Result{
+			Fields:       fields,
+			Rows:         wantRows[i],
+			RowsAffected: uint64(len(wantRows[i])),
+		}
+		if !reflect.DeepEqual(results[i], want) {
+			t.Errorf("Execute(%s): %v, want %v", queries[i], results[i], want)
+		}
+	}
+}
+
+func TestQueryExecutorPlanPassSelectBatchedLeaderCancelled(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+	fields := []*querypb.Field{
+		{Name: "pk", Type: sqltypes.Int32},
+		{Name: "name", Type: sqltypes.Int32},
+	}
+	db.AddQuery("select pk, name from test_table where 1 != 1", &sqltypes.Result{
+		Fields: fields,
+	})
+	tsv := newTestTabletServer(context.Background(), enablePKLookupBatching, db)
+	defer tsv.StopService()
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leader := newTestQueryExecutor(leaderCtx, tsv, "select pk, name from test_table where pk = 1", 0)
+	follower := newTestQueryExecutor(context.Background(), tsv, "select pk, name from test_table where pk = 2", 0)
+
+	// The batches are blocked in MySQL until release is closed, so the
+	// leader can only return before its batch is done.
+	release := make(chan struct{})
+	for _, query := range []string{
+		"select pk, name, pk from test_table where pk in (1)",
+		"select pk, name, pk from test_table where pk in (1, 2)",
+		"select pk, name, pk from test_table where pk in (2)",
+	} {
+		db.AddQuery(query, &sqltypes.Result{
+			Fields: append(fields, &querypb.Field{Name: "pk", Type: sqltypes.Int32}),
+			Rows: [][]sqltypes.Value{
+				{sqltypes.NewInt32(2), sqltypes.NewInt32(20), sqltypes.NewInt32(2)},
+			},
+			RowsAffected: 1,
+		})
+		db.SetBeforeFunc(query, func() { <-release })
+	}
+
+	// The leader is cancelled while its batch is still open. It stops
+	// waiting, but the batch goes on.
+	cancel()
+	if _, err := leader.Execute(); vterrors.Code(err) != vtrpcpb.Code_DEADLINE_EXCEEDED {
+		t.Errorf("leader Execute() = %v, want DEADLINE_EXCEEDED", err)
+	}
+
+	// The follower still gets its row, from the batch of the leader
+	// if it joined it in time.
+	type result struct {
+		qr  *sqltypes.Result
+		err error
+	}
+	followerDone := make(chan result)
+	go func() {
+		qr, err := follower.Execute()
+		followerDone <- result{qr, err}
+	}()
+	close(release)
+	got := <-followerDone
+	if got.err != nil {
+		t.Fatalf("follower Execute() = %v, want nil", got.err)
+	}
+	want := &sqltypes.Result{
+		Fields:       fields,
+		Rows:         [][]sqltypes.Value{{sqltypes.NewInt32(2), sqltypes.NewInt32(20)}},
+		RowsAffected: 1,
+	}
+	if !reflect.DeepEqual(got.qr, want) {
+		t.Errorf("follower Execute(): %v, want %v", got.qr, want)
+	}
+}
+
 func TestQueryExecutorPlanPassSelectSqlSelectLimit(t *testing.T) {
 	db := setUpQueryExecutorTest(t)
 	defer db.Close()
@@ -1948,6 +2086,7 @@ const (
 	smallTxPool
 	noTwopc
 	shortTwopcAge
+	enablePKLookupBatching
 )
 
 // newTestQueryExecutor uses a package level variable testTabletServer defined in tabletserver_test.go
@@ -1978,6 +2117,10 @@ func newTestTabletServer(ctx context.Context, flags executorFlags, db *fakesqldb
 	} else {
 		config.TwoPCAbandonAge = 10
 	}
+	if flags&enablePKLookupBatching > 0 {
+		config.EnablePKLookupBatching = true
+		config.PKLookupBatchingInterval = 50 * time.Millisecond
+	}
 	tsv := NewTabletServerWithNilTopoServer(config)
 	testUtils := newTestUtils()
 	dbconfigs := testUtils.newDBConfigs(db)
@@ -2012,7 +2155,7 @@ func newTestQueryExecutor(ctx context.Context, tsv *TabletServer, sql string, tx
 }
 
 func testCommitHelper(t *testing.T, tsv *TabletServer, queryExecutor *QueryExecutor) {
-	if err := tsv.Commit(queryExecutor.ctx, &tsv.target, queryExecutor.transactionID); err != nil {
+	if _, err := tsv.Commit(queryExecutor.ctx, &tsv.target, queryExecutor.transactionID, false); err != nil {
 		t.Fatalf("failed to commit transaction: %d, err: %v", queryExecutor.transactionID, err)
 	}
 }
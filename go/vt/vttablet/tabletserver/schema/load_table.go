@@ -172,6 +172,17 @@ func loadMessageInfo(ta *Table, comment string) error {
 	if ta.MessageInfo.PollInterval, err = getDuration(keyvals, "vt_poller_interval"); err != nil {
 		return err
 	}
+	if keyvals["vt_max_epoch"] != "" {
+		if ta.MessageInfo.MaxEpoch, err = getNum(keyvals, "vt_max_epoch"); err != nil {
+			return err
+		}
+	}
+	if name := keyvals["vt_dead_letter_table"]; name != "" {
+		if ta.MessageInfo.MaxEpoch == 0 {
+			return fmt.Errorf("vt_dead_letter_table requires vt_max_epoch for message table: %s", ta.Name.String())
+		}
+		ta.MessageInfo.DeadLetterTable = sqlparser.NewTableIdent(name)
+	}
 	for _, col := range orderedColumns {
 		num := ta.FindColumn(sqlparser.NewColIdent(col))
 		if num == -1 {
@@ -171,6 +171,23 @@ func TestLoadTableMessage(t *testing.T) {
 		t.Errorf("newTestLoadTable: %v, want %s", err, wanterr)
 	}
 
+	// Max epoch and dead letter table.
+	for query, result := range getMessageTableQueries() {
+		db.AddQuery(query, result)
+	}
+	table, err = newTestLoadTable("USER_TABLE", "vitess_message,vt_ack_wait=30,vt_purge_after=120,vt_batch_size=1,vt_cache_size=10,vt_poller_interval=30,vt_max_epoch=5,vt_dead_letter_table=dead_table", db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.MessageInfo.MaxEpoch != 5 || table.MessageInfo.DeadLetterTable.String() != "dead_table" {
+		t.Errorf("MessageInfo: %+v, want MaxEpoch 5 and DeadLetterTable dead_table", table.MessageInfo)
+	}
+	_, err = newTestLoadTable("USER_TABLE", "vitess_message,vt_ack_wait=30,vt_purge_after=120,vt_batch_size=1,vt_cache_size=10,vt_poller_interval=30,vt_dead_letter_table=dead_table", db)
+	wanterr = "vt_dead_letter_table requires vt_max_epoch for message table: test_table"
+	if err == nil || err.Error() != wanterr {
+		t.Errorf("newTestLoadTable: %v, want %s", err, wanterr)
+	}
+
 	// id column must be part of primary key.
 	for query, result := range getMessageTableQueries() {
 		db.AddQuery(query, result)
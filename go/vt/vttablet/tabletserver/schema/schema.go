@@ -120,6 +120,16 @@ type MessageInfo struct {
 	// PollInterval specifies the polling frequency to
 	// look for messages to be sent.
 	PollInterval time.Duration
+
+	// MaxEpoch specifies the number of times a message
+	// is sent before it's considered failed. It is 0 if
+	// messages are resent until they're acked.
+	MaxEpoch int
+
+	// DeadLetterTable specifies the table failed messages
+	// are moved to. If empty, failed messages stay in the
+	// message table and are not resent.
+	DeadLetterTable sqlparser.TableIdent
 }
 
 // NewTable creates a new Table.
@@ -87,6 +87,10 @@ func init() {
 	flag.IntVar(&Config.HotRowProtectionMaxGlobalQueueSize, "hot_row_protection_max_global_queue_size", DefaultQsConfig.HotRowProtectionMaxGlobalQueueSize, "Global queue limit across all row (ranges). Useful to prevent that the queue can grow unbounded.")
 	flag.IntVar(&Config.HotRowProtectionConcurrentTransactions, "hot_row_protection_concurrent_transactions", DefaultQsConfig.HotRowProtectionConcurrentTransactions, "Number of concurrent transactions let through to the txpool/MySQL for the same hot row. Should be > 1 to have enough 'ready' transactions in MySQL and benefit from a pipelining effect.")
 
+	flag.BoolVar(&Config.EnablePKLookupBatching, "enable_pk_lookup_batching", DefaultQsConfig.EnablePKLookupBatching, "If true, concurrent non-transactional selects by a single integral primary key on the same table will be batched into one query. Useful for replicas which serve a high rate of point lookups.")
+	flag.DurationVar(&Config.PKLookupBatchingInterval, "pk_lookup_batching_interval", DefaultQsConfig.PKLookupBatchingInterval, "How long the first lookup of a batch waits for others to join the batch before it is executed.")
+	flag.IntVar(&Config.PKLookupBatchingMaxSize, "pk_lookup_batching_max_size", DefaultQsConfig.PKLookupBatchingMaxSize, "Maximum number of distinct primary key values which will be fetched by a single batch.")
+
 	flag.BoolVar(&Config.EnableTransactionLimit, "enable_transaction_limit", DefaultQsConfig.EnableTransactionLimit, "If true, limit on number of transactions open at the same time will be enforced for all users. User trying to open a new transaction after exhausting their limit will receive an error immediately, regardless of whether there are available slots or not.")
 	flag.BoolVar(&Config.EnableTransactionLimitDryRun, "enable_transaction_limit_dry_run", DefaultQsConfig.EnableTransactionLimitDryRun, "If true, limit on number of transactions open at the same time will be tracked for all users, but not enforced.")
 	flag.Float64Var(&Config.TransactionLimitPerUser, "transaction_limit_per_user", DefaultQsConfig.TransactionLimitPerUser, "Maximum number of transactions a single user is allowed to use at any time, represented as fraction of -transaction_cap.")
@@ -163,6 +167,10 @@ type TabletConfig struct {
 	HotRowProtectionMaxGlobalQueueSize     int
 	HotRowProtectionConcurrentTransactions int
 
+	EnablePKLookupBatching   bool
+	PKLookupBatchingInterval time.Duration
+	PKLookupBatchingMaxSize  int
+
 	TransactionLimitConfig
 
 	HeartbeatEnable   bool
@@ -236,6 +244,10 @@ var DefaultQsConfig = TabletConfig{
 	// of them ready in MySQL and profit from a pipelining effect.
 	HotRowProtectionConcurrentTransactions: 5,
 
+	EnablePKLookupBatching:   false,
+	PKLookupBatchingInterval: 1 * time.Millisecond,
+	PKLookupBatchingMaxSize:  100,
+
 	TransactionLimitConfig: defaultTransactionLimitConfig(),
 
 	HeartbeatEnable:   false,
@@ -39,6 +39,8 @@ const (
 	QuerySourceConsolidator = 1 << iota
 	// QuerySourceMySQL means query result is returned from MySQL.
 	QuerySourceMySQL
+	// QuerySourcePKBatcher means query result is found in a batch of pk lookups.
+	QuerySourcePKBatcher
 )
 
 // LogStats records the stats for a single query
@@ -192,7 +194,7 @@ func (stats *LogStats) FmtQuerySources() string {
 	if stats.QuerySources == 0 {
 		return "none"
 	}
-	sources := make([]string, 2)
+	sources := make([]string, 3)
 	n := 0
 	if stats.QuerySources&QuerySourceMySQL != 0 {
 		sources[n] = "mysql"
@@ -202,6 +204,10 @@ func (stats *LogStats) FmtQuerySources() string {
 		sources[n] = "consolidator"
 		n++
 	}
+	if stats.QuerySources&QuerySourcePKBatcher != 0 {
+		sources[n] = "pk_batcher"
+		n++
+	}
 	return strings.Join(sources[:n], ",")
 }
 
@@ -208,6 +208,11 @@ func TestLogStatsFormatQuerySources(t *testing.T) {
 	if !strings.Contains(logStats.FmtQuerySources(), "consolidator") {
 		t.Fatalf("'consolidator' should be in formated query sources")
 	}
+
+	logStats.QuerySources |= QuerySourcePKBatcher
+	if !strings.Contains(logStats.FmtQuerySources(), "pk_batcher") {
+		t.Fatalf("'pk_batcher' should be in formated query sources")
+	}
 }
 
 func TestLogStatsContextHTML(t *testing.T) {
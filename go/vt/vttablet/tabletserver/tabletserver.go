@@ -36,6 +36,7 @@ import (
 	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/sync2"
 	"vitess.io/vitess/go/tb"
+	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/binlog"
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/dbconfigs"
@@ -711,18 +712,24 @@ func (tsv *TabletServer) Begin(ctx context.Context, target *querypb.Target, opti
 	return transactionID, err
 }
 
-// Commit commits the specified transaction.
-func (tsv *TabletServer) Commit(ctx context.Context, target *querypb.Target, transactionID int64) (err error) {
-	return tsv.execRequest(
+// Commit commits the specified transaction. If includeGTIDExecuted
+// is true, it also returns the replication position after the commit.
+func (tsv *TabletServer) Commit(ctx context.Context, target *querypb.Target, transactionID int64, includeGTIDExecuted bool) (gtidExecuted string, err error) {
+	err = tsv.execRequest(
 		ctx, tsv.QueryTimeout.Get(),
 		"Commit", "commit", nil,
 		target, nil, false /* isBegin */, true, /* allowOnShutdown */
 		func(ctx context.Context, logStats *tabletenv.LogStats) error {
 			defer tabletenv.QueryStats.Record("COMMIT", time.Now())
 			logStats.TransactionID = transactionID
-			return tsv.te.txPool.Commit(ctx, transactionID, tsv.messager)
+			if err := tsv.te.txPool.Commit(ctx, transactionID, tsv.messager); err != nil {
+				return err
+			}
+			gtidExecuted = tsv.gtidExecutedAfterCommit(ctx, includeGTIDExecuted)
+			return nil
 		},
 	)
+	return gtidExecuted, err
 }
 
 // Rollback rollsback the specified transaction.
@@ -757,9 +764,11 @@ func (tsv *TabletServer) Prepare(ctx context.Context, target *querypb.Target, tr
 	)
 }
 
-// CommitPrepared commits the prepared transaction.
-func (tsv *TabletServer) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string) (err error) {
-	return tsv.execRequest(
+// CommitPrepared commits the prepared transaction. If
+// includeGTIDExecuted is true, it also returns the replication position
+// after the commit.
+func (tsv *TabletServer) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string, includeGTIDExecuted bool) (gtidExecuted string, err error) {
+	err = tsv.execRequest(
 		ctx, tsv.QueryTimeout.Get(),
 		"CommitPrepared", "commit_prepared", nil,
 		target, nil, false /* isBegin */, true, /* allowOnShutdown */
@@ -770,9 +779,14 @@ func (tsv *TabletServer) CommitPrepared(ctx context.Context, target *querypb.Tar
 				te:       tsv.te,
 				messager: tsv.messager,
 			}
-			return txe.CommitPrepared(dtid)
+			if err := txe.CommitPrepared(dtid); err != nil {
+				return err
+			}
+			gtidExecuted = tsv.gtidExecutedAfterCommit(ctx, includeGTIDExecuted)
+			return nil
 		},
 	)
+	return gtidExecuted, err
 }
 
 // RollbackPrepared commits the prepared transaction.
@@ -812,9 +826,11 @@ func (tsv *TabletServer) CreateTransaction(ctx context.Context, target *querypb.
 }
 
 // StartCommit atomically commits the transaction along with the
-// decision to commit the associated 2pc transaction.
-func (tsv *TabletServer) StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string) (err error) {
-	return tsv.execRequest(
+// decision to commit the associated 2pc transaction. If
+// includeGTIDExecuted is true, it also returns the replication position
+// after the commit.
+func (tsv *TabletServer) StartCommit(ctx context.Context, target *querypb.Target, transactionID int64, dtid string, includeGTIDExecuted bool) (gtidExecuted string, err error) {
+	err = tsv.execRequest(
 		ctx, tsv.QueryTimeout.Get(),
 		"StartCommit", "start_commit", nil,
 		target, nil, false /* isBegin */, true, /* allowOnShutdown */
@@ -825,9 +841,14 @@ func (tsv *TabletServer) StartCommit(ctx context.Context, target *querypb.Target
 				te:       tsv.te,
 				messager: tsv.messager,
 			}
-			return txe.StartCommit(transactionID, dtid)
+			if err := txe.StartCommit(transactionID, dtid); err != nil {
+				return err
+			}
+			gtidExecuted = tsv.gtidExecutedAfterCommit(ctx, includeGTIDExecuted)
+			return nil
 		},
 	)
+	return gtidExecuted, err
 }
 
 // SetRollback transitions the 2pc transaction to the Rollback state.
@@ -896,6 +917,9 @@ func (tsv *TabletServer) Execute(ctx context.Context, target *querypb.Target, sq
 		"Execute", sql, bindVariables,
 		target, options, false /* isBegin */, allowOnShutdown,
 		func(ctx context.Context, logStats *tabletenv.LogStats) error {
+			if err := tsv.waitForGTIDSet(ctx, options); err != nil {
+				return err
+			}
 			if bindVariables == nil {
 				bindVariables = make(map[string]*querypb.BindVariable)
 			}
@@ -937,6 +961,9 @@ func (tsv *TabletServer) StreamExecute(ctx context.Context, target *querypb.Targ
 		"StreamExecute", sql, bindVariables,
 		target, options, false /* isBegin */, false, /* allowOnShutdown */
 		func(ctx context.Context, logStats *tabletenv.LogStats) error {
+			if err := tsv.waitForGTIDSet(ctx, options); err != nil {
+				return err
+			}
 			if bindVariables == nil {
 				bindVariables = make(map[string]*querypb.BindVariable)
 			}
@@ -971,6 +998,10 @@ func (tsv *TabletServer) ExecuteBatch(ctx context.Context, target *querypb.Targe
 	if asTransaction && transactionID != 0 {
 		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot start a new transaction in the scope of an existing one")
 	}
+	options, err = tsv.waitForGTIDSetRequest(ctx, target, options)
+	if err != nil {
+		return nil, err
+	}
 
 	if tsv.enableHotRowProtection && asTransaction {
 		// Serialize transactions which target the same hot row range.
@@ -1022,7 +1053,7 @@ func (tsv *TabletServer) ExecuteBatch(ctx context.Context, target *querypb.Targe
 		results = append(results, *localReply)
 	}
 	if asTransaction {
-		if err = tsv.Commit(ctx, target, transactionID); err != nil {
+		if _, err = tsv.Commit(ctx, target, transactionID, false /* includeGTIDExecuted */); err != nil {
 			transactionID = 0
 			return nil, err
 		}
@@ -1033,6 +1064,10 @@ func (tsv *TabletServer) ExecuteBatch(ctx context.Context, target *querypb.Targe
 
 // BeginExecute combines Begin and Execute.
 func (tsv *TabletServer) BeginExecute(ctx context.Context, target *querypb.Target, sql string, bindVariables map[string]*querypb.BindVariable, options *querypb.ExecuteOptions) (*sqltypes.Result, int64, error) {
+	options, err := tsv.waitForGTIDSetRequest(ctx, target, options)
+	if err != nil {
+		return nil, 0, err
+	}
 	if tsv.enableHotRowProtection {
 		txDone, err := tsv.beginWaitForSameRangeTransactions(ctx, target, options, sql, bindVariables)
 		if err != nil {
@@ -1131,6 +1166,10 @@ func (tsv *TabletServer) computeTxSerializerKey(ctx context.Context, logStats *t
 func (tsv *TabletServer) BeginExecuteBatch(ctx context.Context, target *querypb.Target, queries []*querypb.BoundQuery, asTransaction bool, options *querypb.ExecuteOptions) ([]sqltypes.Result, int64, error) {
 	// TODO(mberlin): Integrate hot row protection here as we did for BeginExecute()
 	// and ExecuteBatch(asTransaction=true).
+	options, err := tsv.waitForGTIDSetRequest(ctx, target, options)
+	if err != nil {
+		return nil, 0, err
+	}
 	transactionID, err := tsv.Begin(ctx, target, options)
 	if err != nil {
 		return nil, 0, err
@@ -1196,14 +1235,81 @@ func (tsv *TabletServer) PurgeMessages(ctx context.Context, target *querypb.Targ
 	})
 }
 
+// FailMessages fails the list of messages that reached the max epoch of a
+// given message table. They are moved to the dead letter table if there is one.
+// It returns the number of messages successfully failed.
+func (tsv *TabletServer) FailMessages(ctx context.Context, target *querypb.Target, name string, ids []string) (count int64, err error) {
+	return tsv.execDMLs(ctx, target, func() ([]string, map[string]*querypb.BindVariable, error) {
+		return tsv.messager.GenerateFailQueries(name, ids)
+	})
+}
+
+// RequeueMessages moves the dead letters of a given message table that were
+// marked for requeuing back to the message table. It requeues at most 500
+// messages. It returns the number of messages successfully requeued.
+func (tsv *TabletServer) RequeueMessages(ctx context.Context, target *querypb.Target, name string) (count int64, err error) {
+	if err = tsv.startRequest(ctx, target, true /* isBegin */, false /* allowOnShutdown */); err != nil {
+		return 0, err
+	}
+	defer tsv.endRequest(true)
+	defer tsv.handlePanicAndSendLogStats("requeue", nil, &err, nil)
+
+	readQuery, err := tsv.messager.GenerateReadDeadLettersQuery(name)
+	if err != nil {
+		return 0, err
+	}
+	transactionID, err := tsv.Begin(ctx, target, nil)
+	if err != nil {
+		return 0, err
+	}
+	// If transaction was not committed by the end, it means
+	// that there was an error or nothing to requeue, roll it back.
+	defer func() {
+		if transactionID != 0 {
+			tsv.Rollback(ctx, target, transactionID)
+		}
+	}()
+	qr, err := tsv.Execute(ctx, target, readQuery, nil, transactionID, nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(qr.Rows) == 0 {
+		return 0, nil
+	}
+	queries, bv, err := tsv.messager.GenerateRequeueQueries(name, qr.Rows)
+	if err != nil {
+		return 0, err
+	}
+	for _, query := range queries {
+		if _, err := tsv.Execute(ctx, target, query, bv, transactionID, nil); err != nil {
+			return 0, err
+		}
+	}
+	if _, err = tsv.Commit(ctx, target, transactionID, false /* includeGTIDExecuted */); err != nil {
+		transactionID = 0
+		return 0, err
+	}
+	transactionID = 0
+	return int64(len(qr.Rows)), nil
+}
+
 func (tsv *TabletServer) execDML(ctx context.Context, target *querypb.Target, queryGenerator func() (string, map[string]*querypb.BindVariable, error)) (count int64, err error) {
+	return tsv.execDMLs(ctx, target, func() ([]string, map[string]*querypb.BindVariable, error) {
+		query, bv, err := queryGenerator()
+		return []string{query}, bv, err
+	})
+}
+
+// execDMLs executes the queries in the same transaction, and returns
+// the number of rows affected by the last one.
+func (tsv *TabletServer) execDMLs(ctx context.Context, target *querypb.Target, queryGenerator func() ([]string, map[string]*querypb.BindVariable, error)) (count int64, err error) {
 	if err = tsv.startRequest(ctx, target, true /* isBegin */, false /* allowOnShutdown */); err != nil {
 		return 0, err
 	}
 	defer tsv.endRequest(true)
 	defer tsv.handlePanicAndSendLogStats("ack", nil, &err, nil)
 
-	query, bv, err := queryGenerator()
+	queries, bv, err := queryGenerator()
 	if err != nil {
 		return 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", err)
 	}
@@ -1219,11 +1325,14 @@ func (tsv *TabletServer) execDML(ctx context.Context, target *querypb.Target, qu
 			tsv.Rollback(ctx, target, transactionID)
 		}
 	}()
-	qr, err := tsv.Execute(ctx, target, query, bv, transactionID, nil)
-	if err != nil {
-		return 0, err
+	var qr *sqltypes.Result
+	for _, query := range queries {
+		qr, err = tsv.Execute(ctx, target, query, bv, transactionID, nil)
+		if err != nil {
+			return 0, err
+		}
 	}
-	if err = tsv.Commit(ctx, target, transactionID); err != nil {
+	if _, err = tsv.Commit(ctx, target, transactionID, false /* includeGTIDExecuted */); err != nil {
 		transactionID = 0
 		return 0, err
 	}
@@ -1303,6 +1412,15 @@ func (tsv *TabletServer) execRequest(
 	target *querypb.Target, options *querypb.ExecuteOptions, isBegin, allowOnShutdown bool,
 	exec func(ctx context.Context, logStats *tabletenv.LogStats) error,
 ) (err error) {
+	span := trace.NewSpanFromContext(ctx)
+	span.StartLocal("TabletServer." + requestName)
+	if target != nil {
+		span.Annotate("keyspace", target.Keyspace)
+		span.Annotate("shard", target.Shard)
+	}
+	defer span.Finish()
+	ctx = trace.NewContext(ctx, span)
+
 	logStats := tabletenv.NewLogStats(ctx, requestName)
 	logStats.Target = target
 	logStats.OriginalSQL = sql
@@ -1811,6 +1929,68 @@ func (tsv *TabletServer) UpdateStream(ctx context.Context, target *querypb.Targe
 	}
 }
 
+// StreamChanges is part of the queryservice.QueryService interface.
+// It streams the changes made to the database, with the row images if
+// the binlogs have them, starting at position. If position is empty,
+// the current position is sent first, in an event without changes, and
+// the stream starts there.
+func (tsv *TabletServer) StreamChanges(ctx context.Context, target *querypb.Target, position string, callback func(*querypb.ChangeEvent) error) error {
+	var p mysql.Position
+	if position != "" {
+		var err error
+		p, err = mysql.DecodePosition(position)
+		if err != nil {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot parse position: %v", err)
+		}
+	}
+
+	// Validate proper target is used.
+	if err := tsv.startRequest(ctx, target, false /* isBegin */, false /* allowOnShutdown */); err != nil {
+		return err
+	}
+	defer tsv.endRequest(false)
+
+	cp := tsv.dbconfigs.Dba
+	cp.DbName = tsv.dbconfigs.App.DbName
+	if p.IsZero() {
+		var err error
+		p, err = currentPosition(ctx, &cp)
+		if err != nil {
+			return vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "cannot get the current position: %v", err)
+		}
+		if err := callback(&querypb.ChangeEvent{Position: mysql.EncodePosition(p)}); err != nil {
+			return err
+		}
+	}
+	s := binlog.NewChangeStreamer(&cp, tsv.se, p, callback)
+
+	// Create a cancelable wrapping context, so the stream is
+	// terminated like the update streams.
+	streamCtx, streamCancel := context.WithCancel(ctx)
+	i := tsv.updateStreamList.Add(streamCancel)
+	defer tsv.updateStreamList.Delete(i)
+
+	err := s.Stream(streamCtx)
+	switch err {
+	case binlog.ErrBinlogUnavailable:
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "%v", err)
+	case nil, io.EOF:
+		return nil
+	default:
+		return vterrors.Errorf(vtrpcpb.Code_UNKNOWN, "%v", err)
+	}
+}
+
+// currentPosition returns the current replication position of mysqld.
+func currentPosition(ctx context.Context, cp *mysql.ConnParams) (mysql.Position, error) {
+	conn, err := mysql.Connect(ctx, cp)
+	if err != nil {
+		return mysql.Position{}, err
+	}
+	defer conn.Close()
+	return conn.MasterPosition()
+}
+
 // HandlePanic is part of the queryservice.QueryService interface
 func (tsv *TabletServer) HandlePanic(err *error) {
 	if x := recover(); x != nil {
@@ -577,7 +577,7 @@ func TestTabletServerTarget(t *testing.T) {
 	if err == nil || !strings.Contains(err.Error(), want) {
 		t.Errorf("err: %v, must contain %s", err, want)
 	}
-	err = tsv.Commit(ctx, &target1, 1)
+	_, err = tsv.Commit(ctx, &target1, 1, false)
 	want = "invalid tablet type: MASTER"
 	if err == nil || !strings.Contains(err.Error(), want) {
 		t.Errorf("err: %v, must contain %s", err, want)
@@ -796,14 +796,14 @@ func TestTabletServerStartCommit(t *testing.T) {
 	commitTransition := fmt.Sprintf("update `_vt`.dt_state set state = %d where dtid = 'aa' and state = %d", int(querypb.TransactionState_COMMIT), int(querypb.TransactionState_PREPARE))
 	db.AddQuery(commitTransition, &sqltypes.Result{RowsAffected: 1})
 	txid := newTxForPrep(tsv)
-	err := tsv.StartCommit(ctx, &target, txid, "aa")
+	_, err := tsv.StartCommit(ctx, &target, txid, "aa", false)
 	if err != nil {
 		t.Error(err)
 	}
 
 	db.AddQuery(commitTransition, &sqltypes.Result{})
 	txid = newTxForPrep(tsv)
-	err = tsv.StartCommit(ctx, &target, txid, "aa")
+	_, err = tsv.StartCommit(ctx, &target, txid, "aa", false)
 	want := "could not transition to COMMIT: aa"
 	if err == nil || err.Error() != want {
 		t.Errorf("Prepare err: %v, want %s", err, want)
@@ -1016,11 +1016,226 @@ func TestTabletServerCommitTransaction(t *testing.T) {
 	if _, err := tsv.Execute(ctx, &target, executeSQL, nil, transactionID, nil); err != nil {
 		t.Fatalf("failed to execute query: %s: %s", executeSQL, err)
 	}
-	if err := tsv.Commit(ctx, &target, transactionID); err != nil {
+	if _, err := tsv.Commit(ctx, &target, transactionID, false); err != nil {
 		t.Fatalf("call TabletServer.Commit failed: %v", err)
 	}
 }
 
+func TestTabletServerCommitGTIDExecuted(t *testing.T) {
+	db := setUpTabletServerTest(t)
+	defer db.Close()
+	testUtils := newTestUtils()
+	db.AddQuery("select @@global.gtid_executed", &sqltypes.Result{
+		Fields:       []*querypb.Field{{Type: sqltypes.VarChar}},
+		RowsAffected: 1,
+		Rows: [][]sqltypes.Value{
+			{sqltypes.NewVarChar("8bc65c84-3fe4-11e6-a87a-0242ac110003:1-10")},
+		},
+	})
+	config := testUtils.newQueryServiceConfig()
+	tsv := NewTabletServerWithNilTopoServer(config)
+	dbcfgs := testUtils.newDBConfigs(db)
+	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
+	err := tsv.StartService(target, dbcfgs)
+	if err != nil {
+		t.Fatalf("StartService failed: %v", err)
+	}
+	defer tsv.StopService()
+	ctx := context.Background()
+	transactionID, err := tsv.Begin(ctx, &target, nil)
+	if err != nil {
+		t.Fatalf("call TabletServer.Begin failed: %v", err)
+	}
+	gtidExecuted, err := tsv.Commit(ctx, &target, transactionID, true /* includeGTIDExecuted */)
+	if err != nil {
+		t.Fatalf("call TabletServer.Commit failed: %v", err)
+	}
+	want := "MySQL56/8bc65c84-3fe4-11e6-a87a-0242ac110003:1-10"
+	if gtidExecuted != want {
+		t.Errorf("gtid_executed: %v, want %v", gtidExecuted, want)
+	}
+
+	// Without includeGTIDExecuted, the position is not returned.
+	transactionID, err = tsv.Begin(ctx, &target, nil)
+	if err != nil {
+		t.Fatalf("call TabletServer.Begin failed: %v", err)
+	}
+	gtidExecuted, err = tsv.Commit(ctx, &target, transactionID, false /* includeGTIDExecuted */)
+	if err != nil {
+		t.Fatalf("call TabletServer.Commit failed: %v", err)
+	}
+	if gtidExecuted != "" {
+		t.Errorf("gtid_executed: %v, want empty", gtidExecuted)
+	}
+}
+
+func TestTabletServerExecuteWaitForGTIDSet(t *testing.T) {
+	db := setUpTabletServerTest(t)
+	defer db.Close()
+	testUtils := newTestUtils()
+	executeSQL := "select * from test_table limit 1000"
+	db.AddQuery(executeSQL, &sqltypes.Result{})
+	// The timeout is rounded up to a whole second.
+	db.AddQuery("SELECT WAIT_UNTIL_SQL_THREAD_AFTER_GTIDS('8bc65c84-3fe4-11e6-a87a-0242ac110003:1-10', 1)", &sqltypes.Result{
+		Fields:       []*querypb.Field{{Type: sqltypes.Int64}},
+		RowsAffected: 1,
+		Rows:         [][]sqltypes.Value{{sqltypes.NewInt64(0)}},
+	})
+	db.AddQuery("SELECT WAIT_UNTIL_SQL_THREAD_AFTER_GTIDS('8bc65c84-3fe4-11e6-a87a-0242ac110003:1-20', 1)", &sqltypes.Result{
+		Fields:       []*querypb.Field{{Type: sqltypes.Int64}},
+		RowsAffected: 1,
+		Rows:         [][]sqltypes.Value{{sqltypes.NewInt64(-1)}},
+	})
+	db.AddQuery("SELECT WAIT_UNTIL_SQL_THREAD_AFTER_GTIDS('8bc65c84-3fe4-11e6-a87a-0242ac110003:1-30', 1)", &sqltypes.Result{
+		Fields:       []*querypb.Field{{Type: sqltypes.Int64}},
+		RowsAffected: 1,
+		Rows:         [][]sqltypes.Value{{sqltypes.NULL}},
+	})
+	config := testUtils.newQueryServiceConfig()
+	tsv := NewTabletServerWithNilTopoServer(config)
+	dbcfgs := testUtils.newDBConfigs(db)
+	target := querypb.Target{TabletType: topodatapb.TabletType_REPLICA}
+	err := tsv.StartService(target, dbcfgs)
+	if err != nil {
+		t.Fatalf("StartService failed: %v", err)
+	}
+	defer tsv.StopService()
+	ctx := context.Background()
+
+	options := &querypb.ExecuteOptions{
+		WaitForGtidSet:          "MySQL56/8bc65c84-3fe4-11e6-a87a-0242ac110003:1-10",
+		WaitForGtidSetTimeoutMs: 500,
+	}
+	if _, err := tsv.Execute(ctx, &target, executeSQL, nil, 0, options); err != nil {
+		t.Fatalf("failed to execute query: %s: %s", executeSQL, err)
+	}
+
+	options.WaitForGtidSet = "MySQL56/8bc65c84-3fe4-11e6-a87a-0242ac110003:1-20"
+	_, err = tsv.Execute(ctx, &target, executeSQL, nil, 0, options)
+	if code := vterrors.Code(err); code != vtrpcpb.Code_DEADLINE_EXCEEDED {
+		t.Errorf("Execute: %v, want DEADLINE_EXCEEDED", err)
+	}
+
+	options.WaitForGtidSet = "MySQL56/8bc65c84-3fe4-11e6-a87a-0242ac110003:1-30"
+	_, err = tsv.Execute(ctx, &target, executeSQL, nil, 0, options)
+	if code := vterrors.Code(err); code != vtrpcpb.Code_FAILED_PRECONDITION {
+		t.Errorf("Execute: %v, want FAILED_PRECONDITION", err)
+	}
+
+	options.WaitForGtidSet = "8bc65c84-3fe4-11e6-a87a-0242ac110003:1-10"
+	_, err = tsv.Execute(ctx, &target, executeSQL, nil, 0, options)
+	if code := vterrors.Code(err); code != vtrpcpb.Code_INVALID_ARGUMENT {
+		t.Errorf("Execute: %v, want INVALID_ARGUMENT", err)
+	}
+}
+
+func TestTabletServerWaitForGTIDSetPaths(t *testing.T) {
+	db := setUpTabletServerTest(t)
+	defer db.Close()
+	testUtils := newTestUtils()
+	executeSQL := "select * from test_table limit 1000"
+	db.AddQuery(executeSQL, &sqltypes.Result{})
+	waitSQL := "SELECT WAIT_UNTIL_SQL_THREAD_AFTER_GTIDS('8bc65c84-3fe4-11e6-a87a-0242ac110003:1-10', 1)"
+	db.AddQuery(waitSQL, &sqltypes.Result{
+		Fields:       []*querypb.Field{{Type: sqltypes.Int64}},
+		RowsAffected: 1,
+		Rows:         [][]sqltypes.Value{{sqltypes.NewInt64(0)}},
+	})
+	config := testUtils.newQueryServiceConfig()
+	tsv := NewTabletServerWithNilTopoServer(config)
+	dbcfgs := testUtils.newDBConfigs(db)
+	target := querypb.Target{TabletType: topodatapb.TabletType_REPLICA}
+	err := tsv.StartService(target, dbcfgs)
+	if err != nil {
+		t.Fatalf("StartService failed: %v", err)
+	}
+	defer tsv.StopService()
+	ctx := context.Background()
+
+	// The wait must not hold a connection of the query or transaction pools.
+	var inUse []int64
+	db.SetBeforeFunc(waitSQL, func() {
+		inUse = append(inUse, tsv.qe.conns.InUse(), tsv.qe.streamConns.InUse(), tsv.te.txPool.conns.InUse())
+	})
+	options := &querypb.ExecuteOptions{
+		WaitForGtidSet:          "MySQL56/8bc65c84-3fe4-11e6-a87a-0242ac110003:1-10",
+		WaitForGtidSetTimeoutMs: 500,
+	}
+	queries := []*querypb.BoundQuery{{Sql: executeSQL}, {Sql: executeSQL}}
+	// Transactions only run on masters, which wait all the same if
+	// they're asked to.
+	testcases := []struct {
+		name       string
+		tabletType topodatapb.TabletType
+		run        func() error
+	}{{
+		name:       "Execute",
+		tabletType: topodatapb.TabletType_REPLICA,
+		run: func() error {
+			_, err := tsv.Execute(ctx, &target, executeSQL, nil, 0, options)
+			return err
+		},
+	}, {
+		name:       "StreamExecute",
+		tabletType: topodatapb.TabletType_REPLICA,
+		run: func() error {
+			return tsv.StreamExecute(ctx, &target, executeSQL, nil, options, func(*sqltypes.Result) error { return nil })
+		},
+	}, {
+		name:       "ExecuteBatch",
+		tabletType: topodatapb.TabletType_REPLICA,
+		run: func() error {
+			_, err := tsv.ExecuteBatch(ctx, &target, queries, false /* asTransaction */, 0, options)
+			return err
+		},
+	}, {
+		name:       "ExecuteBatch as transaction",
+		tabletType: topodatapb.TabletType_MASTER,
+		run: func() error {
+			_, err := tsv.ExecuteBatch(ctx, &target, queries, true /* asTransaction */, 0, options)
+			return err
+		},
+	}, {
+		name:       "BeginExecute",
+		tabletType: topodatapb.TabletType_MASTER,
+		run: func() error {
+			_, transactionID, err := tsv.BeginExecute(ctx, &target, executeSQL, nil, options)
+			if err != nil {
+				return err
+			}
+			return tsv.Rollback(ctx, &target, transactionID)
+		},
+	}, {
+		name:       "BeginExecuteBatch",
+		tabletType: topodatapb.TabletType_MASTER,
+		run: func() error {
+			_, transactionID, err := tsv.BeginExecuteBatch(ctx, &target, queries, false /* asTransaction */, options)
+			if err != nil {
+				return err
+			}
+			return tsv.Rollback(ctx, &target, transactionID)
+		},
+	}}
+	for _, tcase := range testcases {
+		if _, err := tsv.SetServingType(tcase.tabletType, true, nil); err != nil {
+			t.Fatalf("SetServingType failed: %v", err)
+		}
+		target.TabletType = tcase.tabletType
+		inUse = nil
+		before := db.GetQueryCalledNum(waitSQL)
+		if err := tcase.run(); err != nil {
+			t.Errorf("%v: %v", tcase.name, err)
+			continue
+		}
+		if got := db.GetQueryCalledNum(waitSQL) - before; got != 1 {
+			t.Errorf("%v: waited %v times, want 1", tcase.name, got)
+		}
+		if want := []int64{0, 0, 0}; !reflect.DeepEqual(inUse, want) {
+			t.Errorf("%v: pool connections in use during the wait: %v, want %v", tcase.name, inUse, want)
+		}
+	}
+}
+
 func TestTabletServerCommiRollbacktFail(t *testing.T) {
 	db := setUpTabletServerTest(t)
 	defer db.Close()
@@ -1035,7 +1250,7 @@ func TestTabletServerCommiRollbacktFail(t *testing.T) {
 	}
 	defer tsv.StopService()
 	ctx := context.Background()
-	err = tsv.Commit(ctx, &target, -1)
+	_, err = tsv.Commit(ctx, &target, -1, false)
 	want := "transaction -1: not found"
 	if err == nil || err.Error() != want {
 		t.Fatalf("Commit err: %v, want %v", err, want)
@@ -1122,7 +1337,7 @@ func TestTabletServerCommitPrepared(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer tsv.RollbackPrepared(ctx, &target, "aa", 0)
-	if err := tsv.CommitPrepared(ctx, &target, "aa"); err != nil {
+	if _, err := tsv.CommitPrepared(ctx, &target, "aa", false); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -1534,7 +1749,7 @@ func TestSerializeTransactionsSameRow(t *testing.T) {
 		if err != nil {
 			t.Fatalf("failed to execute query: %s: %s", q1, err)
 		}
-		if err := tsv.Commit(ctx, &target, tx1); err != nil {
+		if _, err := tsv.Commit(ctx, &target, tx1, false); err != nil {
 			t.Fatalf("call TabletServer.Commit failed: %v", err)
 		}
 	}()
@@ -1554,7 +1769,7 @@ func TestSerializeTransactionsSameRow(t *testing.T) {
 		// open a second connection while the request of the first connection is
 		// still pending.
 		<-tx3Finished
-		if err := tsv.Commit(ctx, &target, tx2); err != nil {
+		if _, err := tsv.Commit(ctx, &target, tx2, false); err != nil {
 			t.Fatalf("call TabletServer.Commit failed: %v", err)
 		}
 	}()
@@ -1569,7 +1784,7 @@ func TestSerializeTransactionsSameRow(t *testing.T) {
 		if err != nil {
 			t.Fatalf("failed to execute query: %s: %s", q3, err)
 		}
-		if err := tsv.Commit(ctx, &target, tx3); err != nil {
+		if _, err := tsv.Commit(ctx, &target, tx3, false); err != nil {
 			t.Fatalf("call TabletServer.Commit failed: %v", err)
 		}
 		close(tx3Finished)
@@ -1775,7 +1990,7 @@ func TestSerializeTransactionsSameRow_ConcurrentTransactions(t *testing.T) {
 			t.Fatalf("failed to execute query: %s: %s", q1, err)
 		}
 
-		if err := tsv.Commit(ctx, &target, tx1); err != nil {
+		if _, err := tsv.Commit(ctx, &target, tx1, false); err != nil {
 			t.Fatalf("call TabletServer.Commit failed: %v", err)
 		}
 	}()
@@ -1794,7 +2009,7 @@ func TestSerializeTransactionsSameRow_ConcurrentTransactions(t *testing.T) {
 			t.Fatalf("failed to execute query: %s: %s", q2, err)
 		}
 
-		if err := tsv.Commit(ctx, &target, tx2); err != nil {
+		if _, err := tsv.Commit(ctx, &target, tx2, false); err != nil {
 			t.Fatalf("call TabletServer.Commit failed: %v", err)
 		}
 	}()
@@ -1813,7 +2028,7 @@ func TestSerializeTransactionsSameRow_ConcurrentTransactions(t *testing.T) {
 			t.Fatalf("failed to execute query: %s: %s", q3, err)
 		}
 
-		if err := tsv.Commit(ctx, &target, tx3); err != nil {
+		if _, err := tsv.Commit(ctx, &target, tx3, false); err != nil {
 			t.Fatalf("call TabletServer.Commit failed: %v", err)
 		}
 	}()
@@ -1913,7 +2128,7 @@ func TestSerializeTransactionsSameRow_TooManyPendingRequests(t *testing.T) {
 		if err != nil {
 			t.Fatalf("failed to execute query: %s: %s", q1, err)
 		}
-		if err := tsv.Commit(ctx, &target, tx1); err != nil {
+		if _, err := tsv.Commit(ctx, &target, tx1, false); err != nil {
 			t.Fatalf("call TabletServer.Commit failed: %v", err)
 		}
 	}()
@@ -2101,7 +2316,7 @@ func TestSerializeTransactionsSameRow_RequestCanceled(t *testing.T) {
 			t.Fatalf("failed to execute query: %s: %s", q1, err)
 		}
 
-		if err := tsv.Commit(ctx, &target, tx1); err != nil {
+		if _, err := tsv.Commit(ctx, &target, tx1, false); err != nil {
 			t.Fatalf("call TabletServer.Commit failed: %v", err)
 		}
 	}()
@@ -2138,7 +2353,7 @@ func TestSerializeTransactionsSameRow_RequestCanceled(t *testing.T) {
 			t.Fatalf("failed to execute query: %s: %s", q3, err)
 		}
 
-		if err := tsv.Commit(ctx, &target, tx3); err != nil {
+		if _, err := tsv.Commit(ctx, &target, tx3, false); err != nil {
 			t.Fatalf("call TabletServer.Commit failed: %v", err)
 		}
 	}()
@@ -2188,6 +2403,32 @@ func TestMessageStream(t *testing.T) {
 	}
 }
 
+func TestStreamChangesErrors(t *testing.T) {
+	tsv := NewTabletServerWithNilTopoServer(newTestUtils().newQueryServiceConfig())
+	ctx := context.Background()
+	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
+	testcases := []struct {
+		position string
+		err      string
+	}{{
+		position: "invalid",
+		err:      "cannot parse position",
+	}, {
+		// The tablet is not serving.
+		position: "",
+		err:      "operation not allowed in state NOT_SERVING",
+	}}
+	for _, tcase := range testcases {
+		err := tsv.StreamChanges(ctx, &target, tcase.position, func(*querypb.ChangeEvent) error {
+			t.Errorf("unexpected event")
+			return nil
+		})
+		if err == nil || !strings.Contains(err.Error(), tcase.err) {
+			t.Errorf("StreamChanges(%q): %v, want %v", tcase.position, err, tcase.err)
+		}
+	}
+}
+
 func TestMessageAck(t *testing.T) {
 	_, tsv, db := newTestTxExecutor(t)
 	defer db.Close()
@@ -2324,6 +2565,36 @@ func TestPurgeMessages(t *testing.T) {
 	}
 }
 
+func TestFailAndRequeueMessages(t *testing.T) {
+	_, tsv, db := newTestTxExecutor(t)
+	defer db.Close()
+	defer tsv.StopService()
+	ctx := context.Background()
+	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
+
+	_, err := tsv.FailMessages(ctx, &target, "nonmsg", []string{"1"})
+	want := "message table nonmsg not found in schema"
+	if err == nil || err.Error() != want {
+		t.Errorf("tsv.FailMessages(invalid): %v, want %s", err, want)
+	}
+	_, err = tsv.FailMessages(ctx, &target, "msg", []string{"1"})
+	want = "message table msg has no max epoch"
+	if err == nil || err.Error() != want {
+		t.Errorf("tsv.FailMessages(msg): %v, want %s", err, want)
+	}
+
+	_, err = tsv.RequeueMessages(ctx, &target, "nonmsg")
+	want = "message table nonmsg not found in schema"
+	if err == nil || err.Error() != want {
+		t.Errorf("tsv.RequeueMessages(invalid): %v, want %s", err, want)
+	}
+	_, err = tsv.RequeueMessages(ctx, &target, "msg")
+	want = "message table msg has no dead letter table"
+	if err == nil || err.Error() != want {
+		t.Errorf("tsv.RequeueMessages(msg): %v, want %s", err, want)
+	}
+}
+
 func TestTabletServerSplitQuery(t *testing.T) {
 	db := setUpTabletServerTest(t)
 	defer db.Close()
@@ -27,6 +27,7 @@ import (
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/throttler"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 
 	throttlerdatapb "vitess.io/vitess/go/vt/proto/throttlerdata"
@@ -327,5 +328,9 @@ func (ts *txThrottlerState) StatsUpdate(tabletStats *discovery.TabletStats) {
 	if tabletStats.Target.TabletType != topodatapb.TabletType_REPLICA {
 		return
 	}
+	// Delayed replicas are behind on purpose.
+	if topoproto.IsDelayedReplica(tabletStats.Tablet) {
+		return
+	}
 	ts.throttler.RecordReplicationLag(time.Now(), tabletStats)
 }
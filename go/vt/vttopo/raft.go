@@ -0,0 +1,597 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttopo
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/coreos/etcd/raft"
+	"github.com/coreos/etcd/raft/raftpb"
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/log"
+)
+
+// The consensus algorithm is the raft implementation of etcd
+// (github.com/coreos/etcd/raft). It only implements the algorithm:
+// this file runs it, persists its log with raftLog, sends its messages
+// with grpcTransport, and applies the committed entries to the state
+// machine.
+//
+// Cluster membership is static: the raft id of a server is its
+// position in the sorted list of all the server ids, starting at 1.
+
+var (
+	// errNotLeader is returned when a command is sent to a server
+	// that is not the leader.
+	errNotLeader = errors.New("not the raft leader")
+
+	// errLeadershipLost is returned when the leader stepped down
+	// before a command was committed. It may still be committed
+	// by the next leader.
+	errLeadershipLost = errors.New("raft leadership lost while committing, the change may or may not be applied")
+
+	// errStopped is returned after the server was stopped.
+	errStopped = errors.New("raft server stopped")
+)
+
+// stateMachine is what the log is applied to.
+type stateMachine interface {
+	// apply applies a command at an index. It returns the result
+	// for the proposer.
+	apply(index uint64, command []byte) interface{}
+	// snapshot returns the current state.
+	snapshot() ([]byte, error)
+	// restore replaces the current state.
+	restore(data []byte) error
+}
+
+// raftConfig is the configuration of a raft server.
+type raftConfig struct {
+	// id is the id of this server.
+	id string
+	// peers maps the ids of all the servers, including this one,
+	// to their address.
+	peers map[string]string
+	// heartbeatInterval is the raft tick: how often the leader
+	// sends heartbeats.
+	heartbeatInterval time.Duration
+	// electionTimeout is the minimum time without hearing from
+	// a leader before starting an election. The actual timeout is
+	// random, up to twice that.
+	electionTimeout time.Duration
+	// snapshotThreshold is the number of entries after which
+	// the log is compacted.
+	snapshotThreshold int
+}
+
+// raftNode is a raft server.
+type raftNode struct {
+	config    raftConfig
+	names     map[uint64]string
+	log       *raftLog
+	transport *grpcTransport
+	fsm       stateMachine
+	onLeader  func()
+	node      raft.Node
+
+	// The following fields are only used by the run goroutine.
+	// confState is the cluster membership, saved in the snapshots.
+	confState     raftpb.ConfState
+	snapshotIndex uint64
+
+	// mu protects all the following fields.
+	mu       sync.Mutex
+	leaderID uint64
+	leading  bool
+	term     uint64
+	applied  uint64
+	// appliedTerm is the term of the last applied entry. Raft
+	// ignores the read requests until the leader applied an entry
+	// of its term.
+	appliedTerm uint64
+	// appliedChanged is closed and replaced when applied changes.
+	appliedChanged chan struct{}
+	// nextID is the id of the next proposal or read. It starts
+	// from the current time, so it is not reused after a restart.
+	nextID    uint64
+	proposals map[uint64]chan interface{}
+	reads     map[uint64]chan uint64
+	stopped   bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newRaftNode starts a raft server, with the state stored in raftLog.
+// onLeader is called when this server becomes the leader.
+func newRaftNode(config raftConfig, raftLog *raftLog, fsm stateMachine, onLeader func()) (*raftNode, error) {
+	electionTick := int(config.electionTimeout / config.heartbeatInterval)
+	if electionTick < 2 {
+		return nil, fmt.Errorf("the election timeout (%v) must be at least twice the heartbeat interval (%v)", config.electionTimeout, config.heartbeatInterval)
+	}
+
+	var servers []string
+	for id := range config.peers {
+		servers = append(servers, id)
+	}
+	sort.Strings(servers)
+	r := &raftNode{
+		config:         config,
+		names:          make(map[uint64]string),
+		log:            raftLog,
+		fsm:            fsm,
+		onLeader:       onLeader,
+		appliedChanged: make(chan struct{}),
+		nextID:         uint64(time.Now().UnixNano()),
+		proposals:      make(map[uint64]chan interface{}),
+		reads:          make(map[uint64]chan uint64),
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+	var raftID uint64
+	var peers []raft.Peer
+	addrs := make(map[uint64]string)
+	for i, id := range servers {
+		rid := uint64(i + 1)
+		r.names[rid] = id
+		peers = append(peers, raft.Peer{ID: rid})
+		if id == config.id {
+			raftID = rid
+		} else {
+			addrs[rid] = config.peers[id]
+		}
+	}
+
+	snapshot, err := raftLog.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	if !raft.IsEmptySnap(snapshot) {
+		if err := fsm.restore(snapshot.Data); err != nil {
+			return nil, err
+		}
+		r.confState = snapshot.Metadata.ConfState
+		r.snapshotIndex = snapshot.Metadata.Index
+		r.applied = snapshot.Metadata.Index
+	}
+	state, _, err := raftLog.InitialState()
+	if err != nil {
+		return nil, err
+	}
+	r.term = state.Term
+
+	c := &raft.Config{
+		ID:              raftID,
+		ElectionTick:    electionTick,
+		HeartbeatTick:   1,
+		Storage:         raftLog.MemoryStorage,
+		Applied:         r.applied,
+		MaxSizePerMsg:   1024 * 1024,
+		MaxInflightMsgs: 256,
+		// The leader steps down when it can't reach the
+		// majority, and servers that were partitioned don't
+		// disrupt the cluster when they come back.
+		CheckQuorum: true,
+		PreVote:     true,
+		Logger:      raftLogger{},
+	}
+	if raftLog.isEmpty() {
+		r.node = raft.StartNode(c, peers)
+	} else {
+		r.node = raft.RestartNode(c)
+	}
+	r.transport = newGRPCTransport(addrs, config.electionTimeout, r.reportSent)
+	go r.run()
+	return r, nil
+}
+
+// close stops the server.
+func (r *raftNode) close() {
+	r.mu.Lock()
+	r.stopped = true
+	r.mu.Unlock()
+	close(r.stop)
+	<-r.done
+	r.transport.close()
+	if err := r.log.close(); err != nil {
+		log.Warningf("raft: cannot close the log: %v", err)
+	}
+}
+
+// run ticks the raft node, and processes what it returns.
+func (r *raftNode) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.config.heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.node.Tick()
+		case rd := <-r.node.Ready():
+			r.processReady(rd)
+			r.node.Advance()
+		case <-r.stop:
+			r.node.Stop()
+			return
+		}
+	}
+}
+
+// processReady persists, sends and applies a Ready.
+func (r *raftNode) processReady(rd raft.Ready) {
+	if rd.SoftState != nil {
+		r.setSoftState(rd.SoftState)
+	}
+	// Raft can't go on with a state that may not be saved.
+	if err := r.log.save(rd.HardState, rd.Entries, rd.Snapshot); err != nil {
+		log.Fatalf("raft: cannot save the log: %v", err)
+	}
+	if !raft.IsEmptyHardState(rd.HardState) {
+		r.mu.Lock()
+		r.term = rd.HardState.Term
+		r.mu.Unlock()
+	}
+	if !raft.IsEmptySnap(rd.Snapshot) {
+		if err := r.fsm.restore(rd.Snapshot.Data); err != nil {
+			log.Fatalf("raft: cannot restore snapshot %v: %v", rd.Snapshot.Metadata.Index, err)
+		}
+		r.confState = rd.Snapshot.Metadata.ConfState
+		r.snapshotIndex = rd.Snapshot.Metadata.Index
+		r.setApplied(rd.Snapshot.Metadata.Index, rd.Snapshot.Metadata.Term)
+	}
+	r.transport.send(rd.Messages)
+	r.readIndexes(rd.ReadStates)
+	r.applyEntries(rd.CommittedEntries)
+	r.maybeCompact()
+}
+
+// setSoftState records who the leader is.
+func (r *raftNode) setSoftState(ss *raft.SoftState) {
+	r.mu.Lock()
+	wasLeader := r.leading
+	r.leaderID = ss.Lead
+	r.leading = ss.RaftState == raft.StateLeader
+	isLeader := r.leading
+	if wasLeader && !isLeader {
+		log.Infof("raft: %v stepping down", r.config.id)
+		for id, c := range r.proposals {
+			c <- errLeadershipLost
+			delete(r.proposals, id)
+		}
+	}
+	r.mu.Unlock()
+
+	if !wasLeader && isLeader {
+		log.Infof("raft: %v is the leader", r.config.id)
+		if r.onLeader != nil {
+			r.onLeader()
+		}
+	}
+}
+
+// applyEntries applies committed entries to the state machine, and
+// returns the results to the proposers.
+func (r *raftNode) applyEntries(entries []raftpb.Entry) {
+	for _, e := range entries {
+		r.mu.Lock()
+		applied := r.applied
+		r.mu.Unlock()
+		if e.Index <= applied {
+			// Already in the snapshot we restored.
+			continue
+		}
+
+		switch e.Type {
+		case raftpb.EntryConfChange:
+			// These are the initial members of the cluster.
+			cc := raftpb.ConfChange{}
+			if err := cc.Unmarshal(e.Data); err != nil {
+				log.Fatalf("raft: cannot decode configuration change %v: %v", e.Index, err)
+			}
+			r.confState = *r.node.ApplyConfChange(cc)
+		case raftpb.EntryNormal:
+			// Each new leader adds an empty entry.
+			if len(e.Data) < 8 {
+				break
+			}
+			id := binary.BigEndian.Uint64(e.Data)
+			result := r.fsm.apply(e.Index, e.Data[8:])
+			r.mu.Lock()
+			if c, ok := r.proposals[id]; ok {
+				c <- result
+				delete(r.proposals, id)
+			}
+			r.mu.Unlock()
+		}
+		r.setApplied(e.Index, e.Term)
+	}
+}
+
+// setApplied records the last applied entry.
+func (r *raftNode) setApplied(index, term uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.applied = index
+	r.appliedTerm = term
+	close(r.appliedChanged)
+	r.appliedChanged = make(chan struct{})
+}
+
+// maybeCompact takes a snapshot if the log is long enough.
+func (r *raftNode) maybeCompact() {
+	r.mu.Lock()
+	applied := r.applied
+	r.mu.Unlock()
+	if applied-r.snapshotIndex < uint64(r.config.snapshotThreshold) {
+		return
+	}
+
+	data, err := r.fsm.snapshot()
+	if err != nil {
+		log.Errorf("raft: cannot take snapshot: %v", err)
+		return
+	}
+	if err := r.log.compact(applied, &r.confState, data); err != nil {
+		log.Errorf("raft: cannot compact the log: %v", err)
+		return
+	}
+	r.snapshotIndex = applied
+}
+
+// reportSent is called by the transport after sending a message.
+func (r *raftNode) reportSent(msg raftpb.Message, err error) {
+	if err != nil {
+		r.node.ReportUnreachable(msg.To)
+	}
+	if msg.Type == raftpb.MsgSnap {
+		status := raft.SnapshotFinish
+		if err != nil {
+			status = raft.SnapshotFailure
+		}
+		r.node.ReportSnapshot(msg.To, status)
+	}
+}
+
+// step handles a message from another server.
+func (r *raftNode) step(ctx context.Context, data []byte) error {
+	msg := raftpb.Message{}
+	if err := msg.Unmarshal(data); err != nil {
+		return fmt.Errorf("cannot decode raft message: %v", err)
+	}
+	return convertRaftError(r.node.Step(ctx, msg))
+}
+
+// convertRaftError converts the errors of the raft library.
+func convertRaftError(err error) error {
+	if err == raft.ErrStopped {
+		return errStopped
+	}
+	return err
+}
+
+// newID returns the id of a new proposal or read.
+// r.mu must be held.
+func (r *raftNode) newID() uint64 {
+	r.nextID++
+	return r.nextID
+}
+
+// propose appends a command to the log, and waits until it is
+// applied. It returns the result of the state machine.
+func (r *raftNode) propose(ctx context.Context, command []byte) (interface{}, error) {
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return nil, errStopped
+	}
+	if !r.leading {
+		r.mu.Unlock()
+		return nil, errNotLeader
+	}
+	id := r.newID()
+	result := make(chan interface{}, 1)
+	r.proposals[id] = result
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.proposals, id)
+		r.mu.Unlock()
+	}()
+
+	// The entry is the id of the proposal, and the command.
+	data := make([]byte, 8+len(command))
+	binary.BigEndian.PutUint64(data, id)
+	copy(data[8:], command)
+	if err := r.node.Propose(ctx, data); err != nil {
+		return nil, convertRaftError(err)
+	}
+
+	select {
+	case res := <-result:
+		if res == errLeadershipLost {
+			return nil, errLeadershipLost
+		}
+		return res, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-r.stop:
+		return nil, errStopped
+	}
+}
+
+// readIndexes returns the read indexes to the readers.
+func (r *raftNode) readIndexes(states []raft.ReadState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rs := range states {
+		if len(rs.RequestCtx) != 8 {
+			continue
+		}
+		id := binary.BigEndian.Uint64(rs.RequestCtx)
+		if c, ok := r.reads[id]; ok {
+			c <- rs.Index
+			delete(r.reads, id)
+		}
+	}
+}
+
+// waitForLeaderRead waits until the leader can serve a linearizable
+// read from its state machine: it confirms with the majority that it
+// is still the leader, and waits until it has applied everything that
+// was committed when the read started.
+func (r *raftNode) waitForLeaderRead(ctx context.Context) error {
+	// Raft ignores reads until the leader has applied an entry of
+	// its term, wait for that first.
+	if err := r.waitForApplied(ctx, func() bool { return r.appliedTerm == r.term }); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	id := r.newID()
+	result := make(chan uint64, 1)
+	r.reads[id] = result
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.reads, id)
+		r.mu.Unlock()
+	}()
+
+	rctx := make([]byte, 8)
+	binary.BigEndian.PutUint64(rctx, id)
+	if err := r.node.ReadIndex(ctx, rctx); err != nil {
+		return convertRaftError(err)
+	}
+	// Raft drops the request if we are not the leader any more.
+	timer := time.NewTimer(r.config.electionTimeout)
+	defer timer.Stop()
+	var index uint64
+	select {
+	case index = <-result:
+	case <-timer.C:
+		return errNotLeader
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-r.stop:
+		return errStopped
+	}
+	return r.waitForApplied(ctx, func() bool { return r.applied >= index })
+}
+
+// waitForApplied waits until the condition is true, while we are the
+// leader. The condition is evaluated with r.mu held.
+func (r *raftNode) waitForApplied(ctx context.Context, condition func() bool) error {
+	for {
+		r.mu.Lock()
+		if r.stopped {
+			r.mu.Unlock()
+			return errStopped
+		}
+		if !r.leading {
+			r.mu.Unlock()
+			return errNotLeader
+		}
+		if condition() {
+			r.mu.Unlock()
+			return nil
+		}
+		changed := r.appliedChanged
+		r.mu.Unlock()
+
+		// The leader may step down without an apply: we check
+		// again periodically.
+		select {
+		case <-changed:
+		case <-time.After(r.config.heartbeatInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-r.stop:
+			return errStopped
+		}
+	}
+}
+
+// isLeader returns true if we are the leader.
+func (r *raftNode) isLeader() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.leading
+}
+
+// leader returns the id of the current leader, if known.
+func (r *raftNode) leader() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.names[r.leaderID]
+}
+
+// raftLogger sends the logs of the raft library to the Vitess log.
+type raftLogger struct{}
+
+func (raftLogger) Debug(v ...interface{}) {
+	log.V(2).Info(v...)
+}
+
+func (raftLogger) Debugf(format string, v ...interface{}) {
+	log.V(2).Infof(format, v...)
+}
+
+func (raftLogger) Info(v ...interface{}) {
+	log.Info(v...)
+}
+
+func (raftLogger) Infof(format string, v ...interface{}) {
+	log.Infof(format, v...)
+}
+
+func (raftLogger) Warning(v ...interface{}) {
+	log.Warning(v...)
+}
+
+func (raftLogger) Warningf(format string, v ...interface{}) {
+	log.Warningf(format, v...)
+}
+
+func (raftLogger) Error(v ...interface{}) {
+	log.Error(v...)
+}
+
+func (raftLogger) Errorf(format string, v ...interface{}) {
+	log.Errorf(format, v...)
+}
+
+func (raftLogger) Fatal(v ...interface{}) {
+	log.Fatal(v...)
+}
+
+func (raftLogger) Fatalf(format string, v ...interface{}) {
+	log.Fatalf(format, v...)
+}
+
+func (raftLogger) Panic(v ...interface{}) {
+	panic(fmt.Sprint(v...))
+}
+
+func (raftLogger) Panicf(format string, v ...interface{}) {
+	panic(fmt.Sprintf(format, v...))
+}
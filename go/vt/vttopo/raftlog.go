@@ -0,0 +1,342 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttopo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+
+	"github.com/coreos/etcd/raft"
+	"github.com/coreos/etcd/raft/raftpb"
+)
+
+// Files in the data directory.
+const (
+	snapshotFile = "snapshot"
+	logFile      = "log"
+)
+
+// Types of the records of the log file.
+const (
+	entryRecord = 1
+	stateRecord = 2
+)
+
+// raftLog is the storage of the raft library: a raft.MemoryStorage,
+// persisted in a data directory.
+//   - snapshot has the last raftpb.Snapshot.
+//   - log has the records after the snapshot: raftpb.Entry and
+//     raftpb.HardState, each one prefixed by its type and length.
+//
+// Records are appended to the log file, and synced. When raft replaces
+// entries, the new ones are just appended: reading the file appends
+// them in order to the MemoryStorage, which replaces the old ones
+// again. The file is rewritten when the log is compacted.
+// It is only used by the raft goroutine.
+type raftLog struct {
+	*raft.MemoryStorage
+	dir  string
+	file *os.File
+}
+
+// openRaftLog reads the data directory, creating it if necessary.
+func openRaftLog(dir string) (*raftLog, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	l := &raftLog{
+		MemoryStorage: raft.NewMemoryStorage(),
+		dir:           dir,
+	}
+
+	data, err := ioutil.ReadFile(path.Join(dir, snapshotFile))
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, err
+	default:
+		snapshot := raftpb.Snapshot{}
+		if err := snapshot.Unmarshal(data); err != nil {
+			return nil, fmt.Errorf("cannot decode %v: %v", path.Join(dir, snapshotFile), err)
+		}
+		if err := l.ApplySnapshot(snapshot); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := os.OpenFile(path.Join(dir, logFile), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	valid, err := l.readRecords(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("corrupted log %v: %v", path.Join(dir, logFile), err)
+	}
+	// Remove any partial record at the end, and append after the rest.
+	if err := file.Truncate(valid); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(valid, 0); err != nil {
+		file.Close()
+		return nil, err
+	}
+	l.file = file
+	return l, nil
+}
+
+// readRecords loads the records of the log file. It returns the
+// length of the complete records: the last one may be partial if we
+// stopped while writing it, it was never acknowledged.
+func (l *raftLog) readRecords(file *os.File) (int64, error) {
+	r := bufio.NewReader(file)
+	var valid int64
+	for {
+		recordType, err := r.ReadByte()
+		if err != nil {
+			return valid, nil
+		}
+		length, err := binary.ReadUvarint(r)
+		if err != nil {
+			return valid, nil
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return valid, nil
+		}
+
+		switch recordType {
+		case entryRecord:
+			entry := raftpb.Entry{}
+			if err := entry.Unmarshal(data); err != nil {
+				return 0, err
+			}
+			first, _ := l.FirstIndex()
+			last, _ := l.LastIndex()
+			if entry.Index < first {
+				// Compacted, but the log wasn't rewritten.
+				break
+			}
+			if entry.Index > last+1 {
+				return 0, fmt.Errorf("entry %v after %v", entry.Index, last)
+			}
+			if err := l.Append([]raftpb.Entry{entry}); err != nil {
+				return 0, err
+			}
+		case stateRecord:
+			state := raftpb.HardState{}
+			if err := state.Unmarshal(data); err != nil {
+				return 0, err
+			}
+			if err := l.SetHardState(state); err != nil {
+				return 0, err
+			}
+		default:
+			return 0, fmt.Errorf("unknown record type %v", recordType)
+		}
+		valid += int64(1 + uvarintLen(length) + len(data))
+	}
+}
+
+// uvarintLen returns the encoded length of a uvarint.
+func uvarintLen(v uint64) int {
+	var buf [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(buf[:], v)
+}
+
+// isEmpty returns true if nothing was ever saved.
+func (l *raftLog) isEmpty() bool {
+	state, _, _ := l.InitialState()
+	snapshot, _ := l.Snapshot()
+	last, _ := l.LastIndex()
+	return raft.IsEmptyHardState(state) && raft.IsEmptySnap(snapshot) && last == 0
+}
+
+// close closes the log file.
+func (l *raftLog) close() error {
+	return l.file.Close()
+}
+
+// save persists what raft returned in a Ready, before its messages
+// are sent.
+func (l *raftLog) save(state raftpb.HardState, entries []raftpb.Entry, snapshot raftpb.Snapshot) error {
+	if !raft.IsEmptySnap(snapshot) {
+		if err := l.saveSnapshot(snapshot); err != nil {
+			return err
+		}
+		if err := l.ApplySnapshot(snapshot); err != nil {
+			return err
+		}
+		// The rewritten log must have the commit index of the
+		// snapshot.
+		if !raft.IsEmptyHardState(state) {
+			if err := l.SetHardState(state); err != nil {
+				return err
+			}
+		}
+		if err := l.rewrite(); err != nil {
+			return err
+		}
+	}
+	if len(entries) == 0 && raft.IsEmptyHardState(state) {
+		return nil
+	}
+
+	w := bufio.NewWriter(l.file)
+	for i := range entries {
+		if err := writeRecord(w, entryRecord, &entries[i]); err != nil {
+			return err
+		}
+	}
+	if !raft.IsEmptyHardState(state) {
+		if err := writeRecord(w, stateRecord, &state); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+	if err := l.Append(entries); err != nil {
+		return err
+	}
+	if !raft.IsEmptyHardState(state) {
+		return l.SetHardState(state)
+	}
+	return nil
+}
+
+// compact saves a snapshot of the state at index, and removes the
+// entries up to index.
+func (l *raftLog) compact(index uint64, confState *raftpb.ConfState, data []byte) error {
+	snapshot, err := l.CreateSnapshot(index, confState, data)
+	if err != nil {
+		return err
+	}
+	if err := l.saveSnapshot(snapshot); err != nil {
+		return err
+	}
+	if err := l.Compact(index); err != nil {
+		return err
+	}
+	return l.rewrite()
+}
+
+// saveSnapshot atomically replaces the snapshot file.
+func (l *raftLog) saveSnapshot(snapshot raftpb.Snapshot) error {
+	data, err := snapshot.Marshal()
+	if err != nil {
+		return err
+	}
+	name := path.Join(l.dir, snapshotFile)
+	tmp := name + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// rewrite writes the log file again with the current state and
+// entries.
+func (l *raftLog) rewrite() error {
+	state, _, err := l.InitialState()
+	if err != nil {
+		return err
+	}
+	first, _ := l.FirstIndex()
+	last, _ := l.LastIndex()
+	var entries []raftpb.Entry
+	if last >= first {
+		entries, err = l.Entries(first, last+1, math.MaxUint64)
+		if err != nil {
+			return err
+		}
+	}
+
+	name := path.Join(l.dir, logFile)
+	tmp, err := os.OpenFile(name+".tmp", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(tmp)
+	for i := range entries {
+		if err := writeRecord(w, entryRecord, &entries[i]); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if !raft.IsEmptyHardState(state) {
+		if err := writeRecord(w, stateRecord, &state); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := os.Rename(name+".tmp", name); err != nil {
+		tmp.Close()
+		return err
+	}
+	l.file.Close()
+	l.file = tmp
+	return nil
+}
+
+// record is a raftpb message.
+type record interface {
+	Marshal() ([]byte, error)
+}
+
+// writeRecord writes the type, length and data of a record.
+func writeRecord(w *bufio.Writer, recordType byte, r record) error {
+	data, err := r.Marshal()
+	if err != nil {
+		return err
+	}
+	var buf [binary.MaxVarintLen64]byte
+	w.WriteByte(recordType)
+	w.Write(buf[:binary.PutUvarint(buf[:], uint64(len(data)))])
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttopo
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/coreos/etcd/raft/raftpb"
+)
+
+func entries(term uint64, indexes ...uint64) []raftpb.Entry {
+	var result []raftpb.Entry
+	for _, index := range indexes {
+		result = append(result, raftpb.Entry{Term: term, Index: index, Data: []byte{byte(index)}})
+	}
+	return result
+}
+
+// checkLog checks the entries and the hard state of a log.
+func checkLog(t *testing.T, l *raftLog, first, last, commit uint64) {
+	t.Helper()
+	if got, _ := l.FirstIndex(); got != first {
+		t.Errorf("FirstIndex() = %v, want %v", got, first)
+	}
+	if got, _ := l.LastIndex(); got != last {
+		t.Errorf("LastIndex() = %v, want %v", got, last)
+	}
+	if state, _, _ := l.InitialState(); state.Commit != commit {
+		t.Errorf("commit = %v, want %v", state.Commit, commit)
+	}
+}
+
+func TestRaftLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vttopo")
+	if err != nil {
+		t.Fatalf("cannot create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	l, err := openRaftLog(dir)
+	if err != nil {
+		t.Fatalf("openRaftLog failed: %v", err)
+	}
+	if !l.isEmpty() {
+		t.Errorf("new log is not empty")
+	}
+	if err := l.save(raftpb.HardState{Term: 1, Commit: 2}, entries(1, 1, 2, 3), raftpb.Snapshot{}); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	// A new leader replaces the uncommitted entry 3.
+	if err := l.save(raftpb.HardState{Term: 2, Commit: 4}, entries(2, 3, 4), raftpb.Snapshot{}); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	l.close()
+
+	// Add a partial record, as if we stopped while writing it.
+	f, err := os.OpenFile(path.Join(dir, logFile), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatalf("cannot open log file: %v", err)
+	}
+	f.Write([]byte{entryRecord, 100, 1, 2})
+	f.Close()
+
+	l, err = openRaftLog(dir)
+	if err != nil {
+		t.Fatalf("openRaftLog failed: %v", err)
+	}
+	if l.isEmpty() {
+		t.Errorf("reopened log is empty")
+	}
+	checkLog(t, l, 1, 4, 4)
+	if term, _ := l.Term(3); term != 2 {
+		t.Errorf("Term(3) = %v, want 2", term)
+	}
+
+	// Compact, and append after the snapshot.
+	if err := l.compact(3, &raftpb.ConfState{Nodes: []uint64{1}}, []byte("state")); err != nil {
+		t.Fatalf("compact failed: %v", err)
+	}
+	if err := l.save(raftpb.HardState{Term: 2, Commit: 5}, entries(2, 5), raftpb.Snapshot{}); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	l.close()
+
+	l, err = openRaftLog(dir)
+	if err != nil {
+		t.Fatalf("openRaftLog failed: %v", err)
+	}
+	defer l.close()
+	checkLog(t, l, 4, 5, 5)
+	snapshot, _ := l.Snapshot()
+	if snapshot.Metadata.Index != 3 || string(snapshot.Data) != "state" {
+		t.Errorf("Snapshot() = %v, want the state at 3", snapshot)
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttopo
+
+import (
+	"flag"
+
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+)
+
+// There are two gRPC services on the same port, defined in
+// vttoposervice.proto: Topo, used by the topology clients, and Raft,
+// used between the vttopo servers.
+
+var (
+	cert = flag.String("vttopo_grpc_cert", "", "the cert to use to connect to vttopo servers")
+	key  = flag.String("vttopo_grpc_key", "", "the key to use to connect to vttopo servers")
+	ca   = flag.String("vttopo_grpc_ca", "", "the server ca to use to validate vttopo servers when connecting")
+	name = flag.String("vttopo_grpc_server_name", "", "the server name to use to validate the vttopo server certificate")
+)
+
+// Dial returns a connection to a vttopo server. Calls fail right away
+// if the server is not reachable, so the caller can try another one.
+func Dial(addr string) (*grpc.ClientConn, error) {
+	opt, err := grpcclient.SecureDialOption(*cert, *key, *ca, *name)
+	if err != nil {
+		return nil, err
+	}
+	return grpcclient.Dial(addr, grpcclient.FailFast(true), opt)
+}
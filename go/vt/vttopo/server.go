@@ -0,0 +1,505 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package vttopo is an embedded topology server: a small replicated
+key-value store that implements what topo.Conn needs, so a Vitess
+cluster can run without an external etcd, Consul or ZooKeeper.
+
+A vttopo cluster is a fixed set of servers, usually three or five.
+They replicate a log of changes with the Raft consensus algorithm,
+using the etcd raft library, and persist it in a local directory.
+The leader serves all the requests; the other servers answer with an
+Unavailable error, and the clients try the next server. The client
+is in go/vt/topo/grpctopo.
+
+Files have the index of their last change in the log as version.
+Ephemeral files are attached to a lease, which the clients keep alive;
+the leader revokes leases that expire. Locks are ephemeral files in a
+locks subdirectory, ordered by creation.
+*/
+package vttopo
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+	vttoposervicepb "vitess.io/vitess/go/vt/proto/vttoposervice"
+)
+
+// Config is the configuration of a vttopo server.
+type Config struct {
+	// ID is the id of this server.
+	ID string
+	// Peers maps the ids of all the servers of the cluster,
+	// including this one, to their gRPC address.
+	Peers map[string]string
+	// DataDir is where the log is stored.
+	DataDir string
+	// HeartbeatInterval is how often the leader contacts the
+	// other servers.
+	HeartbeatInterval time.Duration
+	// ElectionTimeout is how long the other servers wait without
+	// hearing from the leader before electing a new one. It must
+	// be at least twice the heartbeat interval.
+	ElectionTimeout time.Duration
+	// SnapshotThreshold is the number of log entries after which
+	// the log is compacted.
+	SnapshotThreshold int
+}
+
+// Server is a vttopo server. It implements both the Topo and the Raft
+// services.
+type Server struct {
+	config Config
+	store  *store
+	raft   *raftNode
+
+	// mu protects keepAlives.
+	mu sync.Mutex
+	// keepAlives has the last time each lease was kept alive.
+	// Only the leader keeps it up to date.
+	keepAlives map[uint64]time.Time
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewServer loads the data of a server, and starts it.
+func NewServer(config Config) (*Server, error) {
+	if _, ok := config.Peers[config.ID]; !ok {
+		return nil, fmt.Errorf("server %v is not in the peers", config.ID)
+	}
+	if config.HeartbeatInterval <= 0 {
+		return nil, fmt.Errorf("invalid heartbeat interval %v", config.HeartbeatInterval)
+	}
+	raftLog, err := openRaftLog(config.DataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		config:     config,
+		store:      newStore(),
+		keepAlives: make(map[uint64]time.Time),
+		stop:       make(chan struct{}),
+	}
+	s.raft, err = newRaftNode(raftConfig{
+		id:                config.ID,
+		peers:             config.Peers,
+		heartbeatInterval: config.HeartbeatInterval,
+		electionTimeout:   config.ElectionTimeout,
+		snapshotThreshold: config.SnapshotThreshold,
+	}, raftLog, s, s.resetKeepAlives)
+	if err != nil {
+		raftLog.close()
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go s.expireLeases()
+	return s, nil
+}
+
+// Register registers the Topo and Raft services.
+func (s *Server) Register(grpcServer *grpc.Server) {
+	vttoposervicepb.RegisterTopoServer(grpcServer, s)
+	vttoposervicepb.RegisterRaftServer(grpcServer, s)
+}
+
+// Close stops the server.
+func (s *Server) Close() {
+	close(s.stop)
+	s.wg.Wait()
+	s.raft.close()
+}
+
+// IsLeader returns true if this server is the leader.
+func (s *Server) IsLeader() bool {
+	return s.raft.isLeader()
+}
+
+// Leader returns the id of the current leader, as known by this server.
+func (s *Server) Leader() string {
+	return s.raft.leader()
+}
+
+//
+// Raft state machine.
+//
+
+// apply is part of the stateMachine interface.
+func (s *Server) apply(index uint64, data []byte) interface{} {
+	cmd := &command{}
+	if err := json.Unmarshal(data, cmd); err != nil {
+		// This can't happen, we wrote it.
+		log.Errorf("vttopo: cannot decode command %v: %v", index, err)
+		return &commandResult{Err: err}
+	}
+	return s.store.apply(index, cmd)
+}
+
+// snapshot is part of the stateMachine interface.
+func (s *Server) snapshot() ([]byte, error) {
+	return s.store.snapshot()
+}
+
+// restore is part of the stateMachine interface.
+func (s *Server) restore(data []byte) error {
+	return s.store.restore(data)
+}
+
+// run proposes a command, and returns its result.
+func (s *Server) run(ctx context.Context, cmd *command) (*commandResult, error) {
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	result, err := s.raft.propose(ctx, data)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	cr := result.(*commandResult)
+	if cr.Err != nil {
+		return nil, toGRPCError(cr.Err)
+	}
+	return cr, nil
+}
+
+// read waits until this server can serve reads.
+func (s *Server) read(ctx context.Context) error {
+	return toGRPCError(s.raft.waitForLeaderRead(ctx))
+}
+
+// toGRPCError converts an error to a gRPC status.
+func toGRPCError(err error) error {
+	switch err {
+	case nil:
+		return nil
+	case topo.ErrNoNode:
+		return status.Error(codes.NotFound, err.Error())
+	case topo.ErrNodeExists:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case topo.ErrBadVersion:
+		return status.Error(codes.Aborted, err.Error())
+	case errNotLeader, errStopped:
+		// The client can try another server.
+		return status.Error(codes.Unavailable, err.Error())
+	case context.Canceled:
+		return status.Error(codes.Canceled, err.Error())
+	case context.DeadlineExceeded:
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+//
+// Leases.
+//
+
+// resetKeepAlives is called when this server becomes leader. It gives
+// all the leases a full TTL, as it doesn't know when they were last
+// kept alive.
+func (s *Server) resetKeepAlives() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keepAlives = make(map[uint64]time.Time)
+}
+
+// keepAlive records that a lease was kept alive now.
+func (s *Server) keepAlive(lease uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keepAlives[lease] = time.Now()
+}
+
+// expireLeases revokes the expired leases, when we are the leader.
+func (s *Server) expireLeases() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.config.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+		if !s.raft.isLeader() {
+			continue
+		}
+
+		now := time.Now()
+		leases := s.store.leaseTTLs()
+		var expired []uint64
+		s.mu.Lock()
+		for id := range s.keepAlives {
+			if _, ok := leases[id]; !ok {
+				delete(s.keepAlives, id)
+			}
+		}
+		for id, ttl := range leases {
+			last, ok := s.keepAlives[id]
+			if !ok {
+				s.keepAlives[id] = now
+				continue
+			}
+			if now.Sub(last) > ttl {
+				expired = append(expired, id)
+			}
+		}
+		s.mu.Unlock()
+
+		for _, id := range expired {
+			log.Infof("vttopo: lease %v expired", id)
+			ctx, cancel := context.WithTimeout(context.Background(), s.config.ElectionTimeout)
+			if _, err := s.run(ctx, &command{Op: opRevoke, Lease: id}); err != nil {
+				log.Warningf("vttopo: cannot revoke lease %v: %v", id, err)
+			}
+			cancel()
+		}
+	}
+}
+
+//
+// Topo service.
+//
+
+// Create is part of the TopoServer interface.
+func (s *Server) Create(ctx context.Context, req *vttopopb.CreateRequest) (*vttopopb.CreateResponse, error) {
+	result, err := s.run(ctx, &command{
+		Op:       opCreate,
+		Path:     req.Path,
+		Contents: req.Contents,
+		Lease:    req.Lease,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &vttopopb.CreateResponse{Version: result.Version}, nil
+}
+
+// Update is part of the TopoServer interface.
+func (s *Server) Update(ctx context.Context, req *vttopopb.UpdateRequest) (*vttopopb.UpdateResponse, error) {
+	result, err := s.run(ctx, &command{
+		Op:       opUpdate,
+		Path:     req.Path,
+		Contents: req.Contents,
+		Version:  req.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &vttopopb.UpdateResponse{Version: result.Version}, nil
+}
+
+// Get is part of the TopoServer interface.
+func (s *Server) Get(ctx context.Context, req *vttopopb.GetRequest) (*vttopopb.GetResponse, error) {
+	if err := s.read(ctx); err != nil {
+		return nil, err
+	}
+	n, _ := s.store.get(req.Path)
+	if n == nil {
+		return nil, toGRPCError(topo.ErrNoNode)
+	}
+	return &vttopopb.GetResponse{
+		Contents: n.Contents,
+		Version:  n.Version,
+	}, nil
+}
+
+// Delete is part of the TopoServer interface.
+func (s *Server) Delete(ctx context.Context, req *vttopopb.DeleteRequest) (*vttopopb.DeleteResponse, error) {
+	if _, err := s.run(ctx, &command{
+		Op:      opDelete,
+		Path:    req.Path,
+		Version: req.Version,
+	}); err != nil {
+		return nil, err
+	}
+	return &vttopopb.DeleteResponse{}, nil
+}
+
+// ListDir is part of the TopoServer interface.
+func (s *Server) ListDir(ctx context.Context, req *vttopopb.ListDirRequest) (*vttopopb.ListDirResponse, error) {
+	if err := s.read(ctx); err != nil {
+		return nil, err
+	}
+	entries, err := s.store.listDir(req.Path)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &vttopopb.ListDirResponse{Entries: entries}, nil
+}
+
+// Watch is part of the TopoServer interface. The stream ends with an
+// Unavailable error if this server stops being the leader, so the
+// client reconnects to the new one.
+func (s *Server) Watch(req *vttopopb.WatchRequest, stream vttoposervicepb.Topo_WatchServer) error {
+	ctx := stream.Context()
+	if err := s.read(ctx); err != nil {
+		return err
+	}
+
+	version := req.Version
+	ticker := time.NewTicker(s.config.ElectionTimeout)
+	defer ticker.Stop()
+	for {
+		n, changed := s.store.get(req.Path)
+		if n == nil {
+			return toGRPCError(topo.ErrNoNode)
+		}
+		if n.Version != version {
+			if err := stream.Send(&vttopopb.WatchResponse{
+				Contents: n.Contents,
+				Version:  n.Version,
+			}); err != nil {
+				return err
+			}
+			version = n.Version
+		}
+
+		select {
+		case <-changed:
+		case <-ticker.C:
+			if !s.raft.isLeader() {
+				return toGRPCError(errNotLeader)
+			}
+		case <-ctx.Done():
+			return toGRPCError(ctx.Err())
+		case <-s.stop:
+			return toGRPCError(errStopped)
+		}
+	}
+}
+
+// Grant is part of the TopoServer interface.
+func (s *Server) Grant(ctx context.Context, req *vttopopb.GrantRequest) (*vttopopb.GrantResponse, error) {
+	if req.Ttl <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid lease TTL %v", req.Ttl)
+	}
+	result, err := s.run(ctx, &command{
+		Op:  opGrant,
+		TTL: time.Duration(req.Ttl) * time.Millisecond,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.keepAlive(result.Lease)
+	return &vttopopb.GrantResponse{Lease: result.Lease}, nil
+}
+
+// KeepAlive is part of the TopoServer interface.
+func (s *Server) KeepAlive(ctx context.Context, req *vttopopb.KeepAliveRequest) (*vttopopb.KeepAliveResponse, error) {
+	if err := s.read(ctx); err != nil {
+		return nil, err
+	}
+	if !s.store.hasLease(req.Lease) {
+		return nil, toGRPCError(topo.ErrNoNode)
+	}
+	s.keepAlive(req.Lease)
+	return &vttopopb.KeepAliveResponse{}, nil
+}
+
+// Revoke is part of the TopoServer interface.
+func (s *Server) Revoke(ctx context.Context, req *vttopopb.RevokeRequest) (*vttopopb.RevokeResponse, error) {
+	if _, err := s.run(ctx, &command{
+		Op:    opRevoke,
+		Lease: req.Lease,
+	}); err != nil {
+		return nil, err
+	}
+	return &vttopopb.RevokeResponse{}, nil
+}
+
+// Lock is part of the TopoServer interface. The lock file stays
+// there if the wait is interrupted: the client revokes the lease.
+func (s *Server) Lock(ctx context.Context, req *vttopopb.LockRequest) (*vttopopb.LockResponse, error) {
+	lockPath := path.Join(req.Path, locksDir, fmt.Sprintf("%v", req.Lease))
+	if _, err := s.run(ctx, &command{
+		Op:       opCreate,
+		Path:     lockPath,
+		Contents: []byte(req.Contents),
+		Lease:    req.Lease,
+	}); err != nil && status.Code(err) != codes.AlreadyExists {
+		// The lock file exists already if the client is retrying
+		// after a leader change: only this lease can have
+		// created it.
+		return nil, err
+	}
+
+	ticker := time.NewTicker(s.config.ElectionTimeout)
+	defer ticker.Stop()
+	for {
+		holderPath, _, changed := s.store.lockHolder(req.Path)
+		if holderPath == lockPath {
+			return &vttopopb.LockResponse{}, nil
+		}
+		if n, _ := s.store.get(lockPath); n == nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "lock file %v was deleted, lease %v expired", lockPath, req.Lease)
+		}
+
+		select {
+		case <-changed:
+		case <-ticker.C:
+			if !s.raft.isLeader() {
+				return nil, toGRPCError(errNotLeader)
+			}
+		case <-ctx.Done():
+			return nil, toGRPCError(ctx.Err())
+		case <-s.stop:
+			return nil, toGRPCError(errStopped)
+		}
+	}
+}
+
+// LockHolder is part of the TopoServer interface.
+func (s *Server) LockHolder(ctx context.Context, req *vttopopb.LockHolderRequest) (*vttopopb.LockHolderResponse, error) {
+	if err := s.read(ctx); err != nil {
+		return nil, err
+	}
+	_, holder, _ := s.store.lockHolder(req.Path)
+	if holder == nil {
+		return &vttopopb.LockHolderResponse{}, nil
+	}
+	return &vttopopb.LockHolderResponse{
+		Held:     true,
+		Contents: string(holder.Contents),
+	}, nil
+}
+
+//
+// Raft service.
+//
+
+// Message is part of the RaftServer interface.
+func (s *Server) Message(ctx context.Context, req *vttopopb.RaftMessageRequest) (*vttopopb.RaftMessageResponse, error) {
+	if err := s.raft.step(ctx, req.Message); err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &vttopopb.RaftMessageResponse{}, nil
+}
@@ -0,0 +1,315 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttopo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+	vttoposervicepb "vitess.io/vitess/go/vt/proto/vttoposervice"
+)
+
+// testCluster is a cluster of vttopo servers in this process.
+type testCluster struct {
+	t       *testing.T
+	dir     string
+	peers   map[string]string
+	config  Config
+	servers map[string]*Server
+	grpc    map[string]*grpc.Server
+}
+
+func newTestCluster(t *testing.T, size, snapshotThreshold int) *testCluster {
+	dir, err := ioutil.TempDir("", "vttopo")
+	if err != nil {
+		t.Fatalf("cannot create temp dir: %v", err)
+	}
+	c := &testCluster{
+		t:     t,
+		dir:   dir,
+		peers: make(map[string]string),
+		config: Config{
+			HeartbeatInterval: 20 * time.Millisecond,
+			ElectionTimeout:   150 * time.Millisecond,
+			SnapshotThreshold: snapshotThreshold,
+		},
+		servers: make(map[string]*Server),
+		grpc:    make(map[string]*grpc.Server),
+	}
+	// Reserve the addresses first, all servers need all of them.
+	listeners := make(map[string]net.Listener)
+	for i := 0; i < size; i++ {
+		id := fmt.Sprintf("s%v", i)
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("cannot listen: %v", err)
+		}
+		listeners[id] = listener
+		c.peers[id] = listener.Addr().String()
+	}
+	for id, listener := range listeners {
+		c.startOn(id, listener)
+	}
+	return c
+}
+
+// start restarts a stopped server, on the same address.
+func (c *testCluster) start(id string) {
+	listener, err := net.Listen("tcp", c.peers[id])
+	if err != nil {
+		c.t.Fatalf("cannot listen: %v", err)
+	}
+	c.startOn(id, listener)
+}
+
+func (c *testCluster) startOn(id string, listener net.Listener) {
+	config := c.config
+	config.ID = id
+	config.Peers = c.peers
+	config.DataDir = path.Join(c.dir, id)
+	s, err := NewServer(config)
+	if err != nil {
+		c.t.Fatalf("NewServer(%v) failed: %v", id, err)
+	}
+	grpcServer := grpc.NewServer()
+	s.Register(grpcServer)
+	go grpcServer.Serve(listener)
+	c.servers[id] = s
+	c.grpc[id] = grpcServer
+}
+
+// stop stops a server.
+func (c *testCluster) stop(id string) {
+	c.grpc[id].Stop()
+	c.servers[id].Close()
+	delete(c.servers, id)
+	delete(c.grpc, id)
+}
+
+// close stops all the servers, and removes their data.
+func (c *testCluster) close() {
+	for id := range c.servers {
+		c.stop(id)
+	}
+	os.RemoveAll(c.dir)
+}
+
+// waitForLeader returns the id of the leader, once there is one that
+// can serve reads.
+func (c *testCluster) waitForLeader() string {
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		for id, s := range c.servers {
+			if s.raft.waitForLeaderRead(context.Background()) == nil {
+				return id
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.t.Fatalf("no leader elected")
+	return ""
+}
+
+// client returns a client to a server.
+func (c *testCluster) client(id string) vttoposervicepb.TopoClient {
+	cc, err := Dial(c.peers[id])
+	if err != nil {
+		c.t.Fatalf("Dial(%v) failed: %v", id, err)
+	}
+	return vttoposervicepb.NewTopoClient(cc)
+}
+
+// waitForFile waits until a server has a file, with the given contents.
+func (c *testCluster) waitForFile(id, filePath, contents string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if n, _ := c.servers[id].store.get(filePath); n != nil && string(n.Contents) == contents {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.t.Fatalf("server %v doesn't have %v=%v", id, filePath, contents)
+}
+
+func TestReplication(t *testing.T) {
+	c := newTestCluster(t, 3, 1000)
+	defer c.close()
+	ctx := context.Background()
+
+	leader := c.waitForLeader()
+	cli := c.client(leader)
+	resp, err := cli.Create(ctx, &vttopopb.CreateRequest{Path: "/a/b", Contents: []byte("1")})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := cli.Create(ctx, &vttopopb.CreateRequest{Path: "/a/b"}); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("second Create returned %v, expected AlreadyExists", err)
+	}
+	if _, err := cli.Update(ctx, &vttopopb.UpdateRequest{Path: "/a/b", Contents: []byte("2"), Version: resp.Version + 100}); status.Code(err) != codes.Aborted {
+		t.Errorf("Update with bad version returned %v, expected Aborted", err)
+	}
+	if _, err := cli.Update(ctx, &vttopopb.UpdateRequest{Path: "/a/b", Contents: []byte("2"), Version: resp.Version}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	for id := range c.servers {
+		c.waitForFile(id, "/a/b", "2")
+		if id == leader {
+			continue
+		}
+		if _, err := c.client(id).Get(ctx, &vttopopb.GetRequest{Path: "/a/b"}); status.Code(err) != codes.Unavailable {
+			t.Errorf("Get on follower %v returned %v, expected Unavailable", id, err)
+		}
+	}
+
+	list, err := cli.ListDir(ctx, &vttopopb.ListDirRequest{Path: "/"})
+	if err != nil || len(list.Entries) != 1 || list.Entries[0].Name != "a" || !list.Entries[0].Directory {
+		t.Errorf("ListDir(/) returned %v %v", list, err)
+	}
+}
+
+func TestFailoverAndRestart(t *testing.T) {
+	c := newTestCluster(t, 3, 1000)
+	defer c.close()
+	ctx := context.Background()
+
+	leader := c.waitForLeader()
+	if _, err := c.client(leader).Create(ctx, &vttopopb.CreateRequest{Path: "/f", Contents: []byte("before")}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// Stop the leader, another one takes over.
+	c.stop(leader)
+	newLeader := c.waitForLeader()
+	if newLeader == leader {
+		t.Fatalf("stopped server is still the leader")
+	}
+	cli := c.client(newLeader)
+	got, err := cli.Get(ctx, &vttopopb.GetRequest{Path: "/f"})
+	if err != nil || string(got.Contents) != "before" {
+		t.Fatalf("Get on new leader returned %v %v", got, err)
+	}
+	if _, err := cli.Update(ctx, &vttopopb.UpdateRequest{Path: "/f", Contents: []byte("after")}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	// The old leader catches up when it comes back.
+	c.start(leader)
+	c.waitForFile(leader, "/f", "after")
+
+	// And everything is still there after a full restart.
+	for id := range c.peers {
+		c.stop(id)
+	}
+	for id := range c.peers {
+		c.start(id)
+	}
+	leader = c.waitForLeader()
+	got, err = c.client(leader).Get(ctx, &vttopopb.GetRequest{Path: "/f"})
+	if err != nil || string(got.Contents) != "after" {
+		t.Fatalf("Get after restart returned %v %v", got, err)
+	}
+}
+
+func TestSnapshotInstall(t *testing.T) {
+	c := newTestCluster(t, 3, 10)
+	defer c.close()
+	ctx := context.Background()
+
+	leader := c.waitForLeader()
+	var lagging string
+	for id := range c.servers {
+		if id != leader {
+			lagging = id
+			break
+		}
+	}
+	c.stop(lagging)
+
+	// Write enough for the log to be compacted past what the
+	// stopped server has.
+	cli := c.client(leader)
+	for i := 0; i < 50; i++ {
+		if _, err := cli.Update(ctx, &vttopopb.UpdateRequest{Path: "/s", Contents: []byte(fmt.Sprintf("%v", i))}); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+	}
+	if snapshot, _ := c.servers[leader].raft.log.Snapshot(); snapshot.Metadata.Index == 0 {
+		t.Errorf("leader log was not compacted")
+	}
+
+	c.start(lagging)
+	c.waitForFile(lagging, "/s", "49")
+}
+
+func TestLeases(t *testing.T) {
+	c := newTestCluster(t, 1, 1000)
+	defer c.close()
+	ctx := context.Background()
+
+	leader := c.waitForLeader()
+	cli := c.client(leader)
+	grant, err := cli.Grant(ctx, &vttopopb.GrantRequest{Ttl: 300})
+	if err != nil {
+		t.Fatalf("Grant failed: %v", err)
+	}
+	if _, err := cli.Create(ctx, &vttopopb.CreateRequest{Path: "/dir/e", Lease: grant.Lease}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	list, err := cli.ListDir(ctx, &vttopopb.ListDirRequest{Path: "/"})
+	if err != nil || len(list.Entries) != 1 || !list.Entries[0].Ephemeral {
+		t.Errorf("ListDir(/) returned %v %v, expected an ephemeral dir", list, err)
+	}
+
+	// The lease stays while it is kept alive.
+	for i := 0; i < 5; i++ {
+		time.Sleep(100 * time.Millisecond)
+		if _, err := cli.KeepAlive(ctx, &vttopopb.KeepAliveRequest{Lease: grant.Lease}); err != nil {
+			t.Fatalf("KeepAlive failed: %v", err)
+		}
+	}
+	if _, err := cli.Get(ctx, &vttopopb.GetRequest{Path: "/dir/e"}); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	// And it expires when it is not.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := cli.Get(ctx, &vttopopb.GetRequest{Path: "/dir/e"})
+		if status.Code(err) == codes.NotFound {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("ephemeral file was not deleted: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if _, err := cli.KeepAlive(ctx, &vttopopb.KeepAliveRequest{Lease: grant.Lease}); status.Code(err) != codes.NotFound {
+		t.Errorf("KeepAlive of expired lease returned %v, expected NotFound", err)
+	}
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttopo
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/topo"
+
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+)
+
+const (
+	// locksDir is the subdirectory of a locked directory that
+	// contains the lock files.
+	locksDir = "locks"
+)
+
+// Operations of the replicated commands.
+const (
+	opCreate = "create"
+	opUpdate = "update"
+	opDelete = "delete"
+	opGrant  = "grant"
+	opRevoke = "revoke"
+)
+
+// command is a change to the store. Commands are the entries of the
+// replicated log, so they must be applied the same way everywhere:
+// they only depend on the store and the log index.
+type command struct {
+	Op       string        `json:"op"`
+	Path     string        `json:"path,omitempty"`
+	Contents []byte        `json:"contents,omitempty"`
+	Version  uint64        `json:"version,omitempty"`
+	Lease    uint64        `json:"lease,omitempty"`
+	TTL      time.Duration `json:"ttl,omitempty"`
+}
+
+// commandResult is the result of applying a command.
+type commandResult struct {
+	// Version is the new version of a file.
+	Version uint64
+	// Lease is the id of a new lease.
+	Lease uint64
+	// Err is a topo error.
+	Err error
+}
+
+// storeNode is a file in the store.
+type storeNode struct {
+	Contents []byte `json:"contents,omitempty"`
+	// Version is the log index of the last change.
+	Version uint64 `json:"version"`
+	// Created is the log index of the creation. Lock files are
+	// ordered by creation.
+	Created uint64 `json:"created"`
+	// Lease is the lease of ephemeral files.
+	Lease uint64 `json:"lease,omitempty"`
+}
+
+// storeSnapshot is the serialized state of the store.
+type storeSnapshot struct {
+	Nodes  map[string]*storeNode    `json:"nodes"`
+	Leases map[uint64]time.Duration `json:"leases"`
+}
+
+// store is the state machine: the topology files, and the leases.
+// Files are indexed by their full path, directories only exist
+// implicitly.
+type store struct {
+	mu     sync.Mutex
+	nodes  map[string]*storeNode
+	leases map[uint64]time.Duration
+	// changed is closed and replaced at each change.
+	changed chan struct{}
+}
+
+func newStore() *store {
+	return &store{
+		nodes:   make(map[string]*storeNode),
+		leases:  make(map[uint64]time.Duration),
+		changed: make(chan struct{}),
+	}
+}
+
+// apply applies a command, at a log index.
+func (s *store) apply(index uint64, cmd *command) *commandResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := s.applyLocked(index, cmd)
+	if result.Err == nil {
+		close(s.changed)
+		s.changed = make(chan struct{})
+	}
+	return result
+}
+
+func (s *store) applyLocked(index uint64, cmd *command) *commandResult {
+	switch cmd.Op {
+	case opCreate:
+		if _, ok := s.nodes[cmd.Path]; ok {
+			return &commandResult{Err: topo.ErrNodeExists}
+		}
+		if cmd.Lease != 0 {
+			if _, ok := s.leases[cmd.Lease]; !ok {
+				return &commandResult{Err: topo.ErrNoNode}
+			}
+		}
+		s.nodes[cmd.Path] = &storeNode{
+			Contents: cmd.Contents,
+			Version:  index,
+			Created:  index,
+			Lease:    cmd.Lease,
+		}
+		return &commandResult{Version: index}
+	case opUpdate:
+		n, ok := s.nodes[cmd.Path]
+		if !ok {
+			if cmd.Version != 0 {
+				return &commandResult{Err: topo.ErrNoNode}
+			}
+			n = &storeNode{
+				Created: index,
+			}
+			s.nodes[cmd.Path] = n
+		} else if cmd.Version != 0 && cmd.Version != n.Version {
+			return &commandResult{Err: topo.ErrBadVersion}
+		}
+		n.Contents = cmd.Contents
+		n.Version = index
+		return &commandResult{Version: index}
+	case opDelete:
+		n, ok := s.nodes[cmd.Path]
+		if !ok {
+			return &commandResult{Err: topo.ErrNoNode}
+		}
+		if cmd.Version != 0 && cmd.Version != n.Version {
+			return &commandResult{Err: topo.ErrBadVersion}
+		}
+		delete(s.nodes, cmd.Path)
+		return &commandResult{}
+	case opGrant:
+		// The lease id is the log index, so it is unique.
+		s.leases[index] = cmd.TTL
+		return &commandResult{Lease: index}
+	case opRevoke:
+		if _, ok := s.leases[cmd.Lease]; !ok {
+			return &commandResult{Err: topo.ErrNoNode}
+		}
+		delete(s.leases, cmd.Lease)
+		for p, n := range s.nodes {
+			if n.Lease == cmd.Lease {
+				delete(s.nodes, p)
+			}
+		}
+		return &commandResult{}
+	default:
+		return &commandResult{Err: fmt.Errorf("unknown command %v", cmd.Op)}
+	}
+}
+
+// get returns a copy of a file, and the channel closed at the next
+// change.
+func (s *store) get(filePath string) (*storeNode, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, ok := s.nodes[filePath]
+	if !ok {
+		return nil, s.changed
+	}
+	result := *n
+	return &result, s.changed
+}
+
+// listDir returns the entries of a directory.
+func (s *store) listDir(dirPath string) ([]*vttopopb.DirEntry, error) {
+	prefix := dirPath + "/"
+	if dirPath == "/" {
+		prefix = "/"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make(map[string]*vttopopb.DirEntry)
+	for p, n := range s.nodes {
+		if !strings.HasPrefix(p, prefix) {
+			continue
+		}
+		name := p[len(prefix):]
+		directory := false
+		if i := strings.Index(name, "/"); i >= 0 {
+			name = name[:i]
+			directory = true
+		}
+		e, ok := entries[name]
+		if !ok {
+			e = &vttopopb.DirEntry{
+				Name:      name,
+				Directory: directory,
+				Ephemeral: true,
+			}
+			entries[name] = e
+		}
+		e.Ephemeral = e.Ephemeral && n.Lease != 0
+	}
+	if len(entries) == 0 {
+		return nil, topo.ErrNoNode
+	}
+
+	result := make([]*vttopopb.DirEntry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// lockHolder returns the path and the node of the oldest file in the
+// locks subdirectory of a directory, and the channel closed at the
+// next change.
+func (s *store) lockHolder(dirPath string) (string, *storeNode, <-chan struct{}) {
+	prefix := path.Join(dirPath, locksDir) + "/"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var holderPath string
+	var holder *storeNode
+	for p, n := range s.nodes {
+		if !strings.HasPrefix(p, prefix) || strings.Contains(p[len(prefix):], "/") {
+			continue
+		}
+		if holder == nil || n.Created < holder.Created {
+			holderPath = p
+			holder = n
+		}
+	}
+	if holder == nil {
+		return "", nil, s.changed
+	}
+	result := *holder
+	return holderPath, &result, s.changed
+}
+
+// leaseTTLs returns a copy of the leases.
+func (s *store) leaseTTLs() map[uint64]time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make(map[uint64]time.Duration, len(s.leases))
+	for id, ttl := range s.leases {
+		result[id] = ttl
+	}
+	return result
+}
+
+// hasLease returns true if the lease exists.
+func (s *store) hasLease(id uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.leases[id]
+	return ok
+}
+
+// snapshot returns the serialized state.
+func (s *store) snapshot() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return json.Marshal(&storeSnapshot{
+		Nodes:  s.nodes,
+		Leases: s.leases,
+	})
+}
+
+// restore replaces the state with a snapshot.
+func (s *store) restore(data []byte) error {
+	snapshot := &storeSnapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return fmt.Errorf("cannot decode snapshot: %v", err)
+	}
+	if snapshot.Nodes == nil {
+		snapshot.Nodes = make(map[string]*storeNode)
+	}
+	if snapshot.Leases == nil {
+		snapshot.Leases = make(map[uint64]time.Duration)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nodes = snapshot.Nodes
+	s.leases = snapshot.Leases
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttopo
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/coreos/etcd/raft/raftpb"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	vttopopb "vitess.io/vitess/go/vt/proto/vttopo"
+	vttoposervicepb "vitess.io/vitess/go/vt/proto/vttoposervice"
+)
+
+// maxQueuedMessages is the number of raft messages waiting to be sent
+// to a peer, after which they are dropped.
+const maxQueuedMessages = 1024
+
+// errQueueFull is reported for the messages that are dropped.
+var errQueueFull = errors.New("too many raft messages queued")
+
+// grpcTransport sends the raft messages to the other servers. Each
+// peer has its own queue and goroutine, so a slow or unreachable peer
+// doesn't delay the others. Messages can be lost, raft sends them
+// again.
+type grpcTransport struct {
+	timeout time.Duration
+	// report is called with the result of each message.
+	report func(msg raftpb.Message, err error)
+	queues map[uint64]chan raftpb.Message
+	wg     sync.WaitGroup
+}
+
+// newGRPCTransport starts sending to the peers, indexed by raft id.
+func newGRPCTransport(addrs map[uint64]string, timeout time.Duration, report func(raftpb.Message, error)) *grpcTransport {
+	t := &grpcTransport{
+		timeout: timeout,
+		report:  report,
+		queues:  make(map[uint64]chan raftpb.Message),
+	}
+	for id, addr := range addrs {
+		queue := make(chan raftpb.Message, maxQueuedMessages)
+		t.queues[id] = queue
+		t.wg.Add(1)
+		go t.sendLoop(addr, queue)
+	}
+	return t
+}
+
+// sendLoop sends the messages of a peer, in order.
+func (t *grpcTransport) sendLoop(addr string, queue chan raftpb.Message) {
+	defer t.wg.Done()
+	var cc *grpc.ClientConn
+	defer func() {
+		if cc != nil {
+			cc.Close()
+		}
+	}()
+	for msg := range queue {
+		var err error
+		if cc == nil {
+			cc, err = Dial(addr)
+		}
+		if err == nil {
+			err = t.sendOne(vttoposervicepb.NewRaftClient(cc), msg)
+		}
+		t.report(msg, err)
+	}
+}
+
+func (t *grpcTransport) sendOne(client vttoposervicepb.RaftClient, msg raftpb.Message) error {
+	data, err := msg.Marshal()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+	_, err = client.Message(ctx, &vttopopb.RaftMessageRequest{
+		Message: data,
+	})
+	return err
+}
+
+// send queues messages for their peers.
+func (t *grpcTransport) send(msgs []raftpb.Message) {
+	for _, msg := range msgs {
+		queue, ok := t.queues[msg.To]
+		if !ok {
+			continue
+		}
+		select {
+		case queue <- msg:
+		default:
+			t.report(msg, errQueueFull)
+		}
+	}
+}
+
+// close stops sending. send must not be called any more.
+func (t *grpcTransport) close() {
+	for _, queue := range t.queues {
+		close(queue)
+	}
+	t.wg.Wait()
+}
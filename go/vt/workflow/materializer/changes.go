@@ -0,0 +1,193 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package materializer
+
+import (
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// changedRows returns the primary keys of the rows of table modified by
+// a DML statement read from the binlogs, or nil if the statement modifies
+// another table. Statements replicated with statement based replication
+// carry a stream comment listing the primary keys, and the ones generated
+// from row based events identify the rows by their primary key in their
+// WHERE clause and SET list. pkColumns are the primary key columns of
+// table. insertid is the value of the last SET INSERT_ID statement, used
+// for the auto-increment values of the stream comments; the next one is
+// returned.
+func changedRows(sql, table string, pkColumns []string, insertid int64) ([][]sqltypes.Value, int64, error) {
+	if binlog.HasStreamComment(sql) {
+		name, _, pks, nextid, err := binlog.ParseStreamComment(sql, insertid)
+		if err != nil || name != table {
+			return nil, nextid, err
+		}
+		return pks, nextid, nil
+	}
+	pks, err := parseRowStatement(sql, table, pkColumns)
+	return pks, insertid, err
+}
+
+// parseRowStatement extracts the primary keys from a statement generated
+// by the binlog streamer for a row based event: INSERT INTO t SET ...,
+// UPDATE t SET ... WHERE ... or DELETE FROM t WHERE .... An UPDATE that
+// changes the primary key returns both the old and the new one. It
+// returns nil if the statement modifies another table than table.
+func parseRowStatement(sql, table string, pkColumns []string) ([][]sqltypes.Value, error) {
+	stmt, err := sqlparser.Parse(sql)
+	if err != nil {
+		return nil, err
+	}
+	switch stmt := stmt.(type) {
+	case *sqlparser.Insert:
+		if stmt.Table.Name.String() != table {
+			return nil, nil
+		}
+		values, ok := stmt.Rows.(sqlparser.Values)
+		if !ok || len(values) != 1 {
+			return nil, fmt.Errorf("unexpected row based insert")
+		}
+		assigned := make(map[string]sqlparser.Expr)
+		for i, col := range stmt.Columns {
+			assigned[col.Lowered()] = values[0][i]
+		}
+		pk, err := pkValues(assigned, pkColumns)
+		if err != nil {
+			return nil, err
+		}
+		return [][]sqltypes.Value{pk}, nil
+	case *sqlparser.Update:
+		name, err := singleTable(stmt.TableExprs)
+		if err != nil || name != table {
+			return nil, err
+		}
+		matched, err := whereValues(stmt.Where)
+		if err != nil {
+			return nil, err
+		}
+		oldPK, err := pkValues(matched, pkColumns)
+		if err != nil {
+			return nil, err
+		}
+		pks := [][]sqltypes.Value{oldPK}
+
+		// The after image assigns all the columns, so the new pk can be
+		// read from the SET list when it differs from the old one.
+		assigned := make(map[string]sqlparser.Expr)
+		for _, expr := range stmt.Exprs {
+			assigned[expr.Name.Name.Lowered()] = expr.Expr
+		}
+		if newPK, err := pkValues(assigned, pkColumns); err == nil && pkKey(newPK) != pkKey(oldPK) {
+			pks = append(pks, newPK)
+		}
+		return pks, nil
+	case *sqlparser.Delete:
+		name, err := singleTable(stmt.TableExprs)
+		if err != nil || name != table {
+			return nil, err
+		}
+		matched, err := whereValues(stmt.Where)
+		if err != nil {
+			return nil, err
+		}
+		pk, err := pkValues(matched, pkColumns)
+		if err != nil {
+			return nil, err
+		}
+		return [][]sqltypes.Value{pk}, nil
+	}
+	return nil, fmt.Errorf("unexpected statement type %T", stmt)
+}
+
+func singleTable(tableExprs sqlparser.TableExprs) (string, error) {
+	if len(tableExprs) == 1 {
+		if ate, ok := tableExprs[0].(*sqlparser.AliasedTableExpr); ok {
+			if tn, ok := ate.Expr.(sqlparser.TableName); ok {
+				return tn.Name.String(), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unexpected table expression %v", sqlparser.String(tableExprs))
+}
+
+// whereValues returns the values of the columns compared with '=' in
+// a WHERE clause made of a conjunction of such comparisons.
+func whereValues(where *sqlparser.Where) (map[string]sqlparser.Expr, error) {
+	matched := make(map[string]sqlparser.Expr)
+	if where == nil {
+		return matched, nil
+	}
+	var add func(expr sqlparser.Expr) error
+	add = func(expr sqlparser.Expr) error {
+		switch expr := expr.(type) {
+		case *sqlparser.AndExpr:
+			if err := add(expr.Left); err != nil {
+				return err
+			}
+			return add(expr.Right)
+		case *sqlparser.ComparisonExpr:
+			col, ok := expr.Left.(*sqlparser.ColName)
+			if !ok || expr.Operator != sqlparser.EqualStr {
+				return fmt.Errorf("unexpected condition %v", sqlparser.String(expr))
+			}
+			matched[col.Name.Lowered()] = expr.Right
+		case *sqlparser.IsExpr:
+			// Only nullable columns are matched with IS NULL, and
+			// they cannot be part of the primary key.
+		default:
+			return fmt.Errorf("unexpected condition %v", sqlparser.String(expr))
+		}
+		return nil
+	}
+	return matched, add(where.Expr)
+}
+
+// pkValues returns the values of the primary key columns.
+func pkValues(values map[string]sqlparser.Expr, pkColumns []string) ([]sqltypes.Value, error) {
+	pk := make([]sqltypes.Value, 0, len(pkColumns))
+	for _, col := range pkColumns {
+		expr, ok := values[strings.ToLower(col)]
+		if !ok {
+			return nil, fmt.Errorf("missing value for primary key column %v", col)
+		}
+		v, err := exprValue(expr)
+		if err != nil {
+			return nil, err
+		}
+		pk = append(pk, v)
+	}
+	return pk, nil
+}
+
+// exprValue returns the value of a literal.
+func exprValue(expr sqlparser.Expr) (sqltypes.Value, error) {
+	if val, ok := expr.(*sqlparser.SQLVal); ok && val.Type == sqlparser.FloatVal {
+		return sqltypes.MakeTrusted(sqltypes.Float64, val.Val), nil
+	}
+	pv, err := sqlparser.NewPlanValue(expr)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	if pv.Key != "" || pv.ListKey != "" || pv.Values != nil {
+		return sqltypes.NULL, fmt.Errorf("unexpected value %v", sqlparser.String(expr))
+	}
+	return pv.Value, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package materializer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChangedRows(t *testing.T) {
+	testcases := []struct {
+		sql       string
+		pkColumns []string
+		insertid  int64
+		// pks are the pkKey of the returned primary keys.
+		pks    []string
+		nextid int64
+		err    string
+	}{{
+		// Statement based replication.
+		sql:       "insert into t(id, name) values (1, 'a'), (2, 'b') /* _stream t (id ) (1 ) (2 ); */",
+		pkColumns: []string{"id"},
+		pks:       []string{"1", "2"},
+	}, {
+		sql:       "insert into t(name) values ('a') /* _stream t (id ) (null ); */",
+		pkColumns: []string{"id"},
+		insertid:  7,
+		pks:       []string{"7"},
+		nextid:    8,
+	}, {
+		sql:       "update t set c = 1 where a = 1 /* _stream t (a b ) (1 'eA==' ) (-2 'eQ==' ); */",
+		pkColumns: []string{"a", "b"},
+		pks:       []string{"1,'x'", "-2,'y'"},
+	}, {
+		sql:       "delete from other where id = 1 /* _stream other (id ) (1 ); */",
+		pkColumns: []string{"id"},
+	}, {
+		sql:       "insert into t(id) values (1) /* _stream t (id ) (1 */",
+		pkColumns: []string{"id"},
+		err:       "syntax error",
+	}, {
+		// Row based replication.
+		sql:       "INSERT INTO t SET id=1, name='a'",
+		pkColumns: []string{"id"},
+		pks:       []string{"1"},
+	}, {
+		sql:       "UPDATE t SET id=1, name='b' WHERE id=1",
+		pkColumns: []string{"id"},
+		pks:       []string{"1"},
+	}, {
+		sql:       "UPDATE t SET id=2, name='b' WHERE id=1",
+		pkColumns: []string{"id"},
+		pks:       []string{"1", "2"},
+	}, {
+		sql:       "DELETE FROM t WHERE a=-1 AND b='x' AND c IS NULL",
+		pkColumns: []string{"a", "b"},
+		pks:       []string{"-1,'x'"},
+	}, {
+		sql:       "DELETE FROM other WHERE a=1",
+		pkColumns: []string{"id"},
+	}, {
+		sql:       "DELETE FROM t WHERE name='a'",
+		pkColumns: []string{"id"},
+		err:       "missing value for primary key column id",
+	}, {
+		sql:       "DELETE FROM t WHERE id>1",
+		pkColumns: []string{"id"},
+		err:       "unexpected condition",
+	}}
+	for _, tcase := range testcases {
+		pks, nextid, err := changedRows(tcase.sql, "t", tcase.pkColumns, tcase.insertid)
+		if tcase.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tcase.err) {
+				t.Errorf("changedRows(%q): %v, want %v", tcase.sql, err, tcase.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("changedRows(%q): %v", tcase.sql, err)
+			continue
+		}
+		var got []string
+		for _, pk := range pks {
+			got = append(got, pkKey(pk))
+		}
+		if strings.Join(got, " ") != strings.Join(tcase.pks, " ") {
+			t.Errorf("changedRows(%q): %v, want %v", tcase.sql, got, tcase.pks)
+		}
+		if tcase.nextid != 0 && nextid != tcase.nextid {
+			t.Errorf("changedRows(%q): next insert id %v, want %v", tcase.sql, nextid, tcase.nextid)
+		}
+	}
+}
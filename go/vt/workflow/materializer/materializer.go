@@ -0,0 +1,408 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package materializer contains the materialization workflow. It copies
+// the rows selected by a query on a table of a source keyspace to a table
+// of a target keyspace, and then keeps the target table up to date by
+// replaying the changes of the source table read from the binlogs. The
+// target table can be sharded differently from the source table, or
+// copied to all the shards of the target keyspace.
+package materializer
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/throttler"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/workflow"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	workflowpb "vitess.io/vitess/go/vt/proto/workflow"
+)
+
+var (
+	chunkSize = flag.Int("materialize_chunk_size", 1000,
+		"number of rows copied at a time to the target table of a materialization")
+	maxRate = flag.Int64("materialize_max_rate", throttler.MaxRateModuleDisabled,
+		"maximum number of chunks copied per second and per source shard during the initial copy of a materialization, unlimited by default")
+	retryDelay = flag.Duration("materialize_retry_delay", 10*time.Second,
+		"time to wait before resuming the materialization of a source shard after an error")
+	checkpointInterval = flag.Duration("materialize_checkpoint_interval", 5*time.Second,
+		"how often the replication positions of a materialization are saved")
+)
+
+// WorkflowFactoryName is the name of the materialization workflow factory.
+const WorkflowFactoryName = "materialize"
+
+// Register registers the materialization as a factory
+// in the workflow framework.
+func Register() {
+	workflow.Register(WorkflowFactoryName, &WorkflowFactory{})
+}
+
+// WorkflowFactory is the factory to create materialization workflows.
+type WorkflowFactory struct{}
+
+// materializeData is the data saved in the workflow.
+type materializeData struct {
+	SourceKeyspace, TargetKeyspace, TargetTable, SQL string
+	// AllShards is set if the rows are copied to all the target shards.
+	AllShards bool
+	// SourceTabletType is the type of the source tablets the rows are
+	// read from.
+	SourceTabletType string
+
+	// Positions are the replication positions of the source shards,
+	// by shard name, from which the changes have to be replayed.
+	Positions map[string]string
+	// Copied is set for the source shards whose initial copy is done.
+	Copied map[string]bool
+}
+
+// Init is part of the workflow.Factory interface.
+func (*WorkflowFactory) Init(_ *workflow.Manager, w *workflowpb.Workflow, args []string) error {
+	subFlags := flag.NewFlagSet(WorkflowFactoryName, flag.ContinueOnError)
+	sourceKeyspace := subFlags.String("source_keyspace", "", "Name of the keyspace to read the rows from")
+	targetKeyspace := subFlags.String("target_keyspace", "", "Name of the keyspace to copy the rows to")
+	targetTable := subFlags.String("target_table", "", "Name of the table to copy the rows to, the source table by default")
+	sql := subFlags.String("sql", "", "SELECT statement filtering and projecting the rows of the source table")
+	allShards := subFlags.Bool("all_shards", false, "Copy the rows to all the shards of the target keyspace instead of routing them with the primary vindex of the target table")
+	sourceTabletType := subFlags.String("source_tablet_type", "REPLICA", "Type of the source tablets to read the rows and the binlogs from")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if *sourceKeyspace == "" || *targetKeyspace == "" || *sql == "" {
+		return fmt.Errorf("source keyspace, target keyspace and SQL statement must be provided for materialization")
+	}
+	if _, err := topoproto.ParseTabletType(*sourceTabletType); err != nil {
+		return err
+	}
+	_, table, err := parseSourceQuery(*sql)
+	if err != nil {
+		return err
+	}
+	if *targetTable == "" {
+		*targetTable = table
+	}
+	if *sourceKeyspace == *targetKeyspace && *targetTable == table {
+		return fmt.Errorf("cannot materialize table %v into itself", table)
+	}
+
+	w.Name = fmt.Sprintf("Materialize %v.%v into %v.%v", *sourceKeyspace, table, *targetKeyspace, *targetTable)
+	w.Data, err = json.Marshal(&materializeData{
+		SourceKeyspace:   *sourceKeyspace,
+		TargetKeyspace:   *targetKeyspace,
+		TargetTable:      *targetTable,
+		SQL:              *sql,
+		AllShards:        *allShards,
+		SourceTabletType: *sourceTabletType,
+	})
+	return err
+}
+
+// Instantiate is part of the workflow.Factory interface.
+func (*WorkflowFactory) Instantiate(_ *workflow.Manager, w *workflowpb.Workflow, rootNode *workflow.Node) (workflow.Workflow, error) {
+	data := &materializeData{}
+	if err := json.Unmarshal(w.Data, data); err != nil {
+		return nil, err
+	}
+	if data.Positions == nil {
+		data.Positions = make(map[string]string)
+	}
+	if data.Copied == nil {
+		data.Copied = make(map[string]bool)
+	}
+	sel, table, err := parseSourceQuery(data.SQL)
+	if err != nil {
+		return nil, err
+	}
+	tabletType, err := topoproto.ParseTabletType(data.SourceTabletType)
+	if err != nil {
+		return nil, err
+	}
+	rootNode.Message = fmt.Sprintf("Rows of %v.%v are materialized into %v.%v: %v", data.SourceKeyspace, table, data.TargetKeyspace, data.TargetTable, data.SQL)
+
+	return &Materializer{
+		data:       data,
+		sel:        sel,
+		table:      table,
+		tabletType: tabletType,
+		rootUINode: rootNode,
+		uiLogger:   logutil.NewMemoryLogger(),
+	}, nil
+}
+
+// Materializer is the workflow copying the rows of a source table to a
+// target table, and replaying the changes of the source table on the
+// target table until it is stopped. Each source shard is copied and
+// replayed independently, and its replication position is checkpointed
+// in the workflow so the replay resumes where it stopped when the
+// workflow is restarted.
+type Materializer struct {
+	// mu protects data, which is shared by the source shards.
+	mu   sync.Mutex
+	data *materializeData
+
+	// sel is the source query.
+	sel *sqlparser.Select
+	// table is the name of the source table.
+	table string
+	// tabletType is the type of the source tablets.
+	tabletType topodatapb.TabletType
+
+	// rootUINode is the root node in the workflow UI representing
+	// this materialization.
+	rootUINode *workflow.Node
+	// uiLogger is the logger collecting logs that will be displayed
+	// in the UI.
+	uiLogger *logutil.MemoryLogger
+
+	wi             *topo.WorkflowInfo
+	topoServer     *topo.Server
+	tabletClient   tmclient.TabletManagerClient
+	lastCheckpoint time.Time
+
+	// m generates the queries copying the rows.
+	m *materialization
+	// router maps the rows to the target shards.
+	router *router
+	// shards contains the streams of each source shard.
+	shards []*shardStream
+}
+
+// Run is part of the workflow.Workflow interface.
+func (mz *Materializer) Run(ctx context.Context, manager *workflow.Manager, wi *topo.WorkflowInfo) error {
+	mz.wi = wi
+	mz.topoServer = manager.TopoServer()
+	mz.tabletClient = tmclient.NewTabletManagerClient()
+	defer mz.tabletClient.Close()
+
+	log.Infof("Starting materialization of %v.%v into %v.%v: %v", mz.data.SourceKeyspace, mz.table, mz.data.TargetKeyspace, mz.data.TargetTable, mz.data.SQL)
+	if err := mz.run(ctx); err != nil {
+		mz.setUIMessage(fmt.Sprintf("Error: %v", err))
+		return err
+	}
+	return nil
+}
+
+func (mz *Materializer) run(ctx context.Context) error {
+	if err := mz.createShardStreams(ctx); err != nil {
+		return err
+	}
+
+	// The source schema is read from the first source shard, the
+	// other ones are expected to have the same.
+	td, err := mz.getSourceTableDefinition(ctx)
+	if err != nil {
+		return err
+	}
+	mz.m, err = newMaterialization(mz.sel, mz.data.TargetTable, td.Columns, td.PrimaryKeyColumns)
+	if err != nil {
+		return err
+	}
+	mz.router, err = newRouter(ctx, mz.topoServer, mz.data.TargetKeyspace, mz.m, mz.data.AllShards)
+	if err != nil {
+		return err
+	}
+
+	mz.setUIMessage("Materializing the rows of the source shards")
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var rec concurrency.AllErrorRecorder
+	var wg sync.WaitGroup
+	for _, ss := range mz.shards {
+		wg.Add(1)
+		go func(ss *shardStream) {
+			defer wg.Done()
+			if err := ss.run(ctx); err != nil {
+				ss.setUIMessage(fmt.Sprintf("Error: %v", err))
+				rec.RecordError(err)
+				cancel()
+			}
+		}(ss)
+	}
+	wg.Wait()
+	return rec.Error()
+}
+
+// createShardStreams creates the streams of all the source shards,
+// and their UI nodes.
+func (mz *Materializer) createShardStreams(ctx context.Context) error {
+	shards, err := mz.topoServer.GetShardNames(ctx, mz.data.SourceKeyspace)
+	if err != nil {
+		return err
+	}
+	if len(shards) == 0 {
+		return fmt.Errorf("keyspace %v has no shard", mz.data.SourceKeyspace)
+	}
+	mz.shards = nil
+	mz.rootUINode.Children = nil
+	for _, shard := range shards {
+		ss := &shardStream{
+			parent: mz,
+			shard:  shard,
+			uiNode: &workflow.Node{
+				Name:     fmt.Sprintf("Source shard %v", shard),
+				PathName: shard,
+				State:    workflowpb.WorkflowState_Running,
+			},
+			uiLogger: logutil.NewMemoryLogger(),
+		}
+		mz.shards = append(mz.shards, ss)
+		mz.rootUINode.Children = append(mz.rootUINode.Children, ss.uiNode)
+	}
+	mz.rootUINode.BroadcastChanges(true /* updateChildren */)
+	return nil
+}
+
+// getSourceTableDefinition returns the definition of the source table,
+// read from the master of the first source shard.
+func (mz *Materializer) getSourceTableDefinition(ctx context.Context) (*tabletmanagerdatapb.TableDefinition, error) {
+	si, err := mz.topoServer.GetShard(ctx, mz.data.SourceKeyspace, mz.shards[0].shard)
+	if err != nil {
+		return nil, err
+	}
+	if !si.HasMaster() {
+		return nil, fmt.Errorf("shard %v/%v has no master", mz.data.SourceKeyspace, si.ShardName())
+	}
+	ti, err := mz.topoServer.GetTablet(ctx, si.MasterAlias)
+	if err != nil {
+		return nil, err
+	}
+	sd, err := mz.tabletClient.GetSchema(ctx, ti.Tablet, []string{mz.table}, nil /* excludeTables */, false /* includeViews */)
+	if err != nil {
+		return nil, err
+	}
+	for _, td := range sd.TableDefinitions {
+		if td.Name == mz.table {
+			return td, nil
+		}
+	}
+	return nil, fmt.Errorf("table %v does not exist on shard %v/%v", mz.table, mz.data.SourceKeyspace, si.ShardName())
+}
+
+// writeRows upserts rows on the target shards they belong to.
+func (mz *Materializer) writeRows(ctx context.Context, rows [][]sqltypes.Value) error {
+	byShard := make(map[string][][]sqltypes.Value)
+	for _, row := range rows {
+		shards, err := mz.router.rowShards(row)
+		if err != nil {
+			return err
+		}
+		for _, shard := range shards {
+			byShard[shard] = append(byShard[shard], row)
+		}
+	}
+	for shard, rows := range byShard {
+		if err := mz.executeOnTarget(ctx, shard, mz.m.upsertQuery(rows)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteRows deletes the target rows copied from the source rows with
+// the given primary keys, except from the shards listed in keep for
+// their primary key.
+func (mz *Materializer) deleteRows(ctx context.Context, pks [][]sqltypes.Value, keep map[string]map[string]bool) error {
+	byShard := make(map[string][][]sqltypes.Value)
+	for _, pk := range pks {
+		shards, err := mz.router.pkShards(pk)
+		if err != nil {
+			return err
+		}
+		for _, shard := range shards {
+			if !keep[pkKey(pk)][shard] {
+				byShard[shard] = append(byShard[shard], pk)
+			}
+		}
+	}
+	for shard, pks := range byShard {
+		if err := mz.executeOnTarget(ctx, shard, mz.m.deleteQuery(pks)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeOnTarget runs a statement on the master of a target shard.
+// The master is resolved again from the topology by the next statement
+// if it fails, so the statement is retried on the new master if the shard
+// was reparented.
+func (mz *Materializer) executeOnTarget(ctx context.Context, shard, sql string) error {
+	tablet, err := mz.router.master(ctx, shard)
+	if err != nil {
+		return fmt.Errorf("cannot resolve the master of shard %v/%v: %v", mz.data.TargetKeyspace, shard, err)
+	}
+	if _, err := mz.tabletClient.ExecuteFetchAsApp(ctx, tablet, true /* usePool */, []byte(sql), 0 /* maxRows */); err != nil {
+		mz.router.masterFailed(shard, tablet)
+		return fmt.Errorf("%v failed on shard %v/%v: %v", sql, mz.data.TargetKeyspace, shard, err)
+	}
+	return nil
+}
+
+// checkpoint records the progress of a source shard, and saves the
+// workflow data if it was not saved recently or if force is set.
+func (mz *Materializer) checkpoint(ctx context.Context, shard, position string, copied, force bool) error {
+	mz.mu.Lock()
+	defer mz.mu.Unlock()
+	mz.data.Positions[shard] = position
+	mz.data.Copied[shard] = copied
+	if !force && time.Since(mz.lastCheckpoint) < *checkpointInterval {
+		return nil
+	}
+
+	var err error
+	mz.wi.Data, err = json.Marshal(mz.data)
+	if err != nil {
+		return err
+	}
+	if err := mz.topoServer.SaveWorkflow(ctx, mz.wi); err != nil {
+		return err
+	}
+	mz.lastCheckpoint = time.Now()
+	return nil
+}
+
+// progress returns the saved progress of a source shard.
+func (mz *Materializer) progress(shard string) (string, bool) {
+	mz.mu.Lock()
+	defer mz.mu.Unlock()
+	return mz.data.Positions[shard], mz.data.Copied[shard]
+}
+
+// setUIMessage updates the message of the root UI node and broadcasts changes.
+func (mz *Materializer) setUIMessage(message string) {
+	log.Infof("Materialization of %v.%v: %v", mz.data.SourceKeyspace, mz.table, message)
+	mz.uiLogger.Infof("%v", message)
+	mz.rootUINode.Log = mz.uiLogger.String()
+	mz.rootUINode.Message = message
+	mz.rootUINode.BroadcastChanges(false /* updateChildren */)
+}
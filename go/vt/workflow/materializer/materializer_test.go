@@ -0,0 +1,550 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package materializer
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/workflow"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	workflowpb "vitess.io/vitess/go/vt/proto/workflow"
+)
+
+const fakeBinlogPlayerProtocol = "materializer_test"
+
+// fakeBinlog is the binlog server of the source tablets, shared by all
+// the clients created by the fake binlog player protocol.
+var fakeBinlog = &fakeBinlogClient{}
+
+func init() {
+	binlogplayer.RegisterClientFactory(fakeBinlogPlayerProtocol, func() binlogplayer.Client {
+		return fakeBinlog
+	})
+}
+
+// testPosition returns the position of the source after n transactions.
+func testPosition(n int) string {
+	return fmt.Sprintf("MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-%d", n)
+}
+
+// fakeBinlogClient implements binlogplayer.Client. It streams the
+// transactions that come after the requested position, and ends the
+// stream after the last one.
+type fakeBinlogClient struct {
+	mu           sync.Mutex
+	transactions []*binlogdatapb.BinlogTransaction
+	// positions records the positions the streams were started from.
+	positions []string
+	// onStream is called every time a stream is started.
+	onStream func()
+}
+
+func (fbc *fakeBinlogClient) reset(transactions []*binlogdatapb.BinlogTransaction, onStream func()) {
+	fbc.mu.Lock()
+	defer fbc.mu.Unlock()
+	fbc.transactions = transactions
+	fbc.positions = nil
+	fbc.onStream = onStream
+}
+
+// Dial is part of the binlogplayer.Client interface.
+func (fbc *fakeBinlogClient) Dial(tablet *topodatapb.Tablet) error {
+	return nil
+}
+
+// Close is part of the binlogplayer.Client interface.
+func (fbc *fakeBinlogClient) Close() {
+}
+
+// StreamTables is part of the binlogplayer.Client interface.
+func (fbc *fakeBinlogClient) StreamTables(ctx context.Context, position string, tables []string, charset *binlogdatapb.Charset) (binlogplayer.BinlogTransactionStream, error) {
+	fbc.mu.Lock()
+	defer fbc.mu.Unlock()
+	fbc.positions = append(fbc.positions, position)
+	if fbc.onStream != nil {
+		fbc.onStream()
+	}
+	start, err := mysql.DecodePosition(position)
+	if err != nil {
+		return nil, err
+	}
+	stream := &fakeBinlogStream{}
+	for _, tx := range fbc.transactions {
+		pos, err := mysql.DecodePosition(tx.EventToken.Position)
+		if err != nil {
+			return nil, err
+		}
+		if !start.AtLeast(pos) {
+			stream.transactions = append(stream.transactions, tx)
+		}
+	}
+	return stream, nil
+}
+
+// StreamKeyRange is part of the binlogplayer.Client interface.
+func (fbc *fakeBinlogClient) StreamKeyRange(ctx context.Context, position string, keyRange *topodatapb.KeyRange, charset *binlogdatapb.Charset) (binlogplayer.BinlogTransactionStream, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+type fakeBinlogStream struct {
+	transactions []*binlogdatapb.BinlogTransaction
+}
+
+// Recv is part of the binlogplayer.BinlogTransactionStream interface.
+func (fbs *fakeBinlogStream) Recv() (*binlogdatapb.BinlogTransaction, error) {
+	if len(fbs.transactions) == 0 {
+		return nil, io.EOF
+	}
+	tx := fbs.transactions[0]
+	fbs.transactions = fbs.transactions[1:]
+	return tx, nil
+}
+
+// testTransaction returns a transaction made of the given statements,
+// that brings the source to testPosition(n).
+func testTransaction(n int, statements ...*binlogdatapb.BinlogTransaction_Statement) *binlogdatapb.BinlogTransaction {
+	return &binlogdatapb.BinlogTransaction{
+		Statements: statements,
+		EventToken: &querypb.EventToken{Position: testPosition(n)},
+	}
+}
+
+func testStatement(category binlogdatapb.BinlogTransaction_Statement_Category, sql string) *binlogdatapb.BinlogTransaction_Statement {
+	return &binlogdatapb.BinlogTransaction_Statement{
+		Category: category,
+		Sql:      []byte(sql),
+	}
+}
+
+var (
+	lastPKRE = regexp.MustCompile(`id > (\d+)`)
+	limitRE  = regexp.MustCompile(`limit (\d+)`)
+	inRE     = regexp.MustCompile(`id in \(([^)]*)\)`)
+)
+
+// fakeTabletManagerClient simulates the tablets of the "src" keyspace,
+// holding the table "t" with the columns "id" and "name", and the
+// masters of the "dst" keyspace, whose statements are recorded.
+type fakeTabletManagerClient struct {
+	tmclient.TabletManagerClient
+
+	mu sync.Mutex
+	// rows are the names of the rows of the source table, by id.
+	rows map[int64]string
+	// position is the replication position of the source tablets.
+	position string
+	// queries records the statements run on the target masters, by
+	// tablet uid.
+	queries map[uint32][]string
+	// failing lists the uids of the target tablets that fail the
+	// statements.
+	failing map[uint32]bool
+}
+
+func newFakeTabletManagerClient(rows map[int64]string, position string) *fakeTabletManagerClient {
+	return &fakeTabletManagerClient{
+		rows:     rows,
+		position: position,
+		queries:  make(map[uint32][]string),
+		failing:  make(map[uint32]bool),
+	}
+}
+
+// GetSchema is part of the tmclient.TabletManagerClient interface.
+func (client *fakeTabletManagerClient) GetSchema(ctx context.Context, tablet *topodatapb.Tablet, tables, excludeTables []string, includeViews bool) (*tabletmanagerdatapb.SchemaDefinition, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	sd := &tabletmanagerdatapb.SchemaDefinition{}
+	for _, table := range tables {
+		if table == "t" {
+			sd.TableDefinitions = append(sd.TableDefinitions, &tabletmanagerdatapb.TableDefinition{
+				Name:              "t",
+				Columns:           []string{"id", "name"},
+				PrimaryKeyColumns: []string{"id"},
+				RowCount:          uint64(len(client.rows)),
+			})
+		}
+	}
+	return sd, nil
+}
+
+// SlaveStatus is part of the tmclient.TabletManagerClient interface.
+func (client *fakeTabletManagerClient) SlaveStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.Status, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return &replicationdatapb.Status{Position: client.position}, nil
+}
+
+// ExecuteFetchAsApp is part of the tmclient.TabletManagerClient interface.
+func (client *fakeTabletManagerClient) ExecuteFetchAsApp(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, query []byte, maxRows int) (*querypb.QueryResult, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	sql := string(query)
+	if tablet.Keyspace != "src" {
+		if client.failing[tablet.Alias.Uid] {
+			return nil, fmt.Errorf("tablet %v is not serving", tablet.Alias.Uid)
+		}
+		client.queries[tablet.Alias.Uid] = append(client.queries[tablet.Alias.Uid], sql)
+		return &querypb.QueryResult{}, nil
+	}
+
+	// The source queries select the rows of "t" by primary key range
+	// for the copy, and by primary key for the replay.
+	var ids []int64
+	for id := range client.rows {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if m := inRE.FindStringSubmatch(sql); m != nil {
+		wanted := make(map[int64]bool)
+		for _, v := range strings.Split(m[1], ", ") {
+			id, _ := strconv.ParseInt(v, 10, 64)
+			wanted[id] = true
+		}
+		var selected []int64
+		for _, id := range ids {
+			if wanted[id] {
+				selected = append(selected, id)
+			}
+		}
+		ids = selected
+	} else {
+		var lastPK int64
+		if m := lastPKRE.FindStringSubmatch(sql); m != nil {
+			lastPK, _ = strconv.ParseInt(m[1], 10, 64)
+		}
+		limit, _ := strconv.Atoi(limitRE.FindStringSubmatch(sql)[1])
+		var selected []int64
+		for _, id := range ids {
+			if id > lastPK && len(selected) < limit {
+				selected = append(selected, id)
+			}
+		}
+		ids = selected
+	}
+	result := &sqltypes.Result{
+		Fields: []*querypb.Field{{Name: "id", Type: sqltypes.Int64}, {Name: "name", Type: sqltypes.VarChar}},
+	}
+	for _, id := range ids {
+		result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewInt64(id), sqltypes.NewVarChar(client.rows[id])})
+	}
+	return sqltypes.ResultToProto3(result), nil
+}
+
+// reset forgets the statements run on the target masters.
+func (client *fakeTabletManagerClient) reset() {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.queries = make(map[uint32][]string)
+}
+
+// targetQueries returns the statements run on a target master.
+func (client *fakeTabletManagerClient) targetQueries(uid uint32) []string {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.queries[uid]
+}
+
+// addTestTablet creates a tablet in "cell1", and makes it the master of
+// its shard if it is a master.
+func addTestTablet(t *testing.T, ts *topo.Server, keyspace, shard string, uid uint32, tabletType topodatapb.TabletType) *topodatapb.Tablet {
+	ctx := context.Background()
+	tablet := &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: uid},
+		Keyspace: keyspace,
+		Shard:    shard,
+		Type:     tabletType,
+	}
+	if err := ts.CreateTablet(ctx, tablet); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.UpdateShardFields(ctx, keyspace, shard, func(si *topo.ShardInfo) error {
+		si.Cells = []string{"cell1"}
+		if tabletType == topodatapb.TabletType_MASTER {
+			si.MasterAlias = tablet.Alias
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return tablet
+}
+
+// newTestTopo returns a topology with the unsharded "src" keyspace,
+// whose shard has a master with uid 100 and a replica with uid 101, and
+// the "dst" keyspace, whose shards "-80" and "80-" have the masters with
+// uids 200 and 210. The table "t" of "dst" has a hash primary vindex
+// on "id".
+func newTestTopo(t *testing.T) *topo.Server {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	if err := ts.CreateKeyspace(ctx, "src", &topodatapb.Keyspace{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.CreateShard(ctx, "src", "0"); err != nil {
+		t.Fatal(err)
+	}
+	addTestTablet(t, ts, "src", "0", 100, topodatapb.TabletType_MASTER)
+	addTestTablet(t, ts, "src", "0", 101, topodatapb.TabletType_REPLICA)
+
+	if err := ts.CreateKeyspace(ctx, "dst", &topodatapb.Keyspace{}); err != nil {
+		t.Fatal(err)
+	}
+	for i, shard := range []string{"-80", "80-"} {
+		if err := ts.CreateShard(ctx, "dst", shard); err != nil {
+			t.Fatal(err)
+		}
+		addTestTablet(t, ts, "dst", shard, uint32(200+10*i), topodatapb.TabletType_MASTER)
+	}
+	if err := ts.SaveVSchema(ctx, "dst", &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"hash": {Type: "hash"},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t": {ColumnVindexes: []*vschemapb.ColumnVindex{{Name: "hash", Column: "id"}}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
+
+// newTestMaterializer returns the materialization of sql from the "src"
+// keyspace into the "dst" keyspace, whose tablets are served by tmc.
+func newTestMaterializer(t *testing.T, tmc tmclient.TabletManagerClient, sql string, extraArgs ...string) *Materializer {
+	if err := flag.Set("binlog_player_protocol", fakeBinlogPlayerProtocol); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	ts := newTestTopo(t)
+	w := &workflowpb.Workflow{Uuid: "materialize-test"}
+	args := append([]string{"-source_keyspace=src", "-target_keyspace=dst", "-sql=" + sql}, extraArgs...)
+	factory := &WorkflowFactory{}
+	if err := factory.Init(nil, w, args); err != nil {
+		t.Fatal(err)
+	}
+	wi, err := ts.CreateWorkflow(ctx, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootNode := workflow.NewNode()
+	rootNode.PathName = "materialize"
+	if err := workflow.NewNodeManager().AddRootNode(rootNode); err != nil {
+		t.Fatal(err)
+	}
+	ww, err := factory.Instantiate(nil, w, rootNode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mz := ww.(*Materializer)
+	mz.wi = wi
+	mz.topoServer = ts
+	mz.tabletClient = tmc
+	return mz
+}
+
+// prepare resolves the source table and the target shards, like run.
+func prepare(t *testing.T, mz *Materializer) {
+	ctx := context.Background()
+	if err := mz.createShardStreams(ctx); err != nil {
+		t.Fatal(err)
+	}
+	td, err := mz.getSourceTableDefinition(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mz.m, err = newMaterialization(mz.sel, mz.data.TargetTable, td.Columns, td.PrimaryKeyColumns); err != nil {
+		t.Fatal(err)
+	}
+	if mz.router, err = newRouter(ctx, mz.topoServer, mz.data.TargetKeyspace, mz.m, mz.data.AllShards); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setFlag sets a flag and returns a function restoring its value.
+func setFlag(t *testing.T, name, value string) func() {
+	saved := flag.Lookup(name).Value.String()
+	if err := flag.Set(name, value); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		flag.Set(name, saved)
+	}
+}
+
+func TestMaterializerInitErrors(t *testing.T) {
+	testcases := []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"-source_keyspace=src", "-sql=select * from t"},
+		err:  "must be provided",
+	}, {
+		args: []string{"-source_keyspace=src", "-target_keyspace=dst", "-sql=select * from t", "-source_tablet_type=bogus"},
+		err:  "unknown TabletType",
+	}, {
+		args: []string{"-source_keyspace=src", "-target_keyspace=src", "-sql=select * from t"},
+		err:  "cannot materialize table t into itself",
+	}}
+	for _, tcase := range testcases {
+		err := (&WorkflowFactory{}).Init(nil, &workflowpb.Workflow{}, tcase.args)
+		if err == nil || !strings.Contains(err.Error(), tcase.err) {
+			t.Errorf("Init(%v): %v, want %v", tcase.args, err, tcase.err)
+		}
+	}
+}
+
+func TestMaterializerWriteRows(t *testing.T) {
+	tmc := newFakeTabletManagerClient(nil, testPosition(1))
+	mz := newTestMaterializer(t, tmc, "select * from t")
+	prepare(t, mz)
+
+	rows := [][]sqltypes.Value{
+		{sqltypes.NewInt64(1), sqltypes.NewVarChar("a")},
+		{sqltypes.NewInt64(4), sqltypes.NewVarChar("b")},
+	}
+	if err := mz.writeRows(context.Background(), rows); err != nil {
+		t.Fatal(err)
+	}
+	want := map[uint32][]string{
+		200: {"insert into t(id, name) values (1, 'a') on duplicate key update id = values(id), name = values(name)"},
+		210: {"insert into t(id, name) values (4, 'b') on duplicate key update id = values(id), name = values(name)"},
+	}
+	if !reflect.DeepEqual(tmc.queries, want) {
+		t.Errorf("queries: %v, want %v", tmc.queries, want)
+	}
+
+	tmc.reset()
+	pks := [][]sqltypes.Value{{sqltypes.NewInt64(1)}, {sqltypes.NewInt64(4)}}
+	keep := map[string]map[string]bool{"4": {"80-": true}}
+	if err := mz.deleteRows(context.Background(), pks, keep); err != nil {
+		t.Fatal(err)
+	}
+	want = map[uint32][]string{
+		200: {"delete from t where id in (1)"},
+	}
+	if !reflect.DeepEqual(tmc.queries, want) {
+		t.Errorf("queries: %v, want %v", tmc.queries, want)
+	}
+}
+
+func TestMaterializerExecuteOnTargetReparent(t *testing.T) {
+	tmc := newFakeTabletManagerClient(nil, testPosition(1))
+	mz := newTestMaterializer(t, tmc, "select * from t")
+	prepare(t, mz)
+	ctx := context.Background()
+
+	if err := mz.executeOnTarget(ctx, "-80", "select 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The shard is reparented: the write to the old master fails,
+	// and the next one goes to the new master.
+	addTestTablet(t, mz.topoServer, "dst", "-80", 201, topodatapb.TabletType_MASTER)
+	tmc.failing[200] = true
+	if err := mz.executeOnTarget(ctx, "-80", "select 2"); err == nil || !strings.Contains(err.Error(), "tablet 200 is not serving") {
+		t.Errorf("executeOnTarget on the old master: %v, want tablet 200 is not serving", err)
+	}
+	if err := mz.executeOnTarget(ctx, "-80", "select 3"); err != nil {
+		t.Fatal(err)
+	}
+	want := map[uint32][]string{
+		200: {"select 1"},
+		201: {"select 3"},
+	}
+	if !reflect.DeepEqual(tmc.queries, want) {
+		t.Errorf("queries: %v, want %v", tmc.queries, want)
+	}
+}
+
+func TestMaterializerRun(t *testing.T) {
+	defer setFlag(t, "materialize_chunk_size", "2")()
+	defer setFlag(t, "materialize_retry_delay", "10ms")()
+	tmc := newFakeTabletManagerClient(map[int64]string{1: "a", 2: "b", 3: "c"}, testPosition(2))
+	mz := newTestMaterializer(t, tmc, "select * from t")
+
+	// The materialization is stopped once the binlog stream was
+	// restarted after it ended.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	streams := 0
+	fakeBinlog.reset([]*binlogdatapb.BinlogTransaction{
+		testTransaction(3,
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_UPDATE, "update t set name = 'x' where id = 1 /* _stream t (id ) (1 ); */")),
+	}, func() {
+		streams++
+		if streams == 2 {
+			cancel()
+		}
+	})
+
+	done := make(chan error)
+	go func() {
+		done <- mz.run(ctx)
+	}()
+	select {
+	case err := <-done:
+		if err == nil || err.Error() != context.Canceled.Error() {
+			t.Errorf("run returned %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("materialization did not stop: %v", mz.shards[0].uiNode.Message)
+	}
+
+	if got, want := fakeBinlog.positions, []string{testPosition(2), testPosition(3)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("stream positions: %v, want %v", got, want)
+	}
+	wi, err := mz.topoServer.GetWorkflow(context.Background(), "materialize-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(wi.Data), `"Positions":{"0":"`+testPosition(2)+`"},"Copied":{"0":true}`; !strings.Contains(got, want) {
+		t.Errorf("saved workflow data: %v, want %v", got, want)
+	}
+	if got, want := mz.data.Positions["0"], testPosition(3); got != want {
+		t.Errorf("position: %v, want %v", got, want)
+	}
+	if got := len(tmc.targetQueries(200)) + len(tmc.targetQueries(210)); got == 0 {
+		t.Errorf("no row was written to the target shards")
+	}
+}
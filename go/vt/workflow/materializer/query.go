@@ -0,0 +1,311 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package materializer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// parseSourceQuery checks that sql is a SELECT that can be materialized,
+// and returns it with the name of the source table. The query must read
+// from a single table, and can only filter and project its rows: the
+// target rows must map one to one to the source rows.
+func parseSourceQuery(sql string) (*sqlparser.Select, string, error) {
+	stmt, err := sqlparser.Parse(sql)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot parse %q: %v", sql, err)
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok {
+		return nil, "", fmt.Errorf("materialization only supports SELECT statements, got: %q", sql)
+	}
+	if sel.Distinct != "" || sel.GroupBy != nil || sel.Having != nil || sel.OrderBy != nil || sel.Limit != nil || sel.Lock != "" {
+		return nil, "", fmt.Errorf("materialization only supports filtering and projecting the rows of a table: %q", sql)
+	}
+	if len(sel.From) != 1 {
+		return nil, "", fmt.Errorf("materialization only supports a single source table: %q", sql)
+	}
+	ate, ok := sel.From[0].(*sqlparser.AliasedTableExpr)
+	if !ok {
+		return nil, "", fmt.Errorf("materialization only supports a single source table: %q", sql)
+	}
+	tn, ok := ate.Expr.(sqlparser.TableName)
+	if !ok {
+		return nil, "", fmt.Errorf("materialization only supports a single source table: %q", sql)
+	}
+	if !tn.Qualifier.IsEmpty() {
+		return nil, "", fmt.Errorf("materialization does not support qualified table names: %q", sql)
+	}
+	if !ate.As.IsEmpty() {
+		return nil, "", fmt.Errorf("materialization does not support table aliases: %q", sql)
+	}
+	for _, expr := range sel.SelectExprs {
+		switch expr := expr.(type) {
+		case *sqlparser.StarExpr:
+			if !expr.TableName.IsEmpty() && expr.TableName.Name != tn.Name {
+				return nil, "", fmt.Errorf("unknown table in %v: %q", sqlparser.String(expr), sql)
+			}
+		case *sqlparser.AliasedExpr:
+			if _, ok := expr.Expr.(*sqlparser.ColName); !ok && expr.As.IsEmpty() {
+				return nil, "", fmt.Errorf("expression %v needs an alias naming the target column: %q", sqlparser.String(expr.Expr), sql)
+			}
+		default:
+			return nil, "", fmt.Errorf("unsupported select expression %v: %q", sqlparser.String(expr), sql)
+		}
+	}
+	return sel, tn.Name.String(), nil
+}
+
+// materialization generates the statements that copy the rows selected
+// by the source query from the source table to the target table.
+type materialization struct {
+	source sqlparser.TableIdent
+	target sqlparser.TableIdent
+	// selectExprs is the projection of the source query, with the stars
+	// expanded to the columns of the source table.
+	selectExprs sqlparser.SelectExprs
+	// where is the filter of the source query, nil if there is none.
+	where sqlparser.Expr
+	// columns are the target columns, in the order of selectExprs.
+	columns sqlparser.Columns
+	// pkColumns are the primary key columns of the source table.
+	pkColumns []*sqlparser.ColName
+	// pkIndexes are the indexes in selectExprs of pkColumns.
+	pkIndexes []int
+}
+
+// newMaterialization returns the materialization of the source query to
+// the target table. sourceColumns and pkColumns are the columns and the
+// primary key of the source table. The primary key has to be copied as is
+// to the target table, as it identifies the target rows to update when
+// the source rows change.
+func newMaterialization(sel *sqlparser.Select, target string, sourceColumns, pkColumns []string) (*materialization, error) {
+	if len(pkColumns) == 0 {
+		return nil, fmt.Errorf("materialization requires the source table to have a primary key")
+	}
+	m := &materialization{
+		source: sel.From[0].(*sqlparser.AliasedTableExpr).Expr.(sqlparser.TableName).Name,
+		target: sqlparser.NewTableIdent(target),
+	}
+	if sel.Where != nil {
+		m.where = sel.Where.Expr
+	}
+	for _, expr := range sel.SelectExprs {
+		switch expr := expr.(type) {
+		case *sqlparser.StarExpr:
+			for _, col := range sourceColumns {
+				m.selectExprs = append(m.selectExprs, &sqlparser.AliasedExpr{Expr: &sqlparser.ColName{Name: sqlparser.NewColIdent(col)}})
+				m.columns = append(m.columns, sqlparser.NewColIdent(col))
+			}
+		case *sqlparser.AliasedExpr:
+			m.selectExprs = append(m.selectExprs, expr)
+			if !expr.As.IsEmpty() {
+				m.columns = append(m.columns, expr.As)
+			} else {
+				m.columns = append(m.columns, expr.Expr.(*sqlparser.ColName).Name)
+			}
+		}
+	}
+	for i, col := range m.columns {
+		for _, other := range m.columns[:i] {
+			if col.Equal(other) {
+				return nil, fmt.Errorf("target column %v is selected more than once", col)
+			}
+		}
+	}
+
+	for _, pk := range pkColumns {
+		index := -1
+		for i, expr := range m.selectExprs {
+			if col, ok := expr.(*sqlparser.AliasedExpr).Expr.(*sqlparser.ColName); ok && col.Qualifier.IsEmpty() && col.Name.EqualString(pk) {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			return nil, fmt.Errorf("the primary key column %v of table %v must be selected", pk, m.source.String())
+		}
+		m.pkColumns = append(m.pkColumns, &sqlparser.ColName{Name: sqlparser.NewColIdent(pk)})
+		m.pkIndexes = append(m.pkIndexes, index)
+	}
+	return m, nil
+}
+
+// targetPKColumns returns the target columns the source primary key is copied to.
+func (m *materialization) targetPKColumns() []sqlparser.ColIdent {
+	cols := make([]sqlparser.ColIdent, 0, len(m.pkIndexes))
+	for _, i := range m.pkIndexes {
+		cols = append(cols, m.columns[i])
+	}
+	return cols
+}
+
+// rowPK returns the source primary key of a row returned by the
+// materialization queries.
+func (m *materialization) rowPK(row []sqltypes.Value) []sqltypes.Value {
+	pk := make([]sqltypes.Value, 0, len(m.pkIndexes))
+	for _, i := range m.pkIndexes {
+		pk = append(pk, row[i])
+	}
+	return pk
+}
+
+// chunkQuery returns the query that selects the next size rows of the
+// source query in primary key order, starting right after lastPK. A nil
+// lastPK means the chunk starts at the beginning of the table.
+func (m *materialization) chunkQuery(lastPK []sqltypes.Value, size int) string {
+	cond := m.where
+	if lastPK != nil {
+		cond = and(cond, &sqlparser.ComparisonExpr{
+			Operator: sqlparser.GreaterThanStr,
+			Left:     pkExpr(m.pkColumns),
+			Right:    valuesExpr(lastPK),
+		})
+	}
+	var orderBy sqlparser.OrderBy
+	for _, col := range m.pkColumns {
+		orderBy = append(orderBy, &sqlparser.Order{Expr: col, Direction: sqlparser.AscScr})
+	}
+	return sqlparser.String(&sqlparser.Select{
+		SelectExprs: m.selectExprs,
+		From:        sqlparser.TableExprs{&sqlparser.AliasedTableExpr{Expr: sqlparser.TableName{Name: m.source}}},
+		Where:       sqlparser.NewWhere(sqlparser.WhereStr, cond),
+		OrderBy:     orderBy,
+		Limit:       &sqlparser.Limit{Rowcount: sqlparser.NewIntVal([]byte(strconv.Itoa(size)))},
+	})
+}
+
+// resyncQuery returns the query that selects the rows of the source
+// query with the given primary keys.
+func (m *materialization) resyncQuery(pks [][]sqltypes.Value) string {
+	cond := and(m.where, inExpr(m.pkColumns, pks))
+	return sqlparser.String(&sqlparser.Select{
+		SelectExprs: m.selectExprs,
+		From:        sqlparser.TableExprs{&sqlparser.AliasedTableExpr{Expr: sqlparser.TableName{Name: m.source}}},
+		Where:       sqlparser.NewWhere(sqlparser.WhereStr, cond),
+	})
+}
+
+// upsertQuery returns the statement that writes rows to the target
+// table, overwriting the rows that already exist. It is idempotent, so
+// the changes replayed from the binlogs can overlap with the copy.
+func (m *materialization) upsertQuery(rows [][]sqltypes.Value) string {
+	values := make(sqlparser.Values, 0, len(rows))
+	for _, row := range rows {
+		values = append(values, valuesExpr(row).(sqlparser.ValTuple))
+	}
+	onDup := make(sqlparser.OnDup, 0, len(m.columns))
+	for _, col := range m.columns {
+		onDup = append(onDup, &sqlparser.UpdateExpr{
+			Name: &sqlparser.ColName{Name: col},
+			Expr: &sqlparser.ValuesFuncExpr{Name: &sqlparser.ColName{Name: col}},
+		})
+	}
+	return sqlparser.String(&sqlparser.Insert{
+		Action:  sqlparser.InsertStr,
+		Table:   sqlparser.TableName{Name: m.target},
+		Columns: m.columns,
+		Rows:    values,
+		OnDup:   onDup,
+	})
+}
+
+// deleteQuery returns the statement that deletes the target rows copied
+// from the source rows with the given primary keys.
+func (m *materialization) deleteQuery(pks [][]sqltypes.Value) string {
+	var cols []*sqlparser.ColName
+	for _, col := range m.targetPKColumns() {
+		cols = append(cols, &sqlparser.ColName{Name: col})
+	}
+	return sqlparser.String(&sqlparser.Delete{
+		TableExprs: sqlparser.TableExprs{&sqlparser.AliasedTableExpr{Expr: sqlparser.TableName{Name: m.target}}},
+		Where:      sqlparser.NewWhere(sqlparser.WhereStr, inExpr(cols, pks)),
+	})
+}
+
+// and returns the conjunction of both conditions. left may be nil.
+func and(left, right sqlparser.Expr) sqlparser.Expr {
+	if left == nil {
+		return right
+	}
+	return &sqlparser.AndExpr{Left: &sqlparser.ParenExpr{Expr: left}, Right: right}
+}
+
+// pkExpr returns the column of a single column primary key, and the
+// tuple of its columns otherwise.
+func pkExpr(cols []*sqlparser.ColName) sqlparser.Expr {
+	if len(cols) == 1 {
+		return cols[0]
+	}
+	tuple := make(sqlparser.ValTuple, 0, len(cols))
+	for _, col := range cols {
+		tuple = append(tuple, col)
+	}
+	return tuple
+}
+
+// valuesExpr is the counterpart of pkExpr for the values of a row.
+func valuesExpr(values []sqltypes.Value) sqlparser.Expr {
+	tuple := make(sqlparser.ValTuple, 0, len(values))
+	for _, v := range values {
+		tuple = append(tuple, valueExpr(v))
+	}
+	if len(tuple) == 1 {
+		return tuple[0]
+	}
+	return tuple
+}
+
+// inExpr returns the condition matching the rows with the given primary keys.
+func inExpr(cols []*sqlparser.ColName, pks [][]sqltypes.Value) sqlparser.Expr {
+	tuple := make(sqlparser.ValTuple, 0, len(pks))
+	for _, pk := range pks {
+		tuple = append(tuple, valuesExpr(pk))
+	}
+	return &sqlparser.ComparisonExpr{
+		Operator: sqlparser.InStr,
+		Left:     pkExpr(cols),
+		Right:    tuple,
+	}
+}
+
+// valueExpr returns the literal of a value.
+func valueExpr(v sqltypes.Value) sqlparser.Expr {
+	expr, err := sqlparser.ExprFromValue(v)
+	if err != nil {
+		// The values read from MySQL are never expressions, but fall
+		// back to a string literal rather than losing the value.
+		return sqlparser.NewStrVal(v.ToBytes())
+	}
+	return expr
+}
+
+// pkKey returns a string identifying a primary key value.
+func pkKey(pk []sqltypes.Value) string {
+	parts := make([]string, 0, len(pk))
+	for _, v := range pk {
+		buf := sqlparser.NewTrackedBuffer(nil)
+		v.EncodeSQL(buf)
+		parts = append(parts, buf.String())
+	}
+	return strings.Join(parts, ",")
+}
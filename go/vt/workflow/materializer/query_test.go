@@ -0,0 +1,168 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package materializer
+
+import (
+	"strings"
+	"testing"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+func TestParseSourceQuery(t *testing.T) {
+	testcases := []struct {
+		sql   string
+		table string
+		err   string
+	}{{
+		sql:   "select * from t",
+		table: "t",
+	}, {
+		sql:   "select id, name as user_name, concat(a, b) as ab from t where region = 'eu'",
+		table: "t",
+	}, {
+		sql: "select id, a + 1 from t",
+		err: "needs an alias",
+	}, {
+		sql: "select id from t order by id",
+		err: "only supports filtering and projecting",
+	}, {
+		sql: "select id, count(*) as c from t group by id",
+		err: "only supports filtering and projecting",
+	}, {
+		sql: "select t.id from t join u on t.id = u.id",
+		err: "single source table",
+	}, {
+		sql: "select id from ks.t",
+		err: "qualified table names",
+	}, {
+		sql: "select u.* from t",
+		err: "unknown table",
+	}, {
+		sql: "delete from t",
+		err: "only supports SELECT",
+	}}
+	for _, tcase := range testcases {
+		_, table, err := parseSourceQuery(tcase.sql)
+		if tcase.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tcase.err) {
+				t.Errorf("parseSourceQuery(%q): %v, want %v", tcase.sql, err, tcase.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseSourceQuery(%q): %v", tcase.sql, err)
+			continue
+		}
+		if table != tcase.table {
+			t.Errorf("parseSourceQuery(%q): %v, want %v", tcase.sql, table, tcase.table)
+		}
+	}
+}
+
+func newTestMaterialization(t *testing.T, sql, target string, columns, pkColumns []string) *materialization {
+	sel, _, err := parseSourceQuery(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := newMaterialization(sel, target, columns, pkColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMaterializationQueries(t *testing.T) {
+	m := newTestMaterialization(t, "select name, id as user_id from user where deleted = 0", "name_user_idx", []string{"id", "name", "deleted"}, []string{"id"})
+
+	testcases := []struct {
+		got, want string
+	}{{
+		got:  m.chunkQuery(nil, 100),
+		want: "select name, id as user_id from user where deleted = 0 order by id asc limit 100",
+	}, {
+		got:  m.chunkQuery([]sqltypes.Value{sqltypes.NewInt64(10)}, 100),
+		want: "select name, id as user_id from user where (deleted = 0) and id > 10 order by id asc limit 100",
+	}, {
+		got:  m.resyncQuery([][]sqltypes.Value{{sqltypes.NewInt64(1)}, {sqltypes.NewInt64(2)}}),
+		want: "select name, id as user_id from user where (deleted = 0) and id in (1, 2)",
+	}, {
+		got:  m.upsertQuery([][]sqltypes.Value{{sqltypes.NewVarChar("a"), sqltypes.NewInt64(1)}}),
+		want: "insert into name_user_idx(name, user_id) values ('a', 1) on duplicate key update name = values(name), user_id = values(user_id)",
+	}, {
+		got:  m.deleteQuery([][]sqltypes.Value{{sqltypes.NewInt64(1)}, {sqltypes.NewInt64(2)}}),
+		want: "delete from name_user_idx where user_id in (1, 2)",
+	}}
+	for _, tcase := range testcases {
+		if tcase.got != tcase.want {
+			t.Errorf("got:\n%v\nwant:\n%v", tcase.got, tcase.want)
+		}
+	}
+}
+
+func TestMaterializationCompositePK(t *testing.T) {
+	m := newTestMaterialization(t, "select * from t", "t_copy", []string{"a", "b", "c"}, []string{"a", "b"})
+
+	row := []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("x"), sqltypes.NULL}
+	if got, want := pkKey(m.rowPK(row)), "1,'x'"; got != want {
+		t.Errorf("rowPK: %v, want %v", got, want)
+	}
+	pk := m.rowPK(row)
+	testcases := []struct {
+		got, want string
+	}{{
+		got:  m.chunkQuery(pk, 10),
+		want: "select a, b, c from t where (a, b) > (1, 'x') order by a asc, b asc limit 10",
+	}, {
+		got:  m.deleteQuery([][]sqltypes.Value{pk}),
+		want: "delete from t_copy where (a, b) in ((1, 'x'))",
+	}, {
+		got:  m.upsertQuery([][]sqltypes.Value{row}),
+		want: "insert into t_copy(a, b, c) values (1, 'x', null) on duplicate key update a = values(a), b = values(b), c = values(c)",
+	}}
+	for _, tcase := range testcases {
+		if tcase.got != tcase.want {
+			t.Errorf("got:\n%v\nwant:\n%v", tcase.got, tcase.want)
+		}
+	}
+}
+
+func TestMaterializationErrors(t *testing.T) {
+	testcases := []struct {
+		sql string
+		err string
+	}{{
+		sql: "select name from user",
+		err: "primary key column id of table user must be selected",
+	}, {
+		sql: "select id + 1 as id, name from user",
+		err: "primary key column id of table user must be selected",
+	}, {
+		sql: "select id, name, name as id from user",
+		err: "selected more than once",
+	}}
+	for _, tcase := range testcases {
+		sel, _, err := parseSourceQuery(tcase.sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = newMaterialization(sel, "target", []string{"id", "name"}, []string{"id"})
+		if err == nil || !strings.Contains(err.Error(), tcase.err) {
+			t.Errorf("newMaterialization(%q): %v, want %v", tcase.sql, err, tcase.err)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package materializer
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/throttler"
+	"vitess.io/vitess/go/vt/workflow"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// shardStream materializes the rows of a single source shard.
+//
+// The position of a source tablet is recorded, then the rows selected by
+// the source query are copied in chunks to the target shards, and the
+// changes made to the source table since the recorded position are read
+// from the binlogs of the tablet. For each changed row, the source query
+// is run again for its primary key: the target row is deleted if the
+// source row was deleted or does not match the filter anymore, and
+// written again otherwise. Since this only depends on the current state
+// of the source rows, replaying changes that were already copied is
+// harmless.
+type shardStream struct {
+	parent *Materializer
+	shard  string
+
+	// uiNode is the UI node representing this shard.
+	uiNode *workflow.Node
+	// uiLogger is the logger collecting logs that will be displayed
+	// in the UI for this shard.
+	uiLogger *logutil.MemoryLogger
+
+	// tablet is the source tablet the rows are read from.
+	tablet *topodatapb.Tablet
+	// rowCount is the approximate number of rows in the source table.
+	rowCount uint64
+	// rowsCopied is the number of rows copied so far.
+	rowsCopied uint64
+	// rowsResynced is the number of changed rows replayed so far.
+	rowsResynced uint64
+}
+
+// run materializes the rows of the shard until ctx is canceled. The
+// errors are logged and the materialization is resumed after a delay,
+// from the last checkpointed position.
+func (ss *shardStream) run(ctx context.Context) error {
+	for {
+		err := ss.runOnce(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		ss.setUIMessage(fmt.Sprintf("Error: %v, resuming in %v", err, *retryDelay))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(*retryDelay):
+		}
+	}
+}
+
+func (ss *shardStream) runOnce(ctx context.Context) error {
+	if err := ss.pickTablet(ctx); err != nil {
+		return err
+	}
+	position, copied := ss.parent.progress(ss.shard)
+	if !copied {
+		// The position is only recorded before the first copy: the
+		// changes since then are replayed even if the copy has to be
+		// restarted, so the rows deleted in the meantime are deleted
+		// from the target too.
+		if position == "" {
+			pos, err := ss.tabletPosition(ctx)
+			if err != nil {
+				return err
+			}
+			position = mysql.EncodePosition(pos)
+			if err := ss.parent.checkpoint(ctx, ss.shard, position, false /* copied */, true /* force */); err != nil {
+				return err
+			}
+		}
+		if err := ss.copyRows(ctx); err != nil {
+			return err
+		}
+		if err := ss.parent.checkpoint(ctx, ss.shard, position, true /* copied */, true /* force */); err != nil {
+			return err
+		}
+	}
+	return ss.replay(ctx, position)
+}
+
+// pickTablet picks a random serving tablet of the source tablet type.
+func (ss *shardStream) pickTablet(ctx context.Context) error {
+	tablets, err := ss.parent.topoServer.GetTabletMapForShard(ctx, ss.parent.data.SourceKeyspace, ss.shard)
+	if err != nil {
+		return err
+	}
+	var candidates []*topodatapb.Tablet
+	for _, ti := range tablets {
+		if ti.Type == ss.parent.tabletType {
+			candidates = append(candidates, ti.Tablet)
+		}
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("no %v tablet in shard %v/%v", ss.parent.tabletType, ss.parent.data.SourceKeyspace, ss.shard)
+	}
+	ss.tablet = candidates[rand.Intn(len(candidates))]
+	return nil
+}
+
+func (ss *shardStream) tabletPosition(ctx context.Context) (mysql.Position, error) {
+	var pos string
+	if ss.tablet.Type == topodatapb.TabletType_MASTER {
+		var err error
+		pos, err = ss.parent.tabletClient.MasterPosition(ctx, ss.tablet)
+		if err != nil {
+			return mysql.Position{}, err
+		}
+	} else {
+		status, err := ss.parent.tabletClient.SlaveStatus(ctx, ss.tablet)
+		if err != nil {
+			return mysql.Position{}, err
+		}
+		pos = status.Position
+	}
+	return mysql.DecodePosition(pos)
+}
+
+// copyRows copies the rows selected by the source query to the target
+// shards, one chunk at a time, at the rate allowed by the throttler.
+func (ss *shardStream) copyRows(ctx context.Context) error {
+	ss.setUIMessage("Copying rows to the target table")
+	name := fmt.Sprintf("Materialize-%v-%v-%v", ss.parent.data.SourceKeyspace, ss.shard, ss.parent.table)
+	t, err := throttler.NewThrottler(name, "chunks", 1 /* threadCount */, *maxRate, throttler.ReplicationLagModuleDisabled)
+	if err != nil {
+		return err
+	}
+	defer t.Close()
+
+	if sd, err := ss.parent.tabletClient.GetSchema(ctx, ss.tablet, []string{ss.parent.table}, nil /* excludeTables */, false /* includeViews */); err == nil && len(sd.TableDefinitions) == 1 {
+		ss.rowCount = sd.TableDefinitions[0].RowCount
+	}
+	ss.rowsCopied = 0
+	ss.uiNode.Display = workflow.NodeDisplayDeterminate
+	var lastPK []sqltypes.Value
+	for {
+		if err := t.Wait(ctx, 0 /* threadID */); err != nil {
+			return err
+		}
+		qr, err := ss.execute(ctx, ss.parent.m.chunkQuery(lastPK, *chunkSize), *chunkSize)
+		if err != nil {
+			return err
+		}
+		if len(qr.Rows) == 0 {
+			break
+		}
+		if err := ss.parent.writeRows(ctx, qr.Rows); err != nil {
+			return err
+		}
+		lastPK = ss.parent.m.rowPK(qr.Rows[len(qr.Rows)-1])
+		ss.rowsCopied += uint64(len(qr.Rows))
+		ss.updateProgress()
+	}
+	ss.uiNode.Progress = 100
+	ss.uiNode.ProgressMessage = fmt.Sprintf("%v rows copied", ss.rowsCopied)
+	ss.uiNode.Display = workflow.NodeDisplayNone
+	ss.uiNode.BroadcastChanges(false /* updateChildren */)
+	return nil
+}
+
+// replay applies the changes of the source table read from the binlogs
+// of the tablet, starting at position, until ctx is canceled or the
+// stream fails. The transactions received while a batch is applied are
+// applied together in the next one.
+func (ss *shardStream) replay(ctx context.Context, position string) error {
+	ss.setUIMessage("Replaying the changes of the source table")
+	client, err := binlogplayer.NewClient()
+	if err != nil {
+		return err
+	}
+	if err := client.Dial(ss.tablet); err != nil {
+		return fmt.Errorf("error dialing binlog server: %v", err)
+	}
+	defer client.Close()
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := client.StreamTables(streamCtx, position, []string{ss.parent.table}, nil)
+	if err != nil {
+		return fmt.Errorf("error sending streaming query to binlog server: %v", err)
+	}
+
+	txs := make(chan *binlogdatapb.BinlogTransaction, *chunkSize)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(txs)
+		for {
+			tx, err := stream.Recv()
+			if err != nil {
+				if err == io.EOF {
+					err = fmt.Errorf("binlog stream ended")
+				}
+				errs <- err
+				return
+			}
+			select {
+			case txs <- tx:
+			case <-streamCtx.Done():
+				errs <- streamCtx.Err()
+				return
+			}
+		}
+	}()
+
+	pending := make(map[string][]sqltypes.Value)
+	var insertid int64
+	for tx := range txs {
+		pos, err := ss.addChanges(tx, pending, &insertid)
+		if err != nil {
+			return err
+		}
+		// Batch the transactions that are already received.
+	batch:
+		for len(pending) < *chunkSize {
+			select {
+			case tx, ok := <-txs:
+				if !ok {
+					break batch
+				}
+				p, err := ss.addChanges(tx, pending, &insertid)
+				if err != nil {
+					return err
+				}
+				if p != "" {
+					pos = p
+				}
+			default:
+				break batch
+			}
+		}
+
+		if err := ss.resync(ctx, pending); err != nil {
+			return err
+		}
+		pending = make(map[string][]sqltypes.Value)
+		if pos != "" {
+			position = pos
+			if err := ss.parent.checkpoint(ctx, ss.shard, position, true /* copied */, false /* force */); err != nil {
+				return err
+			}
+		}
+		ss.uiNode.ProgressMessage = fmt.Sprintf("%v changed rows replayed, at position %v", ss.rowsResynced, position)
+		ss.uiNode.BroadcastChanges(false /* updateChildren */)
+	}
+	return <-errs
+}
+
+// addChanges adds to pending the primary keys of the source rows changed
+// by a transaction, and returns the position of the transaction.
+func (ss *shardStream) addChanges(tx *binlogdatapb.BinlogTransaction, pending map[string][]sqltypes.Value, insertid *int64) (string, error) {
+	pkColumns := make([]string, 0, len(ss.parent.m.pkColumns))
+	for _, col := range ss.parent.m.pkColumns {
+		pkColumns = append(pkColumns, col.Name.String())
+	}
+	for _, stmt := range tx.Statements {
+		switch stmt.Category {
+		case binlogdatapb.BinlogTransaction_Statement_BL_SET:
+			if id, ok := binlog.ParseInsertID(string(stmt.Sql)); ok {
+				*insertid = id
+			}
+		case binlogdatapb.BinlogTransaction_Statement_BL_INSERT,
+			binlogdatapb.BinlogTransaction_Statement_BL_UPDATE,
+			binlogdatapb.BinlogTransaction_Statement_BL_DELETE:
+			pks, nextid, err := changedRows(string(stmt.Sql), ss.parent.table, pkColumns, *insertid)
+			if err != nil {
+				return "", fmt.Errorf("cannot replay statement %q: %v", stmt.Sql, err)
+			}
+			*insertid = nextid
+			for _, pk := range pks {
+				pending[pkKey(pk)] = pk
+			}
+		}
+	}
+	if tx.EventToken == nil {
+		return "", nil
+	}
+	return tx.EventToken.Position, nil
+}
+
+// resync makes the target rows of the given source primary keys
+// identical to the current result of the source query for them.
+func (ss *shardStream) resync(ctx context.Context, pending map[string][]sqltypes.Value) error {
+	if len(pending) == 0 {
+		return nil
+	}
+	pks := make([][]sqltypes.Value, 0, len(pending))
+	for _, pk := range pending {
+		pks = append(pks, pk)
+	}
+	qr, err := ss.execute(ctx, ss.parent.m.resyncQuery(pks), len(pks))
+	if err != nil {
+		return err
+	}
+
+	// The rows that still exist are overwritten in the shards they
+	// belong to, and deleted from the other ones in case their
+	// primary vindex column changed.
+	keep := make(map[string]map[string]bool)
+	for _, row := range qr.Rows {
+		shards, err := ss.parent.router.rowShards(row)
+		if err != nil {
+			return err
+		}
+		key := pkKey(ss.parent.m.rowPK(row))
+		keep[key] = make(map[string]bool)
+		for _, shard := range shards {
+			keep[key][shard] = true
+		}
+	}
+	if err := ss.parent.deleteRows(ctx, pks, keep); err != nil {
+		return err
+	}
+	if len(qr.Rows) != 0 {
+		if err := ss.parent.writeRows(ctx, qr.Rows); err != nil {
+			return err
+		}
+	}
+	ss.rowsResynced += uint64(len(pks))
+	return nil
+}
+
+// execute runs a query on the source tablet.
+func (ss *shardStream) execute(ctx context.Context, sql string, maxRows int) (*sqltypes.Result, error) {
+	qr, err := ss.parent.tabletClient.ExecuteFetchAsApp(ctx, ss.tablet, true /* usePool */, []byte(sql), maxRows)
+	if err != nil {
+		return nil, fmt.Errorf("%v failed on shard %v/%v: %v", sql, ss.parent.data.SourceKeyspace, ss.shard, err)
+	}
+	return sqltypes.Proto3ToResult(qr), nil
+}
+
+func (ss *shardStream) updateProgress() {
+	if ss.rowCount > 0 {
+		progress := int(ss.rowsCopied * 100 / ss.rowCount)
+		if progress > 99 {
+			progress = 99
+		}
+		ss.uiNode.Progress = progress
+	}
+	ss.uiNode.ProgressMessage = fmt.Sprintf("%v/~%v rows copied", ss.rowsCopied, ss.rowCount)
+	ss.uiNode.BroadcastChanges(false /* updateChildren */)
+}
+
+func (ss *shardStream) setUIMessage(message string) {
+	log.Infof("Materialization of %v/%v: %v", ss.parent.data.SourceKeyspace, ss.shard, message)
+	ss.uiLogger.Infof("%v", message)
+	ss.uiNode.Log = ss.uiLogger.String()
+	ss.uiNode.Message = message
+	ss.uiNode.BroadcastChanges(false /* updateChildren */)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package materializer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+func TestShardStreamCopyRows(t *testing.T) {
+	defer setFlag(t, "materialize_chunk_size", "2")()
+	tmc := newFakeTabletManagerClient(map[int64]string{1: "a", 2: "b", 3: "c", 4: "d", 5: "e"}, testPosition(1))
+	mz := newTestMaterializer(t, tmc, "select * from t")
+	prepare(t, mz)
+	ss := mz.shards[0]
+	if err := ss.pickTablet(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ss.tablet.Alias.Uid, uint32(101); got != want {
+		t.Errorf("source tablet: %v, want %v", got, want)
+	}
+
+	if err := ss.copyRows(context.Background()); err != nil {
+		t.Fatalf("copyRows failed: %v", err)
+	}
+	want := map[uint32][]string{
+		200: {
+			"insert into t(id, name) values (1, 'a'), (2, 'b') on duplicate key update id = values(id), name = values(name)",
+			"insert into t(id, name) values (3, 'c') on duplicate key update id = values(id), name = values(name)",
+			"insert into t(id, name) values (5, 'e') on duplicate key update id = values(id), name = values(name)",
+		},
+		210: {
+			"insert into t(id, name) values (4, 'd') on duplicate key update id = values(id), name = values(name)",
+		},
+	}
+	if !reflect.DeepEqual(tmc.queries, want) {
+		t.Errorf("queries:\n%v, want\n%v", tmc.queries, want)
+	}
+	if got, want := ss.rowsCopied, uint64(5); got != want {
+		t.Errorf("rowsCopied: %v, want %v", got, want)
+	}
+	if got, want := ss.uiNode.Progress, 100; got != want {
+		t.Errorf("progress: %v, want %v", got, want)
+	}
+}
+
+func TestShardStreamReplay(t *testing.T) {
+	tmc := newFakeTabletManagerClient(map[int64]string{1: "x", 2: "b", 6: "f"}, testPosition(1))
+	mz := newTestMaterializer(t, tmc, "select * from t")
+	prepare(t, mz)
+	ss := mz.shards[0]
+	if err := ss.pickTablet(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Row 1 is updated, row 4 deleted and row 6 inserted with an auto
+	// increment id. The changes of the other tables are ignored.
+	fakeBinlog.reset([]*binlogdatapb.BinlogTransaction{
+		testTransaction(2,
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_UPDATE, "update t set name = 'x' where id = 1 /* _stream t (id ) (1 ); */"),
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_UPDATE, "update u set name = 'y' where id = 7 /* _stream u (id ) (7 ); */")),
+		testTransaction(3,
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_DELETE, "delete from t where id = 4 /* _stream t (id ) (4 ); */")),
+		testTransaction(4,
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_SET, "SET INSERT_ID=6"),
+			testStatement(binlogdatapb.BinlogTransaction_Statement_BL_INSERT, "insert into t(name) values ('f') /* _stream t (id ) (null ); */")),
+	}, nil)
+
+	err := ss.replay(context.Background(), testPosition(1))
+	if err == nil || err.Error() != "binlog stream ended" {
+		t.Fatalf("replay returned %v, want binlog stream ended", err)
+	}
+	if got, want := fakeBinlog.positions, []string{testPosition(1)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("stream positions: %v, want %v", got, want)
+	}
+	// The transactions may be batched differently, so only the final
+	// statements of each row are checked.
+	for _, tcase := range []struct {
+		uid  uint32
+		want string
+	}{{
+		uid:  200,
+		want: "insert into t(id, name) values (1, 'x') on duplicate key update id = values(id), name = values(name)",
+	}, {
+		uid:  210,
+		want: "delete from t where id in (4)",
+	}, {
+		uid:  210,
+		want: "insert into t(id, name) values (6, 'f') on duplicate key update id = values(id), name = values(name)",
+	}} {
+		queries := strings.Join(tmc.targetQueries(tcase.uid), "\n")
+		if !strings.Contains(queries, tcase.want) {
+			t.Errorf("queries on tablet %v:\n%v\nwant %v", tcase.uid, queries, tcase.want)
+		}
+	}
+	if got, want := ss.rowsResynced, uint64(3); got != want {
+		t.Errorf("rowsResynced: %v, want %v", got, want)
+	}
+	if got, want := mz.data.Positions["0"], testPosition(4); got != want {
+		t.Errorf("position: %v, want %v", got, want)
+	}
+}
+
+func TestShardStreamResyncMovedRow(t *testing.T) {
+	tmc := newFakeTabletManagerClient(map[int64]string{4: "d"}, testPosition(1))
+	mz := newTestMaterializer(t, tmc, "select * from t")
+	prepare(t, mz)
+	ss := mz.shards[0]
+	if err := ss.pickTablet(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a primary vindex on the primary key, the rows are deleted
+	// from all the shards but the one they are written to.
+	mz.router.vindexPK = -1
+	pending := map[string][]sqltypes.Value{
+		"1": {sqltypes.NewInt64(1)},
+		"4": {sqltypes.NewInt64(4)},
+	}
+	if err := ss.resync(context.Background(), pending); err != nil {
+		t.Fatal(err)
+	}
+	want := map[uint32][]string{
+		200: {"delete from t where id in (1, 4)"},
+		210: {
+			"delete from t where id in (1)",
+			"insert into t(id, name) values (4, 'd') on duplicate key update id = values(id), name = values(name)",
+		},
+	}
+	if !reflect.DeepEqual(tmc.queries, want) {
+		t.Errorf("queries:\n%v, want\n%v", tmc.queries, want)
+	}
+}
+
+func TestShardStreamAddChangesErrors(t *testing.T) {
+	tmc := newFakeTabletManagerClient(nil, testPosition(1))
+	mz := newTestMaterializer(t, tmc, "select * from t")
+	prepare(t, mz)
+	ss := mz.shards[0]
+
+	tx := testTransaction(2,
+		testStatement(binlogdatapb.BinlogTransaction_Statement_BL_UPDATE, "update t set name = 'x' where id = 1 /* _stream t (id ) (1 */"))
+	var insertid int64
+	_, err := ss.addChanges(tx, make(map[string][]sqltypes.Value), &insertid)
+	if err == nil || !strings.Contains(err.Error(), "cannot replay statement") {
+		t.Errorf("addChanges returned %v, want cannot replay statement", err)
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package materializer
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// router maps the target rows to the shards of the target keyspace.
+type router struct {
+	ts       *topo.Server
+	keyspace string
+	// shards are all the shards of the target keyspace.
+	shards []*topodatapb.ShardReference
+
+	// mu protects masters.
+	mu sync.Mutex
+	// masters are the master tablets of the shards, by shard name.
+	// They are resolved from the topology when first needed, and
+	// resolved again after a write to them failed, in case the
+	// shard was reparented.
+	masters map[string]*topodatapb.Tablet
+
+	// vindex is the primary vindex of the target table. It is nil if
+	// the rows are copied to all the shards.
+	vindex vindexes.Vindex
+	// vindexColumn is the index of the column of the primary vindex
+	// in the target columns.
+	vindexColumn int
+	// vindexPK is the index of the column of the primary vindex in
+	// the source primary key, or -1 if it is not part of it. When it is,
+	// the rows to delete can be routed to a single shard.
+	vindexPK int
+}
+
+// newRouter returns the router for the rows of m. The rows are copied to
+// all the shards of the target keyspace if allShards is set or if the
+// keyspace is not sharded, and to the shard of their keyspace id computed
+// by the primary vindex of the target table otherwise.
+func newRouter(ctx context.Context, ts *topo.Server, keyspace string, m *materialization, allShards bool) (*router, error) {
+	r := &router{
+		ts:       ts,
+		keyspace: keyspace,
+		masters:  make(map[string]*topodatapb.Tablet),
+		vindexPK: -1,
+	}
+	shards, err := ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("keyspace %v has no shard", keyspace)
+	}
+	for _, shard := range shards {
+		si, err := ts.GetShard(ctx, keyspace, shard)
+		if err != nil {
+			return nil, err
+		}
+		r.shards = append(r.shards, &topodatapb.ShardReference{Name: shard, KeyRange: si.KeyRange})
+	}
+	if allShards {
+		return r, nil
+	}
+
+	vs, err := ts.GetVSchema(ctx, keyspace)
+	switch {
+	case err == topo.ErrNoNode:
+		return r, nil
+	case err != nil:
+		return nil, err
+	case !vs.Sharded:
+		return r, nil
+	}
+	table := m.target.String()
+	vt, ok := vs.Tables[table]
+	if !ok || len(vt.ColumnVindexes) == 0 {
+		return nil, fmt.Errorf("table %v has no primary vindex in the vschema of keyspace %v", table, keyspace)
+	}
+	cv := vt.ColumnVindexes[0]
+	column := cv.Column
+	if len(cv.Columns) > 1 {
+		return nil, fmt.Errorf("the primary vindex of table %v cannot have more than one column", table)
+	} else if len(cv.Columns) == 1 {
+		column = cv.Columns[0]
+	}
+	vdef, ok := vs.Vindexes[cv.Name]
+	if !ok {
+		return nil, fmt.Errorf("vindex %v of table %v is not defined in the vschema of keyspace %v", cv.Name, table, keyspace)
+	}
+	r.vindex, err = vindexes.CreateVindex(vdef.Type, cv.Name, vdef.Params)
+	if err != nil {
+		return nil, err
+	}
+	if !r.vindex.IsFunctional() {
+		return nil, fmt.Errorf("the primary vindex %v of table %v must be functional", cv.Name, table)
+	}
+
+	r.vindexColumn = -1
+	for i, col := range m.columns {
+		if col.EqualString(column) {
+			r.vindexColumn = i
+		}
+	}
+	if r.vindexColumn == -1 {
+		return nil, fmt.Errorf("the column %v of the primary vindex of table %v must be selected", column, table)
+	}
+	for i, index := range m.pkIndexes {
+		if index == r.vindexColumn {
+			r.vindexPK = i
+		}
+	}
+	return r, nil
+}
+
+// master returns the master tablet of a target shard, and resolves it
+// from the topology if it is not known yet.
+func (r *router) master(ctx context.Context, shard string) (*topodatapb.Tablet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if tablet, ok := r.masters[shard]; ok {
+		return tablet, nil
+	}
+	si, err := r.ts.GetShard(ctx, r.keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	if !si.HasMaster() {
+		return nil, fmt.Errorf("shard %v/%v has no master", r.keyspace, shard)
+	}
+	ti, err := r.ts.GetTablet(ctx, si.MasterAlias)
+	if err != nil {
+		return nil, err
+	}
+	r.masters[shard] = ti.Tablet
+	return ti.Tablet, nil
+}
+
+// masterFailed forgets the master tablet of a target shard after a write
+// to it failed, so it is resolved again from the topology by the next
+// write. tablet is the master the write was sent to: the cached master
+// is kept if it was already resolved again in the meantime.
+func (r *router) masterFailed(shard string, tablet *topodatapb.Tablet) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.masters[shard] == tablet {
+		delete(r.masters, shard)
+	}
+}
+
+// allShards returns the names of all the target shards.
+func (r *router) allShards() []string {
+	names := make([]string, 0, len(r.shards))
+	for _, shard := range r.shards {
+		names = append(names, shard.Name)
+	}
+	return names
+}
+
+// rowShards returns the shards a target row is written to.
+func (r *router) rowShards(row []sqltypes.Value) ([]string, error) {
+	if r.vindex == nil {
+		return r.allShards(), nil
+	}
+	return r.resolve(row[r.vindexColumn])
+}
+
+// pkShards returns the shards the target row copied from the source row
+// with the given primary key may be in.
+func (r *router) pkShards(pk []sqltypes.Value) ([]string, error) {
+	if r.vindex == nil || r.vindexPK == -1 {
+		return r.allShards(), nil
+	}
+	return r.resolve(pk[r.vindexPK])
+}
+
+func (r *router) resolve(id sqltypes.Value) ([]string, error) {
+	destinations, err := r.vindex.Map(nil, []sqltypes.Value{id})
+	if err != nil {
+		return nil, err
+	}
+	var shards []string
+	if err := destinations[0].Resolve(r.shards, func(shard string) error {
+		shards = append(shards, shard)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("cannot route %v to a shard of keyspace %v: %v", id, r.keyspace, err)
+	}
+	return shards, nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package materializer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+// newTestRouter returns the router of the rows selected by sql to a
+// keyspace of newTestTopo. vschema replaces the vschema of the keyspace
+// if it is set.
+func newTestRouter(t *testing.T, sql, keyspace string, allShards bool, vschema *vschemapb.Keyspace) (*router, error) {
+	ctx := context.Background()
+	ts := newTestTopo(t)
+	if vschema != nil {
+		if err := ts.SaveVSchema(ctx, keyspace, vschema); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sel, _, err := parseSourceQuery(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := newMaterialization(sel, "t", []string{"id", "name"}, []string{"id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newRouter(ctx, ts, keyspace, m, allShards)
+}
+
+func TestRouter(t *testing.T) {
+	testcases := []struct {
+		name      string
+		sql       string
+		keyspace  string
+		allShards bool
+		vschema   *vschemapb.Keyspace
+		// row is routed to rowShards, and its source primary key,
+		// 4, to pkShards.
+		row       []sqltypes.Value
+		rowShards []string
+		pkShards  []string
+	}{{
+		name:      "primary vindex",
+		sql:       "select * from t",
+		keyspace:  "dst",
+		row:       []sqltypes.Value{sqltypes.NewInt64(4), sqltypes.NewVarChar("a")},
+		rowShards: []string{"80-"},
+		pkShards:  []string{"80-"},
+	}, {
+		name:      "reordered columns",
+		sql:       "select name, id from t",
+		keyspace:  "dst",
+		row:       []sqltypes.Value{sqltypes.NewVarChar("a"), sqltypes.NewInt64(4)},
+		rowShards: []string{"80-"},
+		pkShards:  []string{"80-"},
+	}, {
+		name:     "primary vindex not on the primary key",
+		sql:      "select * from t",
+		keyspace: "dst",
+		vschema: &vschemapb.Keyspace{
+			Sharded:  true,
+			Vindexes: map[string]*vschemapb.Vindex{"hash": {Type: "hash"}},
+			Tables: map[string]*vschemapb.Table{
+				"t": {ColumnVindexes: []*vschemapb.ColumnVindex{{Name: "hash", Columns: []string{"name"}}}},
+			},
+		},
+		row:       []sqltypes.Value{sqltypes.NewInt64(4), sqltypes.NewInt64(1)},
+		rowShards: []string{"-80"},
+		pkShards:  []string{"-80", "80-"},
+	}, {
+		name:      "all shards",
+		sql:       "select * from t",
+		keyspace:  "dst",
+		allShards: true,
+		row:       []sqltypes.Value{sqltypes.NewInt64(4), sqltypes.NewVarChar("a")},
+		rowShards: []string{"-80", "80-"},
+		pkShards:  []string{"-80", "80-"},
+	}, {
+		name:      "unsharded keyspace",
+		sql:       "select * from t",
+		keyspace:  "src",
+		row:       []sqltypes.Value{sqltypes.NewInt64(4), sqltypes.NewVarChar("a")},
+		rowShards: []string{"0"},
+		pkShards:  []string{"0"},
+	}}
+	for _, tcase := range testcases {
+		r, err := newTestRouter(t, tcase.sql, tcase.keyspace, tcase.allShards, tcase.vschema)
+		if err != nil {
+			t.Errorf("%v: newRouter failed: %v", tcase.name, err)
+			continue
+		}
+		shards, err := r.rowShards(tcase.row)
+		if err != nil {
+			t.Errorf("%v: rowShards failed: %v", tcase.name, err)
+		} else if !reflect.DeepEqual(shards, tcase.rowShards) {
+			t.Errorf("%v: rowShards: %v, want %v", tcase.name, shards, tcase.rowShards)
+		}
+		shards, err = r.pkShards([]sqltypes.Value{sqltypes.NewInt64(4)})
+		if err != nil {
+			t.Errorf("%v: pkShards failed: %v", tcase.name, err)
+		} else if !reflect.DeepEqual(shards, tcase.pkShards) {
+			t.Errorf("%v: pkShards: %v, want %v", tcase.name, shards, tcase.pkShards)
+		}
+	}
+}
+
+func TestRouterErrors(t *testing.T) {
+	testcases := []struct {
+		name    string
+		sql     string
+		vschema *vschemapb.Keyspace
+		err     string
+	}{{
+		name:    "missing table",
+		sql:     "select * from t",
+		vschema: &vschemapb.Keyspace{Sharded: true},
+		err:     "table t has no primary vindex",
+	}, {
+		name: "multi-column vindex",
+		sql:  "select * from t",
+		vschema: &vschemapb.Keyspace{
+			Sharded:  true,
+			Vindexes: map[string]*vschemapb.Vindex{"hash": {Type: "hash"}},
+			Tables: map[string]*vschemapb.Table{
+				"t": {ColumnVindexes: []*vschemapb.ColumnVindex{{Name: "hash", Columns: []string{"id", "name"}}}},
+			},
+		},
+		err: "cannot have more than one column",
+	}, {
+		name: "vindex column not selected",
+		sql:  "select id from t",
+		vschema: &vschemapb.Keyspace{
+			Sharded:  true,
+			Vindexes: map[string]*vschemapb.Vindex{"hash": {Type: "hash"}},
+			Tables: map[string]*vschemapb.Table{
+				"t": {ColumnVindexes: []*vschemapb.ColumnVindex{{Name: "hash", Column: "name"}}},
+			},
+		},
+		err: "the column name of the primary vindex of table t must be selected",
+	}}
+	for _, tcase := range testcases {
+		if _, err := newTestRouter(t, tcase.sql, "dst", false, tcase.vschema); err == nil || !strings.Contains(err.Error(), tcase.err) {
+			t.Errorf("%v: newRouter returned %v, want %v", tcase.name, err, tcase.err)
+		}
+	}
+}
+
+func TestRouterMaster(t *testing.T) {
+	ctx := context.Background()
+	r, err := newTestRouter(t, "select * from t", "dst", false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	master, err := r.master(ctx, "80-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := master.Alias.Uid, uint32(210); got != want {
+		t.Errorf("master: %v, want %v", got, want)
+	}
+
+	// The master is cached until a write to it fails.
+	addTestTablet(t, r.ts, "dst", "80-", 211, topodatapb.TabletType_MASTER)
+	if master, err := r.master(ctx, "80-"); err != nil || master.Alias.Uid != 210 {
+		t.Errorf("cached master: %v %v, want 210", master, err)
+	}
+	r.masterFailed("80-", master)
+	if master, err := r.master(ctx, "80-"); err != nil || master.Alias.Uid != 211 {
+		t.Errorf("master after a failure: %v %v, want 211", master, err)
+	}
+	// A failure reported for the previous master keeps the new one.
+	r.masterFailed("80-", master)
+	if master, err := r.master(ctx, "80-"); err != nil || master.Alias.Uid != 211 {
+		t.Errorf("master after a stale failure: %v %v, want 211", master, err)
+	}
+
+	if err := r.ts.CreateShard(ctx, "dst", "c0-"); err != nil {
+		t.Fatal(err)
+	}
+	want := "shard dst/c0- has no master"
+	if _, err := r.master(ctx, "c0-"); err == nil || err.Error() != want {
+		t.Errorf("master of a shard without master: %v, want %v", err, want)
+	}
+}
@@ -22,6 +22,7 @@ This file handles the reparenting operations.
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"vitess.io/vitess/go/event"
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
@@ -572,10 +574,19 @@ func (wr *Wrangler) chooseNewMaster(
 }
 
 // EmergencyReparentShard will make the provided tablet the master for
-// the shard, when the old master is completely unreachable.
-func (wr *Wrangler) EmergencyReparentShard(ctx context.Context, keyspace, shard string, masterElectTabletAlias *topodatapb.TabletAlias, waitSlaveTimeout time.Duration) (err error) {
-	// lock the shard
-	ctx, unlock, lockErr := wr.ts.LockShard(ctx, keyspace, shard, fmt.Sprintf("EmergencyReparentShard(%v)", topoproto.TabletAliasString(masterElectTabletAlias)))
+// the shard, when the old master is completely unreachable. If
+// masterElectTabletAlias is nil, the most advanced replica is chosen, see
+// chooseEmergencyMaster. Replicas with errant GTIDs make the automatic
+// choice fail, unless quarantineErrantReplicas is set, in which case they
+// are changed to DRAINED and left out of the reparent.
+func (wr *Wrangler) EmergencyReparentShard(ctx context.Context, keyspace, shard string, masterElectTabletAlias *topodatapb.TabletAlias, waitSlaveTimeout time.Duration, quarantineErrantReplicas bool) (err error) {
+	// lock the shard, the master-elect of an automatic reparent is
+	// only known once the shard is locked.
+	lockAction := "EmergencyReparentShard(automatic)"
+	if masterElectTabletAlias != nil {
+		lockAction = fmt.Sprintf("EmergencyReparentShard(%v)", topoproto.TabletAliasString(masterElectTabletAlias))
+	}
+	ctx, unlock, lockErr := wr.ts.LockShard(ctx, keyspace, shard, lockAction)
 	if lockErr != nil {
 		return lockErr
 	}
@@ -585,7 +596,7 @@ func (wr *Wrangler) EmergencyReparentShard(ctx context.Context, keyspace, shard
 	ev := &events.Reparent{}
 
 	// do the work
-	err = wr.emergencyReparentShardLocked(ctx, ev, keyspace, shard, masterElectTabletAlias, waitSlaveTimeout)
+	err = wr.emergencyReparentShardLocked(ctx, ev, keyspace, shard, masterElectTabletAlias, waitSlaveTimeout, quarantineErrantReplicas)
 	if err != nil {
 		event.DispatchUpdate(ev, "failed EmergencyReparentShard: "+err.Error())
 	} else {
@@ -594,7 +605,7 @@ func (wr *Wrangler) EmergencyReparentShard(ctx context.Context, keyspace, shard
 	return err
 }
 
-func (wr *Wrangler) emergencyReparentShardLocked(ctx context.Context, ev *events.Reparent, keyspace, shard string, masterElectTabletAlias *topodatapb.TabletAlias, waitSlaveTimeout time.Duration) error {
+func (wr *Wrangler) emergencyReparentShardLocked(ctx context.Context, ev *events.Reparent, keyspace, shard string, masterElectTabletAlias *topodatapb.TabletAlias, waitSlaveTimeout time.Duration, quarantineErrantReplicas bool) error {
 	shardInfo, err := wr.ts.GetShard(ctx, keyspace, shard)
 	if err != nil {
 		return err
@@ -608,14 +619,19 @@ func (wr *Wrangler) emergencyReparentShardLocked(ctx context.Context, ev *events
 	}
 
 	// Check corner cases we're going to depend on
-	masterElectTabletAliasStr := topoproto.TabletAliasString(masterElectTabletAlias)
-	masterElectTabletInfo, ok := tabletMap[masterElectTabletAliasStr]
-	if !ok {
-		return fmt.Errorf("master-elect tablet %v is not in the shard", masterElectTabletAliasStr)
-	}
-	ev.NewMaster = *masterElectTabletInfo.Tablet
-	if topoproto.TabletAliasEqual(shardInfo.MasterAlias, masterElectTabletAlias) {
-		return fmt.Errorf("master-elect tablet %v is already the master", topoproto.TabletAliasString(masterElectTabletAlias))
+	var masterElectTabletAliasStr string
+	var masterElectTabletInfo *topo.TabletInfo
+	if masterElectTabletAlias != nil {
+		masterElectTabletAliasStr = topoproto.TabletAliasString(masterElectTabletAlias)
+		var ok bool
+		masterElectTabletInfo, ok = tabletMap[masterElectTabletAliasStr]
+		if !ok {
+			return fmt.Errorf("master-elect tablet %v is not in the shard", masterElectTabletAliasStr)
+		}
+		ev.NewMaster = *masterElectTabletInfo.Tablet
+		if topoproto.TabletAliasEqual(shardInfo.MasterAlias, masterElectTabletAlias) {
+			return fmt.Errorf("master-elect tablet %v is already the master", topoproto.TabletAliasString(masterElectTabletAlias))
+		}
 	}
 
 	// Deal with the old master: try to remote-scrap it, if it's
@@ -677,25 +693,36 @@ func (wr *Wrangler) emergencyReparentShardLocked(ctx context.Context, ev *events
 		return fmt.Errorf("lost topology lock, aborting: %v", err)
 	}
 
-	// Verify masterElect is alive and has the most advanced position
-	masterElectStatus, ok := statusMap[masterElectTabletAliasStr]
-	if !ok {
-		return fmt.Errorf("couldn't get master elect %v replication position", topoproto.TabletAliasString(masterElectTabletAlias))
-	}
-	masterElectPos, err := mysql.DecodePosition(masterElectStatus.Position)
-	if err != nil {
-		return fmt.Errorf("cannot decode master elect position %v: %v", masterElectStatus.Position, err)
-	}
-	for alias, status := range statusMap {
-		if alias == masterElectTabletAliasStr {
-			continue
+	if masterElectTabletAlias == nil {
+		// Choose the master-elect, and apply its relay logs.
+		masterElectTabletInfo, err = wr.emergencyMasterElect(ctx, ev, shardInfo, tabletMap, statusMap, waitSlaveTimeout, quarantineErrantReplicas)
+		if err != nil {
+			return err
+		}
+		masterElectTabletAlias = masterElectTabletInfo.Alias
+		masterElectTabletAliasStr = topoproto.TabletAliasString(masterElectTabletAlias)
+		ev.NewMaster = *masterElectTabletInfo.Tablet
+	} else {
+		// Verify masterElect is alive and has the most advanced position
+		masterElectStatus, ok := statusMap[masterElectTabletAliasStr]
+		if !ok {
+			return fmt.Errorf("couldn't get master elect %v replication position", topoproto.TabletAliasString(masterElectTabletAlias))
 		}
-		pos, err := mysql.DecodePosition(status.Position)
+		masterElectPos, err := mysql.DecodePosition(masterElectStatus.Position)
 		if err != nil {
-			return fmt.Errorf("cannot decode slave %v position %v: %v", alias, status.Position, err)
+			return fmt.Errorf("cannot decode master elect position %v: %v", masterElectStatus.Position, err)
 		}
-		if !masterElectPos.AtLeast(pos) {
-			return fmt.Errorf("tablet %v is more advanced than master elect tablet %v: %v > %v", alias, masterElectTabletAliasStr, status.Position, masterElectStatus)
+		for alias, status := range statusMap {
+			if alias == masterElectTabletAliasStr {
+				continue
+			}
+			pos, err := mysql.DecodePosition(status.Position)
+			if err != nil {
+				return fmt.Errorf("cannot decode slave %v position %v: %v", alias, status.Position, err)
+			}
+			if !masterElectPos.AtLeast(pos) {
+				return fmt.Errorf("tablet %v is more advanced than master elect tablet %v: %v > %v", alias, masterElectTabletAliasStr, status.Position, masterElectStatus)
+			}
 		}
 	}
 
@@ -782,3 +809,278 @@ func (wr *Wrangler) emergencyReparentShardLocked(ctx context.Context, ev *events
 
 	return nil
 }
+
+// emergencyCandidate is the replication state of a tablet, as read by an
+// automatic EmergencyReparentShard.
+type emergencyCandidate struct {
+	alias    string
+	tablet   *topodatapb.Tablet
+	status   mysql.SlaveStatus
+	semiSync bool
+
+	// masterPosition is the position of the master when it was
+	// promoted, from the reparent journal. It is zero if the tablet
+	// has no reparent journal entry.
+	masterPosition mysql.Position
+}
+
+// eligible returns true if the tablet can become the master.
+func (c *emergencyCandidate) eligible() bool {
+	return c.tablet.Type == topodatapb.TabletType_REPLICA && !topoproto.IsDelayedReplica(c.tablet)
+}
+
+// emergencyMasterElect picks the master-elect of an automatic
+// EmergencyReparentShard among the tablets that stopped replicating, and
+// applies its relay logs. The tablets with errant GTIDs that are
+// quarantined are removed from tabletMap and statusMap, so they are not
+// reparented. All the decisions are dispatched in ev.
+func (wr *Wrangler) emergencyMasterElect(ctx context.Context, ev *events.Reparent, shardInfo *topo.ShardInfo, tabletMap map[string]*topo.TabletInfo, statusMap map[string]*replicationdatapb.Status, waitSlaveTimeout time.Duration, quarantineErrantReplicas bool) (*topo.TabletInfo, error) {
+	event.DispatchUpdate(ev, "reading relay log positions")
+	candidates := wr.readEmergencyCandidates(ctx, ev, tabletMap, statusMap, waitSlaveTimeout)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("could not read the relay log position of any tablet")
+	}
+
+	errant, err := findErrantGTIDs(candidates)
+	if err != nil {
+		wr.reportReparent(ev, "errant GTIDs cannot be detected, skipping the check: %v", err)
+	}
+	if len(errant) != 0 {
+		var aliases []string
+		for alias, gtids := range errant {
+			wr.reportReparent(ev, "tablet %v has errant GTIDs %v", alias, gtids)
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+		if !quarantineErrantReplicas {
+			return nil, fmt.Errorf("tablets %v have errant GTIDs, fix them or quarantine them with -quarantine_errant_replicas", aliases)
+		}
+		for _, alias := range aliases {
+			wr.reportReparent(ev, "quarantining tablet %v as %v", alias, topodatapb.TabletType_DRAINED)
+			if err := wr.tmc.ChangeType(ctx, tabletMap[alias].Tablet, topodatapb.TabletType_DRAINED); err != nil {
+				return nil, fmt.Errorf("cannot quarantine tablet %v: %v", alias, err)
+			}
+			delete(tabletMap, alias)
+			delete(statusMap, alias)
+		}
+		var remaining []*emergencyCandidate
+		for _, c := range candidates {
+			if _, ok := errant[c.alias]; !ok {
+				remaining = append(remaining, c)
+			}
+		}
+		candidates = remaining
+	}
+
+	var masterCell string
+	if shardInfo.MasterAlias != nil {
+		masterCell = shardInfo.MasterAlias.Cell
+	}
+	elect, err := chooseEmergencyMaster(candidates, masterCell)
+	if err != nil {
+		return nil, err
+	}
+	wr.reportReparent(ev, "chose tablet %v as master-elect, at relay log position %v (cell %v, semi-sync %v)", elect.alias, mysql.EncodePosition(elect.status.RelayLogPosition), elect.tablet.Alias.Cell, elect.semiSync)
+
+	// The master-elect has to apply its relay logs before it is
+	// promoted, which resets them.
+	if !elect.status.Position.Equal(elect.status.RelayLogPosition) {
+		wr.reportReparent(ev, "applying the relay logs of %v, from %v", elect.alias, mysql.EncodePosition(elect.status.Position))
+		if _, err := wr.tmc.ExecuteFetchAsDba(ctx, elect.tablet, false, []byte("START SLAVE SQL_THREAD"), 0, false, false); err != nil {
+			return nil, fmt.Errorf("cannot start the SQL thread of %v: %v", elect.alias, err)
+		}
+		pos, err := wr.tmc.StopSlaveMinimum(ctx, elect.tablet, mysql.EncodePosition(elect.status.RelayLogPosition), waitSlaveTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("master-elect %v did not apply its relay logs: %v", elect.alias, err)
+		}
+		statusMap[elect.alias].Position = pos
+	}
+	return tabletMap[elect.alias], nil
+}
+
+// readEmergencyCandidates reads the relay log position and the semi-sync
+// state of the tablets in statusMap. The tablets that cannot be read are
+// ignored.
+func (wr *Wrangler) readEmergencyCandidates(ctx context.Context, ev *events.Reparent, tabletMap map[string]*topo.TabletInfo, statusMap map[string]*replicationdatapb.Status, waitSlaveTimeout time.Duration) []*emergencyCandidate {
+	wg := sync.WaitGroup{}
+	mu := sync.Mutex{}
+	var candidates []*emergencyCandidate
+	ignored := make(map[string]error)
+	for alias, status := range statusMap {
+		wg.Add(1)
+		go func(alias string, status *replicationdatapb.Status) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, waitSlaveTimeout)
+			defer cancel()
+			c, err := wr.readEmergencyCandidate(ctx, tabletMap[alias].Tablet, status)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				ignored[alias] = err
+				return
+			}
+			candidates = append(candidates, c)
+		}(alias, status)
+	}
+	wg.Wait()
+
+	for alias, err := range ignored {
+		wr.reportReparent(ev, "ignoring tablet %v: %v", alias, err)
+	}
+	return candidates
+}
+
+func (wr *Wrangler) readEmergencyCandidate(ctx context.Context, tablet *topodatapb.Tablet, status *replicationdatapb.Status) (*emergencyCandidate, error) {
+	pos, err := mysql.DecodePosition(status.Position)
+	if err != nil {
+		return nil, err
+	}
+	query, err := mysql.SlaveStatusQuery(pos)
+	if err != nil {
+		return nil, err
+	}
+	qr, err := wr.tmc.ExecuteFetchAsDba(ctx, tablet, false, []byte(query), 100, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read replication status: %v", err)
+	}
+	slaveStatus, err := mysql.ParseSlaveStatus(pos, sqltypes.Proto3ToResult(qr))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse replication status: %v", err)
+	}
+	// StopReplicationAndGetStatus read the position after
+	// replication stopped, which may be after SHOW SLAVE STATUS.
+	slaveStatus.Position = pos
+
+	qr, err = wr.tmc.ExecuteFetchAsDba(ctx, tablet, false, []byte("SHOW VARIABLES LIKE 'rpl_semi_sync_slave_enabled'"), 1, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read semi-sync state: %v", err)
+	}
+	semiSync := false
+	if result := sqltypes.Proto3ToResult(qr); len(result.Rows) == 1 && len(result.Rows[0]) == 2 {
+		semiSync = result.Rows[0][1].ToString() == "ON"
+	}
+
+	// The reparent journal may not exist, or be empty, if the shard
+	// was not reparented by vitess: the position of the master is
+	// then unknown.
+	var masterPos mysql.Position
+	if qr, err := wr.tmc.ExecuteFetchAsDba(ctx, tablet, false, []byte(lastReparentJournalQuery), 1, false, false); err == nil {
+		if result := sqltypes.Proto3ToResult(qr); len(result.Rows) == 1 && !result.Rows[0][0].IsNull() {
+			masterPos, _ = mysql.DecodePosition(result.Rows[0][0].ToString())
+		}
+	}
+
+	return &emergencyCandidate{
+		alias:          topoproto.TabletAliasString(tablet.Alias),
+		tablet:         tablet,
+		status:         slaveStatus,
+		semiSync:       semiSync,
+		masterPosition: masterPos,
+	}, nil
+}
+
+// lastReparentJournalQuery reads the position of the master at the last
+// reparent.
+const lastReparentJournalQuery = "SELECT replication_position FROM _vt.reparent_journal ORDER BY time_created_ns DESC LIMIT 1"
+
+// findErrantGTIDs returns the candidates that have GTIDs in their relay
+// logs that are not in the last known position of the master: the
+// position it had when it was promoted, from the reparent journal, and
+// the GTIDs it wrote since. These transactions were executed on the
+// replicas directly. If one of them became the master, the other
+// replicas would replicate them, and if not, they could be lost or
+// replicated later, when the replica is promoted. Several replicas can
+// have the same errant GTIDs, for instance if they were restored from a
+// backup of an errant replica.
+//
+// It returns an error if the errant GTIDs cannot be found.
+func findErrantGTIDs(candidates []*emergencyCandidate) (map[string]mysql.Mysql56GTIDSet, error) {
+	sets := make([]mysql.Mysql56GTIDSet, len(candidates))
+	var masterSet mysql.Mysql56GTIDSet
+	for i, c := range candidates {
+		set, ok := c.status.RelayLogPosition.GTIDSet.(mysql.Mysql56GTIDSet)
+		if !ok {
+			return nil, fmt.Errorf("not supported with flavor %v", c.status.RelayLogPosition.GTIDSet.Flavor())
+		}
+		sets[i] = set
+		if c.masterPosition.IsZero() {
+			continue
+		}
+		journalSet, ok := c.masterPosition.GTIDSet.(mysql.Mysql56GTIDSet)
+		if !ok {
+			return nil, fmt.Errorf("the reparent journal of %v has a position of flavor %v", c.alias, c.masterPosition.GTIDSet.Flavor())
+		}
+		masterSet = masterSet.Union(journalSet).(mysql.Mysql56GTIDSet)
+	}
+	if masterSet == nil {
+		return nil, fmt.Errorf("no tablet has a reparent journal entry, the transactions of the previous masters are unknown")
+	}
+
+	// The replicas may not all have the same master anymore, so the
+	// GTIDs of all their masters are legit.
+	for i, c := range candidates {
+		if sid, err := mysql.ParseSID(c.status.MasterUUID); err == nil && sets[i][sid] != nil {
+			masterSet = masterSet.Union(mysql.Mysql56GTIDSet{sid: sets[i][sid]}).(mysql.Mysql56GTIDSet)
+		}
+	}
+
+	errant := make(map[string]mysql.Mysql56GTIDSet)
+	for i, c := range candidates {
+		if diff := sets[i].Difference(masterSet); len(diff) != 0 {
+			errant[c.alias] = diff
+		}
+	}
+	return errant, nil
+}
+
+// chooseEmergencyMaster returns the master-elect of an automatic
+// EmergencyReparentShard. It must have all the transactions the other
+// candidates received, so no transaction is lost. If several candidates
+// have them, the ones in the cell of the old master are preferred, then
+// the semi-sync ackers.
+func chooseEmergencyMaster(candidates []*emergencyCandidate, masterCell string) (*emergencyCandidate, error) {
+	var mostAdvanced []*emergencyCandidate
+	for _, c := range candidates {
+		advanced := true
+		for _, other := range candidates {
+			if !c.status.RelayLogPosition.AtLeast(other.status.RelayLogPosition) {
+				advanced = false
+				break
+			}
+		}
+		if advanced {
+			mostAdvanced = append(mostAdvanced, c)
+		}
+	}
+	if len(mostAdvanced) == 0 {
+		return nil, fmt.Errorf("no tablet has all the transactions of the others, their relay logs have diverged")
+	}
+
+	var eligible []*emergencyCandidate
+	for _, c := range mostAdvanced {
+		if c.eligible() {
+			eligible = append(eligible, c)
+		}
+	}
+	if len(eligible) == 0 {
+		return nil, fmt.Errorf("no master-eligible tablet has all the transactions, the most advanced is %v (%v)", mostAdvanced[0].alias, mostAdvanced[0].tablet.Type)
+	}
+
+	sort.Slice(eligible, func(i, j int) bool {
+		if sameCell := eligible[i].tablet.Alias.Cell == masterCell; sameCell != (eligible[j].tablet.Alias.Cell == masterCell) {
+			return sameCell
+		}
+		if eligible[i].semiSync != eligible[j].semiSync {
+			return eligible[i].semiSync
+		}
+		return eligible[i].alias < eligible[j].alias
+	})
+	return eligible[0], nil
+}
+
+// reportReparent logs a decision of a reparent, and dispatches it.
+func (wr *Wrangler) reportReparent(ev *events.Reparent, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	wr.logger.Infof("%v", msg)
+	event.DispatchUpdate(ev, msg)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func newTestCandidate(cell string, uid uint32, tabletType topodatapb.TabletType, relayLogPos string, semiSync bool) *emergencyCandidate {
+	alias := &topodatapb.TabletAlias{Cell: cell, Uid: uid}
+	return &emergencyCandidate{
+		alias: topoproto.TabletAliasString(alias),
+		tablet: &topodatapb.Tablet{
+			Alias: alias,
+			Type:  tabletType,
+		},
+		status: mysql.SlaveStatus{
+			RelayLogPosition: mysql.MustParsePosition("MySQL56", relayLogPos),
+			MasterUUID:       "00000000-0000-0000-0000-000000000001",
+		},
+		semiSync: semiSync,
+	}
+}
+
+func TestChooseEmergencyMaster(t *testing.T) {
+	uuid := "00000000-0000-0000-0000-000000000001"
+	table := []struct {
+		desc       string
+		candidates []*emergencyCandidate
+		want       string
+		err        string
+	}{{
+		desc: "most advanced",
+		candidates: []*emergencyCandidate{
+			newTestCandidate("cell1", 1, topodatapb.TabletType_REPLICA, uuid+":1-10", true),
+			newTestCandidate("cell2", 2, topodatapb.TabletType_REPLICA, uuid+":1-11", false),
+		},
+		want: "cell2-0000000002",
+	}, {
+		desc: "same cell before semi-sync",
+		candidates: []*emergencyCandidate{
+			newTestCandidate("cell2", 1, topodatapb.TabletType_REPLICA, uuid+":1-10", true),
+			newTestCandidate("cell1", 2, topodatapb.TabletType_REPLICA, uuid+":1-10", false),
+		},
+		want: "cell1-0000000002",
+	}, {
+		desc: "semi-sync",
+		candidates: []*emergencyCandidate{
+			newTestCandidate("cell1", 1, topodatapb.TabletType_REPLICA, uuid+":1-10", false),
+			newTestCandidate("cell1", 2, topodatapb.TabletType_REPLICA, uuid+":1-10", true),
+		},
+		want: "cell1-0000000002",
+	}, {
+		desc: "most advanced is not eligible",
+		candidates: []*emergencyCandidate{
+			newTestCandidate("cell1", 1, topodatapb.TabletType_REPLICA, uuid+":1-10", true),
+			newTestCandidate("cell1", 2, topodatapb.TabletType_RDONLY, uuid+":1-11", false),
+		},
+		err: "no master-eligible tablet has all the transactions",
+	}, {
+		desc: "diverged",
+		candidates: []*emergencyCandidate{
+			newTestCandidate("cell1", 1, topodatapb.TabletType_REPLICA, uuid+":1-10", true),
+			newTestCandidate("cell1", 2, topodatapb.TabletType_REPLICA, uuid+":1-9:11", false),
+		},
+		err: "relay logs have diverged",
+	}}
+	for _, tcase := range table {
+		got, err := chooseEmergencyMaster(tcase.candidates, "cell1")
+		if tcase.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tcase.err) {
+				t.Errorf("%v: got error %v, want %v", tcase.desc, err, tcase.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tcase.desc, err)
+			continue
+		}
+		if got.alias != tcase.want {
+			t.Errorf("%v: got %v, want %v", tcase.desc, got.alias, tcase.want)
+		}
+	}
+}
+
+func TestFindErrantGTIDs(t *testing.T) {
+	master := "00000000-0000-0000-0000-000000000001"
+	oldMaster := "00000000-0000-0000-0000-000000000002"
+	local := "00000000-0000-0000-0000-000000000003"
+	restored := "00000000-0000-0000-0000-000000000004"
+	candidates := []*emergencyCandidate{
+		// The most advanced, from the master.
+		newTestCandidate("cell1", 1, topodatapb.TabletType_REPLICA, master+":1-12,"+oldMaster+":1-5", false),
+		newTestCandidate("cell1", 2, topodatapb.TabletType_REPLICA, master+":1-10,"+oldMaster+":1-5", false),
+		// Executed a transaction locally.
+		newTestCandidate("cell1", 3, topodatapb.TabletType_REPLICA, master+":1-10,"+oldMaster+":1-5,"+local+":1", false),
+		// Both restored from the backup of a replica that executed
+		// a transaction locally.
+		newTestCandidate("cell1", 4, topodatapb.TabletType_REPLICA, master+":1-10,"+oldMaster+":1-5,"+restored+":1-2", false),
+		newTestCandidate("cell1", 5, topodatapb.TabletType_REPLICA, master+":1-11,"+oldMaster+":1-5,"+restored+":1-2", false),
+	}
+
+	// Without reparent journal, the GTIDs of the old master are unknown.
+	if _, err := findErrantGTIDs(candidates); err == nil || !strings.Contains(err.Error(), "no tablet has a reparent journal entry") {
+		t.Errorf("findErrantGTIDs() without reparent journal: %v", err)
+	}
+
+	// The master was promoted at the end of the transactions of the old
+	// master. Only one replica needs the journal entry.
+	candidates[1].masterPosition = mysql.MustParsePosition("MySQL56", master+":1-3,"+oldMaster+":1-5")
+	errant, err := findErrantGTIDs(candidates)
+	if err != nil {
+		t.Fatalf("findErrantGTIDs() failed: %v", err)
+	}
+	want := map[string]string{
+		"cell1-0000000003": local + ":1",
+		"cell1-0000000004": restored + ":1-2",
+		"cell1-0000000005": restored + ":1-2",
+	}
+	got := make(map[string]string)
+	for alias, set := range errant {
+		got[alias] = set.String()
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findErrantGTIDs() = %v, want %v", got, want)
+	}
+
+	// MariaDB GTIDs cannot be compared.
+	candidates[0].status.RelayLogPosition = mysql.MustParsePosition("MariaDB", "0-1-12")
+	if _, err := findErrantGTIDs(candidates); err == nil || !strings.Contains(err.Error(), "not supported with flavor MariaDB") {
+		t.Errorf("findErrantGTIDs() with MariaDB positions: %v", err)
+	}
+}
@@ -18,9 +18,12 @@ package wrangler
 
 import (
 	"fmt"
+	"time"
 
 	"golang.org/x/net/context"
+	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
@@ -169,3 +172,62 @@ func (wr *Wrangler) ExecuteFetchAsDba(ctx context.Context, tabletAlias *topodata
 	}
 	return wr.tmc.ExecuteFetchAsDba(ctx, ti.Tablet, false, []byte(query), maxRows, disableBinlogs, reloadSchema)
 }
+
+// FastForwardDelayedReplica makes a delayed replica apply its relay
+// logs up to position, ignoring its delay, and stop there. It is used
+// to recover the data of a delayed replica right before a bad write.
+// It waits for the replica to reach the position until ctx is done.
+// Replication is stopped afterwards, and is delayed again the next
+// time the tablet is reparented, for instance with ReparentTablet.
+func (wr *Wrangler) FastForwardDelayedReplica(ctx context.Context, tabletAlias *topodatapb.TabletAlias, position string) error {
+	ti, err := wr.ts.GetTablet(ctx, tabletAlias)
+	if err != nil {
+		return err
+	}
+	if !topoproto.IsDelayedReplica(ti.Tablet) {
+		return fmt.Errorf("tablet %v is not a delayed replica", tabletAlias)
+	}
+	pos, err := mysql.DecodePosition(position)
+	if err != nil {
+		return err
+	}
+	startUntil, err := mysql.StartSlaveUntilAfterCommand(pos)
+	if err != nil {
+		return err
+	}
+
+	// Stopping replication through the tablet tells it replication
+	// is stopped on purpose, so it does not restart it.
+	if err := wr.tmc.StopSlave(ctx, ti.Tablet); err != nil {
+		return fmt.Errorf("cannot stop replication on %v: %v", tabletAlias, err)
+	}
+	for _, query := range []string{mysqlctl.SetMasterDelayCommand(0), startUntil} {
+		if _, err := wr.tmc.ExecuteFetchAsDba(ctx, ti.Tablet, false, []byte(query), 0, false, false); err != nil {
+			return fmt.Errorf("%v failed on %v: %v", query, tabletAlias, err)
+		}
+	}
+	wr.Logger().Infof("Replication of %v restarted until %v, waiting for it", tabletAlias, position)
+
+	for {
+		status, err := wr.tmc.SlaveStatus(ctx, ti.Tablet)
+		if err != nil {
+			return fmt.Errorf("cannot get replication status of %v: %v", tabletAlias, err)
+		}
+		current, err := mysql.DecodePosition(status.Position)
+		if err != nil {
+			return err
+		}
+		if current.AtLeast(pos) {
+			wr.Logger().Infof("Tablet %v reached %v", tabletAlias, position)
+			return nil
+		}
+		if !status.SlaveSqlRunning {
+			return fmt.Errorf("replication of %v stopped at %v, before %v", tabletAlias, status.Position, position)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("tablet %v did not reach %v, it is at %v: %v", tabletAlias, position, status.Position, ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+}
@@ -108,6 +108,9 @@ func TestBackupRestore(t *testing.T) {
 		"STOP SLAVE",
 		"START SLAVE",
 	}
+	sourceTablet.FakeMysqlDaemon.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		"SHOW DATABASES": {},
+	}
 	sourceTablet.FakeMysqlDaemon.Mycnf = &mysqlctl.Mycnf{
 		DataDir:               sourceDataDir,
 		InnodbDataHomeDir:     sourceInnodbDataDir,
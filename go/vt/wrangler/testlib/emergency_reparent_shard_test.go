@@ -24,6 +24,8 @@ import (
 	"golang.org/x/net/context"
 
 	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/fakesqldb"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
@@ -197,7 +199,7 @@ func TestEmergencyReparentShardMasterElectNotBest(t *testing.T) {
 	defer moreAdvancedSlave.StopActionLoop(t)
 
 	// run EmergencyReparentShard
-	if err := wr.EmergencyReparentShard(ctx, newMaster.Tablet.Keyspace, newMaster.Tablet.Shard, newMaster.Tablet.Alias, 10*time.Second); err == nil || !strings.Contains(err.Error(), "is more advanced than master elect tablet") {
+	if err := wr.EmergencyReparentShard(ctx, newMaster.Tablet.Keyspace, newMaster.Tablet.Shard, newMaster.Tablet.Alias, 10*time.Second, false); err == nil || !strings.Contains(err.Error(), "is more advanced than master elect tablet") {
 		t.Fatalf("EmergencyReparentShard returned the wrong error: %v", err)
 	}
 
@@ -212,3 +214,136 @@ func TestEmergencyReparentShardMasterElectNotBest(t *testing.T) {
 		t.Fatalf("moreAdvancedSlave.FakeMysqlDaemon.CheckSuperQueryList failed: %v", err)
 	}
 }
+
+// TestEmergencyReparentShardAuto lets EmergencyReparentShard choose the
+// new master, and quarantine a replica with errant GTIDs.
+func TestEmergencyReparentShardAuto(t *testing.T) {
+	ts := memorytopo.NewServer("cell1", "cell2")
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	vp := NewVtctlPipe(t, ts)
+	defer vp.Close()
+
+	masterUUID := "00000000-0000-0000-0000-000000000001"
+	statusFields := sqltypes.MakeTestFields(
+		"Master_Host|Slave_IO_Running|Slave_SQL_Running|Master_UUID|Retrieved_Gtid_Set|Executed_Gtid_Set",
+		"varchar|varchar|varchar|varchar|varchar|varchar")
+	semiSyncFields := sqltypes.MakeTestFields("Variable_name|Value", "varchar|varchar")
+	journalFields := sqltypes.MakeTestFields("replication_position", "varbinary")
+	newDB := func(name, retrieved, executed, semiSync string) *fakesqldb.DB {
+		db := fakesqldb.New(t).SetName(name)
+		db.AddQuery("SHOW SLAVE STATUS", sqltypes.MakeTestResult(statusFields,
+			"master|No|No|"+masterUUID+"|"+retrieved+"|"+executed))
+		db.AddQuery("SHOW VARIABLES LIKE 'rpl_semi_sync_slave_enabled'", sqltypes.MakeTestResult(semiSyncFields,
+			"rpl_semi_sync_slave_enabled|"+semiSync))
+		db.AddQuery("START SLAVE SQL_THREAD", &sqltypes.Result{})
+		// The old master was promoted at transaction 5.
+		db.AddQuery("SELECT replication_position FROM _vt.reparent_journal ORDER BY time_created_ns DESC LIMIT 1", sqltypes.MakeTestResult(journalFields,
+			"MySQL56/"+masterUUID+":1-5"))
+		return db
+	}
+
+	// The new master and the other good slave received the same
+	// transactions, the new master is in the cell of the old master.
+	newMasterDB := newDB("newMaster", masterUUID+":1-12", masterUUID+":1-10", "OFF")
+	defer newMasterDB.Close()
+	goodSlaveDB := newDB("goodSlave", masterUUID+":1-12", masterUUID+":1-12", "ON")
+	defer goodSlaveDB.Close()
+	// The errant slave has a transaction of its own.
+	errantSlaveDB := newDB("errantSlave", masterUUID+":1-11", masterUUID+":1-11,00000000-0000-0000-0000-000000000002:1", "ON")
+	defer errantSlaveDB.Close()
+
+	oldMaster := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_MASTER, nil)
+	newMaster := NewFakeTablet(t, wr, "cell1", 1, topodatapb.TabletType_REPLICA, newMasterDB)
+	goodSlave := NewFakeTablet(t, wr, "cell2", 2, topodatapb.TabletType_REPLICA, goodSlaveDB)
+	errantSlave := NewFakeTablet(t, wr, "cell1", 3, topodatapb.TabletType_REPLICA, errantSlaveDB)
+
+	// new master, applies its relay logs before it is promoted
+	newMaster.FakeMysqlDaemon.ReadOnly = true
+	newMaster.FakeMysqlDaemon.Replicating = true
+	newMaster.FakeMysqlDaemon.CurrentMasterPosition = mysql.MustParsePosition("MySQL56", masterUUID+":1-10")
+	newMaster.FakeMysqlDaemon.WaitMasterPosition = mysql.MustParsePosition("MySQL56", masterUUID+":1-12")
+	newMaster.FakeMysqlDaemon.PromoteSlaveResult = mysql.MustParsePosition("MySQL56", masterUUID+":1-12")
+	newMaster.FakeMysqlDaemon.ExpectedExecuteSuperQueryList = []string{
+		"STOP SLAVE",
+		"STOP SLAVE",
+		"CREATE DATABASE IF NOT EXISTS _vt",
+		"SUBCREATE TABLE IF NOT EXISTS _vt.reparent_journal",
+		"SUBINSERT INTO _vt.reparent_journal (time_created_ns, action_name, master_alias, replication_position) VALUES",
+	}
+	newMaster.StartActionLoop(t, wr)
+	defer newMaster.StopActionLoop(t)
+
+	// old master, will be scrapped
+	oldMaster.StartActionLoop(t, wr)
+	defer oldMaster.StopActionLoop(t)
+
+	// good slave, is reparented
+	goodSlave.FakeMysqlDaemon.ReadOnly = true
+	goodSlave.FakeMysqlDaemon.Replicating = true
+	goodSlave.FakeMysqlDaemon.CurrentMasterPosition = mysql.MustParsePosition("MySQL56", masterUUID+":1-12")
+	goodSlave.FakeMysqlDaemon.SetMasterInput = topoproto.MysqlAddr(newMaster.Tablet)
+	goodSlave.FakeMysqlDaemon.ExpectedExecuteSuperQueryList = []string{
+		"STOP SLAVE",
+		"FAKE SET MASTER",
+		"START SLAVE",
+	}
+	goodSlave.StartActionLoop(t, wr)
+	defer goodSlave.StopActionLoop(t)
+
+	// errant slave, is quarantined
+	errantSlave.FakeMysqlDaemon.ReadOnly = true
+	errantSlave.FakeMysqlDaemon.Replicating = true
+	errantSlave.FakeMysqlDaemon.CurrentMasterPosition = mysql.MustParsePosition("MySQL56", masterUUID+":1-11,00000000-0000-0000-0000-000000000002:1")
+	errantSlave.FakeMysqlDaemon.ExpectedExecuteSuperQueryList = []string{
+		"STOP SLAVE",
+	}
+	errantSlave.StartActionLoop(t, wr)
+	defer errantSlave.StopActionLoop(t)
+
+	// Without -quarantine_errant_replicas, it fails before changing
+	// anything but the old master.
+	keyspaceShard := newMaster.Tablet.Keyspace + "/" + newMaster.Tablet.Shard
+	if err := vp.Run([]string{"EmergencyReparentShard", "-wait_slave_timeout", "10s", "-keyspace_shard", keyspaceShard}); err == nil || !strings.Contains(err.Error(), "have errant GTIDs") {
+		t.Fatalf("EmergencyReparentShard returned the wrong error: %v", err)
+	}
+
+	// The slaves are stopped now.
+	newMaster.FakeMysqlDaemon.Replicating = false
+	goodSlave.FakeMysqlDaemon.ExpectedExecuteSuperQueryList = []string{
+		"STOP SLAVE",
+		"FAKE SET MASTER",
+	}
+	goodSlave.FakeMysqlDaemon.Replicating = false
+	errantSlave.FakeMysqlDaemon.Replicating = false
+	if err := vp.Run([]string{"EmergencyReparentShard", "-wait_slave_timeout", "10s", "-keyspace_shard", keyspaceShard, "-quarantine_errant_replicas"}); err != nil {
+		t.Fatalf("EmergencyReparentShard failed: %v", err)
+	}
+
+	// check what was run
+	if err := newMaster.FakeMysqlDaemon.CheckSuperQueryList(); err != nil {
+		t.Fatalf("newMaster.FakeMysqlDaemon.CheckSuperQueryList failed: %v", err)
+	}
+	if err := goodSlave.FakeMysqlDaemon.CheckSuperQueryList(); err != nil {
+		t.Fatalf("goodSlave.FakeMysqlDaemon.CheckSuperQueryList failed: %v", err)
+	}
+	if err := errantSlave.FakeMysqlDaemon.CheckSuperQueryList(); err != nil {
+		t.Fatalf("errantSlave.FakeMysqlDaemon.CheckSuperQueryList failed: %v", err)
+	}
+	if newMaster.FakeMysqlDaemon.ReadOnly {
+		t.Errorf("newMaster.FakeMysqlDaemon.ReadOnly set")
+	}
+	si, err := ts.GetShard(context.Background(), newMaster.Tablet.Keyspace, newMaster.Tablet.Shard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !topoproto.TabletAliasEqual(si.MasterAlias, newMaster.Tablet.Alias) {
+		t.Errorf("shard master is %v, want %v", topoproto.TabletAliasString(si.MasterAlias), topoproto.TabletAliasString(newMaster.Tablet.Alias))
+	}
+	ti, err := ts.GetTablet(context.Background(), errantSlave.Tablet.Alias)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ti.Type != topodatapb.TabletType_DRAINED {
+		t.Errorf("errant slave has type %v, want %v", ti.Type, topodatapb.TabletType_DRAINED)
+	}
+}
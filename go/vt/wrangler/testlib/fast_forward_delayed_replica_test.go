@@ -0,0 +1,100 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testlib
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/fakesqldb"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestFastForwardDelayedReplica(t *testing.T) {
+	masterUUID := "3e11fa47-71ca-11e1-9e33-c80aa9429562"
+	target := masterUUID + ":1-20"
+	testcases := []struct {
+		desc     string
+		delayed  bool
+		position string
+		err      string
+	}{{
+		desc:     "reaches the position",
+		delayed:  true,
+		position: masterUUID + ":1-20",
+	}, {
+		desc:     "replication stops before the position",
+		delayed:  true,
+		position: masterUUID + ":1-10",
+		err:      "stopped at MySQL56/" + masterUUID + ":1-10, before MySQL56/" + target,
+	}, {
+		desc:     "not a delayed replica",
+		position: masterUUID + ":1-20",
+		err:      "is not a delayed replica",
+	}}
+	for _, tcase := range testcases {
+		t.Run(tcase.desc, func(t *testing.T) {
+			ts := memorytopo.NewServer("cell1")
+			wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+			db := fakesqldb.New(t)
+			defer db.Close()
+			db.AddQuery("CHANGE MASTER TO MASTER_DELAY = 0", &sqltypes.Result{})
+			db.AddQuery("START SLAVE UNTIL SQL_AFTER_GTIDS = '"+target+"'", &sqltypes.Result{})
+
+			var options []TabletOption
+			if tcase.delayed {
+				options = append(options, func(tablet *topodatapb.Tablet) {
+					tablet.Tags = map[string]string{topoproto.DelayedReplicaTag: "1h0m0s"}
+				})
+			}
+			replica := NewFakeTablet(t, wr, "cell1", 1, topodatapb.TabletType_REPLICA, db, options...)
+			replica.FakeMysqlDaemon.CurrentMasterPosition = mysql.MustParsePosition("MySQL56", tcase.position)
+			if tcase.delayed {
+				replica.FakeMysqlDaemon.ExpectedExecuteSuperQueryList = []string{
+					"STOP SLAVE",
+				}
+			}
+			replica.StartActionLoop(t, wr)
+			defer replica.StopActionLoop(t)
+
+			err := wr.FastForwardDelayedReplica(context.Background(), replica.Tablet.Alias, "MySQL56/"+target)
+			if tcase.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tcase.err) {
+					t.Fatalf("FastForwardDelayedReplica: %v, want %v", err, tcase.err)
+				}
+			} else if err != nil {
+				t.Fatalf("FastForwardDelayedReplica failed: %v", err)
+			}
+			if err := replica.FakeMysqlDaemon.CheckSuperQueryList(); err != nil {
+				t.Errorf("replica.FakeMysqlDaemon.CheckSuperQueryList: %v", err)
+			}
+			if tcase.delayed && db.GetQueryCalledNum("START SLAVE UNTIL SQL_AFTER_GTIDS = '"+target+"'") != 1 {
+				t.Errorf("replication was not restarted until %v", target)
+			}
+		})
+	}
+}